@@ -0,0 +1,230 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+	"github.com/wricardo/tesla-road-trip-game/game/strategy"
+)
+
+const (
+	defaultAutoplayMovesPerSecond = 2
+	defaultAutoplayMaxMoves       = 500
+	maxAutoplayMovesPerSecond     = 20
+	maxAutoplayMoves              = 10000
+
+	// autoplaySolver plays the solver's winning plan rather than a heuristic
+	autoplaySolver = "solver"
+)
+
+// autoplayRequest is the body accepted by POST /api/sessions/{id}/autoplay
+type autoplayRequest struct {
+	Strategy       string  `json:"strategy"` // random (default), greedy or solver
+	MovesPerSecond float64 `json:"moves_per_second"`
+	MaxMoves       int     `json:"max_moves"`
+}
+
+// autoplayRun tracks a background bot playing a single session
+type autoplayRun struct {
+	Strategy       string    `json:"strategy"`
+	MovesPerSecond float64   `json:"moves_per_second"`
+	MaxMoves       int       `json:"max_moves"`
+	StartedAt      time.Time `json:"started_at"`
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (s *Server) handleStartAutoplay(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["id"]
+
+	var req autoplayRequest
+	if err := s.decodeBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+
+	// Apply defaults
+	if req.Strategy == "" {
+		req.Strategy = "random"
+	}
+	if req.MovesPerSecond == 0 {
+		req.MovesPerSecond = defaultAutoplayMovesPerSecond
+	}
+	if req.MaxMoves == 0 {
+		req.MaxMoves = defaultAutoplayMaxMoves
+	}
+
+	if req.MovesPerSecond < 0 || req.MovesPerSecond > maxAutoplayMovesPerSecond {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("moves_per_second must be between 0 and %d", maxAutoplayMovesPerSecond))
+		return
+	}
+	if req.MaxMoves < 0 || req.MaxMoves > maxAutoplayMoves {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("max_moves must be between 1 and %d", maxAutoplayMoves))
+		return
+	}
+
+	// The solver plans against the session's own config, so it is built
+	// once the run has a context to stop its planning with
+	var newStrategy func(ctx context.Context) strategy.Strategy
+	if name := strings.ToLower(req.Strategy); name == autoplaySolver {
+		cfg, err := s.service.GetSessionConfig(r.Context(), sessionID)
+		if err != nil {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		newStrategy = func(ctx context.Context) strategy.Strategy {
+			return strategy.NewPlanner(ctx, &cfg.GameConfig)
+		}
+	} else {
+		strat, err := strategy.New(name)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("%v; autoplay also offers %s", err, autoplaySolver))
+			return
+		}
+		newStrategy = func(context.Context) strategy.Strategy { return strat }
+	}
+
+	// Verify session exists before starting
+	if _, err := s.service.GetGameState(r.Context(), sessionID); err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	run, err := s.startAutoplay(sessionID, newStrategy, req)
+	if err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, map[string]interface{}{
+		"message":  "Autoplay started",
+		"autoplay": run,
+	})
+}
+
+func (s *Server) handleStopAutoplay(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["id"]
+
+	if !s.stopAutoplay(sessionID) {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("no autoplay running for session %s", sessionID))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Autoplay stopped",
+	})
+}
+
+// startAutoplay registers and launches a background run; only one run per session is allowed
+func (s *Server) startAutoplay(sessionID string, newStrategy func(ctx context.Context) strategy.Strategy, req autoplayRequest) (*autoplayRun, error) {
+	s.autoplayMu.Lock()
+	defer s.autoplayMu.Unlock()
+
+	key := strings.ToLower(sessionID)
+	if _, exists := s.autoplays[key]; exists {
+		return nil, fmt.Errorf("autoplay already running for session %s", sessionID)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	strat := newStrategy(ctx)
+	run := &autoplayRun{
+		Strategy:       strat.Name(),
+		MovesPerSecond: req.MovesPerSecond,
+		MaxMoves:       req.MaxMoves,
+		StartedAt:      time.Now(),
+		cancel:         cancel,
+		done:           make(chan struct{}),
+	}
+	s.autoplays[key] = run
+
+	go s.runAutoplay(ctx, sessionID, strat, run)
+	return run, nil
+}
+
+// stopAutoplay cancels a running autoplay and waits for it to exit
+func (s *Server) stopAutoplay(sessionID string) bool {
+	s.autoplayMu.Lock()
+	run, exists := s.autoplays[strings.ToLower(sessionID)]
+	s.autoplayMu.Unlock()
+
+	if !exists {
+		return false
+	}
+
+	run.cancel()
+	<-run.done
+	return true
+}
+
+// runAutoplay issues moves through the service at the configured rate until the
+// game ends, the move budget is exhausted, the session disappears, or it is cancelled
+func (s *Server) runAutoplay(ctx context.Context, sessionID string, strat strategy.Strategy, run *autoplayRun) {
+	defer func() {
+		s.autoplayMu.Lock()
+		if s.autoplays[strings.ToLower(sessionID)] == run {
+			delete(s.autoplays, strings.ToLower(sessionID))
+		}
+		s.autoplayMu.Unlock()
+		close(run.done)
+	}()
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / run.MovesPerSecond))
+	defer ticker.Stop()
+
+	executed := 0
+	reason := "max_moves"
+loop:
+	for executed < run.MaxMoves {
+		select {
+		case <-ctx.Done():
+			reason = "stopped"
+			break loop
+		case <-ticker.C:
+		}
+
+		state, err := s.service.GetGameState(ctx, sessionID)
+		if err != nil {
+			reason = "session_gone"
+			break loop
+		}
+		if state.GameOver {
+			reason = "game_over"
+			break loop
+		}
+
+		direction := strat.Next(state)
+		if ctx.Err() != nil {
+			reason = "stopped" // Stopped while the strategy was planning
+			break loop
+		}
+		if direction == "" {
+			reason = "no_moves"
+			break loop
+		}
+
+		result, err := s.service.MoveWithOptions(ctx, sessionID, direction, service.MoveOptions{Autoplay: true})
+		if err != nil {
+			reason = "session_gone"
+			break loop
+		}
+		executed++
+
+		if s.hub != nil {
+			s.hub.BroadcastToSession(sessionID, result.GameState)
+		}
+		if result.GameState != nil && result.GameState.GameOver {
+			reason = "game_over"
+			break loop
+		}
+	}
+
+	s.logger.Info("autoplay finished", "session_id", sessionID, "strategy", run.Strategy, "moves", executed, "stop", reason)
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/config"
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+	"github.com/wricardo/tesla-road-trip-game/game/session"
+)
+
+func autoplayTestState(gameOver bool) *engine.GameState {
+	grid := make([][]engine.Cell, 3)
+	for y := range grid {
+		grid[y] = make([]engine.Cell, 3)
+		for x := range grid[y] {
+			grid[y][x] = engine.Cell{Type: engine.Road}
+		}
+	}
+	return &engine.GameState{
+		Grid:       grid,
+		PlayerPos:  engine.Position{X: 1, Y: 1},
+		Battery:    10,
+		MaxBattery: 10,
+		GameOver:   gameOver,
+	}
+}
+
+func waitForAutoplayExit(t *testing.T, s *Server, sessionID string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		s.autoplayMu.Lock()
+		_, running := s.autoplays[sessionID]
+		s.autoplayMu.Unlock()
+		if !running {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("autoplay for session %s did not stop", sessionID)
+}
+
+func TestAutoplay_StartStop(t *testing.T) {
+	var moves int32
+	mockService := &MockGameService{
+		GetGameStateFunc: func(ctx context.Context, sessionID string) (*engine.GameState, error) {
+			return autoplayTestState(false), nil
+		},
+		MoveWithOptionsFunc: func(ctx context.Context, sessionID, direction string, opts service.MoveOptions) (*service.MoveResult, error) {
+			if !opts.Autoplay {
+				t.Error("Expected autoplay moves to be tagged")
+			}
+			atomic.AddInt32(&moves, 1)
+			return &service.MoveResult{Success: true, GameState: autoplayTestState(false)}, nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/sess-1/autoplay", map[string]interface{}{
+		"strategy":         "random",
+		"moves_per_second": 20,
+		"max_moves":        1000,
+	}))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+
+	// Only one autoplay per session
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/sess-1/autoplay", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d for second autoplay, got %d", http.StatusConflict, w.Code)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("DELETE", "/api/sessions/sess-1/autoplay", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d stopping autoplay, got %d", http.StatusOK, w.Code)
+	}
+	if atomic.LoadInt32(&moves) == 0 {
+		t.Error("Expected autoplay to issue at least one move")
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("DELETE", "/api/sessions/sess-1/autoplay", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d when no autoplay is running, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestAutoplay_StopsOnGameOver(t *testing.T) {
+	mockService := &MockGameService{
+		GetGameStateFunc: func(ctx context.Context, sessionID string) (*engine.GameState, error) {
+			return autoplayTestState(false), nil
+		},
+		MoveWithOptionsFunc: func(ctx context.Context, sessionID, direction string, opts service.MoveOptions) (*service.MoveResult, error) {
+			return &service.MoveResult{Success: true, GameState: autoplayTestState(true)}, nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/sess-2/autoplay", map[string]interface{}{
+		"strategy":         "greedy",
+		"moves_per_second": 20,
+	}))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+
+	waitForAutoplayExit(t, server, "sess-2")
+}
+
+func TestAutoplay_StopsOnSessionDelete(t *testing.T) {
+	mockService := &MockGameService{
+		GetGameStateFunc: func(ctx context.Context, sessionID string) (*engine.GameState, error) {
+			return autoplayTestState(false), nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/sess-3/autoplay", map[string]interface{}{
+		"moves_per_second": 1,
+	}))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("DELETE", "/api/sessions/sess-3", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d deleting session, got %d", http.StatusOK, w.Code)
+	}
+
+	waitForAutoplayExit(t, server, "sess-3")
+}
+
+func TestAutoplay_InvalidRequests(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+
+	tests := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"unknown strategy", map[string]interface{}{"strategy": "teleport"}},
+		{"rate too high", map[string]interface{}{"moves_per_second": 1000}},
+		{"negative max moves", map[string]interface{}{"max_moves": -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, makeRequest("POST", "/api/sessions/sess-4/autoplay", tt.body))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestAutoplay_Solver(t *testing.T) {
+	configDir := t.TempDir()
+	cfg := `{
+  "name": "Autoplay Solver",
+  "description": "Two parks on either side of home",
+  "grid_size": 5,
+  "max_battery": 4,
+  "starting_battery": 4,
+  "layout": ["BBBBB", "PRHRP", "BBRBB", "BBPBB", "BBBBB"],
+  "legend": {"R": "road", "H": "home", "P": "park", "S": "supercharger", "W": "water", "B": "building"},
+  "messages": {
+    "welcome": "Welcome!",
+    "park_visited": "Park visited! Score: %d",
+    "victory": "Victory! All %d parks visited!",
+    "out_of_battery": "Out of battery! Game Over!"
+  }
+}`
+	if err := os.WriteFile(filepath.Join(configDir, "solver.json"), []byte(cfg), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	configs, err := config.NewManager(configDir)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	svc := service.NewGameService(session.NewManager(), configs)
+	server := NewServer(svc, nil)
+
+	info, err := svc.CreateSession(context.Background(), "solver")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/"+info.ID+"/autoplay", map[string]interface{}{
+		"strategy":         "solver",
+		"moves_per_second": 20,
+	}))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	waitForAutoplayExit(t, server, info.ID)
+
+	state, err := svc.GetGameState(context.Background(), info.ID)
+	if err != nil {
+		t.Fatalf("GetGameState failed: %v", err)
+	}
+	if !state.Victory {
+		t.Fatalf("Expected the solver to win, got score %d battery %d: %s", state.Score, state.Battery, state.Message)
+	}
+	for _, move := range state.CurrentMoves {
+		if !move.Autoplay {
+			t.Errorf("Expected every move to be tagged as autoplay, got %+v", move)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// DefaultMaxBodyBytes is the largest request body accepted unless
+// SetMaxBodyBytes changes it
+const DefaultMaxBodyBytes = 1 << 20
+
+// MaxRequestMoves is the longest moves array a bulk move request may carry.
+// The service still executes at most engine.MaxBulkMoves of them and reports
+// the rest as truncated; longer arrays are turned down before any move is
+// processed.
+const MaxRequestMoves = 10 * engine.MaxBulkMoves
+
+// SetMaxBodyBytes limits the size of request bodies; larger ones are
+// answered with a 400. 0 or less restores DefaultMaxBodyBytes.
+func (s *Server) SetMaxBodyBytes(n int64) {
+	if n <= 0 {
+		n = DefaultMaxBodyBytes
+	}
+	s.maxBodyBytes = n
+}
+
+// SetStrictJSON makes request bodies with fields the endpoint doesn't know
+// fail with a 400 instead of having those fields ignored
+func (s *Server) SetStrictJSON(enabled bool) {
+	s.strictJSON = enabled
+}
+
+// bodyLimitMiddleware caps every request body at the configured size, so a
+// huge body fails to decode instead of exhausting memory
+func (s *Server) bodyLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, s.maxBodyBytes)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decodeBody decodes the JSON request body into v, rejecting unknown fields
+// when strict JSON is on. An empty body yields io.EOF, which handlers whose
+// body is optional ignore; an oversized one yields a *http.MaxBytesError.
+func (s *Server) decodeBody(r *http.Request, v any) error {
+	if r.Body == nil {
+		return io.EOF
+	}
+	decoder := json.NewDecoder(r.Body)
+	if s.strictJSON {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(v)
+}
+
+// bodyErrorMessage describes why a request body could not be decoded
+func bodyErrorMessage(err error) string {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit)
+	}
+	return "Invalid request body"
+}
+
+// checkMovesLength turns down a moves array longer than MaxRequestMoves
+func checkMovesLength(moves []string) error {
+	if len(moves) > MaxRequestMoves {
+		return fmt.Errorf("moves cannot exceed %d entries, got %d", MaxRequestMoves, len(moves))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+)
+
+func TestBodyLimit_OversizedBody(t *testing.T) {
+	moved := false
+	server := setupTestServer(&MockGameService{
+		BulkMoveWithOptionsFunc: func(ctx context.Context, sessionID string, moves []string, opts service.BulkMoveOptions) (*service.BulkMoveResult, error) {
+			moved = true
+			return &service.BulkMoveResult{GameState: &engine.GameState{}}, nil
+		},
+	})
+	server.SetMaxBodyBytes(64)
+
+	w := httptest.NewRecorder()
+	body := `{"moves": ["up"], "intent": "` + strings.Repeat("x", 100) + `"}`
+	req := httptest.NewRequest("POST", "/api/sessions/test-session/bulk-move", strings.NewReader(body))
+	server.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for an oversized body, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "exceeds 64 bytes") {
+		t.Errorf("Expected the size limit in the error, got %s", w.Body.String())
+	}
+	if moved {
+		t.Error("Expected an oversized body not to reach the service")
+	}
+
+	// A body under the limit still goes through
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/bulk-move", map[string][]string{"moves": {"up"}}))
+	if w.Code != http.StatusOK || !moved {
+		t.Errorf("Expected a small body to be served, got %d", w.Code)
+	}
+}
+
+func TestBodyLimit_MovesCap(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		BulkMoveWithOptionsFunc: func(ctx context.Context, sessionID string, moves []string, opts service.BulkMoveOptions) (*service.BulkMoveResult, error) {
+			t.Errorf("Expected %d moves to be turned down before the service", len(moves))
+			return nil, nil
+		},
+		BulkMoveMultiFunc: func(ctx context.Context, sessionIDs []string, moves []string, opts service.BulkMoveOptions) (*service.MultiBulkMoveResult, error) {
+			t.Errorf("Expected %d moves to be turned down before the service", len(moves))
+			return nil, nil
+		},
+	})
+
+	moves := make([]string, MaxRequestMoves+1)
+	for i := range moves {
+		moves[i] = "up"
+	}
+	requests := map[string]interface{}{
+		"/api/sessions/test-session/bulk-move": map[string]interface{}{"moves": moves},
+		"/api/sessions/bulk-move-multi":        map[string]interface{}{"session_ids": []string{"a"}, "moves": moves},
+	}
+	for path, body := range requests {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("POST", path, body))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 for %d moves, got %d", path, len(moves), w.Code)
+		}
+	}
+}
+
+func TestBodyLimit_StrictJSON(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+	body := map[string]string{"direction": "up", "directon": "up"}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/move", body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected unknown fields to be ignored by default, got %d", w.Code)
+	}
+
+	server.SetStrictJSON(true)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/move", body))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown field in strict mode, got %d", w.Code)
+	}
+}
+
+func TestBodyLimit_MalformedCreateSession(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("POST", "/api/sessions", strings.NewReader(`{"config_id":`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed body, got %d", w.Code)
+	}
+
+	// The body stays optional
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("POST", "/api/sessions", nil))
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected an empty body to create a session, got %d", w.Code)
+	}
+}
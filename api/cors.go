@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSConfig controls the cross-origin headers added to API responses
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins; "*" allows any origin and an entry
+	// ending in ":*" (e.g. "http://localhost:*") allows any port on that host
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// DefaultCORSConfig allows browser clients served from localhost on any port
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins: []string{"http://localhost:*", "http://127.0.0.1:*"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+	}
+}
+
+// allowOrigin returns the value for Access-Control-Allow-Origin, or "" when
+// the origin is not allowed
+func (c CORSConfig) allowOrigin(origin string) string {
+	for _, allowed := range c.AllowedOrigins {
+		switch {
+		case allowed == "*":
+			return "*"
+		case allowed == origin:
+			return origin
+		case strings.HasSuffix(allowed, ":*"):
+			host := strings.TrimSuffix(allowed, ":*")
+			if origin == host || strings.HasPrefix(origin, host+":") {
+				return origin
+			}
+		}
+	}
+	return ""
+}
+
+// SetCORSConfig replaces the CORS settings used for subsequent requests
+func (s *Server) SetCORSConfig(cfg CORSConfig) {
+	s.cors = cfg
+}
+
+// corsMiddleware adds CORS headers for allowed origins and answers preflight
+// requests before they reach the router, which would reject OPTIONS on
+// POST-only routes such as move
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		allowed := s.cors.allowOrigin(origin)
+		if allowed != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(s.cors.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(s.cors.AllowedHeaders, ", "))
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if allowed == "" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+)
+
+func TestCORS_CrossOriginGet(t *testing.T) {
+	mockService := &MockGameService{
+		ListSessionsFunc: func(ctx context.Context) ([]*service.SessionInfo, error) {
+			return []*service.SessionInfo{}, nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	req := makeRequest("GET", "/api/sessions", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:3000" {
+		t.Errorf("Expected Access-Control-Allow-Origin for localhost, got %q", got)
+	}
+	if w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Error("Expected Access-Control-Allow-Methods header")
+	}
+
+	// Origins outside the defaults get no CORS headers
+	req = makeRequest("GET", "/api/sessions", nil)
+	req.Header.Set("Origin", "https://example.com")
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no Access-Control-Allow-Origin for foreign origin, got %q", got)
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+	server.SetCORSConfig(CORSConfig{
+		AllowedOrigins: []string{"https://game.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type"},
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		origin     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"sessions", "/api/sessions", "https://game.example.com", http.StatusNoContent, "https://game.example.com"},
+		{"move", "/api/sessions/abc/move", "https://game.example.com", http.StatusNoContent, "https://game.example.com"},
+		{"bulk move", "/api/sessions/abc/bulk-move", "https://game.example.com", http.StatusNoContent, "https://game.example.com"},
+		{"overridden default", "/api/sessions/abc/move", "http://localhost:3000", http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("OPTIONS", tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.wantOrigin, got)
+			}
+			if tt.wantOrigin != "" && w.Header().Get("Access-Control-Allow-Methods") != "GET, POST" {
+				t.Errorf("Expected configured methods, got %q", w.Header().Get("Access-Control-Allow-Methods"))
+			}
+		})
+	}
+}
+
+func TestCORSConfig_AllowOrigin(t *testing.T) {
+	cfg := DefaultCORSConfig()
+	tests := []struct {
+		origin string
+		want   string
+	}{
+		{"http://localhost:8080", "http://localhost:8080"},
+		{"http://localhost", "http://localhost"},
+		{"http://127.0.0.1:5173", "http://127.0.0.1:5173"},
+		{"http://localhost.evil.com", ""},
+		{"https://example.com", ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.allowOrigin(tt.origin); got != tt.want {
+			t.Errorf("allowOrigin(%q) = %q, want %q", tt.origin, got, tt.want)
+		}
+	}
+
+	cfg.AllowedOrigins = []string{"*"}
+	if got := cfg.allowOrigin("https://example.com"); got != "*" {
+		t.Errorf("Expected wildcard to allow any origin, got %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// SetDebug enables the debug endpoints under /api/sessions/{id}/debug, which
+// let callers set up game states directly. Keep it off in production.
+func (s *Server) SetDebug(enabled bool) {
+	s.debug = enabled
+}
+
+// teleportRequest is the body accepted by POST /api/sessions/{id}/debug/teleport
+type teleportRequest struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (s *Server) handleTeleport(w http.ResponseWriter, r *http.Request) {
+	if !s.debug {
+		respondError(w, http.StatusNotFound, "Debug endpoints are disabled; start the server with -debug")
+		return
+	}
+
+	sessionID := mux.Vars(r)["id"]
+
+	var req teleportRequest
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+
+	result, err := s.service.Teleport(r.Context(), sessionID, req.X, req.Y)
+	if err != nil {
+		if errors.Is(err, engine.ErrImpassable) || errors.Is(err, engine.ErrGameOver) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// Broadcast to WebSocket clients
+	if s.hub != nil {
+		s.hub.BroadcastToSession(sessionID, result.GameState)
+	}
+
+	s.logger.Info("teleport", "session_id", sessionID, "to", engine.Position{X: req.X, Y: req.Y},
+		"battery", result.GameState.Battery, "score", result.GameState.Score)
+
+	respondJSON(w, http.StatusOK, result)
+}
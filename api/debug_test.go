@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+)
+
+func TestTeleport(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		TeleportFunc: func(ctx context.Context, sessionID string, x, y int) (*service.MoveResult, error) {
+			if x == 2 && y == 2 {
+				return nil, fmt.Errorf("%w: (%d,%d)", engine.ErrImpassable, x, y)
+			}
+			return &service.MoveResult{Success: true, GameState: &engine.GameState{PlayerPos: engine.Position{X: x, Y: y}}}, nil
+		},
+	})
+
+	// Hidden until debug is enabled
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/debug/teleport", map[string]int{"x": 1, "y": 3}))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 without debug, got %d", w.Code)
+	}
+
+	server.SetDebug(true)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/debug/teleport", map[string]int{"x": 1, "y": 3}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var result service.MoveResult
+	parseResponse(t, w, &result)
+	if result.GameState.PlayerPos != (engine.Position{X: 1, Y: 3}) {
+		t.Errorf("Expected player at (1,3), got %+v", result.GameState.PlayerPos)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/debug/teleport", map[string]int{"x": 2, "y": 2}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an impassable cell, got %d", w.Code)
+	}
+}
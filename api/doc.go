@@ -30,13 +30,18 @@
 //
 // Request/Response Format:
 //
+// GET /api/openapi.json serves an OpenAPI 3.1 document generated from the
+// request and response types below (see apiOperations in openapi.go), and
+// GET /api/docs renders it with Swagger UI. The spec is authoritative where
+// this comment disagrees.
+//
 // All endpoints accept and return JSON. Session-specific operations
 // support optional sessionId query parameter for targeting specific sessions.
 //
 // Actions are sent as POST with JSON body:
 //
 //	{
-//	  "action": "up|down|left|right|reset|save|load",
+//	  "action": "up|down|left|right|charge|reset|save|load",
 //	  "actions": ["up", "down", "left"], // for bulk moves
 //	  "reset": true|false,                // optional reset before move
 //	  "saveFile": "save_123.json"         // for load action
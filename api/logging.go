@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+)
+
+// SetRequestLogging enables one log line per request with method, path,
+// status, duration and session ID
+func (s *Server) SetRequestLogging(enabled bool) {
+	s.logRequests = enabled
+}
+
+// SetLogger sends request lines and the per-action summaries to logger
+// instead of service.DefaultLogger
+func (s *Server) SetLogger(logger service.Logger) {
+	s.logger = logger
+}
+
+// statusRecorder captures the response status for request logging
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Hijack lets websocket upgrades through the wrapper; the upgrader writes the
+// 101 response on the raw connection, so it is recorded here
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// loggingMiddleware logs a single summary line per request when request
+// logging is enabled. Bodies and headers are never logged.
+func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.logRequests {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		args := []any{"method", r.Method, "path", r.URL.Path, "status", status,
+			"duration", time.Since(start).Round(time.Microsecond)}
+		if isWebSocketUpgrade(r) {
+			args = append(args, "upgrade", "websocket")
+		}
+		if id := requestSessionID(r); id != "" {
+			args = append(args, "session_id", id)
+		}
+		s.logger.Info("http request", args...)
+	})
+}
+
+// requestSessionID extracts the session ID from /api/sessions/{id}/... paths
+// or the session query parameter used by the websocket endpoint. The
+// middleware wraps the router, so mux route variables are not available.
+func requestSessionID(r *http.Request) string {
+	if id := r.URL.Query().Get("session"); id != "" {
+		return id
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) >= 3 && parts[0] == "api" && parts[1] == "sessions" {
+		switch parts[2] {
+		case "unified", "compare":
+			return ""
+		}
+		return parts[2]
+	}
+	return ""
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/internal/logtest"
+)
+
+func TestRequestLogging_LogsStatus(t *testing.T) {
+	mockService := &MockGameService{
+		GetGameStateFunc: func(ctx context.Context, sessionID string) (*engine.GameState, error) {
+			return nil, errors.New("session not found")
+		},
+	}
+	server := setupTestServer(mockService)
+	logger := &logtest.Recorder{}
+	server.SetLogger(logger)
+
+	// Disabled by default
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/missing/state", nil))
+	if got := logger.Find("http request"); len(got) != 0 {
+		t.Fatalf("Expected no request log when disabled, got %+v", got)
+	}
+
+	server.SetRequestLogging(true)
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/missing/state", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404, got %d", w.Code)
+	}
+
+	got := logger.Find("http request")
+	if len(got) != 1 {
+		t.Fatalf("Expected a single request log entry, got %+v", got)
+	}
+	entry := got[0]
+	if entry.Level != "INFO" || entry.Attrs["method"] != "GET" || entry.Attrs["path"] != "/api/sessions/missing/state" ||
+		entry.Attrs["status"] != http.StatusNotFound {
+		t.Errorf("Expected 404 request log entry, got %+v", entry)
+	}
+	if entry.Attrs["session_id"] != "missing" {
+		t.Errorf("Expected session ID in log entry, got %+v", entry)
+	}
+	if _, ok := entry.Attrs["duration"].(time.Duration); !ok {
+		t.Errorf("Expected the request duration, got %+v", entry)
+	}
+}
+
+func TestRequestLogging_WebSocketUpgrade(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+	server.SetRequestLogging(true)
+	logger := &logtest.Recorder{}
+	server.SetLogger(logger)
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	// The wrapped writer must still allow the connection to be hijacked
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws?session=abc", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect through logging middleware: %v", err)
+	}
+	defer conn.Close()
+
+	// The entry is logged once the handler returns, after the handshake
+	deadline := time.Now().Add(time.Second)
+	for len(logger.Find("http request")) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	got := logger.Find("http request")
+	if len(got) != 1 {
+		t.Fatalf("Expected the upgrade to be logged, got %+v", got)
+	}
+	entry := got[0]
+	if entry.Attrs["path"] != "/ws" || entry.Attrs["status"] != http.StatusSwitchingProtocols ||
+		entry.Attrs["upgrade"] != "websocket" || entry.Attrs["session_id"] != "abc" {
+		t.Errorf("Expected upgrade to be logged with status 101, got %+v", entry)
+	}
+}
+
+func TestRequestSessionID(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/api/sessions/abc123/move", "abc123"},
+		{"/api/sessions/abc123", "abc123"},
+		{"/api/sessions", ""},
+		{"/api/sessions/unified", ""},
+		{"/ws?session=xyz", "xyz"},
+		{"/api/configs/classic", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.target, nil)
+		if got := requestSessionID(req); got != tt.want {
+			t.Errorf("requestSessionID(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,532 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+	"github.com/wricardo/tesla-road-trip-game/transport/webhook"
+)
+
+// schemaOf names the Go type whose JSON encoding a request or response uses
+func schemaOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// object describes an ad-hoc map response. Values are reflect.Types or
+// nested objects; every property is required.
+type object map[string]interface{}
+
+// queryParam documents an optional query string or header parameter
+type queryParam struct {
+	name        string
+	kind        string // JSON schema type
+	description string
+}
+
+// apiOperation documents one route registered in setupRoutes
+type apiOperation struct {
+	method   string
+	path     string // Relative to /api
+	summary  string
+	query    []queryParam
+	header   []queryParam
+	request  reflect.Type // Body type, nil when the route takes none
+	status   int
+	response interface{} // reflect.Type or object
+	// plainText marks routes that answer with text/plain unless asked for JSON
+	plainText bool
+}
+
+// idempotencyKeyParam documents the Idempotency-Key header of the move routes
+var idempotencyKeyParam = queryParam{idempotencyKeyHeader, "string",
+	"Retrying with the same key returns the first result instead of moving again; reusing it for a different request is a 422"}
+
+// viewRadiusParam documents the grid crop of the state and move routes
+var viewRadiusParam = queryParam{"viewRadius", "integer",
+	"Return only the grid cells within this many steps of the player; the state's view gives the crop's origin"}
+
+// messageResponse is the shape of simple confirmation responses
+var messageResponse = object{"message": schemaOf[string]()}
+
+// apiOperations lists the documented REST endpoints. Request and response
+// shapes reference the types the handlers actually encode, so the schemas
+// follow the structs; TestOpenAPI_* keeps this table in step with the router.
+var apiOperations = []apiOperation{
+	{method: "POST", path: "/sessions", summary: "Create a session",
+		request: schemaOf[createSessionRequest](), status: http.StatusCreated, response: schemaOf[service.SessionInfo]()},
+	{method: "GET", path: "/sessions", summary: "List sessions",
+		query: []queryParam{
+			{"sort", "string", "created or accessed (default)"},
+			{"order", "string", "asc or desc (default)"},
+			{"limit", "integer", "Maximum number of sessions to return"},
+			{"label", "string", "Only sessions whose label contains this, ignoring case"},
+		},
+		status: http.StatusOK, response: object{
+			"count":    schemaOf[int](),
+			"total":    schemaOf[int](),
+			"sessions": schemaOf[[]*service.SessionInfo](),
+			"sort":     schemaOf[string](),
+			"order":    schemaOf[string](),
+		}},
+	{method: "DELETE", path: "/sessions", summary: "Delete every session matching a filter; unknown filters are rejected",
+		query: []queryParam{
+			{"configName", "string", "Only sessions playing this config"},
+			{"gameOver", "boolean", "Only finished (true) or unfinished (false) sessions"},
+		},
+		status: http.StatusOK, response: schemaOf[deleteSessionsResponse]()},
+	{method: "GET", path: "/sessions/unified", summary: "Sessions for the multi-session view",
+		query: []queryParam{
+			{"sessionIds", "string", "Comma-separated session IDs"},
+			{"configName", "string", "Only sessions playing this config"},
+		},
+		status: http.StatusOK, response: object{
+			"config_name": schemaOf[string](),
+			"total_parks": schemaOf[int](),
+			"sessions": []object{{
+				"session_id":    schemaOf[string](),
+				"config_name":   schemaOf[string](),
+				"game_state":    schemaOf[*engine.GameState](),
+				"created_at":    schemaOf[time.Time](),
+				"last_accessed": schemaOf[time.Time](),
+				"label":         schemaOf[string](),
+			}},
+		}},
+	{method: "GET", path: "/sessions/compare", summary: "Compare two sessions on the same config",
+		query: []queryParam{
+			{"a", "string", "First session ID (required)"},
+			{"b", "string", "Second session ID (required)"},
+		},
+		status: http.StatusOK, response: schemaOf[service.SessionComparison]()},
+	{method: "POST", path: "/sessions/bulk-move-multi", summary: "Execute the same sequence of moves on several sessions",
+		request: schemaOf[bulkMoveMultiRequest](), status: http.StatusOK, response: schemaOf[service.MultiBulkMoveResult]()},
+	{method: "GET", path: "/sessions/{id}", summary: "Get a session",
+		status: http.StatusOK, response: schemaOf[service.SessionInfo]()},
+	{method: "PATCH", path: "/sessions/{id}", summary: "Set a session's label",
+		request: schemaOf[updateSessionRequest](), status: http.StatusOK, response: schemaOf[service.SessionInfo]()},
+	{method: "DELETE", path: "/sessions/{id}", summary: "Delete a session",
+		status: http.StatusOK, response: messageResponse},
+	{method: "POST", path: "/sessions/{id}/clone", summary: "Branch a new session from this session's current state",
+		request: schemaOf[cloneSessionRequest](), status: http.StatusCreated, response: schemaOf[service.SessionInfo]()},
+	{method: "GET", path: "/sessions/{id}/state", summary: "Get the game state",
+		query:  []queryParam{viewRadiusParam},
+		status: http.StatusOK, response: schemaOf[engine.GameState]()},
+	{method: "POST", path: "/sessions/{id}/move", summary: "Move one step or charge",
+		query:   []queryParam{viewRadiusParam},
+		header:  []queryParam{idempotencyKeyParam},
+		request: schemaOf[moveRequest](), status: http.StatusOK, response: schemaOf[service.MoveResult]()},
+	{method: "POST", path: "/sessions/{id}/park", summary: "Collect the park the player stands on",
+		query:  []queryParam{viewRadiusParam},
+		status: http.StatusOK, response: schemaOf[service.MoveResult]()},
+	{method: "POST", path: "/sessions/{id}/wait", summary: "Stay put for a turn",
+		query:  []queryParam{viewRadiusParam},
+		status: http.StatusOK, response: schemaOf[service.MoveResult]()},
+	{method: "POST", path: "/sessions/{id}/move-toward", summary: "Take one step along the shortest road to a cell",
+		query:   []queryParam{viewRadiusParam},
+		request: schemaOf[moveTowardRequest](), status: http.StatusOK, response: schemaOf[service.MoveTowardResult]()},
+	{method: "POST", path: "/sessions/{id}/bulk-move", summary: "Execute a sequence of moves",
+		query:   []queryParam{viewRadiusParam},
+		header:  []queryParam{idempotencyKeyParam},
+		request: schemaOf[bulkMoveRequest](), status: http.StatusOK, response: schemaOf[service.BulkMoveResult]()},
+	{method: "POST", path: "/sessions/{id}/reset", summary: "Reset the game",
+		query: []queryParam{
+			{"keep", "integer", "Replay this many of the game's moves after resetting, stepping back to where it stood then; clamped to the moves made"},
+		},
+		status: http.StatusOK, response: object{
+			"message": schemaOf[string](),
+			"state":   schemaOf[*engine.GameState](),
+		}},
+	{method: "POST", path: "/sessions/{id}/surrender", summary: "Give up the game as a defeat, keeping the session for review",
+		status: http.StatusOK, response: object{
+			"message": schemaOf[string](),
+			"state":   schemaOf[*engine.GameState](),
+		}},
+	{method: "GET", path: "/sessions/{id}/history", summary: "Paginated move history",
+		query: []queryParam{
+			{"page", "integer", "1-based page number"},
+			{"limit", "integer", "Moves per page (default the config's default_history_page_size, else 20; at most 100)"},
+			{"order", "string", "asc or desc (default)"},
+		},
+		status: http.StatusOK, response: schemaOf[service.HistoryResponse]()},
+	{method: "GET", path: "/sessions/{id}/history/{index}", summary: "One move by its 1-based history index, with the tile entered and event caused",
+		status: http.StatusOK, response: schemaOf[service.MoveDetail]()},
+	{method: "GET", path: "/sessions/{id}/ghost", summary: "Another session's run on the same config, for racing against",
+		query: []queryParam{
+			{"from", "string", "Session whose run to replay (required)"},
+		},
+		status: http.StatusOK, response: schemaOf[service.GhostPath]()},
+	{method: "GET", path: "/sessions/{id}/parks", summary: "List every park with its collected status",
+		status: http.StatusOK, response: schemaOf[service.ParksResponse]()},
+	{method: "GET", path: "/sessions/{id}/risk", summary: "The battery risk band with its thresholds and the battery left",
+		status: http.StatusOK, response: schemaOf[engine.RiskAssessment]()},
+	{method: "GET", path: "/sessions/{id}/eventlog", summary: "The session's most recent events, oldest first",
+		status: http.StatusOK, response: schemaOf[service.EventLogResponse]()},
+	{method: "GET", path: "/sessions/{id}/config", summary: "The config a session plays on, with its checksum",
+		status: http.StatusOK, response: schemaOf[service.SessionConfig]()},
+	{method: "POST", path: "/sessions/{id}/solve", summary: "Compute a winning move plan from the current state",
+		status: http.StatusOK, response: schemaOf[service.SolveResult]()},
+	{method: "GET", path: "/sessions/{id}/plan", summary: "Compute a winning plan as steps annotated with the park, key or charger each heads for",
+		status: http.StatusOK, response: schemaOf[planResponse]()},
+	{method: "GET", path: "/sessions/{id}/verify", summary: "Replay the current game from its history and report the first field that differs from the live state",
+		status: http.StatusOK, response: schemaOf[service.VerifyResult]()},
+	{method: "POST", path: "/sessions/{id}/autoplay", summary: "Start server-side autoplay",
+		request: schemaOf[autoplayRequest](), status: http.StatusAccepted, response: object{
+			"message":  schemaOf[string](),
+			"autoplay": schemaOf[*autoplayRun](),
+		}},
+	{method: "DELETE", path: "/sessions/{id}/autoplay", summary: "Stop autoplay",
+		status: http.StatusOK, response: messageResponse},
+	{method: "POST", path: "/sessions/{id}/debug/teleport", summary: "Place the player on a passable cell (server must run with -debug)",
+		request: schemaOf[teleportRequest](), status: http.StatusOK, response: schemaOf[service.MoveResult]()},
+	{method: "GET", path: "/leaderboard", summary: "Rank sessions by their current game",
+		query: []queryParam{
+			{"config", "string", "Only sessions playing this config"},
+			{"sort", "string", "score (default) or moves, which ranks only victories"},
+			{"limit", "integer", "Maximum number of entries to return"},
+		},
+		status: http.StatusOK, response: schemaOf[service.Leaderboard]()},
+	{method: "POST", path: "/shared-sessions", summary: "Create a competitive session shared by several players",
+		request: schemaOf[createSharedSessionRequest](), status: http.StatusCreated, response: schemaOf[service.SharedSessionInfo]()},
+	{method: "GET", path: "/shared-sessions/{id}", summary: "Get a shared session",
+		status: http.StatusOK, response: schemaOf[service.SharedSessionInfo]()},
+	{method: "POST", path: "/shared-sessions/{id}/players/{playerId}/move", summary: "Move one player of a shared session",
+		request: schemaOf[sharedMoveRequest](), status: http.StatusOK, response: schemaOf[service.SharedMoveResult]()},
+	{method: "GET", path: "/configs", summary: "List configurations",
+		status: http.StatusOK, response: schemaOf[[]*service.ConfigInfo]()},
+	{method: "POST", path: "/configs", summary: "Save a configuration",
+		request: schemaOf[engine.GameConfig](), status: http.StatusCreated, response: object{
+			"message":   schemaOf[string](),
+			"config_id": schemaOf[string](),
+		}},
+	{method: "POST", path: "/configs/validate", summary: "Check a configuration without saving it, listing every problem",
+		request: schemaOf[engine.GameConfig](), status: http.StatusOK, response: schemaOf[configValidationResponse]()},
+	{method: "GET", path: "/configs/{name}", summary: "Get a configuration",
+		status: http.StatusOK, response: schemaOf[engine.GameConfig]()},
+	{method: "GET", path: "/configs/{name}/analysis", summary: "Difficulty analysis for a configuration",
+		status: http.StatusOK, response: schemaOf[engine.ConfigAnalysis]()},
+	{method: "GET", path: "/configs/{name}/analytics", summary: "Win rate, moves and game over reasons across the sessions playing a configuration",
+		status: http.StatusOK, response: schemaOf[service.ConfigAnalytics]()},
+	{method: "GET", path: "/configs/{name}/preview", summary: "Preview the board a configuration starts on",
+		query: []queryParam{
+			{"format", "string", "text (default) for the rendered grid, or json for the initial game state"},
+		},
+		status: http.StatusOK, response: schemaOf[engine.GameState](), plainText: true},
+	{method: "POST", path: "/webhooks", summary: "Register a webhook",
+		request: schemaOf[createWebhookRequest](), status: http.StatusCreated, response: schemaOf[webhook.Webhook]()},
+	{method: "GET", path: "/webhooks", summary: "List webhooks",
+		status: http.StatusOK, response: object{
+			"count":          schemaOf[int](),
+			"webhooks":       schemaOf[[]webhook.Webhook](),
+			"dropped_events": schemaOf[uint64](),
+		}},
+	{method: "DELETE", path: "/webhooks/{id}", summary: "Delete a webhook",
+		status: http.StatusOK, response: messageResponse},
+}
+
+// enumValues lists the allowed values of string types with a fixed set
+var enumValues = map[reflect.Type][]string{
+	schemaOf[engine.CellType](): {
+		string(engine.Road), string(engine.Home), string(engine.Park),
+		string(engine.Supercharger), string(engine.Water), string(engine.Building), string(engine.Fuel),
+		string(engine.Trickle), string(engine.Checkpoint), string(engine.Key), string(engine.Door),
+	},
+	schemaOf[engine.GameOverReason](): {
+		string(engine.GameOverVictory), string(engine.GameOverOutOfBattery), string(engine.GameOverStranded),
+		string(engine.GameOverWallCrash), string(engine.GameOverMaxMoves), string(engine.GameOverManual),
+		string(engine.GameOverHazard), string(engine.GameOverSurrendered), string(engine.GameOverParksExpired),
+	},
+	schemaOf[engine.GameOutcome](): {string(engine.OutcomeVictory), string(engine.OutcomeDefeat)},
+	schemaOf[engine.ResultReason](): {
+		string(engine.ResultAllParks), string(engine.ResultOutOfBattery), string(engine.ResultStranded),
+		string(engine.ResultWallCrash), string(engine.ResultMoveLimit), string(engine.ResultManual),
+		string(engine.ResultHazard), string(engine.ResultSurrendered), string(engine.ResultParksExpired),
+	},
+}
+
+// enumDescriptions documents enum types whose values need more than a name
+var enumDescriptions = map[reflect.Type]string{
+	schemaOf[engine.GameOverReason](): "Why the game ended. max_moves and manual are reserved: nothing ends a game with them yet.",
+	schemaOf[engine.ResultReason]():   "Why the game ended. move_limit and manual are reserved: nothing ends a game with them yet.",
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// openAPISpec builds the OpenAPI 3.1 document served at /api/openapi.json
+func openAPISpec() map[string]interface{} {
+	b := &schemaBuilder{
+		schemas: map[string]interface{}{
+			"Error": map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{"error": map[string]interface{}{"type": "string"}},
+				"required":             []string{"error"},
+				"additionalProperties": false,
+			},
+		},
+		names: make(map[reflect.Type]string),
+	}
+
+	paths := make(map[string]interface{})
+	for _, op := range apiOperations {
+		var params []interface{}
+		for _, m := range pathParamPattern.FindAllStringSubmatch(op.path, -1) {
+			params = append(params, map[string]interface{}{
+				"name": m[1], "in": "path", "required": true,
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, q := range op.query {
+			params = append(params, map[string]interface{}{
+				"name": q.name, "in": "query", "description": q.description,
+				"schema": map[string]interface{}{"type": q.kind},
+			})
+		}
+		for _, h := range op.header {
+			params = append(params, map[string]interface{}{
+				"name": h.name, "in": "header", "description": h.description,
+				"schema": map[string]interface{}{"type": h.kind},
+			})
+		}
+
+		success := jsonContent("Success", b.resolve(op.response))
+		if op.plainText {
+			content := success["content"].(map[string]interface{})
+			content["text/plain"] = map[string]interface{}{"schema": map[string]interface{}{"type": "string"}}
+		}
+		operation := map[string]interface{}{
+			"summary": op.summary,
+			"responses": map[string]interface{}{
+				strconv.Itoa(op.status): success,
+				"default":               jsonContent("Error", map[string]interface{}{"$ref": "#/components/schemas/Error"}),
+			},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if op.request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": b.schema(op.request)},
+				},
+			}
+		}
+
+		path := "/api" + op.path
+		item, _ := paths[path].(map[string]interface{})
+		if item == nil {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+		item[strings.ToLower(op.method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":       "Tesla Road Trip Game API",
+			"version":     "1.0.0",
+			"description": "REST API for playing Tesla Road Trip sessions. Live updates are available over the /ws websocket.",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": b.schemas},
+	}
+}
+
+func jsonContent(description string, schema interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// schemaBuilder derives JSON schemas from Go types following encoding/json
+// rules. Named structs are emitted once under components/schemas.
+type schemaBuilder struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+// resolve turns an apiOperation response into a schema
+func (b *schemaBuilder) resolve(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case reflect.Type:
+		return b.nullable(v, b.schema(v))
+	case object:
+		properties := make(map[string]interface{}, len(v))
+		required := make([]string, 0, len(v))
+		for name, prop := range v {
+			properties[name] = b.resolve(prop)
+			required = append(required, name)
+		}
+		sort.Strings(required)
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	case []object:
+		return map[string]interface{}{"type": "array", "items": b.resolve(v[0])}
+	}
+	panic("openapi: unsupported response description")
+}
+
+// nullable allows null for types encoding/json writes as null when nil
+func (b *schemaBuilder) nullable(t reflect.Type, schema map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+	default:
+		return schema
+	}
+	if typ, ok := schema["type"].(string); ok {
+		out := make(map[string]interface{}, len(schema))
+		for k, v := range schema {
+			out[k] = v
+		}
+		out["type"] = []string{typ, "null"}
+		return out
+	}
+	if len(schema) == 0 {
+		return schema // Already accepts anything
+	}
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
+
+// schema returns the schema for t, referencing named structs
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	if t == schemaOf[time.Time]() {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		s := map[string]interface{}{"type": "string"}
+		if values, ok := enumValues[t]; ok {
+			s["enum"] = values
+		}
+		if description, ok := enumDescriptions[t]; ok {
+			s["description"] = description
+		}
+		return s
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.nullable(t.Elem(), b.schema(t.Elem()))}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.nullable(t.Elem(), b.schema(t.Elem()))}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name, ok := b.names[t]
+		if !ok {
+			name = b.componentName(t)
+			b.names[t] = name
+			b.schemas[name] = nil // Reserve the name for recursive types
+			b.schemas[name] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+// componentName exports the Go type name, qualifying it with the package on collision
+func (b *schemaBuilder) componentName(t reflect.Type) string {
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+	if _, taken := b.schemas[string(name)]; !taken {
+		return string(name)
+	}
+	pkg := []rune(t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:])
+	pkg[0] = unicode.ToUpper(pkg[0])
+	return string(pkg) + string(name)
+}
+
+// structSchema describes a struct's JSON object. Fields without omitempty
+// are always encoded and therefore required.
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	b.addFields(t, properties, &required)
+	sort.Strings(required)
+
+	s := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			b.addFields(f.Type, properties, required) // Embedded fields are promoted
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		properties[name] = b.nullable(f.Type, b.schema(f.Type))
+		if !strings.Contains(opts, "omitempty") || f.Type.Kind() == reflect.Struct {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func (s *Server) handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, openAPISpec())
+}
+
+// docsPage renders the spec with Swagger UI loaded from a CDN
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>Tesla Road Trip Game API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({ url: "/api/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
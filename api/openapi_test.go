@@ -0,0 +1,399 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/wricardo/tesla-road-trip-game/game/config"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+	"github.com/wricardo/tesla-road-trip-game/game/session"
+	"github.com/wricardo/tesla-road-trip-game/transport/webhook"
+	"github.com/wricardo/tesla-road-trip-game/transport/websocket"
+)
+
+// loadSpec fetches the served document and decodes it generically, the way
+// an integrator's tooling would see it
+func loadSpec(t *testing.T, server *Server) map[string]interface{} {
+	t.Helper()
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for spec, got %d", w.Code)
+	}
+	var spec map[string]interface{}
+	parseResponse(t, w, &spec)
+	return spec
+}
+
+// validateSchema checks a decoded JSON value against the subset of JSON
+// Schema the generated document uses
+func validateSchema(spec map[string]interface{}, schema map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		target, ok := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})[name].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: unresolved reference %s", path, ref)
+		}
+		return validateSchema(spec, target, value, path)
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		var errs []string
+		for _, option := range anyOf {
+			err := validateSchema(spec, option.(map[string]interface{}), value, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s: matches no anyOf option (%s)", path, strings.Join(errs, "; "))
+	}
+
+	if typ, ok := schema["type"]; ok {
+		var types []string
+		switch typ := typ.(type) {
+		case string:
+			types = []string{typ}
+		case []interface{}:
+			for _, t := range typ {
+				types = append(types, t.(string))
+			}
+		}
+		matched := false
+		for _, t := range types {
+			if jsonTypeMatches(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected %v, got %T", path, types, value)
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, v := range enum {
+			if v == value {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, present := v[name.(string)]; !present {
+					return fmt.Errorf("%s: missing required property %q", path, name)
+				}
+			}
+		}
+		for key, item := range v {
+			propSchema, declared := properties[key].(map[string]interface{})
+			if !declared {
+				switch extra := schema["additionalProperties"].(type) {
+				case bool:
+					if !extra {
+						return fmt.Errorf("%s: undocumented property %q", path, key)
+					}
+					continue
+				case map[string]interface{}:
+					propSchema = extra
+				default:
+					continue
+				}
+			}
+			if err := validateSchema(spec, propSchema, item, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := validateSchema(spec, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func jsonTypeMatches(typ string, value interface{}) bool {
+	switch typ {
+	case "null":
+		return value == nil
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	}
+	return false
+}
+
+// responseSchema picks the documented schema for a route and status
+func responseSchema(t *testing.T, spec map[string]interface{}, method, route string, status int) map[string]interface{} {
+	t.Helper()
+	item, ok := spec["paths"].(map[string]interface{})[route].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Route %s is not documented", route)
+	}
+	op, ok := item[strings.ToLower(method)].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Operation %s %s is not documented", method, route)
+	}
+	responses := op["responses"].(map[string]interface{})
+	response, ok := responses[fmt.Sprint(status)].(map[string]interface{})
+	if !ok {
+		response = responses["default"].(map[string]interface{})
+	}
+	return response["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+	spec := loadSpec(t, server)
+	paths := spec["paths"].(map[string]interface{})
+
+	documented := 0
+	for _, item := range paths {
+		documented += len(item.(map[string]interface{}))
+	}
+
+	registered := 0
+	err := server.router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		tmpl, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(tmpl, "/api/") {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		switch tmpl {
+		case "/api/openapi.json", "/api/docs":
+			return nil
+		}
+		for _, method := range methods {
+			registered++
+			item, _ := paths[tmpl].(map[string]interface{})
+			if _, ok := item[strings.ToLower(method)]; !ok {
+				t.Errorf("%s %s is registered but missing from the OpenAPI document", method, tmpl)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	if documented != registered {
+		t.Errorf("Document lists %d operations but the router has %d", documented, registered)
+	}
+}
+
+func TestOpenAPI_HandlerResponsesMatchSchema(t *testing.T) {
+	// Real service stack so responses carry fully populated state
+	configDir := t.TempDir()
+	data, err := os.ReadFile("../configs/classic.json")
+	if err != nil {
+		t.Fatalf("Failed to read classic config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "classic.json"), data, 0644); err != nil {
+		t.Fatalf("Failed to copy classic config: %v", err)
+	}
+	configs, err := config.NewManager(configDir)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	svc := service.NewGameService(session.NewManager(), configs)
+	hub := websocket.NewHub()
+	go hub.Run()
+	server := NewServer(svc, hub)
+
+	webhooks, err := webhook.NewManager(filepath.Join(t.TempDir(), "webhooks.json"))
+	if err != nil {
+		t.Fatalf("Failed to create webhook manager: %v", err)
+	}
+	defer webhooks.Close()
+	server.SetWebhooks(webhooks)
+
+	spec := loadSpec(t, server)
+
+	// call performs a request and validates the body against the route's schema
+	call := func(method, route, target string, body interface{}) map[string]interface{} {
+		t.Helper()
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest(method, target, body))
+
+		var decoded interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("%s %s: invalid JSON: %v", method, target, err)
+		}
+		schema := responseSchema(t, spec, method, route, w.Code)
+		if err := validateSchema(spec, schema, decoded, "response"); err != nil {
+			t.Errorf("%s %s (%d): %v", method, target, w.Code, err)
+		}
+		obj, _ := decoded.(map[string]interface{})
+		return obj
+	}
+
+	created := call("POST", "/api/sessions", "/api/sessions", map[string]string{"config_id": "classic"})
+	id, _ := created["id"].(string)
+	if id == "" {
+		t.Fatalf("Expected a session ID, got %v", created)
+	}
+	other, _ := call("POST", "/api/sessions", "/api/sessions", nil)["id"].(string)
+
+	call("PATCH", "/api/sessions/{id}", "/api/sessions/"+id, map[string]string{"label": "Morning run"})
+	call("GET", "/api/sessions", "/api/sessions?limit=5", nil)
+	call("GET", "/api/sessions", "/api/sessions?label=morning", nil)
+	call("GET", "/api/sessions/unified", "/api/sessions/unified", nil)
+	call("GET", "/api/sessions/{id}", "/api/sessions/"+id, nil)
+	call("GET", "/api/sessions/{id}/state", "/api/sessions/"+id+"/state", nil)
+	call("POST", "/api/sessions/{id}/clone", "/api/sessions/"+id+"/clone", map[string]bool{"copy_history": true})
+
+	call("POST", "/api/sessions/{id}/move", "/api/sessions/"+id+"/move", map[string]string{"direction": "left", "intent": "explore"})
+	call("POST", "/api/sessions/{id}/move", "/api/sessions/"+id+"/move", map[string]string{"direction": "charge"})
+	call("POST", "/api/sessions/{id}/move", "/api/sessions/"+id+"/move", map[string]string{"direction": "up"})
+	call("POST", "/api/sessions/{id}/park", "/api/sessions/"+id+"/park", nil)
+	call("POST", "/api/sessions/{id}/wait", "/api/sessions/"+id+"/wait", nil)
+	call("POST", "/api/sessions/{id}/move-toward", "/api/sessions/"+id+"/move-toward", map[string]int{"x": 0, "y": 0})
+	call("POST", "/api/sessions/{id}/bulk-move", "/api/sessions/"+id+"/bulk-move", map[string]interface{}{
+		"moves": []string{"right", "right", "down", "down"},
+	})
+	call("POST", "/api/sessions/{id}/bulk-move", "/api/sessions/"+id+"/bulk-move", map[string]interface{}{
+		"moves": []string{"down", "left", "left"}, "continue_on_block": true,
+	})
+	call("POST", "/api/sessions/bulk-move-multi", "/api/sessions/bulk-move-multi", map[string]interface{}{
+		"session_ids": []string{id, other}, "moves": []string{"left"},
+	})
+	call("GET", "/api/sessions/{id}/history", "/api/sessions/"+id+"/history?limit=2", nil)
+	call("GET", "/api/sessions/{id}/history/{index}", "/api/sessions/"+id+"/history/1", nil)
+	call("GET", "/api/sessions/{id}/parks", "/api/sessions/"+id+"/parks", nil)
+	call("GET", "/api/sessions/{id}/risk", "/api/sessions/"+id+"/risk", nil)
+	call("GET", "/api/sessions/{id}/eventlog", "/api/sessions/"+id+"/eventlog", nil)
+	call("GET", "/api/sessions/{id}/config", "/api/sessions/"+id+"/config", nil)
+	server.SetDebug(true)
+	call("POST", "/api/sessions/{id}/debug/teleport", "/api/sessions/"+id+"/debug/teleport", map[string]int{"x": 1, "y": 1})
+	call("POST", "/api/sessions/{id}/debug/teleport", "/api/sessions/"+id+"/debug/teleport", map[string]int{"x": -1, "y": 0})
+	if verify := call("GET", "/api/sessions/{id}/verify", "/api/sessions/"+id+"/verify", nil); verify["verified"] != true {
+		t.Errorf("Expected the session's history to replay to its state, got %v", verify)
+	}
+	call("POST", "/api/sessions/{id}/solve", "/api/sessions/"+other+"/solve", nil)
+	call("GET", "/api/sessions/{id}/plan", "/api/sessions/"+other+"/plan", nil)
+	call("GET", "/api/sessions/compare", "/api/sessions/compare?a="+id+"&b="+other, nil)
+	call("GET", "/api/leaderboard", "/api/leaderboard?config=classic&limit=10", nil)
+	call("GET", "/api/sessions/{id}/ghost", "/api/sessions/"+other+"/ghost?from="+id, nil)
+	call("POST", "/api/sessions/{id}/reset", "/api/sessions/"+id+"/reset", nil)
+	call("POST", "/api/sessions/{id}/surrender", "/api/sessions/"+id+"/surrender", nil)
+
+	call("POST", "/api/sessions/{id}/autoplay", "/api/sessions/"+id+"/autoplay", map[string]interface{}{"moves_per_second": 1, "max_moves": 1})
+	call("DELETE", "/api/sessions/{id}/autoplay", "/api/sessions/"+id+"/autoplay", nil)
+	waitForAutoplayExit(t, server, id)
+
+	shared := call("POST", "/api/shared-sessions", "/api/shared-sessions", map[string]interface{}{
+		"config_id": "classic", "players": []string{"alice", "bob"},
+	})
+	sharedID, _ := shared["id"].(string)
+	call("POST", "/api/shared-sessions/{id}/players/{playerId}/move", "/api/shared-sessions/"+sharedID+"/players/alice/move", map[string]string{"direction": "right"})
+	call("GET", "/api/shared-sessions/{id}", "/api/shared-sessions/"+sharedID, nil)
+
+	call("GET", "/api/configs", "/api/configs", nil)
+	classic := call("GET", "/api/configs/{name}", "/api/configs/classic", nil)
+	call("GET", "/api/configs/{name}/analysis", "/api/configs/classic/analysis", nil)
+	call("GET", "/api/configs/{name}/analytics", "/api/configs/classic/analytics", nil)
+	call("GET", "/api/configs/{name}/preview", "/api/configs/classic/preview?format=json", nil)
+	classic["name"] = "copy"
+	call("POST", "/api/configs/validate", "/api/configs/validate", classic)
+	call("POST", "/api/configs", "/api/configs", classic)
+
+	hook := call("POST", "/api/webhooks", "/api/webhooks", map[string]interface{}{
+		"url": "http://example.com/hook", "events": []string{"victory"},
+	})
+	call("GET", "/api/webhooks", "/api/webhooks", nil)
+	call("DELETE", "/api/webhooks/{id}", "/api/webhooks/"+fmt.Sprint(hook["id"]), nil)
+
+	// Error responses follow the shared Error schema
+	call("GET", "/api/sessions/{id}", "/api/sessions/missing", nil)
+	call("DELETE", "/api/sessions/{id}", "/api/sessions/"+other, nil)
+	call("DELETE", "/api/sessions", "/api/sessions", nil)
+	call("DELETE", "/api/sessions", "/api/sessions?configName=classic&gameOver=false", nil)
+}
+
+func TestOpenAPI_DocsPage(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+	spec := loadSpec(t, server)
+	if spec["openapi"] != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %v", spec["openapi"])
+	}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/docs", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("Expected HTML docs page, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Body.String(), "/api/openapi.json") {
+		t.Error("Docs page should load the served spec")
+	}
+}
+
+func TestOpenAPI_ReservedReasons(t *testing.T) {
+	spec := loadSpec(t, setupTestServer(&MockGameService{}))
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	for schema, property := range map[string]string{"GameState": "game_over_reason", "GameResult": "reason"} {
+		properties := schemas[schema].(map[string]interface{})["properties"].(map[string]interface{})
+		description, _ := properties[property].(map[string]interface{})["description"].(string)
+		if !strings.Contains(description, "reserved") {
+			t.Errorf("Expected %s.%s to mark its unused values reserved, got %q", schema, property, description)
+		}
+	}
+}
+
+func TestValidateSchema_RejectsDrift(t *testing.T) {
+	spec := openAPISpec()
+	raw, _ := json.Marshal(spec)
+	var decoded map[string]interface{}
+	json.Unmarshal(raw, &decoded)
+
+	schema := map[string]interface{}{"$ref": "#/components/schemas/Position"}
+	if err := validateSchema(decoded, schema, map[string]interface{}{"x": 1.0, "y": 2.0}, "pos"); err != nil {
+		t.Errorf("Valid position rejected: %v", err)
+	}
+	if err := validateSchema(decoded, schema, map[string]interface{}{"x": 1.0}, "pos"); err == nil {
+		t.Error("Expected missing property to be rejected")
+	}
+	if err := validateSchema(decoded, schema, map[string]interface{}{"x": 1.0, "y": 2.0, "z": 3.0}, "pos"); err == nil {
+		t.Error("Expected undocumented property to be rejected")
+	}
+	if err := validateSchema(decoded, schema, map[string]interface{}{"x": 1.5, "y": 2.0}, "pos"); err == nil {
+		t.Error("Expected non-integer coordinate to be rejected")
+	}
+}
@@ -3,16 +3,22 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/wricardo/tesla-road-trip-game/game/engine"
 	"github.com/wricardo/tesla-road-trip-game/game/service"
+	"github.com/wricardo/tesla-road-trip-game/game/strategy"
+	"github.com/wricardo/tesla-road-trip-game/transport/webhook"
 	"github.com/wricardo/tesla-road-trip-game/transport/websocket"
 )
 
@@ -21,17 +27,44 @@ type Server struct {
 	service service.GameService
 	hub     *websocket.Hub
 	router  *mux.Router
+	handler http.Handler // router wrapped in middleware
+	cors    CORSConfig
+
+	// Log a summary line per request, see SetRequestLogging
+	logRequests bool
+
+	// Optional webhook registry, see SetWebhooks
+	webhooks *webhook.Manager
+
+	// Serve the debug endpoints, see SetDebug
+	debug bool
+
+	// Background autoplay runs keyed by session ID
+	autoplays  map[string]*autoplayRun
+	autoplayMu sync.Mutex
+
+	// Receives request lines and per-action summaries, see SetLogger
+	logger service.Logger
+
+	// Request body limits, see SetMaxBodyBytes and SetStrictJSON
+	maxBodyBytes int64
+	strictJSON   bool
 }
 
 // NewServer creates a new API server
 func NewServer(gameService service.GameService, hub *websocket.Hub) *Server {
 	s := &Server{
-		service: gameService,
-		hub:     hub,
-		router:  mux.NewRouter(),
+		service:      gameService,
+		hub:          hub,
+		router:       mux.NewRouter(),
+		autoplays:    make(map[string]*autoplayRun),
+		cors:         DefaultCORSConfig(),
+		logger:       service.DefaultLogger(),
+		maxBodyBytes: DefaultMaxBodyBytes,
 	}
 
 	s.setupRoutes()
+	s.handler = s.loggingMiddleware(s.corsMiddleware(s.bodyLimitMiddleware(s.router)))
 	return s
 }
 
@@ -43,22 +76,70 @@ func (s *Server) setupRoutes() {
 	// Session management
 	api.HandleFunc("/sessions", s.handleCreateSession).Methods("POST")
 	api.HandleFunc("/sessions", s.handleListSessions).Methods("GET")
+	api.HandleFunc("/sessions", s.handleDeleteSessions).Methods("DELETE")
 	// Unified sessions for multi-session view (must be before {id} pattern)
 	api.HandleFunc("/sessions/unified", s.handleUnifiedSessions).Methods("GET")
+	api.HandleFunc("/sessions/compare", s.handleCompareSessions).Methods("GET")
+	api.HandleFunc("/sessions/bulk-move-multi", s.handleBulkMoveMulti).Methods("POST")
 	api.HandleFunc("/sessions/{id}", s.handleGetSession).Methods("GET")
+	api.HandleFunc("/sessions/{id}", s.handleUpdateSession).Methods("PATCH")
 	api.HandleFunc("/sessions/{id}", s.handleDeleteSession).Methods("DELETE")
+	api.HandleFunc("/sessions/{id}/clone", s.handleCloneSession).Methods("POST")
 
 	// Game operations
 	api.HandleFunc("/sessions/{id}/state", s.handleGetGameState).Methods("GET")
 	api.HandleFunc("/sessions/{id}/move", s.handleMove).Methods("POST")
+	api.HandleFunc("/sessions/{id}/park", s.handlePark).Methods("POST")
+	api.HandleFunc("/sessions/{id}/wait", s.handleWait).Methods("POST")
 	api.HandleFunc("/sessions/{id}/bulk-move", s.handleBulkMove).Methods("POST")
+	api.HandleFunc("/sessions/{id}/move-toward", s.handleMoveToward).Methods("POST")
 	api.HandleFunc("/sessions/{id}/reset", s.handleReset).Methods("POST")
+	api.HandleFunc("/sessions/{id}/surrender", s.handleSurrender).Methods("POST")
 	api.HandleFunc("/sessions/{id}/history", s.handleGetHistory).Methods("GET")
+	api.HandleFunc("/sessions/{id}/history/{index}", s.handleGetMove).Methods("GET")
+	api.HandleFunc("/sessions/{id}/parks", s.handleGetParks).Methods("GET")
+	api.HandleFunc("/sessions/{id}/risk", s.handleGetRisk).Methods("GET")
+	api.HandleFunc("/sessions/{id}/eventlog", s.handleGetEventLog).Methods("GET")
+	api.HandleFunc("/sessions/{id}/config", s.handleGetSessionConfig).Methods("GET")
+	api.HandleFunc("/sessions/{id}/ghost", s.handleGetGhost).Methods("GET")
+	api.HandleFunc("/sessions/{id}/solve", s.handleSolve).Methods("POST")
+	api.HandleFunc("/sessions/{id}/plan", s.handleSolvePlan).Methods("GET")
+	api.HandleFunc("/sessions/{id}/verify", s.handleVerifySession).Methods("GET")
+	api.HandleFunc("/sessions/{id}/autoplay", s.handleStartAutoplay).Methods("POST")
+	api.HandleFunc("/sessions/{id}/autoplay", s.handleStopAutoplay).Methods("DELETE")
+
+	// Debugging, answered only when enabled with SetDebug
+	api.HandleFunc("/sessions/{id}/debug/teleport", s.handleTeleport).Methods("POST")
+
+	// Leaderboard across sessions
+	api.HandleFunc("/leaderboard", s.handleGetLeaderboard).Methods("GET")
+
+	// Shared competitive sessions
+	api.HandleFunc("/shared-sessions", s.handleCreateSharedSession).Methods("POST")
+	api.HandleFunc("/shared-sessions/{id}", s.handleGetSharedSession).Methods("GET")
+	api.HandleFunc("/shared-sessions/{id}/players/{playerId}/move", s.handleSharedMove).Methods("POST")
 
 	// Configuration
 	api.HandleFunc("/configs", s.handleListConfigs).Methods("GET")
 	api.HandleFunc("/configs", s.handleCreateConfig).Methods("POST")
+	api.HandleFunc("/configs/validate", s.handleValidateConfig).Methods("POST")
 	api.HandleFunc("/configs/{name}", s.handleGetConfig).Methods("GET")
+	api.HandleFunc("/configs/{name}/analysis", s.handleAnalyzeConfig).Methods("GET")
+	api.HandleFunc("/configs/{name}/analytics", s.handleConfigAnalytics).Methods("GET")
+	api.HandleFunc("/configs/{name}/preview", s.handlePreviewConfig).Methods("GET")
+
+	// Webhooks
+	api.HandleFunc("/webhooks", s.handleCreateWebhook).Methods("POST")
+	api.HandleFunc("/webhooks", s.handleListWebhooks).Methods("GET")
+	api.HandleFunc("/webhooks/{id}", s.handleDeleteWebhook).Methods("DELETE")
+
+	// API documentation
+	api.HandleFunc("/openapi.json", s.handleOpenAPISpec).Methods("GET")
+	api.HandleFunc("/docs", s.handleDocs).Methods("GET")
+
+	// Liveness and readiness probes
+	s.router.HandleFunc("/healthz", s.handleHealth).Methods("GET")
+	s.router.HandleFunc("/readyz", s.handleReady).Methods("GET")
 
 	// WebSocket
 	s.router.HandleFunc("/ws", s.handleWebSocket)
@@ -69,7 +150,7 @@ func (s *Server) setupRoutes() {
 
 // ServeHTTP implements http.Handler
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	s.router.ServeHTTP(w, r)
+	s.handler.ServeHTTP(w, r)
 }
 
 // Response helpers
@@ -83,16 +164,132 @@ func respondError(w http.ResponseWriter, status int, message string) {
 	respondJSON(w, status, map[string]string{"error": message})
 }
 
+// idempotencyKeyHeader lets clients retry a move or bulk move safely: a
+// retry with the same key gets the first result back instead of moving again
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// viewRadius reads the optional viewRadius query parameter, which crops the
+// returned grid to the cells within that many steps of the player; ok is
+// false when it is absent
+func viewRadius(r *http.Request) (radius int, ok bool, err error) {
+	raw := r.URL.Query().Get("viewRadius")
+	if raw == "" {
+		return 0, false, nil
+	}
+	radius, err = strconv.Atoi(raw)
+	if err != nil || radius < 0 {
+		return 0, false, fmt.Errorf("viewRadius must be a non-negative integer, got %q", raw)
+	}
+	return radius, true, nil
+}
+
+// cropMoveResult returns a copy of result whose state is cropped to radius,
+// leaving the service's result untouched
+func cropMoveResult(result *service.MoveResult, radius int) *service.MoveResult {
+	cropped := *result
+	cropped.GameState = result.GameState.CropView(radius)
+	return &cropped
+}
+
+// moveErrorStatus maps a move or bulk move error to its HTTP status
+func moveErrorStatus(err error) int {
+	if errors.Is(err, service.ErrIdempotencyKeyReused) {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, engine.ErrGameOver) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// Request bodies, named so the OpenAPI document can describe them
+
+// createSessionRequest is the body accepted by POST /api/sessions
+type createSessionRequest struct {
+	ConfigID   string `json:"config_id,omitempty"`
+	ConfigName string `json:"config_name,omitempty"` // Deprecated, use config_id
+	TTLSeconds int    `json:"ttl_seconds,omitempty"` // Inactivity timeout; defaults to the server-wide window
+}
+
+// updateSessionRequest is the body accepted by PATCH /api/sessions/{id}
+type updateSessionRequest struct {
+	Label *string `json:"label"` // User-facing name; empty clears it
+}
+
+// cloneSessionRequest is the optional body accepted by POST /api/sessions/{id}/clone
+type cloneSessionRequest struct {
+	CopyHistory bool `json:"copy_history,omitempty"`
+}
+
+// moveRequest is the body accepted by POST /api/sessions/{id}/move
+type moveRequest struct {
+	Direction string `json:"direction"`
+	Reset     bool   `json:"reset,omitempty"`
+	Intent    string `json:"intent,omitempty"`
+}
+
+// moveTowardRequest is the body accepted by POST /api/sessions/{id}/move-toward
+type moveTowardRequest struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// bulkMoveRequest is the body accepted by POST /api/sessions/{id}/bulk-move
+type bulkMoveRequest struct {
+	Moves           []string `json:"moves"`
+	Reset           bool     `json:"reset,omitempty"`
+	ContinueOnBlock bool     `json:"continue_on_block,omitempty"`
+	Intent          string   `json:"intent,omitempty"`
+	// Intents annotates each move by index; it may be shorter than Moves
+	Intents []string `json:"intents,omitempty"`
+	// StopBelowBattery stops the sequence once battery drops below it; 0 disables
+	StopBelowBattery int `json:"stop_below_battery,omitempty"`
+}
+
+// bulkMoveMultiRequest is the body accepted by POST /api/sessions/bulk-move-multi
+type bulkMoveMultiRequest struct {
+	SessionIDs       []string `json:"session_ids"`
+	Moves            []string `json:"moves"`
+	Reset            bool     `json:"reset,omitempty"`
+	ContinueOnBlock  bool     `json:"continue_on_block,omitempty"`
+	Intent           string   `json:"intent,omitempty"`
+	StopBelowBattery int      `json:"stop_below_battery,omitempty"`
+}
+
+// createSharedSessionRequest is the body accepted by POST /api/shared-sessions
+type createSharedSessionRequest struct {
+	ConfigID string   `json:"config_id,omitempty"`
+	Players  []string `json:"players"`
+	// HideOpponentStats masks other players' battery and score in each
+	// player's updates
+	HideOpponentStats bool `json:"hide_opponent_stats,omitempty"`
+}
+
+// sharedMoveRequest is the body accepted by POST /api/shared-sessions/{id}/players/{playerId}/move
+type sharedMoveRequest struct {
+	Direction string `json:"direction"`
+}
+
+// deleteSessionsResponse is the result of DELETE /api/sessions
+type deleteSessionsResponse struct {
+	Deleted    int      `json:"deleted"`
+	SessionIDs []string `json:"session_ids"`
+}
+
+// configValidationResponse is the result of POST /api/configs/validate
+type configValidationResponse struct {
+	Valid    bool                       `json:"valid"`
+	Problems []engine.ValidationProblem `json:"problems,omitempty"`
+}
+
 // Session Handlers
 
 func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		ConfigID   string `json:"config_id,omitempty"`
-		ConfigName string `json:"config_name,omitempty"` // Deprecated, use config_id
-	}
+	var req createSessionRequest
 
-	if r.Body != nil {
-		json.NewDecoder(r.Body).Decode(&req)
+	if err := s.decodeBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
 	}
 
 	// Support both new and old parameter names, but prefer config_id
@@ -100,8 +297,12 @@ func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
 	if configID == "" && req.ConfigName != "" {
 		configID = req.ConfigName
 	}
+	if req.TTLSeconds < 0 {
+		respondError(w, http.StatusBadRequest, "ttl_seconds must not be negative")
+		return
+	}
 
-	session, err := s.service.CreateSession(r.Context(), configID)
+	session, err := s.service.CreateSessionWithOptions(r.Context(), configID, service.CreateSessionOptions{TTLSeconds: req.TTLSeconds})
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -122,6 +323,14 @@ func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
 	sortBy := query.Get("sort")    // "created", "accessed" (default)
 	order := query.Get("order")    // "asc", "desc" (default: "desc")
 	limitStr := query.Get("limit") // number of sessions to return
+	label := query.Get("label")    // case-insensitive substring of the label
+
+	if label != "" {
+		label = strings.ToLower(label)
+		sessions = slices.DeleteFunc(sessions, func(session *service.SessionInfo) bool {
+			return !strings.Contains(strings.ToLower(session.Label), label)
+		})
+	}
 
 	// Set defaults
 	if sortBy == "" {
@@ -177,6 +386,33 @@ func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, session)
 }
 
+// handleUpdateSession changes a session's metadata; only the label can be set
+func (s *Server) handleUpdateSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["id"]
+
+	var req updateSessionRequest
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+	if req.Label == nil {
+		respondError(w, http.StatusBadRequest, "label is required")
+		return
+	}
+
+	session, err := s.service.SetSessionLabel(r.Context(), sessionID, *req.Label)
+	if err != nil {
+		if errors.Is(err, service.ErrLabelTooLong) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, session)
+}
+
 func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	sessionID := vars["id"]
@@ -187,23 +423,96 @@ func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A deleted session can no longer be played
+	s.stopAutoplay(sessionID)
+
 	respondJSON(w, http.StatusOK, map[string]string{
 		"message": fmt.Sprintf("Session %s deleted", sessionID),
 	})
 }
 
+// deleteSessionsFilters are the query parameters DELETE /api/sessions accepts
+var deleteSessionsFilters = []string{"configName", "gameOver"}
+
+// handleDeleteSessions deletes every session matching the configName and
+// gameOver query parameters; at least one is required. Any other parameter
+// is rejected rather than ignored, so a filter the server doesn't know, such
+// as ?tag=, can't widen the delete to every session the rest match.
+func (s *Server) handleDeleteSessions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	for param := range query {
+		if !slices.Contains(deleteSessionsFilters, param) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("Unknown filter '%s'; supported filters are configName and gameOver", param))
+			return
+		}
+	}
+	filter := service.SessionFilter{ConfigName: query.Get("configName")}
+	if raw := query.Get("gameOver"); raw != "" {
+		gameOver, err := strconv.ParseBool(raw)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "gameOver must be true or false")
+			return
+		}
+		filter.GameOver = &gameOver
+	}
+	if filter.Empty() {
+		respondError(w, http.StatusBadRequest, "At least one filter is required: configName or gameOver")
+		return
+	}
+
+	deleted, err := s.service.DeleteSessions(r.Context(), filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Deleted sessions can no longer be played
+	for _, sessionID := range deleted {
+		s.stopAutoplay(sessionID)
+	}
+
+	respondJSON(w, http.StatusOK, deleteSessionsResponse{Deleted: len(deleted), SessionIDs: deleted})
+}
+
+func (s *Server) handleCloneSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["id"]
+
+	var req cloneSessionRequest
+	if err := s.decodeBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+
+	session, err := s.service.CloneSession(r.Context(), sessionID, req.CopyHistory)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, session)
+}
+
 // Game Operation Handlers
 
 func (s *Server) handleGetGameState(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	sessionID := vars["id"]
 
+	radius, crop, err := viewRadius(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	state, err := s.service.GetGameState(r.Context(), sessionID)
 	if err != nil {
 		respondError(w, http.StatusNotFound, err.Error())
 		return
 	}
 
+	if crop {
+		state = state.CropView(radius)
+	}
 	respondJSON(w, http.StatusOK, state)
 }
 
@@ -211,19 +520,25 @@ func (s *Server) handleMove(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	sessionID := vars["id"]
 
-	var req struct {
-		Direction string `json:"direction"`
-		Reset     bool   `json:"reset,omitempty"`
-	}
+	var req moveRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+	radius, crop, err := viewRadius(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	result, err := s.service.Move(r.Context(), sessionID, req.Direction, req.Reset)
+	result, err := s.service.MoveWithOptions(r.Context(), sessionID, req.Direction, service.MoveOptions{
+		Reset:          req.Reset,
+		Intent:         req.Intent,
+		IdempotencyKey: r.Header.Get(idempotencyKeyHeader),
+	})
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondError(w, moveErrorStatus(err), err.Error())
 		return
 	}
 
@@ -234,39 +549,160 @@ func (s *Server) handleMove(w http.ResponseWriter, r *http.Request) {
 
 	// Compact server log for observability
 	if result.Step != nil {
-		s := result.Step
-		status := "FAIL"
-		if result.Success {
-			status = "OK"
-		}
-		fmt.Printf("[MOVE] session=%s %s (%d,%d)->(%d,%d) tile=%s batt=%d status=%s\n",
-			sessionID, s.Dir, s.From.X, s.From.Y, s.To.X, s.To.Y, s.TileChar, s.BatteryAfter, status)
+		step := result.Step
+		s.logger.Info("move", "session_id", sessionID, "dir", step.Dir, "from", step.From, "to", step.To,
+			"tile", step.TileChar, "battery", step.BatteryAfter, "success", result.Success)
 	} else if result.AttemptedTo != nil {
 		a := result.AttemptedTo
-		fmt.Printf("[MOVE] session=%s BLOCKED attempt=(%d,%d) tile=%s type=%s\n",
-			sessionID, a.X, a.Y, a.TileChar, a.TileType)
+		s.logger.Info("move blocked", "session_id", sessionID, "attempt", engine.Position{X: a.X, Y: a.Y},
+			"tile", a.TileChar, "type", a.TileType)
 	}
 
+	if crop {
+		result = cropMoveResult(result, radius)
+	}
 	respondJSON(w, http.StatusOK, result)
 }
 
-func (s *Server) handleBulkMove(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleMoveToward(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["id"]
+
+	var req moveTowardRequest
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+	radius, crop, err := viewRadius(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.service.MoveToward(r.Context(), sessionID, req.X, req.Y)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTargetUnreachable), errors.Is(err, service.ErrAlreadyAtTarget):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, engine.ErrGameOver):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusNotFound, err.Error())
+		}
+		return
+	}
+
+	// Broadcast to WebSocket clients
+	if s.hub != nil {
+		s.hub.BroadcastToSession(sessionID, result.GameState)
+	}
+
+	s.logger.Info("move toward", "session_id", sessionID, "target", result.Target, "dir", result.Direction,
+		"remaining", result.RemainingPath, "battery", result.GameState.Battery, "success", result.Success)
+
+	if crop {
+		result.MoveResult = cropMoveResult(result.MoveResult, radius)
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handlePark(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	sessionID := vars["id"]
 
-	var req struct {
-		Moves []string `json:"moves"`
-		Reset bool     `json:"reset,omitempty"`
+	radius, crop, err := viewRadius(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	result, err := s.service.Move(r.Context(), sessionID, engine.ActionPark, false)
+	if err != nil {
+		respondError(w, moveErrorStatus(err), err.Error())
 		return
 	}
 
-	result, err := s.service.BulkMove(r.Context(), sessionID, req.Moves, req.Reset)
+	// Broadcast to WebSocket clients
+	if s.hub != nil {
+		s.hub.BroadcastToSession(sessionID, result.GameState)
+	}
+
+	s.logger.Info("park", "session_id", sessionID, "at", result.GameState.PlayerPos,
+		"score", result.GameState.Score, "success", result.Success)
+
+	if crop {
+		result = cropMoveResult(result, radius)
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleWait(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	radius, crop, err := viewRadius(r)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.service.Move(r.Context(), sessionID, engine.ActionWait, false)
+	if err != nil {
+		respondError(w, moveErrorStatus(err), err.Error())
+		return
+	}
+
+	// Broadcast to WebSocket clients
+	if s.hub != nil {
+		s.hub.BroadcastToSession(sessionID, result.GameState)
+	}
+
+	s.logger.Info("wait", "session_id", sessionID, "at", result.GameState.PlayerPos,
+		"battery", result.GameState.Battery, "success", result.Success)
+
+	if crop {
+		result = cropMoveResult(result, radius)
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleBulkMove(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	var req bulkMoveRequest
+
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+	if req.StopBelowBattery < 0 {
+		respondError(w, http.StatusBadRequest, "stop_below_battery cannot be negative")
+		return
+	}
+	if err := checkMovesLength(req.Moves); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Intents) > len(req.Moves) {
+		respondError(w, http.StatusBadRequest, "intents cannot outnumber moves")
+		return
+	}
+	radius, crop, err := viewRadius(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.service.BulkMoveWithOptions(r.Context(), sessionID, req.Moves, service.BulkMoveOptions{
+		Reset:            req.Reset,
+		ContinueOnBlock:  req.ContinueOnBlock,
+		Intent:           req.Intent,
+		Intents:          req.Intents,
+		StopBelowBattery: req.StopBelowBattery,
+		IdempotencyKey:   r.Header.Get(idempotencyKeyHeader),
+	})
+	if err != nil {
+		respondError(w, moveErrorStatus(err), err.Error())
 		return
 	}
 
@@ -284,8 +720,56 @@ func (s *Server) handleBulkMove(w http.ResponseWriter, r *http.Request) {
 	if stop == "" && result.StoppedReason != "" {
 		stop = "stopped"
 	}
-	fmt.Printf("[BULK] session=%s exec=%d/%d stop=%s end=(%d,%d) batt=%d scoreΔ=%d\n",
-		sessionID, result.MovesExecuted, requested, stop, result.GameState.PlayerPos.X, result.GameState.PlayerPos.Y, result.GameState.Battery, result.ScoreDelta)
+	s.logger.Info("bulk move", "session_id", sessionID, "executed", result.MovesExecuted, "requested", requested,
+		"blocked", result.BlockedCount, "stop", stop, "end", result.GameState.PlayerPos,
+		"battery", result.GameState.Battery, "score_delta", result.ScoreDelta)
+
+	if crop {
+		cropped := *result
+		cropped.GameState = result.GameState.CropView(radius)
+		result = &cropped
+	}
+	respondJSON(w, http.StatusOK, result)
+}
+
+// handleBulkMoveMulti applies one move sequence to several sessions; the
+// sessions that couldn't be moved are reported without failing the request
+func (s *Server) handleBulkMoveMulti(w http.ResponseWriter, r *http.Request) {
+	var req bulkMoveMultiRequest
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+	if err := checkMovesLength(req.Moves); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.StopBelowBattery < 0 {
+		respondError(w, http.StatusBadRequest, "stop_below_battery cannot be negative")
+		return
+	}
+
+	result, err := s.service.BulkMoveMulti(r.Context(), req.SessionIDs, req.Moves, service.BulkMoveOptions{
+		Reset:            req.Reset,
+		ContinueOnBlock:  req.ContinueOnBlock,
+		Intent:           req.Intent,
+		StopBelowBattery: req.StopBelowBattery,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSessionList) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Broadcast to WebSocket clients
+	if s.hub != nil {
+		for sessionID, res := range result.Results {
+			s.hub.BroadcastToSession(sessionID, res.GameState)
+		}
+	}
 
 	respondJSON(w, http.StatusOK, result)
 }
@@ -294,8 +778,50 @@ func (s *Server) handleReset(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	sessionID := vars["id"]
 
-	state, err := s.service.Reset(r.Context(), sessionID)
+	// keep steps back to that many moves into the game instead of the start
+	keepStr := r.URL.Query().Get("keep")
+	keep, err := strconv.Atoi(keepStr)
+	if keepStr != "" && err != nil {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("keep must be an integer, got %q", keepStr))
+		return
+	}
+
+	var state *engine.GameState
+	if keepStr != "" {
+		state, err = s.service.ResetAndReplay(r.Context(), sessionID, keep)
+	} else {
+		state, err = s.service.Reset(r.Context(), sessionID)
+	}
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	message := "Game reset successfully"
+	if keepStr != "" {
+		message = fmt.Sprintf("Game reset and %d moves replayed", len(state.CurrentMoves))
+	}
+
+	// Broadcast to WebSocket clients
+	if s.hub != nil {
+		s.hub.BroadcastToSession(sessionID, state)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": message,
+		"state":   state,
+	})
+}
+
+// handleSurrender gives up a session's game; the session stays for review
+func (s *Server) handleSurrender(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["id"]
+
+	state, err := s.service.Surrender(r.Context(), sessionID)
 	if err != nil {
+		if errors.Is(err, engine.ErrGameOver) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
 		respondError(w, http.StatusNotFound, err.Error())
 		return
 	}
@@ -306,7 +832,7 @@ func (s *Server) handleReset(w http.ResponseWriter, r *http.Request) {
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"message": "Game reset successfully",
+		"message": "Game surrendered",
 		"state":   state,
 	})
 }
@@ -315,10 +841,10 @@ func (s *Server) handleGetHistory(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	sessionID := vars["id"]
 
-	// Parse query parameters
+	// Parse query parameters; without a limit the session's config picks
+	// the page size
 	opts := service.HistoryOptions{
 		Page:  1,
-		Limit: 20,
 		Order: "desc",
 	}
 
@@ -348,6 +874,205 @@ func (s *Server) handleGetHistory(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, history)
 }
 
+func (s *Server) handleGetMove(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	index, err := strconv.Atoi(vars["index"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "index must be an integer")
+		return
+	}
+
+	move, err := s.service.GetMove(r.Context(), sessionID, index)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, move)
+}
+
+func (s *Server) handleGetParks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	parks, err := s.service.GetParks(r.Context(), sessionID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, parks)
+}
+
+func (s *Server) handleGetRisk(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	risk, err := s.service.GetBatteryRisk(r.Context(), sessionID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, risk)
+}
+
+func (s *Server) handleGetEventLog(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	eventLog, err := s.service.GetEventLog(r.Context(), sessionID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, eventLog)
+}
+
+func (s *Server) handleGetSessionConfig(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	cfg, err := s.service.GetSessionConfig(r.Context(), sessionID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, cfg)
+}
+
+func (s *Server) handleSolve(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	result, err := s.service.SolveGame(r.Context(), sessionID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	s.logger.Info("solve", "session_id", sessionID, "solved", result.Solved, "moves", result.MoveCount,
+		"reason", result.ReasonCode, "elapsed_ms", result.ElapsedMs)
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// planResponse is the body returned by GET /api/sessions/{id}/plan
+type planResponse struct {
+	Steps     []service.PlanStep `json:"steps"` // Empty when the game is already won
+	MoveCount int                `json:"move_count"`
+}
+
+func (s *Server) handleSolvePlan(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["id"]
+
+	steps, err := s.service.SolvePlan(r.Context(), sessionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, engine.ErrGameOver):
+			respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, strategy.ErrNoSolution), errors.Is(err, strategy.ErrUnsolvable):
+			respondError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			respondError(w, http.StatusNotFound, err.Error())
+		}
+		return
+	}
+
+	s.logger.Info("plan", "session_id", sessionID, "moves", len(steps))
+
+	respondJSON(w, http.StatusOK, planResponse{Steps: steps, MoveCount: len(steps)})
+}
+
+// handleVerifySession replays the session's current game and reports whether
+// it reproduces the live state; a mismatch is a 200 with verified false
+func (s *Server) handleVerifySession(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["id"]
+
+	result, err := s.service.VerifySession(r.Context(), sessionID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if !result.Verified {
+		s.logger.Warn("replay mismatch", "session_id", sessionID, "field", result.Mismatch.Field)
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleCompareSessions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	sessionA, sessionB := query.Get("a"), query.Get("b")
+	if sessionA == "" || sessionB == "" {
+		respondError(w, http.StatusBadRequest, "Both 'a' and 'b' session IDs are required")
+		return
+	}
+
+	comparison, err := s.service.CompareSessions(r.Context(), sessionA, sessionB)
+	if err != nil {
+		if errors.Is(err, service.ErrConfigMismatch) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, comparison)
+}
+
+func (s *Server) handleGetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := service.LeaderboardOptions{
+		ConfigName: query.Get("config"),
+		SortBy:     service.LeaderboardSort(query.Get("sort")),
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			opts.Limit = l
+		}
+	}
+
+	board, err := s.service.GetLeaderboard(r.Context(), opts)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLeaderboardSort) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, board)
+}
+
+func (s *Server) handleGetGhost(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+	from := r.URL.Query().Get("from")
+	if from == "" {
+		respondError(w, http.StatusBadRequest, "'from' session ID is required")
+		return
+	}
+
+	ghost, err := s.service.GetGhost(r.Context(), sessionID, from)
+	if err != nil {
+		if errors.Is(err, service.ErrConfigMismatch) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, ghost)
+}
+
 // Configuration Handlers
 
 func (s *Server) handleListConfigs(w http.ResponseWriter, r *http.Request) {
@@ -376,12 +1101,80 @@ func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, config)
 }
 
+func (s *Server) handleAnalyzeConfig(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	configName := strings.TrimSuffix(vars["name"], ".json")
+
+	analysis, err := s.service.AnalyzeConfig(r.Context(), configName)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, analysis)
+}
+
+// handleConfigAnalytics reports how the sessions playing a config fared; the
+// service caches the aggregate briefly
+func (s *Server) handleConfigAnalytics(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	configName := strings.TrimSuffix(vars["name"], ".json")
+
+	analytics, err := s.service.GetConfigAnalytics(r.Context(), configName)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, analytics)
+}
+
+// handlePreviewConfig shows the board a config starts on without creating a
+// session: as text by default, or the initial game state with ?format=json
+func (s *Server) handlePreviewConfig(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	configName := strings.TrimSuffix(vars["name"], ".json")
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "text" && format != "json" {
+		respondError(w, http.StatusBadRequest, "format must be text or json")
+		return
+	}
+
+	state, err := s.service.PreviewConfig(r.Context(), configName)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	if format == "json" {
+		respondJSON(w, http.StatusOK, state)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, service.RenderGrid(state))
+}
+
+// handleValidateConfig checks a config without saving it and lists every
+// problem found rather than just the first
+func (s *Server) handleValidateConfig(w http.ResponseWriter, r *http.Request) {
+	var gameConfig engine.GameConfig
+	if err := s.decodeBody(r, &gameConfig); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+
+	problems := engine.ValidateGameConfigAll(&gameConfig)
+	respondJSON(w, http.StatusOK, configValidationResponse{Valid: len(problems) == 0, Problems: problems})
+}
+
 func (s *Server) handleCreateConfig(w http.ResponseWriter, r *http.Request) {
 	// Decode directly into engine.GameConfig which has the correct structure
 	var gameConfig engine.GameConfig
 
-	if err := json.NewDecoder(r.Body).Decode(&gameConfig); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := s.decodeBody(r, &gameConfig); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
 		return
 	}
 
@@ -479,6 +1272,7 @@ func (s *Server) handleUnifiedSessions(w http.ResponseWriter, r *http.Request) {
 			"game_state":    session.GameState,
 			"created_at":    session.CreatedAt,
 			"last_accessed": session.LastAccessedAt,
+			"label":         session.Label,
 		}
 		response["sessions"] = append(response["sessions"].([]map[string]interface{}), sessionData)
 	}
@@ -486,6 +1280,74 @@ func (s *Server) handleUnifiedSessions(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, response)
 }
 
+// Shared Session Handlers
+
+func (s *Server) handleCreateSharedSession(w http.ResponseWriter, r *http.Request) {
+	var req createSharedSessionRequest
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+
+	info, err := s.service.CreateSharedSessionWithOptions(r.Context(), req.ConfigID, req.Players,
+		service.SharedSessionOptions{HideOpponentStats: req.HideOpponentStats})
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, info)
+}
+
+func (s *Server) handleGetSharedSession(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+
+	info, err := s.service.GetSharedSession(r.Context(), sessionID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, info)
+}
+
+func (s *Server) handleSharedMove(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["id"]
+	playerID := vars["playerId"]
+
+	var req sharedMoveRequest
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+
+	result, err := s.service.MoveInShared(r.Context(), sessionID, playerID, req.Direction)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// Broadcast to WebSocket clients, tagged with the player who moved; with
+	// opponent stats hidden each client gets the view of the player it is
+	if s.hub != nil {
+		if result.HideOpponentStats {
+			s.hub.BroadcastPlayerUpdateFor(sessionID, playerID, func(viewer string) interface{} {
+				return result.ViewFor(viewer)
+			})
+		} else {
+			s.hub.BroadcastPlayerUpdate(sessionID, playerID, result)
+		}
+	}
+
+	s.logger.Info("shared move", "session_id", sessionID, "player", playerID, "dir", req.Direction,
+		"at", result.Player.Position, "battery", result.Player.Battery, "score", result.Player.Score,
+		"success", result.Success)
+
+	respondJSON(w, http.StatusOK, result.ViewFor(playerID))
+}
+
 // WebSocket Handler
 
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
@@ -495,20 +1357,115 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify session exists
-	_, err := s.service.GetSession(context.Background(), sessionID)
-	if err != nil {
-		http.Error(w, "Invalid session", http.StatusNotFound)
+	// Verify session exists, either a regular or a shared one
+	var shared *service.SharedSessionInfo
+	if _, err := s.service.GetSession(context.Background(), sessionID); err != nil {
+		if shared, err = s.service.GetSharedSession(context.Background(), sessionID); err != nil {
+			http.Error(w, "Invalid session", http.StatusNotFound)
+			return
+		}
+	}
+
+	// Payload mode: full state (default) or grid deltas, which ?delta=true
+	// also selects
+	mode := r.URL.Query().Get("mode")
+	if v := r.URL.Query().Get("delta"); v != "" {
+		delta, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "delta must be true or false", http.StatusBadRequest)
+			return
+		}
+		deltaMode := websocket.ModeFull
+		if delta {
+			deltaMode = websocket.ModeDelta
+		}
+		if mode != "" && mode != deltaMode {
+			http.Error(w, "delta conflicts with mode", http.StatusBadRequest)
+			return
+		}
+		mode = deltaMode
+	}
+	if mode == "" {
+		mode = websocket.ModeFull
+	}
+	if mode != websocket.ModeFull && mode != websocket.ModeDelta {
+		http.Error(w, "mode must be 'full' or 'delta'", http.StatusBadRequest)
+		return
+	}
+
+	// Frame encoding: JSON text (default) or gzipped binary
+	encoding := r.URL.Query().Get("encoding")
+	if encoding == "" {
+		encoding = websocket.EncodingJSON
+	}
+	if encoding != websocket.EncodingJSON && encoding != websocket.EncodingGzip {
+		http.Error(w, "encoding must be 'json' or 'gzip'", http.StatusBadRequest)
+		return
+	}
+
+	// Shared sessions only send player updates, which have no grid deltas or
+	// catch-up; ?player= names the player the client plays as
+	if shared != nil {
+		if mode != websocket.ModeFull {
+			http.Error(w, "shared sessions only support mode 'full'", http.StatusBadRequest)
+			return
+		}
+		playerID := r.URL.Query().Get("player")
+		if playerID != "" && !slices.ContainsFunc(shared.State.Players, func(p *engine.SharedPlayer) bool { return p.ID == playerID }) {
+			http.Error(w, "Unknown player", http.StatusBadRequest)
+			return
+		}
+		s.hub.ServeWSWithOptions(w, r, sessionID, websocket.ClientOptions{Mode: mode, Encoding: encoding, PlayerID: playerID})
 		return
 	}
 
+	// On reconnect, resend the current state if the client missed any moves
+	var initial *websocket.Message
+	if lastMoveStr := r.URL.Query().Get("lastMove"); lastMoveStr != "" {
+		lastMove, err := strconv.Atoi(lastMoveStr)
+		if err != nil || lastMove < 0 {
+			http.Error(w, "lastMove must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		state, err := s.service.GetGameState(r.Context(), sessionID)
+		if err == nil && state.TotalMoves > lastMove {
+			initial = &websocket.Message{
+				SessionID: sessionID,
+				GameState: state,
+				Event:     "state_update",
+				Catchup:   true,
+			}
+		}
+	}
+
+	// Delta clients need the current state to start from
+	if mode == websocket.ModeDelta && initial == nil {
+		if state, err := s.service.GetGameState(r.Context(), sessionID); err == nil {
+			initial = &websocket.Message{SessionID: sessionID, GameState: state}
+		}
+	}
+
 	// Upgrade to WebSocket
-	s.hub.ServeWS(w, r, sessionID)
+	s.hub.ServeWSWithOptions(w, r, sessionID, websocket.ClientOptions{Mode: mode, Encoding: encoding, Initial: initial})
 }
 
-// Health check
+// Health check: the process is up and serving requests
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{
 		"status": "healthy",
 	})
 }
+
+// Readiness check: configs and session storage are available
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if err := s.service.Ready(r.Context()); err != nil {
+		respondJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"status": "not ready",
+			"reason": err.Error(),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{
+		"status": "ready",
+	})
+}
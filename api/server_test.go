@@ -4,38 +4,78 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gorilla/mux"
+	gorillaws "github.com/gorilla/websocket"
 	"github.com/wricardo/tesla-road-trip-game/game/engine"
 	"github.com/wricardo/tesla-road-trip-game/game/service"
+	"github.com/wricardo/tesla-road-trip-game/game/session"
+	"github.com/wricardo/tesla-road-trip-game/game/strategy"
 	"github.com/wricardo/tesla-road-trip-game/transport/websocket"
 )
 
 // MockGameService implements service.GameService for testing
 type MockGameService struct {
 	// Session Management
-	CreateSessionFunc func(ctx context.Context, configName string) (*service.SessionInfo, error)
-	GetSessionFunc    func(ctx context.Context, sessionID string) (*service.SessionInfo, error)
-	ListSessionsFunc  func(ctx context.Context) ([]*service.SessionInfo, error)
-	DeleteSessionFunc func(ctx context.Context, sessionID string) error
+	CreateSessionFunc            func(ctx context.Context, configName string) (*service.SessionInfo, error)
+	CreateSessionWithOptionsFunc func(ctx context.Context, configName string, opts service.CreateSessionOptions) (*service.SessionInfo, error)
+	GetSessionFunc               func(ctx context.Context, sessionID string) (*service.SessionInfo, error)
+	ListSessionsFunc             func(ctx context.Context) ([]*service.SessionInfo, error)
+	DeleteSessionFunc            func(ctx context.Context, sessionID string) error
+	CloneSessionFunc             func(ctx context.Context, sessionID string, copyHistory bool) (*service.SessionInfo, error)
+	DeleteSessionsFunc           func(ctx context.Context, filter service.SessionFilter) ([]string, error)
+	SetSessionLabelFunc          func(ctx context.Context, sessionID, label string) (*service.SessionInfo, error)
 
 	// Game Operations
-	MoveFunc     func(ctx context.Context, sessionID, direction string, reset bool) (*service.MoveResult, error)
-	BulkMoveFunc func(ctx context.Context, sessionID string, moves []string, reset bool) (*service.BulkMoveResult, error)
-	ResetFunc    func(ctx context.Context, sessionID string) (*engine.GameState, error)
+	MoveFunc                func(ctx context.Context, sessionID, direction string, reset bool) (*service.MoveResult, error)
+	MoveWithOptionsFunc     func(ctx context.Context, sessionID, direction string, opts service.MoveOptions) (*service.MoveResult, error)
+	BulkMoveFunc            func(ctx context.Context, sessionID string, moves []string, reset bool) (*service.BulkMoveResult, error)
+	BulkMoveWithOptionsFunc func(ctx context.Context, sessionID string, moves []string, opts service.BulkMoveOptions) (*service.BulkMoveResult, error)
+	BulkMoveMultiFunc       func(ctx context.Context, sessionIDs []string, moves []string, opts service.BulkMoveOptions) (*service.MultiBulkMoveResult, error)
+	ResetFunc               func(ctx context.Context, sessionID string) (*engine.GameState, error)
+	ResetAndReplayFunc      func(ctx context.Context, sessionID string, keepMoves int) (*engine.GameState, error)
+	SurrenderFunc           func(ctx context.Context, sessionID string) (*engine.GameState, error)
+	TeleportFunc            func(ctx context.Context, sessionID string, x, y int) (*service.MoveResult, error)
+	MoveTowardFunc          func(ctx context.Context, sessionID string, x, y int) (*service.MoveTowardResult, error)
 
 	// Game State
-	GetGameStateFunc   func(ctx context.Context, sessionID string) (*engine.GameState, error)
-	GetMoveHistoryFunc func(ctx context.Context, sessionID string, opts service.HistoryOptions) (*service.HistoryResponse, error)
+	GetGameStateFunc     func(ctx context.Context, sessionID string) (*engine.GameState, error)
+	GetMoveHistoryFunc   func(ctx context.Context, sessionID string, opts service.HistoryOptions) (*service.HistoryResponse, error)
+	CompareSessionsFunc  func(ctx context.Context, sessionA, sessionB string) (*service.SessionComparison, error)
+	GetLeaderboardFunc   func(ctx context.Context, opts service.LeaderboardOptions) (*service.Leaderboard, error)
+	SolveGameFunc        func(ctx context.Context, sessionID string) (*service.SolveResult, error)
+	SolvePlanFunc        func(ctx context.Context, sessionID string) ([]service.PlanStep, error)
+	VerifySessionFunc    func(ctx context.Context, sessionID string) (*service.VerifyResult, error)
+	GetMoveFunc          func(ctx context.Context, sessionID string, index int) (*service.MoveDetail, error)
+	GetParksFunc         func(ctx context.Context, sessionID string) (*service.ParksResponse, error)
+	GetBatteryRiskFunc   func(ctx context.Context, sessionID string) (*engine.RiskAssessment, error)
+	GetEventLogFunc      func(ctx context.Context, sessionID string) (*service.EventLogResponse, error)
+	GetSessionConfigFunc func(ctx context.Context, sessionID string) (*service.SessionConfig, error)
+	GetGhostFunc         func(ctx context.Context, sessionID, fromSessionID string) (*service.GhostPath, error)
 
 	// Configuration
-	ListConfigsFunc func(ctx context.Context) ([]*service.ConfigInfo, error)
-	LoadConfigFunc  func(ctx context.Context, configName string) (*engine.GameConfig, error)
+	ListConfigsFunc        func(ctx context.Context) ([]*service.ConfigInfo, error)
+	LoadConfigFunc         func(ctx context.Context, configName string) (*engine.GameConfig, error)
+	SaveConfigFunc         func(ctx context.Context, configName string, config *engine.GameConfig) error
+	AnalyzeConfigFunc      func(ctx context.Context, configName string) (*engine.ConfigAnalysis, error)
+	GetConfigAnalyticsFunc func(ctx context.Context, configName string) (*service.ConfigAnalytics, error)
+	PreviewConfigFunc      func(ctx context.Context, configName string) (*engine.GameState, error)
+
+	// Shared Sessions
+	CreateSharedSessionFunc            func(ctx context.Context, configName string, playerIDs []string) (*service.SharedSessionInfo, error)
+	CreateSharedSessionWithOptionsFunc func(ctx context.Context, configName string, playerIDs []string, opts service.SharedSessionOptions) (*service.SharedSessionInfo, error)
+	GetSharedSessionFunc               func(ctx context.Context, sessionID string) (*service.SharedSessionInfo, error)
+	MoveInSharedFunc                   func(ctx context.Context, sessionID, playerID, direction string) (*service.SharedMoveResult, error)
+
+	ReadyFunc func(ctx context.Context) error
 }
 
 // Session Management
@@ -50,6 +90,13 @@ func (m *MockGameService) CreateSession(ctx context.Context, configName string)
 	}, nil
 }
 
+func (m *MockGameService) CreateSessionWithOptions(ctx context.Context, configName string, opts service.CreateSessionOptions) (*service.SessionInfo, error) {
+	if m.CreateSessionWithOptionsFunc != nil {
+		return m.CreateSessionWithOptionsFunc(ctx, configName, opts)
+	}
+	return m.CreateSession(ctx, configName)
+}
+
 func (m *MockGameService) GetSession(ctx context.Context, sessionID string) (*service.SessionInfo, error) {
 	if m.GetSessionFunc != nil {
 		return m.GetSessionFunc(ctx, sessionID)
@@ -75,6 +122,27 @@ func (m *MockGameService) DeleteSession(ctx context.Context, sessionID string) e
 	return nil
 }
 
+func (m *MockGameService) DeleteSessions(ctx context.Context, filter service.SessionFilter) ([]string, error) {
+	if m.DeleteSessionsFunc != nil {
+		return m.DeleteSessionsFunc(ctx, filter)
+	}
+	return []string{}, nil
+}
+
+func (m *MockGameService) SetSessionLabel(ctx context.Context, sessionID, label string) (*service.SessionInfo, error) {
+	if m.SetSessionLabelFunc != nil {
+		return m.SetSessionLabelFunc(ctx, sessionID, label)
+	}
+	return &service.SessionInfo{ID: sessionID, ConfigName: "default", Label: label, GameState: &engine.GameState{}}, nil
+}
+
+func (m *MockGameService) CloneSession(ctx context.Context, sessionID string, copyHistory bool) (*service.SessionInfo, error) {
+	if m.CloneSessionFunc != nil {
+		return m.CloneSessionFunc(ctx, sessionID, copyHistory)
+	}
+	return &service.SessionInfo{ID: "clone", ConfigName: "default", GameState: &engine.GameState{}}, nil
+}
+
 // Game Operations
 func (m *MockGameService) Move(ctx context.Context, sessionID, direction string, reset bool) (*service.MoveResult, error) {
 	if m.MoveFunc != nil {
@@ -86,6 +154,13 @@ func (m *MockGameService) Move(ctx context.Context, sessionID, direction string,
 	}, nil
 }
 
+func (m *MockGameService) MoveWithOptions(ctx context.Context, sessionID, direction string, opts service.MoveOptions) (*service.MoveResult, error) {
+	if m.MoveWithOptionsFunc != nil {
+		return m.MoveWithOptionsFunc(ctx, sessionID, direction, opts)
+	}
+	return m.Move(ctx, sessionID, direction, opts.Reset)
+}
+
 func (m *MockGameService) BulkMove(ctx context.Context, sessionID string, moves []string, reset bool) (*service.BulkMoveResult, error) {
 	if m.BulkMoveFunc != nil {
 		return m.BulkMoveFunc(ctx, sessionID, moves, reset)
@@ -96,6 +171,20 @@ func (m *MockGameService) BulkMove(ctx context.Context, sessionID string, moves
 	}, nil
 }
 
+func (m *MockGameService) BulkMoveWithOptions(ctx context.Context, sessionID string, moves []string, opts service.BulkMoveOptions) (*service.BulkMoveResult, error) {
+	if m.BulkMoveWithOptionsFunc != nil {
+		return m.BulkMoveWithOptionsFunc(ctx, sessionID, moves, opts)
+	}
+	return m.BulkMove(ctx, sessionID, moves, opts.Reset)
+}
+
+func (m *MockGameService) BulkMoveMulti(ctx context.Context, sessionIDs []string, moves []string, opts service.BulkMoveOptions) (*service.MultiBulkMoveResult, error) {
+	if m.BulkMoveMultiFunc != nil {
+		return m.BulkMoveMultiFunc(ctx, sessionIDs, moves, opts)
+	}
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (m *MockGameService) Reset(ctx context.Context, sessionID string) (*engine.GameState, error) {
 	if m.ResetFunc != nil {
 		return m.ResetFunc(ctx, sessionID)
@@ -103,6 +192,38 @@ func (m *MockGameService) Reset(ctx context.Context, sessionID string) (*engine.
 	return &engine.GameState{}, nil
 }
 
+func (m *MockGameService) ResetAndReplay(ctx context.Context, sessionID string, keepMoves int) (*engine.GameState, error) {
+	if m.ResetAndReplayFunc != nil {
+		return m.ResetAndReplayFunc(ctx, sessionID, keepMoves)
+	}
+	return &engine.GameState{}, nil
+}
+
+func (m *MockGameService) Surrender(ctx context.Context, sessionID string) (*engine.GameState, error) {
+	if m.SurrenderFunc != nil {
+		return m.SurrenderFunc(ctx, sessionID)
+	}
+	return &engine.GameState{GameOver: true, GameOverReason: engine.GameOverSurrendered}, nil
+}
+
+func (m *MockGameService) Teleport(ctx context.Context, sessionID string, x, y int) (*service.MoveResult, error) {
+	if m.TeleportFunc != nil {
+		return m.TeleportFunc(ctx, sessionID, x, y)
+	}
+	return &service.MoveResult{Success: true, GameState: &engine.GameState{PlayerPos: engine.Position{X: x, Y: y}}}, nil
+}
+
+func (m *MockGameService) MoveToward(ctx context.Context, sessionID string, x, y int) (*service.MoveTowardResult, error) {
+	if m.MoveTowardFunc != nil {
+		return m.MoveTowardFunc(ctx, sessionID, x, y)
+	}
+	return &service.MoveTowardResult{
+		MoveResult: &service.MoveResult{Success: true, GameState: &engine.GameState{}},
+		Target:     engine.Position{X: x, Y: y},
+		Direction:  "up",
+	}, nil
+}
+
 // Game State
 func (m *MockGameService) GetGameState(ctx context.Context, sessionID string) (*engine.GameState, error) {
 	if m.GetGameStateFunc != nil {
@@ -124,6 +245,86 @@ func (m *MockGameService) GetMoveHistory(ctx context.Context, sessionID string,
 	}, nil
 }
 
+func (m *MockGameService) GetLeaderboard(ctx context.Context, opts service.LeaderboardOptions) (*service.Leaderboard, error) {
+	if m.GetLeaderboardFunc != nil {
+		return m.GetLeaderboardFunc(ctx, opts)
+	}
+	return &service.Leaderboard{ConfigName: opts.ConfigName, SortBy: service.LeaderboardByScore, Entries: []service.LeaderboardEntry{}}, nil
+}
+
+func (m *MockGameService) CompareSessions(ctx context.Context, sessionA, sessionB string) (*service.SessionComparison, error) {
+	if m.CompareSessionsFunc != nil {
+		return m.CompareSessionsFunc(ctx, sessionA, sessionB)
+	}
+	return &service.SessionComparison{
+		A: service.SessionTrace{SessionID: sessionA},
+		B: service.SessionTrace{SessionID: sessionB},
+	}, nil
+}
+
+func (m *MockGameService) SolveGame(ctx context.Context, sessionID string) (*service.SolveResult, error) {
+	if m.SolveGameFunc != nil {
+		return m.SolveGameFunc(ctx, sessionID)
+	}
+	return &service.SolveResult{Solved: true, Moves: []string{}}, nil
+}
+
+func (m *MockGameService) SolvePlan(ctx context.Context, sessionID string) ([]service.PlanStep, error) {
+	if m.SolvePlanFunc != nil {
+		return m.SolvePlanFunc(ctx, sessionID)
+	}
+	return []service.PlanStep{}, nil
+}
+
+func (m *MockGameService) VerifySession(ctx context.Context, sessionID string) (*service.VerifyResult, error) {
+	if m.VerifySessionFunc != nil {
+		return m.VerifySessionFunc(ctx, sessionID)
+	}
+	return &service.VerifyResult{SessionID: sessionID, Verified: true}, nil
+}
+
+func (m *MockGameService) GetMove(ctx context.Context, sessionID string, index int) (*service.MoveDetail, error) {
+	if m.GetMoveFunc != nil {
+		return m.GetMoveFunc(ctx, sessionID, index)
+	}
+	return nil, service.ErrMoveNotFound
+}
+
+func (m *MockGameService) GetParks(ctx context.Context, sessionID string) (*service.ParksResponse, error) {
+	if m.GetParksFunc != nil {
+		return m.GetParksFunc(ctx, sessionID)
+	}
+	return &service.ParksResponse{Parks: []service.ParkInfo{}}, nil
+}
+
+func (m *MockGameService) GetBatteryRisk(ctx context.Context, sessionID string) (*engine.RiskAssessment, error) {
+	if m.GetBatteryRiskFunc != nil {
+		return m.GetBatteryRiskFunc(ctx, sessionID)
+	}
+	return &engine.RiskAssessment{Level: engine.RiskSafe, ChargerDistance: -1}, nil
+}
+
+func (m *MockGameService) GetEventLog(ctx context.Context, sessionID string) (*service.EventLogResponse, error) {
+	if m.GetEventLogFunc != nil {
+		return m.GetEventLogFunc(ctx, sessionID)
+	}
+	return &service.EventLogResponse{SessionID: sessionID, Events: []service.GameEvent{}}, nil
+}
+
+func (m *MockGameService) GetSessionConfig(ctx context.Context, sessionID string) (*service.SessionConfig, error) {
+	if m.GetSessionConfigFunc != nil {
+		return m.GetSessionConfigFunc(ctx, sessionID)
+	}
+	return &service.SessionConfig{ConfigID: "classic"}, nil
+}
+
+func (m *MockGameService) GetGhost(ctx context.Context, sessionID, fromSessionID string) (*service.GhostPath, error) {
+	if m.GetGhostFunc != nil {
+		return m.GetGhostFunc(ctx, sessionID, fromSessionID)
+	}
+	return &service.GhostPath{SessionID: sessionID, FromSessionID: fromSessionID, Steps: []service.GhostStep{}}, nil
+}
+
 // Configuration
 func (m *MockGameService) ListConfigs(ctx context.Context) ([]*service.ConfigInfo, error) {
 	if m.ListConfigsFunc != nil {
@@ -142,6 +343,75 @@ func (m *MockGameService) LoadConfig(ctx context.Context, configName string) (*e
 	}, nil
 }
 
+func (m *MockGameService) SaveConfig(ctx context.Context, configName string, config *engine.GameConfig) error {
+	if m.SaveConfigFunc != nil {
+		return m.SaveConfigFunc(ctx, configName, config)
+	}
+	return nil
+}
+
+func (m *MockGameService) AnalyzeConfig(ctx context.Context, configName string) (*engine.ConfigAnalysis, error) {
+	if m.AnalyzeConfigFunc != nil {
+		return m.AnalyzeConfigFunc(ctx, configName)
+	}
+	return &engine.ConfigAnalysis{Name: configName}, nil
+}
+
+func (m *MockGameService) GetConfigAnalytics(ctx context.Context, configName string) (*service.ConfigAnalytics, error) {
+	if m.GetConfigAnalyticsFunc != nil {
+		return m.GetConfigAnalyticsFunc(ctx, configName)
+	}
+	return &service.ConfigAnalytics{ConfigName: configName}, nil
+}
+
+func (m *MockGameService) PreviewConfig(ctx context.Context, configName string) (*engine.GameState, error) {
+	if m.PreviewConfigFunc != nil {
+		return m.PreviewConfigFunc(ctx, configName)
+	}
+	return nil, fmt.Errorf("config '%s' not found", configName)
+}
+
+// Shared Sessions
+func (m *MockGameService) CreateSharedSession(ctx context.Context, configName string, playerIDs []string) (*service.SharedSessionInfo, error) {
+	if m.CreateSharedSessionFunc != nil {
+		return m.CreateSharedSessionFunc(ctx, configName, playerIDs)
+	}
+	return &service.SharedSessionInfo{ID: "m-test", ConfigName: configName, CreatedAt: time.Now()}, nil
+}
+
+func (m *MockGameService) CreateSharedSessionWithOptions(ctx context.Context, configName string, playerIDs []string, opts service.SharedSessionOptions) (*service.SharedSessionInfo, error) {
+	if m.CreateSharedSessionWithOptionsFunc != nil {
+		return m.CreateSharedSessionWithOptionsFunc(ctx, configName, playerIDs, opts)
+	}
+	return m.CreateSharedSession(ctx, configName, playerIDs)
+}
+
+func (m *MockGameService) GetSharedSession(ctx context.Context, sessionID string) (*service.SharedSessionInfo, error) {
+	if m.GetSharedSessionFunc != nil {
+		return m.GetSharedSessionFunc(ctx, sessionID)
+	}
+	return nil, fmt.Errorf("shared session not found: %s", sessionID)
+}
+
+func (m *MockGameService) MoveInShared(ctx context.Context, sessionID, playerID, direction string) (*service.SharedMoveResult, error) {
+	if m.MoveInSharedFunc != nil {
+		return m.MoveInSharedFunc(ctx, sessionID, playerID, direction)
+	}
+	return &service.SharedMoveResult{
+		Success:  true,
+		PlayerID: playerID,
+		Player:   &engine.SharedPlayer{ID: playerID},
+		State:    &engine.SharedGameState{},
+	}, nil
+}
+
+func (m *MockGameService) Ready(ctx context.Context) error {
+	if m.ReadyFunc != nil {
+		return m.ReadyFunc(ctx)
+	}
+	return nil
+}
+
 // Test helpers
 func setupTestServer(mockService *MockGameService) *Server {
 	hub := websocket.NewHub()
@@ -264,6 +534,41 @@ func TestCreateSession(t *testing.T) {
 	}
 }
 
+func TestCreateSession_TTL(t *testing.T) {
+	t.Run("passes ttl_seconds to the service", func(t *testing.T) {
+		mockService := &MockGameService{
+			CreateSessionWithOptionsFunc: func(ctx context.Context, configName string, opts service.CreateSessionOptions) (*service.SessionInfo, error) {
+				if opts.TTLSeconds != 300 {
+					t.Errorf("Expected TTLSeconds 300, got %d", opts.TTLSeconds)
+				}
+				return &service.SessionInfo{ID: "sess-ttl", ConfigName: configName, TTLSeconds: opts.TTLSeconds}, nil
+			},
+		}
+		server := setupTestServer(mockService)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("POST", "/api/sessions", map[string]any{"config_id": "easy", "ttl_seconds": 300}))
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+		var resp service.SessionInfo
+		parseResponse(t, w, &resp)
+		if resp.TTLSeconds != 300 {
+			t.Errorf("Expected ttl_seconds 300 in response, got %d", resp.TTLSeconds)
+		}
+	})
+
+	t.Run("rejects negative ttl_seconds", func(t *testing.T) {
+		server := setupTestServer(&MockGameService{})
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("POST", "/api/sessions", map[string]any{"ttl_seconds": -1}))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
 func TestListSessions(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -502,6 +807,247 @@ func TestDeleteSession(t *testing.T) {
 
 // Game Operations Tests
 
+func TestDeleteSessions(t *testing.T) {
+	yes, no := true, false
+	var got service.SessionFilter
+	server := setupTestServer(&MockGameService{
+		DeleteSessionsFunc: func(ctx context.Context, filter service.SessionFilter) ([]string, error) {
+			got = filter
+			return []string{"a1", "b2"}, nil
+		},
+	})
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		wantConfig     string
+		wantGameOver   *bool
+	}{
+		{"by config", "?configName=easy", http.StatusOK, "easy", nil},
+		{"by game over", "?gameOver=true", http.StatusOK, "", &yes},
+		{"by both", "?configName=easy&gameOver=false", http.StatusOK, "easy", &no},
+		{"no filter", "", http.StatusBadRequest, "", nil},
+		{"unknown filter only", "?tag=exp1", http.StatusBadRequest, "", nil},
+		{"unknown filter with a known one", "?tag=exp1&gameOver=true", http.StatusBadRequest, "", nil},
+		{"misspelled filter", "?configname=easy&gameOver=true", http.StatusBadRequest, "", nil},
+		{"invalid game over", "?gameOver=maybe", http.StatusBadRequest, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = service.SessionFilter{}
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, makeRequest("DELETE", "/api/sessions"+tt.query, nil))
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				if !got.Empty() {
+					t.Errorf("Service should not be called, got filter %+v", got)
+				}
+				return
+			}
+			if got.ConfigName != tt.wantConfig {
+				t.Errorf("Expected config filter %q, got %q", tt.wantConfig, got.ConfigName)
+			}
+			if (got.GameOver == nil) != (tt.wantGameOver == nil) ||
+				(got.GameOver != nil && *got.GameOver != *tt.wantGameOver) {
+				t.Errorf("Expected game over filter %v, got %v", tt.wantGameOver, got.GameOver)
+			}
+			var resp struct {
+				Deleted    int      `json:"deleted"`
+				SessionIDs []string `json:"session_ids"`
+			}
+			parseResponse(t, w, &resp)
+			if resp.Deleted != 2 || len(resp.SessionIDs) != 2 {
+				t.Errorf("Expected 2 deleted sessions, got %+v", resp)
+			}
+		})
+	}
+}
+
+func TestCloneSession(t *testing.T) {
+	var gotHistory bool
+	server := setupTestServer(&MockGameService{
+		CloneSessionFunc: func(ctx context.Context, sessionID string, copyHistory bool) (*service.SessionInfo, error) {
+			if sessionID != "sess-123" {
+				return nil, fmt.Errorf("session not found")
+			}
+			gotHistory = copyHistory
+			return &service.SessionInfo{ID: "sess-456", ConfigName: "default", GameState: &engine.GameState{}}, nil
+		},
+	})
+
+	tests := []struct {
+		name           string
+		sessionID      string
+		body           interface{}
+		expectedStatus int
+		wantHistory    bool
+	}{
+		{"no body", "sess-123", nil, http.StatusCreated, false},
+		{"copy history", "sess-123", map[string]bool{"copy_history": true}, http.StatusCreated, true},
+		{"invalid body", "sess-123", "not an object", http.StatusBadRequest, false},
+		{"missing session", "nonexistent", nil, http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHistory = false
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, makeRequest("POST", "/api/sessions/"+tt.sessionID+"/clone", tt.body))
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if gotHistory != tt.wantHistory {
+				t.Errorf("Expected copy_history %v, got %v", tt.wantHistory, gotHistory)
+			}
+			if w.Code == http.StatusCreated {
+				var resp service.SessionInfo
+				parseResponse(t, w, &resp)
+				if resp.ID != "sess-456" {
+					t.Errorf("Expected clone ID sess-456, got %s", resp.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifySession(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		VerifySessionFunc: func(ctx context.Context, sessionID string) (*service.VerifyResult, error) {
+			switch sessionID {
+			case "good":
+				return &service.VerifyResult{SessionID: sessionID, Verified: true, MovesReplayed: 3}, nil
+			case "bad":
+				return &service.VerifyResult{SessionID: sessionID, MovesReplayed: 3,
+					Mismatch: &service.ReplayMismatch{Field: "current_moves[1].to_position.y", Live: 1, Replayed: 3}}, nil
+			}
+			return nil, fmt.Errorf("session not found")
+		},
+	})
+
+	tests := []struct {
+		sessionID      string
+		expectedStatus int
+		wantVerified   bool
+		wantField      string
+	}{
+		{"good", http.StatusOK, true, ""},
+		{"bad", http.StatusOK, false, "current_moves[1].to_position.y"},
+		{"nonexistent", http.StatusNotFound, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sessionID, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, makeRequest("GET", "/api/sessions/"+tt.sessionID+"/verify", nil))
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var resp service.VerifyResult
+			parseResponse(t, w, &resp)
+			if resp.Verified != tt.wantVerified {
+				t.Errorf("Expected verified %v, got %v", tt.wantVerified, resp.Verified)
+			}
+			field := ""
+			if resp.Mismatch != nil {
+				field = resp.Mismatch.Field
+			}
+			if field != tt.wantField {
+				t.Errorf("Expected mismatch on %q, got %q", tt.wantField, field)
+			}
+		})
+	}
+}
+
+func TestSessionLabels(t *testing.T) {
+	sessions := []*service.SessionInfo{
+		{ID: "sess-1", ConfigName: "easy", GameState: &engine.GameState{}},
+		{ID: "sess-2", ConfigName: "easy", Label: "Evening commute", GameState: &engine.GameState{}},
+	}
+	server := setupTestServer(&MockGameService{
+		SetSessionLabelFunc: func(ctx context.Context, sessionID, label string) (*service.SessionInfo, error) {
+			if len(label) > service.MaxSessionLabelLength {
+				return nil, service.ErrLabelTooLong
+			}
+			for _, session := range sessions {
+				if session.ID == sessionID {
+					session.Label = label
+					return session, nil
+				}
+			}
+			return nil, fmt.Errorf("session not found")
+		},
+		ListSessionsFunc: func(ctx context.Context) ([]*service.SessionInfo, error) {
+			return slices.Clone(sessions), nil
+		},
+	})
+
+	patches := []struct {
+		name           string
+		sessionID      string
+		body           interface{}
+		expectedStatus int
+	}{
+		{"set label", "sess-1", map[string]string{"label": "Morning Commute"}, http.StatusOK},
+		{"missing label", "sess-1", map[string]string{}, http.StatusBadRequest},
+		{"label too long", "sess-1", map[string]string{"label": strings.Repeat("x", service.MaxSessionLabelLength+1)}, http.StatusBadRequest},
+		{"missing session", "nonexistent", map[string]string{"label": "x"}, http.StatusNotFound},
+	}
+	for _, tt := range patches {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, makeRequest("PATCH", "/api/sessions/"+tt.sessionID, tt.body))
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusOK {
+				var resp service.SessionInfo
+				parseResponse(t, w, &resp)
+				if resp.ID != "sess-1" || resp.Label != "Morning Commute" {
+					t.Errorf("Expected sess-1 labelled Morning Commute, got %+v", resp)
+				}
+			}
+		})
+	}
+
+	filters := map[string][]string{
+		"":        {"sess-1", "sess-2"},
+		"commute": {"sess-1", "sess-2"},
+		"MORNING": {"sess-1"},
+		"weekend": {},
+	}
+	for label, want := range filters {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("GET", "/api/sessions?sort=created&order=asc&label="+label, nil))
+		var resp struct {
+			Sessions []service.SessionInfo `json:"sessions"`
+		}
+		parseResponse(t, w, &resp)
+		got := []string{}
+		for _, session := range resp.Sessions {
+			got = append(got, session.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("label=%q: expected sessions %v, got %v", label, want, got)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/unified", nil))
+	var unified struct {
+		Sessions []struct {
+			SessionID string `json:"session_id"`
+			Label     string `json:"label"`
+		} `json:"sessions"`
+	}
+	parseResponse(t, w, &unified)
+	if len(unified.Sessions) != 2 || unified.Sessions[0].Label != "Morning Commute" || unified.Sessions[1].Label != "Evening commute" {
+		t.Errorf("Expected the labels in the unified sessions, got %+v", unified.Sessions)
+	}
+}
 func TestMove(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -568,6 +1114,23 @@ func TestMove(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:        "Move with intent",
+			sessionID:   "sess-123",
+			requestBody: map[string]interface{}{"direction": "up", "intent": "closest park is north"},
+			setupMock: func(m *MockGameService) {
+				m.MoveWithOptionsFunc = func(ctx context.Context, sessionID, direction string, opts service.MoveOptions) (*service.MoveResult, error) {
+					if opts.Intent != "closest park is north" {
+						t.Errorf("Expected intent to be passed through, got %q", opts.Intent)
+					}
+					return &service.MoveResult{
+						Success:   true,
+						GameState: &engine.GameState{Battery: 99},
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name:        "Invalid request body",
 			sessionID:   "sess-123",
@@ -635,6 +1198,50 @@ func TestMove(t *testing.T) {
 	}
 }
 
+func TestMove_IdempotencyKey(t *testing.T) {
+	mockService := &MockGameService{
+		MoveWithOptionsFunc: func(ctx context.Context, sessionID, direction string, opts service.MoveOptions) (*service.MoveResult, error) {
+			if opts.IdempotencyKey != "retry-1" {
+				t.Errorf("Expected the Idempotency-Key header to be passed through, got %q", opts.IdempotencyKey)
+			}
+			if direction != "up" {
+				return nil, fmt.Errorf("%w: %q", service.ErrIdempotencyKeyReused, opts.IdempotencyKey)
+			}
+			return &service.MoveResult{Success: true, GameState: &engine.GameState{Battery: 99}}, nil
+		},
+		BulkMoveWithOptionsFunc: func(ctx context.Context, sessionID string, moves []string, opts service.BulkMoveOptions) (*service.BulkMoveResult, error) {
+			if opts.IdempotencyKey != "retry-1" {
+				t.Errorf("Expected the Idempotency-Key header to be passed through, got %q", opts.IdempotencyKey)
+			}
+			return &service.BulkMoveResult{Success: true, GameState: &engine.GameState{Battery: 98}}, nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	for _, tc := range []struct {
+		direction string
+		want      int
+	}{{"up", http.StatusOK}, {"down", http.StatusUnprocessableEntity}} {
+		w := httptest.NewRecorder()
+		req := makeRequest("POST", "/api/sessions/sess-123/move", map[string]interface{}{"direction": tc.direction})
+		req.Header.Set("Idempotency-Key", "retry-1")
+		req = mux.SetURLVars(req, map[string]string{"id": "sess-123"})
+		server.handleMove(w, req)
+		if w.Code != tc.want {
+			t.Errorf("Move %s: expected status %d, got %d: %s", tc.direction, tc.want, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req := makeRequest("POST", "/api/sessions/sess-123/bulk-move", map[string]interface{}{"moves": []string{"up"}})
+	req.Header.Set("Idempotency-Key", "retry-1")
+	req = mux.SetURLVars(req, map[string]string{"id": "sess-123"})
+	server.handleBulkMove(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
 func TestBulkMove(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -671,17 +1278,18 @@ func TestBulkMove(t *testing.T) {
 			},
 		},
 		{
-			name:        "Bulk move with reset",
+			name:        "Bulk move with continue_on_block",
 			sessionID:   "sess-123",
-			requestBody: map[string]interface{}{"moves": []string{"up", "up"}, "reset": true},
+			requestBody: map[string]interface{}{"moves": []string{"up", "left"}, "continue_on_block": true},
 			setupMock: func(m *MockGameService) {
-				m.BulkMoveFunc = func(ctx context.Context, sessionID string, moves []string, reset bool) (*service.BulkMoveResult, error) {
-					if !reset {
-						t.Error("Expected reset to be true")
+				m.BulkMoveWithOptionsFunc = func(ctx context.Context, sessionID string, moves []string, opts service.BulkMoveOptions) (*service.BulkMoveResult, error) {
+					if !opts.ContinueOnBlock {
+						t.Error("Expected continue_on_block to be true")
 					}
 					return &service.BulkMoveResult{
-						Success:   true,
-						GameState: &engine.GameState{Battery: 98},
+						GameState:     &engine.GameState{Battery: 9},
+						MovesExecuted: 1,
+						BlockedCount:  1,
 					}, nil
 				}
 			},
@@ -689,29 +1297,94 @@ func TestBulkMove(t *testing.T) {
 			validateResp: func(t *testing.T, w *httptest.ResponseRecorder) {
 				var resp service.BulkMoveResult
 				parseResponse(t, w, &resp)
-				if resp.GameState.Battery != 98 {
-					t.Errorf("Expected battery 98, got %d", resp.GameState.Battery)
+				if resp.BlockedCount != 1 {
+					t.Errorf("Expected blocked_count 1, got %d", resp.BlockedCount)
 				}
 			},
 		},
 		{
-			name:           "Empty moves array",
-			sessionID:      "sess-123",
-			requestBody:    map[string]interface{}{"moves": []string{}},
-			setupMock:      nil,
+			name:        "Bulk move with intent",
+			sessionID:   "sess-123",
+			requestBody: map[string]interface{}{"moves": []string{"up"}, "intent": "scout the corridor"},
+			setupMock: func(m *MockGameService) {
+				m.BulkMoveWithOptionsFunc = func(ctx context.Context, sessionID string, moves []string, opts service.BulkMoveOptions) (*service.BulkMoveResult, error) {
+					if opts.Intent != "scout the corridor" {
+						t.Errorf("Expected intent to be passed through, got %q", opts.Intent)
+					}
+					return &service.BulkMoveResult{
+						GameState:     &engine.GameState{Battery: 9},
+						MovesExecuted: 1,
+					}, nil
+				}
+			},
 			expectedStatus: http.StatusOK,
-			validateResp: func(t *testing.T, w *httptest.ResponseRecorder) {
-				var resp service.BulkMoveResult
-				parseResponse(t, w, &resp)
-				if resp.MovesExecuted != 0 {
-					t.Errorf("Expected 0 moves executed for empty array, got %d", resp.MovesExecuted)
+		},
+		{
+			name:        "Bulk move with per-move intents",
+			sessionID:   "sess-123",
+			requestBody: map[string]interface{}{"moves": []string{"up", "left"}, "intents": []string{"", "toward the park"}},
+			setupMock: func(m *MockGameService) {
+				m.BulkMoveWithOptionsFunc = func(ctx context.Context, sessionID string, moves []string, opts service.BulkMoveOptions) (*service.BulkMoveResult, error) {
+					if len(opts.Intents) != 2 || opts.Intents[1] != "toward the park" {
+						t.Errorf("Expected intents to be passed through, got %q", opts.Intents)
+					}
+					return &service.BulkMoveResult{
+						GameState:     &engine.GameState{Battery: 8},
+						MovesExecuted: 2,
+					}, nil
 				}
 			},
+			expectedStatus: http.StatusOK,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
+		{
+			name:           "More intents than moves",
+			sessionID:      "sess-123",
+			requestBody:    map[string]interface{}{"moves": []string{"up"}, "intents": []string{"a", "b"}},
+			setupMock:      nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Bulk move with reset",
+			sessionID:   "sess-123",
+			requestBody: map[string]interface{}{"moves": []string{"up", "up"}, "reset": true},
+			setupMock: func(m *MockGameService) {
+				m.BulkMoveFunc = func(ctx context.Context, sessionID string, moves []string, reset bool) (*service.BulkMoveResult, error) {
+					if !reset {
+						t.Error("Expected reset to be true")
+					}
+					return &service.BulkMoveResult{
+						Success:   true,
+						GameState: &engine.GameState{Battery: 98},
+					}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp service.BulkMoveResult
+				parseResponse(t, w, &resp)
+				if resp.GameState.Battery != 98 {
+					t.Errorf("Expected battery 98, got %d", resp.GameState.Battery)
+				}
+			},
+		},
+		{
+			name:           "Empty moves array",
+			sessionID:      "sess-123",
+			requestBody:    map[string]interface{}{"moves": []string{}},
+			setupMock:      nil,
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp service.BulkMoveResult
+				parseResponse(t, w, &resp)
+				if resp.MovesExecuted != 0 {
+					t.Errorf("Expected 0 moves executed for empty array, got %d", resp.MovesExecuted)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
 			mockService := &MockGameService{}
 			if tt.setupMock != nil {
 				tt.setupMock(mockService)
@@ -735,10 +1408,50 @@ func TestBulkMove(t *testing.T) {
 	}
 }
 
+func TestBulkMoveMulti(t *testing.T) {
+	mockService := &MockGameService{
+		BulkMoveMultiFunc: func(ctx context.Context, sessionIDs []string, moves []string, opts service.BulkMoveOptions) (*service.MultiBulkMoveResult, error) {
+			if len(sessionIDs) == 0 {
+				return nil, service.ErrInvalidSessionList
+			}
+			if !opts.ContinueOnBlock || len(moves) != 2 {
+				t.Errorf("Expected the moves and options to be passed on, got %v and %+v", moves, opts)
+			}
+			return &service.MultiBulkMoveResult{
+				Results:   map[string]*service.BulkMoveResult{"a": {MovesExecuted: 2, GameState: &engine.GameState{}}},
+				Errors:    map[string]string{"b": "session b not found"},
+				Succeeded: 1,
+				Failed:    1,
+			}, nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/bulk-move-multi", map[string]interface{}{
+		"session_ids": []string{"a", "b"}, "moves": []string{"up", "left"}, "continue_on_block": true,
+	}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp service.MultiBulkMoveResult
+	parseResponse(t, w, &resp)
+	if resp.Results["a"] == nil || resp.Results["a"].MovesExecuted != 2 || resp.Errors["b"] == "" {
+		t.Errorf("Expected a result for a and an error for b, got %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/bulk-move-multi", map[string]interface{}{"moves": []string{"up"}}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d without sessions, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
 func TestReset(t *testing.T) {
 	tests := []struct {
 		name           string
 		sessionID      string
+		query          string
 		setupMock      func(*MockGameService)
 		expectedStatus int
 		validateResp   func(*testing.T, *httptest.ResponseRecorder)
@@ -769,6 +1482,33 @@ func TestReset(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "Reset keeping moves",
+			sessionID: "sess-123",
+			query:     "?keep=2",
+			setupMock: func(m *MockGameService) {
+				m.ResetAndReplayFunc = func(ctx context.Context, sessionID string, keepMoves int) (*engine.GameState, error) {
+					if keepMoves != 2 {
+						t.Errorf("Expected keep 2, got %d", keepMoves)
+					}
+					return &engine.GameState{CurrentMoves: make([]engine.MoveHistoryEntry, 2)}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+			validateResp: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var resp map[string]interface{}
+				parseResponse(t, w, &resp)
+				if resp["message"] != "Game reset and 2 moves replayed" {
+					t.Errorf("Expected replay message, got %s", resp["message"])
+				}
+			},
+		},
+		{
+			name:           "Invalid keep",
+			sessionID:      "sess-123",
+			query:          "?keep=two",
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:      "Reset non-existent session",
 			sessionID: "nonexistent",
@@ -797,7 +1537,7 @@ func TestReset(t *testing.T) {
 
 			server := setupTestServer(mockService)
 			w := httptest.NewRecorder()
-			req := makeRequest("POST", "/api/sessions/"+tt.sessionID+"/reset", nil)
+			req := makeRequest("POST", "/api/sessions/"+tt.sessionID+"/reset"+tt.query, nil)
 			req = mux.SetURLVars(req, map[string]string{"id": tt.sessionID})
 
 			server.handleReset(w, req)
@@ -828,8 +1568,9 @@ func TestGetHistory(t *testing.T) {
 			queryParams: "",
 			setupMock: func(m *MockGameService) {
 				m.GetMoveHistoryFunc = func(ctx context.Context, sessionID string, opts service.HistoryOptions) (*service.HistoryResponse, error) {
-					if opts.Page != 1 || opts.Limit != 20 {
-						t.Errorf("Expected default page=1, limit=20, got page=%d, limit=%d", opts.Page, opts.Limit)
+					// The limit is left to the session's config
+					if opts.Page != 1 || opts.Limit != 0 {
+						t.Errorf("Expected default page=1, no limit, got page=%d, limit=%d", opts.Page, opts.Limit)
 					}
 					return &service.HistoryResponse{
 						Moves: []engine.MoveHistoryEntry{
@@ -905,6 +1646,769 @@ func TestGetHistory(t *testing.T) {
 	}
 }
 
+func TestSolvePlan(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		SolvePlanFunc: func(ctx context.Context, sessionID string) ([]service.PlanStep, error) {
+			switch sessionID {
+			case "test-session":
+				return []service.PlanStep{
+					{Direction: "left", Purpose: strategy.PurposePark, BatteryAfter: 9, Target: engine.Position{X: 2, Y: 0}},
+					{Direction: "up", Purpose: strategy.PurposePark, BatteryAfter: 8, Target: engine.Position{X: 2, Y: 0}},
+				}, nil
+			case "lost":
+				return nil, engine.ErrGameOver
+			case "stuck":
+				return nil, strategy.ErrUnsolvable
+			}
+			return nil, fmt.Errorf("session not found: %s", sessionID)
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/test-session/plan", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var plan planResponse
+	parseResponse(t, w, &plan)
+	if plan.MoveCount != 2 || len(plan.Steps) != 2 || plan.Steps[1].Target != (engine.Position{X: 2, Y: 0}) || plan.Steps[1].BatteryAfter != 8 {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+
+	for id, want := range map[string]int{"lost": http.StatusConflict, "stuck": http.StatusUnprocessableEntity, "missing": http.StatusNotFound} {
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("GET", "/api/sessions/"+id+"/plan", nil))
+		if w.Code != want {
+			t.Errorf("%s: expected %d, got %d", id, want, w.Code)
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	t.Run("Returns plan", func(t *testing.T) {
+		server := setupTestServer(&MockGameService{
+			SolveGameFunc: func(ctx context.Context, sessionID string) (*service.SolveResult, error) {
+				return &service.SolveResult{Solved: true, Moves: []string{"up", "left"}, MoveCount: 2}, nil
+			},
+		})
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/solve", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+		var result service.SolveResult
+		parseResponse(t, w, &result)
+		if !result.Solved || len(result.Moves) != 2 {
+			t.Errorf("Unexpected solve result: %+v", result)
+		}
+	})
+
+	t.Run("Session not found", func(t *testing.T) {
+		server := setupTestServer(&MockGameService{
+			SolveGameFunc: func(ctx context.Context, sessionID string) (*service.SolveResult, error) {
+				return nil, fmt.Errorf("session not found")
+			},
+		})
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("POST", "/api/sessions/missing/solve", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestPark(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		MoveFunc: func(ctx context.Context, sessionID, direction string, reset bool) (*service.MoveResult, error) {
+			if direction != engine.ActionPark {
+				t.Errorf("Expected park action, got %s", direction)
+			}
+			return &service.MoveResult{
+				Success:   true,
+				GameState: &engine.GameState{PlayerPos: engine.Position{X: 3, Y: 1}, Score: 1},
+				Step:      &service.StepInfo{Idx: 1, Dir: direction, Park: true},
+			}, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/park", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var result service.MoveResult
+	parseResponse(t, w, &result)
+	if !result.Success || result.Step == nil || !result.Step.Park {
+		t.Errorf("Expected park step in result, got %+v", result)
+	}
+}
+
+func TestWait(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		MoveFunc: func(ctx context.Context, sessionID, direction string, reset bool) (*service.MoveResult, error) {
+			if direction != engine.ActionWait {
+				t.Errorf("Expected wait action, got %s", direction)
+			}
+			return &service.MoveResult{
+				Success:   true,
+				GameState: &engine.GameState{PlayerPos: engine.Position{X: 3, Y: 1}, Battery: 7},
+			}, nil
+		},
+	})
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/wait", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var result service.MoveResult
+	parseResponse(t, w, &result)
+	if !result.Success || result.GameState.Battery != 7 {
+		t.Errorf("Expected the wait result, got %+v", result)
+	}
+}
+
+func TestSharedSession(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		CreateSharedSessionFunc: func(ctx context.Context, configName string, playerIDs []string) (*service.SharedSessionInfo, error) {
+			if len(playerIDs) < 2 {
+				return nil, errors.New("shared game needs 2 to 8 players")
+			}
+			return &service.SharedSessionInfo{ID: "m1a2b", ConfigName: configName}, nil
+		},
+		MoveInSharedFunc: func(ctx context.Context, sessionID, playerID, direction string) (*service.SharedMoveResult, error) {
+			if playerID != "alice" {
+				return nil, fmt.Errorf("%w '%s'", engine.ErrUnknownPlayer, playerID)
+			}
+			return &service.SharedMoveResult{
+				Success:   true,
+				PlayerID:  playerID,
+				Player:    &engine.SharedPlayer{ID: playerID, Position: engine.Position{X: 3, Y: 1}, Score: 1},
+				Collected: "park_0",
+				State:     &engine.SharedGameState{},
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/shared-sessions", map[string]interface{}{"players": []string{"alice"}}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a single player, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/shared-sessions", map[string]interface{}{
+		"config_id": "classic", "players": []string{"alice", "bob"},
+	}))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/shared-sessions/m1a2b/players/alice/move", map[string]string{"direction": "right"}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var result service.SharedMoveResult
+	parseResponse(t, w, &result)
+	if result.PlayerID != "alice" || result.Collected != "park_0" {
+		t.Errorf("Unexpected move result %+v", result)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/shared-sessions/m1a2b/players/carol/move", map[string]string{"direction": "right"}))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown player, got %d", w.Code)
+	}
+}
+
+func TestSharedSession_HideOpponentStats(t *testing.T) {
+	var gotOpts service.SharedSessionOptions
+	server := setupTestServer(&MockGameService{
+		CreateSharedSessionWithOptionsFunc: func(ctx context.Context, configName string, playerIDs []string, opts service.SharedSessionOptions) (*service.SharedSessionInfo, error) {
+			gotOpts = opts
+			return &service.SharedSessionInfo{ID: "m1a2b", ConfigName: configName, HideOpponentStats: opts.HideOpponentStats}, nil
+		},
+		MoveInSharedFunc: func(ctx context.Context, sessionID, playerID, direction string) (*service.SharedMoveResult, error) {
+			alice := &engine.SharedPlayer{ID: "alice", Battery: 7, Score: 1}
+			return &service.SharedMoveResult{
+				Success:           true,
+				PlayerID:          playerID,
+				Player:            alice,
+				State:             &engine.SharedGameState{Players: []*engine.SharedPlayer{alice, {ID: "bob", Battery: 4, Score: 2}}},
+				HideOpponentStats: true,
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/shared-sessions", map[string]interface{}{
+		"players": []string{"alice", "bob"}, "hide_opponent_stats": true,
+	}))
+	if w.Code != http.StatusCreated || !gotOpts.HideOpponentStats {
+		t.Fatalf("Expected 201 with hidden opponent stats, got %d and %+v", w.Code, gotOpts)
+	}
+
+	// The mover's own response hides the opponent but not the mover
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/shared-sessions/m1a2b/players/alice/move", map[string]string{"direction": "right"}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var result service.SharedMoveResult
+	parseResponse(t, w, &result)
+	alice, bob := result.State.Players[0], result.State.Players[1]
+	if alice.Battery != 7 || alice.Score != 1 || alice.StatsHidden {
+		t.Errorf("Expected alice's own stats in full, got %+v", alice)
+	}
+	if bob.Battery != 0 || bob.Score != 0 || !bob.StatsHidden {
+		t.Errorf("Expected bob's stats masked, got %+v", bob)
+	}
+}
+
+// failingConfigManager is a config manager whose config directory is unreadable
+type failingConfigManager struct{}
+
+func (failingConfigManager) LoadConfig(name string) (*engine.GameConfig, error) {
+	return nil, errors.New("config directory unreadable")
+}
+
+func (failingConfigManager) ListConfigs() ([]*service.ConfigInfo, error) {
+	return nil, errors.New("config directory unreadable")
+}
+
+func (failingConfigManager) GetDefault() *engine.GameConfig { return nil }
+
+func (failingConfigManager) SaveConfig(name string, config *engine.GameConfig) error {
+	return errors.New("config directory unreadable")
+}
+
+func TestHealthAndReadiness(t *testing.T) {
+	healthy := setupTestServer(&MockGameService{})
+	for _, path := range []string{"/healthz", "/readyz"} {
+		w := httptest.NewRecorder()
+		healthy.ServeHTTP(w, makeRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s: expected 200, got %d", path, w.Code)
+		}
+	}
+
+	// Configs that can't be listed keep the server live but not ready
+	notReady := NewServer(service.NewGameService(session.NewManager(), failingConfigManager{}), nil)
+
+	w := httptest.NewRecorder()
+	notReady.ServeHTTP(w, makeRequest("GET", "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected liveness 200 while not ready, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	notReady.ServeHTTP(w, makeRequest("GET", "/readyz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503, got %d", w.Code)
+	}
+	var body map[string]string
+	parseResponse(t, w, &body)
+	if body["status"] != "not ready" || !strings.Contains(body["reason"], "config directory unreadable") {
+		t.Errorf("Expected a not-ready reason, got %v", body)
+	}
+}
+
+func TestGetParks(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		GetParksFunc: func(ctx context.Context, sessionID string) (*service.ParksResponse, error) {
+			if sessionID != "test-session" {
+				return nil, fmt.Errorf("session not found: %s", sessionID)
+			}
+			return &service.ParksResponse{
+				Parks: []service.ParkInfo{
+					{ID: "park_0", Position: engine.Position{X: 2, Y: 0}, Collected: true},
+					{ID: "park_1", Position: engine.Position{X: 2, Y: 4}},
+				},
+				Collected: 1,
+				Total:     2,
+				Required:  2,
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/test-session/parks", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var parks service.ParksResponse
+	parseResponse(t, w, &parks)
+	if len(parks.Parks) != 2 || parks.Collected != 1 || !parks.Parks[0].Collected {
+		t.Errorf("Unexpected parks response %+v", parks)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/missing/parks", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing session, got %d", w.Code)
+	}
+}
+
+func TestMoveToward(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		MoveTowardFunc: func(ctx context.Context, sessionID string, x, y int) (*service.MoveTowardResult, error) {
+			if sessionID != "test-session" {
+				return nil, fmt.Errorf("session not found: %s", sessionID)
+			}
+			if x != 2 || y != 0 {
+				return nil, fmt.Errorf("%w: no road leads from (3,2) to (%d,%d)", service.ErrTargetUnreachable, x, y)
+			}
+			return &service.MoveTowardResult{
+				MoveResult:    &service.MoveResult{Success: true, GameState: &engine.GameState{PlayerPos: engine.Position{X: 2, Y: 2}}},
+				Target:        engine.Position{X: 2, Y: 0},
+				Direction:     "left",
+				RemainingPath: 2,
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/move-toward", map[string]int{"x": 2, "y": 0}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var result service.MoveTowardResult
+	parseResponse(t, w, &result)
+	if !result.Success || result.Direction != "left" || result.RemainingPath != 2 || result.GameState.PlayerPos != (engine.Position{X: 2, Y: 2}) {
+		t.Errorf("Unexpected move toward response %+v", result)
+	}
+
+	for _, tc := range []struct {
+		path string
+		body interface{}
+		want int
+	}{
+		{"/api/sessions/test-session/move-toward", map[string]int{"x": 1, "y": 1}, http.StatusBadRequest},
+		{"/api/sessions/test-session/move-toward", "not an object", http.StatusBadRequest},
+		{"/api/sessions/missing/move-toward", map[string]int{"x": 2, "y": 0}, http.StatusNotFound},
+	} {
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("POST", tc.path, tc.body))
+		if w.Code != tc.want {
+			t.Errorf("%s %v: expected %d, got %d", tc.path, tc.body, tc.want, w.Code)
+		}
+	}
+}
+
+func TestGetMove(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		GetMoveFunc: func(ctx context.Context, sessionID string, index int) (*service.MoveDetail, error) {
+			if sessionID != "test-session" {
+				return nil, fmt.Errorf("session not found: %s", sessionID)
+			}
+			if index != 2 {
+				return nil, fmt.Errorf("%w: index %d of 2", service.ErrMoveNotFound, index)
+			}
+			return &service.MoveDetail{
+				MoveHistoryEntry: engine.MoveHistoryEntry{Action: "right", BatteryDelta: -1, Success: true, MoveNumber: 2},
+				Index:            2, TotalMoves: 2, TileEntered: engine.Park, Event: service.MoveEventPark,
+				CurrentSegment: true,
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/test-session/history/2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var move service.MoveDetail
+	parseResponse(t, w, &move)
+	if move.Index != 2 || move.Action != "right" || move.TileEntered != engine.Park || move.Event != service.MoveEventPark || !move.CurrentSegment {
+		t.Errorf("Unexpected move response %+v", move)
+	}
+
+	for path, want := range map[string]int{
+		"/api/sessions/test-session/history/3": http.StatusNotFound,
+		"/api/sessions/test-session/history/x": http.StatusBadRequest,
+		"/api/sessions/missing/history/2":      http.StatusNotFound,
+	} {
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("GET", path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func TestGetRisk(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		GetBatteryRiskFunc: func(ctx context.Context, sessionID string) (*engine.RiskAssessment, error) {
+			if sessionID != "test-session" {
+				return nil, fmt.Errorf("session not found: %s", sessionID)
+			}
+			return &engine.RiskAssessment{
+				Level: engine.RiskCaution, Battery: 4, MaxBattery: 10,
+				ChargerDistance: 2, DangerAt: 2, CautionAt: 4, LowAt: 3,
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/test-session/risk", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var risk engine.RiskAssessment
+	parseResponse(t, w, &risk)
+	if risk.Level != engine.RiskCaution || risk.Battery != 4 || risk.CautionAt != 4 || risk.LowAt != 3 {
+		t.Errorf("Unexpected risk response %+v", risk)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/missing/risk", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing session, got %d", w.Code)
+	}
+}
+
+func TestGetEventLog(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		GetEventLogFunc: func(ctx context.Context, sessionID string) (*service.EventLogResponse, error) {
+			if sessionID != "test-session" {
+				return nil, fmt.Errorf("session not found: %s", sessionID)
+			}
+			return &service.EventLogResponse{
+				SessionID: sessionID,
+				Count:     2,
+				Events: []service.GameEvent{
+					{Type: "session_created", Message: "Session created with config test", Timestamp: time.Unix(100, 0)},
+					{Type: "move", Message: "Moved left to (1,2)", Timestamp: time.Unix(101, 0)},
+				},
+			}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/test-session/eventlog", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var eventLog service.EventLogResponse
+	parseResponse(t, w, &eventLog)
+	if eventLog.Count != 2 || eventLog.Events[1].Type != "move" || !eventLog.Events[1].Timestamp.Equal(time.Unix(101, 0)) {
+		t.Errorf("Unexpected event log response %+v", eventLog)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/missing/eventlog", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing session, got %d", w.Code)
+	}
+}
+
+func TestSurrender(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		SurrenderFunc: func(ctx context.Context, sessionID string) (*engine.GameState, error) {
+			switch sessionID {
+			case "test-session":
+				return &engine.GameState{GameOver: true, GameOverReason: engine.GameOverSurrendered}, nil
+			case "finished":
+				return nil, engine.ErrGameOver
+			}
+			return nil, fmt.Errorf("session not found: %s", sessionID)
+		},
+		MoveWithOptionsFunc: func(ctx context.Context, sessionID, direction string, opts service.MoveOptions) (*service.MoveResult, error) {
+			return nil, engine.ErrGameOver
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/surrender", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var resp struct {
+		Message string            `json:"message"`
+		State   *engine.GameState `json:"state"`
+	}
+	parseResponse(t, w, &resp)
+	if resp.State == nil || resp.State.GameOverReason != engine.GameOverSurrendered {
+		t.Errorf("Expected the surrendered state, got %+v", resp)
+	}
+
+	for path, want := range map[string]int{
+		"/api/sessions/finished/surrender": http.StatusConflict,
+		"/api/sessions/missing/surrender":  http.StatusNotFound,
+	} {
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("POST", path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+
+	// Moves on a surrendered game conflict rather than fail
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/sessions/test-session/move", map[string]string{"direction": "up"}))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 moving after surrender, got %d", w.Code)
+	}
+}
+
+func TestGetSessionConfig(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		GetSessionConfigFunc: func(ctx context.Context, sessionID string) (*service.SessionConfig, error) {
+			if sessionID != "test-session" {
+				return nil, fmt.Errorf("session not found: %s", sessionID)
+			}
+			cfg := service.SessionConfig{ConfigID: "easy", Checksum: "abc123"}
+			cfg.Name = "Easy"
+			cfg.MaxBattery = 15
+			cfg.Layout = []string{"RHP"}
+			return &cfg, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/test-session/config", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	var resp service.SessionConfig
+	parseResponse(t, w, &resp)
+	if resp.ConfigID != "easy" || resp.Checksum != "abc123" {
+		t.Errorf("Unexpected session config response %+v", resp)
+	}
+	// The config fields are inlined, so the body is a usable GameConfig
+	var gameConfig engine.GameConfig
+	parseResponse(t, w, &gameConfig)
+	if gameConfig.Name != "Easy" || gameConfig.MaxBattery != 15 || len(gameConfig.Layout) != 1 {
+		t.Errorf("Expected the body to decode as a GameConfig, got %+v", gameConfig)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/sessions/missing/config", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for missing session, got %d", w.Code)
+	}
+}
+
+func TestGetGhost(t *testing.T) {
+	server := setupTestServer(&MockGameService{
+		GetGhostFunc: func(ctx context.Context, sessionID, fromSessionID string) (*service.GhostPath, error) {
+			if fromSessionID == "other-config" {
+				return nil, fmt.Errorf("%w: mismatch", service.ErrConfigMismatch)
+			}
+			return &service.GhostPath{
+				SessionID:     sessionID,
+				FromSessionID: fromSessionID,
+				Steps:         []service.GhostStep{{Step: 1, Action: "left", Position: engine.Position{X: 1, Y: 2}}},
+			}, nil
+		},
+	})
+
+	tests := []struct {
+		query          string
+		expectedStatus int
+	}{
+		{"?from=saved", http.StatusOK},
+		{"", http.StatusBadRequest},
+		{"?from=other-config", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("GET", "/api/sessions/live/ghost"+tt.query, nil))
+		if w.Code != tt.expectedStatus {
+			t.Errorf("ghost%s: expected %d, got %d", tt.query, tt.expectedStatus, w.Code)
+		}
+	}
+}
+
+func TestCompareSessions(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockGameService)
+		expectedStatus int
+	}{
+		{
+			name:  "Compare sessions on same config",
+			query: "?a=sess-a&b=sess-b",
+			setupMock: func(m *MockGameService) {
+				m.CompareSessionsFunc = func(ctx context.Context, sessionA, sessionB string) (*service.SessionComparison, error) {
+					if sessionA != "sess-a" || sessionB != "sess-b" {
+						t.Errorf("Unexpected session IDs %s, %s", sessionA, sessionB)
+					}
+					return &service.SessionComparison{Diverged: true, DivergedAtMove: 3}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing session parameter",
+			query:          "?a=sess-a",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Config mismatch",
+			query: "?a=sess-a&b=sess-b",
+			setupMock: func(m *MockGameService) {
+				m.CompareSessionsFunc = func(ctx context.Context, sessionA, sessionB string) (*service.SessionComparison, error) {
+					return nil, fmt.Errorf("%w: different", service.ErrConfigMismatch)
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "Session not found",
+			query: "?a=sess-a&b=missing",
+			setupMock: func(m *MockGameService) {
+				m.CompareSessionsFunc = func(ctx context.Context, sessionA, sessionB string) (*service.SessionComparison, error) {
+					return nil, fmt.Errorf("session not found")
+				}
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockGameService{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+
+			server := setupTestServer(mockService)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, makeRequest("GET", "/api/sessions/compare"+tt.query, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*MockGameService)
+		expectedStatus int
+	}{
+		{
+			name:  "Passes config, sort and limit",
+			query: "?config=easy&sort=moves&limit=10",
+			setupMock: func(m *MockGameService) {
+				m.GetLeaderboardFunc = func(ctx context.Context, opts service.LeaderboardOptions) (*service.Leaderboard, error) {
+					if opts.ConfigName != "easy" || opts.SortBy != service.LeaderboardByMoves || opts.Limit != 10 {
+						t.Errorf("Unexpected options %+v", opts)
+					}
+					return &service.Leaderboard{ConfigName: opts.ConfigName, SortBy: opts.SortBy}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Ignores an invalid limit",
+			query: "?limit=abc",
+			setupMock: func(m *MockGameService) {
+				m.GetLeaderboardFunc = func(ctx context.Context, opts service.LeaderboardOptions) (*service.Leaderboard, error) {
+					if opts.Limit != 0 {
+						t.Errorf("Expected no limit, got %d", opts.Limit)
+					}
+					return &service.Leaderboard{}, nil
+				}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "Unknown sort",
+			query: "?sort=battery",
+			setupMock: func(m *MockGameService) {
+				m.GetLeaderboardFunc = func(ctx context.Context, opts service.LeaderboardOptions) (*service.Leaderboard, error) {
+					return nil, fmt.Errorf("%w, got 'battery'", service.ErrInvalidLeaderboardSort)
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockGameService{}
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+
+			server := setupTestServer(mockService)
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, makeRequest("GET", "/api/leaderboard"+tt.query, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestViewRadius(t *testing.T) {
+	// A 5x5 grid with the player in the bottom-right corner
+	newState := func() *engine.GameState {
+		grid := make([][]engine.Cell, 5)
+		for y := range grid {
+			grid[y] = make([]engine.Cell, 5)
+			for x := range grid[y] {
+				grid[y][x] = engine.Cell{Type: engine.Road}
+			}
+		}
+		grid[3][4] = engine.Cell{Type: engine.Park, ID: "park_4_3"}
+		return &engine.GameState{Grid: grid, PlayerPos: engine.Position{X: 4, Y: 4}}
+	}
+	live := newState()
+	mockService := &MockGameService{
+		GetGameStateFunc: func(ctx context.Context, sessionID string) (*engine.GameState, error) {
+			return live, nil
+		},
+		MoveWithOptionsFunc: func(ctx context.Context, sessionID, direction string, opts service.MoveOptions) (*service.MoveResult, error) {
+			return &service.MoveResult{Success: true, GameState: live}, nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	w := httptest.NewRecorder()
+	req := mux.SetURLVars(makeRequest("GET", "/api/sessions/sess-123/state?viewRadius=1", nil), map[string]string{"id": "sess-123"})
+	server.handleGetGameState(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var state engine.GameState
+	parseResponse(t, w, &state)
+	if state.View == nil || state.View.Origin != (engine.Position{X: 3, Y: 3}) || state.View.Width != 5 {
+		t.Fatalf("Expected a view from (3,3) of the 5x5 grid, got %+v", state.View)
+	}
+	if len(state.Grid) != 2 || len(state.Grid[0]) != 2 || state.Grid[0][1].ID != "park_4_3" {
+		t.Errorf("Expected the 2x2 corner with the park at its top right, got %+v", state.Grid)
+	}
+	if len(live.Grid) != 5 || live.View != nil {
+		t.Errorf("Expected the service's state to keep its full grid")
+	}
+
+	w = httptest.NewRecorder()
+	req = mux.SetURLVars(makeRequest("POST", "/api/sessions/sess-123/move?viewRadius=0", map[string]interface{}{"direction": "up"}),
+		map[string]string{"id": "sess-123"})
+	server.handleMove(w, req)
+	var result service.MoveResult
+	parseResponse(t, w, &result)
+	if result.GameState == nil || len(result.GameState.Grid) != 1 || result.GameState.View.Origin != (engine.Position{X: 4, Y: 4}) {
+		t.Errorf("Expected only the player's cell, got %+v", result.GameState)
+	}
+
+	for _, bad := range []string{"-1", "two"} {
+		w = httptest.NewRecorder()
+		req = mux.SetURLVars(makeRequest("GET", "/api/sessions/sess-123/state?viewRadius="+bad, nil), map[string]string{"id": "sess-123"})
+		server.handleGetGameState(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("viewRadius=%s: expected status 400, got %d", bad, w.Code)
+		}
+	}
+}
+
 func TestGetGameState(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1137,6 +2641,153 @@ func TestGetConfig(t *testing.T) {
 	}
 }
 
+func TestAnalyzeConfig(t *testing.T) {
+	mockService := &MockGameService{
+		AnalyzeConfigFunc: func(ctx context.Context, configName string) (*engine.ConfigAnalysis, error) {
+			if configName != "easy" {
+				return nil, fmt.Errorf("config '%s' not found", configName)
+			}
+			return &engine.ConfigAnalysis{Name: "Easy", ParkCount: 4, DifficultyScore: 35}, nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/configs/easy/analysis", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp engine.ConfigAnalysis
+	parseResponse(t, w, &resp)
+	if resp.ParkCount != 4 || resp.DifficultyScore != 35 {
+		t.Errorf("Unexpected analysis: %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/configs/missing/analysis", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestConfigAnalytics(t *testing.T) {
+	mockService := &MockGameService{
+		GetConfigAnalyticsFunc: func(ctx context.Context, configName string) (*service.ConfigAnalytics, error) {
+			if configName != "easy" {
+				return nil, fmt.Errorf("config '%s' not found", configName)
+			}
+			return &service.ConfigAnalytics{ConfigName: "easy", Sessions: 3, FinishedGames: 2, Wins: 1, WinRate: 0.5}, nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/configs/easy.json/analytics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp service.ConfigAnalytics
+	parseResponse(t, w, &resp)
+	if resp.Sessions != 3 || resp.WinRate != 0.5 {
+		t.Errorf("Unexpected analytics: %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/configs/missing/analytics", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestPreviewConfig(t *testing.T) {
+	classic, err := engine.LoadGameConfig("../configs/classic.json")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+	mockService := &MockGameService{
+		PreviewConfigFunc: func(ctx context.Context, configName string) (*engine.GameState, error) {
+			if configName != "classic" {
+				return nil, fmt.Errorf("config '%s' not found", configName)
+			}
+			return engine.InitGameStateFromConfig(classic), nil
+		},
+	}
+	server := setupTestServer(mockService)
+
+	// The text preview marks the player on the home tile
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/configs/classic/preview", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("Expected a text preview, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	home := engine.InitGameStateFromConfig(classic).PrimaryHome
+	rows := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(rows) != len(classic.Layout) {
+		t.Fatalf("Expected %d rows, got %d:\n%s", len(classic.Layout), len(rows), w.Body.String())
+	}
+	if rows[home.Y][home.X] != 'T' || classic.Layout[home.Y][home.X] != 'H' {
+		t.Errorf("Expected T on the home tile (%d,%d), got row %q", home.X, home.Y, rows[home.Y])
+	}
+	if strings.Count(w.Body.String(), "T") != 1 || !strings.Contains(w.Body.String(), "P") || !strings.Contains(w.Body.String(), "S") {
+		t.Errorf("Expected one player with parks and chargers shown, got:\n%s", w.Body.String())
+	}
+
+	// JSON returns the raw initial state
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/configs/classic/preview?format=json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var state engine.GameState
+	parseResponse(t, w, &state)
+	if state.PlayerPos != home || state.Grid[home.Y][home.X].Type != engine.Home || state.TotalMoves != 0 {
+		t.Errorf("Expected a fresh state at home %v, got %v", home, state.PlayerPos)
+	}
+
+	for target, want := range map[string]int{
+		"/api/configs/missing/preview":          http.StatusNotFound,
+		"/api/configs/classic/preview?format=x": http.StatusBadRequest,
+	} {
+		w = httptest.NewRecorder()
+		server.ServeHTTP(w, makeRequest("GET", target, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", target, want, w.Code)
+		}
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+
+	config, err := engine.LoadGameConfig("../configs/classic.json")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/configs/validate", config))
+	var resp configValidationResponse
+	parseResponse(t, w, &resp)
+	if w.Code != http.StatusOK || !resp.Valid || len(resp.Problems) != 0 {
+		t.Errorf("Expected classic to be valid, got %d %+v", w.Code, resp)
+	}
+
+	config.Description = ""
+	config.StartingBattery = 0
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/configs/validate", config))
+	resp = configValidationResponse{}
+	parseResponse(t, w, &resp)
+	if w.Code != http.StatusOK || resp.Valid || len(resp.Problems) != 2 {
+		t.Errorf("Expected two problems, got %d %+v", w.Code, resp)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("POST", "/api/configs/validate", strings.NewReader("{")))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a malformed body, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
 func TestUnifiedSessions(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -1304,6 +2955,46 @@ func TestWebSocket(t *testing.T) {
 			},
 			expectedStatus: http.StatusSwitchingProtocols,
 		},
+		{
+			name:        "Delta flag",
+			queryParams: "?session=sess-123&delta=true",
+			setupMock: func(m *MockGameService) {
+				m.GetSessionFunc = func(ctx context.Context, sessionID string) (*service.SessionInfo, error) {
+					return &service.SessionInfo{ID: sessionID, ConfigName: "test"}, nil
+				}
+			},
+			expectedStatus: http.StatusSwitchingProtocols,
+		},
+		{
+			name:        "Delta flag conflicting with mode",
+			queryParams: "?session=sess-123&delta=true&mode=full",
+			setupMock: func(m *MockGameService) {
+				m.GetSessionFunc = func(ctx context.Context, sessionID string) (*service.SessionInfo, error) {
+					return &service.SessionInfo{ID: sessionID, ConfigName: "test"}, nil
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Invalid delta flag",
+			queryParams: "?session=sess-123&delta=maybe",
+			setupMock: func(m *MockGameService) {
+				m.GetSessionFunc = func(ctx context.Context, sessionID string) (*service.SessionInfo, error) {
+					return &service.SessionInfo{ID: sessionID, ConfigName: "test"}, nil
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Unknown encoding",
+			queryParams: "?session=sess-123&encoding=brotli",
+			setupMock: func(m *MockGameService) {
+				m.GetSessionFunc = func(ctx context.Context, sessionID string) (*service.SessionInfo, error) {
+					return &service.SessionInfo{ID: sessionID, ConfigName: "test"}, nil
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1343,3 +3034,65 @@ func TestWebSocket(t *testing.T) {
 		})
 	}
 }
+
+func TestWebSocketCatchup(t *testing.T) {
+	mockService := &MockGameService{
+		GetGameStateFunc: func(ctx context.Context, sessionID string) (*engine.GameState, error) {
+			return &engine.GameState{TotalMoves: 5, Battery: 7}, nil
+		},
+	}
+	ts := httptest.NewServer(setupTestServer(mockService))
+	defer ts.Close()
+
+	wsBase := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws?session=sess-123"
+
+	tests := []struct {
+		name        string
+		query       string
+		wantCatchup bool
+	}{
+		{"Missed moves", "&lastMove=3", true},
+		{"Up to date", "&lastMove=5", false},
+		{"No lastMove", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, _, err := gorillaws.DefaultDialer.Dial(wsBase+tt.query, nil)
+			if err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer conn.Close()
+
+			conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			_, data, err := conn.ReadMessage()
+			if !tt.wantCatchup {
+				if err == nil {
+					t.Errorf("Expected no initial message, got %s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected catch-up message, got error: %v", err)
+			}
+
+			var msg websocket.Message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				t.Fatalf("Failed to parse message: %v", err)
+			}
+			if !msg.Catchup {
+				t.Error("Expected catchup flag to be set")
+			}
+			if msg.GameState == nil || msg.GameState.TotalMoves != 5 {
+				t.Errorf("Expected current state with total_moves=5, got %+v", msg.GameState)
+			}
+		})
+	}
+
+	// Invalid lastMove is rejected before upgrade
+	w := httptest.NewRecorder()
+	setupTestServer(mockService).ServeHTTP(w, httptest.NewRequest("GET", "/ws?session=sess-123&lastMove=abc", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for invalid lastMove, got %d", http.StatusBadRequest, w.Code)
+	}
+}
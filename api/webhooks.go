@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/wricardo/tesla-road-trip-game/transport/webhook"
+)
+
+// SetWebhooks enables the /api/webhooks endpoints backed by m
+func (s *Server) SetWebhooks(m *webhook.Manager) {
+	s.webhooks = m
+}
+
+// createWebhookRequest is the body accepted by POST /api/webhooks
+type createWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		respondError(w, http.StatusServiceUnavailable, "Webhooks are not enabled")
+		return
+	}
+
+	var req createWebhookRequest
+	if err := s.decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, bodyErrorMessage(err))
+		return
+	}
+
+	hook, err := s.webhooks.Register(req.URL, req.Events)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.logger.Info("webhook registered", "id", hook.ID, "url", hook.URL, "events", hook.Events)
+	respondJSON(w, http.StatusCreated, hook)
+}
+
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		respondError(w, http.StatusServiceUnavailable, "Webhooks are not enabled")
+		return
+	}
+
+	hooks := s.webhooks.List()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"count":          len(hooks),
+		"webhooks":       hooks,
+		"dropped_events": s.webhooks.Dropped(),
+	})
+}
+
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.webhooks == nil {
+		respondError(w, http.StatusServiceUnavailable, "Webhooks are not enabled")
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	if err := s.webhooks.Delete(id); err != nil {
+		if errors.Is(err, webhook.ErrNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": fmt.Sprintf("Webhook %s deleted", id),
+	})
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/transport/webhook"
+)
+
+func TestWebhookEndpoints(t *testing.T) {
+	server := setupTestServer(&MockGameService{})
+
+	// Disabled until a manager is attached
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/webhooks", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 without webhooks, got %d", w.Code)
+	}
+
+	manager, err := webhook.NewManager(filepath.Join(t.TempDir(), "webhooks.json"))
+	if err != nil {
+		t.Fatalf("Failed to create webhook manager: %v", err)
+	}
+	defer manager.Close()
+	server.SetWebhooks(manager)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/webhooks", map[string]interface{}{
+		"url":    "http://example.com/hook",
+		"events": []string{"victory", "moved"},
+	}))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown event, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("POST", "/api/webhooks", map[string]interface{}{
+		"url":    "http://example.com/hook",
+		"events": []string{"victory", "game_over"},
+	}))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created webhook.Webhook
+	parseResponse(t, w, &created)
+	if created.ID == "" || len(created.Events) != 2 {
+		t.Errorf("Unexpected registration: %+v", created)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("GET", "/api/webhooks", nil))
+	var list struct {
+		Count    int               `json:"count"`
+		Webhooks []webhook.Webhook `json:"webhooks"`
+	}
+	parseResponse(t, w, &list)
+	if list.Count != 1 || list.Webhooks[0].ID != created.ID {
+		t.Errorf("Expected the registered webhook in list, got %+v", list)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("DELETE", "/api/webhooks/"+created.ID, nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 on delete, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, makeRequest("DELETE", "/api/webhooks/"+created.ID, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 deleting unknown webhook, got %d", w.Code)
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/wricardo/tesla-road-trip-game/bruteforcer
 
-go 1.21
+go 1.24.4
+
+require github.com/wricardo/tesla-road-trip-game v0.0.0
+
+replace github.com/wricardo/tesla-road-trip-game => ../
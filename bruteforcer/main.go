@@ -10,36 +10,15 @@ import (
 	"net/http"
 	"os"
 	"time"
-)
-
-type Position struct {
-	X int `json:"x"`
-	Y int `json:"y"`
-}
 
-type Cell struct {
-	Type    string `json:"type"`
-	Visited bool   `json:"visited,omitempty"`
-	ID      string `json:"id,omitempty"`
-}
-
-type GameState struct {
-	Grid         [][]Cell        `json:"grid"`
-	PlayerPos    Position        `json:"player_pos"`
-	Battery      int             `json:"battery"`
-	MaxBattery   int             `json:"max_battery"`
-	Score        int             `json:"score"`
-	GameOver     bool            `json:"game_over"`
-	Victory      bool            `json:"victory"`
-	Message      string          `json:"message"`
-	VisitedParks map[string]bool `json:"visited_parks"`
-	ConfigName   string          `json:"config_name"`
-}
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/strategy"
+)
 
 type SessionResponse struct {
 	ID         string     `json:"id"`
 	ConfigName string     `json:"config_name"`
-	GameState  *GameState `json:"game_state"`
+	GameState  *engine.GameState `json:"game_state"`
 }
 
 type MoveRequest struct {
@@ -63,7 +42,7 @@ func NewClient(baseURL string) *Client {
 	}
 }
 
-func (c *Client) CreateSession(configName string) (*GameState, error) {
+func (c *Client) CreateSession(configName string) (*engine.GameState, error) {
 	var reqBody []byte
 	var err error
 
@@ -94,7 +73,7 @@ func (c *Client) CreateSession(configName string) (*GameState, error) {
 	return session.GameState, nil
 }
 
-func (c *Client) GetState() (*GameState, error) {
+func (c *Client) GetState() (*engine.GameState, error) {
 	url := fmt.Sprintf("%s/api/sessions/%s", c.baseURL, c.sessionID)
 	resp, err := c.client.Get(url)
 	if err != nil {
@@ -110,22 +89,22 @@ func (c *Client) GetState() (*GameState, error) {
 	return session.GameState, nil
 }
 
-func (c *Client) Move(direction string) (*GameState, error) {
+func (c *Client) Move(direction string) (*engine.GameState, error) {
 	req := MoveRequest{Direction: direction}
 	return c.executeMove(req)
 }
 
-func (c *Client) BulkMove(directions []string) (*GameState, error) {
+func (c *Client) BulkMove(directions []string) (*engine.GameState, error) {
 	req := MoveRequest{Directions: directions}
 	return c.executeMove(req)
 }
 
 type ResetResponse struct {
 	Message string     `json:"message"`
-	State   *GameState `json:"state"`
+	State   *engine.GameState `json:"state"`
 }
 
-func (c *Client) Reset() (*GameState, error) {
+func (c *Client) Reset() (*engine.GameState, error) {
 	url := fmt.Sprintf("%s/api/sessions/%s/reset", c.baseURL, c.sessionID)
 	resp, err := c.client.Post(url, "application/json", nil)
 	if err != nil {
@@ -143,11 +122,11 @@ func (c *Client) Reset() (*GameState, error) {
 
 type MoveResponse struct {
 	Success   bool       `json:"success"`
-	GameState *GameState `json:"game_state"`
+	GameState *engine.GameState `json:"game_state"`
 	Message   string     `json:"message"`
 }
 
-func (c *Client) executeMove(req MoveRequest) (*GameState, error) {
+func (c *Client) executeMove(req MoveRequest) (*engine.GameState, error) {
 	body, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("marshal move: %w", err)
@@ -192,7 +171,7 @@ func main() {
 	log.Printf("Connecting to game server at %s", *serverURL)
 	client := NewClient(*serverURL)
 
-	var state *GameState
+	var state *engine.GameState
 	var err error
 	var totalParks int
 
@@ -253,7 +232,7 @@ func main() {
 		state.PlayerPos.X, state.PlayerPos.Y, state.Battery, state.MaxBattery)
 
 	// Initialize systematic strategy
-	systematicStrategy := NewSystematicStrategy(state)
+	systematicStrategy := strategy.NewSystematicStrategy(state)
 
 	// Keep trying until victory or max attempts
 	attemptNum := 0
@@ -343,11 +322,11 @@ func main() {
 	os.Exit(1)
 }
 
-func countTotalParks(state *GameState) int {
+func countTotalParks(state *engine.GameState) int {
 	count := 0
 	for _, row := range state.Grid {
 		for _, cell := range row {
-			if cell.Type == "park" {
+			if cell.Type == engine.Park {
 				count++
 			}
 		}
@@ -1,14 +1,27 @@
 // Command analyze prints quick, human-readable heuristics about configuration
 // files in the project's configs directory. It summarizes dimensions, battery
 // settings, counts of chargers and parks, and highlights unreachable locations
-// based on Manhattan distance vs. max battery.
+// based on road distance (a flood fill over passable cells) vs. max battery.
+// The heuristics themselves live in engine.AnalyzeConfig and are shared with
+// the API.
+//
+// Flags:
+//
+//	-dir     directory of configs to analyze (default "configs")
+//	-config  analyze a single file instead of a directory
+//	-json    emit the analyses as a JSON array for scripts and CI; exits 1 if
+//	         any file could not be analyzed
 package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
+	"os"
 	"path/filepath"
+	"sort"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
 )
 
 // AnalysisConfig is a light struct for reading config files used by analysis.
@@ -16,6 +29,8 @@ type AnalysisConfig struct {
 	Name              string            `json:"name"`
 	Description       string            `json:"description"`
 	GridSize          int               `json:"grid_size"`
+	GridWidth         int               `json:"grid_width"`
+	GridHeight        int               `json:"grid_height"`
 	MaxBattery        int               `json:"max_battery"`
 	StartingBattery   int               `json:"starting_battery"`
 	Layout            []string          `json:"layout"`
@@ -24,138 +39,143 @@ type AnalysisConfig struct {
 	Messages          map[string]string `json:"messages"`
 }
 
-// AnalysisPoint denotes a grid coordinate used during analysis output.
-type AnalysisPoint struct {
-	X, Y int
+// AnalysisResult is one entry of the -json output
+type AnalysisResult struct {
+	File     string                 `json:"file"`
+	Analysis *engine.ConfigAnalysis `json:"analysis,omitempty"`
+	Error    string                 `json:"error,omitempty"`
 }
 
 func main() {
-	configs := []string{
-		"challenge.json",
-		"classic.json",
-		"easy.json",
-		"easy_circuit.json",
-		"easy_gardens.json",
-		"hard_expedition.json",
-		"medium_maze.json",
+	os.Exit(run(os.Args[1:]))
+}
+
+// run parses flags and analyzes the selected configs, returning the exit code
+func run(args []string) int {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "Emit the analysis as JSON instead of text")
+	configPath := fs.String("config", "", "Analyze a single config file")
+	dir := fs.String("dir", "configs", "Directory of config files to analyze")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	configs := []string{*configPath}
+	if *configPath == "" {
+		configs, _ = filepath.Glob(filepath.Join(*dir, "*.json"))
+		sort.Strings(configs)
 	}
 
-	for _, configFile := range configs {
-		fmt.Printf("\n=== Analyzing %s ===\n", configFile)
-		analyzeConfig(filepath.Join("configs", configFile))
+	if *jsonOutput {
+		return writeJSON(configs)
 	}
+
+	if len(configs) == 0 {
+		fmt.Printf("No config files found in %s/\n", *dir)
+		return 0
+	}
+	for _, path := range configs {
+		fmt.Printf("\n=== Analyzing %s ===\n", filepath.Base(path))
+		analyzeConfig(path)
+	}
+	return 0
 }
 
-func analyzeConfig(path string) {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		fmt.Printf("Error reading file: %v\n", err)
-		return
+// writeJSON prints every config's analysis as a JSON array. It returns 1 when
+// any file could not be analyzed so CI jobs fail on broken configs.
+func writeJSON(configs []string) int {
+	code := 0
+	results := make([]AnalysisResult, 0, len(configs))
+	for _, path := range configs {
+		result := AnalysisResult{File: filepath.Base(path)}
+		if _, analysis, err := loadAnalysis(path); err != nil {
+			result.Error = err.Error()
+			code = 1
+		} else {
+			result.Analysis = &analysis
+		}
+		results = append(results, result)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(results); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+		return 1
 	}
+	return code
+}
 
+// loadAnalysis reads a config file and runs the engine's analysis on it
+func loadAnalysis(path string) (AnalysisConfig, engine.ConfigAnalysis, error) {
 	var config AnalysisConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, engine.ConfigAnalysis{}, fmt.Errorf("reading file: %w", err)
+	}
 	if err := json.Unmarshal(data, &config); err != nil {
-		fmt.Printf("Error parsing JSON: %v\n", err)
-		return
+		return config, engine.ConfigAnalysis{}, fmt.Errorf("parsing JSON: %w", err)
 	}
 
-	fmt.Printf("Name: %s\n", config.Name)
-	fmt.Printf("Grid Size: %d x %d\n", config.GridSize, len(config.Layout))
-	fmt.Printf("Max Battery: %d\n", config.MaxBattery)
-	fmt.Printf("Starting Battery: %d\n", config.StartingBattery)
-
-	// Find all chargers (S and H)
-	var chargers []AnalysisPoint
-	var parks []AnalysisPoint
-	var homePos AnalysisPoint
-	foundHome := false
-
-	for y, row := range config.Layout {
-		for x, cell := range row {
-			switch cell {
-			case 'S':
-				chargers = append(chargers, AnalysisPoint{x, y})
-			case 'H':
-				chargers = append(chargers, AnalysisPoint{x, y})
-				if !foundHome {
-					homePos = AnalysisPoint{x, y}
-					foundHome = true
-				}
-			case 'P':
-				parks = append(parks, AnalysisPoint{x, y})
-			}
-		}
-	}
+	analysis := engine.AnalyzeConfig(&engine.GameConfig{
+		Name:              config.Name,
+		Description:       config.Description,
+		GridSize:          config.GridSize,
+		GridWidth:         config.GridWidth,
+		GridHeight:        config.GridHeight,
+		MaxBattery:        config.MaxBattery,
+		StartingBattery:   config.StartingBattery,
+		Layout:            config.Layout,
+		Legend:            config.Legend,
+		WallCrashEndsGame: config.WallCrashEndsGame,
+	})
+	return config, analysis, nil
+}
 
-	fmt.Printf("Home Position: (%d, %d)\n", homePos.X, homePos.Y)
-	fmt.Printf("Total Chargers (S+H): %d\n", len(chargers))
-	fmt.Printf("Total Parks: %d\n", len(parks))
-
-	// Check reachability from any position
-	maxReachableDistance := config.MaxBattery
-	unreachablePoints := []AnalysisPoint{}
-
-	for y, row := range config.Layout {
-		for x, cell := range row {
-			if cell == 'R' || cell == 'P' || cell == 'S' || cell == 'H' {
-				// This is a traversable cell
-				minDistToCharger := 999999
-				for _, charger := range chargers {
-					dist := abs(x-charger.X) + abs(y-charger.Y)
-					if dist < minDistToCharger {
-						minDistToCharger = dist
-					}
-				}
-				if minDistToCharger > maxReachableDistance {
-					unreachablePoints = append(unreachablePoints, AnalysisPoint{x, y})
-				}
-			}
-		}
+func analyzeConfig(path string) {
+	config, analysis, err := loadAnalysis(path)
+	if err != nil {
+		fmt.Printf("Error %v\n", err)
+		return
 	}
 
-	if len(unreachablePoints) > 0 {
-		fmt.Printf("⚠️  WARNING: %d points are unreachable from any charger!\n", len(unreachablePoints))
-		fmt.Printf("   Max battery: %d, but some points are further than this from all chargers\n", config.MaxBattery)
-		for i, p := range unreachablePoints {
+	fmt.Printf("Name: %s\n", analysis.Name)
+	fmt.Printf("Grid Size: %d x %d\n", analysis.Width, analysis.Height)
+	fmt.Printf("Max Battery: %d\n", analysis.MaxBattery)
+	fmt.Printf("Starting Battery: %d\n", analysis.StartingBattery)
+	fmt.Printf("Home Position: (%d, %d)\n", analysis.HomePosition.X, analysis.HomePosition.Y)
+	fmt.Printf("Total Chargers (S+H): %d\n", analysis.ChargerCount)
+	fmt.Printf("Total Parks: %d\n", analysis.ParkCount)
+	fmt.Printf("Difficulty Score: %d/100\n", analysis.DifficultyScore)
+
+	if len(analysis.UnreachableCells) > 0 {
+		fmt.Printf("⚠️  WARNING: %d points are unreachable from any charger!\n", len(analysis.UnreachableCells))
+		fmt.Printf("   Max battery: %d, but some points are walled off or further than this by road from all chargers\n", config.MaxBattery)
+		for i, p := range analysis.UnreachableCells {
 			if i < 5 { // Show first 5 unreachable points
 				fmt.Printf("   Unreachable: (%d, %d) - '%c'\n", p.X, p.Y, config.Layout[p.Y][p.X])
 			}
 		}
-		if len(unreachablePoints) > 5 {
-			fmt.Printf("   ... and %d more\n", len(unreachablePoints)-5)
+		if len(analysis.UnreachableCells) > 5 {
+			fmt.Printf("   ... and %d more\n", len(analysis.UnreachableCells)-5)
 		}
 	} else {
 		fmt.Printf("✅ All traversable points are within reach of at least one charger\n")
 	}
 
-	// Check if all parks are reachable
-	unreachableParks := []AnalysisPoint{}
-	for _, park := range parks {
-		minDistToCharger := 999999
-		for _, charger := range chargers {
-			dist := abs(park.X-charger.X) + abs(park.Y-charger.Y)
-			if dist < minDistToCharger {
-				minDistToCharger = dist
-			}
-		}
-		if minDistToCharger > maxReachableDistance {
-			unreachableParks = append(unreachableParks, park)
-		}
-	}
-
-	if len(unreachableParks) > 0 {
-		fmt.Printf("⚠️  CRITICAL: %d parks are unreachable from any charger!\n", len(unreachableParks))
-		for _, p := range unreachableParks {
+	if len(analysis.UnreachableParks) > 0 {
+		fmt.Printf("⚠️  CRITICAL: %d parks are unreachable from any charger!\n", len(analysis.UnreachableParks))
+		for _, p := range analysis.UnreachableParks {
 			fmt.Printf("   Unreachable Park: (%d, %d)\n", p.X, p.Y)
 		}
 	} else {
 		fmt.Printf("✅ All parks are within reach of at least one charger\n")
 	}
-}
 
-func abs(x int) int {
-	if x < 0 {
-		return -x
+	if len(analysis.OneWayParks) > 0 {
+		fmt.Printf("ℹ️  %d parks have no charger within return range and must be visited last:\n", len(analysis.OneWayParks))
+		for _, p := range analysis.OneWayParks {
+			fmt.Printf("   One-way Park: (%d, %d)\n", p.X, p.Y)
+		}
 	}
-	return x
 }
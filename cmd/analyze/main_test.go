@@ -1,8 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -47,38 +50,6 @@ func TestAnalysisConfig(t *testing.T) {
 	}
 }
 
-func TestAnalysisPoint(t *testing.T) {
-	point := AnalysisPoint{X: 3, Y: 5}
-
-	if point.X != 3 {
-		t.Errorf("Expected X 3, got %d", point.X)
-	}
-
-	if point.Y != 5 {
-		t.Errorf("Expected Y 5, got %d", point.Y)
-	}
-}
-
-func TestAbs(t *testing.T) {
-	tests := []struct {
-		input    int
-		expected int
-	}{
-		{5, 5},
-		{-5, 5},
-		{0, 0},
-		{-10, 10},
-		{100, 100},
-	}
-
-	for _, test := range tests {
-		result := abs(test.input)
-		if result != test.expected {
-			t.Errorf("abs(%d) = %d, expected %d", test.input, result, test.expected)
-		}
-	}
-}
-
 func TestAnalyzeConfig_ValidFile(t *testing.T) {
 	// Create a temporary test config file
 	validConfig := `{
@@ -217,16 +188,17 @@ func TestMain_Integration(t *testing.T) {
 		t.Fatalf("Failed to move config file: %v", err)
 	}
 
-	// Test that main doesn't panic (we can't easily test output without complex mocking)
+	// Test that run doesn't panic (we can't easily test output without complex mocking)
 	defer func() {
 		if r := recover(); r != nil {
-			t.Errorf("main() panicked: %v", r)
+			t.Errorf("run() panicked: %v", r)
 		}
 	}()
 
-	// We can't call main() directly as it would process all hardcoded configs,
-	// but we can test analyzeConfig with our test file
-	analyzeConfig("configs/classic.json")
+	// run discovers configs from the configs directory by default
+	if code := run(nil); code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
 }
 
 func TestAnalyzeConfig_ReachabilityAnalysis(t *testing.T) {
@@ -276,3 +248,85 @@ func TestAnalyzeConfig_ReachabilityAnalysis(t *testing.T) {
 
 	analyzeConfig(tmpfile.Name())
 }
+
+func TestAnalyzeConfig_MazeUsesRoadDistance(t *testing.T) {
+	// testdata/maze.json has a park two cells from home that is walled in by
+	// water, and a park four cells away whose road is twelve moves long
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	analyzeConfig(filepath.Join("testdata", "maze.json"))
+	os.Stdout = stdout
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	output := string(out)
+
+	for _, want := range []string{
+		"1 parks are unreachable from any charger",
+		"Unreachable Park: (1, 3)",
+		"One-way Park: (1, 5)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Unreachable Park: (1, 5)") {
+		t.Errorf("Park at the end of the winding road should be reachable, got:\n%s", output)
+	}
+}
+
+func TestRun_JSONSingleConfig(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	code := run([]string{"-json", "-config", filepath.Join("testdata", "maze.json")})
+	os.Stdout = stdout
+	w.Close()
+
+	if code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+	var results []AnalysisResult
+	if err := json.NewDecoder(r).Decode(&results); err != nil {
+		t.Fatalf("Failed to decode JSON output: %v", err)
+	}
+	if len(results) != 1 || results[0].File != "maze.json" || results[0].Analysis == nil {
+		t.Fatalf("Expected one analysis for maze.json, got %+v", results)
+	}
+	if a := results[0].Analysis; a.ParkCount != 2 || len(a.UnreachableParks) != 1 {
+		t.Errorf("Expected 2 parks with 1 unreachable, got %d and %v", a.ParkCount, a.UnreachableParks)
+	}
+}
+
+func TestRun_JSONReportsErrors(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	code := run([]string{"-json", "-dir", "testdata", "-config", "/non/existent/file.json"})
+	os.Stdout = stdout
+	w.Close()
+
+	if code != 1 {
+		t.Errorf("Expected exit code 1 for a missing file, got %d", code)
+	}
+	var results []AnalysisResult
+	if err := json.NewDecoder(r).Decode(&results); err != nil {
+		t.Fatalf("Failed to decode JSON output: %v", err)
+	}
+	if len(results) != 1 || results[0].Error == "" || results[0].Analysis != nil {
+		t.Errorf("Expected a single error entry, got %+v", results)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"image/color"
@@ -28,6 +29,8 @@ const (
 	baseURL           = "http://localhost:8080"
 	animationDuration = 150 * time.Millisecond // Smooth animation duration
 	crashDuration     = 400 * time.Millisecond // Crash animation duration
+	flashDuration     = 300 * time.Millisecond // Rejected click flash duration
+	welcomeRowHeight  = 15                     // Line height of session rows on the welcome screen
 )
 
 // ScreenType represents different screens in the app
@@ -60,16 +63,20 @@ type Cell struct {
 
 // GameState represents the state from the Tesla game server
 type GameState struct {
-	Grid        [][]Cell `json:"grid"`
-	PlayerPos   Position `json:"player_pos"`
-	Battery     int      `json:"battery"`
-	MaxBattery  int      `json:"max_battery"`
-	Score       int      `json:"score"`
-	GameOver    bool     `json:"game_over"`
-	Victory     bool     `json:"victory"`
-	Message     string   `json:"message"`
-	ConfigName  string   `json:"config_name"`
-	MoveHistory []Move   `json:"move_history,omitempty"`
+	Grid           [][]Cell `json:"grid"`
+	PlayerPos      Position `json:"player_pos"`
+	Battery        int      `json:"battery"`
+	MaxBattery     int      `json:"max_battery"`
+	Score          int      `json:"score"`
+	GameOver       bool     `json:"game_over"`
+	Victory        bool     `json:"victory"`
+	GameOverReason string   `json:"game_over_reason,omitempty"`
+	Message        string   `json:"message"`
+	ConfigName     string   `json:"config_name"`
+	MoveHistory    []Move   `json:"move_history,omitempty"`
+	// LastMoveOutcome is what the last move did: "moved", "blocked",
+	// "charged", "collected", "victory" or "game_over"
+	LastMoveOutcome string `json:"last_move_outcome,omitempty"`
 }
 
 // Move represents a single move in history
@@ -101,23 +108,29 @@ type SessionData struct {
 	state         *GameState
 	wsConn        *websocket.Conn
 	lastUpdate    time.Time
-	prevPos       Position  // Previous position for interpolation
-	targetPos     Position  // Target position for interpolation
-	moveStartTime time.Time // When the move started
-	animationTime float64   // Animation progress 0.0 to 1.0
-	crashTime     time.Time // When a crash happened
-	isCrashing    bool      // Currently showing crash animation
+	prevPos       Position   // Previous position for interpolation
+	targetPos     Position   // Target position for interpolation
+	moveStartTime time.Time  // When the move started
+	animationTime float64    // Animation progress 0.0 to 1.0
+	crashTime     time.Time  // When a crash happened
+	isCrashing    bool       // Currently showing crash animation
+	plannedPath   []Position // Click-to-move route still to be driven
+	bulkInFlight  bool       // A click-to-move bulk request is pending
+	flashPos      Position   // Cell flashed red after a rejected click
+	flashTime     time.Time  // When the rejected click happened
+	isFlashing    bool       // Currently flashing a rejected cell
 }
 
 // SessionListItem represents a session from the server
 type SessionListItem struct {
-	ID         string `json:"id"`
-	ConfigName string `json:"config_name"`
-	CreatedAt  string `json:"created_at"`
-	Battery    int    `json:"battery"`
-	Score      int    `json:"score"`
-	Victory    bool   `json:"victory"`
-	GameOver   bool   `json:"game_over"`
+	ID             string `json:"id"`
+	ConfigName     string `json:"config_name"`
+	CreatedAt      string `json:"created_at"`
+	Battery        int    `json:"battery"`
+	Score          int    `json:"score"`
+	Victory        bool   `json:"victory"`
+	GameOver       bool   `json:"game_over"`
+	GameOverReason string `json:"game_over_reason,omitempty"`
 }
 
 // ConfigListItem represents a game configuration
@@ -311,8 +324,8 @@ func (g *Game) listenWebSocket(session *SessionData) {
 				session.moveStartTime = time.Now()
 				session.animationTime = 0.0
 				session.isCrashing = false
-			} else if newMoves > oldMoves {
-				// Move was attempted but position didn't change - CRASH!
+			} else if newMoves > oldMoves && wsMsg.GameState.LastMoveOutcome == "blocked" {
+				// The server reports the new move hit an obstacle - CRASH!
 				session.crashTime = time.Now()
 				session.isCrashing = true
 			}
@@ -324,6 +337,7 @@ func (g *Game) listenWebSocket(session *SessionData) {
 		}
 		session.state = wsMsg.GameState
 		session.lastUpdate = time.Now()
+		session.advancePlannedPath()
 		g.stateMutex.Unlock()
 	}
 }
@@ -366,8 +380,8 @@ func (g *Game) fetchGameState(session *SessionData) error {
 			session.moveStartTime = time.Now()
 			session.animationTime = 0.0
 			session.isCrashing = false
-		} else if newMoves > oldMoves {
-			// Move was attempted but position didn't change - CRASH!
+		} else if newMoves > oldMoves && state.LastMoveOutcome == "blocked" {
+			// The server reports the new move hit an obstacle - CRASH!
 			session.crashTime = time.Now()
 			session.isCrashing = true
 		}
@@ -379,6 +393,7 @@ func (g *Game) fetchGameState(session *SessionData) error {
 	}
 	session.state = &state
 	session.lastUpdate = time.Now()
+	session.advancePlannedPath()
 	g.stateMutex.Unlock()
 
 	return nil
@@ -510,6 +525,91 @@ func (g *Game) sendAction(action string) error {
 	return g.fetchGameState(session)
 }
 
+// sendBulkMove drives a session along a click-to-move route with a single
+// bulk move request. The planned path is cleared once the final state is in.
+func (g *Game) sendBulkMove(session *SessionData, moves []string) error {
+	defer func() {
+		g.stateMutex.Lock()
+		session.plannedPath = nil
+		session.bulkInFlight = false
+		g.stateMutex.Unlock()
+	}()
+
+	payload, err := json.Marshal(map[string][]string{"moves": moves})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/sessions/%s/bulk-move", baseURL, session.sessionID)
+	resp, err := http.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Printf("Bulk move rejected for %s: %s", session.sessionID, strings.TrimSpace(string(body)))
+	}
+
+	return g.fetchGameState(session)
+}
+
+// handleGridClick plans a route from the active car to the clicked cell and
+// submits it as one bulk move. Clicks on impassable or unreachable cells
+// flash the cell instead, and clicks during a pending bulk move are ignored.
+func (g *Game) handleGridClick(mouseX, mouseY int) {
+	g.stateMutex.Lock()
+	defer g.stateMutex.Unlock()
+
+	session := g.sessions[g.activeSession]
+	if session.state == nil || session.bulkInFlight || mouseX < 0 || mouseY < headerHeight {
+		return
+	}
+
+	target := Position{X: mouseX / cellSize, Y: (mouseY - headerHeight) / cellSize}
+	grid := session.state.Grid
+	if !inBounds(grid, target) {
+		return
+	}
+
+	var moves []string
+	if isPassable(grid[target.Y][target.X].Type) {
+		moves = findPath(grid, session.state.PlayerPos, target)
+	}
+	if moves == nil {
+		session.flashPos = target
+		session.flashTime = time.Now()
+		session.isFlashing = true
+		return
+	}
+	if len(moves) == 0 {
+		return // Already there
+	}
+
+	session.plannedPath = pathPositions(session.state.PlayerPos, moves)
+	session.bulkInFlight = true
+	go func() {
+		if err := g.sendBulkMove(session, moves); err != nil {
+			log.Printf("Error sending bulk move for %s: %v", session.sessionID, err)
+		}
+	}()
+}
+
+// advancePlannedPath drops the part of the planned route the car has already
+// driven. Callers must hold stateMutex.
+func (s *SessionData) advancePlannedPath() {
+	if s.state == nil {
+		return
+	}
+	for i, pos := range s.plannedPath {
+		if pos == s.state.PlayerPos {
+			s.plannedPath = s.plannedPath[i+1:]
+			return
+		}
+	}
+}
+
 // Update updates game logic
 func (g *Game) Update() error {
 	// Route to appropriate screen update
@@ -548,11 +648,17 @@ func (g *Game) updateWelcomeScreen() error {
 
 	// Toggle selection with Space
 	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-		if ws.cursorPos < len(ws.availableSessions) {
-			sessionID := ws.availableSessions[ws.cursorPos].ID
-			g.selectedSessions[sessionID] = !g.selectedSessions[sessionID]
-			if !g.selectedSessions[sessionID] {
-				delete(g.selectedSessions, sessionID)
+		g.toggleSessionSelection(ws.cursorPos)
+	}
+
+	// Toggle selection by clicking a session row
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && !ws.loading {
+		_, mouseY := ebiten.CursorPosition()
+		if offset := mouseY - ws.sessionRowsTop(); offset >= 0 {
+			row := offset / welcomeRowHeight
+			if row < len(ws.availableSessions) {
+				ws.cursorPos = row
+				g.toggleSessionSelection(row)
 			}
 		}
 	}
@@ -598,6 +704,29 @@ func (g *Game) updateWelcomeScreen() error {
 	return nil
 }
 
+// toggleSessionSelection flips the selection of the session at a welcome screen row
+func (g *Game) toggleSessionSelection(row int) {
+	ws := g.welcomeScreen
+	if row < 0 || row >= len(ws.availableSessions) {
+		return
+	}
+	sessionID := ws.availableSessions[row].ID
+	g.selectedSessions[sessionID] = !g.selectedSessions[sessionID]
+	if !g.selectedSessions[sessionID] {
+		delete(g.selectedSessions, sessionID)
+	}
+}
+
+// sessionRowsTop returns the y coordinate of the first session row, matching
+// the layout in drawWelcomeScreen
+func (ws *WelcomeScreen) sessionRowsTop() int {
+	y := 20 + 30 + 20 // Title, then the "Available Sessions:" heading
+	if ws.errorMsg != "" {
+		y += 20
+	}
+	return y
+}
+
 // updateGameScreen handles game screen input
 func (g *Game) updateGameScreen() error {
 	if len(g.sessions) == 0 {
@@ -619,6 +748,11 @@ func (g *Game) updateGameScreen() error {
 		if session.isCrashing && time.Since(session.crashTime) > crashDuration {
 			session.isCrashing = false
 		}
+
+		// End rejected click flash after duration
+		if session.isFlashing && time.Since(session.flashTime) > flashDuration {
+			session.isFlashing = false
+		}
 	}
 	g.stateMutex.Unlock()
 
@@ -669,6 +803,16 @@ func (g *Game) updateGameScreen() error {
 		g.sendAction("reset")
 	}
 
+	// Click a cell to drive there; right-click cancels the route preview
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		g.handleGridClick(ebiten.CursorPosition())
+	}
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		g.stateMutex.Lock()
+		g.sessions[g.activeSession].plannedPath = nil
+		g.stateMutex.Unlock()
+	}
+
 	// Return to welcome screen with Escape
 	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
 		g.currentScreen = ScreenWelcome
@@ -732,6 +876,8 @@ func (g *Game) drawWelcomeScreen(screen *ebiten.Image) {
 			status := ""
 			if session.Victory {
 				status = " VICTORY"
+			} else if session.GameOverReason != "" {
+				status = " GAME OVER: " + session.GameOverReason
 			} else if session.GameOver {
 				status = " GAME OVER"
 			}
@@ -741,7 +887,7 @@ func (g *Game) drawWelcomeScreen(screen *ebiten.Image) {
 				session.Battery, session.Score, status)
 
 			ebitenutil.DebugPrintAt(screen, line, 20, y)
-			y += 15
+			y += welcomeRowHeight
 		}
 	}
 
@@ -786,7 +932,7 @@ func (g *Game) drawWelcomeScreen(screen *ebiten.Image) {
 	y += 20
 	ebitenutil.DebugPrintAt(screen, "  ↑/↓      - Navigate sessions", 20, y)
 	y += 15
-	ebitenutil.DebugPrintAt(screen, "  SPACE    - Toggle session selection", 20, y)
+	ebitenutil.DebugPrintAt(screen, "  SPACE    - Toggle session selection (or click a row)", 20, y)
 	y += 15
 	ebitenutil.DebugPrintAt(screen, "  TAB      - Cycle config for new session", 20, y)
 	y += 15
@@ -865,6 +1011,30 @@ func (g *Game) drawGameScreen(screen *ebiten.Image) {
 		}
 	}
 
+	// Highlight planned click-to-move routes and rejected clicks
+	for idx, session := range g.sessions {
+		carColor := carColors[idx%len(carColors)]
+		pathColor := color.RGBA{carColor.R, carColor.G, carColor.B, 90}
+		for _, pos := range session.plannedPath {
+			ebitenutil.DrawRect(screen,
+				float64(pos.X*cellSize),
+				float64(pos.Y*cellSize+gridOffsetY),
+				cellSize-1, cellSize-1, pathColor)
+		}
+
+		if session.isFlashing {
+			flashProgress := time.Since(session.flashTime).Seconds() / flashDuration.Seconds()
+			if flashProgress > 1.0 {
+				flashProgress = 1.0
+			}
+			ebitenutil.DrawRect(screen,
+				float64(session.flashPos.X*cellSize),
+				float64(session.flashPos.Y*cellSize+gridOffsetY),
+				cellSize-1, cellSize-1,
+				color.RGBA{255, 0, 0, uint8((1.0 - flashProgress) * 200)})
+		}
+	}
+
 	// Draw trails for each car (before drawing the cars themselves)
 	for idx, session := range g.sessions {
 		if session.state == nil || len(session.state.MoveHistory) == 0 {
@@ -962,7 +1132,7 @@ func (g *Game) drawGameScreen(screen *ebiten.Image) {
 	}
 
 	// Footer controls
-	ebitenutil.DebugPrintAt(screen, "1-9: Switch Car | N: New Car | Arrow/WASD: Move | R: Reset | ESC: Menu", 10, screenHeight-20)
+	ebitenutil.DebugPrintAt(screen, "1-9: Switch Car | N: New Car | Arrow/WASD: Move | Click: Drive To | Right-Click: Cancel | R: Reset | ESC: Menu", 10, screenHeight-20)
 }
 
 // drawSessionStats draws stats for all sessions in header
@@ -1006,6 +1176,9 @@ func (g *Game) drawSessionStats(screen *ebiten.Image) {
 			info += " VICTORY!"
 		} else if session.state.GameOver {
 			info += " GAME OVER"
+			if session.state.GameOverReason != "" {
+				info += " (" + session.state.GameOverReason + ")"
+			}
 		}
 
 		ebitenutil.DebugPrintAt(screen, info, 20, y)
@@ -1017,6 +1190,85 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return screenWidth, screenHeight
 }
 
+// isPassable reports whether a car can drive onto a cell of this type
+func isPassable(cellType string) bool {
+	return cellType != "water" && cellType != "building"
+}
+
+// inBounds reports whether pos lies on the grid
+func inBounds(grid [][]Cell, pos Position) bool {
+	return pos.Y >= 0 && pos.Y < len(grid) && pos.X >= 0 && pos.X < len(grid[pos.Y])
+}
+
+// nextPosition returns the cell one step from pos in the given direction
+func nextPosition(pos Position, dir string) Position {
+	switch dir {
+	case "up":
+		return Position{X: pos.X, Y: pos.Y - 1}
+	case "down":
+		return Position{X: pos.X, Y: pos.Y + 1}
+	case "left":
+		return Position{X: pos.X - 1, Y: pos.Y}
+	case "right":
+		return Position{X: pos.X + 1, Y: pos.Y}
+	}
+	return pos
+}
+
+// findPath returns the shortest list of moves from start to goal over passable
+// cells, mirroring the bruteforcer's BFS. It returns nil when goal is
+// unreachable and an empty list when start is already the goal.
+func findPath(grid [][]Cell, start, goal Position) []string {
+	if start == goal {
+		return []string{}
+	}
+
+	type QueueItem struct {
+		pos  Position
+		path []string
+	}
+
+	queue := []QueueItem{{pos: start, path: []string{}}}
+	visited := make(map[Position]bool)
+	visited[start] = true
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range []string{"up", "down", "left", "right"} {
+			newPos := nextPosition(current.pos, dir)
+
+			if visited[newPos] || !inBounds(grid, newPos) || !isPassable(grid[newPos.Y][newPos.X].Type) {
+				continue
+			}
+
+			newPath := append([]string{}, current.path...)
+			newPath = append(newPath, dir)
+
+			if newPos == goal {
+				return newPath
+			}
+
+			visited[newPos] = true
+			queue = append(queue, QueueItem{pos: newPos, path: newPath})
+		}
+	}
+
+	return nil
+}
+
+// pathPositions lists the cells visited when following moves from start
+func pathPositions(start Position, moves []string) []Position {
+	positions := make([]Position, 0, len(moves))
+	pos := start
+	for _, dir := range moves {
+		pos = nextPosition(pos, dir)
+		positions = append(positions, pos)
+	}
+	return positions
+}
+
 // getCellColor returns the color for each cell type
 func getCellColor(cellType string, visited bool) color.Color {
 	switch cellType {
@@ -1026,6 +1278,8 @@ func getCellColor(cellType string, visited bool) color.Color {
 		return color.RGBA{0, 200, 0, 255} // Green for home
 	case "supercharger":
 		return color.RGBA{255, 0, 0, 255} // Red for supercharger
+	case "fuel":
+		return color.RGBA{255, 220, 0, 255} // Yellow for fuel
 	case "water":
 		return color.RGBA{0, 100, 200, 255} // Blue for water
 	case "building":
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// Checksum hashes the parts of a configuration that change how a game plays:
+// the layout and the rules. The name, description and messages are left out
+// so rewording a config doesn't mark its sessions as drifted.
+func Checksum(config *engine.GameConfig) string {
+	rules := *config
+	rules.Name = ""
+	rules.Description = ""
+	rules.Messages = engine.GameConfig{}.Messages
+
+	data, err := json.Marshal(&rules)
+	if err != nil {
+		return "" // Every GameConfig field marshals; unreachable
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
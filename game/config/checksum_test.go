@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestChecksum(t *testing.T) {
+	base := Checksum(createValidConfig())
+
+	reworded := createValidConfig()
+	reworded.Name = "Renamed"
+	reworded.Description = "Different words"
+	reworded.Messages.Welcome = "Hello!"
+	if got := Checksum(reworded); got != base {
+		t.Errorf("Cosmetic changes should keep the checksum, got %s want %s", got, base)
+	}
+
+	relaid := createValidConfig()
+	relaid.Layout[1] = "BRHRB"
+	if Checksum(relaid) == base {
+		t.Error("Layout change should change the checksum")
+	}
+
+	rules := createValidConfig()
+	rules.WallCrashEndsGame = true
+	if Checksum(rules) == base {
+		t.Error("Rule change should change the checksum")
+	}
+}
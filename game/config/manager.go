@@ -23,19 +23,40 @@ type Manager struct {
 	configDir     string
 	defaultConfig *engine.GameConfig
 	configs       map[string]*engine.GameConfig
+	maxGridSize   int // Largest grid width or height accepted, see WithMaxGridSize
 	mu            sync.RWMutex
 }
 
+// Option configures optional config manager settings
+type Option func(*Manager)
+
+// WithMaxGridSize lets configs loaded or saved by the manager have grids up
+// to n cells across instead of engine.MaxGridSize. NewManager fails when n
+// is below engine.MinGridSize or above engine.GridSizeCeiling.
+func WithMaxGridSize(n int) Option {
+	return func(m *Manager) {
+		m.maxGridSize = n
+	}
+}
+
 // NewManager creates a new configuration manager
-func NewManager(configDir string) (*Manager, error) {
+func NewManager(configDir string, opts ...Option) (*Manager, error) {
 	// Ensure config directory exists
 	if _, err := os.Stat(configDir); os.IsNotExist(err) {
 		return nil, fmt.Errorf("config directory does not exist: %s", configDir)
 	}
 
 	m := &Manager{
-		configDir: configDir,
-		configs:   make(map[string]*engine.GameConfig),
+		configDir:   configDir,
+		configs:     make(map[string]*engine.GameConfig),
+		maxGridSize: engine.MaxGridSize,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.maxGridSize < engine.MinGridSize || m.maxGridSize > engine.GridSizeCeiling {
+		return nil, fmt.Errorf("max grid size must be between %d and %d, got %d",
+			engine.MinGridSize, engine.GridSizeCeiling, m.maxGridSize)
 	}
 
 	// Load default config
@@ -87,9 +108,10 @@ func (m *Manager) LoadConfig(name string) (*engine.GameConfig, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
+	config.NormalizeMetadata()
 
 	// Validate config
-	if err := engine.ValidateGameConfig(&config); err != nil {
+	if err := engine.ValidateGameConfig(&config, engine.WithMaxGridSize(m.maxGridSize)); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
 	}
 
@@ -122,13 +144,20 @@ func (m *Manager) ListConfigs() ([]*service.ConfigInfo, error) {
 			continue
 		}
 
+		width, height := config.Dimensions()
 		configs = append(configs, &service.ConfigInfo{
 			Filename:    entry.Name(),
 			ConfigID:    name, // This is the identifier to use for session creation
 			Name:        config.Name,
 			Description: config.Description,
+			Author:      config.Author,
+			Version:     config.Version,
+			Notes:       config.Notes,
 			GridSize:    config.GridSize,
+			GridWidth:   width,
+			GridHeight:  height,
 			MaxBattery:  config.MaxBattery,
+			Difficulty:  engine.AnalyzeConfig(config).DifficultyScore,
 		})
 	}
 
@@ -194,8 +223,10 @@ func (m *Manager) loadDefaultConfig() error {
 
 // SaveConfig saves a configuration to disk
 func (m *Manager) SaveConfig(name string, config *engine.GameConfig) error {
+	config.NormalizeMetadata()
+
 	// Validate config before saving
-	if err := engine.ValidateGameConfig(config); err != nil {
+	if err := engine.ValidateGameConfig(config, engine.WithMaxGridSize(m.maxGridSize)); err != nil {
 		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
 	}
 
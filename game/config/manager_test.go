@@ -2,8 +2,10 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
@@ -390,6 +392,112 @@ func TestManager_ValidateConfig(t *testing.T) {
 	})
 }
 
+// squareConfig returns a valid config with an n by n grid of roads
+func squareConfig(n int) *engine.GameConfig {
+	config := createValidConfig()
+	config.GridSize = n
+	config.Layout = make([]string, n)
+	for y := range config.Layout {
+		config.Layout[y] = strings.Repeat("R", n)
+	}
+	config.Layout[0] = "HP" + strings.Repeat("R", n-2)
+	return config
+}
+
+func TestManager_MaxGridSize(t *testing.T) {
+	dir := createTestConfigDir(t)
+	defer os.RemoveAll(dir)
+	writeConfigFile(t, dir, "default", createValidConfig())
+	writeConfigFile(t, dir, "big", squareConfig(60))
+
+	// The default keeps the engine's limit
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := manager.SaveConfig("edge", squareConfig(engine.MaxGridSize)); err != nil {
+		t.Errorf("Expected a %d grid to be accepted by default: %v", engine.MaxGridSize, err)
+	}
+	if err := manager.SaveConfig("over", squareConfig(engine.MaxGridSize+1)); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected a %d grid to be rejected by default, got: %v", engine.MaxGridSize+1, err)
+	}
+	if _, err := manager.LoadConfig("big"); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected the 60 grid to be rejected at load by default, got: %v", err)
+	}
+
+	// A raised limit accepts grids up to it
+	manager, err = NewManager(dir, WithMaxGridSize(60))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	config, err := manager.LoadConfig("big")
+	if err != nil {
+		t.Fatalf("Expected the 60 grid to load: %v", err)
+	}
+	if _, err := engine.NewEngine(config); err != nil {
+		t.Errorf("Expected an engine for the loaded 60 grid: %v", err)
+	}
+	if err := manager.SaveConfig("over", squareConfig(61)); err == nil || !strings.Contains(err.Error(), "between 5 and 60") {
+		t.Errorf("Expected a 61 grid to be rejected, got: %v", err)
+	}
+
+	// The limit itself is bounded
+	for _, n := range []int{engine.MinGridSize - 1, engine.GridSizeCeiling + 1} {
+		if _, err := NewManager(dir, WithMaxGridSize(n)); err == nil {
+			t.Errorf("Expected a max grid size of %d to be refused", n)
+		}
+	}
+}
+
+func TestManager_Metadata(t *testing.T) {
+	dir := createTestConfigDir(t)
+	defer os.RemoveAll(dir)
+	writeConfigFile(t, dir, "default", createValidConfig())
+
+	manager, err := NewManager(dir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	config := createValidConfig()
+	config.Author = "  Ada  "
+	config.Version = "1.2"
+	config.Notes = strings.Repeat("n", engine.MaxConfigNotesLength+10)
+	if err := manager.SaveConfig("annotated", config); err != nil {
+		t.Fatalf("Expected metadata to pass validation: %v", err)
+	}
+
+	// A fresh manager reads the tidied metadata back from the file
+	manager, err = NewManager(dir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	loaded, err := manager.LoadConfig("annotated")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if loaded.Author != "Ada" || loaded.Version != "1.2" || len(loaded.Notes) != engine.MaxConfigNotesLength {
+		t.Errorf("Expected trimmed metadata with notes cut to %d characters, got %q, %q and %d characters",
+			engine.MaxConfigNotesLength, loaded.Author, loaded.Version, len(loaded.Notes))
+	}
+
+	configs, err := manager.ListConfigs()
+	if err != nil {
+		t.Fatalf("Failed to list configs: %v", err)
+	}
+	for _, info := range configs {
+		switch info.ConfigID {
+		case "annotated":
+			if info.Author != "Ada" || info.Version != "1.2" || info.Notes != loaded.Notes {
+				t.Errorf("Expected the metadata in the listing, got %+v", info)
+			}
+		case "default":
+			if info.Author != "" || info.Version != "" || info.Notes != "" {
+				t.Errorf("Expected no metadata for the plain config, got %+v", info)
+			}
+		}
+	}
+}
+
 func TestManager_ConcurrentAccess(t *testing.T) {
 	dir := createTestConfigDir(t)
 	defer os.RemoveAll(dir)
@@ -486,7 +594,7 @@ func (m *Manager) ReloadConfig(name string) error {
 }
 
 func (m *Manager) ValidateConfig(config *engine.GameConfig) error {
-	return engine.ValidateGameConfig(config)
+	return engine.ValidateGameConfig(config, engine.WithMaxGridSize(m.maxGridSize))
 }
 
 func (m *Manager) Count() int {
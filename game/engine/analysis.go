@@ -0,0 +1,177 @@
+package engine
+
+import "math"
+
+// ConfigAnalysis summarizes difficulty heuristics for a game configuration
+type ConfigAnalysis struct {
+	Name            string `json:"name"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	MaxBattery      int    `json:"max_battery"`
+	StartingBattery int    `json:"starting_battery"`
+
+	HomePosition Position `json:"home_position"` // The primary home, where the game starts
+	HasHome      bool     `json:"has_home"`
+	ChargerCount int      `json:"charger_count"` // Homes and superchargers
+	ParkCount    int      `json:"park_count"`
+
+	// Road distance from each park to its nearest charger
+	MinParkChargerDistance int `json:"min_park_charger_distance"`
+	MaxParkChargerDistance int `json:"max_park_charger_distance"`
+
+	// Passable cells and parks that are walled off from home or farther than
+	// max battery by road from every charger
+	UnreachableCells []Position `json:"unreachable_cells"`
+	UnreachableParks []Position `json:"unreachable_parks"`
+
+	// Parks within range of a charger but too far to get back to one on the
+	// same charge, so they can only be visited as the final park
+	OneWayParks []Position `json:"one_way_parks"`
+
+	// Rough 0-100 estimate, higher is harder
+	DifficultyScore int `json:"difficulty_score"`
+}
+
+// AnalyzeConfig computes difficulty heuristics for a configuration using road
+// distances between cells and chargers. Distances come from a breadth-first
+// search over passable cells, so walls and water are accounted for.
+func AnalyzeConfig(cfg *GameConfig) ConfigAnalysis {
+	analysis := ConfigAnalysis{
+		Name:             cfg.Name,
+		Height:           len(cfg.Layout),
+		MaxBattery:       cfg.MaxBattery,
+		StartingBattery:  cfg.StartingBattery,
+		UnreachableCells: []Position{},
+		UnreachableParks: []Position{},
+		OneWayParks:      []Position{},
+	}
+
+	var chargers, parks []Position
+	for y, row := range cfg.Layout {
+		if len(row) > analysis.Width {
+			analysis.Width = len(row)
+		}
+		for x, char := range row {
+			switch char {
+			case 'S', 'C':
+				chargers = append(chargers, Position{X: x, Y: y})
+			case 'H':
+				chargers = append(chargers, Position{X: x, Y: y})
+				analysis.HasHome = true
+			case 'P':
+				parks = append(parks, Position{X: x, Y: y})
+			}
+		}
+	}
+	if analysis.HasHome {
+		analysis.HomePosition = primaryHome(cfg)
+	}
+	analysis.ChargerCount = len(chargers)
+	analysis.ParkCount = len(parks)
+
+	// Chargers walled off from home can never be used, so only chargers in
+	// home's region seed the search
+	if analysis.HasHome {
+		fromHome := layoutDistances(cfg.Layout, []Position{analysis.HomePosition})
+		connected := chargers[:0:0]
+		for _, charger := range chargers {
+			if fromHome[charger.Y][charger.X] != UnreachableDistance {
+				connected = append(connected, charger)
+			}
+		}
+		chargers = connected
+	}
+	toCharger := layoutDistances(cfg.Layout, chargers)
+
+	for y, row := range cfg.Layout {
+		for x, char := range row {
+			if layoutPassable(char) && toCharger[y][x] > cfg.MaxBattery {
+				analysis.UnreachableCells = append(analysis.UnreachableCells, Position{X: x, Y: y})
+			}
+		}
+	}
+
+	measured := false
+	for _, park := range parks {
+		dist := toCharger[park.Y][park.X]
+		if dist > cfg.MaxBattery {
+			analysis.UnreachableParks = append(analysis.UnreachableParks, park)
+		} else if 2*dist > cfg.MaxBattery {
+			analysis.OneWayParks = append(analysis.OneWayParks, park)
+		}
+		// Walled-off parks have no road distance to report
+		if dist == UnreachableDistance {
+			continue
+		}
+		if !measured || dist < analysis.MinParkChargerDistance {
+			measured = true
+			analysis.MinParkChargerDistance = dist
+		}
+		if dist > analysis.MaxParkChargerDistance {
+			analysis.MaxParkChargerDistance = dist
+		}
+	}
+
+	analysis.DifficultyScore = difficultyScore(analysis)
+	return analysis
+}
+
+// layoutPassable reports whether a layout character is a cell the car can
+// drive onto. Doors count as open, as if their keys had been collected.
+func layoutPassable(char rune) bool {
+	return char == 'R' || char == 'P' || char == 'S' || char == 'H' || char == 'F' || char == 'C' || char == 'K' ||
+		char == 'Y' || char == 'D'
+}
+
+// layoutDistances runs a multi-source breadth-first search over the passable
+// cells of a layout and returns the road distance from each cell to the
+// nearest source. Cells that cannot be reached hold UnreachableDistance.
+func layoutDistances(layout []string, sources []Position) [][]int {
+	dist := make([][]int, len(layout))
+	for y, row := range layout {
+		dist[y] = make([]int, len(row))
+		for x := range dist[y] {
+			dist[y][x] = UnreachableDistance
+		}
+	}
+
+	queue := make([]Position, 0, len(sources))
+	for _, src := range sources {
+		dist[src.Y][src.X] = 0
+		queue = append(queue, src)
+	}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, d := range []Position{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}} {
+			next := Position{X: pos.X + d.X, Y: pos.Y + d.Y}
+			if next.Y < 0 || next.Y >= len(layout) || next.X < 0 || next.X >= len(layout[next.Y]) {
+				continue
+			}
+			if !layoutPassable(rune(layout[next.Y][next.X])) || dist[next.Y][next.X] != UnreachableDistance {
+				continue
+			}
+			dist[next.Y][next.X] = dist[pos.Y][pos.X] + 1
+			queue = append(queue, next)
+		}
+	}
+	return dist
+}
+
+// difficultyScore weighs battery pressure (round trip to the farthest park
+// against max battery), park count and charger scarcity into a 0-100 score.
+// Configs with unreachable parks score 100.
+func difficultyScore(a ConfigAnalysis) int {
+	if len(a.UnreachableParks) > 0 {
+		return 100
+	}
+	if a.MaxBattery <= 0 || a.ParkCount == 0 {
+		return 0
+	}
+
+	pressure := math.Min(1, float64(2*a.MaxParkChargerDistance)/float64(a.MaxBattery))
+	parks := math.Min(1, float64(a.ParkCount)/15)
+	scarcity := 1 - math.Min(1, float64(a.ChargerCount)/float64(a.ParkCount))
+
+	return int(math.Round(60*pressure + 20*parks + 20*scarcity))
+}
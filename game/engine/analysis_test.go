@@ -0,0 +1,102 @@
+package engine
+
+import "testing"
+
+func TestAnalyzeConfig(t *testing.T) {
+	config := createValidConfig()
+
+	analysis := AnalyzeConfig(config)
+
+	if analysis.Width != 5 || analysis.Height != 5 {
+		t.Errorf("Expected 5x5, got %dx%d", analysis.Width, analysis.Height)
+	}
+	if !analysis.HasHome || analysis.HomePosition != (Position{X: 2, Y: 1}) {
+		t.Errorf("Expected home at (2,1), got %+v (found=%v)", analysis.HomePosition, analysis.HasHome)
+	}
+	if analysis.ChargerCount != 1 || analysis.ParkCount != 4 {
+		t.Errorf("Expected 1 charger and 4 parks, got %d and %d", analysis.ChargerCount, analysis.ParkCount)
+	}
+	if analysis.MinParkChargerDistance != 1 || analysis.MaxParkChargerDistance != 3 {
+		t.Errorf("Expected park distances 1..3, got %d..%d",
+			analysis.MinParkChargerDistance, analysis.MaxParkChargerDistance)
+	}
+	if len(analysis.UnreachableCells) != 0 || len(analysis.UnreachableParks) != 0 {
+		t.Errorf("Expected everything reachable, got %d cells and %d parks unreachable",
+			len(analysis.UnreachableCells), len(analysis.UnreachableParks))
+	}
+	if analysis.DifficultyScore <= 0 || analysis.DifficultyScore >= 100 {
+		t.Errorf("Expected a difficulty score strictly between 0 and 100, got %d", analysis.DifficultyScore)
+	}
+}
+
+func TestAnalyzeConfig_UnreachablePark(t *testing.T) {
+	config := createValidConfig()
+	config.MaxBattery = 2
+	config.StartingBattery = 2
+
+	analysis := AnalyzeConfig(config)
+
+	// Parks at (1,3) and (3,3) are 3 moves from home
+	if len(analysis.UnreachableParks) != 2 {
+		t.Errorf("Expected 2 unreachable parks, got %d", len(analysis.UnreachableParks))
+	}
+	if len(analysis.UnreachableCells) == 0 {
+		t.Error("Expected unreachable cells")
+	}
+	if analysis.DifficultyScore != 100 {
+		t.Errorf("Expected difficulty 100 with unreachable parks, got %d", analysis.DifficultyScore)
+	}
+}
+
+func TestAnalyzeConfig_Maze(t *testing.T) {
+	config := createValidConfig()
+	config.GridSize = 7
+	config.MaxBattery = 12
+	config.Layout = []string{
+		"BBBBBBB",
+		"BHRRRRB",
+		"BWWWWRB",
+		"BPWRRRB", // (1,3) is two cells from home but walled in by water
+		"BWWRWWB",
+		"BPRRBBB", // (1,5) is four cells from home but twelve by road
+		"BBBBBBB",
+	}
+
+	analysis := AnalyzeConfig(config)
+
+	if len(analysis.UnreachableParks) != 1 || analysis.UnreachableParks[0] != (Position{X: 1, Y: 3}) {
+		t.Errorf("Expected only the walled-in park to be unreachable, got %v", analysis.UnreachableParks)
+	}
+	if len(analysis.UnreachableCells) != 1 {
+		t.Errorf("Expected 1 unreachable cell, got %v", analysis.UnreachableCells)
+	}
+	if len(analysis.OneWayParks) != 1 || analysis.OneWayParks[0] != (Position{X: 1, Y: 5}) {
+		t.Errorf("Expected the winding-road park to be one-way, got %v", analysis.OneWayParks)
+	}
+	if analysis.MinParkChargerDistance != 12 || analysis.MaxParkChargerDistance != 12 {
+		t.Errorf("Expected road distance 12 to the reachable park, got %d..%d",
+			analysis.MinParkChargerDistance, analysis.MaxParkChargerDistance)
+	}
+}
+
+func TestAnalyzeConfig_ChargerWalledOffFromHome(t *testing.T) {
+	config := createValidConfig()
+	config.MaxBattery = 3
+	config.Layout = []string{
+		"BBBBB",
+		"BRHRB",
+		"BWWWB",
+		"BPSRB",
+		"BBBBB",
+	}
+
+	analysis := AnalyzeConfig(config)
+
+	// The supercharger's island can never be reached, so its park doesn't count
+	if len(analysis.UnreachableParks) != 1 {
+		t.Errorf("Expected the island park to be unreachable, got %v", analysis.UnreachableParks)
+	}
+	if len(analysis.UnreachableCells) != 3 {
+		t.Errorf("Expected the 3 island cells to be unreachable, got %v", analysis.UnreachableCells)
+	}
+}
@@ -0,0 +1,25 @@
+package engine
+
+// carryParks marks the parks with the given IDs collected on a state fresh
+// from reset, keeping their score and leaving the park order to the rest
+func (gs *GameState) carryParks(ids []string, config *GameConfig) {
+	if len(ids) == 0 {
+		return
+	}
+	carried := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		carried[id] = true
+	}
+	for y := range gs.Grid {
+		for x := range gs.Grid[y] {
+			if cell := &gs.Grid[y][x]; cell.Type == Park && carried[cell.ID] {
+				cell.Visited = true
+				gs.VisitedParks[cell.ID] = true
+				gs.Score++
+			}
+		}
+	}
+	gs.CarriedParks = ids
+	gs.NextPark = gs.nextOrderedPark(config)
+	gs.ReturnHome = gs.awaitingReturnHome(config)
+}
@@ -0,0 +1,100 @@
+package engine
+
+import "fmt"
+
+// DefaultTrickleCharge is the battery a trickle charger adds per charge
+// unless the config's charger effects say otherwise
+const DefaultTrickleCharge = 1
+
+// PassThroughCharge is the battery a charger that fills the battery adds on
+// arrival under the config's charge_requires_wait
+const PassThroughCharge = 1
+
+// ChargerEffect is how one kind of charger charges. Charge is the battery a
+// charge adds; 0 keeps the kind's default, which for homes and
+// superchargers is the config's charge_per_turn. Instant fills the battery
+// whatever charge_per_turn says. MovePenalty holds the player on the
+// charger for that many turns after a charge that added battery.
+type ChargerEffect struct {
+	Charge      int  `json:"charge,omitempty"`
+	Instant     bool `json:"instant,omitempty"`
+	MovePenalty int  `json:"move_penalty,omitempty"`
+}
+
+// IsCharger reports whether cells of type t charge the battery
+func IsCharger(t CellType) bool {
+	return t == Home || t == Supercharger || t == Trickle
+}
+
+// ChargerEffectFor returns the effect chargers of type t have under config,
+// with the defaults filled in: Instant is set when a charge fills the
+// battery, otherwise Charge is the battery it adds. Secondary homes may
+// still charge SecondaryHomeCharge instead.
+func ChargerEffectFor(t CellType, config *GameConfig) ChargerEffect {
+	effect := config.ChargerEffects[t]
+	switch {
+	case effect.Instant:
+		effect.Charge = 0
+	case effect.Charge > 0:
+	case t == Trickle:
+		effect.Charge = DefaultTrickleCharge
+	default:
+		effect.Charge = chargeRate(config)
+	}
+	effect.Instant = effect.Charge == 0
+	return effect
+}
+
+// validateChargerEffects checks that effects are only set for charger types
+// and that their values are in range
+func validateChargerEffects(config *GameConfig) error {
+	for t, effect := range config.ChargerEffects {
+		if !IsCharger(t) {
+			return fmt.Errorf("config validation: charger_effects key '%s' must be home, supercharger or trickle", t)
+		}
+		if effect.Charge < 0 || effect.Charge > config.MaxBattery {
+			return fmt.Errorf("config validation: charger_effects['%s'].charge must be between 0 and max_battery (%d), got %d", t, config.MaxBattery, effect.Charge)
+		}
+		if effect.Instant && effect.Charge > 0 {
+			return fmt.Errorf("config validation: charger_effects['%s'] can't set both charge and instant", t)
+		}
+		if effect.MovePenalty < 0 {
+			return fmt.Errorf("config validation: charger_effects['%s'].move_penalty must not be negative, got %d", t, effect.MovePenalty)
+		}
+	}
+	return nil
+}
+
+// chargesOnWait reports whether the charger the player is on only fills the
+// battery on a wait, under the config's charge_requires_wait. Chargers that
+// add a set amount per charge are unaffected.
+func (gs *GameState) chargesOnWait(config *GameConfig) bool {
+	if !config.ChargeRequiresWait || !IsCharger(gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type) {
+		return false
+	}
+	if config.SecondaryHomeCharge > 0 && gs.onSecondaryHome() {
+		return false
+	}
+	return gs.chargerEffectHere(config).Instant
+}
+
+// passThroughMessage reports the partial charge of driving onto a charger
+// that only fills the battery on a wait
+func (gs *GameState) passThroughMessage() string {
+	return fmt.Sprintf("%s: +%d battery passing through (%d/%d), wait here to charge fully",
+		chargerName(gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type), PassThroughCharge, gs.Battery, gs.MaxBattery)
+}
+
+// holdForCharge holds the player on the charger they just charged at for
+// its move penalty
+func (gs *GameState) holdForCharge(config *GameConfig) {
+	gs.ChargeHold = ChargerEffectFor(gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type, config).MovePenalty
+}
+
+// heldMessage explains a move turned down while the player is held on a charger
+func (gs *GameState) heldMessage(direction string) string {
+	if gs.ChargeHold == 0 {
+		return fmt.Sprintf("Can't move %s: still charging, free to drive next turn", direction)
+	}
+	return fmt.Sprintf("Can't move %s: still charging, free to drive in %d turns", direction, gs.ChargeHold+1)
+}
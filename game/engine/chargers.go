@@ -0,0 +1,165 @@
+package engine
+
+import "fmt"
+
+// ChargerLimit restricts the charger at (X, Y). Uses caps how
+// many times it charges; Cooldown is the number of moves after a charge
+// before it charges again.
+type ChargerLimit struct {
+	X        int `json:"x"`
+	Y        int `json:"y"`
+	Uses     int `json:"uses,omitempty"`     // 0 is unlimited
+	Cooldown int `json:"cooldown,omitempty"` // 0 charges every turn
+}
+
+// ChargerStatus is the live state of a limited charger
+type ChargerStatus struct {
+	Position     Position `json:"position"`
+	UsesLeft     int      `json:"uses_left"`     // -1 when uses are unlimited
+	CooldownLeft int      `json:"cooldown_left"` // Moves until it charges again
+	Depleted     bool     `json:"depleted"`      // Out of uses; it no longer charges
+	// UsedOnMove is the number of the move it last charged on, 0 before its first use
+	UsedOnMove int `json:"used_on_move,omitempty"`
+}
+
+// validateChargers checks that every limit sits on a distinct charger of
+// the layout, which must already be validated
+func validateChargers(config *GameConfig) error {
+	seen := make(map[Position]bool, len(config.Chargers))
+	for _, limit := range config.Chargers {
+		pos := Position{X: limit.X, Y: limit.Y}
+		if limit.Y < 0 || limit.Y >= len(config.Layout) || limit.X < 0 || limit.X >= len(config.Layout[limit.Y]) {
+			return fmt.Errorf("config validation: chargers entry (%d,%d) is outside the grid", limit.X, limit.Y)
+		}
+		if c := config.Layout[limit.Y][limit.X]; c != 'H' && c != 'S' && c != 'C' {
+			return fmt.Errorf("config validation: chargers entry (%d,%d) must be a home, supercharger or trickle charger, got '%c'", limit.X, limit.Y, c)
+		}
+		if seen[pos] {
+			return fmt.Errorf("config validation: chargers lists (%d,%d) more than once", limit.X, limit.Y)
+		}
+		seen[pos] = true
+		if limit.Uses < 0 || limit.Cooldown < 0 {
+			return fmt.Errorf("config validation: chargers entry (%d,%d) uses and cooldown must not be negative", limit.X, limit.Y)
+		}
+		if limit.Uses == 0 && limit.Cooldown == 0 {
+			return fmt.Errorf("config validation: chargers entry (%d,%d) must set uses or cooldown", limit.X, limit.Y)
+		}
+	}
+	return nil
+}
+
+// newChargerStatus returns fresh status for the config's limited chargers
+func newChargerStatus(config *GameConfig) []ChargerStatus {
+	if config == nil || len(config.Chargers) == 0 {
+		return nil
+	}
+	status := make([]ChargerStatus, len(config.Chargers))
+	for i, limit := range config.Chargers {
+		status[i] = ChargerStatus{Position: Position{X: limit.X, Y: limit.Y}, UsesLeft: -1}
+		if limit.Uses > 0 {
+			status[i].UsesLeft = limit.Uses
+		}
+	}
+	return status
+}
+
+// chargerAt returns the status of the limited charger at (x, y), or nil for
+// any other cell
+func (gs *GameState) chargerAt(x, y int) *ChargerStatus {
+	for i := range gs.Chargers {
+		if gs.Chargers[i].Position.X == x && gs.Chargers[i].Position.Y == y {
+			return &gs.Chargers[i]
+		}
+	}
+	return nil
+}
+
+// chargerReady reports whether the charger at (x, y) can charge this turn
+func (gs *GameState) chargerReady(x, y int) bool {
+	c := gs.chargerAt(x, y)
+	return c == nil || (!c.Depleted && c.CooldownLeft == 0)
+}
+
+// ChargedThisMove reports whether the charger at (x, y) charged on the most
+// recently recorded move. Only limited chargers can fail to charge, so any
+// other charger the player arrived on did.
+func (gs *GameState) ChargedThisMove(x, y int) bool {
+	c := gs.chargerAt(x, y)
+	return c == nil || c.UsedOnMove == gs.TotalMoves
+}
+
+// chargerDepleted reports whether the charger at (x, y) has no uses left
+func (gs *GameState) chargerDepleted(x, y int) bool {
+	c := gs.chargerAt(x, y)
+	return c != nil && c.Depleted
+}
+
+// coolingDown reports whether the player stands on a charger that will
+// charge again after a few moves
+func (gs *GameState) coolingDown() bool {
+	c := gs.chargerAt(gs.PlayerPos.X, gs.PlayerPos.Y)
+	return c != nil && !c.Depleted && c.CooldownLeft > 0
+}
+
+// tickChargers counts one move towards every charger's cooldown
+func (gs *GameState) tickChargers() {
+	for i := range gs.Chargers {
+		if gs.Chargers[i].CooldownLeft > 0 {
+			gs.Chargers[i].CooldownLeft--
+		}
+	}
+}
+
+// chargeHere charges from the charger the player stands on and uses it up,
+// or explains in Message why it can't charge
+func (gs *GameState) chargeHere(config *GameConfig, passing bool) bool {
+	x, y := gs.PlayerPos.X, gs.PlayerPos.Y
+	c := gs.chargerAt(x, y)
+	if c != nil && c.Depleted {
+		gs.Message = fmt.Sprintf("%s at (%d,%d) is depleted: no charges left", chargerName(gs.Grid[y][x].Type), x, y)
+		return false
+	}
+	if c != nil && c.CooldownLeft > 0 {
+		gs.Message = fmt.Sprintf("%s at (%d,%d) is cooling down: ready in %d moves", chargerName(gs.Grid[y][x].Type), x, y, c.CooldownLeft)
+		return false
+	}
+
+	// Driving onto a charger that fills on a wait only tops up a little,
+	// without using it up
+	if passing && gs.chargesOnWait(config) {
+		gs.Battery = min(gs.Battery+PassThroughCharge, gs.MaxBattery)
+		return true
+	}
+
+	before := gs.Battery
+	gs.addCharge(config)
+	if gs.Battery > before {
+		gs.countCharge(config)
+		gs.holdForCharge(config)
+	}
+	if c == nil {
+		return true
+	}
+	c.UsedOnMove = gs.TotalMoves + 1 // The move is recorded after it is made
+	if c.UsesLeft > 0 {
+		c.UsesLeft--
+		c.Depleted = c.UsesLeft == 0
+	}
+	for _, limit := range config.Chargers {
+		if limit.X == x && limit.Y == y {
+			c.CooldownLeft = limit.Cooldown
+		}
+	}
+	return true
+}
+
+// chargerName names a charger cell type for messages
+func chargerName(t CellType) string {
+	switch t {
+	case Home:
+		return "Home"
+	case Trickle:
+		return "Trickle charger"
+	}
+	return "Supercharger"
+}
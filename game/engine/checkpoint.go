@@ -0,0 +1,49 @@
+package engine
+
+import "fmt"
+
+// validateLives checks the lives and the battery a respawn restores
+func validateLives(config *GameConfig) error {
+	if config.Lives < 0 {
+		return fmt.Errorf("config validation: lives must not be negative, got %d", config.Lives)
+	}
+	if config.RespawnBattery < 0 || config.RespawnBattery > config.MaxBattery {
+		return fmt.Errorf("config validation: respawn_battery must be between 0 and max_battery (%d), got %d",
+			config.MaxBattery, config.RespawnBattery)
+	}
+	return nil
+}
+
+// respawnBattery returns the battery the player respawns with: the config's
+// respawn battery, or half the max battery when it sets none
+func respawnBattery(config *GameConfig) int {
+	if config.RespawnBattery > 0 {
+		return config.RespawnBattery
+	}
+	return max(1, config.MaxBattery/2)
+}
+
+// reachCheckpoint makes the checkpoint the player is on their respawn point
+func (gs *GameState) reachCheckpoint() {
+	pos := gs.PlayerPos
+	gs.LastCheckpoint = &pos
+	gs.Message = fmt.Sprintf("Checkpoint reached at (%d,%d)", pos.X, pos.Y)
+	if gs.Lives > 0 {
+		gs.Message += fmt.Sprintf(": %d lives left", gs.Lives)
+	}
+}
+
+// respawn takes a life to send the player back to their last checkpoint
+// with the respawn battery, in place of a defeat on an empty battery. It
+// reports whether it did, which needs a checkpoint and a life left.
+func (gs *GameState) respawn(config *GameConfig) bool {
+	if gs.LastCheckpoint == nil || gs.Lives <= 0 {
+		return false
+	}
+	gs.Lives--
+	gs.PlayerPos = *gs.LastCheckpoint
+	gs.Battery = respawnBattery(config)
+	gs.Message = fmt.Sprintf("Out of battery! Respawned at checkpoint (%d,%d) with %d battery, %d lives left",
+		gs.PlayerPos.X, gs.PlayerPos.Y, gs.Battery, gs.Lives)
+	return true
+}
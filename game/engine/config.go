@@ -8,105 +8,220 @@ import (
 	"strings"
 )
 
-// ValidateGameConfig validates a game configuration for correctness and playability
-func ValidateGameConfig(config *GameConfig) error {
+// ValidationProblem is one way a configuration is invalid
+type ValidationProblem struct {
+	Field   string `json:"field"`   // Offending field, e.g. "max_battery" or "messages.victory"
+	Message string `json:"message"` // What is wrong with it
+	// Position and ParkID locate problems with a single cell of the layout
+	Position *Position `json:"position,omitempty"`
+	ParkID   string    `json:"park_id,omitempty"`
+}
+
+// Error formats the problem the way ValidateGameConfig reports it
+func (p ValidationProblem) Error() string {
+	return "config validation: " + p.Message
+}
+
+// ValidateOption changes a limit ValidateGameConfig checks against
+type ValidateOption func(*validateOptions)
+
+type validateOptions struct {
+	maxGridSize int
+}
+
+// WithMaxGridSize accepts grids up to n cells across instead of
+// MaxGridSize; n is capped at GridSizeCeiling
+func WithMaxGridSize(n int) ValidateOption {
+	return func(o *validateOptions) {
+		o.maxGridSize = min(n, GridSizeCeiling)
+	}
+}
+
+// ValidateGameConfig validates a game configuration for correctness and
+// playability, returning the first problem found
+func ValidateGameConfig(config *GameConfig, opts ...ValidateOption) error {
+	if problems := ValidateGameConfigAll(config, opts...); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// ValidateGameConfigAll checks a configuration like ValidateGameConfig but
+// reports every problem rather than stopping at the first. It returns nil
+// for a valid configuration.
+func ValidateGameConfigAll(config *GameConfig, opts ...ValidateOption) []ValidationProblem {
+	o := validateOptions{maxGridSize: MaxGridSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var problems []ValidationProblem
+	add := func(field, format string, args ...any) {
+		problems = append(problems, ValidationProblem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	addErr := func(field string, err error) {
+		if err != nil {
+			add(field, "%s", strings.TrimPrefix(err.Error(), "config validation: "))
+		}
+	}
+
 	// Validate required fields
 	if config.Name == "" {
-		return fmt.Errorf("config validation: name is required")
+		add("name", "name is required")
 	}
 	if config.Description == "" {
-		return fmt.Errorf("config validation: description is required")
+		add("description", "description is required")
 	}
 
-	// Validate grid size
-	if config.GridSize < MinGridSize || config.GridSize > MaxGridSize {
-		return fmt.Errorf("config validation: grid_size must be between %d and %d, got %d", MinGridSize, MaxGridSize, config.GridSize)
+	// Validate grid size; grid_width and grid_height override grid_size per axis
+	width, height := config.Dimensions()
+	widthField, heightField := "grid_width", "grid_height"
+	if config.GridWidth == 0 {
+		widthField = "grid_size"
+	}
+	if config.GridHeight == 0 {
+		heightField = "grid_size"
+	}
+	if width < MinGridSize || width > o.maxGridSize {
+		add(widthField, "%s must be between %d and %d, got %d", widthField, MinGridSize, o.maxGridSize, width)
+	}
+	if height < MinGridSize || height > o.maxGridSize {
+		add(heightField, "%s must be between %d and %d, got %d", heightField, MinGridSize, o.maxGridSize, height)
 	}
 
 	// Validate battery settings
 	if config.MaxBattery < MinBattery || config.MaxBattery > MaxBattery {
-		return fmt.Errorf("config validation: max_battery must be between %d and %d, got %d", MinBattery, MaxBattery, config.MaxBattery)
+		add("max_battery", "max_battery must be between %d and %d, got %d", MinBattery, MaxBattery, config.MaxBattery)
 	}
 	if config.StartingBattery < MinBattery || config.StartingBattery > config.MaxBattery {
-		return fmt.Errorf("config validation: starting_battery must be between %d and max_battery (%d), got %d",
+		add("starting_battery", "starting_battery must be between %d and max_battery (%d), got %d",
 			MinBattery, config.MaxBattery, config.StartingBattery)
 	}
+	if config.ChargePerTurn < 0 || config.ChargePerTurn > config.MaxBattery {
+		add("charge_per_turn", "charge_per_turn must be between 0 and max_battery (%d), got %d",
+			config.MaxBattery, config.ChargePerTurn)
+	}
+	if config.WallCrashBatteryPenalty < 0 {
+		add("wall_crash_battery_penalty", "wall_crash_battery_penalty must not be negative, got %d", config.WallCrashBatteryPenalty)
+	}
+	if config.RevisitPenalty < 0 {
+		add("revisit_penalty", "revisit_penalty must not be negative, got %d", config.RevisitPenalty)
+	}
+	if config.WaitCost < 0 {
+		add("wait_cost", "wait_cost must not be negative, got %d", config.WaitCost)
+	}
+	if config.AutoResetSeconds < 0 {
+		add("auto_reset_seconds", "auto_reset_seconds must not be negative, got %d", config.AutoResetSeconds)
+	}
+	if config.DefaultHistoryPageSize < 0 || config.DefaultHistoryPageSize > MaxHistoryPageSize {
+		add("default_history_page_size", "default_history_page_size must be between 0 and %d (0 uses the server default), got %d",
+			MaxHistoryPageSize, config.DefaultHistoryPageSize)
+	}
+	addErr("random_events", config.RandomEvents.validate())
+	addErr("risk_thresholds", config.RiskThresholds.validate(config.MaxBattery))
 
 	// Validate layout
-	if len(config.Layout) != config.GridSize {
-		return fmt.Errorf("config validation: layout must have %d rows to match grid_size, got %d",
-			config.GridSize, len(config.Layout))
+	if len(config.Layout) != height {
+		add("layout", "layout must have %d rows to match %s, got %d",
+			height, heightField, len(config.Layout))
 	}
 
 	hasHome := false
 	parkCount := 0
 	for i, row := range config.Layout {
-		if len(row) != config.GridSize {
-			return fmt.Errorf("config validation: row %d must have %d characters to match grid_size, got %d",
-				i+1, config.GridSize, len(row))
+		if len(row) != width {
+			add("layout", "row %d must have %d characters to match %s, got %d",
+				i+1, width, widthField, len(row))
 		}
 
 		// Validate characters and count important cells
 		for j, char := range row {
 			switch char {
-			case 'R', 'S', 'W', 'B': // Valid characters
+			case 'R', 'S', 'W', 'B', 'F', 'C', 'K', 'Y', 'D': // Valid characters
 			case 'H':
 				hasHome = true
 			case 'P':
 				parkCount++
 			default:
-				return fmt.Errorf("config validation: invalid character '%c' at row %d, col %d", char, i+1, j+1)
+				add("layout", "invalid character '%c' at row %d, col %d", char, i+1, j+1)
 			}
 		}
 	}
 
 	if !hasHome {
-		return fmt.Errorf("config validation: layout must contain at least one home (H) cell")
+		add("layout", "layout must contain at least one home (H) cell")
 	}
 	if parkCount == 0 {
-		return fmt.Errorf("config validation: layout must contain at least one park (P) cell")
+		add("layout", "layout must contain at least one park (P) cell")
+	}
+	addErr("chargers", validateChargers(config))
+	addErr("charger_effects", validateChargerEffects(config))
+	addErr("fuel_amount", validateFuel(config))
+	addErr("lives", validateLives(config))
+	addErr("keys", validateLocks(config))
+	addErr("primary_home", validatePrimaryHome(config))
+	addErr("hazards", validateHazards(config))
+	addErr("scoring_mode", validateScoring(config))
+	addErr("park_order", validateParkOrder(config))
+	addErr("park_expiry", validateParkExpiry(config))
+
+	// Validate legend, in a fixed order so problems are listed consistently
+	requiredLegend := []struct{ key, value string }{
+		{"R", "road"},
+		{"H", "home"},
+		{"P", "park"},
+		{"S", "supercharger"},
+		{"W", "water"},
+		{"B", "building"},
+	}
+	for _, entry := range requiredLegend {
+		if value, ok := config.Legend[entry.key]; !ok || value != entry.value {
+			add("legend", "legend['%s'] must be '%s', got '%s'", entry.key, entry.value, value)
+		}
 	}
-
-	// Validate legend
-	requiredLegend := map[string]string{
-		"R": "road",
-		"H": "home",
-		"P": "park",
-		"S": "supercharger",
-		"W": "water",
-		"B": "building",
+	// Fuel, trickle chargers, checkpoints, keys and doors are optional, so
+	// their legend entries are too
+	if value, ok := config.Legend["F"]; ok && value != "fuel" {
+		add("legend", "legend['F'] must be 'fuel', got '%s'", value)
 	}
-	for key, expectedValue := range requiredLegend {
-		if value, ok := config.Legend[key]; !ok || value != expectedValue {
-			return fmt.Errorf("config validation: legend['%s'] must be '%s', got '%s'", key, expectedValue, value)
-		}
+	if value, ok := config.Legend["C"]; ok && value != "trickle" {
+		add("legend", "legend['C'] must be 'trickle', got '%s'", value)
+	}
+	if value, ok := config.Legend["K"]; ok && value != "checkpoint" {
+		add("legend", "legend['K'] must be 'checkpoint', got '%s'", value)
+	}
+	if value, ok := config.Legend["Y"]; ok && value != "key" {
+		add("legend", "legend['Y'] must be 'key', got '%s'", value)
+	}
+	if value, ok := config.Legend["D"]; ok && value != "door" {
+		add("legend", "legend['D'] must be 'door', got '%s'", value)
 	}
 
 	// Validate messages
 	if config.Messages.Welcome == "" {
-		return fmt.Errorf("config validation: messages.welcome is required")
+		add("messages.welcome", "messages.welcome is required")
 	}
 	if config.Messages.Victory == "" {
-		return fmt.Errorf("config validation: messages.victory is required")
+		add("messages.victory", "messages.victory is required")
 	}
 	if config.Messages.OutOfBattery == "" {
-		return fmt.Errorf("config validation: messages.out_of_battery is required")
+		add("messages.out_of_battery", "messages.out_of_battery is required")
 	}
 
 	// Validate wall crash message if feature is enabled
 	if config.WallCrashEndsGame && config.Messages.HitWall == "" {
-		return fmt.Errorf("config validation: messages.hit_wall is required when wall_crash_ends_game is true")
+		add("messages.hit_wall", "messages.hit_wall is required when wall_crash_ends_game is true")
 	}
 
 	// Validate format strings
 	if !strings.Contains(config.Messages.ParkVisited, "%d") {
-		return fmt.Errorf("config validation: messages.park_visited must contain %%d for score")
+		add("messages.park_visited", "messages.park_visited must contain %%d for score")
 	}
-	if !strings.Contains(config.Messages.Victory, "%d") {
-		return fmt.Errorf("config validation: messages.victory must contain %%d for park count")
+	if config.Messages.Victory != "" && !strings.Contains(config.Messages.Victory, "%d") {
+		add("messages.victory", "messages.victory must contain %%d for park count")
 	}
 	if config.Messages.BatteryStatus != "" && !strings.Contains(config.Messages.BatteryStatus, "%d") {
-		return fmt.Errorf("config validation: messages.battery_status must contain %%d for battery values")
+		add("messages.battery_status", "messages.battery_status must contain %%d for battery values")
 	}
 
 	// Validate winnability - check that all parks are reachable from chargers
@@ -117,11 +232,11 @@ func ValidateGameConfig(config *GameConfig) error {
 	var chargers []Point
 	var parks []Point
 
-	// Find all chargers (S and H) and parks
+	// Find all chargers (S, H and C) and parks
 	for y, row := range config.Layout {
 		for x, cell := range row {
 			switch cell {
-			case 'S', 'H':
+			case 'S', 'H', 'C':
 				chargers = append(chargers, Point{x, y})
 			case 'P':
 				parks = append(parks, Point{x, y})
@@ -129,8 +244,9 @@ func ValidateGameConfig(config *GameConfig) error {
 		}
 	}
 
-	// Check if all parks are reachable from at least one charger
-	for _, park := range parks {
+	// Check if all parks are reachable from at least one charger. Parks are
+	// numbered in layout order, as InitGameStateFromConfig does.
+	for i, park := range parks {
 		minDistToCharger := UnreachableDistance
 		for _, charger := range chargers {
 			// Manhattan distance
@@ -140,12 +256,17 @@ func ValidateGameConfig(config *GameConfig) error {
 			}
 		}
 		if minDistToCharger > config.MaxBattery {
-			return fmt.Errorf("config validation: park at (%d, %d) is unreachable - nearest charger is %d moves away but max battery is %d",
-				park.X+1, park.Y+1, minDistToCharger, config.MaxBattery)
+			problems = append(problems, ValidationProblem{
+				Field: "layout",
+				Message: fmt.Sprintf("park at (%d, %d) is unreachable - nearest charger is %d moves away but max battery is %d",
+					park.X+1, park.Y+1, minDistToCharger, config.MaxBattery),
+				Position: &Position{X: park.X, Y: park.Y},
+				ParkID:   fmt.Sprintf("park_%d", i),
+			})
 		}
 	}
 
-	return nil
+	return problems
 }
 
 // LoadGameConfig loads a game configuration from a JSON file
@@ -168,6 +289,7 @@ func LoadGameConfig(filename string) (*GameConfig, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, err
 	}
+	config.NormalizeMetadata()
 
 	// Validate the loaded configuration
 	if err := ValidateGameConfig(&config); err != nil {
@@ -201,6 +323,7 @@ func LoadConfigByName(configName string) (*GameConfig, error) {
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file '%s': %v", configName, err)
 	}
+	config.NormalizeMetadata()
 
 	// Validate the config
 	if err := ValidateGameConfig(&config); err != nil {
@@ -249,30 +372,39 @@ func InitGameStateFromConfig(config *GameConfig) *GameState {
 	}
 
 	// Create grid based on config
-	gridSize := config.GridSize
-	grid := make([][]Cell, gridSize)
+	width, height := config.Dimensions()
+	grid := make([][]Cell, height)
 	for i := range grid {
-		grid[i] = make([]Cell, gridSize)
+		grid[i] = make([]Cell, width)
 	}
 
 	parkCount := 0
-	var homePos Position
+	homePos := primaryHome(config)
 
-	for y := 0; y < gridSize; y++ {
-		for x := 0; x < gridSize; x++ {
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
 			if y < len(config.Layout) && x < len(config.Layout[y]) {
 				switch config.Layout[y][x] {
 				case 'R':
 					grid[y][x] = Cell{Type: Road}
 				case 'H':
 					grid[y][x] = Cell{Type: Home}
-					homePos = Position{X: x, Y: y}
 				case 'P':
 					parkID := fmt.Sprintf("park_%d", parkCount)
 					grid[y][x] = Cell{Type: Park, ID: parkID}
 					parkCount++
 				case 'S':
 					grid[y][x] = Cell{Type: Supercharger}
+				case 'F':
+					grid[y][x] = Cell{Type: Fuel}
+				case 'C':
+					grid[y][x] = Cell{Type: Trickle}
+				case 'K':
+					grid[y][x] = Cell{Type: Checkpoint}
+				case 'Y':
+					grid[y][x] = Cell{Type: Key}
+				case 'D':
+					grid[y][x] = Cell{Type: Door}
 				case 'W':
 					grid[y][x] = Cell{Type: Water}
 				case 'B':
@@ -282,6 +414,13 @@ func InitGameStateFromConfig(config *GameConfig) *GameState {
 		}
 	}
 
+	assignLockIDs(grid, config)
+
+	nextPark := ""
+	if len(config.ParkOrder) > 0 {
+		nextPark = config.ParkOrder[0]
+	}
+
 	return &GameState{
 		Grid:              grid,
 		PlayerPos:         homePos,
@@ -289,6 +428,12 @@ func InitGameStateFromConfig(config *GameConfig) *GameState {
 		MaxBattery:        config.MaxBattery,
 		Score:             0,
 		VisitedParks:      make(map[string]bool),
+		VisitedCells:      []Position{homePos},
+		VisitedCellCount:  1,
+		PrimaryHome:       homePos,
+		Hazards:           newHazardStatus(config),
+		ScoringMode:       config.ScoringMode,
+		NextPark:          nextPark,
 		Message:           config.Messages.Welcome,
 		GameOver:          false,
 		Victory:           false,
@@ -297,6 +442,9 @@ func InitGameStateFromConfig(config *GameConfig) *GameState {
 		TotalMoves:        0,
 		CurrentMoves:      []MoveHistoryEntry{},
 		CurrentMovesCount: 0,
+		Chargers:          newChargerStatus(config),
+		ParkExpiry:        newParkExpiryStatus(config, grid),
+		Lives:             config.Lives,
 	}
 }
 
@@ -1,8 +1,10 @@
 package engine
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -58,6 +60,24 @@ func createValidConfig() *GameConfig {
 	}
 }
 
+// createRectangularConfig returns a valid 8x5 configuration with a park in each corner
+func createRectangularConfig() *GameConfig {
+	config := createValidConfig()
+	config.GridSize = 0
+	config.GridWidth = 8
+	config.GridHeight = 5
+	config.MaxBattery = 30
+	config.StartingBattery = 30
+	config.Layout = []string{
+		"PRRRRRRP",
+		"RBBRRBBR",
+		"RRRHRRRR",
+		"RBBRRBBR",
+		"PRRRRRRP",
+	}
+	return config
+}
+
 func TestValidateGameConfig_ValidConfig(t *testing.T) {
 	config := createValidConfig()
 	err := ValidateGameConfig(config)
@@ -143,6 +163,382 @@ func TestValidateGameConfig_InvalidBattery(t *testing.T) {
 	}
 }
 
+func TestValidateGameConfig_ChargePerTurn(t *testing.T) {
+	for _, charge := range []int{-1, 11} {
+		config := createValidConfig()
+		config.MaxBattery = 10
+		config.StartingBattery = 10
+		config.ChargePerTurn = charge
+		err := ValidateGameConfig(config)
+		if err == nil || !strings.Contains(err.Error(), "charge_per_turn must be between 0 and max_battery") {
+			t.Errorf("Expected charge_per_turn validation error for %d, got: %v", charge, err)
+		}
+	}
+}
+
+func TestValidateGameConfig_RandomEvents(t *testing.T) {
+	invalid := []*RandomEventsConfig{
+		{DrainChance: -0.1, DrainAmount: 1},
+		{DrainChance: 0.6, DrainAmount: 1, SurgeChance: 0.5, SurgeAmount: 1},
+		{DrainChance: 0.2},
+		{SurgeChance: 0.2},
+	}
+	for _, events := range invalid {
+		config := createValidConfig()
+		config.RandomEvents = events
+		err := ValidateGameConfig(config)
+		if err == nil || !strings.Contains(err.Error(), "random_events") {
+			t.Errorf("Expected random_events validation error for %+v, got: %v", events, err)
+		}
+	}
+
+	config := createValidConfig()
+	config.RandomEvents = &RandomEventsConfig{DrainChance: 0.1, DrainAmount: 1, SurgeChance: 0.1, SurgeAmount: 2}
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected valid random_events, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_RiskThresholds(t *testing.T) {
+	invalid := []*RiskThresholds{
+		{CautionMargin: -1},
+		{LowBattery: -1},
+		{LowBattery: 1000},
+	}
+	for _, thresholds := range invalid {
+		config := createValidConfig()
+		config.RiskThresholds = thresholds
+		err := ValidateGameConfig(config)
+		if err == nil || !strings.Contains(err.Error(), "risk_thresholds") {
+			t.Errorf("Expected risk_thresholds validation error for %+v, got: %v", thresholds, err)
+		}
+	}
+
+	config := createValidConfig()
+	config.RiskThresholds = &RiskThresholds{CautionMargin: 4, LowBattery: config.MaxBattery}
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected valid risk_thresholds, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_Chargers(t *testing.T) {
+	invalid := [][]ChargerLimit{
+		{{X: 9, Y: 9, Uses: 1}},                            // Off the grid
+		{{X: 1, Y: 1, Uses: 1}},                            // Road, not a charger
+		{{X: 2, Y: 1}},                                     // Neither uses nor cooldown
+		{{X: 2, Y: 1, Uses: -1}},                           // Negative
+		{{X: 2, Y: 1, Uses: 1}, {X: 2, Y: 1, Cooldown: 2}}, // Duplicate
+	}
+	for _, chargers := range invalid {
+		config := createValidConfig()
+		config.Chargers = chargers
+		err := ValidateGameConfig(config)
+		if err == nil || !strings.Contains(err.Error(), "chargers") {
+			t.Errorf("Expected chargers validation error for %+v, got: %v", chargers, err)
+		}
+	}
+
+	config := createValidConfig()
+	config.Chargers = []ChargerLimit{{X: 2, Y: 1, Uses: 2, Cooldown: 3}}
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected valid chargers, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_ChargerEffects(t *testing.T) {
+	invalid := []map[CellType]ChargerEffect{
+		{Park: {Charge: 1}},                        // Not a charger
+		{Trickle: {Charge: 11}},                    // More than max battery
+		{Home: {Charge: -1}},                       // Negative
+		{Supercharger: {Charge: 2, Instant: true}}, // Both
+		{Home: {MovePenalty: -1}},                  // Negative
+	}
+	for _, effects := range invalid {
+		config := createValidConfig()
+		config.ChargerEffects = effects
+		err := ValidateGameConfig(config)
+		if err == nil || !strings.Contains(err.Error(), "charger_effects") {
+			t.Errorf("Expected charger_effects validation error for %+v, got: %v", effects, err)
+		}
+	}
+
+	config := createValidConfig()
+	config.Layout[2] = "BRCRB"
+	config.ChargerEffects = map[CellType]ChargerEffect{Home: {MovePenalty: 1}, Trickle: {Charge: 2}}
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected valid charger effects, got: %v", err)
+	}
+
+	config.Legend["C"] = "slow"
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "legend['C']") {
+		t.Errorf("Expected trickle legend validation error, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_Fuel(t *testing.T) {
+	config := createValidConfig()
+	config.Layout[2] = "BRFRB"
+	err := ValidateGameConfig(config)
+	if err == nil || !strings.Contains(err.Error(), "fuel_amount is required") {
+		t.Errorf("Expected fuel_amount to be required for a fuel cell, got: %v", err)
+	}
+
+	config.FuelAmount = 3
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected valid fuel config, got: %v", err)
+	}
+
+	config.Legend["F"] = "gas"
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "legend['F']") {
+		t.Errorf("Expected fuel legend validation error, got: %v", err)
+	}
+
+	config = createValidConfig()
+	config.FuelAmount = -1
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "fuel_amount must not be negative") {
+		t.Errorf("Expected negative fuel_amount validation error, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_NegativeAutoReset(t *testing.T) {
+	config := createValidConfig()
+	config.AutoResetSeconds = -1
+	err := ValidateGameConfig(config)
+	if err == nil {
+		t.Fatal("Expected error for negative auto_reset_seconds")
+	}
+	if !strings.Contains(err.Error(), "auto_reset_seconds must not be negative") {
+		t.Errorf("Expected auto_reset_seconds validation error, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_NegativeWallCrashPenalty(t *testing.T) {
+	config := createValidConfig()
+	config.WallCrashBatteryPenalty = -1
+	err := ValidateGameConfig(config)
+	if err == nil {
+		t.Fatal("Expected error for negative wall_crash_battery_penalty")
+	}
+	if !strings.Contains(err.Error(), "wall_crash_battery_penalty must not be negative") {
+		t.Errorf("Expected wall_crash_battery_penalty validation error, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_NegativeWaitCost(t *testing.T) {
+	config := createValidConfig()
+	config.WaitCost = -1
+	err := ValidateGameConfig(config)
+	if err == nil {
+		t.Fatal("Expected error for negative wait_cost")
+	}
+	if !strings.Contains(err.Error(), "wait_cost must not be negative") {
+		t.Errorf("Expected wait_cost validation error, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_PrimaryHome(t *testing.T) {
+	cases := []struct {
+		home Position
+		want string
+	}{
+		{Position{X: 9, Y: 1}, "primary_home (9,1) is outside the grid"},
+		{Position{X: 2, Y: 2}, "primary_home (2,2) must be a home (H) cell"},
+	}
+	for _, tc := range cases {
+		config := createValidConfig()
+		config.PrimaryHome = &tc.home
+		err := ValidateGameConfig(config)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Expected %q, got %v", tc.want, err)
+		}
+	}
+
+	config := createValidConfig()
+	config.SecondaryHomeCharge = -1
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "secondary_home_charge") {
+		t.Errorf("Expected a secondary_home_charge error, got %v", err)
+	}
+}
+
+func TestValidateGameConfig_Hazards(t *testing.T) {
+	cases := map[string]struct {
+		mutate func(*GameConfig)
+		want   string
+	}{
+		"empty path": {func(c *GameConfig) { c.Hazards = []Hazard{{}} }, "needs a path"},
+		"off grid": {func(c *GameConfig) { c.Hazards = []Hazard{{Path: []Position{{X: 7, Y: 1}}}} },
+			"(7,1) is outside the grid"},
+		"building": {func(c *GameConfig) { c.Hazards = []Hazard{{Path: []Position{{X: 0, Y: 0}}}} },
+			"(0,0) is not passable"},
+		"jump": {func(c *GameConfig) { c.Hazards = []Hazard{{Path: []Position{{X: 1, Y: 1}, {X: 1, Y: 3}}}} },
+			"jumps from (1,1) to (1,3)"},
+		"open loop": {func(c *GameConfig) {
+			c.Hazards = []Hazard{{Path: []Position{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}}}}
+		}, "loops from (1,3) back to (1,1)"},
+		"starts on home": {func(c *GameConfig) { c.Hazards = []Hazard{{Path: []Position{{X: 2, Y: 1}}}} },
+			"starts on the primary home"},
+		"pattern": {func(c *GameConfig) { c.Hazards = []Hazard{{Path: []Position{{X: 1, Y: 1}}, Pattern: "zigzag"}} },
+			"pattern must be loop or bounce"},
+		"policy":  {func(c *GameConfig) { c.HazardPolicy = "explode" }, "hazard_policy must be end_game or penalty"},
+		"penalty": {func(c *GameConfig) { c.HazardPolicy = HazardPolicyPenalty }, "hazard_penalty must be at least 1"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := createValidConfig()
+			tc.mutate(config)
+			err := ValidateGameConfig(config)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Expected %q, got %v", tc.want, err)
+			}
+		})
+	}
+
+	// A bounce needn't return to its start
+	config := createValidConfig()
+	config.Hazards = []Hazard{{Path: []Position{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 1, Y: 3}}, Pattern: PatrolBounce}}
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected a valid bouncing hazard, got %v", err)
+	}
+}
+
+func TestValidateGameConfig_Scoring(t *testing.T) {
+	config := createValidConfig()
+	config.ScoringMode = "golf"
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "scoring_mode must be standard, eco or energy") {
+		t.Errorf("Expected a scoring_mode error, got %v", err)
+	}
+
+	config = createValidConfig()
+	config.ScoringMode = ScoringEco
+	config.ChargePenalty = -2
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "charge_penalty must not be negative") {
+		t.Errorf("Expected a charge_penalty error, got %v", err)
+	}
+}
+
+func TestValidateGameConfig_Lives(t *testing.T) {
+	config := createValidConfig()
+	config.Lives = -1
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "lives must not be negative") {
+		t.Errorf("Expected a lives error, got %v", err)
+	}
+
+	config = createValidConfig()
+	config.Lives = 2
+	config.RespawnBattery = config.MaxBattery + 1
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "respawn_battery must be between") {
+		t.Errorf("Expected a respawn_battery error, got %v", err)
+	}
+
+	config = createValidConfig()
+	config.Layout[1] = "K" + config.Layout[1][1:]
+	config.Legend["K"] = "checkpoint"
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected a layout with a checkpoint to be valid, got %v", err)
+	}
+}
+
+func TestValidateGameConfig_DefaultHistoryPageSize(t *testing.T) {
+	for _, size := range []int{0, 1, MaxHistoryPageSize} {
+		config := createValidConfig()
+		config.DefaultHistoryPageSize = size
+		if err := ValidateGameConfig(config); err != nil {
+			t.Errorf("Expected default_history_page_size %d to be valid, got %v", size, err)
+		}
+	}
+	for _, size := range []int{-1, MaxHistoryPageSize + 1} {
+		config := createValidConfig()
+		config.DefaultHistoryPageSize = size
+		if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "default_history_page_size must be between 0 and 100 (0 uses the server default)") {
+			t.Errorf("Expected a default_history_page_size error for %d, got %v", size, err)
+		}
+	}
+}
+
+func TestValidateGameConfig_Locks(t *testing.T) {
+	newConfig := func() *GameConfig {
+		config := createValidConfig()
+		config.Layout[2] = "BYRDB"
+		config.Keys = []LockTile{{ID: "gate", X: 1, Y: 2}}
+		config.Doors = []LockTile{{ID: "gate", X: 3, Y: 2}}
+		return config
+	}
+	if err := ValidateGameConfig(newConfig()); err != nil {
+		t.Fatalf("Expected a valid key and door, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*GameConfig)
+		want   string
+	}{
+		{"missing key entry", func(c *GameConfig) { c.Keys = nil }, "has no key with its id"},
+		{"missing door entry", func(c *GameConfig) { c.Doors = nil }, "doors needs an entry for the 'D' cell at row 3, col 4"},
+		{"key off its cell", func(c *GameConfig) { c.Keys[0].X = 2 }, "must be on a 'Y' cell, got 'R'"},
+		{"door off the grid", func(c *GameConfig) { c.Doors[0].Y = 9 }, "is outside the grid"},
+		{"empty id", func(c *GameConfig) { c.Keys[0].ID = "" }, "needs an id"},
+		{"unmatched door", func(c *GameConfig) { c.Doors[0].ID = "vault" }, `doors entry "vault" at (3,2) has no key with its id`},
+		{"duplicate entry", func(c *GameConfig) { c.Keys = append(c.Keys, c.Keys[0]) }, "keys lists (1,2) more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := newConfig()
+			tt.modify(config)
+			if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateGameConfig_ParkOrder(t *testing.T) {
+	config := createValidConfig()
+	config.ParkOrder = []string{"park_3", "park_0"}
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected a valid park order, got %v", err)
+	}
+
+	for _, id := range []string{"park_4", "park_01", "park_-1", "park"} {
+		config.ParkOrder = []string{"park_0", id}
+		if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "park_order entry 2") {
+			t.Errorf("Expected a park_order error for %q, got %v", id, err)
+		}
+	}
+
+	config.ParkOrder = []string{"park_1", "park_1"}
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "lists park_1 more than once") {
+		t.Errorf("Expected a duplicate park_order error, got %v", err)
+	}
+
+	config = createValidConfig()
+	config.ParkOrderPolicy = "shuffle"
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "park_order_policy must be ignore or penalty") {
+		t.Errorf("Expected a park_order_policy error, got %v", err)
+	}
+	config.ParkOrderPolicy = ParkOrderPolicyPenalty
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "park_order_penalty must be at least 1") {
+		t.Errorf("Expected a park_order_penalty error, got %v", err)
+	}
+}
+
+func TestValidateGameConfig_ParkExpiry(t *testing.T) {
+	config := createValidConfig()
+	config.ParkExpiry = map[string]int{"park_0": 3, "park_3": 12}
+	if err := ValidateGameConfig(config); err != nil {
+		t.Errorf("Expected a valid park expiry, got %v", err)
+	}
+
+	config.ParkExpiry = map[string]int{"park_4": 3}
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), `park_expiry entry "park_4" is not a park`) {
+		t.Errorf("Expected an unknown park_expiry error, got %v", err)
+	}
+
+	config.ParkExpiry = map[string]int{"park_1": 0}
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "deadline of park_1 must be at least 1 move") {
+		t.Errorf("Expected a park_expiry deadline error, got %v", err)
+	}
+}
+
 func TestValidateGameConfig_LayoutSizeMismatch(t *testing.T) {
 	config := createValidConfig()
 	config.GridSize = 7
@@ -179,6 +575,66 @@ func TestValidateGameConfig_LayoutRowSizeMismatch(t *testing.T) {
 	}
 }
 
+func TestValidateGameConfig_RectangularGrid(t *testing.T) {
+	config := createRectangularConfig()
+	if err := ValidateGameConfig(config); err != nil {
+		t.Fatalf("Expected rectangular config to pass validation, got: %v", err)
+	}
+
+	width, height := config.Dimensions()
+	if width != 8 || height != 5 {
+		t.Errorf("Expected dimensions 8x5, got %dx%d", width, height)
+	}
+
+	config.Layout[1] = "RBBRRBB"
+	err := ValidateGameConfig(config)
+	if err == nil || !strings.Contains(err.Error(), "must have 8 characters to match grid_width") {
+		t.Errorf("Expected grid_width row validation error, got: %v", err)
+	}
+
+	config = createRectangularConfig()
+	config.GridHeight = 6
+	err = ValidateGameConfig(config)
+	if err == nil || !strings.Contains(err.Error(), "layout must have 6 rows to match grid_height") {
+		t.Errorf("Expected grid_height layout validation error, got: %v", err)
+	}
+
+	config = createRectangularConfig()
+	config.GridWidth = MaxGridSize + 1
+	err = ValidateGameConfig(config)
+	if err == nil || !strings.Contains(err.Error(), "grid_width must be between") {
+		t.Errorf("Expected grid_width range error, got: %v", err)
+	}
+}
+
+func TestValidateGameConfig_MaxGridSize(t *testing.T) {
+	// grid_width is checked first, so the error names the configured limit
+	// regardless of the layout
+	config := createRectangularConfig()
+	config.GridWidth = 60
+	if err := ValidateGameConfig(config); err == nil || !strings.Contains(err.Error(), "between 5 and 50, got 60") {
+		t.Errorf("Expected the default limit of 50, got: %v", err)
+	}
+	problems := ValidateGameConfigAll(config, WithMaxGridSize(60))
+	for _, p := range problems {
+		if p.Field == "grid_width" && strings.Contains(p.Message, "must be between") {
+			t.Errorf("Expected a width of 60 to be within a limit of 60, got: %v", p)
+		}
+	}
+
+	config.GridWidth = 61
+	if err := ValidateGameConfig(config, WithMaxGridSize(60)); err == nil || !strings.Contains(err.Error(), "between 5 and 60, got 61") {
+		t.Errorf("Expected a width of 61 to exceed a limit of 60, got: %v", err)
+	}
+
+	// The limit can't be raised past the ceiling
+	config.GridWidth = GridSizeCeiling + 1
+	err := ValidateGameConfig(config, WithMaxGridSize(1000))
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("between 5 and %d", GridSizeCeiling)) {
+		t.Errorf("Expected the limit to be capped at %d, got: %v", GridSizeCeiling, err)
+	}
+}
+
 func TestValidateGameConfig_InvalidCharacters(t *testing.T) {
 	config := createValidConfig()
 	config.Layout[1] = "BRXPB" // X is invalid
@@ -330,6 +786,56 @@ func TestValidateGameConfig_Winnability(t *testing.T) {
 	}
 }
 
+func TestValidateGameConfigAll(t *testing.T) {
+	if problems := ValidateGameConfigAll(createValidConfig()); problems != nil {
+		t.Errorf("Expected no problems for a valid config, got %v", problems)
+	}
+
+	config := createValidConfig()
+	config.Name = ""
+	config.MaxBattery = 3
+	config.StartingBattery = 3
+	config.Messages.Victory = ""
+	config.GridSize = 9
+	config.Layout = []string{
+		"BBBBBBBBB",
+		"BHRRRRRRB",
+		"BWWWWWWWB",
+		"BWWWWWWWB",
+		"BWWWWWWWB",
+		"BPWWWWWWB", // park_0 at (1,5) is 4 moves from home
+		"BRRRRRRRB",
+		"BWWWWWWPB", // park_1 at (7,7) is 12 moves from home
+		"BBBBBBBBB",
+	}
+
+	problems := ValidateGameConfigAll(config)
+	fields := make(map[string]bool)
+	var unreachable []string
+	for _, p := range problems {
+		fields[p.Field] = true
+		if p.ParkID != "" {
+			unreachable = append(unreachable, p.ParkID)
+			if p.Position == nil {
+				t.Errorf("Expected a position for unreachable %s", p.ParkID)
+			}
+		}
+	}
+	for _, field := range []string{"name", "messages.victory", "layout"} {
+		if !fields[field] {
+			t.Errorf("Expected a problem with %s, got %v", field, problems)
+		}
+	}
+	if !reflect.DeepEqual(unreachable, []string{"park_0", "park_1"}) {
+		t.Errorf("Expected both parks unreachable, got %v", unreachable)
+	}
+
+	// The single-error variant reports the first problem
+	if err := ValidateGameConfig(config); err == nil || err.Error() != problems[0].Error() {
+		t.Errorf("Expected the first problem %q, got %v", problems[0].Error(), err)
+	}
+}
+
 func TestLoadConfigByName(t *testing.T) {
 	// Create a temporary config file
 	tempDir := t.TempDir()
@@ -345,6 +851,7 @@ func TestLoadConfigByName(t *testing.T) {
 	configContent := `{
 		"name": "Test Config",
 		"description": "Test description",
+		"author": "  Route Planner  ",
 		"grid_size": 5,
 		"max_battery": 10,
 		"starting_battery": 8,
@@ -392,6 +899,9 @@ func TestLoadConfigByName(t *testing.T) {
 	if config.Name != "Test Config" {
 		t.Errorf("Expected config name 'Test Config', got '%s'", config.Name)
 	}
+	if config.Author != "Route Planner" {
+		t.Errorf("Expected the author trimmed like LoadGameConfig does, got %q", config.Author)
+	}
 
 	// Test loading by name with extension
 	config2, err := LoadConfigByName("test.json")
@@ -477,6 +987,25 @@ func TestLoadGameConfig(t *testing.T) {
 	}
 }
 
+func TestInitGameStateFromConfig_Rectangular(t *testing.T) {
+	state := InitGameStateFromConfig(createRectangularConfig())
+
+	if len(state.Grid) != 5 {
+		t.Fatalf("Expected 5 rows, got %d", len(state.Grid))
+	}
+	for y, row := range state.Grid {
+		if len(row) != 8 {
+			t.Errorf("Expected row %d to have 8 cells, got %d", y, len(row))
+		}
+	}
+	if state.PlayerPos != (Position{X: 3, Y: 2}) {
+		t.Errorf("Expected player at home (3,2), got %+v", state.PlayerPos)
+	}
+	if state.Grid[4][7].Type != Park {
+		t.Errorf("Expected park in the far corner, got %v", state.Grid[4][7].Type)
+	}
+}
+
 func TestInitGameStateFromConfig(t *testing.T) {
 	config := createValidConfig()
 	state := InitGameStateFromConfig(config)
@@ -12,6 +12,7 @@
 // The Engine interface defines the main contract for game operations,
 // implemented by GameEngine. GameState represents the current game state,
 // while GameConfig defines the game rules and layout loaded from JSON files.
+// SharedGame runs several players on one grid, racing for the same parks.
 //
 // Usage:
 //
@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LockTile gives the key (Y) or door (D) tile at (X, Y) its lock ID.
+// Collecting any key opens every door that shares its ID.
+type LockTile struct {
+	ID string `json:"id"`
+	X  int    `json:"x"`
+	Y  int    `json:"y"`
+}
+
+// validateLocks checks that every key and door of the layout, which must
+// already be validated, has exactly one entry, and that every door has a
+// key to open it
+func validateLocks(config *GameConfig) error {
+	keyIDs := make(map[string]bool, len(config.Keys))
+	seen := make(map[Position]bool, len(config.Keys)+len(config.Doors))
+	check := func(field string, char byte, tile LockTile) error {
+		if tile.ID == "" {
+			return fmt.Errorf("config validation: %s entry (%d,%d) needs an id", field, tile.X, tile.Y)
+		}
+		if tile.Y < 0 || tile.Y >= len(config.Layout) || tile.X < 0 || tile.X >= len(config.Layout[tile.Y]) {
+			return fmt.Errorf("config validation: %s entry %q at (%d,%d) is outside the grid", field, tile.ID, tile.X, tile.Y)
+		}
+		if c := config.Layout[tile.Y][tile.X]; c != char {
+			return fmt.Errorf("config validation: %s entry %q at (%d,%d) must be on a '%c' cell, got '%c'", field, tile.ID, tile.X, tile.Y, char, c)
+		}
+		pos := Position{X: tile.X, Y: tile.Y}
+		if seen[pos] {
+			return fmt.Errorf("config validation: %s lists (%d,%d) more than once", field, tile.X, tile.Y)
+		}
+		seen[pos] = true
+		return nil
+	}
+	for _, key := range config.Keys {
+		if err := check("keys", 'Y', key); err != nil {
+			return err
+		}
+		keyIDs[key.ID] = true
+	}
+	for _, door := range config.Doors {
+		if err := check("doors", 'D', door); err != nil {
+			return err
+		}
+		if !keyIDs[door.ID] {
+			return fmt.Errorf("config validation: doors entry %q at (%d,%d) has no key with its id", door.ID, door.X, door.Y)
+		}
+	}
+
+	for i, row := range config.Layout {
+		for j, char := range row {
+			if (char == 'Y' || char == 'D') && !seen[Position{X: j, Y: i}] {
+				field := "keys"
+				if char == 'D' {
+					field = "doors"
+				}
+				return fmt.Errorf("config validation: %s needs an entry for the '%c' cell at row %d, col %d", field, char, i+1, j+1)
+			}
+		}
+	}
+	return nil
+}
+
+// assignLockIDs sets the lock ID of every key and door cell of grid
+func assignLockIDs(grid [][]Cell, config *GameConfig) {
+	for _, tile := range append(slices.Clone(config.Keys), config.Doors...) {
+		if tile.Y >= 0 && tile.Y < len(grid) && tile.X >= 0 && tile.X < len(grid[tile.Y]) {
+			grid[tile.Y][tile.X].ID = tile.ID
+		}
+	}
+}
+
+// HasKey reports whether the player has collected a key with the lock ID
+func (gs *GameState) HasKey(id string) bool {
+	return slices.Contains(gs.CollectedKeys, id)
+}
+
+// DoorOpen reports whether (x, y) is a door the player holds the key to
+func (gs *GameState) DoorOpen(x, y int) bool {
+	if !gs.InBounds(x, y) || gs.Grid[y][x].Type != Door {
+		return false
+	}
+	return gs.HasKey(gs.Grid[y][x].ID)
+}
+
+// collectKey picks up the key the player is on, opening the doors that
+// share its ID; a key already picked up does nothing
+func (gs *GameState) collectKey(cell *Cell) {
+	if cell.Visited {
+		gs.Message = fmt.Sprintf("Key %q already collected", cell.ID)
+		return
+	}
+	cell.Visited = true
+	if !gs.HasKey(cell.ID) {
+		gs.CollectedKeys = append(gs.CollectedKeys, cell.ID)
+	}
+	gs.Message = fmt.Sprintf("Key %q collected: its doors are open", cell.ID)
+}
@@ -1,12 +1,17 @@
 package engine
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // Engine provides the main interface for game operations
 type Engine interface {
 	// Game state management
 	GetState() *GameState
 	SetState(state *GameState) error
+	Snapshot() ([]byte, error)
+	Restore(data []byte) error
 	Reset() *GameState
 	IsGameOver() bool
 	IsVictory() bool
@@ -42,9 +47,11 @@ type GameEngine struct {
 	config *GameConfig
 }
 
-// NewEngine creates a new game engine with the provided configuration
+// NewEngine creates a new game engine with the provided configuration. Grids
+// are only held to GridSizeCeiling, since configs were already checked
+// against the limit of wherever they were loaded from.
 func NewEngine(config *GameConfig) (*GameEngine, error) {
-	if err := ValidateGameConfig(config); err != nil {
+	if err := ValidateGameConfig(config, WithMaxGridSize(GridSizeCeiling)); err != nil {
 		return nil, err
 	}
 
@@ -52,6 +59,7 @@ func NewEngine(config *GameConfig) (*GameEngine, error) {
 		config: config,
 		state:  InitGameStateFromConfig(config),
 	}
+	engine.state.RandomSeed = newRandomSeed(config)
 
 	return engine, nil
 }
@@ -65,25 +73,69 @@ func NewEngineWithDefaults() *GameEngine {
 	return engine
 }
 
-// GetState returns the current game state
+// GetState returns a deep copy of the current game state, so callers can
+// read it without holding up the engine and change it without corrupting
+// the game
 func (e *GameEngine) GetState() *GameState {
-	return e.state
+	return e.state.Clone()
 }
 
-// SetState sets the game state (used for persistence loading)
+// SetState sets the game state (used for persistence loading). The engine
+// takes ownership of state, which the caller should not change afterwards.
 func (e *GameEngine) SetState(state *GameState) error {
 	if state == nil {
 		return fmt.Errorf("state cannot be nil")
 	}
+	// Saves from before charger limits start with fresh chargers
+	if state.Chargers == nil {
+		state.Chargers = newChargerStatus(e.config)
+	}
+	// Finished games saved before results were recorded
+	state.recordResult()
+	state.clearConsumedFuel()
+	// Saves from before visited cells were tracked
+	state.fillMissingVisits()
+	state.fillMissingPrimaryHome(e.config)
+	state.fillMissingHazards(e.config)
+	state.fillMissingParkExpiry(e.config)
+	state.clearExpiredParks()
+	// The next park of the park order follows from the collected parks
+	state.NextPark = state.nextOrderedPark(e.config)
+	state.ReturnHome = state.awaitingReturnHome(e.config)
 	e.state = state
 	return nil
 }
 
-// Reset resets the game to initial state
+// Clone returns an independent engine sharing the config, for simulating moves
+func (e *GameEngine) Clone() *GameEngine {
+	return &GameEngine{
+		config: e.config,
+		state:  e.state.Clone(),
+	}
+}
+
+// Reset resets the game to initial state and returns a copy of it
 func (e *GameEngine) Reset() *GameState {
+	var carried []string
+	if e.config != nil && e.config.ResetPreservesScore && !e.state.Victory {
+		for id, visited := range e.state.VisitedParks {
+			if visited {
+				carried = append(carried, id)
+			}
+		}
+		sort.Strings(carried)
+	}
+	e.reset(carried)
+	return e.GetState()
+}
+
+// reset reinitializes the state from the config, keeping the cumulative
+// history and random seed, with the carried parks already collected
+func (e *GameEngine) reset(carried []string) {
 	// Preserve cumulative history and totals across resets
 	prevHistory := e.state.MoveHistory
 	prevTotal := e.state.TotalMoves
+	prevSeed := e.state.RandomSeed
 
 	// Reinitialize core state from config
 	e.state = InitGameStateFromConfig(e.config)
@@ -91,10 +143,61 @@ func (e *GameEngine) Reset() *GameState {
 	// Restore cumulative history and totals; clear only the current segment
 	e.state.MoveHistory = prevHistory
 	e.state.TotalMoves = prevTotal
+	e.state.RandomSeed = prevSeed
 	e.state.CurrentMoves = []MoveHistoryEntry{}
 	e.state.CurrentMovesCount = 0
+	e.state.carryParks(carried, e.config)
+}
+
+// Rewind returns the game to where it stood after the first keep moves
+// since the last reset, by resetting and replaying them; keep is clamped to
+// the moves there are. The replayed moves keep their move numbers and
+// timestamps, so seeded random events recur, and the moves after them are
+// dropped from the cumulative history as well. It returns a copy of the
+// resulting state.
+func (e *GameEngine) Rewind(keep int) *GameState {
+	moves := e.state.CurrentMoves
+	keep = max(0, min(keep, len(moves)))
+	history := e.state.MoveHistory
+	base := max(len(history)-len(moves), 0)
+
+	e.reset(e.state.CarriedParks)
+	if len(moves) == 0 {
+		return e.GetState()
+	}
+	e.state.MoveHistory = history[:base:base]
+	e.state.TotalMoves = moves[0].MoveNumber - 1
+	for _, m := range moves[:keep] {
+		e.ReplayMove(m)
+	}
+	return e.GetState()
+}
+
+// ReplayMove makes a recorded move again, as Rewind does for the moves it
+// keeps: teleports go to the recorded cell and the new history entry keeps
+// the recorded timestamp
+func (e *GameEngine) ReplayMove(m MoveHistoryEntry) {
+	if m.Action == ActionTeleport {
+		if err := e.Teleport(m.ToPosition.X, m.ToPosition.Y); err != nil {
+			return
+		}
+	} else {
+		e.MoveWithMeta(m.Action, MoveMeta{Autoplay: m.Autoplay, Intent: m.Intent})
+	}
+	e.state.MoveHistory[len(e.state.MoveHistory)-1].Timestamp = m.Timestamp
+	e.state.CurrentMoves[len(e.state.CurrentMoves)-1].Timestamp = m.Timestamp
+}
 
-	return e.state
+// Surrender gives up the game, ending it as a defeat; the history is kept.
+// It returns ErrGameOver when the game has already ended.
+func (e *GameEngine) Surrender() error {
+	if e.state.GameOver {
+		return ErrGameOver
+	}
+	e.state.EndGame(GameOverSurrendered)
+	e.state.Message = "You gave up. Game Over!"
+	e.state.recordResult()
+	return nil
 }
 
 // IsGameOver returns whether the game is over
@@ -102,6 +205,11 @@ func (e *GameEngine) IsGameOver() bool {
 	return e.state.GameOver
 }
 
+// GetGameOverReason returns why the game ended, or "" while it goes on
+func (e *GameEngine) GetGameOverReason() GameOverReason {
+	return e.state.GameOverReason
+}
+
 // IsVictory returns whether the player has won
 func (e *GameEngine) IsVictory() bool {
 	return e.state.Victory
@@ -117,6 +225,12 @@ func (e *GameEngine) GetBattery() int {
 	return e.state.Battery
 }
 
+// GetChargeHold returns the turns the player must still spend on a charger
+// before driving off
+func (e *GameEngine) GetChargeHold() int {
+	return e.state.ChargeHold
+}
+
 // GetPlayerPosition returns the current player position
 func (e *GameEngine) GetPlayerPosition() Position {
 	return e.state.PlayerPos
@@ -124,21 +238,67 @@ func (e *GameEngine) GetPlayerPosition() Position {
 
 // Move attempts to move the player in the specified direction
 func (e *GameEngine) Move(direction string) bool {
+	return e.MoveWithMeta(direction, MoveMeta{})
+}
+
+// MoveWithMeta moves the player like Move and annotates the recorded history entry
+func (e *GameEngine) MoveWithMeta(direction string, meta MoveMeta) bool {
 	if e.config == nil {
 		return false
 	}
 
-	// Store previous position for history
+	// Store previous position and battery for history
 	prevPos := e.state.PlayerPos
+	prevBattery := e.state.Battery
 	success := e.state.MovePlayer(direction, e.config)
+	randomEvent := ""
+	if success && direction != ActionCharge && direction != ActionPark && direction != ActionWait {
+		randomEvent = e.state.rollRandomEvent(e.config)
+	}
+	if !e.state.GameOver {
+		e.state.moveHazards(prevPos, e.config)
+	}
 
 	// Add to history
-	e.state.AddMoveToHistory(direction, prevPos, e.state.PlayerPos, success)
+	e.state.AddMoveToHistory(direction, prevPos, e.state.PlayerPos, prevBattery, success)
+	e.state.annotateLastMove(meta)
+	if randomEvent != "" {
+		e.state.MoveHistory[len(e.state.MoveHistory)-1].RandomEvent = randomEvent
+		e.state.CurrentMoves[len(e.state.CurrentMoves)-1].RandomEvent = randomEvent
+	}
 
 	return success
 }
 
-// CanMove checks if the player can move in the specified direction
+// Park collects the park the player stands on and records the action in
+// history; it fails unless that park is still uncollected
+func (e *GameEngine) Park() bool {
+	return e.Move(ActionPark)
+}
+
+// Wait spends a turn in place and records it in history; see GameConfig.WaitCost
+func (e *GameEngine) Wait() bool {
+	return e.Move(ActionWait)
+}
+
+// Teleport places the player on a passable cell without moving step by step
+// and records it in history; see GameState.Teleport
+func (e *GameEngine) Teleport(x, y int) error {
+	if e.config == nil {
+		return fmt.Errorf("no config loaded")
+	}
+
+	prevPos := e.state.PlayerPos
+	prevBattery := e.state.Battery
+	if err := e.state.Teleport(x, y, e.config); err != nil {
+		return err
+	}
+	e.state.AddMoveToHistory(ActionTeleport, prevPos, e.state.PlayerPos, prevBattery, true)
+	return nil
+}
+
+// CanMove checks if the player can move in the specified direction; with
+// the battery reserve enforced, a move that would strand the player can't
 func (e *GameEngine) CanMove(direction string) bool {
 	if e.state.GameOver {
 		return false
@@ -159,7 +319,10 @@ func (e *GameEngine) CanMove(direction string) bool {
 		return false
 	}
 
-	return e.state.CanMoveTo(newX, newY) && e.state.Battery > 0
+	if !e.state.CanMoveTo(newX, newY) || e.state.Battery <= 0 {
+		return false
+	}
+	return e.config == nil || !e.config.EnforceBatteryReserve || !e.state.wouldStrand(direction, e.config)
 }
 
 // GetPossibleMoves returns all valid directions the player can move
@@ -176,6 +339,18 @@ func (e *GameEngine) GetPossibleMoves() []string {
 	return possible
 }
 
+// ReachableCells returns every cell the player could drive to from where they
+// are, ignoring battery
+func (e *GameEngine) ReachableCells() []Position {
+	return e.state.ReachableCells()
+}
+
+// PathTo returns the directions of a shortest drive from the player to
+// target, ignoring battery, and whether the target can be reached at all
+func (e *GameEngine) PathTo(target Position) ([]string, bool) {
+	return e.state.PathTo(target)
+}
+
 // GetConfig returns the current game configuration
 func (e *GameEngine) GetConfig() *GameConfig {
 	return e.config
@@ -183,12 +358,13 @@ func (e *GameEngine) GetConfig() *GameConfig {
 
 // SetConfig sets a new game configuration and resets the game
 func (e *GameEngine) SetConfig(config *GameConfig) error {
-	if err := ValidateGameConfig(config); err != nil {
+	if err := ValidateGameConfig(config, WithMaxGridSize(GridSizeCeiling)); err != nil {
 		return err
 	}
 
 	e.config = config
 	e.state = InitGameStateFromConfig(config)
+	e.state.RandomSeed = newRandomSeed(config)
 	return nil
 }
 
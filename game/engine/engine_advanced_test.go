@@ -67,7 +67,7 @@ func TestEngine_BulkMoveOperations(t *testing.T) {
 
 	t.Run("bulk moves stop on game over", func(t *testing.T) {
 		engine.Reset()
-		state := engine.GetState()
+		state := engine.state
 
 		// Set battery to 1 so game ends after exactly 1 successful move
 		state.Battery = 1
@@ -437,7 +437,7 @@ func TestEngine_EdgeCasesAndBoundaries(t *testing.T) {
 
 	t.Run("battery edge cases", func(t *testing.T) {
 		engine.Reset()
-		state := engine.GetState()
+		state := engine.state
 
 		// Test with exactly 1 battery
 		state.Battery = 1
@@ -651,7 +651,7 @@ func TestEngine_StateTransitions(t *testing.T) {
 		// Reset engine to ensure clean state
 		engine.Reset()
 
-		// Capture initial values separately since GetState returns direct pointer
+		// Capture initial values
 		initialPos := engine.GetPlayerPosition()
 		initialBattery := engine.GetBattery()
 		t.Logf("Initial position: (%d,%d), battery: %d", initialPos.X, initialPos.Y, initialBattery)
@@ -677,16 +677,37 @@ func TestEngine_StateTransitions(t *testing.T) {
 
 	t.Run("state immutability", func(t *testing.T) {
 		state1 := engine.GetState()
+		battery := state1.Battery
+		cell := state1.Grid[1][1]
 
-		// Modify the returned state
+		// Modify the returned state, including its grid, maps and history
 		state1.Battery = 999
+		state1.Grid[1][1] = Cell{Type: Water}
+		state1.VisitedParks["park_x"] = true
+		state1.MoveHistory = append(state1.MoveHistory[:0], MoveHistoryEntry{Action: "teleport"})
 
 		// Get state again
 		state2 := engine.GetState()
-		if state2.Battery == 999 {
-			// Note: The engine returns direct pointers, so modification affects engine state
-			// This is a known limitation - in a real implementation, GetState should return a copy
-			t.Skip("Engine state immutability not implemented - GetState returns direct pointer")
+		if state2.Battery != battery || engine.GetBattery() != battery {
+			t.Errorf("Expected battery %d to be untouched, got %d", battery, state2.Battery)
+		}
+		if state2.Grid[1][1] != cell {
+			t.Errorf("Expected grid cell %+v to be untouched, got %+v", cell, state2.Grid[1][1])
+		}
+		if state2.VisitedParks["park_x"] {
+			t.Error("Expected visited parks to be untouched")
+		}
+		for _, entry := range state2.MoveHistory {
+			if entry.Action == "teleport" {
+				t.Error("Expected move history to be untouched")
+			}
+		}
+
+		// The copy doesn't follow later moves either
+		before := engine.GetState()
+		engine.Move("left")
+		if before.PlayerPos == engine.GetPlayerPosition() {
+			t.Error("Expected an earlier copy to keep the position it was taken at")
 		}
 	})
 
@@ -1,6 +1,11 @@
 package engine
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -278,6 +283,170 @@ func TestEngine_Reset(t *testing.T) {
 	}
 }
 
+func TestEngine_ResetPreservesScore(t *testing.T) {
+	config := createTestConfig()
+	config.ResetPreservesScore = true
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// park_0 at (3,1) stays collected, while the player and battery start over
+	engine.Move("right")
+	state := engine.Reset()
+	if state.Score != 1 || !state.VisitedParks["park_0"] || !state.Grid[1][3].Visited {
+		t.Fatalf("Expected park_0 to stay collected, got score %d and parks %v", state.Score, state.VisitedParks)
+	}
+	if state.PlayerPos != (Position{X: 2, Y: 1}) || state.Battery != config.StartingBattery || state.CurrentMovesCount != 0 {
+		t.Errorf("Expected a fresh start at home, got %v with battery %d", state.PlayerPos, state.Battery)
+	}
+	if len(state.CarriedParks) != 1 || state.CarriedParks[0] != "park_0" {
+		t.Errorf("Expected park_0 to be carried, got %v", state.CarriedParks)
+	}
+
+	// A carried park can't be collected again, and rewinding keeps it
+	for _, move := range []string{"right", "down", "down"} {
+		engine.Move(move)
+	}
+	if engine.GetScore() != 2 || !engine.GetVisitedParks()["park_3"] {
+		t.Fatalf("Expected only park_3 to add to the score, got %d", engine.GetScore())
+	}
+	state = engine.Rewind(1)
+	if state.Score != 1 || !state.VisitedParks["park_0"] || state.VisitedParks["park_3"] {
+		t.Errorf("Expected the rewind to keep only the carried park, got score %d and parks %v", state.Score, state.VisitedParks)
+	}
+
+	// After a victory, a reset starts the challenge over
+	for _, move := range []string{"down", "down", "left", "left"} {
+		engine.Move(move)
+	}
+	if !engine.GetState().Victory {
+		t.Fatalf("Expected a victory, got: %s", engine.GetState().Message)
+	}
+	state = engine.Reset()
+	if state.Score != 0 || len(state.VisitedParks) != 0 || state.CarriedParks != nil {
+		t.Errorf("Expected a full reset after victory, got score %d and parks %v", state.Score, state.VisitedParks)
+	}
+}
+
+func TestEngine_MustReturnHome(t *testing.T) {
+	config := createTestConfig()
+	config.MustReturnHome = true
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// Collecting the last park at (1,3) is away from home, so no victory yet
+	for _, move := range []string{"right", "down", "down", "left", "left"} {
+		engine.Move(move)
+	}
+	state := engine.GetState()
+	if state.Score != engine.GetTotalParks() {
+		t.Fatalf("Expected every park collected, got score %d", state.Score)
+	}
+	if state.Victory || state.GameOver {
+		t.Fatalf("Expected no victory away from home, got: %s", state.Message)
+	}
+	if !state.ReturnHome || !strings.Contains(state.Message, "return home") {
+		t.Errorf("Expected a return home prompt, got %v: %s", state.ReturnHome, state.Message)
+	}
+
+	// Stepping onto home at (2,1) wins
+	for _, move := range []string{"up", "up", "right"} {
+		engine.Move(move)
+	}
+	state = engine.GetState()
+	if !state.Victory || state.GameOverReason != GameOverVictory {
+		t.Fatalf("Expected a victory on reaching home, got: %s", state.Message)
+	}
+	if state.ReturnHome {
+		t.Error("Expected the return home prompt to clear on victory")
+	}
+}
+
+func TestAssessBatteryRisk(t *testing.T) {
+	config := createTestConfig()
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	// Home and the supercharger are both 3 away from (1,3)
+	state := engine.GetState()
+	state.PlayerPos = Position{X: 1, Y: 3}
+
+	tests := []struct {
+		name       string
+		thresholds *RiskThresholds
+		battery    int
+		want       string
+	}{
+		{"empty", nil, 0, RiskCritical},
+		{"short of a charger", nil, 3, RiskDanger},
+		{"default caution margin", nil, 5, RiskCaution},
+		{"past default caution margin", nil, 6, RiskSafe},
+		{"wider caution margin", &RiskThresholds{CautionMargin: 3}, 6, RiskCaution},
+		{"past wider caution margin", &RiskThresholds{CautionMargin: 3}, 7, RiskSafe},
+		{"higher low battery", &RiskThresholds{LowBattery: 7}, 7, RiskLow},
+		{"past higher low battery", &RiskThresholds{LowBattery: 7}, 8, RiskSafe},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.RiskThresholds = tt.thresholds
+			state.Battery = tt.battery
+			risk := AssessBatteryRisk(state, config)
+			if risk.Level != tt.want {
+				t.Errorf("Expected %s at battery %d, got %+v", tt.want, tt.battery, risk)
+			}
+			if risk.ChargerDistance != 3 || risk.DangerAt != 3 {
+				t.Errorf("Expected a charger 3 away, got %+v", risk)
+			}
+		})
+	}
+
+	// Without a config the defaults apply, as in the state's battery_risk
+	state.Battery = 5
+	if risk := AssessBatteryRisk(state, nil); risk.Level != RiskCaution || risk.CautionAt != 5 || risk.LowAt != 3 {
+		t.Errorf("Expected default thresholds, got %+v", risk)
+	}
+}
+
+func TestEngine_ChargeRequiresWait(t *testing.T) {
+	config := createTestConfig()
+	config.ChargeRequiresWait = true
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// Driving onto the supercharger at (3,2) only tops up a little
+	engine.Move("right")
+	engine.Move("down")
+	state := engine.GetState()
+	if state.Battery != 6+PassThroughCharge {
+		t.Fatalf("Expected a partial charge passing through, got battery %d: %s", state.Battery, state.Message)
+	}
+	if !strings.Contains(state.Message, "wait here to charge fully") {
+		t.Errorf("Expected a hint to wait, got: %s", state.Message)
+	}
+
+	// Waiting there fills the battery
+	if !engine.Move(ActionWait) {
+		t.Fatalf("Expected the wait to succeed: %s", engine.GetState().Message)
+	}
+	if got := engine.GetBattery(); got != config.MaxBattery {
+		t.Errorf("Expected a full battery after waiting, got %d", got)
+	}
+
+	// Waiting off a charger doesn't charge
+	engine.Move("down")
+	before := engine.GetBattery()
+	engine.Move(ActionWait)
+	if got := engine.GetBattery(); got != before {
+		t.Errorf("Expected no charge waiting on a park, got %d from %d", got, before)
+	}
+}
+
 func TestEngine_ParkManagement(t *testing.T) {
 	config := createTestConfig()
 	engine, err := NewEngine(config)
@@ -344,7 +513,7 @@ func TestEngine_GameOverScenarios(t *testing.T) {
 
 	// Test battery depletion
 	// Reduce battery to minimum
-	state := engine.GetState()
+	state := engine.state
 	state.Battery = 1
 
 	// Move to use last battery and get stranded
@@ -489,7 +658,7 @@ func TestEngine_ErrorHandling(t *testing.T) {
 	}
 
 	// Test moves when game is over
-	state := engine.GetState()
+	state := engine.state
 	state.GameOver = true
 
 	success := engine.Move("right")
@@ -499,7 +668,7 @@ func TestEngine_ErrorHandling(t *testing.T) {
 
 	// Test current segment is empty after reset (global history persists)
 	engine.Reset()
-	state = engine.GetState()
+	state = engine.state
 	if len(state.CurrentMoves) != 0 || state.CurrentMovesCount != 0 {
 		t.Error("Expected no current moves immediately after reset")
 	}
@@ -515,3 +684,1487 @@ func TestEngine_ErrorHandling(t *testing.T) {
 		t.Error("Expected move to fail with empty direction")
 	}
 }
+
+func TestEngine_CloneIsIndependent(t *testing.T) {
+	config := createValidConfig()
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	original := engine.GetState()
+	pos, battery, moves := original.PlayerPos, original.Battery, len(original.MoveHistory)
+
+	clone := engine.Clone()
+	for _, dir := range clone.GetPossibleMoves() {
+		clone.Move(dir)
+	}
+
+	if original.PlayerPos != pos || original.Battery != battery || len(original.MoveHistory) != moves {
+		t.Errorf("Original engine changed by clone moves: pos=%+v battery=%d moves=%d",
+			original.PlayerPos, original.Battery, len(original.MoveHistory))
+	}
+	if clone.GetState() == original {
+		t.Error("Expected clone to have its own state")
+	}
+}
+
+func TestEngine_HistoryBatteryDeltas(t *testing.T) {
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// From home (2,1) with 8 battery: drain onto road, recharge at home, bump a building
+	engine.Move("left")
+	engine.Move("right")
+	engine.Move("up")
+
+	history := engine.GetMoveHistory()
+	want := []struct{ delta, cost int }{{-1, 1}, {3, 1}, {0, 0}}
+	for i, w := range want {
+		if history[i].BatteryDelta != w.delta || history[i].Cost != w.cost {
+			t.Errorf("Move %d (%s): expected delta %d cost %d, got delta %d cost %d",
+				i+1, history[i].Action, w.delta, w.cost, history[i].BatteryDelta, history[i].Cost)
+		}
+	}
+}
+
+func TestEngine_RequireParkAction(t *testing.T) {
+	config := createTestConfig()
+	config.RequireParkAction = true
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// Driving from home onto the park at (3,1) only reaches it
+	if !engine.Move("right") {
+		t.Fatal("Expected move onto park to succeed")
+	}
+	state := engine.state
+	if state.Score != 0 || len(state.VisitedParks) != 0 || state.Grid[1][3].Visited {
+		t.Fatalf("Expected park to stay uncollected on entry, got score %d visited %v", state.Score, state.VisitedParks)
+	}
+
+	battery := state.Battery
+	if !engine.Park() {
+		t.Fatalf("Expected park action to collect the park: %s", state.Message)
+	}
+	if state.Score != 1 || !state.VisitedParks["park_0"] || !state.Grid[1][3].Visited {
+		t.Errorf("Expected park_0 collected, got score %d visited %v", state.Score, state.VisitedParks)
+	}
+	if state.Battery != battery || state.PlayerPos != (Position{X: 3, Y: 1}) {
+		t.Errorf("Expected parking to keep position and battery, got %v battery %d", state.PlayerPos, state.Battery)
+	}
+	last := state.MoveHistory[len(state.MoveHistory)-1]
+	if last.Action != ActionPark || !last.Success || last.Cost != 0 {
+		t.Errorf("Expected successful park entry with no cost, got %+v", last)
+	}
+
+	if engine.Park() {
+		t.Error("Expected parking on a collected park to fail")
+	}
+}
+
+func TestEngine_ParkWithEmptyBattery(t *testing.T) {
+	config := createTestConfig()
+	config.RequireParkAction = true
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	state := engine.state
+	state.PlayerPos = Position{X: 1, Y: 3}
+	state.Battery = 1
+
+	// The last charge reaches the park; it can still be collected before stranding
+	engine.Move("right")
+	if state.GameOver {
+		t.Fatalf("Expected game to wait for the park action, got %q", state.GameOverReason)
+	}
+	if !engine.Park() {
+		t.Fatalf("Expected park action with empty battery to succeed: %s", state.Message)
+	}
+	if state.Score != 1 {
+		t.Errorf("Expected score 1, got %d", state.Score)
+	}
+	if !state.GameOver || state.GameOverReason != GameOverStranded {
+		t.Errorf("Expected stranded after parking, got over=%v reason=%q", state.GameOver, state.GameOverReason)
+	}
+}
+
+func TestEngine_ParkOffPark(t *testing.T) {
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if engine.Park() {
+		t.Error("Expected parking at home to fail")
+	}
+	if engine.GetScore() != 0 || engine.IsGameOver() {
+		t.Error("Failed park action should not change the game")
+	}
+}
+
+func TestEngine_Teleport(t *testing.T) {
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.state.Battery = 4
+
+	// Landing on the supercharger at (3,2) charges without spending battery
+	if err := engine.Teleport(3, 2); err != nil {
+		t.Fatalf("Teleport failed: %v", err)
+	}
+	if engine.GetPlayerPosition() != (Position{X: 3, Y: 2}) || engine.GetBattery() != 10 {
+		t.Errorf("Expected full battery at (3,2), got %+v battery=%d", engine.GetPlayerPosition(), engine.GetBattery())
+	}
+	last := engine.GetLastMove()
+	if last == nil || last.Action != ActionTeleport || last.Cost != 0 || last.FromPosition != (Position{X: 2, Y: 1}) {
+		t.Errorf("Expected a teleport history entry from home, got %+v", last)
+	}
+
+	// Landing on a park collects it
+	if err := engine.Teleport(1, 3); err != nil {
+		t.Fatalf("Teleport failed: %v", err)
+	}
+	if engine.GetScore() != 1 {
+		t.Errorf("Expected park at (1,3) to be collected, got score %d", engine.GetScore())
+	}
+
+	for _, target := range []Position{{X: 2, Y: 2}, {X: 0, Y: 0}, {X: 9, Y: 1}, {X: -1, Y: 2}} {
+		if err := engine.Teleport(target.X, target.Y); !errors.Is(err, ErrImpassable) {
+			t.Errorf("Teleport to %+v: expected ErrImpassable, got %v", target, err)
+		}
+	}
+	if engine.GetPlayerPosition() != (Position{X: 1, Y: 3}) || len(engine.GetMoveHistory()) != 2 {
+		t.Error("Rejected teleports should not move the player or add history")
+	}
+}
+
+func TestBatteryPercent(t *testing.T) {
+	tests := []struct {
+		battery, max, want int
+	}{
+		{149, 200, 75}, // 74.5% rounds up
+		{148, 200, 74},
+		{75, 100, 75},
+		{1, 3, 33},
+		{2, 3, 67},
+		{10, 10, 100},
+		{0, 10, 0},
+		{5, 0, 0}, // No max battery
+	}
+	for _, tt := range tests {
+		if got := BatteryPercent(tt.battery, tt.max); got != tt.want {
+			t.Errorf("BatteryPercent(%d, %d) = %d, want %d", tt.battery, tt.max, got, tt.want)
+		}
+	}
+}
+
+// randomEventTrace plays moves and records each move's battery and random event
+func randomEventTrace(engine *GameEngine, moves []string) []string {
+	var trace []string
+	for _, move := range moves {
+		engine.Move(move)
+		last := engine.GetLastMove()
+		trace = append(trace, fmt.Sprintf("%s battery=%d event=%q", move, last.Battery, last.RandomEvent))
+	}
+	return trace
+}
+
+func TestEngine_RandomEventsDeterministic(t *testing.T) {
+	config := createTestConfig()
+	config.RandomEvents = &RandomEventsConfig{DrainChance: 0.3, DrainAmount: 1, SurgeChance: 0.3, SurgeAmount: 2, Seed: 42}
+
+	var moves []string
+	for i := 0; i < 5; i++ {
+		moves = append(moves, "left", "down", "up", "right")
+	}
+
+	a, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	b, _ := NewEngine(config)
+	traceA := randomEventTrace(a, moves)
+	traceB := randomEventTrace(b, moves)
+	if !reflect.DeepEqual(traceA, traceB) {
+		t.Fatalf("Same seed and moves gave different games:\n%v\n%v", traceA, traceB)
+	}
+
+	events := 0
+	for _, entry := range a.GetMoveHistory() {
+		if entry.RandomEvent != "" {
+			events++
+		}
+	}
+	if events == 0 {
+		t.Errorf("Expected some random events in %d moves", len(moves))
+	}
+
+	// A saved and reloaded state continues with the same events
+	c, _ := NewEngine(config)
+	half := len(moves) / 2
+	randomEventTrace(c, moves[:half])
+	data, err := json.Marshal(c.GetState())
+	if err != nil {
+		t.Fatalf("Failed to marshal state: %v", err)
+	}
+	var saved GameState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+	reloaded := &GameEngine{config: config}
+	reloaded.SetState(&saved)
+	if rest := randomEventTrace(reloaded, moves[half:]); !reflect.DeepEqual(rest, traceA[half:]) {
+		t.Errorf("Reloaded game diverged:\n%v\n%v", rest, traceA[half:])
+	}
+}
+
+func TestEngine_SingleUseCharger(t *testing.T) {
+	config := createTestConfig()
+	config.Chargers = []ChargerLimit{{X: 3, Y: 2, Uses: 1}}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// From home (2,1) through the park at (3,1) onto the supercharger at (3,2)
+	engine.Move("right")
+	engine.Move("down")
+	state := engine.GetState()
+	if state.Battery != config.MaxBattery {
+		t.Fatalf("Expected the first visit to charge, got battery %d", state.Battery)
+	}
+	if c := state.Chargers[0]; c.UsesLeft != 0 || !c.Depleted || c.UsedOnMove != 2 {
+		t.Errorf("Expected the charger used up on move 2, got %+v", c)
+	}
+
+	// Charger state survives a save and reload
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Failed to marshal state: %v", err)
+	}
+	var saved GameState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+	reloaded := &GameEngine{config: config}
+	reloaded.SetState(&saved)
+
+	reloaded.Move("up")
+	reloaded.Move("down")
+	state = reloaded.GetState()
+	if state.Battery != config.MaxBattery-2 {
+		t.Errorf("Expected no recharge on the second visit, got battery %d", state.Battery)
+	}
+	if !strings.Contains(state.Message, "depleted") || state.ChargedThisMove(3, 2) {
+		t.Errorf("Expected a depleted charger, got message %q", state.Message)
+	}
+	if reloaded.Move(ActionCharge) {
+		t.Error("Expected charging on a depleted charger to fail")
+	}
+
+	// A reset restores every charge
+	if c := reloaded.Reset().Chargers[0]; c.Depleted || c.UsesLeft != 1 {
+		t.Errorf("Expected reset to restore the charger, got %+v", c)
+	}
+}
+
+func TestEngine_GameResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(*GameConfig, *GameState)
+		moves   []string
+		outcome GameOutcome
+		reason  ResultReason
+		used    int
+	}{
+		{
+			name:    "all parks",
+			moves:   []string{"right", "down", "down", "left", "left"},
+			outcome: OutcomeVictory,
+			reason:  ResultAllParks,
+			used:    5,
+		},
+		{
+			name:    "out of battery",
+			setup:   func(_ *GameConfig, s *GameState) { s.Battery = 0 },
+			moves:   []string{"right"},
+			outcome: OutcomeDefeat,
+			reason:  ResultOutOfBattery,
+		},
+		{
+			name: "stranded",
+			setup: func(_ *GameConfig, s *GameState) {
+				s.PlayerPos = Position{X: 1, Y: 3}
+				s.Battery = 1
+			},
+			moves:   []string{"right"},
+			outcome: OutcomeDefeat,
+			reason:  ResultStranded,
+			used:    1,
+		},
+		{
+			name:    "wall crash",
+			setup:   func(c *GameConfig, _ *GameState) { c.WallCrashEndsGame = true },
+			moves:   []string{"up"},
+			outcome: OutcomeDefeat,
+			reason:  ResultWallCrash,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := NewEngine(createTestConfig())
+			if err != nil {
+				t.Fatalf("Failed to create engine: %v", err)
+			}
+			if tt.setup != nil {
+				tt.setup(engine.GetConfig(), engine.state)
+			}
+			for _, move := range tt.moves {
+				if engine.state.Result != nil {
+					t.Fatalf("Result set before the game ended: %+v", engine.state.Result)
+				}
+				engine.Move(move)
+			}
+
+			state := engine.state
+			r := state.Result
+			if r == nil {
+				t.Fatalf("Expected a result, game over=%v reason=%q", state.GameOver, state.GameOverReason)
+			}
+			if r.Outcome != tt.outcome || r.Reason != tt.reason {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.outcome, tt.reason, r.Outcome, r.Reason)
+			}
+			if r.MovesUsed != tt.used || r.ElapsedMoves != len(tt.moves) {
+				t.Errorf("Expected %d used of %d moves, got %d of %d", tt.used, len(tt.moves), r.MovesUsed, r.ElapsedMoves)
+			}
+			if r.FinalScore != state.Score || r.ParksCollected != len(state.VisitedParks) || r.TotalParks != 4 {
+				t.Errorf("Expected score %d and %d/4 parks, got %+v", state.Score, len(state.VisitedParks), r)
+			}
+
+			if engine.Reset().Result != nil {
+				t.Error("Expected reset to clear the result")
+			}
+		})
+	}
+}
+
+func TestGameState_RecordResultReasons(t *testing.T) {
+	// Move limits and manual ends have no move that triggers them in the engine
+	for reason, want := range map[GameOverReason]ResultReason{
+		GameOverMaxMoves: ResultMoveLimit,
+		GameOverManual:   ResultManual,
+	} {
+		state, _ := createTestGameState()
+		state.EndGame(reason)
+		state.recordResult()
+		if state.Result == nil || state.Result.Reason != want || state.Result.Outcome != OutcomeDefeat {
+			t.Errorf("%s: expected defeat/%s, got %+v", reason, want, state.Result)
+		}
+	}
+}
+
+func TestEngine_Surrender(t *testing.T) {
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.Move("right")
+
+	if err := engine.Surrender(); err != nil {
+		t.Fatalf("Surrender failed: %v", err)
+	}
+	if !engine.IsGameOver() || engine.IsVictory() || engine.GetGameOverReason() != GameOverSurrendered {
+		t.Errorf("Expected a lost game ended by surrender, got over=%v victory=%v reason=%q",
+			engine.IsGameOver(), engine.IsVictory(), engine.GetGameOverReason())
+	}
+	state := engine.GetState()
+	if state.Result == nil || state.Result.Outcome != OutcomeDefeat || state.Result.Reason != ResultSurrendered || state.Result.MovesUsed != 1 {
+		t.Errorf("Expected a surrendered defeat after 1 move, got %+v", state.Result)
+	}
+	if len(state.MoveHistory) != 1 {
+		t.Errorf("Expected surrender to keep the history, got %d entries", len(state.MoveHistory))
+	}
+
+	if err := engine.Surrender(); !errors.Is(err, ErrGameOver) {
+		t.Errorf("Expected ErrGameOver surrendering a finished game, got %v", err)
+	}
+	if engine.Reset(); engine.IsGameOver() || engine.GetGameOverReason() != "" {
+		t.Error("Expected reset to start a new game after surrender")
+	}
+}
+
+func TestEngine_ReachableCells(t *testing.T) {
+	config := createTestConfig()
+	config.GridSize = 7
+	config.Layout = []string{
+		"BBBBBBB",
+		"BHRRRRB",
+		"BWWWWRB",
+		"BPWRRRB", // park_0 at (1,3) is walled in by water
+		"BWWRWWB",
+		"BPRRBBB", // park_1 at (1,5) is at the end of a winding road
+		"BBBBBBB",
+	}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	walled, winding := Position{X: 1, Y: 3}, Position{X: 1, Y: 5}
+	home := engine.GetPlayerPosition()
+	if ManhattanDistance(home, walled) >= ManhattanDistance(home, winding) {
+		t.Fatal("Expected the walled-in park to look closer by Manhattan distance")
+	}
+
+	reachable := make(map[Position]bool)
+	for _, pos := range engine.ReachableCells() {
+		reachable[pos] = true
+	}
+	if reachable[walled] {
+		t.Error("Walled-in park should not be reachable")
+	}
+	if !reachable[winding] {
+		t.Error("Park at the end of the winding road should be reachable")
+	}
+	if len(reachable) != 13 {
+		t.Errorf("Expected 13 reachable cells, got %d", len(reachable))
+	}
+	for pos := range reachable {
+		if !engine.GetState().CanMoveTo(pos.X, pos.Y) {
+			t.Errorf("Reachable cell %v is not passable", pos)
+		}
+	}
+}
+
+func TestEngine_PathTo(t *testing.T) {
+	config := createTestConfig()
+	config.GridSize = 7
+	config.Layout = []string{
+		"BBBBBBB",
+		"BHRRRRB",
+		"BWWWWRB",
+		"BPWRRRB",
+		"BWWRWWB",
+		"BPRRBBB",
+		"BBBBBBB",
+	}
+	config.MaxBattery = 20
+	config.StartingBattery = 20
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	path, ok := engine.PathTo(Position{X: 1, Y: 5})
+	if !ok {
+		t.Fatal("Expected the park at the end of the winding road to be reachable")
+	}
+	want := []string{"right", "right", "right", "right", "down", "down", "left", "left", "down", "down", "left", "left"}
+	if !reflect.DeepEqual(path, want) {
+		t.Errorf("Expected path %v, got %v", want, path)
+	}
+
+	// Following the path arrives at the target
+	state := engine.GetState()
+	for _, dir := range path {
+		if !state.MovePlayer(dir, config) {
+			t.Fatalf("Expected %s to succeed: %s", dir, state.Message)
+		}
+	}
+	if state.PlayerPos != (Position{X: 1, Y: 5}) {
+		t.Errorf("Expected the path to end on the target, got %v", state.PlayerPos)
+	}
+
+	for _, target := range []Position{{X: 1, Y: 3}, {X: 0, Y: 0}, {X: 2, Y: 2}, {X: 9, Y: 1}} {
+		if path, ok := engine.PathTo(target); ok {
+			t.Errorf("Expected %v to be unreachable, got path %v", target, path)
+		}
+	}
+	if path, ok := engine.PathTo(engine.GetPlayerPosition()); !ok || len(path) != 0 {
+		t.Errorf("Expected an empty path to the player's own cell, got %v, %v", path, ok)
+	}
+}
+
+func TestEngine_NoRandomEventsByDefault(t *testing.T) {
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if engine.GetState().RandomSeed != 0 {
+		t.Errorf("Expected no seed without random events, got %d", engine.GetState().RandomSeed)
+	}
+	for _, move := range []string{"left", "down", "up", "right"} {
+		engine.Move(move)
+		if last := engine.GetLastMove(); last.RandomEvent != "" {
+			t.Errorf("Unexpected random event %q", last.RandomEvent)
+		}
+	}
+}
+
+func TestEngine_FuelPickup(t *testing.T) {
+	config := createTestConfig()
+	config.Layout[3] = "BFPPB" // Fuel at (1,3), below the road at (1,2)
+	config.FuelAmount = 3
+	config.StartingBattery = 5
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// From home (2,1) left along the road and down onto the fuel
+	engine.Move("left")
+	engine.Move("down")
+	engine.Move("down")
+	state := engine.GetState()
+	if state.Battery != 5 {
+		t.Fatalf("Expected 2 + 3 fuel battery, got %d", state.Battery)
+	}
+	if state.Grid[3][1].Type != Road {
+		t.Errorf("Expected the fuel tile to become road, got %s", state.Grid[3][1].Type)
+	}
+	pickup := state.FuelPickedUpThisMove()
+	if pickup == nil || pickup.Position != (Position{X: 1, Y: 3}) || pickup.Amount != 3 || pickup.OnMove != 3 {
+		t.Fatalf("Expected a 3 battery pickup at (1,3) on move 3, got %+v", pickup)
+	}
+
+	// Consumed fuel survives a save and reload
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Failed to marshal state: %v", err)
+	}
+	var saved GameState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+	reloaded := &GameEngine{config: config}
+	reloaded.SetState(&saved)
+
+	// A second visit yields nothing
+	reloaded.Move("up")
+	reloaded.Move("down")
+	state = reloaded.GetState()
+	if state.Battery != 3 {
+		t.Errorf("Expected no fuel on the second visit, got battery %d", state.Battery)
+	}
+	if state.FuelPickedUpThisMove() != nil || len(state.ConsumedFuel) != 1 {
+		t.Errorf("Expected only the first pickup, got %+v", state.ConsumedFuel)
+	}
+
+	// A reset restores the fuel
+	state = reloaded.Reset()
+	if state.Grid[3][1].Type != Fuel || state.ConsumedFuel != nil {
+		t.Errorf("Expected reset to restore the fuel, got %s and %+v", state.Grid[3][1].Type, state.ConsumedFuel)
+	}
+}
+
+func TestEngine_FuelCappedAtMaxBattery(t *testing.T) {
+	config := createTestConfig()
+	config.Layout[3] = "BFPPB"
+	config.FuelAmount = 10
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	engine.Move("left")
+	engine.Move("down")
+	engine.Move("down")
+	state := engine.GetState()
+	if state.Battery != config.MaxBattery || state.ConsumedFuel[0].Amount != 5 {
+		t.Errorf("Expected the fuel capped at max battery, got battery %d and %+v", state.Battery, state.ConsumedFuel)
+	}
+}
+
+func TestEngine_SnapshotRoundTrip(t *testing.T) {
+	config := createTestConfig()
+	config.Layout[3] = "BFPPB"
+	config.FuelAmount = 2
+	config.Chargers = []ChargerLimit{{X: 3, Y: 2, Uses: 1}}
+	config.RandomEvents = &RandomEventsConfig{SurgeChance: 0.5, SurgeAmount: 1, Seed: 7}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// Collect a park, use the limited charger and the fuel, and hit a wall
+	for _, move := range []string{"right", "down", "down", "left", "left", "up", "up"} {
+		engine.Move(move)
+	}
+	engine.Reset()
+	engine.MoveWithMeta("left", MoveMeta{Intent: "explore"})
+	engine.Move("down")
+	engine.Move("down")
+
+	data, err := engine.Snapshot()
+	if err != nil {
+		t.Fatalf("Failed to snapshot: %v", err)
+	}
+	if !strings.Contains(string(data), `"version":1`) {
+		t.Errorf("Expected a versioned snapshot, got %s", data)
+	}
+
+	restored := &GameEngine{config: config, state: InitGameStateFromConfig(config)}
+	if err := restored.Restore(data); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+	if !reflect.DeepEqual(restored.GetState(), engine.GetState()) {
+		t.Errorf("Expected the restored state to match\ngot:  %+v\nwant: %+v", restored.GetState(), engine.GetState())
+	}
+
+	// The restored engine plays on independently
+	restored.Move("up")
+	if engine.GetState().TotalMoves == restored.GetState().TotalMoves {
+		t.Error("Expected the restored engine not to share state with the original")
+	}
+}
+
+func TestEngine_RestoreRejectsBadSnapshots(t *testing.T) {
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	before := engine.state
+
+	if err := engine.Restore([]byte(`{"version":99,"state":{}}`)); !errors.Is(err, ErrSnapshotVersion) {
+		t.Errorf("Expected ErrSnapshotVersion for a future version, got: %v", err)
+	}
+	if err := engine.Restore([]byte(`{"state":{}}`)); !errors.Is(err, ErrSnapshotVersion) {
+		t.Errorf("Expected ErrSnapshotVersion for a missing version, got: %v", err)
+	}
+	if err := engine.Restore([]byte(`{"version":1}`)); err == nil {
+		t.Error("Expected an error for a snapshot without state")
+	}
+	if err := engine.Restore([]byte(`not json`)); err == nil {
+		t.Error("Expected an error for malformed JSON")
+	}
+	if engine.state != before {
+		t.Error("Expected a failed restore to leave the state alone")
+	}
+}
+
+func TestEngine_Wait(t *testing.T) {
+	// Waiting is free by default and leaves the player in place
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	start := engine.GetPlayerPosition()
+	if !engine.Wait() {
+		t.Fatalf("Expected wait to succeed: %s", engine.GetState().Message)
+	}
+	last := engine.GetLastMove()
+	if engine.GetPlayerPosition() != start || engine.GetBattery() != 8 {
+		t.Errorf("Expected to stay at %v with battery 8, got %v with %d", start, engine.GetPlayerPosition(), engine.GetBattery())
+	}
+	if last.Action != ActionWait || last.Cost != 0 || last.FromPosition != last.ToPosition || engine.GetState().TotalMoves != 1 {
+		t.Errorf("Expected a free wait history entry, got %+v", last)
+	}
+
+	config := createTestConfig()
+	config.WaitCost = 2
+	engine, err = NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.Move("left")
+	engine.Wait()
+	last = engine.GetLastMove()
+	if engine.GetBattery() != 5 || last.Cost != 2 || last.BatteryDelta != -2 {
+		t.Errorf("Expected a 2 battery wait, got battery %d and %+v", engine.GetBattery(), last)
+	}
+	if !strings.Contains(engine.GetState().Message, "cost 2 battery") {
+		t.Errorf("Expected the message to mention the cost, got %q", engine.GetState().Message)
+	}
+}
+
+func TestEngine_PrimaryHome(t *testing.T) {
+	config := createTestConfig()
+	config.Layout = []string{
+		"BBBBB",
+		"BHRHB",
+		"BRRSB",
+		"BPPPB",
+		"BBBBB",
+	}
+
+	// Without a primary home the game starts at the last home
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if state := engine.state; state.PlayerPos != (Position{X: 3, Y: 1}) || state.PrimaryHome != state.PlayerPos {
+		t.Errorf("Expected to start at the last home (3,1), got %v with primary home %v", state.PlayerPos, state.PrimaryHome)
+	}
+
+	config.PrimaryHome = &Position{X: 1, Y: 1}
+	config.SecondaryHomeCharge = 3
+	config.StartingBattery = 4
+	engine, err = NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	state := engine.state
+	if state.PlayerPos != (Position{X: 1, Y: 1}) || state.PrimaryHome != state.PlayerPos {
+		t.Fatalf("Expected to start at the primary home (1,1), got %v with primary home %v", state.PlayerPos, state.PrimaryHome)
+	}
+
+	// The secondary home adds its own charge; the primary one fills up
+	engine.Move("right")
+	engine.Move("right")
+	if engine.GetBattery() != 5 || !strings.Contains(state.Message, "Secondary home") {
+		t.Errorf("Expected the secondary home to charge 2+3, got %d: %s", engine.GetBattery(), state.Message)
+	}
+	engine.Move("left")
+	engine.Move("left")
+	if engine.GetBattery() != 10 {
+		t.Errorf("Expected the primary home to fill the battery, got %d", engine.GetBattery())
+	}
+
+	if state := engine.Reset(); state.PlayerPos != (Position{X: 1, Y: 1}) {
+		t.Errorf("Expected reset to return to the primary home, got %v", state.PlayerPos)
+	}
+
+	// States saved before the primary home was recorded get it back
+	saved := engine.state.Clone()
+	saved.PrimaryHome = Position{}
+	if err := engine.SetState(saved); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+	if engine.state.PrimaryHome != (Position{X: 1, Y: 1}) {
+		t.Errorf("Expected the primary home to be filled in, got %v", engine.state.PrimaryHome)
+	}
+}
+
+func TestEngine_HazardStepsOntoPlayer(t *testing.T) {
+	config := createTestConfig()
+	config.Hazards = []Hazard{{Path: []Position{{X: 1, Y: 3}, {X: 1, Y: 2}, {X: 1, Y: 1}}, Pattern: PatrolBounce}}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	engine.Move("left")
+	state := engine.state
+	if state.GameOver || state.Hazards[0].Position != (Position{X: 1, Y: 2}) {
+		t.Fatalf("Expected the hazard one cell away at (1,2), got %+v", state.Hazards)
+	}
+
+	// The hazard walks onto the waiting player
+	engine.Wait()
+	if !state.GameOver || state.GameOverReason != GameOverHazard || state.Result.Reason != ResultHazard {
+		t.Fatalf("Expected the hazard to end the game, got %q: %s", state.GameOverReason, state.Message)
+	}
+	if last := engine.GetLastMove(); !last.HazardHit {
+		t.Errorf("Expected the wait to record the hazard hit, got %+v", last)
+	}
+
+	// Reset sends it back to the start of its patrol
+	if state := engine.Reset(); state.Hazards[0] != (HazardStatus{Position: Position{X: 1, Y: 3}}) {
+		t.Errorf("Expected reset to restart the patrol, got %+v", state.Hazards)
+	}
+}
+
+func TestEngine_PlayerRunsIntoHazard(t *testing.T) {
+	config := createTestConfig()
+	config.Hazards = []Hazard{
+		{Path: []Position{{X: 1, Y: 1}}},               // Stands still
+		{Path: []Position{{X: 1, Y: 3}, {X: 2, Y: 3}}}, // Paces the bottom row
+	}
+	config.HazardPolicy = HazardPolicyPenalty
+	config.HazardPenalty = 2
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// Driving into the hazard costs the move and the penalty
+	engine.Move("left")
+	state := engine.GetState()
+	last := engine.GetLastMove()
+	if state.GameOver || engine.GetBattery() != 5 || !last.HazardHit || last.Cost != 1 || last.BatteryDelta != -3 {
+		t.Fatalf("Expected a 2 battery hazard penalty, got battery %d and %+v", engine.GetBattery(), last)
+	}
+	if !strings.Contains(state.Message, "Hit by a hazard at (1,1)") {
+		t.Errorf("Expected a hazard message, got %q", state.Message)
+	}
+
+	// Sharing the cell with a hazard that stays put is no further hit
+	engine.Wait()
+	if last := engine.GetLastMove(); last.HazardHit || engine.GetBattery() != 5 {
+		t.Errorf("Expected no hit while staying on the hazard, got battery %d and %+v", engine.GetBattery(), last)
+	}
+
+	// Snapshots carry the patrols, so restoring replays the same encounters
+	data, err := engine.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	engine.Move("down")
+	if err := engine.Restore(data); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if got := engine.GetState().Hazards[1]; got != (HazardStatus{Position: Position{X: 1, Y: 3}}) {
+		t.Errorf("Expected the restored pacing hazard at (1,3), got %+v", got)
+	}
+}
+
+func TestEngine_WaitStrands(t *testing.T) {
+	config := createTestConfig()
+	config.WaitCost = 3
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	state := engine.state
+	state.PlayerPos = Position{X: 1, Y: 1}
+	state.Battery = 2
+
+	// The wait takes what is left and ends the game away from a charger
+	if !engine.Wait() {
+		t.Fatalf("Expected wait to succeed: %s", state.Message)
+	}
+	if state.Battery != 0 || !state.GameOver || state.GameOverReason != GameOverStranded {
+		t.Fatalf("Expected to be stranded with an empty battery, got battery %d and %q", state.Battery, state.GameOverReason)
+	}
+	if last := engine.GetLastMove(); last.Cost != 2 {
+		t.Errorf("Expected the wait to cost the 2 battery left, got %d", last.Cost)
+	}
+	if engine.Wait() {
+		t.Error("Expected wait to fail once the game is over")
+	}
+}
+
+func TestEngine_RevisitPenalty(t *testing.T) {
+	// Without a penalty revisits cost the usual single battery
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.Move("left")
+	engine.Move("down")
+	engine.Move("up")
+	if battery := engine.GetBattery(); battery != 5 {
+		t.Errorf("Expected no revisit penalty by default, got battery %d", battery)
+	}
+
+	config := createTestConfig()
+	config.RevisitPenalty = 2
+	engine, err = NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	if state := engine.GetState(); state.VisitedCellCount != 1 {
+		t.Errorf("Expected the starting home to count as visited, got %d", state.VisitedCellCount)
+	}
+
+	// First visits are free
+	engine.Move("left")
+	engine.Move("down")
+	state := engine.GetState()
+	if state.Battery != 6 || state.VisitedCellCount != 3 {
+		t.Fatalf("Expected battery 6 and 3 visited cells, got %d and %d", state.Battery, state.VisitedCellCount)
+	}
+
+	// Revisits cost extra and the cost is recorded
+	engine.Move("up")
+	last := engine.GetLastMove()
+	if engine.GetBattery() != 3 || last.RevisitPenalty != 2 || last.Cost != 3 {
+		t.Errorf("Expected a 2 battery revisit penalty, got battery %d and %+v", engine.GetBattery(), last)
+	}
+	if !strings.Contains(engine.GetState().Message, "revisit cost 2 battery") {
+		t.Errorf("Expected the message to mention the penalty, got %q", engine.GetState().Message)
+	}
+
+	// Charging still applies on a revisited charger
+	engine.Move("right")
+	if battery := engine.GetBattery(); battery != config.MaxBattery {
+		t.Errorf("Expected home to charge after the penalty, got battery %d", battery)
+	}
+
+	// Visited cells survive a save and reload
+	data, err := json.Marshal(engine.GetState())
+	if err != nil {
+		t.Fatalf("Failed to marshal state: %v", err)
+	}
+	var saved GameState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+	reloaded := &GameEngine{config: config}
+	reloaded.SetState(&saved)
+	reloaded.Move("left")
+	if battery := reloaded.GetBattery(); battery != config.MaxBattery-3 {
+		t.Errorf("Expected the reloaded engine to remember visits, got battery %d", battery)
+	}
+
+	// A reset forgets every visit
+	if state := reloaded.Reset(); state.VisitedCellCount != 1 {
+		t.Errorf("Expected reset to clear visited cells, got %d", state.VisitedCellCount)
+	}
+}
+
+func TestGameState_FillMissingVisits(t *testing.T) {
+	state, _ := createTestGameState()
+	state.VisitedCells = nil
+	state.PlayerPos = Position{X: 1, Y: 2}
+	state.CurrentMoves = []MoveHistoryEntry{
+		{Action: "left", FromPosition: Position{X: 2, Y: 1}, ToPosition: Position{X: 1, Y: 1}, Success: true},
+		{Action: "up", FromPosition: Position{X: 1, Y: 1}, ToPosition: Position{X: 1, Y: 0}},
+		{Action: "down", FromPosition: Position{X: 1, Y: 1}, ToPosition: Position{X: 1, Y: 2}, Success: true},
+	}
+
+	state.fillMissingVisits()
+	want := []Position{{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}}
+	if !reflect.DeepEqual(state.VisitedCells, want) || state.VisitedCellCount != 3 {
+		t.Errorf("Expected visits %v, got %v (count %d)", want, state.VisitedCells, state.VisitedCellCount)
+	}
+}
+
+func TestEngine_EcoScoring(t *testing.T) {
+	play := func(moves ...string) *GameState {
+		t.Helper()
+		config := createTestConfig()
+		config.ScoringMode = ScoringEco
+		engine, err := NewEngine(config)
+		if err != nil {
+			t.Fatalf("Failed to create engine: %v", err)
+		}
+		for _, move := range moves {
+			engine.Move(move)
+		}
+		state := engine.GetState()
+		if !state.Victory || state.Result == nil {
+			t.Fatalf("Expected %v to win, got %s", moves, state.Message)
+		}
+		return state
+	}
+
+	route := []string{"left", "down", "down", "right", "right", "up", "up"}
+	direct := play(route...)
+	detour := play(append([]string{"left", "right"}, route...)...)
+
+	if direct.ChargeCount != 1 || direct.Result.ChargeCount != 1 || direct.Result.ChargePenalty != DefaultChargePenalty {
+		t.Errorf("Expected one charge with the default penalty, got %+v", direct.Result)
+	}
+	if direct.Result.FinalScore != 4*EcoParkPoints-1 {
+		t.Errorf("Expected a final score of %d, got %d", 4*EcoParkPoints-1, direct.Result.FinalScore)
+	}
+	if detour.ChargeCount != 2 || detour.Score != direct.Score {
+		t.Errorf("Expected the detour to charge twice for the same parks, got %d charges and %d parks",
+			detour.ChargeCount, detour.Score)
+	}
+	if direct.Result.FinalScore <= detour.Result.FinalScore {
+		t.Errorf("Expected fewer charges to score higher, got %d vs %d", direct.Result.FinalScore, detour.Result.FinalScore)
+	}
+
+	// Standard scoring counts charges but never penalises them
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	for _, move := range route {
+		engine.Move(move)
+	}
+	if state := engine.GetState(); state.ChargeCount != 1 || state.ChargePenalty != 0 || state.Result.FinalScore != state.Score {
+		t.Errorf("Expected an unpenalised standard score, got %+v", state.Result)
+	}
+}
+
+func TestEngine_EnergyScoring(t *testing.T) {
+	play := func(mode string, moves ...string) *GameState {
+		t.Helper()
+		config := createTestConfig()
+		config.ScoringMode = mode
+		engine, err := NewEngine(config)
+		if err != nil {
+			t.Fatalf("Failed to create engine: %v", err)
+		}
+		for _, move := range moves {
+			engine.Move(move)
+		}
+		state := engine.GetState()
+		if !state.Victory || state.Result == nil {
+			t.Fatalf("Expected %v to win, got %s", moves, state.Message)
+		}
+		return state
+	}
+
+	// Both routes charge at the supercharger, one finishing a step after it
+	// and the other three
+	short := []string{"left", "down", "down", "right", "right", "up", "up"}
+	long := []string{"right", "down", "down", "left", "left"}
+	a, b := play(ScoringEnergy, short...), play(ScoringEnergy, long...)
+	if a.Battery == b.Battery {
+		t.Fatalf("Expected the routes to finish with different battery, both have %d", a.Battery)
+	}
+	for _, state := range []*GameState{a, b} {
+		if state.Result.BatteryBonus != state.Battery || state.Result.FinalScore != state.Score+state.Battery {
+			t.Errorf("Expected parks plus %d battery, got %+v", state.Battery, state.Result)
+		}
+		if state.FinalScore() != state.Result.FinalScore {
+			t.Errorf("Expected FinalScore %d to match the result, got %d", state.Result.FinalScore, state.FinalScore())
+		}
+	}
+	if got, want := a.Result.FinalScore-b.Result.FinalScore, a.Battery-b.Battery; got != want {
+		t.Errorf("Expected the scores to differ by the leftover battery %d, got %d", want, got)
+	}
+
+	// Standard scoring ignores the leftover battery
+	if state := play(ScoringStandard, short...); state.Result.BatteryBonus != 0 || state.Result.FinalScore != state.Score {
+		t.Errorf("Expected no battery bonus in standard scoring, got %+v", state.Result)
+	}
+}
+
+func TestCropBounds(t *testing.T) {
+	cases := []struct {
+		center                 Position
+		radius, width, height  int
+		minX, minY, maxX, maxY int
+	}{
+		{Position{X: 0, Y: 0}, 1, 5, 5, 0, 0, 1, 1},
+		{Position{X: 4, Y: 0}, 2, 5, 5, 2, 0, 4, 2},
+		{Position{X: 0, Y: 4}, 2, 5, 5, 0, 2, 2, 4},
+		{Position{X: 4, Y: 4}, 1, 5, 5, 3, 3, 4, 4},
+		{Position{X: 5, Y: 2}, 1, 6, 3, 4, 1, 5, 2},
+		{Position{X: 2, Y: 2}, 0, 5, 5, 2, 2, 2, 2},
+		{Position{X: 2, Y: 1}, 10, 5, 3, 0, 0, 4, 2},
+	}
+	for _, tc := range cases {
+		minX, minY, maxX, maxY := cropBounds(tc.center, tc.radius, tc.width, tc.height)
+		if minX != tc.minX || minY != tc.minY || maxX != tc.maxX || maxY != tc.maxY {
+			t.Errorf("cropBounds(%v, %d, %dx%d) = (%d,%d)-(%d,%d), want (%d,%d)-(%d,%d)",
+				tc.center, tc.radius, tc.width, tc.height, minX, minY, maxX, maxY, tc.minX, tc.minY, tc.maxX, tc.maxY)
+		}
+	}
+}
+
+func TestGameState_CropView(t *testing.T) {
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.Move("left")
+	state := engine.GetState()
+
+	// The player at (1,1) sees from the top-left corner to (3,3)
+	view := state.CropView(2)
+	if view.View == nil || view.View.Origin != (Position{X: 0, Y: 0}) || view.View.Width != 5 || view.View.Height != 5 {
+		t.Fatalf("Expected a view from the origin of the 5x5 grid, got %+v", view.View)
+	}
+	if len(view.Grid) != 4 || len(view.Grid[0]) != 4 {
+		t.Fatalf("Expected a 4x4 crop, got %dx%d", len(view.Grid[0]), len(view.Grid))
+	}
+	if view.Grid[2][3] != state.Grid[2][3] || view.PlayerPos != state.PlayerPos {
+		t.Errorf("Expected the crop to keep full grid coordinates at the origin")
+	}
+
+	view = state.CropView(1)
+	if view.View.Origin != (Position{X: 0, Y: 0}) || len(view.Grid) != 3 {
+		t.Errorf("Expected a 3x3 crop from the origin, got %+v", view.View)
+	}
+
+	// Away from the corner the origin moves with the player
+	engine.Move("down")
+	engine.Move("down")
+	engine.Move("right")
+	view = engine.GetState().CropView(1)
+	if view.View.Origin != (Position{X: 1, Y: 2}) || len(view.Grid) != 3 || len(view.Grid[0]) != 3 {
+		t.Errorf("Expected a 3x3 crop from (1,2), got %+v", view.View)
+	}
+	if view.Grid[1][1].Type != Park {
+		t.Errorf("Expected the player's park at the centre of the crop, got %v", view.Grid[1][1].Type)
+	}
+
+	if state := engine.GetState(); len(state.Grid) != 5 || state.View != nil {
+		t.Errorf("Expected the engine to keep the full grid, got %d rows", len(state.Grid))
+	}
+}
+
+func TestEngine_Rewind(t *testing.T) {
+	config := createTestConfig()
+	config.RandomEvents = &RandomEventsConfig{DrainChance: 0.5, DrainAmount: 1, Seed: 7}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.Move("left")
+	engine.Reset()
+	for _, move := range []string{"left", "down", "down", "right", "right"} {
+		engine.Move(move)
+	}
+	played := append([]MoveHistoryEntry(nil), engine.GetState().CurrentMoves...)
+	if engine.GetState().Score != 3 {
+		t.Fatalf("Expected three parks collected, got %d", engine.GetState().Score)
+	}
+
+	// Step back to just after the first park: the later two are uncollected
+	// and their moves gone from history, while the replay matches the
+	// original moves, random events included
+	state := engine.Rewind(3)
+	if state.PlayerPos != (Position{X: 1, Y: 3}) || state.Score != 1 {
+		t.Errorf("Expected to stand on the first park at (1,3) with it collected, got %v with score %d", state.PlayerPos, state.Score)
+	}
+	if !state.Grid[3][1].Visited || state.Grid[3][2].Visited || state.Grid[3][3].Visited || len(state.VisitedParks) != 1 {
+		t.Errorf("Expected only park (1,3) visited, got %v", state.VisitedParks)
+	}
+	if !reflect.DeepEqual(state.CurrentMoves, played[:3]) {
+		t.Errorf("Expected the replay to match the original moves\nwant %+v\ngot  %+v", played[:3], state.CurrentMoves)
+	}
+	if len(state.MoveHistory) != 4 || state.TotalMoves != 4 || state.CurrentMovesCount != 3 {
+		t.Errorf("Expected the earlier game's move and three replayed ones, got %d entries and %d total",
+			len(state.MoveHistory), state.TotalMoves)
+	}
+	if state.Battery != played[2].Battery {
+		t.Errorf("Expected battery %d as after the third move, got %d", played[2].Battery, state.Battery)
+	}
+
+	// keep is clamped to the moves there are
+	if state := engine.Rewind(10); len(state.CurrentMoves) != 3 || state.Score != 1 {
+		t.Errorf("Expected keeping more moves than made to keep them all, got %d moves", len(state.CurrentMoves))
+	}
+	state = engine.Rewind(-1)
+	if len(state.CurrentMoves) != 0 || state.PlayerPos != (Position{X: 2, Y: 1}) || len(state.MoveHistory) != 1 || state.Score != 0 {
+		t.Errorf("Expected a negative keep to rewind to the start, got %v after %d moves", state.PlayerPos, len(state.CurrentMoves))
+	}
+}
+
+func TestEngine_BatteryReserve(t *testing.T) {
+	// Without the reserve the player can drive away from home and strand
+	engine, err := NewEngine(createTestConfig())
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.state.Battery = 2
+	engine.Move("left")
+	if !engine.CanMove("down") || !engine.Move("down") || engine.state.GameOverReason != GameOverStranded {
+		t.Fatalf("Expected the move to strand without the reserve, got %q", engine.state.GameOverReason)
+	}
+
+	config := createTestConfig()
+	config.EnforceBatteryReserve = true
+	engine, err = NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	state := engine.state
+	state.Battery = 2
+
+	// One step away home is still in reach
+	if !engine.Move("left") {
+		t.Fatalf("Expected the move to keep home in reach: %s", state.Message)
+	}
+
+	// A second step would leave an empty battery two cells from home
+	if engine.CanMove("down") {
+		t.Error("Expected CanMove to turn down the stranding move")
+	}
+	if possible := engine.GetPossibleMoves(); len(possible) != 1 || possible[0] != "right" {
+		t.Errorf("Expected only the way home to be possible, got %v", possible)
+	}
+	if engine.Move("down") {
+		t.Fatal("Expected the stranding move to be turned down")
+	}
+	if state.PlayerPos != (Position{X: 1, Y: 1}) || state.Battery != 1 || state.GameOver {
+		t.Errorf("Expected the player to stay put with battery 1, got %+v with battery %d", state.PlayerPos, state.Battery)
+	}
+	if !strings.Contains(state.Message, "strand") {
+		t.Errorf("Expected a would strand message, got %q", state.Message)
+	}
+	if last := engine.GetLastMove(); last.Success || !last.WouldStrand || last.Cost != 0 {
+		t.Errorf("Expected a failed would strand move costing nothing, got %+v", *last)
+	}
+
+	// Heading home is still allowed
+	if !engine.Move("right") || state.Battery != state.MaxBattery {
+		t.Errorf("Expected to drive home and charge, got battery %d: %s", state.Battery, state.Message)
+	}
+	if last := engine.GetLastMove(); last.WouldStrand {
+		t.Error("Expected the flag only on the turned down move")
+	}
+
+	// Out of reach of every charger already, the reserve doesn't trap the player
+	state.PlayerPos = Position{X: 1, Y: 3}
+	state.Battery = 1
+	if !engine.Move("right") {
+		t.Errorf("Expected the move to be allowed with no charger in reach: %s", state.Message)
+	}
+}
+
+func TestEngine_ParkOrder(t *testing.T) {
+	// Parks are numbered in layout order: park_0 at (3,1), then park_1,
+	// park_2 and park_3 along the bottom row
+	config := createTestConfig()
+	config.ParkOrder = []string{"park_3", "park_0"}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	state := engine.state
+	if state.NextPark != "park_3" {
+		t.Fatalf("Expected park_3 to be next, got %q", state.NextPark)
+	}
+
+	// Reaching park_0 first leaves it uncollected at no cost
+	if !engine.Move("right") {
+		t.Fatalf("Expected the move onto the park to succeed: %s", state.Message)
+	}
+	if state.Score != 0 || state.VisitedParks["park_0"] || !strings.Contains(state.Message, "collect park_3 first") {
+		t.Errorf("Expected park_0 to be skipped, got score %d: %s", state.Score, state.Message)
+	}
+	if state.Battery != 7 {
+		t.Errorf("Expected only the move's battery, got %d", state.Battery)
+	}
+
+	// In order, the sequence advances; park_2 isn't ordered and counts any time
+	for _, move := range []string{"down", "down", "left", "right", "up", "up"} {
+		engine.Move(move)
+		if move == "left" && state.NextPark != "park_0" {
+			t.Errorf("Expected park_0 to be next after park_3, got %q", state.NextPark)
+		}
+	}
+	if state.Score != 3 || !state.VisitedParks["park_0"] || !state.VisitedParks["park_2"] || state.NextPark != "" {
+		t.Errorf("Expected three parks and the order done, got score %d, parks %v, next %q", state.Score, state.VisitedParks, state.NextPark)
+	}
+
+	// A reset starts the order over
+	engine.Reset()
+	if engine.state.NextPark != "park_3" {
+		t.Errorf("Expected the order to start over on reset, got %q", engine.state.NextPark)
+	}
+
+	// The penalty policy also takes battery, on arrival and on parking
+	config = createTestConfig()
+	config.ParkOrder = []string{"park_3", "park_0"}
+	config.ParkOrderPolicy = ParkOrderPolicyPenalty
+	config.ParkOrderPenalty = 2
+	config.RequireParkAction = true
+	engine, err = NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	state = engine.state
+	engine.Move("right")
+	if state.Battery != 5 || !strings.Contains(state.Message, "cost 2 battery") {
+		t.Errorf("Expected the move and penalty to leave 5 battery, got %d: %s", state.Battery, state.Message)
+	}
+	if engine.Park() {
+		t.Error("Expected parking out of order to fail")
+	}
+	if state.Battery != 3 || state.Score != 0 {
+		t.Errorf("Expected another penalty and no park, got battery %d and score %d", state.Battery, state.Score)
+	}
+}
+
+func TestEngine_ParkExpiry(t *testing.T) {
+	// park_0 at (3,1) must be collected by move 1, which the move right does
+	config := createTestConfig()
+	config.ParkExpiry = map[string]int{"park_0": 1}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// An invalid direction isn't a move, so it doesn't count towards the deadline
+	if engine.state.MovePlayer("sideways", config) {
+		t.Fatal("Expected an invalid direction to fail")
+	}
+	if left := engine.state.ParkExpiry[0].MovesLeft; left != 1 || engine.state.parkExpired("park_0") {
+		t.Fatalf("Expected park_0 to keep 1 move left, got %d", left)
+	}
+
+	engine.Move("right")
+	state := engine.state
+	if state.Score != 1 || state.parkExpired("park_0") || len(state.ParksExpiredThisMove()) != 0 {
+		t.Fatalf("Expected park_0 collected on its deadline, got score %d: %s", state.Score, state.Message)
+	}
+
+	// One move late, after a reset brings it back, it has turned into road
+	engine.Reset()
+	engine.Move("left")
+	state = engine.state
+	expired := state.ParksExpiredThisMove()
+	if len(expired) != 1 || expired[0].ParkID != "park_0" || expired[0].ExpiredOnMove != 2 || !strings.Contains(state.Message, "Park park_0 expired!") {
+		t.Fatalf("Expected park_0 to expire on the first move after the reset, got %+v: %s", expired, state.Message)
+	}
+	if state.Grid[1][3].Type != Road || CountTotalParks(state.Grid) != 3 {
+		t.Errorf("Expected the park to become road, got %s and %d parks", state.Grid[1][3].Type, CountTotalParks(state.Grid))
+	}
+
+	// The expiry survives a save and reload onto the config grid
+	data, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Failed to marshal state: %v", err)
+	}
+	var saved GameState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to unmarshal state: %v", err)
+	}
+	saved.Grid[1][3] = Cell{Type: Park, ID: "park_0"}
+	reloaded := &GameEngine{config: config}
+	if err := reloaded.SetState(&saved); err != nil {
+		t.Fatalf("Failed to set state: %v", err)
+	}
+	reloaded.Move("right")
+	reloaded.Move("right")
+	if reloaded.state.Score != 0 || len(reloaded.state.ParksExpiredThisMove()) != 0 {
+		t.Errorf("Expected no park to collect, got score %d: %s", reloaded.state.Score, reloaded.state.Message)
+	}
+
+	// Expired parks no longer count towards victory
+	config = createTestConfig()
+	config.ParkExpiry = map[string]int{"park_1": 2, "park_2": 2, "park_3": 2}
+	engine, err = NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.Move("right")
+	engine.Move("left")
+	state = engine.state
+	if !state.Victory || state.GameOverReason != GameOverVictory || len(state.ParksExpiredThisMove()) != 3 {
+		t.Errorf("Expected a victory once the other parks expired, got %q: %s", state.GameOverReason, state.Message)
+	}
+
+	// With every park gone, the game is lost
+	config = createTestConfig()
+	config.ParkExpiry = map[string]int{"park_0": 1, "park_1": 1, "park_2": 1, "park_3": 1}
+	engine, err = NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	engine.Move("left")
+	state = engine.state
+	if !state.GameOver || state.Victory || state.GameOverReason != GameOverParksExpired {
+		t.Errorf("Expected a parks_expired defeat, got %q: %s", state.GameOverReason, state.Message)
+	}
+}
+
+func TestEngine_Checkpoints(t *testing.T) {
+	newEngine := func(lives int) *GameEngine {
+		t.Helper()
+		config := createTestConfig()
+		config.Layout = []string{
+			"BBBBB",
+			"BHKRB",
+			"BBBRB",
+			"BPRRB",
+			"BBBBB",
+		}
+		config.StartingBattery = 3
+		config.Lives = lives
+		config.RespawnBattery = 4
+		engine, err := NewEngine(config)
+		if err != nil {
+			t.Fatalf("Failed to create engine: %v", err)
+		}
+		return engine
+	}
+
+	engine := newEngine(1)
+	if state := engine.GetState(); state.Lives != 1 || state.LastCheckpoint != nil {
+		t.Fatalf("Expected one life and no checkpoint at the start, got %d and %v", state.Lives, state.LastCheckpoint)
+	}
+	engine.Move("right")
+	checkpoint := Position{X: 2, Y: 1}
+	if state := engine.GetState(); state.LastCheckpoint == nil || *state.LastCheckpoint != checkpoint {
+		t.Fatalf("Expected the checkpoint at %v to be recorded, got %v", checkpoint, state.LastCheckpoint)
+	}
+
+	// Running dry past the checkpoint respawns there with the respawn battery
+	engine.Move("right")
+	engine.Move("down")
+	state := engine.GetState()
+	if state.GameOver || state.PlayerPos != checkpoint || state.Battery != 4 || state.Lives != 0 {
+		t.Fatalf("Expected a respawn at %v with 4 battery and no lives left, got game over %v at %v with %d battery and %d lives",
+			checkpoint, state.GameOver, state.PlayerPos, state.Battery, state.Lives)
+	}
+
+	// Without lives the next empty battery ends the game
+	for _, move := range []string{"right", "down", "down", "left"} {
+		engine.Move(move)
+	}
+	state = engine.GetState()
+	if !state.GameOver || state.GameOverReason != GameOverStranded || state.PlayerPos != (Position{X: 2, Y: 3}) {
+		t.Errorf("Expected to strand at (2,3) once the lives ran out, got game over %v (%s) at %v",
+			state.GameOver, state.GameOverReason, state.PlayerPos)
+	}
+
+	// A reset restores the lives and forgets the checkpoint
+	if state := engine.Reset(); state.Lives != 1 || state.LastCheckpoint != nil {
+		t.Errorf("Expected the reset to restore one life and clear the checkpoint, got %d and %v", state.Lives, state.LastCheckpoint)
+	}
+
+	// A checkpoint alone doesn't save a game without lives
+	engine = newEngine(0)
+	for _, move := range []string{"right", "right", "down"} {
+		engine.Move(move)
+	}
+	if state := engine.GetState(); !state.GameOver || state.GameOverReason != GameOverStranded {
+		t.Errorf("Expected to strand without lives, got game over %v (%s)", state.GameOver, state.GameOverReason)
+	}
+}
+
+func TestEngine_Doors(t *testing.T) {
+	config := createTestConfig()
+	config.Layout = []string{
+		"BBBBB",
+		"BHDPB",
+		"BRBBB",
+		"BYBBB",
+		"BBBBB",
+	}
+	config.Keys = []LockTile{{ID: "gate", X: 1, Y: 3}}
+	config.Doors = []LockTile{{ID: "gate", X: 2, Y: 1}}
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	// The door blocks the way until its key is collected
+	if engine.Move("right") {
+		t.Error("Expected the move into the locked door to fail")
+	}
+	state := engine.GetState()
+	if state.PlayerPos != (Position{X: 1, Y: 1}) || state.DoorOpen(2, 1) {
+		t.Fatalf("Expected the locked door to block the move, got %v", state.PlayerPos)
+	}
+	if _, ok := engine.PathTo(Position{X: 3, Y: 1}); ok {
+		t.Error("Expected no path through the locked door")
+	}
+
+	engine.Move("down")
+	engine.Move("down")
+	state = engine.GetState()
+	if !reflect.DeepEqual(state.CollectedKeys, []string{"gate"}) || !state.DoorOpen(2, 1) {
+		t.Fatalf("Expected the key to open the door, got keys %v", state.CollectedKeys)
+	}
+	if !state.Grid[3][1].Visited {
+		t.Error("Expected the key cell to be marked collected")
+	}
+
+	// Coming back over the key doesn't collect it twice
+	engine.Move("up")
+	engine.Move("down")
+	if state := engine.GetState(); len(state.CollectedKeys) != 1 {
+		t.Errorf("Expected the key to be collected once, got %v", state.CollectedKeys)
+	}
+
+	for _, move := range []string{"up", "up", "right", "right"} {
+		engine.Move(move)
+	}
+	state = engine.GetState()
+	if state.PlayerPos != (Position{X: 3, Y: 1}) || !state.Victory {
+		t.Errorf("Expected to pass the open door and win at the park, got %v (victory %v): %s", state.PlayerPos, state.Victory, state.Message)
+	}
+
+	// A reset locks the door again
+	if state := engine.Reset(); len(state.CollectedKeys) != 0 || state.DoorOpen(2, 1) {
+		t.Errorf("Expected the reset to drop the keys, got %v", state.CollectedKeys)
+	}
+}
@@ -0,0 +1,62 @@
+package engine
+
+import "fmt"
+
+// FuelPickup records a fuel tile the player has used up. The tile turns into
+// road once picked up, so it can't be used again until the game is reset.
+type FuelPickup struct {
+	Position Position `json:"position"`
+	Amount   int      `json:"amount"`  // Battery gained, after capping at max battery
+	OnMove   int      `json:"on_move"` // Number of the move that picked it up
+}
+
+// validateFuel checks that a layout with fuel tiles sets a fuel amount
+func validateFuel(config *GameConfig) error {
+	if config.FuelAmount < 0 {
+		return fmt.Errorf("config validation: fuel_amount must not be negative, got %d", config.FuelAmount)
+	}
+	if config.FuelAmount > 0 {
+		return nil
+	}
+	for i, row := range config.Layout {
+		for j, char := range row {
+			if char == 'F' {
+				return fmt.Errorf("config validation: fuel_amount is required for the fuel (F) cell at row %d, col %d", i+1, j+1)
+			}
+		}
+	}
+	return nil
+}
+
+// pickUpFuel adds the config's fuel amount to the battery, capped at max
+// battery, and turns the fuel tile into road
+func (gs *GameState) pickUpFuel(cell *Cell, config *GameConfig) {
+	gained := min(config.FuelAmount, gs.MaxBattery-gs.Battery)
+	gs.Battery += gained
+	cell.Type = Road
+	gs.ConsumedFuel = append(gs.ConsumedFuel, FuelPickup{
+		Position: gs.PlayerPos,
+		Amount:   gained,
+		OnMove:   gs.TotalMoves + 1, // The move is recorded after it is made
+	})
+	gs.Message = fmt.Sprintf("Fuel pickup! +%d battery (%d/%d)", gained, gs.Battery, gs.MaxBattery)
+}
+
+// FuelPickedUpThisMove returns the fuel picked up on the most recently
+// recorded move, or nil if it picked up none
+func (gs *GameState) FuelPickedUpThisMove() *FuelPickup {
+	if n := len(gs.ConsumedFuel); n > 0 && gs.ConsumedFuel[n-1].OnMove == gs.TotalMoves {
+		return &gs.ConsumedFuel[n-1]
+	}
+	return nil
+}
+
+// clearConsumedFuel turns the tiles of recorded pickups into road, so a
+// state rebuilt from the config grid can't pick them up again
+func (gs *GameState) clearConsumedFuel() {
+	for _, pickup := range gs.ConsumedFuel {
+		if x, y := pickup.Position.X, pickup.Position.Y; gs.InBounds(x, y) && gs.Grid[y][x].Type == Fuel {
+			gs.Grid[y][x].Type = Road
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package engine
+
+import "fmt"
+
+// Hazard patrol patterns
+const (
+	PatrolLoop   = "loop"   // Walk the path and start over from the first cell
+	PatrolBounce = "bounce" // Walk the path, then back along it
+)
+
+// What running into a hazard does
+const (
+	HazardPolicyEndGame = "end_game" // The game is lost
+	HazardPolicyPenalty = "penalty"  // The config's hazard penalty is taken from the battery
+)
+
+// Hazard is an obstacle that patrols a path, one cell per move the player
+// takes. Consecutive cells of the path must be neighbours, as must the last
+// and first cells of a looping patrol.
+type Hazard struct {
+	Path    []Position `json:"path"`              // The first cell is where it starts
+	Pattern string     `json:"pattern,omitempty"` // loop (default) or bounce
+}
+
+// HazardStatus is the live position of a patrolling hazard
+type HazardStatus struct {
+	Position Position `json:"position"`
+	Step     int      `json:"step"` // Index into the hazard's patrol, see Hazard.at
+}
+
+// cycle returns the number of steps before the patrol repeats
+func (h Hazard) cycle() int {
+	if h.Pattern == PatrolBounce && len(h.Path) > 1 {
+		return 2 * (len(h.Path) - 1)
+	}
+	return len(h.Path)
+}
+
+// at returns the hazard's cell after step steps of its patrol
+func (h Hazard) at(step int) Position {
+	i := step % h.cycle()
+	if i >= len(h.Path) {
+		i = h.cycle() - i
+	}
+	return h.Path[i]
+}
+
+// validateHazards checks the hazard policy and that every hazard patrols
+// passable cells of the layout, which must already be validated, one step
+// at a time
+func validateHazards(config *GameConfig) error {
+	if config.HazardPenalty < 0 {
+		return fmt.Errorf("config validation: hazard_penalty must not be negative, got %d", config.HazardPenalty)
+	}
+	switch config.HazardPolicy {
+	case "", HazardPolicyEndGame:
+	case HazardPolicyPenalty:
+		if config.HazardPenalty < 1 {
+			return fmt.Errorf("config validation: hazard_penalty must be at least 1 with the penalty policy, got %d", config.HazardPenalty)
+		}
+	default:
+		return fmt.Errorf("config validation: hazard_policy must be %s or %s, got %q", HazardPolicyEndGame, HazardPolicyPenalty, config.HazardPolicy)
+	}
+
+	home := primaryHome(config)
+	for i, h := range config.Hazards {
+		if h.Pattern != "" && h.Pattern != PatrolLoop && h.Pattern != PatrolBounce {
+			return fmt.Errorf("config validation: hazards entry %d pattern must be %s or %s, got %q", i+1, PatrolLoop, PatrolBounce, h.Pattern)
+		}
+		if len(h.Path) == 0 {
+			return fmt.Errorf("config validation: hazards entry %d needs a path", i+1)
+		}
+		for j, p := range h.Path {
+			if p.Y < 0 || p.Y >= len(config.Layout) || p.X < 0 || p.X >= len(config.Layout[p.Y]) {
+				return fmt.Errorf("config validation: hazards entry %d path cell (%d,%d) is outside the grid", i+1, p.X, p.Y)
+			}
+			if c := config.Layout[p.Y][p.X]; c == 'W' || c == 'B' || c == 'D' {
+				return fmt.Errorf("config validation: hazards entry %d path cell (%d,%d) is not passable", i+1, p.X, p.Y)
+			}
+			if j > 0 && !neighbours(h.Path[j-1], p) {
+				return fmt.Errorf("config validation: hazards entry %d path jumps from (%d,%d) to (%d,%d)",
+					i+1, h.Path[j-1].X, h.Path[j-1].Y, p.X, p.Y)
+			}
+		}
+		if last := h.Path[len(h.Path)-1]; h.Pattern != PatrolBounce && len(h.Path) > 1 && !neighbours(last, h.Path[0]) {
+			return fmt.Errorf("config validation: hazards entry %d loops from (%d,%d) back to (%d,%d), which are not neighbours",
+				i+1, last.X, last.Y, h.Path[0].X, h.Path[0].Y)
+		}
+		if h.Path[0] == home {
+			return fmt.Errorf("config validation: hazards entry %d starts on the primary home (%d,%d)", i+1, home.X, home.Y)
+		}
+	}
+	return nil
+}
+
+// neighbours reports whether a and b are the same cell or share a side
+func neighbours(a, b Position) bool {
+	return abs(a.X-b.X)+abs(a.Y-b.Y) <= 1
+}
+
+// newHazardStatus returns the config's hazards at the start of their patrols
+func newHazardStatus(config *GameConfig) []HazardStatus {
+	if config == nil || len(config.Hazards) == 0 {
+		return nil
+	}
+	status := make([]HazardStatus, len(config.Hazards))
+	for i, h := range config.Hazards {
+		status[i] = HazardStatus{Position: h.Path[0]}
+	}
+	return status
+}
+
+// HazardAt reports whether a hazard occupies (x, y)
+func (gs *GameState) HazardAt(x, y int) bool {
+	for _, h := range gs.Hazards {
+		if h.Position.X == x && h.Position.Y == y {
+			return true
+		}
+	}
+	return false
+}
+
+// moveHazards runs into any hazard on the cell the player just entered
+// from from, then advances every hazard one step of its patrol and runs
+// into any that steps onto the player. It is called once per move of an
+// unfinished game; a move hits at most once.
+func (gs *GameState) moveHazards(from Position, config *GameConfig) {
+	if len(gs.Hazards) != len(config.Hazards) {
+		return
+	}
+	hit := gs.PlayerPos != from && gs.HazardAt(gs.PlayerPos.X, gs.PlayerPos.Y)
+	for i, h := range config.Hazards {
+		status := &gs.Hazards[i]
+		prev := status.Position
+		status.Step = (status.Step + 1) % h.cycle()
+		status.Position = h.at(status.Step)
+		if status.Position == gs.PlayerPos && prev != gs.PlayerPos {
+			hit = true
+		}
+	}
+	if hit {
+		gs.hitHazard(config)
+	}
+}
+
+// hitHazard applies the config's hazard policy to the player
+func (gs *GameState) hitHazard(config *GameConfig) {
+	gs.hazardHit = true
+	x, y := gs.PlayerPos.X, gs.PlayerPos.Y
+	if config.HazardPolicy != HazardPolicyPenalty {
+		gs.EndGame(GameOverHazard)
+		gs.Message = fmt.Sprintf("Hit by a hazard at (%d,%d)! Game Over!", x, y)
+		return
+	}
+
+	lost := min(config.HazardPenalty, gs.Battery)
+	gs.Battery -= lost
+	gs.hazardPenalty = lost
+	gs.Message = fmt.Sprintf("Hit by a hazard at (%d,%d): lost %d battery (%d/%d)", x, y, lost, gs.Battery, gs.MaxBattery)
+	if gs.Battery == 0 && !gs.CanReachCharger() && !gs.awaitingPark(config) {
+		gs.strand(config)
+	}
+}
+
+// fillMissingHazards restarts the patrols of states saved before the
+// config's hazards were tracked
+func (gs *GameState) fillMissingHazards(config *GameConfig) {
+	if len(gs.Hazards) != len(config.Hazards) {
+		gs.Hazards = newHazardStatus(config)
+	}
+}
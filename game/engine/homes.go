@@ -0,0 +1,57 @@
+package engine
+
+import "fmt"
+
+// validatePrimaryHome checks that the config's primary home, if set, is a
+// home (H) cell of the layout, which must already be validated
+func validatePrimaryHome(config *GameConfig) error {
+	if config.SecondaryHomeCharge < 0 || config.SecondaryHomeCharge > config.MaxBattery {
+		return fmt.Errorf("config validation: secondary_home_charge must be between 0 and max_battery (%d), got %d",
+			config.MaxBattery, config.SecondaryHomeCharge)
+	}
+	p := config.PrimaryHome
+	if p == nil {
+		return nil
+	}
+	if p.Y < 0 || p.Y >= len(config.Layout) || p.X < 0 || p.X >= len(config.Layout[p.Y]) {
+		return fmt.Errorf("config validation: primary_home (%d,%d) is outside the grid", p.X, p.Y)
+	}
+	if c := config.Layout[p.Y][p.X]; c != 'H' {
+		return fmt.Errorf("config validation: primary_home (%d,%d) must be a home (H) cell, got '%c'", p.X, p.Y, c)
+	}
+	return nil
+}
+
+// primaryHome returns the home the game starts at: the config's primary
+// home when it names a home cell, otherwise the last home of the layout in
+// reading order, where games have always started
+func primaryHome(config *GameConfig) Position {
+	if p := config.PrimaryHome; p != nil && p.Y >= 0 && p.Y < len(config.Layout) &&
+		p.X >= 0 && p.X < len(config.Layout[p.Y]) && config.Layout[p.Y][p.X] == 'H' {
+		return *p
+	}
+	var home Position
+	for y, row := range config.Layout {
+		for x, char := range row {
+			if char == 'H' {
+				home = Position{X: x, Y: y}
+			}
+		}
+	}
+	return home
+}
+
+// onSecondaryHome reports whether the player stands on a home other than
+// the primary one
+func (gs *GameState) onSecondaryHome() bool {
+	return gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type == Home && gs.PlayerPos != gs.PrimaryHome
+}
+
+// fillMissingPrimaryHome sets the primary home of states saved before it
+// was recorded
+func (gs *GameState) fillMissingPrimaryHome(config *GameConfig) {
+	p := gs.PrimaryHome
+	if !gs.InBounds(p.X, p.Y) || gs.Grid[p.Y][p.X].Type != Home {
+		gs.PrimaryHome = primaryHome(config)
+	}
+}
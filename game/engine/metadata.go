@@ -0,0 +1,28 @@
+package engine
+
+import "strings"
+
+// Longest config metadata kept, in characters; longer values are cut short
+// rather than failing validation
+const (
+	MaxConfigMetadataLength = 100  // Author and version
+	MaxConfigNotesLength    = 2000 // Notes
+)
+
+// NormalizeMetadata trims the config's author, version and notes and cuts
+// overly long ones down to size. Metadata is only there to annotate a map,
+// so it is tidied instead of validated.
+func (c *GameConfig) NormalizeMetadata() {
+	c.Author = clipMetadata(c.Author, MaxConfigMetadataLength)
+	c.Version = clipMetadata(c.Version, MaxConfigMetadataLength)
+	c.Notes = clipMetadata(c.Notes, MaxConfigNotesLength)
+}
+
+// clipMetadata trims s and keeps at most limit characters of it
+func clipMetadata(s string, limit int) string {
+	s = strings.TrimSpace(s)
+	if runes := []rune(s); len(runes) > limit {
+		s = strings.TrimSpace(string(runes[:limit]))
+	}
+	return s
+}
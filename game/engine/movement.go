@@ -1,29 +1,154 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 )
 
+// ErrImpassable is returned when teleporting onto an obstacle or off the grid
+var ErrImpassable = errors.New("cell is not passable")
+
+// ErrGameOver is returned when teleporting after the game has ended
+var ErrGameOver = errors.New("game is over")
+
+// InBounds reports whether the coordinates lie on the grid; rows may be
+// wider than the grid is tall
+func (gs *GameState) InBounds(x, y int) bool {
+	return y >= 0 && y < len(gs.Grid) && x >= 0 && x < len(gs.Grid[y])
+}
+
 // CanMoveTo checks if the player can move to the specified coordinates
 func (gs *GameState) CanMoveTo(x, y int) bool {
-	// Check bounds - handle non-square grids properly
-	if y < 0 || y >= len(gs.Grid) {
-		return false
-	}
-	if x < 0 || x >= len(gs.Grid[0]) {
+	if !gs.InBounds(x, y) {
 		return false
 	}
 	cellType := gs.Grid[y][x].Type
-	// Only water and buildings are obstacles - homes are passable and charge battery
+	// Doors open once their key is collected
+	if cellType == Door {
+		return gs.HasKey(gs.Grid[y][x].ID)
+	}
+	// Otherwise only water and buildings are obstacles - homes are passable and charge battery
 	return cellType != Water && cellType != Building
 }
 
+// ReachableCells flood-fills the passable cells connected to the player's
+// position, ignoring battery, and returns them in row-major order. Unlike a
+// Manhattan distance it follows the roads, so walls and water are accounted for.
+func (gs *GameState) ReachableCells() []Position {
+	return gs.reachableFrom(gs.PlayerPos)
+}
+
+// reachableFrom flood-fills the passable cells connected to start
+func (gs *GameState) reachableFrom(start Position) []Position {
+	if !gs.CanMoveTo(start.X, start.Y) {
+		return []Position{}
+	}
+
+	seen := make([][]bool, len(gs.Grid))
+	for y := range gs.Grid {
+		seen[y] = make([]bool, len(gs.Grid[y]))
+	}
+	seen[start.Y][start.X] = true
+	queue := []Position{start}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, d := range []Position{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}} {
+			next := Position{X: pos.X + d.X, Y: pos.Y + d.Y}
+			if gs.CanMoveTo(next.X, next.Y) && !seen[next.Y][next.X] {
+				seen[next.Y][next.X] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	cells := []Position{}
+	for y, row := range seen {
+		for x, reached := range row {
+			if reached {
+				cells = append(cells, Position{X: x, Y: y})
+			}
+		}
+	}
+	return cells
+}
+
+// PathTo finds a shortest drive along the roads from the player to target,
+// ignoring battery, and returns its directions; the path is empty when the
+// player is already there. It reports false when target is off the grid, an
+// obstacle, or cut off from the player.
+func (gs *GameState) PathTo(target Position) ([]string, bool) {
+	if !gs.CanMoveTo(target.X, target.Y) {
+		return nil, false
+	}
+
+	steps := []struct {
+		dir   string
+		delta Position
+	}{
+		{"up", Position{X: 0, Y: -1}},
+		{"down", Position{X: 0, Y: 1}},
+		{"left", Position{X: -1, Y: 0}},
+		{"right", Position{X: 1, Y: 0}},
+	}
+	// came holds the index into steps of the move that first reached a cell
+	seen := make([][]bool, len(gs.Grid))
+	came := make([][]int, len(gs.Grid))
+	for y := range gs.Grid {
+		seen[y] = make([]bool, len(gs.Grid[y]))
+		came[y] = make([]int, len(gs.Grid[y]))
+	}
+	seen[gs.PlayerPos.Y][gs.PlayerPos.X] = true
+	queue := []Position{gs.PlayerPos}
+	for len(queue) > 0 && !seen[target.Y][target.X] {
+		pos := queue[0]
+		queue = queue[1:]
+		for i, step := range steps {
+			next := Position{X: pos.X + step.delta.X, Y: pos.Y + step.delta.Y}
+			if gs.CanMoveTo(next.X, next.Y) && !seen[next.Y][next.X] {
+				seen[next.Y][next.X] = true
+				came[next.Y][next.X] = i
+				queue = append(queue, next)
+			}
+		}
+	}
+	if !seen[target.Y][target.X] {
+		return nil, false
+	}
+
+	path := []string{}
+	for pos := target; pos != gs.PlayerPos; {
+		step := steps[came[pos.Y][pos.X]]
+		path = append(path, step.dir)
+		pos = Position{X: pos.X - step.delta.X, Y: pos.Y - step.delta.Y}
+	}
+	slices.Reverse(path)
+	return path, true
+}
+
 // MovePlayer attempts to move the player in the specified direction
 func (gs *GameState) MovePlayer(direction string, config *GameConfig) bool {
-	if gs.GameOver {
+	if gs.GameOver || !isMoveAction(direction) {
 		return false
 	}
+	gs.tickChargers()
+	gs.tickParkExpiry()
+	defer gs.expireParks(config)
+	held := gs.ChargeHold > 0
+	if held {
+		gs.ChargeHold--
+	}
+	if direction == ActionCharge {
+		return gs.charge(config)
+	}
+	if direction == ActionPark {
+		return gs.park(config)
+	}
+	if direction == ActionWait {
+		return gs.wait(config)
+	}
 
 	newX, newY := gs.PlayerPos.X, gs.PlayerPos.Y
 
@@ -40,11 +165,16 @@ func (gs *GameState) MovePlayer(direction string, config *GameConfig) bool {
 		return false
 	}
 
+	if held {
+		gs.Message = gs.heldMessage(direction)
+		return false
+	}
+
 	// Check wall collision BEFORE battery check
 	if !gs.CanMoveTo(newX, newY) {
 		// Get the type of obstacle hit
 		obstacleType := "boundary"
-		if newY >= 0 && newY < len(gs.Grid) && newX >= 0 && newX < len(gs.Grid[0]) {
+		if gs.InBounds(newX, newY) {
 			obstacleType = string(gs.Grid[newY][newX].Type)
 		}
 
@@ -55,20 +185,41 @@ func (gs *GameState) MovePlayer(direction string, config *GameConfig) bool {
 			if config.Messages.HitWall != "" {
 				gs.Message = config.Messages.HitWall + fmt.Sprintf(" [Hit: %s at (%d,%d)]", obstacleType, newX, newY)
 			}
-			gs.GameOver = true
+			gs.EndGame(GameOverWallCrash)
 			return false
 		}
 		gs.Message = fmt.Sprintf("Can't move %s: %s at (%d,%d)", direction, obstacleType, newX, newY)
 		if config.Messages.CantMove != "" {
 			gs.Message = config.Messages.CantMove + fmt.Sprintf(" [Blocked by: %s]", obstacleType)
 		}
+		if config.WallCrashBatteryPenalty > 0 {
+			gs.Battery = max(gs.Battery-config.WallCrashBatteryPenalty, 0)
+			gs.Message += fmt.Sprintf(" (crash cost %d battery)", config.WallCrashBatteryPenalty)
+			if gs.Battery == 0 && !gs.CanReachCharger() && !gs.awaitingPark(config) {
+				gs.strand(config)
+			}
+		}
 		return false
 	}
 
 	// Now check battery for valid moves
 	if gs.Battery <= 0 {
+		// With incremental charging or a cooling charger an empty battery on a
+		// charger can still recover
+		if (!gs.chargerEffectHere(config).Instant || gs.coolingDown()) && gs.CanReachCharger() {
+			gs.Message = "Battery empty: charge before moving"
+			return false
+		}
+		if gs.respawn(config) {
+			return false
+		}
 		gs.Message = config.Messages.OutOfBattery
-		gs.GameOver = true
+		gs.EndGame(GameOverOutOfBattery)
+		return false
+	}
+
+	if config.EnforceBatteryReserve && gs.wouldStrand(direction, config) {
+		gs.rejectStrandingMove(direction)
 		return false
 	}
 
@@ -76,62 +227,356 @@ func (gs *GameState) MovePlayer(direction string, config *GameConfig) bool {
 	gs.PlayerPos.X = newX
 	gs.PlayerPos.Y = newY
 	gs.Battery--
+	gs.chargeRevisit(config)
+	gs.arrive(config)
+	if gs.revisitPenalty > 0 && !gs.GameOver {
+		gs.Message += fmt.Sprintf(" (revisit cost %d battery)", gs.revisitPenalty)
+	}
+
+	return true
+}
+
+// isMoveAction reports whether a player can send the action as a move: a
+// direction or the charge, park or wait action. Anything else isn't a turn,
+// so it leaves the turn counters alone.
+func isMoveAction(action string) bool {
+	switch action {
+	case "up", "down", "left", "right", ActionCharge, ActionPark, ActionWait:
+		return true
+	}
+	return false
+}
+
+// Teleport places the player on (x, y) without spending battery, then applies
+// the cell's charge or park effects as if they had driven there. It is a
+// debugging aid rather than a move.
+func (gs *GameState) Teleport(x, y int, config *GameConfig) error {
+	if gs.GameOver {
+		return ErrGameOver
+	}
+	if !gs.CanMoveTo(x, y) {
+		return fmt.Errorf("%w: (%d,%d)", ErrImpassable, x, y)
+	}
+	gs.PlayerPos = Position{X: x, Y: y}
+	gs.markVisited()
+	gs.arrive(config)
+	return nil
+}
 
-	// Check current cell
-	currentCell := &gs.Grid[newY][newX]
+// arrive applies the effects of the cell the player just reached: charging,
+// park collection, and stranding on an empty battery
+func (gs *GameState) arrive(config *GameConfig) {
+	currentCell := &gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X]
 
 	switch currentCell.Type {
 	case Home:
-		gs.Battery = gs.MaxBattery
+		if !gs.chargeHere(config, true) {
+			break
+		}
+		if gs.chargesOnWait(config) {
+			gs.Message = gs.passThroughMessage()
+			break
+		}
 		gs.Message = config.Messages.HomeCharge
+		if config.SecondaryHomeCharge > 0 && gs.onSecondaryHome() {
+			gs.Message = fmt.Sprintf("Secondary home: +%d battery (%d/%d)", config.SecondaryHomeCharge, gs.Battery, gs.MaxBattery)
+		}
+		if config.GradualCharge || config.ChargerEffects[Home].Charge > 0 {
+			gs.Message = gs.chargingMessage()
+		}
 
 	case Supercharger:
-		gs.Battery = gs.MaxBattery
+		if !gs.chargeHere(config, true) {
+			break
+		}
+		if gs.chargesOnWait(config) {
+			gs.Message = gs.passThroughMessage()
+			break
+		}
 		gs.Message = config.Messages.SuperchargerCharge
+		if config.GradualCharge || config.ChargerEffects[Supercharger].Charge > 0 {
+			gs.Message = gs.chargingMessage()
+		}
+
+	case Trickle:
+		if !gs.chargeHere(config, true) {
+			break
+		}
+		gs.Message = gs.chargingMessage()
+		if gs.chargesOnWait(config) {
+			gs.Message = gs.passThroughMessage()
+		}
 
 	case Park:
-		if currentCell.ID != "" && !gs.VisitedParks[currentCell.ID] {
-			gs.VisitedParks[currentCell.ID] = true
-			currentCell.Visited = true
-			gs.Score++
-			gs.Message = fmt.Sprintf(config.Messages.ParkVisited, gs.Score)
-
-			// Check victory condition
-			if gs.Score == CountTotalParks(gs.Grid) {
-				gs.Victory = true
-				gs.GameOver = true
-				gs.Message = fmt.Sprintf(config.Messages.Victory, gs.Score)
+		if gs.canPark() {
+			if !gs.parkInOrder(currentCell.ID, config) {
+				gs.skipOutOfOrderPark(currentCell.ID, config)
+			} else if config.RequireParkAction {
+				gs.Message = fmt.Sprintf("Reached park %s: park here to collect it", currentCell.ID)
+			} else {
+				gs.collectPark(currentCell, config)
 			}
 		} else if currentCell.Visited {
 			gs.Message = config.Messages.ParkAlreadyVisited
 		}
 
+	case Fuel:
+		gs.pickUpFuel(currentCell, config)
+
+	case Checkpoint:
+		gs.reachCheckpoint()
+
+	case Key:
+		gs.collectKey(currentCell)
+
 	default:
 		gs.Message = fmt.Sprintf(config.Messages.BatteryStatus, gs.Battery, gs.MaxBattery)
 	}
+	if config.MustReturnHome && currentCell.Type == Home {
+		gs.checkVictory(config)
+	}
 
-	// Check if stranded
-	if gs.Battery == 0 && !gs.CanReachCharger() {
-		gs.GameOver = true
-		gs.Message = config.Messages.Stranded
+	// Gradual charging also trickles in on the cells next to a charger
+	if config.GradualCharge && !gs.GameOver && !gs.CanReachCharger() && gs.nextToCharger() {
+		gs.addCharge(config)
+		if currentCell.Type != Park {
+			gs.Message = gs.chargingMessage()
+		}
 	}
 
+	// Parking costs no battery, so an empty battery on a park that still
+	// needs the park action can collect it
+	if gs.Battery == 0 && !gs.CanReachCharger() && !gs.awaitingPark(config) {
+		gs.strand(config)
+	}
+}
+
+// park spends a turn collecting the park the player stands on; it fails
+// unless the player is on an uncollected park that is next in the park
+// order, if it has one
+func (gs *GameState) park(config *GameConfig) bool {
+	if !gs.canPark() {
+		gs.Message = fmt.Sprintf("Can't park: no uncollected park at (%d,%d)", gs.PlayerPos.X, gs.PlayerPos.Y)
+		return false
+	}
+	cell := &gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X]
+	if !gs.parkInOrder(cell.ID, config) {
+		gs.skipOutOfOrderPark(cell.ID, config)
+		if gs.Battery == 0 && !gs.CanReachCharger() {
+			gs.strand(config)
+		}
+		return false
+	}
+	gs.collectPark(cell, config)
+	if !gs.GameOver && gs.Battery == 0 && !gs.CanReachCharger() {
+		gs.strand(config)
+	}
+	return true
+}
+
+// wait spends a turn in place, taking the config's wait cost down to an
+// empty battery, which strands the player away from a charger. Under the
+// config's charge_requires_wait, waiting on a charger fills the battery.
+func (gs *GameState) wait(config *GameConfig) bool {
+	cost := min(config.WaitCost, gs.Battery)
+	gs.Battery -= cost
+	gs.Message = fmt.Sprintf("Waited at (%d,%d)", gs.PlayerPos.X, gs.PlayerPos.Y)
+	if cost > 0 {
+		gs.Message += fmt.Sprintf(" (cost %d battery)", cost)
+	}
+	if gs.chargesOnWait(config) && gs.chargeHere(config, false) {
+		gs.Message = fmt.Sprintf("%s: charged fully while waiting (%d/%d)",
+			chargerName(gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type), gs.Battery, gs.MaxBattery)
+	}
+	if gs.Battery == 0 && !gs.CanReachCharger() && !gs.awaitingPark(config) {
+		gs.strand(config)
+	}
+	return true
+}
+
+// awaitingPark reports whether the player stands on a park they can still
+// collect by parking, which needs no battery
+func (gs *GameState) awaitingPark(config *GameConfig) bool {
+	return config.RequireParkAction && gs.canPark() &&
+		gs.parkInOrder(gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].ID, config)
+}
+
+// canPark reports whether the player stands on a park that hasn't been collected
+func (gs *GameState) canPark() bool {
+	cell := gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X]
+	return cell.Type == Park && cell.ID != "" && !gs.VisitedParks[cell.ID]
+}
+
+// collectPark marks a park visited, scores it and checks for victory
+func (gs *GameState) collectPark(cell *Cell, config *GameConfig) {
+	gs.VisitedParks[cell.ID] = true
+	cell.Visited = true
+	gs.collectedPark = cell.ID
+	gs.NextPark = gs.nextOrderedPark(config)
+	gs.Score++
+	gs.Message = fmt.Sprintf(config.Messages.ParkVisited, gs.Score)
+	gs.checkVictory(config)
+}
+
+// ParkCollectedThisMove returns the ID of the park the most recent move of
+// the current game collected, or "" when it collected none
+func (gs *GameState) ParkCollectedThisMove() string {
+	if n := len(gs.CurrentMoves); n > 0 {
+		return gs.CurrentMoves[n-1].ParkCollected
+	}
+	return ""
+}
+
+// strand ends the game with an empty battery away from any charger, unless
+// the player can respawn at a checkpoint
+func (gs *GameState) strand(config *GameConfig) {
+	if gs.respawn(config) {
+		return
+	}
+	gs.EndGame(GameOverStranded)
+	gs.Message = config.Messages.Stranded
+}
+
+// charge spends a turn charging in place; it fails unless the player is on
+// a charger that is ready to charge
+func (gs *GameState) charge(config *GameConfig) bool {
+	cellType := gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type
+	if !IsCharger(cellType) {
+		gs.Message = fmt.Sprintf("Can't charge: not on a charger at (%d,%d)", gs.PlayerPos.X, gs.PlayerPos.Y)
+		return false
+	}
+	if !gs.chargeHere(config, false) {
+		return false
+	}
+	gs.Message = fmt.Sprintf(config.Messages.BatteryStatus, gs.Battery, gs.MaxBattery)
+	if config.GradualCharge || cellType == Trickle || config.ChargerEffects[cellType].Charge > 0 {
+		gs.Message = gs.chargingMessage()
+	}
 	return true
 }
 
-// CanReachCharger checks if the player can reach a charger from their current position
+// chargeRate is the battery one charging turn adds, or 0 when the config
+// fills the battery instantly. Gradual charging defaults to 1 per turn.
+func chargeRate(config *GameConfig) int {
+	if config.GradualCharge && config.ChargePerTurn <= 0 {
+		return 1
+	}
+	return config.ChargePerTurn
+}
+
+// addCharge applies one charging increment of the charger the player is on,
+// or of the config when it's next to one, or fills the battery when that
+// charges instantly. Secondary homes add the config's secondary home charge
+// instead, when it is set.
+func (gs *GameState) addCharge(config *GameConfig) {
+	rate := gs.chargerEffectHere(config).Charge
+	if config.SecondaryHomeCharge > 0 && gs.onSecondaryHome() {
+		rate = config.SecondaryHomeCharge
+	}
+	if rate <= 0 {
+		gs.Battery = gs.MaxBattery
+		return
+	}
+	gs.Battery = min(gs.Battery+rate, gs.MaxBattery)
+}
+
+// chargerEffectHere returns the effect of the charger the player stands on,
+// or the config's charge rate anywhere else
+func (gs *GameState) chargerEffectHere(config *GameConfig) ChargerEffect {
+	if t := gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type; IsCharger(t) {
+		return ChargerEffectFor(t, config)
+	}
+	rate := chargeRate(config)
+	return ChargerEffect{Charge: rate, Instant: rate == 0}
+}
+
+// nextToCharger reports whether a home or supercharger that is ready to
+// charge borders the player's cell. Trickling from it doesn't use it up.
+func (gs *GameState) nextToCharger() bool {
+	for _, d := range []Position{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}} {
+		x, y := gs.PlayerPos.X+d.X, gs.PlayerPos.Y+d.Y
+		if gs.InBounds(x, y) && (gs.Grid[y][x].Type == Home || gs.Grid[y][x].Type == Supercharger) && gs.chargerReady(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// chargingMessage reports gradual charging progress at the player's position
+func (gs *GameState) chargingMessage() string {
+	place := "Next to charger"
+	switch gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type {
+	case Home:
+		place = "Home"
+	case Supercharger:
+		place = "Supercharger"
+	case Trickle:
+		place = "Trickle charger"
+	}
+	return fmt.Sprintf("%s: charging (%d/%d)", place, gs.Battery, gs.MaxBattery)
+}
+
+// Clone returns a deep copy of the game state
+func (gs *GameState) Clone() *GameState {
+	cp := *gs
+	cp.Grid = make([][]Cell, len(gs.Grid))
+	for y, row := range gs.Grid {
+		cp.Grid[y] = append([]Cell(nil), row...)
+	}
+	cp.VisitedParks = make(map[string]bool, len(gs.VisitedParks))
+	for id, visited := range gs.VisitedParks {
+		cp.VisitedParks[id] = visited
+	}
+	cp.Chargers = append([]ChargerStatus(nil), gs.Chargers...)
+	cp.Hazards = append([]HazardStatus(nil), gs.Hazards...)
+	cp.ConsumedFuel = append([]FuelPickup(nil), gs.ConsumedFuel...)
+	cp.VisitedCells = append([]Position(nil), gs.VisitedCells...)
+	cp.ParkExpiry = append([]ParkExpiryStatus(nil), gs.ParkExpiry...)
+	cp.CarriedParks = append([]string(nil), gs.CarriedParks...)
+	cp.CollectedKeys = append([]string(nil), gs.CollectedKeys...)
+	if gs.LastCheckpoint != nil {
+		checkpoint := *gs.LastCheckpoint
+		cp.LastCheckpoint = &checkpoint
+	}
+	if gs.Result != nil {
+		result := *gs.Result
+		cp.Result = &result
+	}
+	cp.MoveHistory = append([]MoveHistoryEntry(nil), gs.MoveHistory...)
+	cp.CurrentMoves = append([]MoveHistoryEntry(nil), gs.CurrentMoves...)
+	cp.LocalView = append([]SurroundingCell(nil), gs.LocalView...)
+	cp.LocalView3x3 = append([]string(nil), gs.LocalView3x3...)
+	if gs.RelativeView != nil {
+		view := *gs.RelativeView
+		view.Rows = append([]string(nil), view.Rows...)
+		cp.RelativeView = &view
+	}
+	cp.MovePreviews = nil
+	return &cp
+}
+
+// EndGame marks the game as over for the given reason
+func (gs *GameState) EndGame(reason GameOverReason) {
+	gs.GameOver = true
+	gs.GameOverReason = reason
+}
+
+// CanReachCharger checks if the player can reach a charger from their current
+// position. A depleted charger no longer counts.
 func (gs *GameState) CanReachCharger() bool {
 	currentCell := gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X]
-	return currentCell.Type == Home || currentCell.Type == Supercharger
+	if !IsCharger(currentCell.Type) {
+		return false
+	}
+	return !gs.chargerDepleted(gs.PlayerPos.X, gs.PlayerPos.Y)
 }
 
 // GenerateLocalView creates list of 8 surrounding cells around the player
 func (gs *GameState) GenerateLocalView() []SurroundingCell {
-	gridSize := len(gs.Grid)
 	px, py := gs.PlayerPos.X, gs.PlayerPos.Y
 
 	getCellType := func(x, y int) CellType {
-		if x >= 0 && x < gridSize && y >= 0 && y < gridSize {
+		if gs.InBounds(x, y) {
 			return gs.Grid[y][x].Type
 		}
 		return Building // Out of bounds = building
@@ -161,17 +606,28 @@ func (gs *GameState) GenerateLocalView() []SurroundingCell {
 	return surroundings
 }
 
-// AddMoveToHistory adds a move to the game's move history
-func (gs *GameState) AddMoveToHistory(action string, fromPos, toPos Position, success bool) {
+// AddMoveToHistory adds a move to the game's move history. batteryBefore is
+// the battery level before the move was attempted.
+func (gs *GameState) AddMoveToHistory(action string, fromPos, toPos Position, batteryBefore int, success bool) {
 	entry := MoveHistoryEntry{
-		Action:       action,
-		FromPosition: fromPos,
-		ToPosition:   toPos,
-		Battery:      gs.Battery,
-		Timestamp:    time.Now().Unix(),
-		Success:      success,
-		MoveNumber:   gs.TotalMoves + 1,
+		Action:         action,
+		FromPosition:   fromPos,
+		ToPosition:     toPos,
+		Battery:        gs.Battery,
+		BatteryDelta:   gs.Battery - batteryBefore,
+		Cost:           moveCost(action, success, batteryBefore-gs.Battery-gs.hazardPenalty) + gs.revisitPenalty,
+		Timestamp:      time.Now().Unix(),
+		Success:        success,
+		MoveNumber:     gs.TotalMoves + 1,
+		RevisitPenalty: gs.revisitPenalty,
+		HazardHit:      gs.hazardHit,
+		WouldStrand:    gs.reserveBlocked,
+		ParkCollected:  gs.collectedPark,
 	}
+	gs.revisitPenalty = 0
+	gs.hazardHit, gs.hazardPenalty = false, 0
+	gs.reserveBlocked = false
+	gs.collectedPark = ""
 	// Append to cumulative history (never cleared by reset) and increment total
 	gs.MoveHistory = append(gs.MoveHistory, entry)
 	gs.TotalMoves++
@@ -179,4 +635,58 @@ func (gs *GameState) AddMoveToHistory(action string, fromPos, toPos Position, su
 	// Append to current segment history and increment its counter
 	gs.CurrentMoves = append(gs.CurrentMoves, entry)
 	gs.CurrentMovesCount++
+
+	gs.recordResult()
+}
+
+// moveCost is the battery a move spends before any charging at its destination.
+// Blocked moves only cost what a wall-crash penalty took, and waits what the
+// wait cost took, given by drained.
+func moveCost(action string, success bool, drained int) int {
+	if !success || action == ActionWait {
+		return max(drained, 0)
+	}
+	if action == ActionCharge || action == ActionPark || action == ActionTeleport {
+		return 0
+	}
+	return 1
+}
+
+// FillMissingBatteryDeltas fills BatteryDelta and Cost on history entries
+// saved before they were recorded. Such entries are successful moves with no
+// cost; their delta is derived from the previous entry's battery, which is
+// approximate across resets.
+func (gs *GameState) FillMissingBatteryDeltas() {
+	fill := func(entries []MoveHistoryEntry) {
+		for i := range entries {
+			e := &entries[i]
+			if e.Cost != 0 || moveCost(e.Action, e.Success, 0) == 0 {
+				continue
+			}
+			e.Cost = moveCost(e.Action, e.Success, 0)
+			if i > 0 {
+				e.BatteryDelta = e.Battery - entries[i-1].Battery
+			} else {
+				e.BatteryDelta = -e.Cost
+			}
+		}
+	}
+	fill(gs.MoveHistory)
+	fill(gs.CurrentMoves)
+}
+
+// annotateLastMove applies move metadata to the most recent history entries
+func (gs *GameState) annotateLastMove(meta MoveMeta) {
+	intent := meta.Intent
+	if runes := []rune(intent); len(runes) > MaxIntentLength {
+		intent = string(runes[:MaxIntentLength])
+	}
+	if n := len(gs.MoveHistory); n > 0 {
+		gs.MoveHistory[n-1].Autoplay = meta.Autoplay
+		gs.MoveHistory[n-1].Intent = intent
+	}
+	if n := len(gs.CurrentMoves); n > 0 {
+		gs.CurrentMoves[n-1].Autoplay = meta.Autoplay
+		gs.CurrentMoves[n-1].Intent = intent
+	}
 }
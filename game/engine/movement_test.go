@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -192,11 +193,69 @@ func TestMovePlayer_WallCrashEndsGame(t *testing.T) {
 	if !state.GameOver {
 		t.Error("Expected game to be over after wall crash")
 	}
+	if state.GameOverReason != GameOverWallCrash {
+		t.Errorf("Expected reason %q, got %q", GameOverWallCrash, state.GameOverReason)
+	}
 	if !strings.Contains(state.Message, "Hit wall!") {
 		t.Errorf("Expected hit wall message, got: %s", state.Message)
 	}
 }
 
+func TestMovePlayer_WallCrashBatteryPenalty(t *testing.T) {
+	state, config := createTestGameState()
+	config.WallCrashBatteryPenalty = 2
+	initialPos := state.PlayerPos
+
+	// Try to move into water
+	if state.MovePlayer("down", config) {
+		t.Error("Expected move to fail when hitting water")
+	}
+	if state.PlayerPos != initialPos {
+		t.Error("Position should not change when hitting obstacle")
+	}
+	if state.Battery != 3 {
+		t.Errorf("Expected crash to cost 2 battery leaving 3, got %d", state.Battery)
+	}
+	if state.GameOver {
+		t.Error("Expected game to continue after a penalized crash")
+	}
+	if !strings.Contains(state.Message, "crash cost 2 battery") {
+		t.Errorf("Expected crash cost in message, got: %s", state.Message)
+	}
+}
+
+func TestMovePlayer_WallCrashPenaltyStrands(t *testing.T) {
+	state, config := createTestGameState()
+	config.WallCrashBatteryPenalty = 3
+	engine, err := NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	state = engine.state
+
+	// Away from chargers with less battery than the penalty
+	state.PlayerPos = Position{X: 1, Y: 3}
+	state.Battery = 2
+
+	if engine.Move("down") {
+		t.Error("Expected move into building to fail")
+	}
+	if state.Battery != 0 {
+		t.Errorf("Expected penalty to floor battery at 0, got %d", state.Battery)
+	}
+	if !state.GameOver || state.GameOverReason != GameOverStranded {
+		t.Errorf("Expected stranded game over, got over=%v reason=%q", state.GameOver, state.GameOverReason)
+	}
+	if state.Message != config.Messages.Stranded {
+		t.Errorf("Expected stranded message, got: %s", state.Message)
+	}
+
+	entry := state.MoveHistory[len(state.MoveHistory)-1]
+	if entry.Success || entry.Cost != 2 || entry.BatteryDelta != -2 || entry.Battery != 0 {
+		t.Errorf("Expected failed entry with cost 2 and delta -2, got %+v", entry)
+	}
+}
+
 func TestMovePlayer_OutOfBattery(t *testing.T) {
 	state, config := createTestGameState()
 	state.Battery = 0
@@ -208,6 +267,9 @@ func TestMovePlayer_OutOfBattery(t *testing.T) {
 	if !state.GameOver {
 		t.Error("Expected game to be over when out of battery")
 	}
+	if state.GameOverReason != GameOverOutOfBattery {
+		t.Errorf("Expected reason %q, got %q", GameOverOutOfBattery, state.GameOverReason)
+	}
 	if state.Message != config.Messages.OutOfBattery {
 		t.Errorf("Expected out of battery message, got: %s", state.Message)
 	}
@@ -259,6 +321,241 @@ func TestMovePlayer_SuperchargerCharging(t *testing.T) {
 	}
 }
 
+func TestMovePlayer_IncrementalCharging(t *testing.T) {
+	state, config := createTestGameState()
+	config.ChargePerTurn = 2
+
+	// Leave home (2,1) and come back: arrival grants one increment
+	state.MovePlayer("left", config)
+	state.MovePlayer("right", config)
+	if state.Battery != 5 {
+		t.Fatalf("Expected 5-1-1+2=5 battery after arriving home, got %d", state.Battery)
+	}
+	if state.Message != config.Messages.HomeCharge {
+		t.Errorf("Expected home charge message, got: %s", state.Message)
+	}
+
+	// Charging in place adds 2 per turn, capped at max
+	expected := []int{7, 9, 10, 10}
+	for i, want := range expected {
+		if !state.MovePlayer(ActionCharge, config) {
+			t.Fatalf("Charge %d failed: %s", i+1, state.Message)
+		}
+		if state.Battery != want {
+			t.Errorf("After charge %d expected battery %d, got %d", i+1, want, state.Battery)
+		}
+	}
+	if state.PlayerPos != (Position{X: 2, Y: 1}) {
+		t.Errorf("Charging should not move the player, got %+v", state.PlayerPos)
+	}
+}
+
+func TestMovePlayer_GradualCharge(t *testing.T) {
+	state, config := createTestGameState()
+	config.GradualCharge = true
+	state.Battery = 3
+
+	// Idling on home (2,1) ramps the battery one unit per turn up to max
+	for turns := 0; state.Battery < config.MaxBattery; turns++ {
+		if turns == config.MaxBattery {
+			t.Fatalf("Battery stuck at %d", state.Battery)
+		}
+		before := state.Battery
+		if !state.MovePlayer(ActionCharge, config) {
+			t.Fatalf("Charge failed: %s", state.Message)
+		}
+		if state.Battery != before+1 {
+			t.Fatalf("Expected battery %d, got %d", before+1, state.Battery)
+		}
+		if state.Message != fmt.Sprintf("Home: charging (%d/%d)", state.Battery, config.MaxBattery) {
+			t.Errorf("Unexpected message: %s", state.Message)
+		}
+	}
+
+	// (1,1) borders home, so the move's cost is regained
+	state.MovePlayer("left", config)
+	if state.Battery != 10 || state.Message != "Next to charger: charging (10/10)" {
+		t.Errorf("Expected regen next to home, got battery=%d message=%q", state.Battery, state.Message)
+	}
+
+	// Leaving the charger stops charging
+	state.MovePlayer("down", config) // Road at (1,2)
+	if state.Battery != 9 {
+		t.Errorf("Expected 9 battery away from chargers, got %d", state.Battery)
+	}
+	if strings.Contains(state.Message, "charging") {
+		t.Errorf("Expected no charging message away from chargers, got: %s", state.Message)
+	}
+
+	// Arriving on a charger adds one unit instead of filling the battery
+	state.MovePlayer("up", config)
+	state.MovePlayer("right", config)
+	if state.Battery != 9 {
+		t.Errorf("Expected 9-1+1-1+1=9 battery back home, got %d", state.Battery)
+	}
+}
+
+func TestMovePlayer_ChargerCooldown(t *testing.T) {
+	state, config := createTestGameState()
+	config.Chargers = []ChargerLimit{{X: 2, Y: 1, Cooldown: 2}}
+	state.Chargers = newChargerStatus(config)
+	state.Battery = 3
+
+	if !state.MovePlayer(ActionCharge, config) || state.Battery != 10 {
+		t.Fatalf("Expected the first charge to fill the battery, got %d: %s", state.Battery, state.Message)
+	}
+
+	// The next turn is still within the cooldown
+	state.Battery = 4
+	if state.MovePlayer(ActionCharge, config) {
+		t.Fatal("Expected charging during the cooldown to fail")
+	}
+	if state.Message != "Home at (2,1) is cooling down: ready in 1 moves" || state.Battery != 4 {
+		t.Errorf("Unexpected cooldown result: battery=%d message=%q", state.Battery, state.Message)
+	}
+
+	// An invalid direction isn't a turn, so the cooldown doesn't run down
+	if state.MovePlayer("sideways", config) {
+		t.Fatal("Expected an invalid direction to fail")
+	}
+	if left := state.Chargers[0].CooldownLeft; left != 1 {
+		t.Fatalf("Expected the invalid direction to leave 1 move of cooldown, got %d", left)
+	}
+
+	// Two moves after the first charge it charges again
+	if !state.MovePlayer(ActionCharge, config) || state.Battery != 10 {
+		t.Errorf("Expected the charger ready after its cooldown, got %d: %s", state.Battery, state.Message)
+	}
+}
+
+func TestMovePlayer_ChargeOffCharger(t *testing.T) {
+	state, config := createTestGameState()
+	config.ChargePerTurn = 2
+
+	state.MovePlayer("left", config) // Road at (1,1)
+	battery := state.Battery
+
+	if state.MovePlayer(ActionCharge, config) {
+		t.Fatal("Expected charge to fail away from a charger")
+	}
+	if state.Message != "Can't charge: not on a charger at (1,1)" {
+		t.Errorf("Unexpected message: %s", state.Message)
+	}
+	if state.Battery != battery || state.GameOver {
+		t.Errorf("Failed charge should not change battery or end the game, got battery=%d game_over=%v", state.Battery, state.GameOver)
+	}
+}
+
+func TestMovePlayer_ChargeInstantMode(t *testing.T) {
+	state, config := createTestGameState()
+	state.Battery = 3
+
+	// Without charge_per_turn, charging on a charger fills the battery
+	if !state.MovePlayer(ActionCharge, config) {
+		t.Fatalf("Charge at home failed: %s", state.Message)
+	}
+	if state.Battery != config.MaxBattery {
+		t.Errorf("Expected full battery, got %d", state.Battery)
+	}
+}
+
+func TestChargerEffectFor_Defaults(t *testing.T) {
+	_, config := createTestGameState()
+	for _, tc := range []struct {
+		t    CellType
+		want ChargerEffect
+	}{
+		{Home, ChargerEffect{Instant: true}},
+		{Supercharger, ChargerEffect{Instant: true}},
+		{Trickle, ChargerEffect{Charge: DefaultTrickleCharge}},
+	} {
+		if got := ChargerEffectFor(tc.t, config); got != tc.want {
+			t.Errorf("%s: expected %+v, got %+v", tc.t, tc.want, got)
+		}
+	}
+
+	// Homes and superchargers follow charge_per_turn unless told otherwise
+	config.ChargePerTurn = 2
+	if got := ChargerEffectFor(Home, config); got != (ChargerEffect{Charge: 2}) {
+		t.Errorf("Expected home to charge 2 per turn, got %+v", got)
+	}
+}
+
+func TestMovePlayer_HomeMovePenalty(t *testing.T) {
+	state, config := createTestGameState()
+	config.ChargerEffects = map[CellType]ChargerEffect{Home: {MovePenalty: 2}}
+
+	// Leave home (2,1) and come back: the battery fills, then holds the car
+	state.MovePlayer("left", config)
+	state.MovePlayer("right", config)
+	if state.Battery != config.MaxBattery || state.ChargeHold != 2 {
+		t.Fatalf("Expected a full battery and a hold of 2, got battery=%d hold=%d", state.Battery, state.ChargeHold)
+	}
+
+	// An invalid direction isn't a turn, so it doesn't wear the hold down
+	if state.MovePlayer("sideways", config) || state.ChargeHold != 2 {
+		t.Fatalf("Expected an invalid direction to keep the hold of 2, got %d", state.ChargeHold)
+	}
+
+	if state.MovePlayer("left", config) {
+		t.Fatal("Expected the first move off the charger to be held")
+	}
+	if state.Message != "Can't move left: still charging, free to drive in 2 turns" {
+		t.Errorf("Unexpected message: %s", state.Message)
+	}
+	if !state.MovePlayer(ActionWait, config) || state.ChargeHold != 0 {
+		t.Fatalf("Expected waiting to use up the hold, got hold=%d: %s", state.ChargeHold, state.Message)
+	}
+	if !state.MovePlayer("left", config) || state.PlayerPos != (Position{X: 1, Y: 1}) {
+		t.Errorf("Expected to drive off once the hold is over, got %+v: %s", state.PlayerPos, state.Message)
+	}
+
+	// A charge that adds nothing doesn't hold the car
+	state.MovePlayer("right", config)
+	state.ChargeHold = 0
+	if !state.MovePlayer(ActionCharge, config) || state.ChargeHold != 0 {
+		t.Errorf("Expected no hold from charging a full battery, got %d", state.ChargeHold)
+	}
+}
+
+func TestMovePlayer_InstantSupercharger(t *testing.T) {
+	state, config := createTestGameState()
+	config.ChargePerTurn = 2
+	config.ChargerEffects = map[CellType]ChargerEffect{Supercharger: {Instant: true}}
+
+	// Home (2,1) charges 2 per turn, while the supercharger (3,2) fills
+	state.MovePlayer(ActionCharge, config)
+	if state.Battery != 7 {
+		t.Errorf("Expected home to add 2 battery, got %d", state.Battery)
+	}
+	state.MovePlayer("right", config) // Park at (3,1)
+	state.MovePlayer("down", config)  // Supercharger at (3,2)
+	if state.Battery != config.MaxBattery || state.Message != config.Messages.SuperchargerCharge {
+		t.Errorf("Expected the supercharger to fill the battery, got battery=%d message=%q", state.Battery, state.Message)
+	}
+}
+
+func TestMovePlayer_TrickleCharger(t *testing.T) {
+	state, config := createTestGameState()
+	config.Layout[2] = "BRWCB" // Trickle charger at (3,2)
+	state = InitGameStateFromConfig(config)
+	state.Battery = 5
+
+	state.MovePlayer("right", config) // Park at (3,1)
+	state.MovePlayer("down", config)  // Trickle charger at (3,2)
+	if state.Battery != 4 || state.Message != "Trickle charger: charging (4/10)" {
+		t.Fatalf("Expected arriving to trickle in 1 battery, got battery=%d message=%q", state.Battery, state.Message)
+	}
+	if !state.MovePlayer(ActionCharge, config) || state.Battery != 5 {
+		t.Errorf("Expected charging to add 1 battery, got %d: %s", state.Battery, state.Message)
+	}
+
+	config.ChargerEffects = map[CellType]ChargerEffect{Trickle: {Charge: 3}}
+	if !state.MovePlayer(ActionCharge, config) || state.Battery != 8 {
+		t.Errorf("Expected the configured trickle charge of 3, got %d: %s", state.Battery, state.Message)
+	}
+}
+
 func TestMovePlayer_ParkVisit(t *testing.T) {
 	state, config := createTestGameState()
 	initialScore := state.Score
@@ -319,6 +616,9 @@ func TestMovePlayer_Victory(t *testing.T) {
 	if !state.GameOver {
 		t.Error("Expected game to be over after victory")
 	}
+	if state.GameOverReason != GameOverVictory {
+		t.Errorf("Expected reason %q, got %q", GameOverVictory, state.GameOverReason)
+	}
 	if !strings.Contains(state.Message, "Victory") {
 		t.Errorf("Expected victory message, got: %s", state.Message)
 	}
@@ -337,11 +637,49 @@ func TestMovePlayer_Stranded(t *testing.T) {
 	if !state.GameOver {
 		t.Error("Expected game to be over when stranded")
 	}
+	if state.GameOverReason != GameOverStranded {
+		t.Errorf("Expected reason %q, got %q", GameOverStranded, state.GameOverReason)
+	}
 	if state.Message != config.Messages.Stranded {
 		t.Errorf("Expected stranded message, got: %s", state.Message)
 	}
 }
 
+func TestMovePlayer_RectangularGridEdges(t *testing.T) {
+	config := createRectangularConfig()
+	state := InitGameStateFromConfig(config)
+
+	move := func(dir string, times int) {
+		t.Helper()
+		for i := 0; i < times; i++ {
+			if !state.MovePlayer(dir, config) {
+				t.Fatalf("Move %s failed at %+v: %s", dir, state.PlayerPos, state.Message)
+			}
+		}
+	}
+
+	// Top long edge, west to east
+	move("left", 3)
+	move("up", 2)
+	move("right", 7)
+	if state.PlayerPos != (Position{X: 7, Y: 0}) {
+		t.Fatalf("Expected to reach top-right corner, got %+v", state.PlayerPos)
+	}
+	if state.MovePlayer("right", config) || state.PlayerPos.X != 7 {
+		t.Errorf("Expected east boundary to block at x=7, got %+v", state.PlayerPos)
+	}
+
+	// Bottom long edge, east to west
+	move("down", 4)
+	move("left", 7)
+	if state.PlayerPos != (Position{X: 0, Y: 4}) {
+		t.Fatalf("Expected to reach bottom-left corner, got %+v", state.PlayerPos)
+	}
+	if !state.Victory || state.Score != 4 {
+		t.Errorf("Expected victory with all 4 corner parks, got victory=%v score=%d", state.Victory, state.Score)
+	}
+}
+
 func TestCanReachCharger(t *testing.T) {
 	state, _ := createTestGameState()
 
@@ -419,7 +757,7 @@ func TestAddMoveToHistory(t *testing.T) {
 	// Record time before adding move
 	beforeTime := time.Now().Unix()
 
-	state.AddMoveToHistory("right", fromPos, toPos, true)
+	state.AddMoveToHistory("right", fromPos, toPos, state.Battery+1, true)
 
 	// Record time after adding move
 	afterTime := time.Now().Unix()
@@ -444,6 +782,9 @@ func TestAddMoveToHistory(t *testing.T) {
 	if move.Battery != state.Battery {
 		t.Errorf("Expected battery %d, got %d", state.Battery, move.Battery)
 	}
+	if move.BatteryDelta != -1 || move.Cost != 1 {
+		t.Errorf("Expected delta -1 and cost 1, got delta %d cost %d", move.BatteryDelta, move.Cost)
+	}
 	if !move.Success {
 		t.Error("Expected success to be true")
 	}
@@ -455,7 +796,7 @@ func TestAddMoveToHistory(t *testing.T) {
 	}
 
 	// Add another move to test incrementing
-	state.AddMoveToHistory("left", toPos, fromPos, false)
+	state.AddMoveToHistory("left", toPos, fromPos, state.Battery, false)
 
 	if len(state.MoveHistory) != 2 {
 		t.Errorf("Expected 2 moves in history, got %d", len(state.MoveHistory))
@@ -471,6 +812,41 @@ func TestAddMoveToHistory(t *testing.T) {
 	if secondMove.Success {
 		t.Error("Expected second move success to be false")
 	}
+	if secondMove.BatteryDelta != 0 || secondMove.Cost != 0 {
+		t.Errorf("Expected failed move to cost nothing, got delta %d cost %d", secondMove.BatteryDelta, secondMove.Cost)
+	}
+}
+
+func TestFillMissingBatteryDeltas(t *testing.T) {
+	state, _ := createTestGameState()
+	// History as saved before deltas were recorded
+	state.MoveHistory = []MoveHistoryEntry{
+		{Action: "left", Battery: 4, Success: true},
+		{Action: "up", Battery: 4, Success: false},
+		{Action: "right", Battery: 10, Success: true},
+		{Action: "right", Battery: 9, Success: true},
+	}
+	state.CurrentMoves = append([]MoveHistoryEntry(nil), state.MoveHistory...)
+
+	state.FillMissingBatteryDeltas()
+
+	wantDelta := []int{-1, 0, 6, -1}
+	wantCost := []int{1, 0, 1, 1}
+	for _, entries := range [][]MoveHistoryEntry{state.MoveHistory, state.CurrentMoves} {
+		for i, e := range entries {
+			if e.BatteryDelta != wantDelta[i] || e.Cost != wantCost[i] {
+				t.Errorf("Entry %d: expected delta %d cost %d, got delta %d cost %d",
+					i, wantDelta[i], wantCost[i], e.BatteryDelta, e.Cost)
+			}
+		}
+	}
+
+	// Entries that already carry a cost are left alone
+	state.MoveHistory[0].BatteryDelta = 3
+	state.FillMissingBatteryDeltas()
+	if state.MoveHistory[0].BatteryDelta != 3 {
+		t.Error("Expected recorded deltas to be preserved")
+	}
 }
 
 func TestCountTotalParks(t *testing.T) {
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ParkExpiryStatus is the live state of a park with a deadline. A park that
+// expires turns into road and no longer counts towards victory.
+type ParkExpiryStatus struct {
+	ParkID    string   `json:"park_id"`
+	Position  Position `json:"position"`
+	MovesLeft int      `json:"moves_left"` // Moves left to collect it in
+	Expired   bool     `json:"expired"`
+	// ExpiredOnMove is the number of the move it expired on, 0 while it hasn't
+	ExpiredOnMove int `json:"expired_on_move,omitempty"`
+}
+
+// validateParkExpiry checks that the park expiry names parks of the layout,
+// which must already be validated, with deadlines of at least one move
+func validateParkExpiry(config *GameConfig) error {
+	parks := layoutParkCount(config)
+	ids := make([]string, 0, len(config.ParkExpiry))
+	for id := range config.ParkExpiry {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !isLayoutPark(id, parks) {
+			return fmt.Errorf("config validation: park_expiry entry %q is not a park of the layout, which has park_0 to park_%d", id, parks-1)
+		}
+		if deadline := config.ParkExpiry[id]; deadline < 1 {
+			return fmt.Errorf("config validation: park_expiry deadline of %s must be at least 1 move, got %d", id, deadline)
+		}
+	}
+	return nil
+}
+
+// newParkExpiryStatus returns fresh status for the config's expiring parks
+// on grid, in layout order
+func newParkExpiryStatus(config *GameConfig, grid [][]Cell) []ParkExpiryStatus {
+	if config == nil || len(config.ParkExpiry) == 0 {
+		return nil
+	}
+	var status []ParkExpiryStatus
+	for y, row := range grid {
+		for x, cell := range row {
+			if deadline, ok := config.ParkExpiry[cell.ID]; ok && cell.Type == Park {
+				status = append(status, ParkExpiryStatus{ParkID: cell.ID, Position: Position{X: x, Y: y}, MovesLeft: deadline})
+			}
+		}
+	}
+	return status
+}
+
+// parkExpired reports whether the park with the given ID has expired
+func (gs *GameState) parkExpired(id string) bool {
+	for _, p := range gs.ParkExpiry {
+		if p.ParkID == id {
+			return p.Expired
+		}
+	}
+	return false
+}
+
+// tickParkExpiry counts one move towards the deadline of every park still
+// waiting to be collected
+func (gs *GameState) tickParkExpiry() {
+	for i := range gs.ParkExpiry {
+		if p := &gs.ParkExpiry[i]; !p.Expired && !gs.VisitedParks[p.ParkID] && p.MovesLeft > 0 {
+			p.MovesLeft--
+		}
+	}
+}
+
+// expireParks removes the parks whose deadline passed with the move just
+// made without being collected. The remaining parks decide the game: it is
+// won once they have all been collected, and lost when none is left.
+func (gs *GameState) expireParks(config *GameConfig) {
+	if gs.GameOver {
+		return
+	}
+	expired := false
+	for i := range gs.ParkExpiry {
+		p := &gs.ParkExpiry[i]
+		if p.Expired || p.MovesLeft > 0 || gs.VisitedParks[p.ParkID] {
+			continue
+		}
+		p.Expired = true
+		p.ExpiredOnMove = gs.TotalMoves + 1 // The move is recorded after it is made
+		gs.Grid[p.Position.Y][p.Position.X] = Cell{Type: Road}
+		gs.Message += fmt.Sprintf(" Park %s expired!", p.ParkID)
+		expired = true
+	}
+	if !expired {
+		return
+	}
+
+	gs.NextPark = gs.nextOrderedPark(config)
+	switch remaining := CountTotalParks(gs.Grid); {
+	case remaining == 0:
+		gs.EndGame(GameOverParksExpired)
+		gs.Message = "Every park expired before it was collected. Game Over!"
+	case gs.Score == remaining:
+		gs.checkVictory(config)
+	}
+}
+
+// ParksExpiredThisMove returns the parks that expired on the most recently
+// recorded move
+func (gs *GameState) ParksExpiredThisMove() []ParkExpiryStatus {
+	var expired []ParkExpiryStatus
+	for _, p := range gs.ParkExpiry {
+		if p.Expired && p.ExpiredOnMove == gs.TotalMoves {
+			expired = append(expired, p)
+		}
+	}
+	return expired
+}
+
+// clearExpiredParks turns expired parks into road, so a state rebuilt from
+// the config grid can't collect them
+func (gs *GameState) clearExpiredParks() {
+	for _, p := range gs.ParkExpiry {
+		if x, y := p.Position.X, p.Position.Y; p.Expired && gs.InBounds(x, y) && gs.Grid[y][x].Type == Park {
+			gs.Grid[y][x] = Cell{Type: Road}
+		}
+	}
+}
+
+// fillMissingParkExpiry starts the deadlines of states saved before the
+// config's expiring parks were tracked
+func (gs *GameState) fillMissingParkExpiry(config *GameConfig) {
+	if len(gs.ParkExpiry) != len(config.ParkExpiry) {
+		gs.ParkExpiry = newParkExpiryStatus(config, gs.Grid)
+	}
+}
@@ -0,0 +1,102 @@
+package engine
+
+import "fmt"
+
+// What reaching a park out of its config's order does
+const (
+	ParkOrderPolicyIgnore  = "ignore"  // Nothing: the park stays uncollected
+	ParkOrderPolicyPenalty = "penalty" // The park stays uncollected and the config's park order penalty is taken from the battery
+)
+
+// validateParkOrder checks the park order policy and that the park order
+// lists parks of the layout, which must already be validated, at most once
+func validateParkOrder(config *GameConfig) error {
+	if config.ParkOrderPenalty < 0 {
+		return fmt.Errorf("config validation: park_order_penalty must not be negative, got %d", config.ParkOrderPenalty)
+	}
+	switch config.ParkOrderPolicy {
+	case "", ParkOrderPolicyIgnore:
+	case ParkOrderPolicyPenalty:
+		if config.ParkOrderPenalty < 1 {
+			return fmt.Errorf("config validation: park_order_penalty must be at least 1 with the penalty policy, got %d", config.ParkOrderPenalty)
+		}
+	default:
+		return fmt.Errorf("config validation: park_order_policy must be %s or %s, got %q", ParkOrderPolicyIgnore, ParkOrderPolicyPenalty, config.ParkOrderPolicy)
+	}
+
+	parks := layoutParkCount(config)
+	seen := make(map[string]bool, len(config.ParkOrder))
+	for i, id := range config.ParkOrder {
+		if !isLayoutPark(id, parks) {
+			return fmt.Errorf("config validation: park_order entry %d %q is not a park of the layout, which has park_0 to park_%d", i+1, id, parks-1)
+		}
+		if seen[id] {
+			return fmt.Errorf("config validation: park_order lists %s more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// layoutParkCount counts the parks of the config's layout
+func layoutParkCount(config *GameConfig) int {
+	parks := 0
+	for _, row := range config.Layout {
+		for _, c := range row {
+			if c == 'P' {
+				parks++
+			}
+		}
+	}
+	return parks
+}
+
+// isLayoutPark reports whether id names one of a layout's parks, which are
+// numbered park_0 to park_<parks-1> in layout order
+func isLayoutPark(id string, parks int) bool {
+	var n int
+	_, err := fmt.Sscanf(id, "park_%d", &n)
+	return err == nil && n >= 0 && n < parks && id == fmt.Sprintf("park_%d", n)
+}
+
+// nextOrderedPark returns the first park of the config's park order that
+// hasn't been collected or expired, or "" once they all have or there is no
+// order
+func (gs *GameState) nextOrderedPark(config *GameConfig) string {
+	if config == nil {
+		return ""
+	}
+	for _, id := range config.ParkOrder {
+		if !gs.VisitedParks[id] && !gs.parkExpired(id) {
+			return id
+		}
+	}
+	return ""
+}
+
+// parkInOrder reports whether the park may be collected now: it is the next
+// one of the park order, or the order doesn't list it
+func (gs *GameState) parkInOrder(id string, config *GameConfig) bool {
+	next := gs.nextOrderedPark(config)
+	if next == "" || next == id {
+		return true
+	}
+	for _, ordered := range config.ParkOrder {
+		if ordered == id {
+			return false
+		}
+	}
+	return true
+}
+
+// skipOutOfOrderPark leaves a park reached out of order uncollected, taking
+// the config's penalty under the penalty policy
+func (gs *GameState) skipOutOfOrderPark(id string, config *GameConfig) {
+	gs.Message = fmt.Sprintf("Park %s is out of order: collect %s first", id, gs.NextPark)
+	if config.ParkOrderPolicy != ParkOrderPolicyPenalty {
+		return
+	}
+	lost := min(config.ParkOrderPenalty, gs.Battery)
+	gs.Battery -= lost
+	gs.Message += fmt.Sprintf(" (cost %d battery)", lost)
+}
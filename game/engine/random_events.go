@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RandomEventsConfig enables chance effects on the battery, rolled after each
+// successful directional move. Chances are probabilities from 0 to 1 and a
+// move gets at most one effect.
+type RandomEventsConfig struct {
+	DrainChance float64 `json:"drain_chance,omitempty"`
+	DrainAmount int     `json:"drain_amount,omitempty"` // Battery lost on a drain
+	SurgeChance float64 `json:"surge_chance,omitempty"`
+	SurgeAmount int     `json:"surge_amount,omitempty"` // Battery gained on a surge, capped at max
+	// Seed fixes the rolls for every game of the config; 0 picks a new seed per game
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// enabled reports whether any random event can happen
+func (rc *RandomEventsConfig) enabled() bool {
+	return rc != nil && (rc.DrainChance > 0 || rc.SurgeChance > 0)
+}
+
+// validate checks chances and amounts
+func (rc *RandomEventsConfig) validate() error {
+	if rc == nil {
+		return nil
+	}
+	if rc.DrainChance < 0 || rc.SurgeChance < 0 || rc.DrainChance+rc.SurgeChance > 1 {
+		return fmt.Errorf("config validation: random_events chances must be between 0 and 1 and add up to at most 1, got drain %g and surge %g",
+			rc.DrainChance, rc.SurgeChance)
+	}
+	if rc.DrainChance > 0 && rc.DrainAmount < 1 {
+		return fmt.Errorf("config validation: random_events drain_amount must be at least 1, got %d", rc.DrainAmount)
+	}
+	if rc.SurgeChance > 0 && rc.SurgeAmount < 1 {
+		return fmt.Errorf("config validation: random_events surge_amount must be at least 1, got %d", rc.SurgeAmount)
+	}
+	return nil
+}
+
+// newRandomSeed returns the random event seed for a new game of config, or 0
+// when the config has no random events
+func newRandomSeed(config *GameConfig) int64 {
+	if config == nil || !config.RandomEvents.enabled() {
+		return 0
+	}
+	if config.RandomEvents.Seed != 0 {
+		return config.RandomEvents.Seed
+	}
+	return time.Now().UnixNano()
+}
+
+// rollRandomEvent applies the random event, if any, for the move about to be
+// recorded as number TotalMoves+1 and returns its description. Each roll uses
+// a generator seeded with RandomSeed and the move number, so the same seed and
+// moves give the same events, including after the state is saved and loaded.
+func (gs *GameState) rollRandomEvent(config *GameConfig) string {
+	rc := config.RandomEvents
+	if !rc.enabled() || gs.GameOver {
+		return ""
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(gs.RandomSeed), uint64(gs.TotalMoves+1)))
+	roll := rng.Float64()
+
+	var description string
+	switch {
+	case roll < rc.DrainChance:
+		lost := min(rc.DrainAmount, gs.Battery)
+		gs.Battery -= lost
+		description = fmt.Sprintf("Battery drain: lost %d battery", lost)
+		if gs.Battery == 0 && !gs.CanReachCharger() && !gs.awaitingPark(config) {
+			gs.strand(config)
+		}
+	case roll < rc.DrainChance+rc.SurgeChance:
+		gained := min(rc.SurgeAmount, gs.MaxBattery-gs.Battery)
+		gs.Battery += gained
+		description = fmt.Sprintf("Battery surge: gained %d battery", gained)
+	default:
+		return ""
+	}
+
+	gs.Message = fmt.Sprintf("%s [%s]", gs.Message, description)
+	return description
+}
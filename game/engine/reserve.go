@@ -0,0 +1,65 @@
+package engine
+
+import "fmt"
+
+// wouldStrand reports whether moving in direction, which must lead onto a
+// passable cell, would leave the player without the battery to drive to any
+// charger that isn't depleted, counting the charging, fuel and penalties of
+// the cell it leads to. A winning move never strands, and neither does one
+// made when no charger was in reach to begin with, so the reserve can't
+// trap a player that is already short. Random events and hazards are left
+// out, since the move can't foresee them.
+func (gs *GameState) wouldStrand(direction string, config *GameConfig) bool {
+	if found, reach := gs.chargerDistance(); !found || reach > gs.Battery {
+		return false
+	}
+
+	relaxed := *config
+	relaxed.EnforceBatteryReserve = false
+	next := gs.Clone()
+	if !next.MovePlayer(direction, &relaxed) || next.Victory {
+		return false
+	}
+	if next.GameOver {
+		return true
+	}
+	found, reach := next.chargerDistance()
+	return !found || reach > next.Battery
+}
+
+// chargerDistance returns the number of moves along the roads from the
+// player to the nearest charger that isn't depleted, and whether one can be
+// reached at all
+func (gs *GameState) chargerDistance() (bool, int) {
+	seen := make([][]bool, len(gs.Grid))
+	for y := range gs.Grid {
+		seen[y] = make([]bool, len(gs.Grid[y]))
+	}
+	seen[gs.PlayerPos.Y][gs.PlayerPos.X] = true
+	queue := []Position{gs.PlayerPos}
+	for dist := 0; len(queue) > 0; dist++ {
+		var next []Position
+		for _, pos := range queue {
+			cell := gs.Grid[pos.Y][pos.X]
+			if IsCharger(cell.Type) && !gs.chargerDepleted(pos.X, pos.Y) {
+				return true, dist
+			}
+			for _, d := range []Position{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}} {
+				n := Position{X: pos.X + d.X, Y: pos.Y + d.Y}
+				if gs.CanMoveTo(n.X, n.Y) && !seen[n.Y][n.X] {
+					seen[n.Y][n.X] = true
+					next = append(next, n)
+				}
+			}
+		}
+		queue = next
+	}
+	return false, 0
+}
+
+// rejectStrandingMove turns down a move the battery reserve forbids,
+// leaving the player and battery where they are
+func (gs *GameState) rejectStrandingMove(direction string) {
+	gs.reserveBlocked = true
+	gs.Message = fmt.Sprintf("Can't move %s: it would strand you out of reach of a charger", direction)
+}
@@ -0,0 +1,102 @@
+package engine
+
+// GameOutcome is whether a finished game was won or lost
+type GameOutcome string
+
+const (
+	OutcomeVictory GameOutcome = "victory"
+	OutcomeDefeat  GameOutcome = "defeat"
+)
+
+// ResultReason is why a finished game ended, as reported in GameResult.
+// ResultMoveLimit and ResultManual are reserved, like the game over reasons
+// they stand for.
+type ResultReason string
+
+const (
+	ResultAllParks     ResultReason = "all_parks"
+	ResultOutOfBattery ResultReason = "out_of_battery"
+	ResultStranded     ResultReason = "stranded"
+	ResultWallCrash    ResultReason = "wall_crash"
+	ResultMoveLimit    ResultReason = "move_limit" // Reserved
+	ResultManual       ResultReason = "manual"     // Reserved
+	ResultHazard       ResultReason = "hazard"
+	ResultSurrendered  ResultReason = "surrendered"
+	ResultParksExpired ResultReason = "parks_expired"
+)
+
+// GameResult summarizes a finished game so clients don't have to parse the
+// free-text message. It is set on the move that ends the game and cleared by
+// reset.
+type GameResult struct {
+	Outcome        GameOutcome  `json:"outcome"`
+	Reason         ResultReason `json:"reason"`
+	MovesUsed      int          `json:"moves_used"`      // Successful moves this game
+	ElapsedMoves   int          `json:"elapsed_moves"`   // Every move this game, blocked ones included
+	ParksCollected int          `json:"parks_collected"` // Out of TotalParks
+	TotalParks     int          `json:"total_parks"`
+	FinalScore     int          `json:"final_score"`              // See GameState.FinalScore
+	ChargeCount    int          `json:"charge_count"`             // Charges that added battery
+	ChargePenalty  int          `json:"charge_penalty,omitempty"` // Points the charges cost under eco scoring
+	BatteryBonus   int          `json:"battery_bonus,omitempty"`  // Battery left on victory, scored under energy scoring
+}
+
+// resultReasons maps the engine's game over reasons to result reasons
+var resultReasons = map[GameOverReason]ResultReason{
+	GameOverVictory:      ResultAllParks,
+	GameOverOutOfBattery: ResultOutOfBattery,
+	GameOverStranded:     ResultStranded,
+	GameOverWallCrash:    ResultWallCrash,
+	GameOverMaxMoves:     ResultMoveLimit,
+	GameOverManual:       ResultManual,
+	GameOverHazard:       ResultHazard,
+	GameOverSurrendered:  ResultSurrendered,
+	GameOverParksExpired: ResultParksExpired,
+}
+
+// recordResult fills Result once the game is over; later calls keep the
+// first result. Games without a recorded reason, saved before reasons
+// existed, are reported as out of battery or victory from their flags.
+func (gs *GameState) recordResult() {
+	if !gs.GameOver || gs.Result != nil {
+		return
+	}
+
+	reason, ok := resultReasons[gs.GameOverReason]
+	if !ok {
+		reason = ResultOutOfBattery
+		if gs.Victory {
+			reason = ResultAllParks
+		}
+	}
+	outcome := OutcomeDefeat
+	if gs.Victory {
+		outcome = OutcomeVictory
+	}
+
+	result := &GameResult{
+		Outcome:       outcome,
+		Reason:        reason,
+		ElapsedMoves:  gs.CurrentMovesCount,
+		FinalScore:    gs.FinalScore(),
+		ChargeCount:   gs.ChargeCount,
+		ChargePenalty: gs.ChargePenalty,
+		BatteryBonus:  gs.batteryBonus(),
+	}
+	for _, move := range gs.CurrentMoves {
+		if move.Success {
+			result.MovesUsed++
+		}
+	}
+	for _, row := range gs.Grid {
+		for _, cell := range row {
+			if cell.Type == Park {
+				result.TotalParks++
+				if cell.Visited {
+					result.ParksCollected++
+				}
+			}
+		}
+	}
+	gs.Result = result
+}
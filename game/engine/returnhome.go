@@ -0,0 +1,27 @@
+package engine
+
+import "fmt"
+
+// checkVictory wins the game once every park is collected, and under the
+// config's must_return_home only on a home; elsewhere it sends the player
+// back home instead
+func (gs *GameState) checkVictory(config *GameConfig) {
+	if gs.GameOver || gs.Score != CountTotalParks(gs.Grid) {
+		return
+	}
+	if config.MustReturnHome && gs.Grid[gs.PlayerPos.Y][gs.PlayerPos.X].Type != Home {
+		gs.ReturnHome = true
+		gs.Message = fmt.Sprintf("All %d parks collected: return home to win!", gs.Score)
+		return
+	}
+	gs.ReturnHome = false
+	gs.Victory = true
+	gs.EndGame(GameOverVictory)
+	gs.Message = fmt.Sprintf(config.Messages.Victory, gs.Score)
+}
+
+// awaitingReturnHome reports whether only the trip home is left of a game
+// played under must_return_home
+func (gs *GameState) awaitingReturnHome(config *GameConfig) bool {
+	return config != nil && config.MustReturnHome && !gs.GameOver && gs.Score == CountTotalParks(gs.Grid)
+}
@@ -0,0 +1,102 @@
+package engine
+
+import "fmt"
+
+// Battery risk levels, from worst to best
+const (
+	RiskCritical = "CRITICAL" // Battery empty
+	RiskWarning  = "WARNING"  // No charger left to reach
+	RiskDanger   = "DANGER"   // Not enough battery to reach the nearest charger
+	RiskCaution  = "CAUTION"  // Within the caution margin of the nearest charger
+	RiskLow      = "LOW"      // At or below the low battery threshold
+	RiskSafe     = "SAFE"
+)
+
+// DefaultRiskCautionMargin is the battery above the distance to the nearest
+// charger that still counts as CAUTION
+const DefaultRiskCautionMargin = 2
+
+// RiskThresholds tunes the battery risk bands of a config. Zero fields keep
+// their defaults.
+type RiskThresholds struct {
+	// CautionMargin is how much battery beyond the distance to the nearest
+	// charger is still CAUTION; DefaultRiskCautionMargin by default
+	CautionMargin int `json:"caution_margin,omitempty"`
+	// LowBattery is the battery at or below which the player is LOW; a third
+	// of max battery by default
+	LowBattery int `json:"low_battery,omitempty"`
+}
+
+// validate checks the thresholds against the config's max battery
+func (rt *RiskThresholds) validate(maxBattery int) error {
+	if rt == nil {
+		return nil
+	}
+	if rt.CautionMargin < 0 {
+		return fmt.Errorf("config validation: risk_thresholds caution_margin must not be negative, got %d", rt.CautionMargin)
+	}
+	if rt.LowBattery < 0 || rt.LowBattery > maxBattery {
+		return fmt.Errorf("config validation: risk_thresholds low_battery must be between 0 and max_battery (%d), got %d", maxBattery, rt.LowBattery)
+	}
+	return nil
+}
+
+// RiskAssessment is the battery risk band of a state together with the
+// numbers behind it. The thresholds are battery levels at or below which
+// each band applies, worst band first.
+type RiskAssessment struct {
+	Level      string `json:"level"`
+	Message    string `json:"message"`
+	Battery    int    `json:"battery"`
+	MaxBattery int    `json:"max_battery"`
+	// ChargerDistance is the straight-line distance to the nearest usable
+	// charger, -1 when there is none
+	ChargerDistance int `json:"charger_distance"`
+	DangerAt        int `json:"danger_at"`
+	CautionAt       int `json:"caution_at"`
+	LowAt           int `json:"low_at"`
+}
+
+// AssessBatteryRisk rates the battery of state against the distance to the
+// nearest charger, using the risk thresholds of config, which may be nil
+func AssessBatteryRisk(state *GameState, config *GameConfig) RiskAssessment {
+	margin := DefaultRiskCautionMargin
+	low := state.MaxBattery / 3
+	if config != nil && config.RiskThresholds != nil {
+		if config.RiskThresholds.CautionMargin > 0 {
+			margin = config.RiskThresholds.CautionMargin
+		}
+		if config.RiskThresholds.LowBattery > 0 {
+			low = config.RiskThresholds.LowBattery
+		}
+	}
+
+	risk := RiskAssessment{
+		Battery:         state.Battery,
+		MaxBattery:      state.MaxBattery,
+		ChargerDistance: -1,
+		LowAt:           low,
+	}
+	_, distance, _, found := FindNearestCharger(state)
+	if found {
+		risk.ChargerDistance = distance
+		risk.DangerAt = distance
+		risk.CautionAt = distance + margin
+	}
+
+	switch {
+	case state.Battery <= 0:
+		risk.Level, risk.Message = RiskCritical, "CRITICAL: Battery empty!"
+	case !found:
+		risk.Level, risk.Message = RiskWarning, "WARNING: No chargers available!"
+	case state.Battery <= risk.DangerAt:
+		risk.Level, risk.Message = RiskDanger, "DANGER: Insufficient battery to reach nearest charger!"
+	case state.Battery <= risk.CautionAt:
+		risk.Level, risk.Message = RiskCaution, "CAUTION: Low battery, prioritize charging"
+	case state.Battery <= risk.LowAt:
+		risk.Level, risk.Message = RiskLow, "LOW: Consider charging soon"
+	default:
+		risk.Level, risk.Message = RiskSafe, "SAFE: Battery sufficient"
+	}
+	return risk
+}
@@ -0,0 +1,84 @@
+package engine
+
+import "fmt"
+
+// Scoring modes
+const (
+	ScoringStandard = "standard" // A point per park
+	ScoringEco      = "eco"      // EcoParkPoints per park, less a penalty per charge
+	ScoringEnergy   = "energy"   // A point per park, plus the battery left on victory
+)
+
+// EcoParkPoints is what a park is worth in eco scoring, so that the charge
+// penalty can stay small next to it
+const EcoParkPoints = 10
+
+// DefaultChargePenalty is the eco scoring penalty per charge when the
+// config sets none
+const DefaultChargePenalty = 1
+
+// validateScoring checks the scoring mode and charge penalty
+func validateScoring(config *GameConfig) error {
+	switch config.ScoringMode {
+	case "", ScoringStandard, ScoringEco, ScoringEnergy:
+	default:
+		return fmt.Errorf("config validation: scoring_mode must be %s, %s or %s, got %q",
+			ScoringStandard, ScoringEco, ScoringEnergy, config.ScoringMode)
+	}
+	if config.ChargePenalty < 0 {
+		return fmt.Errorf("config validation: charge_penalty must not be negative, got %d", config.ChargePenalty)
+	}
+	return nil
+}
+
+// chargePenalty returns the points a charge costs under the config's
+// scoring, 0 outside eco scoring
+func chargePenalty(config *GameConfig) int {
+	if config.ScoringMode != ScoringEco {
+		return 0
+	}
+	if config.ChargePenalty == 0 {
+		return DefaultChargePenalty
+	}
+	return config.ChargePenalty
+}
+
+// countCharge records a charge that added battery, with its eco penalty
+func (gs *GameState) countCharge(config *GameConfig) {
+	gs.ChargeCount++
+	gs.ChargePenalty += chargePenalty(config)
+}
+
+// batteryBonus returns the points the battery left is worth: all of it on
+// victory in energy scoring, nothing otherwise
+func (gs *GameState) batteryBonus() int {
+	if gs.ScoringMode != ScoringEnergy || !gs.Victory {
+		return 0
+	}
+	return gs.Battery
+}
+
+// ParkPoints returns what one park is worth under the game's scoring:
+// EcoParkPoints in eco scoring, a point otherwise
+func (gs *GameState) ParkPoints() int {
+	if gs.ScoringMode == ScoringEco {
+		return EcoParkPoints
+	}
+	return 1
+}
+
+// FinalScore returns what the game is worth so far: the parks collected,
+// in eco scoring EcoParkPoints per park less the charge penalty, or in
+// energy scoring the parks plus the battery bonus snapshotted at victory
+func (gs *GameState) FinalScore() int {
+	switch gs.ScoringMode {
+	case ScoringEco:
+		return gs.Score*gs.ParkPoints() - gs.ChargePenalty
+	case ScoringEnergy:
+		if gs.Result != nil {
+			return gs.Score + gs.Result.BatteryBonus
+		}
+		return gs.Score + gs.batteryBonus()
+	}
+	return gs.Score
+}
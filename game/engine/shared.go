@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownPlayer is returned for moves by a player who isn't in the shared game
+var ErrUnknownPlayer = errors.New("unknown player")
+
+// SharedPlayer is one car racing in a shared game
+type SharedPlayer struct {
+	ID       string   `json:"id"`
+	Position Position `json:"position"`
+	Battery  int      `json:"battery"`
+	Score    int      `json:"score"`
+	Moves    int      `json:"moves"`
+	Message  string   `json:"message"`
+	// Out is set once the player can no longer move, for the reason in OutReason
+	Out       bool           `json:"out"`
+	OutReason GameOverReason `json:"out_reason,omitempty"`
+	// StatsHidden is set on a copy sent to an opponent with the battery,
+	// score and message blanked out
+	StatsHidden bool `json:"stats_hidden,omitempty"`
+}
+
+// SharedGameState is the state of several players racing for the parks of one
+// grid. A park belongs to whoever collects it first; the others find it
+// already visited.
+type SharedGameState struct {
+	Grid       [][]Cell          `json:"grid"`
+	MaxBattery int               `json:"max_battery"`
+	Players    []*SharedPlayer   `json:"players"`     // In join order
+	ParkOwners map[string]string `json:"park_owners"` // Park ID -> ID of the player who collected it
+	TotalParks int               `json:"total_parks"`
+	GameOver   bool              `json:"game_over"`
+	Winners    []string          `json:"winners,omitempty"` // Highest scorers once the game is over; several on a tie
+	ConfigName string            `json:"config_name"`
+}
+
+// SharedGame runs a competitive game where every player moves on the same
+// grid. Each move follows the single-player rules on a game state the player
+// keeps between moves, so keys, charger cooldowns, charge holds, lives,
+// checkpoints, hazards and park expiry carry over as in GameEngine. The grid
+// and the collected parks are shared: a key or fuel cell picked up, or a park
+// expired, is gone for everyone. Players may share a cell. The game ends
+// when every park is collected or every player is out.
+type SharedGame struct {
+	config  *GameConfig
+	state   *SharedGameState
+	visited map[string]bool       // Collected parks, shared by every player's view
+	views   map[string]*GameState // Player ID -> the player's own game state
+}
+
+// NewSharedGame starts a shared game with every player at the start position
+func NewSharedGame(config *GameConfig, playerIDs []string) (*SharedGame, error) {
+	if err := ValidateGameConfig(config, WithMaxGridSize(GridSizeCeiling)); err != nil {
+		return nil, err
+	}
+	if len(playerIDs) < 2 || len(playerIDs) > MaxSharedPlayers {
+		return nil, fmt.Errorf("shared game needs 2 to %d players, got %d", MaxSharedPlayers, len(playerIDs))
+	}
+
+	initial := InitGameStateFromConfig(config)
+	state := &SharedGameState{
+		Grid:       initial.Grid,
+		MaxBattery: initial.MaxBattery,
+		ParkOwners: make(map[string]string),
+		TotalParks: CountTotalParks(initial.Grid),
+		ConfigName: config.Name,
+	}
+	views := make(map[string]*GameState, len(playerIDs))
+	visited := make(map[string]bool)
+	for _, id := range playerIDs {
+		if id == "" {
+			return nil, fmt.Errorf("player ID cannot be empty")
+		}
+		if views[id] != nil {
+			return nil, fmt.Errorf("duplicate player ID '%s'", id)
+		}
+		view := InitGameStateFromConfig(config)
+		view.Grid = state.Grid
+		view.VisitedParks = visited
+		views[id] = view
+		state.Players = append(state.Players, &SharedPlayer{
+			ID:       id,
+			Position: initial.PlayerPos,
+			Battery:  initial.Battery,
+			Message:  initial.Message,
+		})
+	}
+
+	return &SharedGame{config: config, state: state, visited: visited, views: views}, nil
+}
+
+// GetState returns the current shared game state
+func (g *SharedGame) GetState() *SharedGameState {
+	return g.state
+}
+
+// GetConfig returns the shared game's configuration
+func (g *SharedGame) GetConfig() *GameConfig {
+	return g.config
+}
+
+// Player returns the player with the given ID, or nil
+func (g *SharedGame) Player(playerID string) *SharedPlayer {
+	for _, p := range g.state.Players {
+		if p.ID == playerID {
+			return p
+		}
+	}
+	return nil
+}
+
+// Move moves one player like GameEngine.Move. It returns ErrUnknownPlayer
+// for a player not in the game; otherwise it reports whether the move was
+// made, with the player's Message explaining a failure.
+func (g *SharedGame) Move(playerID, direction string) (bool, error) {
+	p := g.Player(playerID)
+	if p == nil {
+		return false, fmt.Errorf("%w '%s'", ErrUnknownPlayer, playerID)
+	}
+	if g.state.GameOver {
+		p.Message = "Game over"
+		return false, nil
+	}
+	if p.Out {
+		p.Message = fmt.Sprintf("Player %s is out: %s", p.ID, p.OutReason)
+		return false, nil
+	}
+
+	// The player's view shares the grid and collected parks with everyone. Its
+	// score counts every collected park so the single-player victory check
+	// fires when the last park of the race is taken.
+	view := g.views[p.ID]
+	view.PlayerPos = p.Position
+	view.Battery = p.Battery
+	view.Score = len(g.visited)
+	success := view.MovePlayer(direction, g.config)
+
+	if len(g.visited) > len(g.state.ParkOwners) {
+		cell := g.state.Grid[view.PlayerPos.Y][view.PlayerPos.X]
+		g.state.ParkOwners[cell.ID] = p.ID
+		p.Score++
+		if !view.Victory {
+			view.Message = fmt.Sprintf(g.config.Messages.ParkVisited, p.Score)
+		}
+	}
+	p.Position = view.PlayerPos
+	p.Battery = view.Battery
+	p.Message = view.Message
+	p.Moves++
+	if view.GameOver && !view.Victory {
+		p.Out = true
+		p.OutReason = view.GameOverReason
+	}
+
+	g.checkGameOver()
+	return success, nil
+}
+
+// checkGameOver ends the game once every park is collected or nobody can move.
+// Parks that expired on the shared grid no longer count.
+func (g *SharedGame) checkGameOver() {
+	g.state.TotalParks = CountTotalParks(g.state.Grid)
+	active := 0
+	for _, p := range g.state.Players {
+		if !p.Out {
+			active++
+		}
+	}
+	if len(g.state.ParkOwners) < g.state.TotalParks && active > 0 {
+		return
+	}
+
+	g.state.GameOver = true
+	best := 0
+	for _, p := range g.state.Players {
+		best = max(best, p.Score)
+	}
+	g.state.Winners = nil
+	if best == 0 {
+		return // Nobody scored, nobody won
+	}
+	for _, p := range g.state.Players {
+		if p.Score == best {
+			g.state.Winners = append(g.state.Winners, p.ID)
+		}
+	}
+}
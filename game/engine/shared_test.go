@@ -0,0 +1,148 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSharedGame_ContendedPark(t *testing.T) {
+	game, err := NewSharedGame(createTestConfig(), []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create shared game: %v", err)
+	}
+
+	// Both start at home (2,1) next to park_0 at (3,1); alice gets there first
+	if ok, err := game.Move("alice", "right"); !ok || err != nil {
+		t.Fatalf("Expected alice's move to succeed, got %v %v", ok, err)
+	}
+	if ok, err := game.Move("bob", "right"); !ok || err != nil {
+		t.Fatalf("Expected bob's move to succeed, got %v %v", ok, err)
+	}
+
+	state := game.GetState()
+	alice, bob := game.Player("alice"), game.Player("bob")
+	if alice.Score != 1 || bob.Score != 0 {
+		t.Errorf("Expected only alice to score, got alice=%d bob=%d", alice.Score, bob.Score)
+	}
+	if state.ParkOwners["park_0"] != "alice" {
+		t.Errorf("Expected park_0 owned by alice, got %v", state.ParkOwners)
+	}
+	if bob.Position != alice.Position {
+		t.Errorf("Expected players to share the park cell, got %v and %v", alice.Position, bob.Position)
+	}
+	if bob.Message != game.GetConfig().Messages.ParkAlreadyVisited {
+		t.Errorf("Expected bob to find the park taken, got %q", bob.Message)
+	}
+	if state.GameOver {
+		t.Error("Game should continue while parks remain")
+	}
+}
+
+func TestSharedGame_EndsWhenParksCollected(t *testing.T) {
+	game, err := NewSharedGame(createTestConfig(), []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create shared game: %v", err)
+	}
+
+	game.Move("bob", "left")
+	for _, dir := range []string{"right", "down", "down", "left", "left"} {
+		game.Move("alice", dir)
+	}
+
+	state := game.GetState()
+	if !state.GameOver {
+		t.Fatal("Expected game over once every park is collected")
+	}
+	if !reflect.DeepEqual(state.Winners, []string{"alice"}) {
+		t.Errorf("Expected alice to win, got %v", state.Winners)
+	}
+	if ok, _ := game.Move("bob", "right"); ok {
+		t.Error("Expected moves after game over to fail")
+	}
+}
+
+func TestSharedGame_KeysCarryOver(t *testing.T) {
+	config := createTestConfig()
+	config.Layout = []string{
+		"BBBBB",
+		"BHDPB",
+		"BRBBB",
+		"BYBBB",
+		"BBBBB",
+	}
+	config.Keys = []LockTile{{ID: "gate", X: 1, Y: 3}}
+	config.Doors = []LockTile{{ID: "gate", X: 2, Y: 1}}
+	game, err := NewSharedGame(config, []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create shared game: %v", err)
+	}
+
+	// The key alice picks up on one move opens the door on a later one
+	for _, dir := range []string{"down", "down", "up", "up"} {
+		game.Move("alice", dir)
+	}
+	if ok, _ := game.Move("alice", "right"); !ok {
+		t.Fatalf("Expected alice's key to open the door, got %q", game.Player("alice").Message)
+	}
+	if alice := game.Player("alice"); alice.Position != (Position{X: 2, Y: 1}) {
+		t.Errorf("Expected alice on the door, got %v", alice.Position)
+	}
+
+	// The key is gone from the shared grid and bob never held it
+	if ok, _ := game.Move("bob", "right"); ok {
+		t.Error("Expected the door to stay locked for bob")
+	}
+	game.Move("bob", "down")
+	game.Move("bob", "down")
+	if bob := game.Player("bob"); bob.Message != `Key "gate" already collected` {
+		t.Errorf("Expected bob to find the key taken, got %q", bob.Message)
+	}
+
+	game.Move("alice", "right")
+	state := game.GetState()
+	if !state.GameOver || !reflect.DeepEqual(state.Winners, []string{"alice"}) {
+		t.Errorf("Expected alice to win past the door, got game over %v winners %v", state.GameOver, state.Winners)
+	}
+}
+
+func TestSharedGame_PlayerOut(t *testing.T) {
+	game, err := NewSharedGame(createTestConfig(), []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create shared game: %v", err)
+	}
+	bob := game.Player("bob")
+	bob.Position = Position{X: 1, Y: 2}
+	bob.Battery = 1
+
+	game.Move("bob", "up")
+	if !bob.Out || bob.OutReason != GameOverStranded {
+		t.Fatalf("Expected bob stranded, got out=%v reason=%q", bob.Out, bob.OutReason)
+	}
+	if game.GetState().GameOver {
+		t.Error("Game should continue while alice can move")
+	}
+	if ok, _ := game.Move("bob", "down"); ok {
+		t.Error("Expected an out player's move to fail")
+	}
+	if ok, _ := game.Move("alice", "right"); !ok {
+		t.Error("Expected alice to keep playing")
+	}
+}
+
+func TestSharedGame_Errors(t *testing.T) {
+	if _, err := NewSharedGame(createTestConfig(), []string{"solo"}); err == nil {
+		t.Error("Expected error for a single player")
+	}
+	if _, err := NewSharedGame(createTestConfig(), []string{"a", "a"}); err == nil {
+		t.Error("Expected error for duplicate player IDs")
+	}
+
+	game, err := NewSharedGame(createTestConfig(), []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create shared game: %v", err)
+	}
+	if _, err := game.Move("carol", "up"); !errors.Is(err, ErrUnknownPlayer) {
+		t.Errorf("Expected ErrUnknownPlayer, got %v", err)
+	}
+}
@@ -8,24 +8,89 @@ const (
 	Home         CellType = "home"
 	Park         CellType = "park"
 	Supercharger CellType = "supercharger"
+	Fuel         CellType = "fuel"       // One-off battery pickup; becomes road once used
+	Trickle      CellType = "trickle"    // Slow charger; adds a little battery per charge
+	Checkpoint   CellType = "checkpoint" // Respawn point for configs with lives
+	Key          CellType = "key"        // Opens the doors that share its lock ID once collected
+	Door         CellType = "door"       // Obstacle until the player holds its key
 	Water        CellType = "water"
 	Building     CellType = "building"
 
 	// Validation constants
 	MinGridSize         = 5
-	MaxGridSize         = 50
+	MaxGridSize         = 50  // Default limit, see WithMaxGridSize
+	GridSizeCeiling     = 200 // Largest limit WithMaxGridSize may raise MaxGridSize to
 	MinBattery          = 1
 	MaxBattery          = 100
 	MaxBulkMoves        = 50
+	MaxSharedPlayers    = 8   // Players in one shared game
+	MaxIntentLength     = 200 // Characters of move intent kept in history
 	UnreachableDistance = 999999
 	WebSocketBufferSize = 256
 )
 
+// History pages hold DefaultHistoryPageSize moves unless the request or the
+// config's DefaultHistoryPageSize asks otherwise, up to MaxHistoryPageSize
+const (
+	DefaultHistoryPageSize = 20
+	MaxHistoryPageSize     = 100
+)
+
+// ActionCharge is accepted in place of a direction: the player stays put and
+// charges while standing on a home or supercharger
+const ActionCharge = "charge"
+
+// ActionPark is accepted in place of a direction: the player stays put and
+// collects the uncollected park they are standing on. Configs with
+// RequireParkAction only collect parks this way.
+const ActionPark = "park"
+
+// ActionWait is accepted in place of a direction: the player stays put for a
+// turn, spending the config's WaitCost
+const ActionWait = "wait"
+
+// ActionTeleport is recorded in history for a debug teleport; players can't
+// send it as a move
+const ActionTeleport = "teleport"
+
+// GameOverReason explains why a game ended. GameOverMaxMoves and
+// GameOverManual are reserved for a move limit and an operator ending the
+// game; the engine has neither yet, so no game ends with them.
+type GameOverReason string
+
+const (
+	GameOverVictory      GameOverReason = "victory"
+	GameOverOutOfBattery GameOverReason = "out_of_battery"
+	GameOverStranded     GameOverReason = "stranded"
+	GameOverWallCrash    GameOverReason = "wall_crash"
+	GameOverMaxMoves     GameOverReason = "max_moves" // Reserved
+	GameOverManual       GameOverReason = "manual"    // Reserved
+	GameOverHazard       GameOverReason = "hazard"
+	GameOverSurrendered  GameOverReason = "surrendered"
+	GameOverParksExpired GameOverReason = "parks_expired" // Every park expired uncollected
+)
+
+// MoveOutcome summarizes what the last move did so clients can animate it
+// without comparing consecutive states
+type MoveOutcome string
+
+const (
+	MoveOutcomeMoved       MoveOutcome = "moved"
+	MoveOutcomeBlocked     MoveOutcome = "blocked"
+	MoveOutcomeCharged     MoveOutcome = "charged"
+	MoveOutcomeCollected   MoveOutcome = "collected"
+	MoveOutcomeWaited      MoveOutcome = "waited"
+	MoveOutcomeHazardHit   MoveOutcome = "hazard_hit"
+	MoveOutcomeVictory     MoveOutcome = "victory"
+	MoveOutcomeGameOver    MoveOutcome = "game_over"
+	MoveOutcomeWouldStrand MoveOutcome = "would_strand" // Turned down by the battery reserve
+)
+
 // Cell represents a single grid cell
 type Cell struct {
 	Type    CellType `json:"type"`
 	Visited bool     `json:"visited,omitempty"` // For parks
-	ID      string   `json:"id,omitempty"`      // Unique ID for parks
+	ID      string   `json:"id,omitempty"`      // Unique ID for parks; the lock ID of keys and doors
 }
 
 // Position represents x,y coordinates
@@ -36,15 +101,98 @@ type Position struct {
 
 // GameConfig represents the game configuration from JSON
 type GameConfig struct {
-	Name              string            `json:"name"`
-	Description       string            `json:"description"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Author, Version and Notes annotate the map for listings; they are
+	// optional and never fail validation, see NormalizeMetadata
+	Author            string            `json:"author,omitempty"`
+	Version           string            `json:"version,omitempty"`
+	Notes             string            `json:"notes,omitempty"`
 	GridSize          int               `json:"grid_size"`
+	GridWidth         int               `json:"grid_width,omitempty"`  // Overrides grid_size for columns
+	GridHeight        int               `json:"grid_height,omitempty"` // Overrides grid_size for rows
 	MaxBattery        int               `json:"max_battery"`
 	StartingBattery   int               `json:"starting_battery"`
 	Layout            []string          `json:"layout"`
 	Legend            map[string]string `json:"legend"`
 	WallCrashEndsGame bool              `json:"wall_crash_ends_game"`
-	Messages          struct {
+	// WallCrashBatteryPenalty is the battery lost on a blocked move when crashes don't end the game
+	WallCrashBatteryPenalty int `json:"wall_crash_battery_penalty,omitempty"`
+	ChargePerTurn           int `json:"charge_per_turn,omitempty"`    // Battery per charge turn; 0 fills instantly
+	AutoResetSeconds        int `json:"auto_reset_seconds,omitempty"` // Reset this long after game over; 0 disables
+	// ResetPreservesScore makes a reset keep the parks collected so far, for
+	// cumulative challenges over several attempts; a reset after victory
+	// still starts over
+	ResetPreservesScore bool `json:"reset_preserves_score,omitempty"`
+	// RequireParkAction makes entering a park leave it uncollected until the player parks there
+	RequireParkAction bool `json:"require_park_action,omitempty"`
+	// ChargeRequiresWait makes chargers that fill the battery add only
+	// PassThroughCharge to a player driving onto them; a wait there fills it
+	ChargeRequiresWait bool `json:"charge_requires_wait,omitempty"`
+	// MustReturnHome makes collecting every park only half the win: the game
+	// is won on reaching a home afterwards
+	MustReturnHome bool `json:"must_return_home,omitempty"`
+	// GradualCharge tops the battery up by a fixed amount per move on or next
+	// to a charger instead of filling it on arrival
+	GradualCharge bool `json:"gradual_charge,omitempty"`
+	// RandomEvents enables seeded battery drains and surges; nil disables them
+	RandomEvents *RandomEventsConfig `json:"random_events,omitempty"`
+	// RevisitPenalty is the extra battery lost on entering a cell already visited this game
+	RevisitPenalty int `json:"revisit_penalty,omitempty"`
+	// FuelAmount is the battery a fuel (F) tile grants, once
+	FuelAmount int `json:"fuel_amount,omitempty"`
+	// Lives lets a player who runs out of battery after reaching a checkpoint
+	// (K) respawn there with RespawnBattery (default half the max battery)
+	// instead of losing, that many times
+	Lives          int `json:"lives,omitempty"`
+	RespawnBattery int `json:"respawn_battery,omitempty"`
+	// Keys and Doors give every key (Y) and door (D) tile a lock ID; a door
+	// blocks the way until the player collects a key with its ID
+	Keys  []LockTile `json:"keys,omitempty"`
+	Doors []LockTile `json:"doors,omitempty"`
+	// WaitCost is the battery spent by waiting a turn in place
+	WaitCost int `json:"wait_cost,omitempty"`
+	// PrimaryHome is the home the game starts at; other homes are secondary.
+	// Without it the game starts at the last home of the layout.
+	PrimaryHome *Position `json:"primary_home,omitempty"`
+	// SecondaryHomeCharge is the battery a charge at a secondary home adds;
+	// 0 charges there like at the primary home
+	SecondaryHomeCharge int `json:"secondary_home_charge,omitempty"`
+	// Hazards patrol the grid, one step per move; running into one applies
+	// HazardPolicy, end_game by default, or takes HazardPenalty battery
+	Hazards       []Hazard `json:"hazards,omitempty"`
+	HazardPolicy  string   `json:"hazard_policy,omitempty"`
+	HazardPenalty int      `json:"hazard_penalty,omitempty"`
+	// ScoringMode is standard (the default), eco, which scores parks at
+	// EcoParkPoints and takes ChargePenalty points (default 1) per charge, or
+	// energy, which adds the battery left on victory to the parks
+	ScoringMode   string `json:"scoring_mode,omitempty"`
+	ChargePenalty int    `json:"charge_penalty,omitempty"`
+	// Chargers limits the uses or adds a cooldown to individual chargers
+	Chargers []ChargerLimit `json:"chargers,omitempty"`
+	// ChargerEffects changes how each kind of charger (home, supercharger,
+	// trickle) charges; kinds left out keep their defaults
+	ChargerEffects map[CellType]ChargerEffect `json:"charger_effects,omitempty"`
+	// ParkOrder lists parks, by ID, that must be collected in that order;
+	// reaching one out of order applies ParkOrderPolicy, ignore by default,
+	// or also takes ParkOrderPenalty battery. Unlisted parks may be
+	// collected any time.
+	ParkOrder        []string `json:"park_order,omitempty"`
+	ParkOrderPolicy  string   `json:"park_order_policy,omitempty"`
+	ParkOrderPenalty int      `json:"park_order_penalty,omitempty"`
+	// ParkExpiry gives parks, by ID, a deadline: a park still uncollected
+	// after that many moves of the game expires and no longer counts
+	// towards victory
+	ParkExpiry map[string]int `json:"park_expiry,omitempty"`
+	// EnforceBatteryReserve turns down moves that would leave too little
+	// battery to drive to any charger, instead of letting the player strand
+	EnforceBatteryReserve bool `json:"enforce_battery_reserve,omitempty"`
+	// RiskThresholds tunes the battery risk bands; nil keeps the defaults
+	RiskThresholds *RiskThresholds `json:"risk_thresholds,omitempty"`
+	// DefaultHistoryPageSize is the history page size for requests that
+	// don't give a limit; 0 keeps the global DefaultHistoryPageSize
+	DefaultHistoryPageSize int `json:"default_history_page_size,omitempty"`
+	Messages               struct {
 		Welcome            string `json:"welcome"`
 		HomeCharge         string `json:"home_charge"`
 		SuperchargerCharge string `json:"supercharger_charge"`
@@ -59,6 +207,19 @@ type GameConfig struct {
 	} `json:"messages"`
 }
 
+// Dimensions returns the grid width and height, falling back to GridSize
+// for either one that is not set
+func (c *GameConfig) Dimensions() (width, height int) {
+	width, height = c.GridWidth, c.GridHeight
+	if width == 0 {
+		width = c.GridSize
+	}
+	if height == 0 {
+		height = c.GridSize
+	}
+	return width, height
+}
+
 // SurroundingCell represents a cell with its absolute position
 type SurroundingCell struct {
 	X    int      `json:"x"`
@@ -68,19 +229,75 @@ type SurroundingCell struct {
 
 // GameState represents the complete game state
 type GameState struct {
-	Grid         [][]Cell           `json:"grid"`
-	PlayerPos    Position           `json:"player_pos"`
-	Battery      int                `json:"battery"`
-	MaxBattery   int                `json:"max_battery"`
-	Score        int                `json:"score"`
-	VisitedParks map[string]bool    `json:"visited_parks"`
-	Message      string             `json:"message"`
-	GameOver     bool               `json:"game_over"`
-	Victory      bool               `json:"victory"`
-	ConfigName   string             `json:"config_name"`
-	MoveHistory  []MoveHistoryEntry `json:"move_history"`
-	TotalMoves   int                `json:"total_moves"`
-	LocalView    []SurroundingCell  `json:"local_view,omitempty"` // 8 surrounding cells
+	Grid         [][]Cell        `json:"grid"`
+	PlayerPos    Position        `json:"player_pos"`
+	Battery      int             `json:"battery"`
+	MaxBattery   int             `json:"max_battery"`
+	Score        int             `json:"score"`
+	VisitedParks map[string]bool `json:"visited_parks"`
+	Message      string          `json:"message"`
+	GameOver     bool            `json:"game_over"`
+	Victory      bool            `json:"victory"`
+	// GameOverReason is set together with GameOver; clients should use it rather than parse Message
+	GameOverReason GameOverReason `json:"game_over_reason,omitempty"`
+	// Result summarizes the game once it is over
+	Result      *GameResult        `json:"result,omitempty"`
+	ConfigName  string             `json:"config_name"`
+	MoveHistory []MoveHistoryEntry `json:"move_history"`
+	TotalMoves  int                `json:"total_moves"`
+	LocalView   []SurroundingCell  `json:"local_view,omitempty"` // 8 surrounding cells
+	// RandomSeed drives the config's random events; kept across resets so a
+	// session replays the same events
+	RandomSeed int64 `json:"random_seed,omitempty"`
+	// Chargers tracks the remaining uses and cooldown of the config's limited chargers
+	Chargers []ChargerStatus `json:"chargers,omitempty"`
+	// ConsumedFuel lists the fuel tiles picked up this game, in order
+	ConsumedFuel []FuelPickup `json:"consumed_fuel,omitempty"`
+	// VisitedCells lists every cell the player has been on this game, in
+	// order of first visit; VisitedCellCount is its length
+	VisitedCells     []Position `json:"visited_cells,omitempty"`
+	VisitedCellCount int        `json:"visited_cell_count"`
+	// PrimaryHome is the home the game started at
+	PrimaryHome Position `json:"primary_home"`
+	// Hazards tracks where the config's patrolling hazards are, in config order
+	Hazards []HazardStatus `json:"hazards,omitempty"`
+	// ScoringMode is the config's; ChargeCount counts the charges that added
+	// battery this game and ChargePenalty the points they cost in eco scoring
+	ScoringMode   string `json:"scoring_mode,omitempty"`
+	ChargeCount   int    `json:"charge_count"`
+	ChargePenalty int    `json:"charge_penalty,omitempty"`
+	// NextPark is the park of the config's park order to collect next, empty
+	// without an order or once it is done
+	NextPark string `json:"next_park,omitempty"`
+	// ParkExpiry tracks the deadlines of the config's expiring parks
+	ParkExpiry []ParkExpiryStatus `json:"park_expiry,omitempty"`
+	// ReturnHome is set once every park is collected under the config's
+	// must_return_home while the player still has to reach a home
+	ReturnHome bool `json:"return_home,omitempty"`
+	// CarriedParks are the parks, sorted by ID, that the last reset kept
+	// collected under the config's reset_preserves_score
+	CarriedParks []string `json:"carried_parks,omitempty"`
+	// ChargeHold is the number of turns the player must still spend on the
+	// charger before driving off, see ChargerEffect.MovePenalty
+	ChargeHold int `json:"charge_hold,omitempty"`
+	// Lives are the respawns left of the config's lives; LastCheckpoint is
+	// where the next one puts the player, nil before reaching a checkpoint
+	Lives          int       `json:"lives"`
+	LastCheckpoint *Position `json:"last_checkpoint,omitempty"`
+	// CollectedKeys are the lock IDs of the keys picked up, in order; doors
+	// with these IDs are passable
+	CollectedKeys []string `json:"collected_keys,omitempty"`
+	// revisitPenalty is the penalty taken by the move being made, until it is recorded
+	revisitPenalty int
+	// hazardHit and hazardPenalty record a hazard hit by the move being made,
+	// and the battery it took, until the move is recorded
+	hazardHit     bool
+	hazardPenalty int
+	// reserveBlocked records a move the battery reserve turned down, until it is recorded
+	reserveBlocked bool
+	// collectedPark is the ID of the park collected by the move being made,
+	// until it is recorded
+	collectedPark string
 
 	// CurrentMoves tracks only the moves since the last reset. It mirrors MoveHistory entries
 	// but gets cleared on reset while MoveHistory remains cumulative.
@@ -88,8 +305,31 @@ type GameState struct {
 	CurrentMovesCount int                `json:"current_moves_count"`
 
 	// Computed helper views (not required for core game logic)
-	LocalView3x3 []string `json:"local_view_3x3,omitempty"`
-	BatteryRisk  string   `json:"battery_risk,omitempty"`
+	LocalView3x3   []string               `json:"local_view_3x3,omitempty"`
+	RelativeView   *RelativeView          `json:"relative_view,omitempty"` // LocalView3x3 turned to the heading
+	BatteryRisk    string                 `json:"battery_risk,omitempty"`
+	BatteryPercent int                    `json:"battery_percent"`         // Battery as a rounded percentage of MaxBattery
+	MovePreviews   map[string]MovePreview `json:"move_previews,omitempty"` // Keyed by possible direction
+	// LastMoveOutcome is empty until the first move after creation or reset
+	LastMoveOutcome MoveOutcome `json:"last_move_outcome,omitempty"`
+	// Crash is set when the last move ran into a building, water or the
+	// grid boundary, and CrashPos is the cell it tried to enter
+	Crash    bool      `json:"crash,omitempty"`
+	CrashPos *Position `json:"crash_pos,omitempty"`
+	// PersistenceDegraded is set while a save has failed and is being
+	// retried, so recent changes may be lost on restart
+	PersistenceDegraded bool `json:"persistence_degraded,omitempty"`
+	// View is set on copies whose grid was cropped around the player, see CropView
+	View *GridView `json:"view,omitempty"`
+}
+
+// MovePreview describes the outcome of a single move without applying it
+type MovePreview struct {
+	To           Position `json:"to"`
+	TileChar     string   `json:"tile_char"`
+	Charges      bool     `json:"charges"`
+	Park         bool     `json:"park"`
+	BatteryAfter int      `json:"battery_after"`
 }
 
 // MoveHistoryEntry represents a single move in the game history
@@ -97,8 +337,29 @@ type MoveHistoryEntry struct {
 	Action       string   `json:"action"`
 	FromPosition Position `json:"from_position"`
 	ToPosition   Position `json:"to_position"`
-	Battery      int      `json:"battery"`
+	Battery      int      `json:"battery"`       // Battery after the move
+	BatteryDelta int      `json:"battery_delta"` // Battery after minus before; positive when charged
+	Cost         int      `json:"cost"`          // Battery spent on movement, before any charging
 	Timestamp    int64    `json:"timestamp"`
 	Success      bool     `json:"success"`
 	MoveNumber   int      `json:"move_number"`
+	Autoplay     bool     `json:"autoplay,omitempty"`     // Move was issued by the server-side autoplay bot
+	Intent       string   `json:"intent,omitempty"`       // Caller's stated reason for the move
+	RandomEvent  string   `json:"random_event,omitempty"` // Random event that followed the move
+	// RevisitPenalty is the battery the move lost for re-entering a visited cell; it is included in Cost
+	RevisitPenalty int `json:"revisit_penalty,omitempty"`
+	// HazardHit is set when the player ran into a patrolling hazard on this move
+	HazardHit bool `json:"hazard_hit,omitempty"`
+	// WouldStrand is set when the battery reserve turned the move down, see
+	// GameConfig.EnforceBatteryReserve
+	WouldStrand bool `json:"would_strand,omitempty"`
+	// ParkCollected is the ID of the park the move collected, empty when it
+	// collected none, such as on re-entering a park already collected
+	ParkCollected string `json:"park_collected,omitempty"`
+}
+
+// MoveMeta carries optional annotations recorded on the history entry of a move
+type MoveMeta struct {
+	Autoplay bool
+	Intent   string // Truncated to MaxIntentLength characters
 }
@@ -50,7 +50,7 @@ func FindNearestUnvisitedPark(state *GameState) (Position, int, bool) {
 	return nearestPos, minDistance, found
 }
 
-// FindNearestCharger finds the closest charging station (Home or Supercharger) and returns position, distance, and type
+// FindNearestCharger finds the closest charging station (Home, Supercharger or Trickle) and returns position, distance, and type
 func FindNearestCharger(state *GameState) (Position, int, CellType, bool) {
 	minDistance := -1
 	var nearestPos Position
@@ -60,7 +60,7 @@ func FindNearestCharger(state *GameState) (Position, int, CellType, bool) {
 	for y := 0; y < len(state.Grid); y++ {
 		for x := 0; x < len(state.Grid[y]); x++ {
 			cell := state.Grid[y][x]
-			if cell.Type == Home || cell.Type == Supercharger {
+			if IsCharger(cell.Type) && !state.chargerDepleted(x, y) {
 				pos := Position{X: x, Y: y}
 				distance := ManhattanDistance(state.PlayerPos, pos)
 				if minDistance == -1 || distance < minDistance {
@@ -78,24 +78,16 @@ func FindNearestCharger(state *GameState) (Position, int, CellType, bool) {
 
 // AnalyzeBatteryRisk assesses battery danger level based on current battery and distance to nearest charger
 func AnalyzeBatteryRisk(state *GameState) string {
-	if state.Battery <= 0 {
-		return "CRITICAL: Battery empty!"
-	}
-
-	_, chargerDistance, _, chargerFound := FindNearestCharger(state)
-	if !chargerFound {
-		return "WARNING: No chargers available!"
-	}
+	return AssessBatteryRisk(state, nil).Message
+}
 
-	if state.Battery <= chargerDistance {
-		return "DANGER: Insufficient battery to reach nearest charger!"
-	} else if state.Battery <= chargerDistance+2 {
-		return "CAUTION: Low battery, prioritize charging"
-	} else if state.Battery <= state.MaxBattery/3 {
-		return "LOW: Consider charging soon"
+// BatteryPercent returns battery as a whole percentage of maxBattery, rounding
+// halves up; a zero max reports 0
+func BatteryPercent(battery, maxBattery int) int {
+	if maxBattery <= 0 {
+		return 0
 	}
-
-	return "SAFE: Battery sufficient"
+	return (200*battery + maxBattery) / (2 * maxBattery)
 }
 
 // CountCellType counts the total number of cells of a specific type in the grid
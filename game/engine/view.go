@@ -0,0 +1,70 @@
+package engine
+
+// GridView describes the window a cropped state's grid covers. Cell
+// Grid[y][x] of the cropped state is cell (Origin.X+x, Origin.Y+y) of the
+// full grid; every other position in the state stays in full grid
+// coordinates.
+type GridView struct {
+	Origin Position `json:"origin"`
+	Radius int      `json:"radius"`
+	// Width and Height are the size of the full grid
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// RelativeView is the 3x3 window around the player turned so their heading
+// points up: Rows[0] is the row ahead, and each row runs from the player's
+// left to their right. Forward, Left, Right and Back are the cells next to
+// the player in those directions.
+type RelativeView struct {
+	Heading string   `json:"heading"` // Direction of the last move, up before any
+	Rows    []string `json:"rows"`
+	Forward string   `json:"forward"`
+	Left    string   `json:"left"`
+	Right   string   `json:"right"`
+	Back    string   `json:"back"`
+}
+
+// Heading returns the direction of the player's last successful move since
+// the last reset, or up when there is none
+func (gs *GameState) Heading() string {
+	for i := len(gs.CurrentMoves) - 1; i >= 0; i-- {
+		switch m := gs.CurrentMoves[i]; m.Action {
+		case "up", "down", "left", "right":
+			if m.Success {
+				return m.Action
+			}
+		}
+	}
+	return "up"
+}
+
+// CropView returns a copy of the state whose grid holds only the cells
+// within radius of the player, in both directions, clipped at the grid's
+// edges. The state itself keeps the full grid.
+func (gs *GameState) CropView(radius int) *GameState {
+	height := len(gs.Grid)
+	width := 0
+	if height > 0 {
+		width = len(gs.Grid[0])
+	}
+	minX, minY, maxX, maxY := cropBounds(gs.PlayerPos, radius, width, height)
+
+	cp := *gs
+	cp.Grid = make([][]Cell, 0, maxY-minY+1)
+	for y := minY; y <= maxY; y++ {
+		cp.Grid = append(cp.Grid, append([]Cell(nil), gs.Grid[y][minX:maxX+1]...))
+	}
+	cp.View = &GridView{Origin: Position{X: minX, Y: minY}, Radius: radius, Width: width, Height: height}
+	return &cp
+}
+
+// cropBounds returns the first and last column and row within radius of
+// center on a width by height grid
+func cropBounds(center Position, radius, width, height int) (minX, minY, maxX, maxY int) {
+	minX = max(center.X-radius, 0)
+	minY = max(center.Y-radius, 0)
+	maxX = min(center.X+radius, width-1)
+	maxY = min(center.Y+radius, height-1)
+	return minX, minY, maxX, maxY
+}
@@ -0,0 +1,52 @@
+package engine
+
+// markVisited records the player's cell as visited this game and reports
+// whether it had been visited before
+func (gs *GameState) markVisited() bool {
+	visited := gs.hasVisited(gs.PlayerPos)
+	gs.addVisit(gs.PlayerPos)
+	return visited
+}
+
+// hasVisited reports whether the player has been on pos this game
+func (gs *GameState) hasVisited(pos Position) bool {
+	for _, visited := range gs.VisitedCells {
+		if visited == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// chargeRevisit takes the config's revisit penalty, down to an empty
+// battery, when the player steps back onto a cell visited this game
+func (gs *GameState) chargeRevisit(config *GameConfig) {
+	if !gs.markVisited() || config.RevisitPenalty == 0 {
+		return
+	}
+	gs.revisitPenalty = min(config.RevisitPenalty, gs.Battery)
+	gs.Battery -= gs.revisitPenalty
+}
+
+// fillMissingVisits rebuilds the visited cells of saves from before they
+// were tracked, from the start of the current moves and where they led
+func (gs *GameState) fillMissingVisits() {
+	if gs.VisitedCells != nil {
+		return
+	}
+	for _, move := range gs.CurrentMoves {
+		gs.addVisit(move.FromPosition)
+		if move.Success {
+			gs.addVisit(move.ToPosition)
+		}
+	}
+	gs.addVisit(gs.PlayerPos)
+}
+
+// addVisit records pos as visited unless it already is
+func (gs *GameState) addVisit(pos Position) {
+	if !gs.hasVisited(pos) {
+		gs.VisitedCells = append(gs.VisitedCells, pos)
+	}
+	gs.VisitedCellCount = len(gs.VisitedCells)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// DefaultAnalyticsTTL is how long config analytics are served from the cache,
+// see WithAnalyticsCache
+const DefaultAnalyticsTTL = 30 * time.Second
+
+// WithAnalyticsCache sets how long the analytics of a config are reused before
+// they are aggregated over its sessions again. Zero disables the cache.
+func WithAnalyticsCache(ttl time.Duration) Option {
+	return func(s *gameServiceImpl) {
+		s.analyticsTTL = ttl
+	}
+}
+
+// GetConfigAnalytics aggregates the current game of every session playing the
+// config; a config nobody has played yet reports zeros
+func (s *gameServiceImpl) GetConfigAnalytics(ctx context.Context, configName string) (*ConfigAnalytics, error) {
+	config, err := s.configs.LoadConfig(configName)
+	if err != nil {
+		return nil, fmt.Errorf("config '%s' not found: %w", configName, err)
+	}
+
+	s.analyticsMu.Lock()
+	defer s.analyticsMu.Unlock()
+	if cached := s.analytics[configName]; cached != nil && time.Since(cached.GeneratedAt) < s.analyticsTTL {
+		return cached, nil
+	}
+
+	analytics := &ConfigAnalytics{
+		ConfigName:      configName,
+		GameOverReasons: map[engine.ResultReason]int{},
+		GeneratedAt:     time.Now(),
+	}
+	movesToWin, parks := 0, 0
+	s.mu.RLock()
+	for _, sess := range s.sessions.List() {
+		if sess.Config.Name != config.Name {
+			continue
+		}
+		analytics.Sessions++
+		result := sess.Engine.GetState().Result
+		if result == nil {
+			continue
+		}
+		analytics.FinishedGames++
+		analytics.GameOverReasons[result.Reason]++
+		parks += result.ParksCollected
+		if result.Outcome == engine.OutcomeVictory {
+			analytics.Wins++
+			movesToWin += result.MovesUsed
+		}
+	}
+	s.mu.RUnlock()
+
+	if analytics.FinishedGames > 0 {
+		analytics.WinRate = float64(analytics.Wins) / float64(analytics.FinishedGames)
+		analytics.AverageParksCollected = float64(parks) / float64(analytics.FinishedGames)
+	}
+	if analytics.Wins > 0 {
+		analytics.AverageMovesToWin = float64(movesToWin) / float64(analytics.Wins)
+	}
+	for reason, n := range analytics.GameOverReasons {
+		best := analytics.GameOverReasons[analytics.MostCommonGameOverReason]
+		if n > best || n == best && reason < analytics.MostCommonGameOverReason {
+			analytics.MostCommonGameOverReason = reason
+		}
+	}
+
+	if s.analyticsTTL > 0 {
+		s.analytics[configName] = analytics
+	}
+	return analytics, nil
+}
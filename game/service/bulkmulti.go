@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BulkMoveMulti runs the bulk move on every session concurrently. Each
+// session's moves are applied under the service lock like any bulk move, so
+// sessions never see each other's moves half done. A session that can't be
+// moved, e.g. because it doesn't exist, fails on its own; only an empty or
+// repeating list fails the call. Idempotency keys are per session and
+// ignored here.
+func (s *gameServiceImpl) BulkMoveMulti(ctx context.Context, sessionIDs []string, moves []string, opts BulkMoveOptions) (*MultiBulkMoveResult, error) {
+	if len(sessionIDs) == 0 {
+		return nil, ErrInvalidSessionList
+	}
+	seen := make(map[string]bool, len(sessionIDs))
+	for _, id := range sessionIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("%w, got '%s' twice", ErrInvalidSessionList, id)
+		}
+		seen[id] = true
+	}
+	opts.IdempotencyKey = ""
+
+	results := make([]*BulkMoveResult, len(sessionIDs))
+	errs := make([]error, len(sessionIDs))
+	var wg sync.WaitGroup
+	for i, id := range sessionIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = s.BulkMoveWithOptions(ctx, id, moves, opts)
+		}()
+	}
+	wg.Wait()
+
+	multi := &MultiBulkMoveResult{Results: make(map[string]*BulkMoveResult, len(sessionIDs))}
+	for i, id := range sessionIDs {
+		if errs[i] != nil {
+			if multi.Errors == nil {
+				multi.Errors = make(map[string]string)
+			}
+			multi.Errors[id] = errs[i].Error()
+			multi.Failed++
+			continue
+		}
+		multi.Results[id] = results[i]
+		multi.Succeeded++
+	}
+	return multi, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// DefaultEventLogSize is how many recent events each session keeps, see
+// WithEventLog
+const DefaultEventLogSize = 200
+
+// WithEventLog sets how many of its most recent events each session keeps in
+// its event log, for debugging agent runs; older events are dropped as new
+// ones arrive. Zero disables the log.
+func WithEventLog(size int) Option {
+	return func(s *gameServiceImpl) {
+		s.eventLogSize = size
+	}
+}
+
+// logEvents appends events to a session's event log, dropping the oldest
+// beyond the log size; callers hold s.mu
+func (s *gameServiceImpl) logEvents(sess *Session, events []GameEvent) {
+	if s.eventLogSize <= 0 || len(events) == 0 {
+		return
+	}
+	sess.EventLog = append(sess.EventLog, events...)
+	if over := len(sess.EventLog) - s.eventLogSize; over > 0 {
+		sess.EventLog = append(sess.EventLog[:0], sess.EventLog[over:]...)
+	}
+}
+
+// GetEventLog returns a copy of a session's recent events, oldest first
+func (s *gameServiceImpl) GetEventLog(ctx context.Context, sessionID string) (*EventLogResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	events := slices.Clone(sess.EventLog)
+	if events == nil {
+		events = []GameEvent{}
+	}
+	return &EventLogResponse{SessionID: sessionID, Count: len(events), Events: events}, nil
+}
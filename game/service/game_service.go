@@ -2,32 +2,122 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/wricardo/tesla-road-trip-game/game/engine"
 )
 
+// ErrConfigMismatch is returned when comparing sessions that play different configs
+var ErrConfigMismatch = errors.New("sessions use different configs")
+
+// ErrEmptySessionFilter is returned by DeleteSessions when no filter is set,
+// so a missing parameter can't wipe every session
+var ErrEmptySessionFilter = errors.New("at least one session filter is required")
+
+// ErrInvalidLeaderboardSort is returned by GetLeaderboard for an unknown sort
+var ErrInvalidLeaderboardSort = errors.New("leaderboard sort must be score or moves")
+
+// ErrInvalidSessionList is returned by BulkMoveMulti when the session IDs
+// are empty or repeat one
+var ErrInvalidSessionList = errors.New("session_ids must list at least one session, each once")
+
+// ErrMoveNotFound is returned by GetMove for an index outside the history
+var ErrMoveNotFound = errors.New("move not found")
+
+// ErrTargetUnreachable is returned by MoveToward when no road leads from the
+// player to the target
+var ErrTargetUnreachable = errors.New("target is unreachable")
+
+// ErrAlreadyAtTarget is returned by MoveToward when the player is already on
+// the target
+var ErrAlreadyAtTarget = errors.New("already at the target")
+
+// ErrLabelTooLong is returned by SetSessionLabel for labels longer than
+// MaxSessionLabelLength
+var ErrLabelTooLong = fmt.Errorf("label must be at most %d characters", MaxSessionLabelLength)
+
+// MaxSessionLabelLength caps a session label, in characters
+const MaxSessionLabelLength = 80
+
 // GameService defines all game-related operations
 type GameService interface {
 	// Session Management
 	CreateSession(ctx context.Context, configName string) (*SessionInfo, error)
+	CreateSessionWithOptions(ctx context.Context, configName string, opts CreateSessionOptions) (*SessionInfo, error)
 	GetSession(ctx context.Context, sessionID string) (*SessionInfo, error)
 	ListSessions(ctx context.Context) ([]*SessionInfo, error)
 	DeleteSession(ctx context.Context, sessionID string) error
+	CloneSession(ctx context.Context, sessionID string, copyHistory bool) (*SessionInfo, error)
+	DeleteSessions(ctx context.Context, filter SessionFilter) ([]string, error)
+	// SetSessionLabel sets the session's user-facing label; an empty label
+	// clears it
+	SetSessionLabel(ctx context.Context, sessionID, label string) (*SessionInfo, error)
 
 	// Game Operations
 	Move(ctx context.Context, sessionID, direction string, reset bool) (*MoveResult, error)
+	MoveWithOptions(ctx context.Context, sessionID, direction string, opts MoveOptions) (*MoveResult, error)
 	BulkMove(ctx context.Context, sessionID string, moves []string, reset bool) (*BulkMoveResult, error)
+	BulkMoveWithOptions(ctx context.Context, sessionID string, moves []string, opts BulkMoveOptions) (*BulkMoveResult, error)
+	// BulkMoveMulti applies the same moves to each session, reporting the
+	// sessions that couldn't be moved alongside the results of the rest
+	BulkMoveMulti(ctx context.Context, sessionIDs []string, moves []string, opts BulkMoveOptions) (*MultiBulkMoveResult, error)
 	Reset(ctx context.Context, sessionID string) (*engine.GameState, error)
+	// ResetAndReplay resets the game and replays its first keepMoves moves,
+	// clamped to the moves made since the last reset
+	ResetAndReplay(ctx context.Context, sessionID string, keepMoves int) (*engine.GameState, error)
+	Teleport(ctx context.Context, sessionID string, x, y int) (*MoveResult, error)
+	// MoveToward takes the first step of a shortest drive to (x, y),
+	// without moving when the target can't be reached
+	MoveToward(ctx context.Context, sessionID string, x, y int) (*MoveTowardResult, error)
+	// Surrender gives up the session's game as a defeat; unlike delete, the
+	// session and its history stay for review
+	Surrender(ctx context.Context, sessionID string) (*engine.GameState, error)
 
 	// Game State
 	GetGameState(ctx context.Context, sessionID string) (*engine.GameState, error)
 	GetMoveHistory(ctx context.Context, sessionID string, opts HistoryOptions) (*HistoryResponse, error)
+	// GetMove returns the move at a 1-based index into the cumulative history
+	GetMove(ctx context.Context, sessionID string, index int) (*MoveDetail, error)
+	// GetEventLog returns the session's most recent events, oldest first
+	GetEventLog(ctx context.Context, sessionID string) (*EventLogResponse, error)
+	GetParks(ctx context.Context, sessionID string) (*ParksResponse, error)
+	// GetBatteryRisk returns the session's battery risk band with the
+	// thresholds behind it
+	GetBatteryRisk(ctx context.Context, sessionID string) (*engine.RiskAssessment, error)
+	GetSessionConfig(ctx context.Context, sessionID string) (*SessionConfig, error)
+	CompareSessions(ctx context.Context, sessionA, sessionB string) (*SessionComparison, error)
+	GetLeaderboard(ctx context.Context, opts LeaderboardOptions) (*Leaderboard, error)
+	GetGhost(ctx context.Context, sessionID, fromSessionID string) (*GhostPath, error)
+	SolveGame(ctx context.Context, sessionID string) (*SolveResult, error)
+	// SolvePlan is SolveGame with every move annotated with the park or
+	// charger it heads for; the plan is empty when the game is already won
+	SolvePlan(ctx context.Context, sessionID string) ([]PlanStep, error)
+	// VerifySession replays the current game's moves on a fresh engine and
+	// reports the first field where the result differs from the live state
+	VerifySession(ctx context.Context, sessionID string) (*VerifyResult, error)
+
+	// Shared sessions
+	CreateSharedSession(ctx context.Context, configName string, playerIDs []string) (*SharedSessionInfo, error)
+	CreateSharedSessionWithOptions(ctx context.Context, configName string, playerIDs []string, opts SharedSessionOptions) (*SharedSessionInfo, error)
+	GetSharedSession(ctx context.Context, sessionID string) (*SharedSessionInfo, error)
+	MoveInShared(ctx context.Context, sessionID, playerID, direction string) (*SharedMoveResult, error)
 
 	// Configuration
 	ListConfigs(ctx context.Context) ([]*ConfigInfo, error)
 	LoadConfig(ctx context.Context, configName string) (*engine.GameConfig, error)
 	SaveConfig(ctx context.Context, configName string, config *engine.GameConfig) error
+	AnalyzeConfig(ctx context.Context, configName string) (*engine.ConfigAnalysis, error)
+	// GetConfigAnalytics aggregates win rate, moves and game over reasons
+	// over every session playing the config
+	GetConfigAnalytics(ctx context.Context, configName string) (*ConfigAnalytics, error)
+	// PreviewConfig returns the state a new game of the config starts in,
+	// without creating a session
+	PreviewConfig(ctx context.Context, configName string) (*engine.GameState, error)
+
+	// Ready reports why the service can't serve games yet, or nil once it can
+	Ready(ctx context.Context) error
 }
 
 // SessionManager defines session storage operations
@@ -41,6 +131,12 @@ type SessionManager interface {
 	Save(id string) error
 }
 
+// StorageChecker is implemented by session managers whose storage can become
+// unreachable; Ready fails while CheckStorage does
+type StorageChecker interface {
+	CheckStorage() error
+}
+
 // ConfigManager handles game configuration loading
 type ConfigManager interface {
 	LoadConfig(name string) (*engine.GameConfig, error)
@@ -49,6 +145,40 @@ type ConfigManager interface {
 	SaveConfig(name string, config *engine.GameConfig) error
 }
 
+// EventPublisher receives game events as they happen, for delivery outside the
+// service such as webhooks. Publish is called with the service lock held and
+// must not block.
+type EventPublisher interface {
+	Publish(event SessionEvent)
+}
+
+// Option configures optional game service dependencies
+type Option func(*gameServiceImpl)
+
+// WithEventPublisher sends session and move events to p; it may be given more
+// than once to add several publishers
+func WithEventPublisher(p EventPublisher) Option {
+	return func(s *gameServiceImpl) {
+		s.publishers = append(s.publishers, p)
+	}
+}
+
+// WithSaveDebounce coalesces the autosaves that follow moves so a session is
+// written at most once per d. A game that just ended is saved straight away;
+// call FlushSaves on shutdown to write the rest. Zero, the default, saves
+// after every change.
+func WithSaveDebounce(d time.Duration) Option {
+	return func(s *gameServiceImpl) {
+		s.saveDebounce = d
+	}
+}
+
+// SaveFlusher is implemented by game services that defer session saves;
+// FlushSaves writes every pending save and should be called on shutdown
+type SaveFlusher interface {
+	FlushSaves()
+}
+
 // Session represents an active game session
 type Session struct {
 	ID             string
@@ -56,4 +186,36 @@ type Session struct {
 	Config         *engine.GameConfig
 	CreatedAt      time.Time
 	LastAccessedAt time.Time
+	// ConfigChecksum identifies the layout and rules the session was played
+	// on; ConfigDrift is set when it was reloaded against a config that has
+	// since changed
+	ConfigChecksum string
+	ConfigDrift    bool
+	// LastMoveOutcome is reported on the session's state until the next move
+	// or reset; it is not persisted
+	LastMoveOutcome engine.MoveOutcome
+	// LastCrash is the obstacle the last move ran into, reported on the
+	// session's state like LastMoveOutcome; nil when it didn't crash
+	LastCrash *engine.Position
+	// TTLSeconds overrides the server-wide inactivity window used by session
+	// cleanup when positive
+	TTLSeconds int
+	// Label is an optional user-facing name; the ID stays the session's key
+	Label string
+	// EventLog holds the session's most recent events, oldest first; see
+	// WithEventLog
+	EventLog []GameEvent
+}
+
+// SharedSession is an active competitive session with several players on one
+// grid. Shared sessions live in memory only.
+type SharedSession struct {
+	ID             string
+	ConfigName     string
+	Game           *engine.SharedGame
+	CreatedAt      time.Time
+	LastAccessedAt time.Time
+	// HideOpponentStats keeps each player's battery and score from the
+	// others until the game is over; see SharedMoveResult.ViewFor
+	HideOpponentStats bool
 }
@@ -2,19 +2,72 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/strategy"
 )
 
 // gameServiceImpl implements the GameService interface
 type gameServiceImpl struct {
-	sessions SessionManager
-	configs  ConfigManager
-	mu       sync.RWMutex
+	sessions   SessionManager
+	configs    ConfigManager
+	publishers []EventPublisher
+	mu         sync.RWMutex
+
+	// Pending auto-resets keyed by session ID, guarded by mu
+	autoResets map[string]*time.Timer
+
+	// Minimum time between autosaves of a session, and the pending debounced
+	// saves keyed by session ID, guarded by mu; see WithSaveDebounce
+	saveDebounce time.Duration
+	pendingSaves map[string]*time.Timer
+
+	// Sessions whose last save failed keyed by ID, guarded by mu, and the
+	// retry backoff; see WithSaveRetry
+	failedSaves      map[string]*failedSave
+	saveRetryBackoff time.Duration
+	saveRetryMax     time.Duration
+
+	logger Logger
+
+	// Shared competitive sessions keyed by ID, guarded by mu
+	shared map[string]*SharedSession
+
+	// Set once configs have been listed successfully, see Ready
+	configsListed atomic.Bool
+
+	// Loop watchdog settings, see WithLoopDetection
+	loopWindow   int
+	loopMaxCells int
+
+	// Results of keyed moves per session ID, guarded by mu; see
+	// WithIdempotency
+	idempotency       map[string]*idempotencyCache
+	idempotencyWindow time.Duration
+	idempotencyKeys   int
+
+	// Recent events kept per session, see WithEventLog
+	eventLogSize int
+
+	// Config analytics keyed by config name, guarded by analyticsMu; see
+	// WithAnalyticsCache
+	analyticsMu  sync.Mutex
+	analytics    map[string]*ConfigAnalytics
+	analyticsTTL time.Duration
+
+	// Extra fields left out of replay verification, see WithVerifyIgnore
+	verifyIgnore []string
 }
 
 // getConfigID returns the config_id for a given config name, used for consistent API responses
@@ -35,15 +88,48 @@ func (s *gameServiceImpl) getConfigID(configName string) string {
 }
 
 // NewGameService creates a new game service instance
-func NewGameService(sessions SessionManager, configs ConfigManager) GameService {
-	return &gameServiceImpl{
-		sessions: sessions,
-		configs:  configs,
+func NewGameService(sessions SessionManager, configs ConfigManager, opts ...Option) GameService {
+	s := &gameServiceImpl{
+		sessions:     sessions,
+		configs:      configs,
+		autoResets:   make(map[string]*time.Timer),
+		pendingSaves: make(map[string]*time.Timer),
+		logger:       DefaultLogger(),
+
+		failedSaves:      make(map[string]*failedSave),
+		saveRetryBackoff: DefaultSaveRetryBackoff,
+		saveRetryMax:     DefaultSaveRetryMaxBackoff,
+
+		shared:       make(map[string]*SharedSession),
+		loopWindow:   DefaultLoopWindow,
+		loopMaxCells: DefaultLoopMaxCells,
+
+		idempotency:       make(map[string]*idempotencyCache),
+		idempotencyWindow: DefaultIdempotencyWindow,
+		idempotencyKeys:   DefaultIdempotencyKeys,
+
+		eventLogSize: DefaultEventLogSize,
+
+		analytics:    make(map[string]*ConfigAnalytics),
+		analyticsTTL: DefaultAnalyticsTTL,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CreateSession creates a new game session
 func (s *gameServiceImpl) CreateSession(ctx context.Context, configName string) (*SessionInfo, error) {
+	return s.CreateSessionWithOptions(ctx, configName, CreateSessionOptions{})
+}
+
+// CreateSessionWithOptions creates a new game session with optional settings
+func (s *gameServiceImpl) CreateSessionWithOptions(ctx context.Context, configName string, opts CreateSessionOptions) (*SessionInfo, error) {
+	if opts.TTLSeconds < 0 {
+		return nil, fmt.Errorf("ttl_seconds must not be negative, got %d", opts.TTLSeconds)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -76,6 +162,10 @@ func (s *gameServiceImpl) CreateSession(ctx context.Context, configName string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
+	if opts.TTLSeconds > 0 {
+		session.TTLSeconds = opts.TTLSeconds
+		s.saveNow(session.ID, "create")
+	}
 
 	// Determine the config identifier to return - prefer the input configName if provided,
 	// otherwise look up the config_id by display name
@@ -84,6 +174,12 @@ func (s *gameServiceImpl) CreateSession(ctx context.Context, configName string)
 		configID = s.getConfigID(config.Name)
 	}
 
+	s.publishEvents(session, []GameEvent{{
+		Type:      "session_created",
+		Message:   fmt.Sprintf("Session created with config %s", configID),
+		Timestamp: time.Now(),
+	}}, session.Engine.GetState(), false)
+
 	return &SessionInfo{
 		ID:             session.ID,
 		ConfigName:     configID, // Return the config_id, not the display name
@@ -91,6 +187,8 @@ func (s *gameServiceImpl) CreateSession(ctx context.Context, configName string)
 		LastAccessedAt: session.LastAccessedAt,
 		GameState:      session.Engine.GetState(),
 		GameConfig:     session.Config,
+		TTLSeconds:     session.TTLSeconds,
+		Label:          session.Label,
 	}, nil
 }
 
@@ -106,13 +204,18 @@ func (s *gameServiceImpl) GetSession(ctx context.Context, sessionID string) (*Se
 
 	s.sessions.UpdateLastAccessed(sessionID)
 
+	state := session.Engine.GetState()
 	return &SessionInfo{
 		ID:             session.ID,
 		ConfigName:     s.getConfigID(session.Config.Name), // Return config_id consistently
 		CreatedAt:      session.CreatedAt,
 		LastAccessedAt: session.LastAccessedAt,
-		GameState:      session.Engine.GetState(),
+		GameState:      state,
 		GameConfig:     session.Config,
+		GameOverReason: state.GameOverReason,
+		ConfigDrift:    session.ConfigDrift,
+		TTLSeconds:     session.TTLSeconds,
+		Label:          session.Label,
 	}, nil
 }
 
@@ -125,13 +228,18 @@ func (s *gameServiceImpl) ListSessions(ctx context.Context) ([]*SessionInfo, err
 	result := make([]*SessionInfo, 0, len(sessions))
 
 	for _, sess := range sessions {
+		state := sess.Engine.GetState()
 		result = append(result, &SessionInfo{
 			ID:             sess.ID,
 			ConfigName:     s.getConfigID(sess.Config.Name), // Return config_id consistently
 			CreatedAt:      sess.CreatedAt,
 			LastAccessedAt: sess.LastAccessedAt,
-			GameState:      sess.Engine.GetState(),
+			GameState:      state,
 			GameConfig:     sess.Config,
+			GameOverReason: state.GameOverReason,
+			ConfigDrift:    sess.ConfigDrift,
+			TTLSeconds:     sess.TTLSeconds,
+			Label:          sess.Label,
 		})
 	}
 
@@ -143,20 +251,173 @@ func (s *gameServiceImpl) DeleteSession(ctx context.Context, sessionID string) e
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.cancelAutoReset(sessionID)
+	// The session's file goes with it, so a pending save is dropped rather
+	// than flushed
+	s.cancelPendingSave(sessionID)
+	s.dropFailedSave(sessionID)
+	delete(s.idempotency, sessionID)
 	return s.sessions.Delete(sessionID)
 }
 
+// DeleteSessions removes every session matching filter, including its saved
+// file, and returns the deleted IDs. An empty filter returns
+// ErrEmptySessionFilter.
+func (s *gameServiceImpl) DeleteSessions(ctx context.Context, filter SessionFilter) ([]string, error) {
+	if filter.Empty() {
+		return nil, ErrEmptySessionFilter
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := []string{}
+	for _, sess := range s.sessions.List() {
+		if filter.ConfigName != "" && filter.ConfigName != sess.Config.Name &&
+			filter.ConfigName != s.getConfigID(sess.Config.Name) {
+			continue
+		}
+		if filter.GameOver != nil && sess.Engine.IsGameOver() != *filter.GameOver {
+			continue
+		}
+
+		s.cancelAutoReset(sess.ID)
+		s.cancelPendingSave(sess.ID)
+		s.dropFailedSave(sess.ID)
+		delete(s.idempotency, sess.ID)
+		if err := s.sessions.Delete(sess.ID); err != nil {
+			return deleted, fmt.Errorf("failed to delete session %s: %w", sess.ID, err)
+		}
+		deleted = append(deleted, sess.ID)
+	}
+	sort.Strings(deleted)
+	return deleted, nil
+}
+
+// CloneSession creates a new session whose game starts from a copy of the
+// source session's current state, so alternatives can be tried without
+// touching the original. Without copyHistory the clone's move history starts
+// empty.
+func (s *gameServiceImpl) CloneSession(ctx context.Context, sessionID string, copyHistory bool) (*SessionInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	s.sessions.UpdateLastAccessed(sessionID)
+
+	state := source.Engine.GetState()
+	if !copyHistory {
+		state.MoveHistory = []engine.MoveHistoryEntry{}
+		state.CurrentMoves = []engine.MoveHistoryEntry{}
+		state.TotalMoves = 0
+		state.CurrentMovesCount = 0
+	}
+
+	session, err := s.sessions.Create("", source.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	if err := session.Engine.SetState(state); err != nil {
+		return nil, fmt.Errorf("failed to copy state: %w", err)
+	}
+	session.ConfigChecksum = source.ConfigChecksum
+	session.ConfigDrift = source.ConfigDrift
+	session.LastMoveOutcome = source.LastMoveOutcome
+	session.LastCrash = source.LastCrash
+	session.TTLSeconds = source.TTLSeconds
+	session.Label = source.Label
+
+	s.saveNow(session.ID, "clone")
+
+	s.publishEvents(session, []GameEvent{{
+		Type:      "session_created",
+		Message:   fmt.Sprintf("Session cloned from %s", sessionID),
+		Timestamp: time.Now(),
+	}}, state, false)
+
+	return &SessionInfo{
+		ID:             session.ID,
+		ConfigName:     s.getConfigID(session.Config.Name),
+		CreatedAt:      session.CreatedAt,
+		LastAccessedAt: session.LastAccessedAt,
+		GameState:      state,
+		GameConfig:     session.Config,
+		GameOverReason: state.GameOverReason,
+		ConfigDrift:    session.ConfigDrift,
+		TTLSeconds:     session.TTLSeconds,
+		Label:          session.Label,
+	}, nil
+}
+
+// SetSessionLabel sets a session's label, trimmed of surrounding space
+func (s *gameServiceImpl) SetSessionLabel(ctx context.Context, sessionID, label string) (*SessionInfo, error) {
+	label = strings.TrimSpace(label)
+	if utf8.RuneCountInString(label) > MaxSessionLabelLength {
+		return nil, ErrLabelTooLong
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	s.sessions.UpdateLastAccessed(sessionID)
+
+	session.Label = label
+	s.saveNow(sessionID, "label")
+
+	state := session.Engine.GetState()
+	return &SessionInfo{
+		ID:             session.ID,
+		ConfigName:     s.getConfigID(session.Config.Name),
+		CreatedAt:      session.CreatedAt,
+		LastAccessedAt: session.LastAccessedAt,
+		GameState:      state,
+		GameConfig:     session.Config,
+		GameOverReason: state.GameOverReason,
+		ConfigDrift:    session.ConfigDrift,
+		TTLSeconds:     session.TTLSeconds,
+		Label:          session.Label,
+	}, nil
+}
+
 // Move executes a single move for a session
 func (s *gameServiceImpl) Move(ctx context.Context, sessionID, direction string, reset bool) (*MoveResult, error) {
+	return s.MoveWithOptions(ctx, sessionID, direction, MoveOptions{Reset: reset})
+}
+
+// MoveWithOptions executes a single move for a session with optional metadata
+func (s *gameServiceImpl) MoveWithOptions(ctx context.Context, sessionID, direction string, opts MoveOptions) (*MoveResult, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.move(sessionID, direction, opts)
+}
 
+// move executes a single move for a session; the caller holds the lock
+func (s *gameServiceImpl) move(sessionID, direction string, opts MoveOptions) (*MoveResult, error) {
 	// Get session
 	sess, err := s.sessions.Get(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("session not found: %w", err)
 	}
 
+	// A retried request gets the result it had the first time
+	request := moveFingerprint("move", []string{direction}, opts.Reset)
+	if cached, err := s.cachedResult(sessionID, opts.IdempotencyKey, request); err != nil {
+		return nil, err
+	} else if result, ok := cached.(*MoveResult); ok {
+		return result, nil
+	}
+	// A game given up takes no more moves until it is reset
+	if !opts.Reset && sess.Engine.GetGameOverReason() == engine.GameOverSurrendered {
+		return nil, engine.ErrGameOver
+	}
+
 	// Update last accessed time
 	s.sessions.UpdateLastAccessed(sessionID)
 
@@ -164,8 +425,11 @@ func (s *gameServiceImpl) Move(ctx context.Context, sessionID, direction string,
 	events := []GameEvent{}
 
 	// Handle reset if requested
-	if reset {
+	if opts.Reset {
+		s.cancelAutoReset(sessionID)
 		sess.Engine.Reset()
+		sess.LastMoveOutcome = ""
+		sess.LastCrash = nil
 		events = append(events, GameEvent{
 			Type:      "reset",
 			Message:   "Game reset to initial state",
@@ -174,10 +438,10 @@ func (s *gameServiceImpl) Move(ctx context.Context, sessionID, direction string,
 	}
 
 	// Execute move
+	wasOver := sess.Engine.IsGameOver()
 	prevPos := sess.Engine.GetPlayerPosition()
-	prevState := sess.Engine.GetState()
-	prevBattery := prevState.Battery
-	success := sess.Engine.Move(direction)
+	prevBattery := sess.Engine.GetBattery()
+	success := sess.Engine.MoveWithMeta(direction, engine.MoveMeta{Autoplay: opts.Autoplay, Intent: opts.Intent})
 	newPos := sess.Engine.GetPlayerPosition()
 	state := sess.Engine.GetState()
 
@@ -190,13 +454,14 @@ func (s *gameServiceImpl) Move(ctx context.Context, sessionID, direction string,
 	}
 
 	// Add move event
+	var crash *engine.Position
 	if success {
-		moveEvents := s.extractMoveEvents(sess, prevPos, newPos, direction)
+		moveEvents := s.extractMoveEvents(state, prevPos, newPos, direction)
 		result.Events = append(result.Events, moveEvents...)
 
 		// Fill compact step info
 		tileChar, tileType := "", ""
-		if newPos.Y >= 0 && newPos.Y < len(state.Grid) && newPos.X >= 0 && newPos.X < len(state.Grid[0]) {
+		if state.InBounds(newPos.X, newPos.Y) {
 			tileChar, tileType = mapCellToCharAndType(state.Grid[newPos.Y][newPos.X])
 		}
 		charged := false
@@ -239,34 +504,247 @@ func (s *gameServiceImpl) Move(ctx context.Context, sessionID, direction string,
 		case "right":
 			attemptedX++
 		}
-		gridH := len(state.Grid)
 		var tileChar, tileType string
 		passable := false
-		if attemptedX < 0 || attemptedY < 0 || attemptedY >= gridH || (gridH > 0 && attemptedX >= len(state.Grid[0])) {
+		if !state.InBounds(attemptedX, attemptedY) {
 			tileChar = "B"
 			tileType = "boundary"
 		} else {
 			cell := state.Grid[attemptedY][attemptedX]
 			tileChar, tileType = mapCellToCharAndType(cell)
-			passable = cell.Type != engine.Water && cell.Type != engine.Building
+			passable = state.CanMoveTo(attemptedX, attemptedY)
 		}
 		result.AttemptedTo = &AttemptInfo{X: attemptedX, Y: attemptedY, TileChar: tileChar, TileType: tileType, Passable: passable}
+		crash = crashCell(state, attemptedX, attemptedY)
+		result.Events = appendParkExpiredEvents(result.Events, state)
 	}
 
-	// Enrich state with decision aids
-	state.LocalView3x3 = buildLocal3x3(state)
-	state.BatteryRisk = riskCode(engine.AnalyzeBatteryRisk(state))
+	sess.LastMoveOutcome = moveOutcome(success, result.Events, state)
+	sess.LastCrash = crash
+
+	enrichState(sess, state)
+
+	if warning, ok := s.loopWarning(state); ok {
+		result.LoopDetected = true
+		result.Events = append(result.Events, warning)
+	}
+
+	s.publishEvents(sess, result.Events, state, wasOver)
+	if !wasOver && state.GameOver {
+		s.scheduleAutoReset(sess)
+	}
 
 	// Auto-save session after move
-	if err := s.sessions.Save(sessionID); err != nil {
-		fmt.Printf("Warning: Failed to persist session %s after move: %v\n", sessionID, err)
+	s.autosave(sessionID, "move", state.GameOver)
+	state.PersistenceDegraded = s.persistenceDegraded()
+
+	if opts.IdempotencyKey != "" {
+		cached := *result
+		cached.GameState = snapshotState(state)
+		s.rememberResult(sessionID, opts.IdempotencyKey, request, &cached)
+	}
+
+	return result, nil
+}
+
+// moveOutcome classifies a move from whether it succeeded, the events it
+// produced and the state after it. A finished game takes precedence, then a
+// hazard hit, then a collected park over a charge.
+func moveOutcome(success bool, events []GameEvent, state *engine.GameState) engine.MoveOutcome {
+	switch {
+	case state.Victory:
+		return engine.MoveOutcomeVictory
+	case state.GameOver:
+		return engine.MoveOutcomeGameOver
+	case hasEvent(events, "hazard_hit"):
+		return engine.MoveOutcomeHazardHit
+	case !success:
+		if n := len(state.CurrentMoves); n > 0 && state.CurrentMoves[n-1].WouldStrand {
+			return engine.MoveOutcomeWouldStrand
+		}
+		return engine.MoveOutcomeBlocked
+	}
+	outcome := engine.MoveOutcomeMoved
+	for _, ev := range events {
+		switch ev.Type {
+		case "park_visited":
+			return engine.MoveOutcomeCollected
+		case "charge":
+			outcome = engine.MoveOutcomeCharged
+		case "wait":
+			outcome = engine.MoveOutcomeWaited
+		}
+	}
+	return outcome
+}
+
+// gameOverCode returns why a finished game ended. The engine records the
+// reason; sessions persisted before it did have none and get game_over.
+func gameOverCode(state *engine.GameState) string {
+	if state.GameOverReason == "" {
+		return "game_over"
+	}
+	return string(state.GameOverReason)
+}
+
+// crashCell returns the cell a failed move tried to enter when it is an
+// obstacle, or nil when the move failed for another reason
+func crashCell(state *engine.GameState, x, y int) *engine.Position {
+	if state.CanMoveTo(x, y) {
+		return nil
+	}
+	return &engine.Position{X: x, Y: y}
+}
+
+// enrichState adds the decision aids computed for clients to a state being
+// returned for a session, along with its last move outcome and crash. Every
+// method returning a session's state calls it, so they all report the same
+// fields; callers set the session's outcome first.
+func enrichState(sess *Session, state *engine.GameState) {
+	state.LocalView3x3 = buildLocal3x3(state)
+	state.RelativeView = buildRelativeView(state.Heading(), state.LocalView3x3)
+	state.BatteryRisk = engine.AssessBatteryRisk(state, sess.Config).Level
+	state.BatteryPercent = engine.BatteryPercent(state.Battery, state.MaxBattery)
+	state.MovePreviews = buildMovePreviews(sess.Engine)
+	reportLastMove(sess, state)
+}
+
+// reportLastMove sets a session's last move outcome and crash on a state
+// being returned
+func reportLastMove(sess *Session, state *engine.GameState) {
+	state.LastMoveOutcome = sess.LastMoveOutcome
+	state.Crash = sess.LastCrash != nil
+	state.CrashPos = nil
+	if sess.LastCrash != nil {
+		pos := *sess.LastCrash
+		state.CrashPos = &pos
+	}
+}
+
+// hasEvent reports whether events include one of type typ
+func hasEvent(events []GameEvent, typ string) bool {
+	for _, ev := range events {
+		if ev.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// Teleport places a session's player on a passable cell for debugging. The
+// destination's charge and park effects apply and the jump is recorded in
+// history as a "teleport" entry. Targets that can't be entered return an
+// error wrapping engine.ErrImpassable; finished games engine.ErrGameOver.
+func (s *gameServiceImpl) Teleport(ctx context.Context, sessionID string, x, y int) (*MoveResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	s.sessions.UpdateLastAccessed(sessionID)
+
+	prevPos := sess.Engine.GetPlayerPosition()
+	prevBattery := sess.Engine.GetBattery()
+	if err := sess.Engine.Teleport(x, y); err != nil {
+		return nil, err
+	}
+	newPos := sess.Engine.GetPlayerPosition()
+	state := sess.Engine.GetState()
+
+	events := s.extractMoveEvents(state, prevPos, newPos, engine.ActionTeleport)
+	tileChar, tileType := mapCellToCharAndType(state.Grid[newPos.Y][newPos.X])
+	result := &MoveResult{
+		Success:   true,
+		GameState: state,
+		Message:   state.Message,
+		Events:    events,
+		Step: &StepInfo{
+			Idx:           1,
+			Dir:           engine.ActionTeleport,
+			From:          prevPos,
+			To:            newPos,
+			TileChar:      tileChar,
+			TileType:      tileType,
+			BatteryBefore: prevBattery,
+			BatteryAfter:  state.Battery,
+			Success:       true,
+		},
+	}
+	for _, ev := range events {
+		switch ev.Type {
+		case "charge":
+			result.Step.Charged = true
+		case "park_visited":
+			result.Step.Park = true
+		case "victory":
+			result.Step.Victory = true
+		}
 	}
 
+	sess.LastMoveOutcome = moveOutcome(true, events, state)
+	sess.LastCrash = nil
+
+	enrichState(sess, state)
+
+	s.publishEvents(sess, events, state, false)
+	if state.GameOver {
+		s.scheduleAutoReset(sess)
+	}
+
+	s.autosave(sessionID, "teleport", state.GameOver)
+	state.PersistenceDegraded = s.persistenceDegraded()
+
 	return result, nil
 }
 
+// MoveToward takes one step along a shortest drive from the player to
+// (x, y), ignoring battery. The path is planned and the step taken under one
+// lock, so no other move can slip in between. After a move that didn't go
+// as planned, say it was held on a charger, the remaining path is measured
+// from wherever the player ended up.
+func (s *gameServiceImpl) MoveToward(ctx context.Context, sessionID string, x, y int) (*MoveTowardResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	from := sess.Engine.GetPlayerPosition()
+	target := engine.Position{X: x, Y: y}
+	path, ok := sess.Engine.PathTo(target)
+	if !ok {
+		return nil, fmt.Errorf("%w: no road leads from (%d,%d) to (%d,%d)", ErrTargetUnreachable, from.X, from.Y, x, y)
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w (%d,%d)", ErrAlreadyAtTarget, x, y)
+	}
+
+	result, err := s.move(sessionID, path[0], MoveOptions{Intent: fmt.Sprintf("move toward (%d,%d)", x, y)})
+	if err != nil {
+		return nil, err
+	}
+	remaining, _ := sess.Engine.PathTo(target)
+	return &MoveTowardResult{
+		MoveResult:    result,
+		Target:        target,
+		Direction:     path[0],
+		RemainingPath: len(remaining),
+	}, nil
+}
+
 // BulkMove executes multiple moves in sequence
 func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves []string, reset bool) (*BulkMoveResult, error) {
+	return s.BulkMoveWithOptions(ctx, sessionID, moves, BulkMoveOptions{Reset: reset})
+}
+
+// BulkMoveWithOptions executes multiple moves in sequence. By default the first
+// failed move stops the sequence; with ContinueOnBlock, moves into obstacles are
+// recorded as failed steps and execution proceeds. StopBelowBattery stops it
+// early, without failing, once the battery runs low.
+func (s *gameServiceImpl) BulkMoveWithOptions(ctx context.Context, sessionID string, moves []string, opts BulkMoveOptions) (*BulkMoveResult, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -275,6 +753,18 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 		return nil, fmt.Errorf("session not found: %w", err)
 	}
 
+	// A retried request gets the result it had the first time
+	request := moveFingerprint("bulk", moves, opts.Reset)
+	if cached, err := s.cachedResult(sessionID, opts.IdempotencyKey, request); err != nil {
+		return nil, err
+	} else if result, ok := cached.(*BulkMoveResult); ok {
+		return result, nil
+	}
+	// A game given up takes no more moves until it is reset
+	if !opts.Reset && sess.Engine.GetGameOverReason() == engine.GameOverSurrendered {
+		return nil, engine.ErrGameOver
+	}
+
 	// Update last accessed
 	s.sessions.UpdateLastAccessed(sessionID)
 
@@ -296,8 +786,11 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 	}
 
 	// Handle reset
-	if reset {
+	if opts.Reset {
+		s.cancelAutoReset(sessionID)
 		sess.Engine.Reset()
+		sess.LastMoveOutcome = ""
+		sess.LastCrash = nil
 		result.Events = append(result.Events, GameEvent{
 			Type:      "reset",
 			Message:   "Game reset to initial state",
@@ -305,6 +798,8 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 		})
 	}
 
+	wasOver := sess.Engine.IsGameOver()
+
 	// Limit moves to prevent abuse
 	if len(moves) > engine.MaxBulkMoves {
 		result.Truncated = true
@@ -312,8 +807,10 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 		moves = moves[:engine.MaxBulkMoves]
 	}
 
-	// Execute moves
+	// Execute moves. See the StopReasonCode field for how a stop is classified.
 	for i, move := range moves {
+		// Only a game already over before the sequence gets here; a move that
+		// ends the game stops the sequence below
 		if sess.Engine.IsGameOver() {
 			result.StoppedReason = "game_over"
 			result.StopReasonCode = "game_over"
@@ -322,14 +819,16 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 		}
 
 		prevPos := sess.Engine.GetPlayerPosition()
-		prevState := sess.Engine.GetState()
-		prevBattery := prevState.Battery
-		success := sess.Engine.Move(move)
+		prevBattery := sess.Engine.GetBattery()
+		prevHold := sess.Engine.GetChargeHold()
+		success := sess.Engine.MoveWithMeta(move, engine.MoveMeta{Intent: opts.intent(i)})
+		intent := ""
+		if last := sess.Engine.GetLastMove(); last != nil {
+			intent = last.Intent
+		}
 
 		if !success {
 			result.Success = false
-			result.StoppedReason = fmt.Sprintf("move %d blocked: %s", i+1, move)
-			result.StoppedOnMove = i + 1
 
 			// Determine attempted target and reason code
 			attemptedX, attemptedY := prevPos.X, prevPos.Y
@@ -345,27 +844,74 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 			}
 
 			st := sess.Engine.GetState()
-			gridH := len(st.Grid)
+			sess.LastMoveOutcome = moveOutcome(false, nil, st)
+			sess.LastCrash = crashCell(st, attemptedX, attemptedY)
+			result.Events = appendParkExpiredEvents(result.Events, st)
+
+			// Blocked by an obstacle: record the failed step and keep going if requested.
+			// Only a wall-crash penalty consumes battery on a blocked move.
+			if opts.ContinueOnBlock && !st.GameOver && !st.CanMoveTo(attemptedX, attemptedY) {
+				attempt := describeAttempt(st, attemptedX, attemptedY)
+				result.BlockedCount++
+				if result.AttemptedTo == nil {
+					result.AttemptedTo = attempt
+				}
+				result.Steps = append(result.Steps, StepInfo{
+					Idx:           i + 1,
+					Dir:           move,
+					From:          prevPos,
+					To:            prevPos,
+					TileChar:      attempt.TileChar,
+					TileType:      attempt.TileType,
+					BatteryBefore: prevBattery,
+					BatteryAfter:  st.Battery,
+					Success:       false,
+					AttemptedTo:   attempt,
+					Intent:        intent,
+				})
+				continue
+			}
+
+			result.StoppedReason = fmt.Sprintf("move %d blocked: %s", i+1, move)
+			result.StoppedOnMove = i + 1
 			var tileChar, tileType string
 			passable := false
-			if attemptedX < 0 || attemptedY < 0 || attemptedY >= gridH || (gridH > 0 && attemptedX >= len(st.Grid[0])) {
+			if move == engine.ActionCharge {
+				tileChar, tileType = mapCellToCharAndType(st.Grid[prevPos.Y][prevPos.X])
+				passable = true
+				result.StopReasonCode = "not_on_charger"
+			} else if move == engine.ActionPark {
+				tileChar, tileType = mapCellToCharAndType(st.Grid[prevPos.Y][prevPos.X])
+				passable = true
+				result.StopReasonCode = "not_on_park"
+			} else if !st.InBounds(attemptedX, attemptedY) {
 				tileChar = "B" // treat boundary as wall-like
 				tileType = "boundary"
 				result.StopReasonCode = "blocked_boundary"
 			} else {
 				cell := st.Grid[attemptedY][attemptedX]
 				tileChar, tileType = mapCellToCharAndType(cell)
-				passable = cell.Type != engine.Water && cell.Type != engine.Building
+				passable = st.CanMoveTo(attemptedX, attemptedY)
 				if !passable {
-					if cell.Type == engine.Water {
+					switch cell.Type {
+					case engine.Water:
 						result.StopReasonCode = "blocked_water"
-					} else if cell.Type == engine.Building {
+					case engine.Building:
 						result.StopReasonCode = "blocked_building"
+					case engine.Door:
+						result.StopReasonCode = "blocked_door"
 					}
+				} else if n := len(st.CurrentMoves); n > 0 && st.CurrentMoves[n-1].WouldStrand {
+					result.StopReasonCode = "would_strand"
+				} else if st.GameOver {
+					// The engine tells moving on an empty battery (out_of_battery)
+					// apart from running empty away from a charger (stranded)
+					result.StopReasonCode = gameOverCode(st)
+				} else if prevHold > 0 {
+					result.StopReasonCode = "charge_hold"
 				} else if prevBattery <= 0 {
+					// Empty on a charger that has to charge before moving on
 					result.StopReasonCode = "out_of_battery"
-				} else if st.GameOver {
-					result.StopReasonCode = "game_over"
 				}
 			}
 			result.AttemptedTo = &AttemptInfo{
@@ -382,14 +928,16 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 		newPos := sess.Engine.GetPlayerPosition()
 
 		// Collect events for this move
-		events := s.extractMoveEvents(sess, prevPos, newPos, move)
+		currState := sess.Engine.GetState()
+		events := s.extractMoveEvents(currState, prevPos, newPos, move)
 		result.Events = append(result.Events, events...)
 
 		// Build step info for this executed move
-		currState := sess.Engine.GetState()
+		sess.LastMoveOutcome = moveOutcome(true, events, currState)
+		sess.LastCrash = nil
 		batteryAfter := currState.Battery
 		tileChar, tileType := "", ""
-		if newPos.Y >= 0 && newPos.Y < len(currState.Grid) && newPos.X >= 0 && newPos.X < len(currState.Grid[0]) {
+		if currState.InBounds(newPos.X, newPos.Y) {
 			tileChar, tileType = mapCellToCharAndType(currState.Grid[newPos.Y][newPos.X])
 		}
 		charged := false
@@ -418,8 +966,29 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 			Charged:       charged,
 			Park:          park,
 			Victory:       victory,
+			Intent:        intent,
 		}
 		result.Steps = append(result.Steps, step)
+
+		// A move that ends the game before the last stops the sequence with
+		// the engine's reason, victory included
+		if currState.GameOver {
+			if i+1 < len(moves) {
+				result.StoppedReason = fmt.Sprintf("game over after move %d: %s", i+1, gameOverCode(currState))
+				result.StopReasonCode = gameOverCode(currState)
+				result.StoppedOnMove = i + 1
+			}
+			break
+		}
+
+		// Hand control back so the caller can reassess before going further
+		if opts.StopBelowBattery > 0 && batteryAfter < opts.StopBelowBattery &&
+			!currState.GameOver && i+1 < len(moves) {
+			result.StoppedReason = fmt.Sprintf("battery %d below %d after move %d", batteryAfter, opts.StopBelowBattery, i+1)
+			result.StopReasonCode = "low_battery"
+			result.StoppedOnMove = i + 1
+			break
+		}
 	}
 
 	result.GameState = sess.Engine.GetState()
@@ -434,53 +1003,38 @@ func (s *gameServiceImpl) BulkMove(ctx context.Context, sessionID string, moves
 	result.GameOver = endState.GameOver
 	result.Message = endState.Message
 
-	// If we ended due to game over without explicit stop reason code
-	if result.GameOver && result.StopReasonCode == "" {
-		if endState.Victory {
-			result.StopReasonCode = "victory"
-			result.GameOverCode = "victory"
-		} else if endState.Battery == 0 {
-			// Determine stranded vs out_of_battery by checking if we executed a move to 0 battery
-			if result.MovesExecuted > 0 {
-				// Last executed step battery_after should equal endState.Battery
-				last := result.Steps[len(result.Steps)-1]
-				if last.BatteryAfter == 0 {
-					// Stranded if not on charger
-					currCell := endState.Grid[endState.PlayerPos.Y][endState.PlayerPos.X]
-					if currCell.Type != engine.Home && currCell.Type != engine.Supercharger {
-						result.StopReasonCode = "stranded"
-						result.GameOverCode = "stranded"
-					} else {
-						result.StopReasonCode = "game_over"
-						result.GameOverCode = "game_over"
-					}
-				} else {
-					result.StopReasonCode = "game_over"
-					result.GameOverCode = "game_over"
-				}
-			} else {
-				// No executed moves, battery must have been 0 at start
-				result.StopReasonCode = "out_of_battery"
-				result.GameOverCode = "out_of_battery"
-			}
-		} else {
-			result.StopReasonCode = "game_over"
-			result.GameOverCode = "game_over"
+	if result.GameOver {
+		result.GameOverCode = gameOverCode(endState)
+		if result.StopReasonCode == "" {
+			result.StopReasonCode = result.GameOverCode
 		}
 	}
 
-	// Decision aids
+	// Decision aids, on the returned state and the result alike
+	enrichState(sess, endState)
 	result.PossibleMoves = sess.Engine.GetPossibleMoves()
-	result.LocalView3x3 = buildLocal3x3(endState)
-	result.BatteryRisk = riskCode(engine.AnalyzeBatteryRisk(endState))
+	result.LocalView3x3 = endState.LocalView3x3
+	result.BatteryRisk = endState.BatteryRisk
+	result.BatteryPercent = endState.BatteryPercent
+
+	if warning, ok := s.loopWarning(endState); ok {
+		result.LoopDetected = true
+		result.Events = append(result.Events, warning)
+	}
 
-	// Also expose decision aids on the returned state for parity
-	endState.LocalView3x3 = result.LocalView3x3
-	endState.BatteryRisk = result.BatteryRisk
+	s.publishEvents(sess, result.Events, endState, wasOver)
+	if !wasOver && endState.GameOver {
+		s.scheduleAutoReset(sess)
+	}
 
 	// Auto-save session after bulk moves
-	if err := s.sessions.Save(sessionID); err != nil {
-		fmt.Printf("Warning: Failed to persist session %s after bulk moves: %v\n", sessionID, err)
+	s.autosave(sessionID, "bulk moves", endState.GameOver)
+	endState.PersistenceDegraded = s.persistenceDegraded()
+
+	if opts.IdempotencyKey != "" {
+		cached := *result
+		cached.GameState = snapshotState(endState)
+		s.rememberResult(sessionID, opts.IdempotencyKey, request, &cached)
 	}
 
 	return result, nil
@@ -497,23 +1051,15 @@ func (s *gameServiceImpl) Reset(ctx context.Context, sessionID string) (*engine.
 	}
 
 	s.sessions.UpdateLastAccessed(sessionID)
-	state := sess.Engine.Reset()
-	// Enrich state with decision aids
-	state.LocalView3x3 = buildLocal3x3(state)
-	state.BatteryRisk = riskCode(engine.AnalyzeBatteryRisk(state))
-
-	// Auto-save session after reset
-	if err := s.sessions.Save(sessionID); err != nil {
-		fmt.Printf("Warning: Failed to persist session %s after reset: %v\n", sessionID, err)
-	}
-
-	return state, nil
+	s.cancelAutoReset(sessionID)
+	return s.resetSession(sess), nil
 }
 
-// GetGameState retrieves the current game state
-func (s *gameServiceImpl) GetGameState(ctx context.Context, sessionID string) (*engine.GameState, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// ResetAndReplay resets a session and replays the first keepMoves moves of
+// its current game, stepping back to where it stood then
+func (s *gameServiceImpl) ResetAndReplay(ctx context.Context, sessionID string, keepMoves int) (*engine.GameState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	sess, err := s.sessions.Get(sessionID)
 	if err != nil {
@@ -521,44 +1067,216 @@ func (s *gameServiceImpl) GetGameState(ctx context.Context, sessionID string) (*
 	}
 
 	s.sessions.UpdateLastAccessed(sessionID)
-	state := sess.Engine.GetState()
-	// Enrich state with decision aids
-	state.LocalView3x3 = buildLocal3x3(state)
-	state.BatteryRisk = riskCode(engine.AnalyzeBatteryRisk(state))
+	s.cancelAutoReset(sessionID)
+	state := s.enrichReset(sess, sess.Engine.Rewind(keepMoves))
+	if state.GameOver {
+		s.scheduleAutoReset(sess)
+	}
 	return state, nil
 }
 
-// GetMoveHistory returns paginated move history
-func (s *gameServiceImpl) GetMoveHistory(ctx context.Context, sessionID string, opts HistoryOptions) (*HistoryResponse, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// Surrender ends a session's game as a defeat with the surrendered reason.
+// The game takes no more moves until it is reset; moves fail with
+// engine.ErrGameOver, as does surrendering a game that has already ended.
+func (s *gameServiceImpl) Surrender(ctx context.Context, sessionID string) (*engine.GameState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	sess, err := s.sessions.Get(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("session not found: %w", err)
 	}
+	s.sessions.UpdateLastAccessed(sessionID)
 
-	history := sess.Engine.GetMoveHistory()
-	total := len(history)
+	if err := sess.Engine.Surrender(); err != nil {
+		return nil, err
+	}
+	state := sess.Engine.GetState()
+	sess.LastMoveOutcome = moveOutcome(true, nil, state)
+	sess.LastCrash = nil
 
-	// Apply defaults
-	if opts.Page < 1 {
-		opts.Page = 1
+	enrichState(sess, state)
+
+	s.publishEvents(sess, nil, state, false)
+	s.scheduleAutoReset(sess)
+	s.saveNow(sessionID, "surrender")
+	state.PersistenceDegraded = s.persistenceDegraded()
+
+	return state, nil
+}
+
+// resetSession restores a session to its initial state, enriches the state
+// and persists it; callers hold s.mu
+func (s *gameServiceImpl) resetSession(sess *Session) *engine.GameState {
+	return s.enrichReset(sess, sess.Engine.Reset())
+}
+
+// enrichReset enriches and persists the state a session was just reset to;
+// callers hold s.mu
+func (s *gameServiceImpl) enrichReset(sess *Session, state *engine.GameState) *engine.GameState {
+	sess.LastMoveOutcome = ""
+	sess.LastCrash = nil
+	enrichState(sess, state)
+
+	// Auto-save session after reset
+	s.saveNow(sess.ID, "reset")
+	state.PersistenceDegraded = s.persistenceDegraded()
+
+	return state
+}
+
+// scheduleAutoReset arms a reset for a session whose game just ended, if its
+// config enables auto-reset. Any earlier timer for the session is replaced.
+// Callers hold s.mu.
+func (s *gameServiceImpl) scheduleAutoReset(sess *Session) {
+	if sess.Config == nil || sess.Config.AutoResetSeconds <= 0 {
+		return
 	}
-	if opts.Limit <= 0 {
-		opts.Limit = 20
+	s.cancelAutoReset(sess.ID)
+
+	sessionID := sess.ID
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Duration(sess.Config.AutoResetSeconds)*time.Second, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// A manual reset, delete or newer game over replaced this timer
+		if s.autoResets[sessionID] != timer {
+			return
+		}
+		delete(s.autoResets, sessionID)
+
+		sess, err := s.sessions.Get(sessionID)
+		if err != nil || !sess.Engine.IsGameOver() {
+			return
+		}
+		state := s.resetSession(sess)
+		s.publishEvents(sess, []GameEvent{{
+			Type:      "auto_reset",
+			Message:   "Game reset automatically after game over",
+			Timestamp: time.Now(),
+			Position:  state.PlayerPos,
+		}}, state, false)
+	})
+	s.autoResets[sessionID] = timer
+}
+
+// cancelAutoReset stops a pending auto-reset; callers hold s.mu
+func (s *gameServiceImpl) cancelAutoReset(sessionID string) {
+	if timer, ok := s.autoResets[sessionID]; ok {
+		timer.Stop()
+		delete(s.autoResets, sessionID)
 	}
-	if opts.Limit > 100 {
-		opts.Limit = 100
+}
+
+// autosave persists a session after a change. With a save debounce the write
+// is deferred and coalesced with later changes, except when the game just
+// ended, which is saved straight away. Callers hold s.mu.
+func (s *gameServiceImpl) autosave(sessionID, action string, gameOver bool) {
+	if s.saveDebounce <= 0 || gameOver {
+		s.saveNow(sessionID, action)
+		return
 	}
-	if opts.Order == "" {
-		opts.Order = "desc"
+	if _, pending := s.pendingSaves[sessionID]; pending {
+		return
 	}
 
-	// Calculate pagination
-	totalPages := (total + opts.Limit - 1) / opts.Limit
-	if totalPages == 0 {
-		totalPages = 1
+	var timer *time.Timer
+	timer = time.AfterFunc(s.saveDebounce, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// Flushed, deleted or already saved by a newer change
+		if s.pendingSaves[sessionID] != timer {
+			return
+		}
+		delete(s.pendingSaves, sessionID)
+		s.saveNow(sessionID, action)
+	})
+	s.pendingSaves[sessionID] = timer
+}
+
+// saveNow persists a session immediately, replacing any pending debounced
+// save; callers hold s.mu
+func (s *gameServiceImpl) saveNow(sessionID, action string) {
+	s.cancelPendingSave(sessionID)
+	if err := s.sessions.Save(sessionID); err != nil {
+		s.saveFailed(sessionID, action, err)
+		return
+	}
+	s.saveRecovered(sessionID)
+}
+
+// cancelPendingSave stops a debounced save; callers hold s.mu
+func (s *gameServiceImpl) cancelPendingSave(sessionID string) {
+	if timer, ok := s.pendingSaves[sessionID]; ok {
+		timer.Stop()
+		delete(s.pendingSaves, sessionID)
+	}
+}
+
+// FlushSaves writes every pending debounced save so no moves are lost on
+// shutdown
+func (s *gameServiceImpl) FlushSaves() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sessionID := range s.pendingSaves {
+		s.saveNow(sessionID, "flush")
+	}
+}
+
+// GetGameState retrieves the current game state
+func (s *gameServiceImpl) GetGameState(ctx context.Context, sessionID string) (*engine.GameState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	s.sessions.UpdateLastAccessed(sessionID)
+	state := sess.Engine.GetState()
+	enrichState(sess, state)
+	state.PersistenceDegraded = s.persistenceDegraded()
+	return state, nil
+}
+
+// GetMoveHistory returns paginated move history
+func (s *gameServiceImpl) GetMoveHistory(ctx context.Context, sessionID string, opts HistoryOptions) (*HistoryResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	history := sess.Engine.GetMoveHistory()
+	total := len(history)
+
+	// Apply defaults; the session's config may set its own page size
+	if opts.Page < 1 {
+		opts.Page = 1
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = engine.DefaultHistoryPageSize
+		if sess.Config != nil && sess.Config.DefaultHistoryPageSize > 0 {
+			opts.Limit = sess.Config.DefaultHistoryPageSize
+		}
+	}
+	if opts.Limit > engine.MaxHistoryPageSize {
+		opts.Limit = engine.MaxHistoryPageSize
+	}
+	if opts.Order == "" {
+		opts.Order = "desc"
+	}
+
+	// Calculate pagination
+	totalPages := (total + opts.Limit - 1) / opts.Limit
+	if totalPages == 0 {
+		totalPages = 1
 	}
 
 	start := (opts.Page - 1) * opts.Limit
@@ -597,6 +1315,582 @@ func (s *gameServiceImpl) GetMoveHistory(ctx context.Context, sessionID string,
 	}, nil
 }
 
+// GetMove returns the move at the 1-based index into the session's
+// cumulative history, with what it did to the player
+func (s *gameServiceImpl) GetMove(ctx context.Context, sessionID string, index int) (*MoveDetail, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	state := sess.Engine.GetState()
+	total := len(state.MoveHistory)
+	if index < 1 || index > total {
+		return nil, fmt.Errorf("%w: index %d of %d", ErrMoveNotFound, index, total)
+	}
+
+	entry := state.MoveHistory[index-1]
+	detail := &MoveDetail{
+		MoveHistoryEntry: entry,
+		Index:            index,
+		TotalMoves:       total,
+		Event:            moveEvent(entry),
+		// Moves since the last reset are the tail of the cumulative history
+		CurrentSegment: index > total-len(state.CurrentMoves),
+	}
+	if entry.Success {
+		detail.TileEntered = cellTypeAt(state, entry.ToPosition)
+	}
+	return detail, nil
+}
+
+// cellTypeAt returns the type of the cell at pos on the current grid, or ""
+// off the grid
+func cellTypeAt(state *engine.GameState, pos engine.Position) engine.CellType {
+	if pos.Y < 0 || pos.Y >= len(state.Grid) || pos.X < 0 || pos.X >= len(state.Grid[pos.Y]) {
+		return ""
+	}
+	return state.Grid[pos.Y][pos.X].Type
+}
+
+// moveEvent names the most notable outcome of a history entry, see the
+// MoveEvent constants. It goes by what the entry recorded rather than the
+// grid, which has moved on since.
+func moveEvent(entry engine.MoveHistoryEntry) string {
+	switch {
+	case entry.WouldStrand:
+		return MoveEventWouldStrand
+	case !entry.Success:
+		return MoveEventBlocked
+	case entry.HazardHit:
+		return MoveEventHazardHit
+	case entry.Action == engine.ActionTeleport:
+		return MoveEventTeleport
+	case entry.ParkCollected != "":
+		return MoveEventPark
+	case entry.BatteryDelta > 0:
+		return MoveEventCharge
+	case entry.FromPosition == entry.ToPosition:
+		return MoveEventStay
+	}
+	return MoveEventMove
+}
+
+// GetSessionConfig returns the config a session plays on, without its state
+func (s *gameServiceImpl) GetSessionConfig(ctx context.Context, sessionID string) (*SessionConfig, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	s.sessions.UpdateLastAccessed(sessionID)
+
+	return &SessionConfig{
+		GameConfig:  *sess.Config,
+		ConfigID:    s.getConfigID(sess.Config.Name),
+		Checksum:    sess.ConfigChecksum,
+		ConfigDrift: sess.ConfigDrift,
+	}, nil
+}
+
+// GetParks lists every park on the session's grid with its collected status,
+// read from VisitedParks
+func (s *gameServiceImpl) GetParks(ctx context.Context, sessionID string) (*ParksResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	state := sess.Engine.GetState()
+	result := &ParksResponse{Parks: []ParkInfo{}}
+	for y, row := range state.Grid {
+		for x, cell := range row {
+			if cell.Type != engine.Park || cell.ID == "" {
+				continue
+			}
+			collected := state.VisitedParks[cell.ID]
+			result.Parks = append(result.Parks, ParkInfo{
+				ID:        cell.ID,
+				Position:  engine.Position{X: x, Y: y},
+				Collected: collected,
+				Points:    state.ParkPoints(),
+			})
+			if collected {
+				result.Collected++
+			}
+		}
+	}
+	// An expired park is road on the grid now; list it where it was
+	for _, p := range state.ParkExpiry {
+		if p.Expired {
+			result.Parks = append(result.Parks, ParkInfo{ID: p.ParkID, Position: p.Position, Points: state.ParkPoints(), Expired: true})
+		}
+	}
+
+	// Order by ID, shorter IDs first so park_2 sorts before park_10
+	sort.Slice(result.Parks, func(i, j int) bool {
+		a, b := result.Parks[i].ID, result.Parks[j].ID
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	result.Total = len(result.Parks)
+	// Victory takes every park that hasn't expired
+	result.Required = engine.CountTotalParks(state.Grid)
+	return result, nil
+}
+
+// GetBatteryRisk rates the session's battery against its config's risk
+// thresholds, the same band the state's battery_risk reports
+func (s *gameServiceImpl) GetBatteryRisk(ctx context.Context, sessionID string) (*engine.RiskAssessment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	risk := engine.AssessBatteryRisk(sess.Engine.GetState(), sess.Config)
+	return &risk, nil
+}
+
+// CompareSessions walks the current games of two sessions in lockstep
+func (s *gameServiceImpl) CompareSessions(ctx context.Context, sessionA, sessionB string) (*SessionComparison, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessA, err := s.sessions.Get(sessionA)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	sessB, err := s.sessions.Get(sessionB)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	if sessA.Config.Name != sessB.Config.Name {
+		return nil, fmt.Errorf("%w: %s uses '%s', %s uses '%s'", ErrConfigMismatch,
+			sessA.ID, sessA.Config.Name, sessB.ID, sessB.Config.Name)
+	}
+
+	stateA := sessA.Engine.GetState()
+	stateB := sessB.Engine.GetState()
+	comparison := &SessionComparison{
+		ConfigName: s.getConfigID(sessA.Config.Name),
+		A:          traceSession(sessA.ID, sessA.Engine),
+		B:          traceSession(sessB.ID, sessB.Engine),
+	}
+
+	movesA, movesB := stateA.CurrentMoves, stateB.CurrentMoves
+	for i := 0; i < len(movesA) || i < len(movesB); i++ {
+		if i >= len(movesA) || i >= len(movesB) ||
+			movesA[i].Action != movesB[i].Action || movesA[i].ToPosition != movesB[i].ToPosition {
+			comparison.Diverged = true
+			comparison.DivergedAtMove = i + 1
+			break
+		}
+	}
+
+	return comparison, nil
+}
+
+// GetLeaderboard ranks the sessions playing a config by their current game
+func (s *gameServiceImpl) GetLeaderboard(ctx context.Context, opts LeaderboardOptions) (*Leaderboard, error) {
+	sortBy := opts.SortBy
+	if sortBy == "" {
+		sortBy = LeaderboardByScore
+	}
+	if sortBy != LeaderboardByScore && sortBy != LeaderboardByMoves {
+		return nil, fmt.Errorf("%w, got '%s'", ErrInvalidLeaderboardSort, sortBy)
+	}
+
+	s.mu.RLock()
+	entries := []LeaderboardEntry{}
+	for _, sess := range s.sessions.List() {
+		configID := s.getConfigID(sess.Config.Name)
+		if opts.ConfigName != "" && opts.ConfigName != sess.Config.Name && opts.ConfigName != configID {
+			continue
+		}
+		state := sess.Engine.GetState()
+		if sortBy == LeaderboardByMoves && !state.Victory {
+			continue
+		}
+		entries = append(entries, LeaderboardEntry{
+			SessionID:  sess.ID,
+			ConfigName: configID,
+			Score:      state.FinalScore(),
+			Moves:      state.CurrentMovesCount,
+			Battery:    state.Battery,
+			Victory:    state.Victory,
+			CreatedAt:  sess.CreatedAt,
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if sortBy == LeaderboardByScore && a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Moves != b.Moves {
+			return a.Moves < b.Moves
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.SessionID < b.SessionID
+	})
+
+	board := &Leaderboard{ConfigName: opts.ConfigName, SortBy: sortBy, Total: len(entries)}
+	if opts.Limit > 0 && opts.Limit < len(entries) {
+		entries = entries[:opts.Limit]
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	board.Entries = entries
+	return board, nil
+}
+
+// SolveGame searches for a move sequence that wins from the session's current
+// state. The search runs on a copy outside the service lock and is bounded by
+// strategy.DefaultSolveBudget unless ctx has an earlier deadline; running out
+// of time is reported in the result rather than as an error.
+func (s *gameServiceImpl) SolveGame(ctx context.Context, sessionID string) (*SolveResult, error) {
+	s.mu.RLock()
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	state := sess.Engine.GetState()
+	config := sess.Config
+	s.mu.RUnlock()
+
+	result := &SolveResult{Moves: []string{}}
+	if state.GameOver && !state.Victory {
+		result.Reason = "game is over; reset to play again"
+		result.ReasonCode = "game_over"
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, strategy.DefaultSolveBudget)
+	defer cancel()
+
+	start := time.Now()
+	moves, err := strategy.Solve(ctx, state, config)
+	result.ElapsedMs = time.Since(start).Milliseconds()
+
+	switch {
+	case err == nil:
+		result.Solved = true
+		result.Moves = moves
+		result.MoveCount = len(moves)
+	case errors.Is(err, strategy.ErrNoSolution):
+		result.Reason = err.Error()
+		result.ReasonCode = "budget_exhausted"
+	default:
+		result.Reason = err.Error()
+		result.ReasonCode = "unsolvable"
+	}
+	return result, nil
+}
+
+// SolvePlan searches for a winning plan like SolveGame and returns it as
+// annotated steps. A won game returns an empty plan and a lost one
+// engine.ErrGameOver; a search that finds no plan returns
+// strategy.ErrNoSolution or strategy.ErrUnsolvable.
+func (s *gameServiceImpl) SolvePlan(ctx context.Context, sessionID string) ([]PlanStep, error) {
+	s.mu.RLock()
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	state := sess.Engine.GetState()
+	config := sess.Config
+	s.mu.RUnlock()
+
+	if state.GameOver && !state.Victory {
+		return nil, fmt.Errorf("%w; reset to play again", engine.ErrGameOver)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, strategy.DefaultSolveBudget)
+	defer cancel()
+
+	plan, err := strategy.SolvePlan(ctx, state, config)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]PlanStep, len(plan))
+	for i, m := range plan {
+		steps[i] = PlanStep{Direction: m.Action, Purpose: m.Purpose, BatteryAfter: m.BatteryAfter, Target: m.Target}
+	}
+	return steps, nil
+}
+
+// CreateSharedSession starts a competitive session where the given players
+// race for the parks of one config
+func (s *gameServiceImpl) CreateSharedSession(ctx context.Context, configName string, playerIDs []string) (*SharedSessionInfo, error) {
+	return s.CreateSharedSessionWithOptions(ctx, configName, playerIDs, SharedSessionOptions{})
+}
+
+// CreateSharedSessionWithOptions is CreateSharedSession with session options
+func (s *gameServiceImpl) CreateSharedSessionWithOptions(ctx context.Context, configName string, playerIDs []string, opts SharedSessionOptions) (*SharedSessionInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var config *engine.GameConfig
+	if configName != "" {
+		var err error
+		config, err = s.configs.LoadConfig(configName)
+		if err != nil {
+			return nil, fmt.Errorf("config '%s' not found: %w", configName, err)
+		}
+	} else {
+		config = s.configs.GetDefault()
+		configName = s.getConfigID(config.Name)
+	}
+
+	game, err := engine.NewSharedGame(config, playerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create shared session: %w", err)
+	}
+
+	now := time.Now()
+	sess := &SharedSession{
+		ID:                s.newSharedSessionID(),
+		ConfigName:        configName,
+		Game:              game,
+		CreatedAt:         now,
+		LastAccessedAt:    now,
+		HideOpponentStats: opts.HideOpponentStats,
+	}
+	s.shared[sess.ID] = sess
+
+	return sharedSessionInfo(sess), nil
+}
+
+// GetSharedSession retrieves a shared session
+func (s *gameServiceImpl) GetSharedSession(ctx context.Context, sessionID string) (*SharedSessionInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, ok := s.shared[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("shared session not found: %s", sessionID)
+	}
+	return sharedSessionInfo(sess), nil
+}
+
+// MoveInShared moves one player of a shared session
+func (s *gameServiceImpl) MoveInShared(ctx context.Context, sessionID, playerID, direction string) (*SharedMoveResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.shared[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("shared session not found: %s", sessionID)
+	}
+	sess.LastAccessedAt = time.Now()
+
+	state := sess.Game.GetState()
+	owned := len(state.ParkOwners)
+	success, err := sess.Game.Move(playerID, direction)
+	if err != nil {
+		return nil, err
+	}
+
+	player := sess.Game.Player(playerID)
+	result := &SharedMoveResult{
+		Success:           success,
+		PlayerID:          playerID,
+		Player:            player,
+		Message:           player.Message,
+		State:             state,
+		HideOpponentStats: sess.HideOpponentStats,
+	}
+	if len(state.ParkOwners) > owned {
+		result.Collected = state.Grid[player.Position.Y][player.Position.X].ID
+	}
+	return result, nil
+}
+
+// newSharedSessionID returns an unused random ID. The "m" prefix keeps it
+// apart from the 4-character hex IDs of regular sessions; callers hold s.mu.
+func (s *gameServiceImpl) newSharedSessionID() string {
+	for {
+		b := make([]byte, 2)
+		rand.Read(b)
+		id := "m" + hex.EncodeToString(b)
+		if _, taken := s.shared[id]; !taken {
+			return id
+		}
+	}
+}
+
+func sharedSessionInfo(sess *SharedSession) *SharedSessionInfo {
+	return &SharedSessionInfo{
+		ID:                sess.ID,
+		ConfigName:        sess.ConfigName,
+		CreatedAt:         sess.CreatedAt,
+		LastAccessedAt:    sess.LastAccessedAt,
+		HideOpponentStats: sess.HideOpponentStats,
+		State:             sess.Game.GetState(),
+	}
+}
+
+// ViewFor returns the move result as the player viewer may see it. With
+// HideOpponentStats set and the game still on, every other player's
+// battery, score and message are masked, along with the parks they own and
+// what an opponent's move collected; viewer's own stats stay in full. An
+// empty viewer sees every player masked. The result itself is not changed.
+func (r *SharedMoveResult) ViewFor(viewer string) *SharedMoveResult {
+	if !r.HideOpponentStats || r.State == nil || r.State.GameOver {
+		return r
+	}
+	view := *r
+	state := *r.State
+	state.Players = make([]*engine.SharedPlayer, len(r.State.Players))
+	for i, p := range r.State.Players {
+		state.Players[i] = maskPlayer(p, viewer)
+	}
+	state.ParkOwners = make(map[string]string)
+	for park, owner := range r.State.ParkOwners {
+		if owner == viewer {
+			state.ParkOwners[park] = owner
+		}
+	}
+	view.State = &state
+	view.Player = maskPlayer(r.Player, viewer)
+	if r.PlayerID != viewer {
+		view.Message = ""
+		view.Collected = ""
+	}
+	return &view
+}
+
+// maskPlayer returns a copy of p with its battery, score and message hidden,
+// unless p is viewer
+func maskPlayer(p *engine.SharedPlayer, viewer string) *engine.SharedPlayer {
+	if p == nil || p.ID == viewer {
+		return p
+	}
+	masked := *p
+	masked.Battery = 0
+	masked.Score = 0
+	masked.Message = ""
+	masked.StatsHidden = true
+	return &masked
+}
+
+// traceSession replays the current move segment on a copy of the session's
+// engine to build per-move park and battery series. Parks are counted once
+// the engine collects them, so parks entered under require_park_action or
+// out of order under the ignore policy count only when actually collected,
+// and parks carried over the last reset count from the start.
+func traceSession(sessionID string, eng *engine.GameEngine) SessionTrace {
+	state := eng.GetState()
+	trace := SessionTrace{
+		SessionID:       sessionID,
+		MovesUsed:       len(state.CurrentMoves),
+		FinalBattery:    state.Battery,
+		Outcome:         traceOutcome(state),
+		ParksOverTime:   make([]int, 0, len(state.CurrentMoves)),
+		BatteryOverTime: make([]int, 0, len(state.CurrentMoves)),
+	}
+
+	replay := eng.Clone()
+	replay.Rewind(0)
+	for _, move := range state.CurrentMoves {
+		replay.ReplayMove(move)
+		trace.ParksOverTime = append(trace.ParksOverTime, len(replay.GetVisitedParks()))
+		trace.BatteryOverTime = append(trace.BatteryOverTime, move.Battery)
+	}
+	trace.ParksCollected = len(state.VisitedParks)
+
+	return trace
+}
+
+// traceOutcome summarizes how a session's current game stands
+func traceOutcome(state *engine.GameState) string {
+	switch {
+	case state.Victory:
+		return "victory"
+	case state.GameOver:
+		return "game_over"
+	}
+	return "in_progress"
+}
+
+// GetGhost returns the current run of fromSessionID as a ghost for sessionID
+// to race against. Both sessions must play the same config; positions that
+// fall outside the live session's grid are left out.
+func (s *gameServiceImpl) GetGhost(ctx context.Context, sessionID, fromSessionID string) (*GhostPath, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	live, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	saved, err := s.sessions.Get(fromSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+
+	if live.Config.Name != saved.Config.Name {
+		return nil, fmt.Errorf("%w: %s uses '%s', %s uses '%s'", ErrConfigMismatch,
+			live.ID, live.Config.Name, saved.ID, saved.Config.Name)
+	}
+
+	liveState := live.Engine.GetState()
+	savedState := saved.Engine.GetState()
+	ghost := &GhostPath{
+		SessionID:     live.ID,
+		FromSessionID: saved.ID,
+		ConfigName:    s.getConfigID(live.Config.Name),
+		Start:         savedState.PlayerPos,
+		Steps:         make([]GhostStep, 0, len(savedState.CurrentMoves)),
+		Outcome:       traceOutcome(savedState),
+	}
+	if len(savedState.CurrentMoves) > 0 {
+		ghost.Start = savedState.CurrentMoves[0].FromPosition
+	}
+
+	for i, move := range savedState.CurrentMoves {
+		pos := move.ToPosition
+		if !liveState.InBounds(pos.X, pos.Y) {
+			continue
+		}
+		ghost.Steps = append(ghost.Steps, GhostStep{
+			Step:          i + 1,
+			Action:        move.Action,
+			Position:      pos,
+			Battery:       move.Battery,
+			Success:       move.Success,
+			Timestamp:     move.Timestamp,
+			OffsetSeconds: move.Timestamp - savedState.CurrentMoves[0].Timestamp,
+		})
+	}
+	if n := len(ghost.Steps); n > 0 {
+		ghost.DurationSecs = ghost.Steps[n-1].OffsetSeconds
+	}
+
+	return ghost, nil
+}
+
 // ListConfigs returns available game configurations
 func (s *gameServiceImpl) ListConfigs(ctx context.Context) ([]*ConfigInfo, error) {
 	return s.configs.ListConfigs()
@@ -612,31 +1906,94 @@ func (s *gameServiceImpl) SaveConfig(ctx context.Context, configName string, con
 	return s.configs.SaveConfig(configName, config)
 }
 
-// extractMoveEvents generates events from a move
-func (s *gameServiceImpl) extractMoveEvents(sess *Session, prevPos, newPos engine.Position, direction string) []GameEvent {
+// AnalyzeConfig computes difficulty heuristics for a configuration
+func (s *gameServiceImpl) AnalyzeConfig(ctx context.Context, configName string) (*engine.ConfigAnalysis, error) {
+	config, err := s.configs.LoadConfig(configName)
+	if err != nil {
+		return nil, fmt.Errorf("config '%s' not found: %w", configName, err)
+	}
+
+	analysis := engine.AnalyzeConfig(config)
+	return &analysis, nil
+}
+
+// PreviewConfig returns the starting state of a configuration without creating a session
+func (s *gameServiceImpl) PreviewConfig(ctx context.Context, configName string) (*engine.GameState, error) {
+	config, err := s.configs.LoadConfig(configName)
+	if err != nil {
+		return nil, fmt.Errorf("config '%s' not found: %w", configName, err)
+	}
+	return engine.InitGameStateFromConfig(config), nil
+}
+
+// extractMoveEvents generates events from a move, given the state after it
+func (s *gameServiceImpl) extractMoveEvents(state *engine.GameState, prevPos, newPos engine.Position, direction string) []GameEvent {
 	events := []GameEvent{}
-	state := sess.Engine.GetState()
+
+	// Charging in place is its own event rather than a move
+	if direction == engine.ActionCharge {
+		events = append(events, GameEvent{
+			Type:      "charge",
+			Message:   fmt.Sprintf("Battery charged to %d/%d", state.Battery, state.MaxBattery),
+			Timestamp: time.Now(),
+			Position:  newPos,
+		})
+		return appendAfterMoveEvents(events, state)
+	}
+
+	// Parking collects in place; only the game can end as a result
+	if direction == engine.ActionPark {
+		if id := state.ParkCollectedThisMove(); id != "" {
+			events = append(events, GameEvent{
+				Type:      "park_visited",
+				Message:   fmt.Sprintf("Park %s visited! Score: %d", id, state.Score),
+				Timestamp: time.Now(),
+				Position:  newPos,
+			})
+		}
+		return appendAfterMoveEvents(events, state)
+	}
+
+	// Waiting spends a turn in place; only the game can end as a result
+	if direction == engine.ActionWait {
+		events = append(events, GameEvent{
+			Type:      "wait",
+			Message:   fmt.Sprintf("Waited at (%d,%d), battery %d/%d", newPos.X, newPos.Y, state.Battery, state.MaxBattery),
+			Timestamp: time.Now(),
+			Position:  newPos,
+		})
+		return appendAfterMoveEvents(events, state)
+	}
 
 	// Basic move event
-	events = append(events, GameEvent{
+	move := GameEvent{
 		Type:      "move",
 		Message:   fmt.Sprintf("Moved %s to (%d,%d)", direction, newPos.X, newPos.Y),
 		Timestamp: time.Now(),
 		Position:  newPos,
-	})
+	}
+	if direction == engine.ActionTeleport {
+		move.Type = "teleport"
+		move.Message = fmt.Sprintf("Teleported to (%d,%d)", newPos.X, newPos.Y)
+	}
+	events = append(events, move)
 
 	// Check if position actually changed (might be blocked)
 	if prevPos.X == newPos.X && prevPos.Y == newPos.Y {
-		return events // Move was blocked, no additional events
+		// Move was blocked; only a hazard stepping onto the player or an
+		// expiring park adds events
+		return appendAfterMoveEvents(events, state)
 	}
 
 	// Check for special cell events
-	if newPos.Y >= 0 && newPos.Y < len(state.Grid) &&
-		newPos.X >= 0 && newPos.X < len(state.Grid[0]) {
+	if state.InBounds(newPos.X, newPos.Y) {
 		cell := state.Grid[newPos.Y][newPos.X]
 
 		switch cell.Type {
-		case engine.Home, engine.Supercharger:
+		case engine.Home, engine.Supercharger, engine.Trickle:
+			if !state.ChargedThisMove(newPos.X, newPos.Y) {
+				break // A depleted or cooling charger
+			}
 			events = append(events, GameEvent{
 				Type:      "charge",
 				Message:   fmt.Sprintf("Battery charged to %d/%d", state.Battery, state.MaxBattery),
@@ -644,10 +2001,11 @@ func (s *gameServiceImpl) extractMoveEvents(sess *Session, prevPos, newPos engin
 				Position:  newPos,
 			})
 		case engine.Park:
-			if cell.Visited {
+			// Re-entering a park collected earlier collects nothing
+			if id := state.ParkCollectedThisMove(); id != "" {
 				events = append(events, GameEvent{
 					Type:      "park_visited",
-					Message:   fmt.Sprintf("Park %s visited! Score: %d", cell.ID, state.Score),
+					Message:   fmt.Sprintf("Park %s visited! Score: %d", id, state.Score),
 					Timestamp: time.Now(),
 					Position:  newPos,
 				})
@@ -655,7 +2013,66 @@ func (s *gameServiceImpl) extractMoveEvents(sess *Session, prevPos, newPos engin
 		}
 	}
 
-	// Check for game over events
+	// A fuel tile turns into road as it is picked up
+	if pickup := state.FuelPickedUpThisMove(); pickup != nil {
+		events = append(events, GameEvent{
+			Type:      "fuel_pickup",
+			Message:   fmt.Sprintf("Fuel picked up: +%d battery (%d/%d)", pickup.Amount, state.Battery, state.MaxBattery),
+			Timestamp: time.Now(),
+			Position:  newPos,
+		})
+	}
+
+	// A random event may follow the move
+	if n := len(state.MoveHistory); n > 0 && state.MoveHistory[n-1].RandomEvent != "" {
+		events = append(events, GameEvent{
+			Type:      "event",
+			Message:   state.MoveHistory[n-1].RandomEvent,
+			Timestamp: time.Now(),
+			Position:  newPos,
+		})
+	}
+
+	return appendAfterMoveEvents(events, state)
+}
+
+// appendAfterMoveEvents adds the events any move can end with: a hazard
+// hit, expired parks and the end of the game
+func appendAfterMoveEvents(events []GameEvent, state *engine.GameState) []GameEvent {
+	return appendGameOverEvents(appendParkExpiredEvents(appendHazardEvent(events, state), state), state)
+}
+
+// appendParkExpiredEvents adds a park_expired event for every park that
+// expired on the last move
+func appendParkExpiredEvents(events []GameEvent, state *engine.GameState) []GameEvent {
+	for _, p := range state.ParksExpiredThisMove() {
+		events = append(events, GameEvent{
+			Type:      "park_expired",
+			Message:   fmt.Sprintf("Park %s expired before it was collected", p.ParkID),
+			Timestamp: time.Now(),
+			Position:  p.Position,
+		})
+	}
+	return events
+}
+
+// appendHazardEvent adds a hazard_hit event when the player ran into a
+// patrolling hazard on the last move
+func appendHazardEvent(events []GameEvent, state *engine.GameState) []GameEvent {
+	if n := len(state.CurrentMoves); n == 0 || !state.CurrentMoves[n-1].HazardHit {
+		return events
+	}
+	return append(events, GameEvent{
+		Type: "hazard_hit",
+		Message: fmt.Sprintf("Hit by a hazard at (%d,%d), battery %d/%d",
+			state.PlayerPos.X, state.PlayerPos.Y, state.Battery, state.MaxBattery),
+		Timestamp: time.Now(),
+		Position:  state.PlayerPos,
+	})
+}
+
+// appendGameOverEvents adds the victory or game_over event for a finished game
+func appendGameOverEvents(events []GameEvent, state *engine.GameState) []GameEvent {
 	if state.GameOver {
 		if state.Victory {
 			events = append(events, GameEvent{
@@ -675,6 +2092,39 @@ func (s *gameServiceImpl) extractMoveEvents(sess *Session, prevPos, newPos engin
 	return events
 }
 
+// publish sends an event to every registered publisher
+func (s *gameServiceImpl) publish(event SessionEvent) {
+	for _, p := range s.publishers {
+		p.Publish(event)
+	}
+}
+
+// publishEvents records events in the session's event log and forwards them
+// to the event publishers, if any. A game that ended without a victory or
+// game_over event, such as by a wall crash or an exhausted battery, gets a
+// game_over event. Callers hold s.mu.
+func (s *gameServiceImpl) publishEvents(sess *Session, events []GameEvent, state *engine.GameState, wasOver bool) {
+	ended := false
+	for _, ev := range events {
+		if ev.Type == "victory" || ev.Type == "game_over" {
+			ended = true
+		}
+	}
+	if !wasOver && state.GameOver && !ended {
+		events = append(slices.Clip(events), GameEvent{
+			Type:      "game_over",
+			Message:   state.Message,
+			Timestamp: time.Now(),
+			Position:  state.PlayerPos,
+		})
+	}
+
+	s.logEvents(sess, events)
+	for _, ev := range events {
+		s.publish(SessionEvent{SessionID: sess.ID, Event: ev, State: state})
+	}
+}
+
 // Helpers for BulkMoveResult enrichment
 func mapCellToCharAndType(cell engine.Cell) (string, string) {
 	switch cell.Type {
@@ -689,6 +2139,19 @@ func mapCellToCharAndType(cell engine.Cell) (string, string) {
 		return "P", "park"
 	case engine.Supercharger:
 		return "S", "supercharger"
+	case engine.Fuel:
+		return "F", "fuel"
+	case engine.Trickle:
+		return "C", "trickle"
+	case engine.Checkpoint:
+		return "K", "checkpoint"
+	case engine.Key:
+		if cell.Visited {
+			return "Y", "key_collected"
+		}
+		return "Y", "key"
+	case engine.Door:
+		return "D", "door"
 	case engine.Water:
 		return "W", "water"
 	case engine.Building:
@@ -698,6 +2161,67 @@ func mapCellToCharAndType(cell engine.Cell) (string, string) {
 	}
 }
 
+// RenderGrid draws a game state's grid as text, one line per row, using the
+// layout characters with T for the player, X for a patrolling hazard and ✓
+// for a collected park
+func RenderGrid(state *engine.GameState) string {
+	var b strings.Builder
+	for y := range state.Grid {
+		for x := range state.Grid[y] {
+			switch {
+			case x == state.PlayerPos.X && y == state.PlayerPos.Y:
+				b.WriteString("T")
+			case state.HazardAt(x, y):
+				b.WriteString("X")
+			default:
+				ch, _ := mapCellToCharAndType(state.Grid[y][x])
+				b.WriteString(ch)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// describeAttempt reports the cell at an attempted target, treating out-of-bounds as boundary
+func describeAttempt(state *engine.GameState, x, y int) *AttemptInfo {
+	if !state.InBounds(x, y) {
+		return &AttemptInfo{X: x, Y: y, TileChar: "B", TileType: "boundary"}
+	}
+	cell := state.Grid[y][x]
+	tileChar, tileType := mapCellToCharAndType(cell)
+	return &AttemptInfo{
+		X:        x,
+		Y:        y,
+		TileChar: tileChar,
+		TileType: tileType,
+		Passable: state.CanMoveTo(x, y),
+	}
+}
+
+// buildMovePreviews simulates each possible move on a clone of the engine,
+// leaving the session's own state untouched
+func buildMovePreviews(eng *engine.GameEngine) map[string]engine.MovePreview {
+	current := eng.GetState()
+	previews := make(map[string]engine.MovePreview)
+	for _, dir := range eng.GetPossibleMoves() {
+		sim := eng.Clone()
+		sim.Move(dir)
+		to := sim.GetPlayerPosition()
+		cell := current.Grid[to.Y][to.X]
+		isCharger := engine.IsCharger(cell.Type)
+		tileChar, _ := mapCellToCharAndType(cell)
+		previews[dir] = engine.MovePreview{
+			To:           to,
+			TileChar:     tileChar,
+			Charges:      isCharger && sim.GetState().ChargedThisMove(to.X, to.Y),
+			Park:         cell.Type == engine.Park,
+			BatteryAfter: sim.GetBattery(),
+		}
+	}
+	return previews
+}
+
 func buildLocal3x3(state *engine.GameState) []string {
 	if state == nil {
 		return nil
@@ -713,7 +2237,7 @@ func buildLocal3x3(state *engine.GameState) []string {
 				continue
 			}
 			// out of bounds → treat as building wall
-			if y < 0 || y >= len(state.Grid) || x < 0 || x >= len(state.Grid[0]) {
+			if !state.InBounds(x, y) {
 				row.WriteString("B")
 				continue
 			}
@@ -725,22 +2249,65 @@ func buildLocal3x3(state *engine.GameState) []string {
 	return lines
 }
 
-func riskCode(text string) string {
-	t := strings.ToLower(text)
-	switch {
-	case strings.Contains(t, "critical"):
-		return "CRITICAL"
-	case strings.Contains(t, "danger"):
-		return "DANGER"
-	case strings.Contains(t, "caution"):
-		return "CAUTION"
-	case strings.Contains(t, "low"):
-		return "LOW"
-	case strings.Contains(t, "warning"):
-		return "WARNING"
-	case strings.Contains(t, "safe"):
-		return "SAFE"
-	default:
-		return "UNKNOWN"
+// buildRelativeView turns a 3x3 local view so heading points up. Cell
+// (forward, right) of the turned view, counted from the player, lies forward
+// steps along the heading and right steps along the heading turned clockwise.
+func buildRelativeView(heading string, local []string) *engine.RelativeView {
+	if len(local) != 3 {
+		return nil
+	}
+	cells := make([][]rune, len(local))
+	for i, line := range local {
+		if cells[i] = []rune(line); len(cells[i]) != 3 {
+			return nil
+		}
+	}
+
+	fx, fy := 0, -1
+	switch heading {
+	case "down":
+		fx, fy = 0, 1
+	case "left":
+		fx, fy = -1, 0
+	case "right":
+		fx, fy = 1, 0
+	}
+	rx, ry := -fy, fx
+	cell := func(forward, right int) rune {
+		return cells[1+forward*fy+right*ry][1+forward*fx+right*rx]
+	}
+
+	view := &engine.RelativeView{
+		Heading: heading,
+		Forward: string(cell(1, 0)),
+		Left:    string(cell(0, -1)),
+		Right:   string(cell(0, 1)),
+		Back:    string(cell(-1, 0)),
+	}
+	for forward := 1; forward >= -1; forward-- {
+		row := make([]rune, 0, 3)
+		for right := -1; right <= 1; right++ {
+			row = append(row, cell(forward, right))
+		}
+		view.Rows = append(view.Rows, string(row))
+	}
+	return view
+}
+
+// Ready checks that configs have been listed at least once and that session
+// storage is reachable. Configs are only listed until the first success, so
+// the check stays cheap for frequent probes.
+func (s *gameServiceImpl) Ready(ctx context.Context) error {
+	if !s.configsListed.Load() {
+		if _, err := s.configs.ListConfigs(); err != nil {
+			return fmt.Errorf("configs unavailable: %w", err)
+		}
+		s.configsListed.Store(true)
+	}
+	if checker, ok := s.sessions.(StorageChecker); ok {
+		if err := checker.CheckStorage(); err != nil {
+			return fmt.Errorf("session storage unreachable: %w", err)
+		}
 	}
+	return nil
 }
@@ -4,16 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/wricardo/tesla-road-trip-game/game/engine"
 	"github.com/wricardo/tesla-road-trip-game/game/service"
+	"github.com/wricardo/tesla-road-trip-game/game/strategy"
+	"github.com/wricardo/tesla-road-trip-game/internal/logtest"
 )
 
 // MockSessionManager implements service.SessionManager for testing
 type MockSessionManager struct {
 	sessions map[string]*service.Session
+	saves    atomic.Int32 // Successful Save calls
+	// saveFailures is how many of the next Save calls fail
+	saveFailures atomic.Int32
 }
 
 func NewMockSessionManager() *MockSessionManager {
@@ -89,7 +98,12 @@ func (m *MockSessionManager) Save(id string) error {
 	if _, exists := m.sessions[id]; !exists {
 		return errors.New("session not found")
 	}
+	if m.saveFailures.Load() > 0 {
+		m.saveFailures.Add(-1)
+		return errors.New("disk full")
+	}
 	// Mock save - in real implementation this would persist to disk
+	m.saves.Add(1)
 	return nil
 }
 
@@ -182,6 +196,11 @@ func (m *MockConfigManager) GetDefault() *engine.GameConfig {
 	return m.configs["default"]
 }
 
+func (m *MockConfigManager) SaveConfig(name string, config *engine.GameConfig) error {
+	m.configs[name] = config
+	return nil
+}
+
 // Test cases
 func TestGameService_CreateSession(t *testing.T) {
 	ctx := context.Background()
@@ -483,6 +502,70 @@ func TestGameService_GetMoveHistory(t *testing.T) {
 	}
 }
 
+func TestGameService_GetMove(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	id := sessionInfo.ID
+
+	// Two road steps, a park and a return to it before the reset, then water
+	// and a road step
+	if _, err := svc.BulkMove(ctx, id, []string{"left", "up", "up", "down", "up"}, false); err != nil {
+		t.Fatalf("Failed to make moves: %v", err)
+	}
+	if _, err := svc.Reset(ctx, id); err != nil {
+		t.Fatalf("Failed to reset: %v", err)
+	}
+	for _, dir := range []string{"down", "right"} {
+		if _, err := svc.Move(ctx, id, dir, false); err != nil {
+			t.Fatalf("Failed to move %s: %v", dir, err)
+		}
+	}
+
+	tests := []struct {
+		index   int
+		action  string
+		tile    engine.CellType
+		event   string
+		current bool
+	}{
+		{1, "left", engine.Road, service.MoveEventMove, false},
+		{3, "up", engine.Park, service.MoveEventPark, false},
+		{5, "up", engine.Park, service.MoveEventMove, false},
+		{6, "down", "", service.MoveEventBlocked, true},
+		{7, "right", engine.Road, service.MoveEventMove, true},
+	}
+	for _, tt := range tests {
+		move, err := svc.GetMove(ctx, id, tt.index)
+		if err != nil {
+			t.Fatalf("GetMove(%d) failed: %v", tt.index, err)
+		}
+		if move.Index != tt.index || move.TotalMoves != 7 || move.Action != tt.action {
+			t.Errorf("GetMove(%d) = index %d of %d, action %q", tt.index, move.Index, move.TotalMoves, move.Action)
+		}
+		if move.TileEntered != tt.tile || move.Event != tt.event || move.CurrentSegment != tt.current {
+			t.Errorf("GetMove(%d) = tile %q, event %q, current %v; want %q, %q, %v",
+				tt.index, move.TileEntered, move.Event, move.CurrentSegment, tt.tile, tt.event, tt.current)
+		}
+	}
+	if move, _ := svc.GetMove(ctx, id, 1); move.BatteryDelta >= 0 {
+		t.Errorf("Expected a road step to cost battery, got delta %d", move.BatteryDelta)
+	}
+
+	for _, index := range []int{0, 8} {
+		if _, err := svc.GetMove(ctx, id, index); !errors.Is(err, service.ErrMoveNotFound) {
+			t.Errorf("GetMove(%d) error = %v, want ErrMoveNotFound", index, err)
+		}
+	}
+	if _, err := svc.GetMove(ctx, "nonexistent", 1); err == nil {
+		t.Error("Expected an error for a missing session")
+	}
+}
+
 func TestGameService_ListSessions(t *testing.T) {
 	ctx := context.Background()
 	sessions := NewMockSessionManager()
@@ -539,3 +622,2620 @@ func TestGameService_Reset(t *testing.T) {
 	// Verify player is back at starting position
 	// (This would depend on your specific game logic)
 }
+
+func TestGameService_MoveWithOptions_TagsAutoplay(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	if _, err := svc.Move(ctx, sessionInfo.ID, "left", false); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if _, err := svc.MoveWithOptions(ctx, sessionInfo.ID, "right", service.MoveOptions{Autoplay: true}); err != nil {
+		t.Fatalf("MoveWithOptions failed: %v", err)
+	}
+
+	history, err := svc.GetMoveHistory(ctx, sessionInfo.ID, service.HistoryOptions{Order: "asc"})
+	if err != nil {
+		t.Fatalf("GetMoveHistory failed: %v", err)
+	}
+	if len(history.Moves) != 2 {
+		t.Fatalf("Expected 2 history entries, got %d", len(history.Moves))
+	}
+	if history.Moves[0].Autoplay {
+		t.Error("Expected human move not to be tagged as autoplay")
+	}
+	if !history.Moves[1].Autoplay {
+		t.Error("Expected autoplay move to be tagged")
+	}
+}
+
+func TestGameService_RecordsIntent(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	long := strings.Repeat("x", engine.MaxIntentLength+50)
+	if _, err := svc.MoveWithOptions(ctx, sessionInfo.ID, "left", service.MoveOptions{Intent: long}); err != nil {
+		t.Fatalf("MoveWithOptions failed: %v", err)
+	}
+	if _, err := svc.BulkMoveWithOptions(ctx, sessionInfo.ID, []string{"up", "up"}, service.BulkMoveOptions{Intent: "head for the park"}); err != nil {
+		t.Fatalf("BulkMoveWithOptions failed: %v", err)
+	}
+
+	history, err := svc.GetMoveHistory(ctx, sessionInfo.ID, service.HistoryOptions{Order: "asc"})
+	if err != nil {
+		t.Fatalf("GetMoveHistory failed: %v", err)
+	}
+	if len(history.Moves) != 3 {
+		t.Fatalf("Expected 3 history entries, got %d", len(history.Moves))
+	}
+	if got := len(history.Moves[0].Intent); got != engine.MaxIntentLength {
+		t.Errorf("Expected intent truncated to %d characters, got %d", engine.MaxIntentLength, got)
+	}
+	if history.Moves[1].Intent != "head for the park" {
+		t.Errorf("Expected bulk intent on first step, got %q", history.Moves[1].Intent)
+	}
+	if history.Moves[2].Intent != "" {
+		t.Errorf("Expected no intent on later bulk steps, got %q", history.Moves[2].Intent)
+	}
+}
+
+func TestGameService_BulkMovePerMoveIntents(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Home is at (3,2) with water above, so the blocked first step keeps the
+	// fallback intent; intents may be shorter than the moves
+	result, err := svc.BulkMoveWithOptions(ctx, sessionInfo.ID, []string{"up", "left", "down"}, service.BulkMoveOptions{
+		Intent:          "fallback",
+		Intents:         []string{"", "back to the road"},
+		ContinueOnBlock: true,
+	})
+	if err != nil {
+		t.Fatalf("BulkMoveWithOptions failed: %v", err)
+	}
+	want := []string{"fallback", "back to the road", ""}
+	if len(result.Steps) != len(want) {
+		t.Fatalf("Expected %d steps, got %+v", len(want), result.Steps)
+	}
+	for i, step := range result.Steps {
+		if step.Intent != want[i] {
+			t.Errorf("Step %d: expected intent %q, got %q", i+1, want[i], step.Intent)
+		}
+	}
+
+	history, err := svc.GetMoveHistory(ctx, sessionInfo.ID, service.HistoryOptions{Order: "asc"})
+	if err != nil {
+		t.Fatalf("GetMoveHistory failed: %v", err)
+	}
+	for i, move := range history.Moves {
+		if move.Intent != want[i] {
+			t.Errorf("History %d: expected intent %q, got %q", i+1, want[i], move.Intent)
+		}
+	}
+}
+
+func TestGameService_GetMoveHistory_ConfigPageSize(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	paged := *configs.configs["test"]
+	paged.DefaultHistoryPageSize = 3
+	configs.configs["paged"] = &paged
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	for _, name := range []string{"test", "paged"} {
+		sess, err := svc.CreateSession(ctx, name)
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		for i := 0; i < 5; i++ {
+			dir := []string{"left", "right"}[i%2]
+			if _, err := svc.Move(ctx, sess.ID, dir, false); err != nil {
+				t.Fatalf("Move failed: %v", err)
+			}
+		}
+
+		// Without a limit the config's page size applies, else the global default
+		want := engine.DefaultHistoryPageSize
+		if name == "paged" {
+			want = 3
+		}
+		history, err := svc.GetMoveHistory(ctx, sess.ID, service.HistoryOptions{})
+		if err != nil {
+			t.Fatalf("GetMoveHistory failed: %v", err)
+		}
+		if history.PageSize != want || len(history.Moves) != min(want, 5) {
+			t.Errorf("%s: expected page size %d, got %d with %d moves", name, want, history.PageSize, len(history.Moves))
+		}
+
+		// A limit in the request wins over the config
+		history, err = svc.GetMoveHistory(ctx, sess.ID, service.HistoryOptions{Limit: 4})
+		if err != nil {
+			t.Fatalf("GetMoveHistory failed: %v", err)
+		}
+		if history.PageSize != 4 || len(history.Moves) != 4 || history.TotalPages != 2 {
+			t.Errorf("%s: expected the requested page size 4, got %d with %d moves over %d pages",
+				name, history.PageSize, len(history.Moves), history.TotalPages)
+		}
+	}
+}
+
+func TestGameService_CompareSessions(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	other := *configs.configs["test"]
+	other.Name = "Other Config"
+	configs.configs["other"] = &other
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	a, _ := svc.CreateSession(ctx, "test")
+	b, _ := svc.CreateSession(ctx, "test")
+	c, _ := svc.CreateSession(ctx, "other")
+
+	for _, dir := range []string{"left", "right", "down"} {
+		svc.Move(ctx, a.ID, dir, false)
+	}
+	for _, dir := range []string{"left", "right"} {
+		svc.Move(ctx, b.ID, dir, false)
+	}
+
+	comparison, err := svc.CompareSessions(ctx, a.ID, b.ID)
+	if err != nil {
+		t.Fatalf("CompareSessions failed: %v", err)
+	}
+	if comparison.A.MovesUsed != 3 || comparison.B.MovesUsed != 2 {
+		t.Errorf("Expected 3 and 2 moves, got %d and %d", comparison.A.MovesUsed, comparison.B.MovesUsed)
+	}
+	if !comparison.Diverged || comparison.DivergedAtMove != 3 {
+		t.Errorf("Expected divergence at move 3, got diverged=%v at %d", comparison.Diverged, comparison.DivergedAtMove)
+	}
+	if len(comparison.A.BatteryOverTime) != 3 || len(comparison.A.ParksOverTime) != 3 {
+		t.Errorf("Expected per-move series of length 3, got battery=%d parks=%d",
+			len(comparison.A.BatteryOverTime), len(comparison.A.ParksOverTime))
+	}
+	if comparison.A.Outcome != "in_progress" {
+		t.Errorf("Expected outcome in_progress, got %s", comparison.A.Outcome)
+	}
+
+	if _, err := svc.CompareSessions(ctx, a.ID, c.ID); !errors.Is(err, service.ErrConfigMismatch) {
+		t.Errorf("Expected ErrConfigMismatch, got %v", err)
+	}
+	if _, err := svc.CompareSessions(ctx, a.ID, "missing"); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestGameService_CompareSessionsCountsCollectedParks(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	parking := *configs.configs["test"]
+	parking.Name = "parking"
+	parking.RequireParkAction = true
+	parking.ResetPreservesScore = true
+	configs.SaveConfig("parking", &parking)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	a, _ := svc.CreateSession(ctx, "parking")
+	b, _ := svc.CreateSession(ctx, "parking")
+
+	// Both drive onto the park at (2,0); only a parks there
+	svc.BulkMove(ctx, a.ID, []string{"left", "up", "up", engine.ActionPark}, false)
+	svc.BulkMove(ctx, b.ID, []string{"left", "up", "up"}, false)
+
+	comparison, err := svc.CompareSessions(ctx, a.ID, b.ID)
+	if err != nil {
+		t.Fatalf("CompareSessions failed: %v", err)
+	}
+	if got := comparison.A.ParksOverTime; !reflect.DeepEqual(got, []int{0, 0, 0, 1}) || comparison.A.ParksCollected != 1 {
+		t.Errorf("Expected the park counted from the park action, got %v and %d", got, comparison.A.ParksCollected)
+	}
+	if got := comparison.B.ParksOverTime; !reflect.DeepEqual(got, []int{0, 0, 0}) || comparison.B.ParksCollected != 0 {
+		t.Errorf("Expected entering the park not to count, got %v and %d", got, comparison.B.ParksCollected)
+	}
+
+	// The park a carries over a reset counts from the first move
+	if _, err := svc.Reset(ctx, a.ID); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	svc.Move(ctx, a.ID, "left", false)
+	comparison, err = svc.CompareSessions(ctx, a.ID, b.ID)
+	if err != nil {
+		t.Fatalf("CompareSessions failed: %v", err)
+	}
+	if got := comparison.A.ParksOverTime; !reflect.DeepEqual(got, []int{1}) || comparison.A.ParksCollected != 1 {
+		t.Errorf("Expected the carried park counted, got %v and %d", got, comparison.A.ParksCollected)
+	}
+}
+
+func TestGameService_GetGhost(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	other := *configs.configs["test"]
+	other.Name = "Other Config"
+	configs.configs["other"] = &other
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	saved, _ := svc.CreateSession(ctx, "test")
+	live, _ := svc.CreateSession(ctx, "test")
+	mismatched, _ := svc.CreateSession(ctx, "other")
+
+	start, _ := svc.GetGameState(ctx, saved.ID)
+	startPos := start.PlayerPos
+	for _, dir := range []string{"left", "left", "up"} {
+		svc.Move(ctx, saved.ID, dir, false)
+	}
+	svc.Move(ctx, live.ID, "right", false)
+
+	ghost, err := svc.GetGhost(ctx, live.ID, saved.ID)
+	if err != nil {
+		t.Fatalf("GetGhost failed: %v", err)
+	}
+	if ghost.SessionID != live.ID || ghost.FromSessionID != saved.ID || ghost.Start != startPos {
+		t.Errorf("Unexpected ghost header %+v", ghost)
+	}
+	if len(ghost.Steps) != 3 {
+		t.Fatalf("Expected 3 ghost steps, got %d", len(ghost.Steps))
+	}
+	history, _ := svc.GetMoveHistory(ctx, saved.ID, service.HistoryOptions{Order: "asc"})
+	for i, step := range ghost.Steps {
+		if step.Step != i+1 || step.Position != history.Moves[i].ToPosition || step.OffsetSeconds < 0 {
+			t.Errorf("Step %d: got %+v, history has %+v", i+1, step, history.Moves[i])
+		}
+	}
+
+	if _, err := svc.GetGhost(ctx, live.ID, mismatched.ID); !errors.Is(err, service.ErrConfigMismatch) {
+		t.Errorf("Expected ErrConfigMismatch, got %v", err)
+	}
+	if _, err := svc.GetGhost(ctx, live.ID, "missing"); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestGameService_BulkMoveContinueOnBlock(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// From home (3,2): down hits water, then two lefts on road, up hits water again
+	moves := []string{"down", "left", "left", "up", "right"}
+
+	// Default behavior stops at the first block
+	stopped, err := svc.BulkMove(ctx, sessionInfo.ID, moves, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if stopped.MovesExecuted != 0 || stopped.StoppedOnMove != 1 {
+		t.Errorf("Expected stop on move 1 with no moves executed, got executed=%d stopped_on=%d",
+			stopped.MovesExecuted, stopped.StoppedOnMove)
+	}
+
+	result, err := svc.BulkMoveWithOptions(ctx, sessionInfo.ID, moves, service.BulkMoveOptions{Reset: true, ContinueOnBlock: true})
+	if err != nil {
+		t.Fatalf("BulkMoveWithOptions failed: %v", err)
+	}
+	if result.MovesExecuted != 3 || result.BlockedCount != 2 {
+		t.Errorf("Expected 3 executed and 2 blocked, got %d and %d", result.MovesExecuted, result.BlockedCount)
+	}
+	if result.Success {
+		t.Error("Expected success=false when moves were blocked")
+	}
+	if len(result.Steps) != 5 {
+		t.Fatalf("Expected 5 steps, got %d", len(result.Steps))
+	}
+	for _, idx := range []int{0, 3} {
+		step := result.Steps[idx]
+		if step.Success || step.AttemptedTo == nil {
+			t.Errorf("Expected step %d to be a failed step with attempted_to", step.Idx)
+		}
+		if step.BatteryBefore != step.BatteryAfter {
+			t.Errorf("Expected no battery consumed on blocked step %d", step.Idx)
+		}
+	}
+	if result.StoppedReason != "" {
+		t.Errorf("Expected no stop reason, got %s", result.StoppedReason)
+	}
+}
+
+func TestGameService_BulkMoveStopBelowBattery(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// From home (3,2) with 10 battery, along the middle row and up the left edge
+	moves := []string{"left", "left", "left", "up", "up", "right"}
+
+	// The fourth move takes the battery from 7 to 6, below the threshold
+	result, err := svc.BulkMoveWithOptions(ctx, sessionInfo.ID, moves, service.BulkMoveOptions{StopBelowBattery: 7})
+	if err != nil {
+		t.Fatalf("BulkMoveWithOptions failed: %v", err)
+	}
+	if result.StopReasonCode != "low_battery" || result.StoppedOnMove != 4 {
+		t.Errorf("Expected low_battery stop on move 4, got code=%q stopped_on=%d", result.StopReasonCode, result.StoppedOnMove)
+	}
+	if result.MovesExecuted != 4 || result.EndBattery != 6 {
+		t.Errorf("Expected 4 moves executed ending at battery 6, got %d and %d", result.MovesExecuted, result.EndBattery)
+	}
+	if !result.Success {
+		t.Error("Expected a low-battery stop to leave success=true")
+	}
+
+	// A threshold of 0 disables the check
+	result, err = svc.BulkMoveWithOptions(ctx, sessionInfo.ID, moves, service.BulkMoveOptions{Reset: true})
+	if err != nil {
+		t.Fatalf("BulkMoveWithOptions failed: %v", err)
+	}
+	if result.MovesExecuted != len(moves) || result.StopReasonCode != "" {
+		t.Errorf("Expected all %d moves without a stop, got %d (code=%q)", len(moves), result.MovesExecuted, result.StopReasonCode)
+	}
+}
+
+func TestGameService_BulkMoveMulti(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	walled := *configs.configs["test"]
+	walled.Name = "walled"
+	walled.Layout = []string{"RRPRR", "RWWWR", "RRRHR", "RWRWR", "RRPRR"}
+	configs.SaveConfig("walled", &walled)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	a, _ := svc.CreateSession(ctx, "test")
+	b, _ := svc.CreateSession(ctx, "test")
+	c, _ := svc.CreateSession(ctx, "walled")
+
+	// The same moves reach the park at (2,0) on the test config, and hit the
+	// water on the walled one; the unknown session fails on its own
+	moves := []string{"left", "up", "up"}
+	multi, err := svc.BulkMoveMulti(ctx, []string{a.ID, b.ID, "missing", c.ID}, moves, service.BulkMoveOptions{})
+	if err != nil {
+		t.Fatalf("BulkMoveMulti failed: %v", err)
+	}
+	if multi.Succeeded != 3 || multi.Failed != 1 || len(multi.Results) != 3 || multi.Errors["missing"] == "" {
+		t.Fatalf("Expected three results and an error for the missing session, got %+v", multi)
+	}
+	for _, id := range []string{a.ID, b.ID} {
+		if r := multi.Results[id]; r.MovesExecuted != 3 || r.ScoreDelta != 1 {
+			t.Errorf("Expected session %s to collect the park, got %+v", id, r)
+		}
+	}
+	if r := multi.Results[c.ID]; r.MovesExecuted != 1 || r.StopReasonCode != "blocked_water" {
+		t.Errorf("Expected the walled session to be blocked after one move, got %+v", r)
+	}
+	if state, _ := svc.GetGameState(ctx, a.ID); state.Score != 1 {
+		t.Errorf("Expected the moves to be applied to the session, got score %d", state.Score)
+	}
+
+	// Only a bad list fails the whole request
+	for _, ids := range [][]string{nil, {a.ID, a.ID}} {
+		if _, err := svc.BulkMoveMulti(ctx, ids, moves, service.BulkMoveOptions{}); !errors.Is(err, service.ErrInvalidSessionList) {
+			t.Errorf("Expected ErrInvalidSessionList for %v, got %v", ids, err)
+		}
+	}
+}
+
+func TestGameService_GameOverReason(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// From home (3,2) visit the parks at (2,0) and (2,4)
+	moves := []string{"left", "up", "up", "down", "down", "down", "down"}
+	result, err := svc.BulkMove(ctx, sessionInfo.ID, moves, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if !result.GameOver || result.GameState.GameOverReason != engine.GameOverVictory {
+		t.Fatalf("Expected victory, got game_over=%v reason=%q", result.GameOver, result.GameState.GameOverReason)
+	}
+	if result.GameOverCode != "victory" || result.StopReasonCode != "victory" {
+		t.Errorf("Expected victory codes, got game_over_code=%q stop_reason_code=%q",
+			result.GameOverCode, result.StopReasonCode)
+	}
+
+	info, err := svc.GetSession(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if info.GameOverReason != engine.GameOverVictory {
+		t.Errorf("Expected session summary reason victory, got %q", info.GameOverReason)
+	}
+
+	// Reset clears the reason
+	state, err := svc.Reset(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if state.GameOverReason != "" {
+		t.Errorf("Expected reason cleared on reset, got %q", state.GameOverReason)
+	}
+}
+
+func TestGameService_LastMoveOutcome(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if sessionInfo.GameState.LastMoveOutcome != "" {
+		t.Errorf("Expected no outcome before the first move, got %q", sessionInfo.GameState.LastMoveOutcome)
+	}
+
+	// Water above home at (3,2)
+	result, err := svc.Move(ctx, sessionInfo.ID, "up", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if result.Success || result.GameState.LastMoveOutcome != engine.MoveOutcomeBlocked {
+		t.Errorf("Expected blocked move, got success=%v outcome=%q", result.Success, result.GameState.LastMoveOutcome)
+	}
+	state, err := svc.GetGameState(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("GetGameState failed: %v", err)
+	}
+	if state.LastMoveOutcome != engine.MoveOutcomeBlocked {
+		t.Errorf("Expected fetched state to report blocked, got %q", state.LastMoveOutcome)
+	}
+
+	result, err = svc.Move(ctx, sessionInfo.ID, "left", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if result.GameState.LastMoveOutcome != engine.MoveOutcomeMoved {
+		t.Errorf("Expected moved, got %q", result.GameState.LastMoveOutcome)
+	}
+
+	// (2,2) -> (2,1) -> park at (2,0)
+	bulk, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"up", "up"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if bulk.GameState.LastMoveOutcome != engine.MoveOutcomeCollected {
+		t.Errorf("Expected collected, got %q", bulk.GameState.LastMoveOutcome)
+	}
+
+	// Re-entering the collected park collects nothing
+	bulk, err = svc.BulkMove(ctx, sessionInfo.ID, []string{"down", "up"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if bulk.GameState.LastMoveOutcome != engine.MoveOutcomeMoved {
+		t.Errorf("Expected moved on re-entering the park, got %q", bulk.GameState.LastMoveOutcome)
+	}
+	for _, ev := range bulk.Events {
+		if ev.Type == "park_visited" {
+			t.Errorf("Expected no park_visited event on re-entering the park, got %+v", ev)
+		}
+	}
+
+	state, err = svc.Reset(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if state.LastMoveOutcome != "" {
+		t.Errorf("Expected reset to clear the outcome, got %q", state.LastMoveOutcome)
+	}
+}
+
+func TestGameService_DeleteSessions(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewMockSessionManager()
+	configs := NewMockConfigManager()
+	other := *configs.configs["test"]
+	other.Name = "other"
+	configs.SaveConfig("other", &other)
+	svc := service.NewGameService(sessions, configs)
+
+	create := func(configName string) string {
+		info, err := svc.CreateSession(ctx, configName)
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		return info.ID
+	}
+	won, playing, elsewhere := create("test"), create("test"), create("other")
+	// Both parks from home
+	if _, err := svc.BulkMove(ctx, won, []string{"left", "up", "up", "down", "down", "down", "down"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+
+	if _, err := svc.DeleteSessions(ctx, service.SessionFilter{}); !errors.Is(err, service.ErrEmptySessionFilter) {
+		t.Errorf("Expected ErrEmptySessionFilter, got %v", err)
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter service.SessionFilter
+		want   string
+	}{
+		{"by config", service.SessionFilter{ConfigName: "other"}, elsewhere},
+		{"by game over", service.SessionFilter{GameOver: &yes}, won},
+		{"by config and game over", service.SessionFilter{ConfigName: "test", GameOver: &no}, playing},
+	}
+	for _, tt := range tests {
+		deleted, err := svc.DeleteSessions(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: DeleteSessions failed: %v", tt.name, err)
+		}
+		if len(deleted) != 1 || deleted[0] != tt.want {
+			t.Errorf("%s: expected to delete %s, got %v", tt.name, tt.want, deleted)
+		}
+		if _, err := svc.GetSession(ctx, tt.want); err == nil {
+			t.Errorf("%s: session %s still exists", tt.name, tt.want)
+		}
+	}
+
+	if remaining, _ := svc.ListSessions(ctx); len(remaining) != 0 {
+		t.Errorf("Expected no sessions left, got %d", len(remaining))
+	}
+}
+
+func TestGameService_CloneSession(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	original, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	// From home (3,2) up to the park at (2,0)
+	if _, err := svc.BulkMove(ctx, original.ID, []string{"left", "up", "up"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	before, _ := svc.GetGameState(ctx, original.ID)
+
+	clone, err := svc.CloneSession(ctx, original.ID, false)
+	if err != nil {
+		t.Fatalf("CloneSession failed: %v", err)
+	}
+	if clone.ID == original.ID {
+		t.Fatal("Expected the clone to get a fresh ID")
+	}
+	cs := clone.GameState
+	if cs.PlayerPos != before.PlayerPos || cs.Battery != before.Battery || cs.Score != before.Score {
+		t.Errorf("Expected clone at %+v with battery %d and score %d, got %+v, %d, %d",
+			before.PlayerPos, before.Battery, before.Score, cs.PlayerPos, cs.Battery, cs.Score)
+	}
+	if len(cs.VisitedParks) != 1 || len(cs.MoveHistory) != 0 || cs.TotalMoves != 0 {
+		t.Errorf("Expected 1 visited park and no history, got %d parks and %d moves",
+			len(cs.VisitedParks), len(cs.MoveHistory))
+	}
+
+	// Playing on the clone, down to the park at (2,4), leaves the original untouched
+	if _, err := svc.BulkMove(ctx, clone.ID, []string{"down", "down", "down", "down"}, false); err != nil {
+		t.Fatalf("BulkMove on clone failed: %v", err)
+	}
+	after, _ := svc.GetGameState(ctx, original.ID)
+	if after.PlayerPos != before.PlayerPos || after.Battery != before.Battery || after.TotalMoves != 3 {
+		t.Errorf("Original changed after moving the clone: pos=%+v battery=%d moves=%d",
+			after.PlayerPos, after.Battery, after.TotalMoves)
+	}
+	if after.Grid[4][2].Visited || len(after.VisitedParks) != 1 || len(after.MoveHistory) != 3 {
+		t.Errorf("Original parks or history changed: %d parks, %d moves", len(after.VisitedParks), len(after.MoveHistory))
+	}
+
+	withHistory, err := svc.CloneSession(ctx, original.ID, true)
+	if err != nil {
+		t.Fatalf("CloneSession with history failed: %v", err)
+	}
+	if len(withHistory.GameState.MoveHistory) != 3 || withHistory.GameState.TotalMoves != 3 {
+		t.Errorf("Expected 3 copied moves, got %d", len(withHistory.GameState.MoveHistory))
+	}
+
+	if _, err := svc.CloneSession(ctx, "missing", false); err == nil {
+		t.Error("Expected an error cloning a missing session")
+	}
+}
+
+func TestGameService_SaveDebounce(t *testing.T) {
+	ctx := context.Background()
+
+	// Home (3,2) and the road to its left, back and forth; home keeps charging
+	moves := func(svc service.GameService, sessionID string) {
+		for i := 0; i < 100; i++ {
+			dir := "left"
+			if i%2 == 1 {
+				dir = "right"
+			}
+			if _, err := svc.Move(ctx, sessionID, dir, false); err != nil {
+				t.Fatalf("Move %d failed: %v", i+1, err)
+			}
+		}
+	}
+
+	sessions := NewMockSessionManager()
+	svc := service.NewGameService(sessions, NewMockConfigManager())
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	moves(svc, sessionInfo.ID)
+	if got := sessions.saves.Load(); got != 100 {
+		t.Errorf("Expected a save per move without debounce, got %d", got)
+	}
+
+	sessions = NewMockSessionManager()
+	svc = service.NewGameService(sessions, NewMockConfigManager(), service.WithSaveDebounce(time.Hour))
+	sessionInfo, err = svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	moves(svc, sessionInfo.ID)
+	if got := sessions.saves.Load(); got != 0 {
+		t.Errorf("Expected saves to be deferred, got %d", got)
+	}
+
+	svc.(service.SaveFlusher).FlushSaves()
+	if got := sessions.saves.Load(); got != 1 {
+		t.Errorf("Expected one save on flush, got %d", got)
+	}
+	svc.(service.SaveFlusher).FlushSaves()
+	if got := sessions.saves.Load(); got != 1 {
+		t.Errorf("Expected nothing left to flush, got %d saves", got)
+	}
+
+	// A game that just ended is written straight away: both parks, from home
+	won, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	result, err := svc.BulkMove(ctx, won.ID, []string{"left", "up", "up", "down", "down", "down", "down"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if !result.GameState.Victory {
+		t.Fatalf("Expected victory, got %+v", result.GameState.Message)
+	}
+	if got := sessions.saves.Load(); got != 2 {
+		t.Errorf("Expected the finished game to be saved immediately, got %d saves", got)
+	}
+}
+
+func TestGameService_SaveDebounceTimer(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewMockSessionManager()
+	svc := service.NewGameService(sessions, NewMockConfigManager(), service.WithSaveDebounce(20*time.Millisecond))
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := svc.Move(ctx, sessionInfo.ID, "left", false); err != nil {
+			t.Fatalf("Move failed: %v", err)
+		}
+		if _, err := svc.Move(ctx, sessionInfo.ID, "right", false); err != nil {
+			t.Fatalf("Move failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for sessions.saves.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := sessions.saves.Load(); got < 1 || got > 3 {
+		t.Errorf("Expected the debounced save to fire once or twice, got %d", got)
+	}
+}
+
+func TestGameService_SaveRetry(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewMockSessionManager()
+	logger := &logtest.Recorder{}
+	svc := service.NewGameService(sessions, NewMockConfigManager(),
+		service.WithSaveRetry(10*time.Millisecond, 50*time.Millisecond),
+		service.WithLogger(logger))
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	saved := sessions.saves.Load()
+	sessions.saveFailures.Store(1)
+	result, err := svc.Move(ctx, sessionInfo.ID, "left", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if !result.GameState.PersistenceDegraded {
+		t.Fatal("Expected persistence to be degraded after a failed save")
+	}
+	warnings := logger.Find("failed to persist session")
+	if len(warnings) != 1 || warnings[0].Level != "WARN" || warnings[0].Attrs["session_id"] != sessionInfo.ID || warnings[0].Attrs["action"] != "move" {
+		t.Errorf("Expected the failed save to be logged at warn, got %+v", warnings)
+	}
+
+	// The retry in the background saves the session and clears the flag
+	deadline := time.Now().Add(time.Second)
+	for sessions.saves.Load() == saved && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := sessions.saves.Load(); got != saved+1 {
+		t.Fatalf("Expected the failed save to be retried, got %d saves", got)
+	}
+	state, err := svc.GetGameState(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("GetGameState failed: %v", err)
+	}
+	if state.PersistenceDegraded {
+		t.Error("Expected persistence to recover after the retry")
+	}
+	if got := logger.Find("persisted session after failed saves"); len(got) != 1 || got[0].Level != "INFO" {
+		t.Errorf("Expected the recovery to be logged, got %+v", got)
+	}
+}
+
+func TestGameService_MovePreviews(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// From home (3,2) to (2,1), just below the park at (2,0)
+	if _, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"left", "up"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+
+	state, err := svc.GetGameState(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("GetGameState failed: %v", err)
+	}
+
+	up, ok := state.MovePreviews["up"]
+	if !ok {
+		t.Fatalf("Expected preview for up, got %+v", state.MovePreviews)
+	}
+	if !up.Park || up.TileChar != "P" || up.To != (engine.Position{X: 2, Y: 0}) {
+		t.Errorf("Expected up to lead to the park at (2,0), got %+v", up)
+	}
+	if up.BatteryAfter != state.Battery-1 {
+		t.Errorf("Expected battery after %d, got %d", state.Battery-1, up.BatteryAfter)
+	}
+	if down := state.MovePreviews["down"]; down.Park || down.Charges {
+		t.Errorf("Expected plain road below, got %+v", down)
+	}
+	if _, ok := state.MovePreviews["left"]; ok {
+		t.Error("Expected no preview into water")
+	}
+
+	// Simulation must not touch the real session
+	if state.PlayerPos != (engine.Position{X: 2, Y: 1}) || state.Score != 0 || state.TotalMoves != 2 {
+		t.Errorf("Session mutated by previews: pos=%+v score=%d moves=%d", state.PlayerPos, state.Score, state.TotalMoves)
+	}
+	if state.Grid[0][2].Visited || len(state.VisitedParks) != 0 {
+		t.Error("Park marked visited by preview simulation")
+	}
+}
+
+// recordingPublisher collects published events for assertions. Auto-resets
+// publish from timer goroutines, so access is locked.
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []service.SessionEvent
+}
+
+func (p *recordingPublisher) Publish(event service.SessionEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) snapshot() []service.SessionEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]service.SessionEvent(nil), p.events...)
+}
+
+// types lists the published event types, skipping plain move events
+func (p *recordingPublisher) types() []string {
+	events := p.snapshot()
+	types := make([]string, 0, len(events))
+	for _, ev := range events {
+		if ev.Event.Type != "move" {
+			types = append(types, ev.Event.Type)
+		}
+	}
+	return types
+}
+
+func TestGameService_PublishesEvents(t *testing.T) {
+	ctx := context.Background()
+	pub := &recordingPublisher{}
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager(), service.WithEventPublisher(pub))
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if events := pub.snapshot(); len(events) != 1 || events[0].Event.Type != "session_created" || events[0].SessionID != sessionInfo.ID {
+		t.Fatalf("Expected session_created event, got %v", pub.types())
+	}
+
+	// Visit both parks from home (3,2) and return for victory
+	moves := []string{"left", "up", "up", "down", "down", "down", "down"}
+	if _, err := svc.BulkMove(ctx, sessionInfo.ID, moves, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+
+	got := strings.Join(pub.types(), ",")
+	want := "session_created,park_visited,park_visited,victory"
+	if got != want {
+		t.Errorf("Expected events %s, got %s", want, got)
+	}
+	events := pub.snapshot()
+	last := events[len(events)-1]
+	if last.State == nil || !last.State.Victory {
+		t.Error("Expected victory event to carry the final state")
+	}
+
+	// Moves after the game ended publish nothing further
+	count := len(events)
+	svc.Move(ctx, sessionInfo.ID, "up", false)
+	if n := len(pub.snapshot()); n != count {
+		t.Errorf("Expected no events after game over, got %d more", n-count)
+	}
+}
+
+func TestGameService_AutoReset(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	autoReset := *configs.configs["test"]
+	autoReset.Name = "autoreset"
+	autoReset.AutoResetSeconds = 1
+	configs.SaveConfig("autoreset", &autoReset)
+
+	pub := &recordingPublisher{}
+	svc := service.NewGameService(NewMockSessionManager(), configs, service.WithEventPublisher(pub))
+
+	endGame := func(moves []string) string {
+		t.Helper()
+		info, err := svc.CreateSession(ctx, "autoreset")
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		result, err := svc.BulkMove(ctx, info.ID, moves, false)
+		if err != nil {
+			t.Fatalf("BulkMove failed: %v", err)
+		}
+		if !result.GameOver {
+			t.Fatalf("Expected game over after %v", moves)
+		}
+		return info.ID
+	}
+
+	// Visit both parks from home (3,2) for victory
+	victory := []string{"left", "up", "up", "down", "down", "down", "down"}
+	// Drain the battery between (4,2) and (4,1)
+	defeat := []string{"right", "up", "down", "up", "down", "up", "down", "up", "down", "up", "down"}
+
+	won := endGame(victory)
+	lost := endGame(defeat)
+	manual := endGame(victory)
+	deleted := endGame(victory)
+
+	// Ending the same session again must replace, not add, its timer
+	if _, err := svc.Reset(ctx, manual); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if _, err := svc.BulkMove(ctx, manual, victory, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if _, err := svc.Reset(ctx, manual); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if err := svc.DeleteSession(ctx, deleted); err != nil {
+		t.Fatalf("DeleteSession failed: %v", err)
+	}
+
+	autoResets := func() map[string]int {
+		counts := map[string]int{}
+		for _, ev := range pub.snapshot() {
+			if ev.Event.Type == "auto_reset" {
+				counts[ev.SessionID]++
+			}
+		}
+		return counts
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for len(autoResets()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	// Leave time for any stray timer to fire
+	time.Sleep(200 * time.Millisecond)
+
+	counts := autoResets()
+	if counts[won] != 1 || counts[lost] != 1 {
+		t.Errorf("Expected one auto-reset each after victory and defeat, got %v", counts)
+	}
+	if counts[manual] != 0 || counts[deleted] != 0 {
+		t.Errorf("Expected manual reset and delete to cancel auto-reset, got %v", counts)
+	}
+
+	for _, id := range []string{won, lost} {
+		state, err := svc.GetGameState(ctx, id)
+		if err != nil {
+			t.Fatalf("GetGameState failed: %v", err)
+		}
+		if state.GameOver || state.CurrentMovesCount != 0 || state.Battery != autoReset.StartingBattery {
+			t.Errorf("Expected session %s to be reset, got game_over=%v current_moves=%d battery=%d",
+				id, state.GameOver, state.CurrentMovesCount, state.Battery)
+		}
+	}
+}
+
+func TestGameService_IncrementalCharging(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	slow := *configs.configs["test"]
+	slow.Name = "slowcharge"
+	slow.ChargePerTurn = 3
+	configs.SaveConfig("slowcharge", &slow)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	sessionInfo, err := svc.CreateSession(ctx, "slowcharge")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Loop out from home (3,2) and back: 10-4 = 6, plus 3 on arrival
+	result, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"right", "up", "down", "left"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if result.GameState.Battery != 9 {
+		t.Fatalf("Expected battery 9 after returning home, got %d", result.GameState.Battery)
+	}
+
+	moveResult, err := svc.Move(ctx, sessionInfo.ID, engine.ActionCharge, false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if !moveResult.Success || moveResult.GameState.Battery != 10 {
+		t.Errorf("Expected charge to reach full battery, got success=%v battery=%d", moveResult.Success, moveResult.GameState.Battery)
+	}
+	if len(moveResult.Events) != 1 || moveResult.Events[0].Type != "charge" {
+		t.Errorf("Expected a single charge event, got %+v", moveResult.Events)
+	}
+	last := moveResult.GameState.MoveHistory[len(moveResult.GameState.MoveHistory)-1]
+	if last.Action != engine.ActionCharge || last.FromPosition != last.ToPosition || !last.Success {
+		t.Errorf("Expected charge recorded in history as a zero-distance action, got %+v", last)
+	}
+
+	// Charging away from a charger stops a bulk move
+	result, err = svc.BulkMove(ctx, sessionInfo.ID, []string{"right", engine.ActionCharge, "left"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if result.MovesExecuted != 1 || result.StopReasonCode != "not_on_charger" {
+		t.Errorf("Expected stop on charge with not_on_charger, got executed=%d code=%q", result.MovesExecuted, result.StopReasonCode)
+	}
+	if !strings.Contains(result.GameState.Message, "not on a charger") {
+		t.Errorf("Expected not-on-charger message, got %q", result.GameState.Message)
+	}
+}
+
+func TestGameService_RequireParkAction(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	parking := *configs.configs["test"]
+	parking.Name = "parking"
+	parking.RequireParkAction = true
+	configs.SaveConfig("parking", &parking)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	sessionInfo, err := svc.CreateSession(ctx, "parking")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Drive from home (3,2) onto the park at (2,0), then park there
+	result, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"left", "up", "up", engine.ActionPark, engine.ActionPark}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if len(result.Steps) != 4 {
+		t.Fatalf("Expected 4 executed steps, got %d", len(result.Steps))
+	}
+	if result.Steps[2].Park {
+		t.Error("Entering the park should not collect it")
+	}
+	if !result.Steps[3].Park || result.Steps[3].From != result.Steps[3].To {
+		t.Errorf("Expected the park action to collect in place, got %+v", result.Steps[3])
+	}
+	if result.ScoreDelta != 1 || result.StopReasonCode != "not_on_park" {
+		t.Errorf("Expected one park and a stop on the repeated park action, got delta=%d code=%q", result.ScoreDelta, result.StopReasonCode)
+	}
+}
+
+func TestGameService_BatteryReserve(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	reserve := *configs.configs["test"]
+	reserve.Name = "reserve"
+	reserve.StartingBattery = 3
+	reserve.EnforceBatteryReserve = true
+	configs.SaveConfig("reserve", &reserve)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	sessionInfo, err := svc.CreateSession(ctx, "reserve")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Two cells left of home (3,2) with 1 battery would strand the player
+	result, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"left", "left", "left"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if result.MovesExecuted != 1 || result.StopReasonCode != "would_strand" || result.StoppedOnMove != 2 {
+		t.Errorf("Expected a would_strand stop on move 2, got executed=%d code=%q on=%d",
+			result.MovesExecuted, result.StopReasonCode, result.StoppedOnMove)
+	}
+	if result.GameState.GameOver || result.GameState.Battery != 2 {
+		t.Errorf("Expected the game to go on with battery 2, got %+v", result.GameState)
+	}
+
+	move, err := svc.Move(ctx, sessionInfo.ID, "left", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if move.Success || move.GameState.LastMoveOutcome != engine.MoveOutcomeWouldStrand {
+		t.Errorf("Expected a would_strand outcome, got success=%v outcome=%q", move.Success, move.GameState.LastMoveOutcome)
+	}
+}
+
+func TestGameService_SharedSessionContention(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	info, err := svc.CreateSharedSession(ctx, "test", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create shared session: %v", err)
+	}
+	if len(info.State.Players) != 2 {
+		t.Fatalf("Expected 2 players, got %d", len(info.State.Players))
+	}
+
+	// Both race from home (3,2) to the park at (2,0); alice moves first each turn
+	var last *service.SharedMoveResult
+	collected := map[string]string{}
+	for _, dir := range []string{"left", "up", "up"} {
+		for _, player := range []string{"alice", "bob"} {
+			last, err = svc.MoveInShared(ctx, info.ID, player, dir)
+			if err != nil {
+				t.Fatalf("MoveInShared(%s, %s) failed: %v", player, dir, err)
+			}
+			if last.Collected != "" {
+				collected[player] = last.Collected
+			}
+		}
+	}
+
+	if len(collected) != 1 || collected["alice"] != "park_0" {
+		t.Errorf("Expected only alice to collect park_0, got %v", collected)
+	}
+	if !last.Success || last.Player.ID != "bob" || last.Player.Position != (engine.Position{X: 2, Y: 0}) {
+		t.Errorf("Expected bob to reach the taken park, got %+v", last.Player)
+	}
+	if owner := last.State.ParkOwners["park_0"]; owner != "alice" {
+		t.Errorf("Expected alice to own park_0, got %q", owner)
+	}
+
+	got, err := svc.GetSharedSession(ctx, info.ID)
+	if err != nil {
+		t.Fatalf("GetSharedSession failed: %v", err)
+	}
+	if got.State.Players[0].Score != 1 || got.State.Players[1].Score != 0 {
+		t.Errorf("Expected scores 1 and 0, got %d and %d", got.State.Players[0].Score, got.State.Players[1].Score)
+	}
+
+	if _, err := svc.MoveInShared(ctx, info.ID, "carol", "up"); !errors.Is(err, engine.ErrUnknownPlayer) {
+		t.Errorf("Expected ErrUnknownPlayer, got %v", err)
+	}
+	if _, err := svc.MoveInShared(ctx, "missing", "alice", "up"); err == nil {
+		t.Error("Expected error for missing shared session")
+	}
+}
+
+func TestGameService_SharedSessionHideOpponentStats(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	info, err := svc.CreateSharedSessionWithOptions(ctx, "test", []string{"alice", "bob"},
+		service.SharedSessionOptions{HideOpponentStats: true})
+	if err != nil {
+		t.Fatalf("Failed to create shared session: %v", err)
+	}
+	if !info.HideOpponentStats {
+		t.Error("Expected the session to report hidden opponent stats")
+	}
+
+	// Alice drives from home (3,2) to the park at (2,0)
+	var result *service.SharedMoveResult
+	for _, dir := range []string{"left", "up", "up"} {
+		if result, err = svc.MoveInShared(ctx, info.ID, "alice", dir); err != nil {
+			t.Fatalf("MoveInShared(%s) failed: %v", dir, err)
+		}
+	}
+	alice := *result.Player
+
+	// Alice sees her own stats in full and bob's masked
+	own := result.ViewFor("alice")
+	if *own.Player != alice || own.Collected != "park_0" || own.State.ParkOwners["park_0"] != "alice" {
+		t.Errorf("Expected alice to see her own move in full, got %+v", own)
+	}
+	if bob := own.State.Players[1]; bob.Battery != 0 || bob.Score != 0 || !bob.StatsHidden {
+		t.Errorf("Expected alice to see bob masked, got %+v", bob)
+	}
+
+	// Bob sees his own stats but not alice's, nor what her move collected
+	opp := result.ViewFor("bob")
+	for _, p := range []*engine.SharedPlayer{opp.Player, opp.State.Players[0]} {
+		if p.Battery != 0 || p.Score != 0 || p.Message != "" || !p.StatsHidden {
+			t.Errorf("Expected bob to see alice masked, got %+v", p)
+		}
+	}
+	if opp.Message != "" || opp.Collected != "" || len(opp.State.ParkOwners) != 0 {
+		t.Errorf("Expected alice's message and parks hidden from bob, got %q, %q and %v", opp.Message, opp.Collected, opp.State.ParkOwners)
+	}
+	if bob := opp.State.Players[1]; bob.Battery != alice.Battery+3 || bob.StatsHidden {
+		t.Errorf("Expected bob to see his own full battery, got %+v", bob)
+	}
+
+	// Masking copies: the session's state is untouched
+	if got, _ := svc.GetSharedSession(ctx, info.ID); got.State.Players[0].Score != 1 || got.State.ParkOwners["park_0"] != "alice" {
+		t.Errorf("Expected the session state unmasked, got %+v", got.State.Players[0])
+	}
+
+	// Without the option every player sees everything
+	plain, err := svc.CreateSharedSession(ctx, "test", []string{"alice", "bob"})
+	if err != nil {
+		t.Fatalf("Failed to create shared session: %v", err)
+	}
+	result, err = svc.MoveInShared(ctx, plain.ID, "alice", "left")
+	if err != nil {
+		t.Fatalf("MoveInShared failed: %v", err)
+	}
+	if view := result.ViewFor("bob"); view.Player.StatsHidden || view.Player.Battery != result.Player.Battery {
+		t.Errorf("Expected stats visible without the option, got %+v", view.Player)
+	}
+}
+
+func TestGameService_AnalyzeConfig(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	analysis, err := svc.AnalyzeConfig(ctx, "test")
+	if err != nil {
+		t.Fatalf("AnalyzeConfig failed: %v", err)
+	}
+	if analysis.ParkCount != 2 || analysis.ChargerCount != 1 {
+		t.Errorf("Expected 2 parks and 1 charger, got %d and %d", analysis.ParkCount, analysis.ChargerCount)
+	}
+
+	if _, err := svc.AnalyzeConfig(ctx, "missing"); err == nil {
+		t.Error("Expected error for missing config")
+	}
+}
+
+func TestGameService_SolveGame(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	result, err := svc.SolveGame(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("SolveGame failed: %v", err)
+	}
+	if !result.Solved || result.MoveCount != len(result.Moves) || result.MoveCount == 0 {
+		t.Fatalf("Expected a plan, got %+v", result)
+	}
+
+	// Solving must not play any moves
+	state, _ := svc.GetGameState(ctx, sessionInfo.ID)
+	if state.CurrentMovesCount != 0 {
+		t.Fatalf("SolveGame modified the session: %d moves", state.CurrentMovesCount)
+	}
+
+	bulk, err := svc.BulkMove(ctx, sessionInfo.ID, result.Moves, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if !bulk.GameState.Victory {
+		t.Errorf("Expected plan to win, stopped: %s", bulk.StoppedReason)
+	}
+
+	// Drain the battery away from a charger to lose the game
+	sessionInfo, _ = svc.CreateSession(ctx, "test")
+	svc.BulkMove(ctx, sessionInfo.ID, []string{"right", "up", "down", "up", "down", "up", "down", "up", "down", "up", "down"}, false)
+	result, err = svc.SolveGame(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("SolveGame failed: %v", err)
+	}
+	if result.Solved || result.ReasonCode != "game_over" {
+		t.Errorf("Expected game_over result, got %+v", result)
+	}
+
+	if _, err := svc.SolveGame(ctx, "missing"); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+// unreachableSessionManager is a session manager whose storage has gone away
+type unreachableSessionManager struct {
+	*MockSessionManager
+}
+
+func (unreachableSessionManager) CheckStorage() error {
+	return errors.New("sessions directory unavailable")
+}
+
+func TestGameService_Ready(t *testing.T) {
+	ctx := context.Background()
+
+	if err := service.NewGameService(NewMockSessionManager(), NewMockConfigManager()).Ready(ctx); err != nil {
+		t.Errorf("Expected service to be ready, got %v", err)
+	}
+
+	svc := service.NewGameService(unreachableSessionManager{NewMockSessionManager()}, NewMockConfigManager())
+	err := svc.Ready(ctx)
+	if err == nil || !strings.Contains(err.Error(), "session storage unreachable") {
+		t.Errorf("Expected unreachable storage error, got %v", err)
+	}
+}
+
+func TestGameService_SolvePlan(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	steps, err := svc.SolvePlan(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("SolvePlan failed: %v", err)
+	}
+	if len(steps) == 0 {
+		t.Fatal("Expected a plan")
+	}
+
+	// Playing the plan step by step matches its annotations and the final
+	// step wins
+	parks := map[engine.Position]bool{{X: 2, Y: 0}: true, {X: 2, Y: 4}: true}
+	for i, step := range steps {
+		if step.Purpose == strategy.PurposePark && !parks[step.Target] {
+			t.Errorf("Step %d: expected a park target, got %+v", i, step.Target)
+		}
+		if step.Purpose == strategy.PurposeCharge && step.Target != (engine.Position{X: 3, Y: 2}) {
+			t.Errorf("Step %d: expected the home as charge target, got %+v", i, step.Target)
+		}
+		result, err := svc.Move(ctx, sessionInfo.ID, step.Direction, false)
+		if err != nil || !result.Success {
+			t.Fatalf("Step %d (%s) failed: %v", i, step.Direction, err)
+		}
+		if result.GameState.Battery != step.BatteryAfter {
+			t.Errorf("Step %d: expected battery %d, got %d", i, step.BatteryAfter, result.GameState.Battery)
+		}
+		if won := result.GameState.Victory; won != (i == len(steps)-1) {
+			t.Fatalf("Step %d of %d: victory=%v", i+1, len(steps), won)
+		}
+	}
+
+	// An already won game needs no plan
+	steps, err = svc.SolvePlan(ctx, sessionInfo.ID)
+	if err != nil || len(steps) != 0 {
+		t.Errorf("Expected an empty plan for a won game, got %+v, %v", steps, err)
+	}
+
+	sessionInfo, _ = svc.CreateSession(ctx, "test")
+	svc.BulkMove(ctx, sessionInfo.ID, []string{"right", "up", "down", "up", "down", "up", "down", "up", "down", "up", "down"}, false)
+	if _, err := svc.SolvePlan(ctx, sessionInfo.ID); !errors.Is(err, engine.ErrGameOver) {
+		t.Errorf("Expected ErrGameOver for a lost game, got %v", err)
+	}
+	if _, err := svc.SolvePlan(ctx, "missing"); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestGameService_Teleport(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	result, err := svc.Teleport(ctx, sess.ID, 2, 0)
+	if err != nil {
+		t.Fatalf("Teleport failed: %v", err)
+	}
+	if result.GameState.PlayerPos != (engine.Position{X: 2, Y: 0}) || result.GameState.Score != 1 {
+		t.Errorf("Expected park at (2,0) collected, got pos=%+v score=%d", result.GameState.PlayerPos, result.GameState.Score)
+	}
+	if result.Step == nil || result.Step.Dir != engine.ActionTeleport || !result.Step.Park {
+		t.Errorf("Expected a teleport step that collected a park, got %+v", result.Step)
+	}
+	if len(result.Events) == 0 || result.Events[0].Type != "teleport" {
+		t.Errorf("Expected a teleport event, got %+v", result.Events)
+	}
+
+	if _, err := svc.Teleport(ctx, sess.ID, 1, 1); !errors.Is(err, engine.ErrImpassable) {
+		t.Errorf("Expected ErrImpassable for water, got %v", err)
+	}
+	if _, err := svc.Teleport(ctx, "missing", 0, 0); err == nil {
+		t.Error("Expected error for missing session")
+	}
+
+	history, err := svc.GetMoveHistory(ctx, sess.ID, service.HistoryOptions{})
+	if err != nil {
+		t.Fatalf("GetMoveHistory failed: %v", err)
+	}
+	if history.TotalMoves != 1 || history.Moves[0].Action != engine.ActionTeleport {
+		t.Errorf("Expected one teleport history entry, got %+v", history.Moves)
+	}
+}
+
+func TestGameService_MoveToward(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Water walls off the straight road up from home at (3,2)
+	if _, err := svc.MoveToward(ctx, sess.ID, 1, 1); !errors.Is(err, service.ErrTargetUnreachable) {
+		t.Errorf("Expected ErrTargetUnreachable for water, got %v", err)
+	}
+	if _, err := svc.MoveToward(ctx, sess.ID, 7, 0); !errors.Is(err, service.ErrTargetUnreachable) {
+		t.Errorf("Expected ErrTargetUnreachable off the grid, got %v", err)
+	}
+	state, _ := svc.GetGameState(ctx, sess.ID)
+	if state.PlayerPos != (engine.Position{X: 3, Y: 2}) || len(state.MoveHistory) != 0 {
+		t.Fatalf("Expected an unreachable target not to move the player, got %+v", state.PlayerPos)
+	}
+
+	for i, want := range []struct {
+		dir       string
+		remaining int
+	}{{"left", 2}, {"up", 1}, {"up", 0}} {
+		result, err := svc.MoveToward(ctx, sess.ID, 2, 0)
+		if err != nil {
+			t.Fatalf("MoveToward %d failed: %v", i, err)
+		}
+		if !result.Success || result.Direction != want.dir || result.RemainingPath != want.remaining {
+			t.Errorf("Step %d: expected %s with %d to go, got %s with %d", i, want.dir, want.remaining,
+				result.Direction, result.RemainingPath)
+		}
+	}
+	state, _ = svc.GetGameState(ctx, sess.ID)
+	if state.PlayerPos != (engine.Position{X: 2, Y: 0}) || state.Score != 1 {
+		t.Errorf("Expected the park at (2,0) collected, got pos=%+v score=%d", state.PlayerPos, state.Score)
+	}
+	if intent := state.MoveHistory[0].Intent; intent != "move toward (2,0)" {
+		t.Errorf("Expected the target recorded as the intent, got %q", intent)
+	}
+
+	if _, err := svc.MoveToward(ctx, sess.ID, 2, 0); !errors.Is(err, service.ErrAlreadyAtTarget) {
+		t.Errorf("Expected ErrAlreadyAtTarget, got %v", err)
+	}
+	if _, err := svc.MoveToward(ctx, "missing", 0, 0); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestGameService_BatteryPercent(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	result, err := svc.Move(ctx, sess.ID, "left", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	state := result.GameState
+	if want := engine.BatteryPercent(state.Battery, state.MaxBattery); state.BatteryPercent != want || want == 0 {
+		t.Errorf("Expected battery_percent %d for %d/%d, got %d", want, state.Battery, state.MaxBattery, state.BatteryPercent)
+	}
+
+	bulk, err := svc.BulkMove(ctx, sess.ID, []string{"left"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if bulk.BatteryPercent != engine.BatteryPercent(bulk.GameState.Battery, bulk.GameState.MaxBattery) {
+		t.Errorf("Expected bulk battery_percent to match the final state, got %d", bulk.BatteryPercent)
+	}
+}
+
+func TestGameService_GetParks(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	many := *configs.configs["test"]
+	many.Name = "manyparks"
+	many.Layout = []string{
+		"PPPPP",
+		"PWRWP",
+		"RRRHR",
+		"PWRWP",
+		"PPRPP",
+	}
+	configs.SaveConfig("manyparks", &many)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	sess, err := svc.CreateSession(ctx, "manyparks")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, err := svc.BulkMove(ctx, sess.ID, []string{"right", "up"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+
+	parks, err := svc.GetParks(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("GetParks failed: %v", err)
+	}
+	if parks.Total != 13 || parks.Required != 13 || parks.Collected != 1 {
+		t.Errorf("Expected 1/13 parks collected, got %d/%d (required %d)", parks.Collected, parks.Total, parks.Required)
+	}
+	for i, park := range parks.Parks {
+		if want := fmt.Sprintf("park_%d", i); park.ID != want {
+			t.Fatalf("Expected parks ordered by ID, got %s at index %d", park.ID, i)
+		}
+	}
+	if p := parks.Parks[6]; !p.Collected || p.Position != (engine.Position{X: 4, Y: 1}) {
+		t.Errorf("Expected park_6 at (4,1) to be collected, got %+v", p)
+	}
+	if p := parks.Parks[10]; p.Collected || p.Position != (engine.Position{X: 1, Y: 4}) {
+		t.Errorf("Expected park_10 at (1,4) uncollected, got %+v", p)
+	}
+	for _, p := range parks.Parks {
+		if p.Points != 1 {
+			t.Fatalf("Expected %s to be worth a point, got %d", p.ID, p.Points)
+		}
+	}
+
+	// Eco scoring makes each park worth more
+	eco := many
+	eco.Name = "ecoparks"
+	eco.ScoringMode = engine.ScoringEco
+	configs.SaveConfig("ecoparks", &eco)
+	ecoSess, err := svc.CreateSession(ctx, "ecoparks")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	parks, err = svc.GetParks(ctx, ecoSess.ID)
+	if err != nil {
+		t.Fatalf("GetParks failed: %v", err)
+	}
+	if p := parks.Parks[0]; p.Points != engine.EcoParkPoints {
+		t.Errorf("Expected park_0 worth %d eco points, got %d", engine.EcoParkPoints, p.Points)
+	}
+
+	if _, err := svc.GetParks(ctx, "missing"); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestGameService_GetSessionConfig(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	cfg, err := svc.GetSessionConfig(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("GetSessionConfig failed: %v", err)
+	}
+	if cfg.Name != sess.GameConfig.Name || cfg.MaxBattery != 10 || strings.Join(cfg.Layout, "/") != "RRPRR/RWRWR/RRRHR/RWRWR/RRPRR" {
+		t.Errorf("Expected the session's config, got %+v", cfg)
+	}
+
+	if _, err := svc.GetSessionConfig(ctx, "missing"); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestGameService_CreateSessionWithTTL(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sess, err := svc.CreateSessionWithOptions(ctx, "test", service.CreateSessionOptions{TTLSeconds: 120})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if sess.TTLSeconds != 120 {
+		t.Errorf("Expected TTLSeconds 120, got %d", sess.TTLSeconds)
+	}
+	info, err := svc.GetSession(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if info.TTLSeconds != 120 {
+		t.Errorf("Expected the TTL to be kept on the session, got %d", info.TTLSeconds)
+	}
+
+	if _, err := svc.CreateSessionWithOptions(ctx, "test", service.CreateSessionOptions{TTLSeconds: -1}); err == nil {
+		t.Error("Expected error for negative TTL")
+	}
+}
+
+func TestGameService_SetSessionLabel(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if sess.Label != "" {
+		t.Errorf("Expected new sessions to have no label, got %q", sess.Label)
+	}
+
+	info, err := svc.SetSessionLabel(ctx, sess.ID, "  Sunday drive  ")
+	if err != nil {
+		t.Fatalf("SetSessionLabel failed: %v", err)
+	}
+	if info.ID != sess.ID || info.Label != "Sunday drive" {
+		t.Errorf("Expected the trimmed label on %s, got %q on %s", sess.ID, info.Label, info.ID)
+	}
+	sessions, err := svc.ListSessions(ctx)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Label != "Sunday drive" {
+		t.Errorf("Expected the label in the session list, got %+v", sessions)
+	}
+	clone, err := svc.CloneSession(ctx, sess.ID, false)
+	if err != nil {
+		t.Fatalf("CloneSession failed: %v", err)
+	}
+	if clone.Label != "Sunday drive" {
+		t.Errorf("Expected the clone to keep the label, got %q", clone.Label)
+	}
+
+	if _, err := svc.SetSessionLabel(ctx, sess.ID, strings.Repeat("x", service.MaxSessionLabelLength+1)); !errors.Is(err, service.ErrLabelTooLong) {
+		t.Errorf("Expected ErrLabelTooLong, got %v", err)
+	}
+	if info, err := svc.SetSessionLabel(ctx, sess.ID, ""); err != nil || info.Label != "" {
+		t.Errorf("Expected an empty label to clear it, got %+v, %v", info, err)
+	}
+	if _, err := svc.SetSessionLabel(ctx, "missing", "x"); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestGameService_VerifySession(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewMockSessionManager()
+	svc := service.NewGameService(sessions, NewMockConfigManager())
+
+	// From home at (3,2) to the park at (2,0)
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, err := svc.BulkMove(ctx, sess.ID, []string{"left", "up", "up"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	result, err := svc.VerifySession(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("VerifySession failed: %v", err)
+	}
+	if !result.Verified || result.MovesReplayed != 3 || result.Mismatch != nil {
+		t.Fatalf("Expected the history to replay to the live state, got %+v", result)
+	}
+
+	// A surrender has no move to replay but still verifies
+	if _, err := svc.Surrender(ctx, sess.ID); err != nil {
+		t.Fatalf("Surrender failed: %v", err)
+	}
+	if result, err := svc.VerifySession(ctx, sess.ID); err != nil || !result.Verified {
+		t.Errorf("Expected a surrendered game to verify, got %+v, %v", result, err)
+	}
+
+	// Corrupt the second move so it no longer leads where it was recorded to
+	corrupted, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, err := svc.BulkMove(ctx, corrupted.ID, []string{"left", "up", "up"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	live := sessions.sessions[corrupted.ID].Engine
+	state := live.GetState()
+	state.CurrentMoves[1].Action = "down"
+	state.MoveHistory[1].Action = "down"
+	if err := live.SetState(state); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+
+	result, err = svc.VerifySession(ctx, corrupted.ID)
+	if err != nil {
+		t.Fatalf("VerifySession failed: %v", err)
+	}
+	want := &service.ReplayMismatch{Field: "current_moves[1].to_position.y", Live: 1, Replayed: 3}
+	if result.Verified || !reflect.DeepEqual(result.Mismatch, want) {
+		t.Errorf("Expected mismatch %+v, got %+v", want, result.Mismatch)
+	}
+	if got := sessions.sessions[corrupted.ID].Engine.GetPlayerPosition(); got != (engine.Position{X: 2, Y: 0}) {
+		t.Errorf("Expected verifying to leave the live game alone, got position %v", got)
+	}
+
+	if _, err := svc.VerifySession(ctx, "missing"); err == nil {
+		t.Error("Expected error for missing session")
+	}
+}
+
+func TestGameService_VerifySessionIgnore(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewMockSessionManager()
+	strict := service.NewGameService(sessions, NewMockConfigManager())
+	lenient := service.NewGameService(sessions, NewMockConfigManager(), service.WithVerifyIgnore("message"))
+
+	sess, err := strict.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, err := strict.Move(ctx, sess.ID, "left", false); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	live := sessions.sessions[sess.ID].Engine
+	state := live.GetState()
+	state.Message = "Reworded by a newer engine"
+	if err := live.SetState(state); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+
+	result, err := strict.VerifySession(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("VerifySession failed: %v", err)
+	}
+	if result.Verified || result.Mismatch == nil || result.Mismatch.Field != "message" {
+		t.Errorf("Expected a message mismatch, got %+v", result.Mismatch)
+	}
+	if result, err := lenient.VerifySession(ctx, sess.ID); err != nil || !result.Verified {
+		t.Errorf("Expected the ignored message to verify, got %+v, %v", result, err)
+	}
+}
+
+func TestGameService_GetLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	other := *configs.configs["test"]
+	other.Name = "other"
+	configs.SaveConfig("other", &other)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	// Parks are at (2,0) and (2,4); the player starts at (3,2)
+	play := func(configName string, moves ...string) string {
+		sess, err := svc.CreateSession(ctx, configName)
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		if len(moves) > 0 {
+			if _, err := svc.BulkMove(ctx, sess.ID, moves, false); err != nil {
+				t.Fatalf("Bulk move failed: %v", err)
+			}
+		}
+		return sess.ID
+	}
+	slowWin := play("test", "left", "right", "left", "up", "up", "down", "down", "down", "down")
+	fastWin := play("test", "left", "up", "up", "down", "down", "down", "down")
+	slowPark := play("test", "left", "right", "left", "up", "up")
+	fastPark := play("test", "left", "up", "up")
+	noPark := play("test", "left")
+	play("other", "left", "up", "up")
+
+	ids := func(board *service.Leaderboard) []string {
+		var result []string
+		for i, entry := range board.Entries {
+			if entry.Rank != i+1 {
+				t.Errorf("Expected rank %d, got %d", i+1, entry.Rank)
+			}
+			result = append(result, entry.SessionID)
+		}
+		return result
+	}
+
+	board, err := svc.GetLeaderboard(ctx, service.LeaderboardOptions{ConfigName: "test"})
+	if err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+	want := []string{fastWin, slowWin, fastPark, slowPark, noPark}
+	if got := ids(board); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected score ranking %v, got %v", want, got)
+	}
+	if board.SortBy != service.LeaderboardByScore || board.Total != 5 {
+		t.Errorf("Expected score sort over 5 sessions, got %s over %d", board.SortBy, board.Total)
+	}
+
+	board, err = svc.GetLeaderboard(ctx, service.LeaderboardOptions{ConfigName: "test", SortBy: service.LeaderboardByMoves})
+	if err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+	if got := ids(board); !reflect.DeepEqual(got, []string{fastWin, slowWin}) {
+		t.Errorf("Expected only victories ranked by moves, got %v", got)
+	}
+
+	board, err = svc.GetLeaderboard(ctx, service.LeaderboardOptions{ConfigName: "test", Limit: 3})
+	if err != nil {
+		t.Fatalf("GetLeaderboard failed: %v", err)
+	}
+	if got := ids(board); len(got) != 3 || board.Total != 5 {
+		t.Errorf("Expected 3 of 5 entries, got %v of %d", got, board.Total)
+	}
+
+	if _, err := svc.GetLeaderboard(ctx, service.LeaderboardOptions{SortBy: "battery"}); !errors.Is(err, service.ErrInvalidLeaderboardSort) {
+		t.Errorf("Expected ErrInvalidLeaderboardSort, got %v", err)
+	}
+}
+
+func TestGameService_WaitEvent(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	wait := *configs.configs["test"]
+	wait.Name = "wait"
+	wait.WaitCost = 2
+	wait.StartingBattery = 5
+	configs.SaveConfig("wait", &wait)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	sess, err := svc.CreateSession(ctx, "wait")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, err := svc.Move(ctx, sess.ID, "left", false); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+
+	result, err := svc.Move(ctx, sess.ID, engine.ActionWait, false)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if !result.Success || len(result.Events) != 1 || result.Events[0].Type != "wait" {
+		t.Fatalf("Expected a single wait event, got %+v", result.Events)
+	}
+	if result.GameState.LastMoveOutcome != engine.MoveOutcomeWaited || result.GameState.Battery != 2 {
+		t.Errorf("Expected a waited outcome with battery 2, got %q and %d", result.GameState.LastMoveOutcome, result.GameState.Battery)
+	}
+
+	// Waiting out the last battery away from a charger ends the game
+	result, err = svc.Move(ctx, sess.ID, engine.ActionWait, false)
+	if err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if !result.GameState.GameOver || len(result.Events) != 2 || result.Events[1].Type != "game_over" {
+		t.Errorf("Expected the wait to end the game, got %+v", result.Events)
+	}
+}
+
+func TestGameService_LoopDetection(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager(), service.WithLoopDetection(4, 2))
+
+	hasWarning := func(events []service.GameEvent) bool {
+		for _, event := range events {
+			if event.Type == "loop_warning" {
+				return true
+			}
+		}
+		return false
+	}
+
+	// A-B-A-B between home and the road beside it
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	for i, dir := range []string{"left", "right", "left", "right"} {
+		result, err := svc.Move(ctx, sess.ID, dir, false)
+		if err != nil {
+			t.Fatalf("Move %d failed: %v", i+1, err)
+		}
+		want := i == 3
+		if result.LoopDetected != want || hasWarning(result.Events) != want {
+			t.Errorf("Move %d: expected loop_detected %v, got %v with events %+v", i+1, want, result.LoopDetected, result.Events)
+		}
+	}
+
+	// The same pattern in one bulk call is flagged too
+	sess, err = svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	bulk, err := svc.BulkMove(ctx, sess.ID, []string{"left", "right", "left", "right"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if !bulk.LoopDetected || !hasWarning(bulk.Events) {
+		t.Errorf("Expected the bulk move to be flagged, got %+v", bulk.Events)
+	}
+
+	// Covering more cells than allowed is not a tight cycle
+	sess, err = svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	bulk, err = svc.BulkMove(ctx, sess.ID, []string{"left", "left", "right", "right"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if bulk.LoopDetected {
+		t.Error("Expected no loop across three cells")
+	}
+
+	// Collecting a park within the window is progress; repeating the trip is not
+	wide := service.NewGameService(NewMockSessionManager(), NewMockConfigManager(), service.WithLoopDetection(4, 3))
+	sess, err = wide.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	bulk, err = wide.BulkMove(ctx, sess.ID, []string{"left", "up", "up", "down", "down"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if bulk.ScoreDelta != 1 || bulk.LoopDetected {
+		t.Errorf("Expected a collected park and no loop, got score delta %d and loop %v", bulk.ScoreDelta, bulk.LoopDetected)
+	}
+	bulk, err = wide.BulkMove(ctx, sess.ID, []string{"up", "up", "down", "down"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if !bulk.LoopDetected {
+		t.Error("Expected revisiting the collected park to be flagged")
+	}
+
+	// A zero window disables the watchdog
+	off := service.NewGameService(NewMockSessionManager(), NewMockConfigManager(), service.WithLoopDetection(0, 2))
+	sess, err = off.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	bulk, err = off.BulkMove(ctx, sess.ID, []string{"left", "right", "left", "right", "left", "right"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if bulk.LoopDetected {
+		t.Error("Expected no loop detection with a zero window")
+	}
+}
+
+func TestGameService_IdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	opts := service.MoveOptions{IdempotencyKey: "retry-1"}
+	first, err := svc.MoveWithOptions(ctx, sess.ID, "left", opts)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	retry, err := svc.MoveWithOptions(ctx, sess.ID, "left", opts)
+	if err != nil {
+		t.Fatalf("Retried move failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, retry) {
+		t.Errorf("Expected the retry to return the first result\nfirst %+v\nretry %+v", first, retry)
+	}
+	state, err := svc.GetGameState(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("GetGameState failed: %v", err)
+	}
+	if state.Battery != 9 || state.PlayerPos != (engine.Position{X: 2, Y: 2}) || len(state.CurrentMoves) != 1 {
+		t.Errorf("Expected a single move to (2,2) with battery 9, got %v with battery %d after %d moves",
+			state.PlayerPos, state.Battery, len(state.CurrentMoves))
+	}
+
+	// The key belongs to that request
+	if _, err := svc.MoveWithOptions(ctx, sess.ID, "up", opts); !errors.Is(err, service.ErrIdempotencyKeyReused) {
+		t.Errorf("Expected ErrIdempotencyKeyReused for a different move, got %v", err)
+	}
+
+	// Bulk moves are remembered the same way, and keep their result even as
+	// the game moves on
+	bulkOpts := service.BulkMoveOptions{IdempotencyKey: "retry-2"}
+	bulk, err := svc.BulkMoveWithOptions(ctx, sess.ID, []string{"left", "left"}, bulkOpts)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if _, err := svc.Move(ctx, sess.ID, "down", false); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	retried, err := svc.BulkMoveWithOptions(ctx, sess.ID, []string{"left", "left"}, bulkOpts)
+	if err != nil {
+		t.Fatalf("Retried bulk move failed: %v", err)
+	}
+	if retried.EndBattery != bulk.EndBattery || retried.GameState.Battery != 7 || retried.GameState.PlayerPos != bulk.EndPos {
+		t.Errorf("Expected the first bulk result with battery 7 at %v, got %+v", bulk.EndPos, retried)
+	}
+	if state, _ := svc.GetGameState(ctx, sess.ID); state.Battery != 6 {
+		t.Errorf("Expected the retried bulk move not to spend battery again, got %d", state.Battery)
+	}
+
+	// Without a key every request moves
+	if result, err := svc.Move(ctx, sess.ID, "up", false); err != nil || result.GameState.Battery != 5 {
+		t.Errorf("Expected an unkeyed move to spend battery, got %v", err)
+	}
+}
+
+func TestGameService_ResetAndReplay(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+	sess, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, err := svc.BulkMove(ctx, sess.ID, []string{"left", "left", "left", "up", "up"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+
+	state, err := svc.ResetAndReplay(ctx, sess.ID, 3)
+	if err != nil {
+		t.Fatalf("ResetAndReplay failed: %v", err)
+	}
+	if state.PlayerPos != (engine.Position{X: 0, Y: 2}) || len(state.CurrentMoves) != 3 || state.Battery != 7 {
+		t.Errorf("Expected three moves to (0,2) with battery 7, got %v after %d moves with battery %d",
+			state.PlayerPos, len(state.CurrentMoves), state.Battery)
+	}
+	if state.LocalView3x3 == nil || state.MovePreviews == nil {
+		t.Error("Expected the replayed state to carry decision aids")
+	}
+
+	if _, err := svc.ResetAndReplay(ctx, "missing", 1); err == nil {
+		t.Error("Expected an error for an unknown session")
+	}
+}
+
+func TestGameService_HazardHitEvent(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	hazard := *configs.configs["test"]
+	hazard.Name = "hazard"
+	hazard.Hazards = []engine.Hazard{{Path: []engine.Position{{X: 2, Y: 2}}}}
+	hazard.HazardPolicy = engine.HazardPolicyPenalty
+	hazard.HazardPenalty = 1
+	configs.SaveConfig("hazard", &hazard)
+	deadly := hazard
+	deadly.Name = "deadly"
+	deadly.HazardPolicy = ""
+	configs.SaveConfig("deadly", &deadly)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	types := func(events []service.GameEvent) []string {
+		var got []string
+		for _, ev := range events {
+			got = append(got, ev.Type)
+		}
+		return got
+	}
+
+	sess, err := svc.CreateSession(ctx, "hazard")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	result, err := svc.Move(ctx, sess.ID, "left", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if got := types(result.Events); !reflect.DeepEqual(got, []string{"move", "hazard_hit"}) {
+		t.Errorf("Expected move and hazard_hit events, got %v", got)
+	}
+	if result.GameState.LastMoveOutcome != engine.MoveOutcomeHazardHit || result.GameState.Battery != 8 {
+		t.Errorf("Expected a hazard_hit outcome with battery 8, got %q and %d",
+			result.GameState.LastMoveOutcome, result.GameState.Battery)
+	}
+
+	sess, err = svc.CreateSession(ctx, "deadly")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	result, err = svc.Move(ctx, sess.ID, "left", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if got := types(result.Events); !reflect.DeepEqual(got, []string{"move", "hazard_hit", "game_over"}) {
+		t.Errorf("Expected the hazard hit to end the game, got %v", got)
+	}
+	if result.GameState.GameOverReason != engine.GameOverHazard {
+		t.Errorf("Expected game_over_reason hazard, got %q", result.GameState.GameOverReason)
+	}
+}
+
+func TestGameService_PreviewConfig(t *testing.T) {
+	ctx := context.Background()
+	sessions := NewMockSessionManager()
+	svc := service.NewGameService(sessions, NewMockConfigManager())
+
+	state, err := svc.PreviewConfig(ctx, "test")
+	if err != nil {
+		t.Fatalf("PreviewConfig failed: %v", err)
+	}
+	if state.PlayerPos != (engine.Position{X: 3, Y: 2}) || state.Battery != 10 {
+		t.Errorf("Expected to start at home (3,2) with battery 10, got %v and %d", state.PlayerPos, state.Battery)
+	}
+	if grid := service.RenderGrid(state); grid != "RRPRR\nRWRWR\nRRRTR\nRWRWR\nRRPRR\n" {
+		t.Errorf("Unexpected rendering:\n%s", grid)
+	}
+	if list, _ := svc.ListSessions(ctx); len(list) != 0 {
+		t.Errorf("Expected no session to be created, got %d", len(list))
+	}
+
+	if _, err := svc.PreviewConfig(ctx, "missing"); err == nil {
+		t.Error("Expected an error for an unknown config")
+	}
+}
+
+func TestGameService_FuelPickupEvent(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	fuel := *configs.configs["test"]
+	fuel.Name = "fuel"
+	fuel.Layout = []string{"RRPRR", "RWRWR", "RFRHR", "RWRWR", "RRPRR"}
+	fuel.FuelAmount = 3
+	fuel.StartingBattery = 5
+	configs.SaveConfig("fuel", &fuel)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	sess, err := svc.CreateSession(ctx, "fuel")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	pickups := func(events []service.GameEvent) int {
+		n := 0
+		for _, ev := range events {
+			if ev.Type == "fuel_pickup" {
+				n++
+			}
+		}
+		return n
+	}
+
+	result, err := svc.BulkMove(ctx, sess.ID, []string{"left", "left"}, false)
+	if err != nil {
+		t.Fatalf("Bulk move failed: %v", err)
+	}
+	if pickups(result.Events) != 1 || result.EndBattery != 6 {
+		t.Errorf("Expected one fuel pickup and battery 6, got battery %d and %+v", result.EndBattery, result.Events)
+	}
+
+	// The tile is road now, so coming back yields nothing
+	result, err = svc.BulkMove(ctx, sess.ID, []string{"right", "left"}, false)
+	if err != nil {
+		t.Fatalf("Bulk move failed: %v", err)
+	}
+	if pickups(result.Events) != 0 || result.EndBattery != 4 {
+		t.Errorf("Expected no fuel on the second visit, got battery %d and %+v", result.EndBattery, result.Events)
+	}
+}
+
+func TestGameService_ParkExpiredEvent(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	expiring := *configs.configs["test"]
+	expiring.Name = "expiring"
+	expiring.ParkExpiry = map[string]int{"park_1": 1}
+	configs.SaveConfig("expiring", &expiring)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	sess, err := svc.CreateSession(ctx, "expiring")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// park_1 at (2,4) isn't reached on the first move, so it expires
+	result, err := svc.Move(ctx, sess.ID, "left", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	var expired []service.GameEvent
+	for _, ev := range result.Events {
+		if ev.Type == "park_expired" {
+			expired = append(expired, ev)
+		}
+	}
+	if len(expired) != 1 || expired[0].Position != (engine.Position{X: 2, Y: 4}) {
+		t.Fatalf("Expected one park_expired event at (2,4), got %+v", result.Events)
+	}
+	if result.GameState.Grid[4][2].Type != engine.Road {
+		t.Errorf("Expected the expired park to become road, got %s", result.GameState.Grid[4][2].Type)
+	}
+
+	// The park list keeps the expired park but no longer requires it
+	parks, err := svc.GetParks(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("GetParks failed: %v", err)
+	}
+	if parks.Total != 2 || parks.Required != 1 {
+		t.Errorf("Expected 1 of 2 parks required, got %d of %d", parks.Required, parks.Total)
+	}
+	if p := parks.Parks[1]; p.ID != "park_1" || !p.Expired || p.Position != (engine.Position{X: 2, Y: 4}) {
+		t.Errorf("Expected park_1 listed as expired at (2,4), got %+v", p)
+	}
+}
+
+func TestGameService_RandomEventEmitted(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	drain := *configs.configs["test"]
+	drain.Name = "drain"
+	drain.RandomEvents = &engine.RandomEventsConfig{DrainChance: 1, DrainAmount: 2}
+	configs.SaveConfig("drain", &drain)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	sess, err := svc.CreateSession(ctx, "drain")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	before, _ := svc.GetGameState(ctx, sess.ID)
+	battery := before.Battery
+
+	result, err := svc.Move(ctx, sess.ID, "left", false)
+	if err != nil || !result.Success {
+		t.Fatalf("Move failed: %v %s", err, result.Message)
+	}
+	found := false
+	for _, ev := range result.Events {
+		if ev.Type == "event" && ev.Message == "Battery drain: lost 2 battery" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a random event, got %+v", result.Events)
+	}
+	if result.GameState.Battery != battery-3 {
+		t.Errorf("Expected battery %d after move and drain, got %d", battery-3, result.GameState.Battery)
+	}
+}
+
+func TestGameService_EventLog(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Collect the park at (2,0), recharge at home (3,2), then win at (2,4)
+	if _, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"left", "up", "up", "down", "down", "right"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	for _, move := range []string{"left", "down", "down"} {
+		if _, err := svc.Move(ctx, sessionInfo.ID, move, false); err != nil {
+			t.Fatalf("Move failed: %v", err)
+		}
+	}
+
+	eventLog, err := svc.GetEventLog(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("GetEventLog failed: %v", err)
+	}
+	if eventLog.SessionID != sessionInfo.ID || eventLog.Count != len(eventLog.Events) {
+		t.Errorf("Expected the session ID and a matching count, got %+v", eventLog)
+	}
+	var types []string
+	for i, ev := range eventLog.Events {
+		if ev.Type != "move" {
+			types = append(types, ev.Type)
+		}
+		if ev.Timestamp.IsZero() || i > 0 && ev.Timestamp.Before(eventLog.Events[i-1].Timestamp) {
+			t.Errorf("Expected event %d to be timestamped in order, got %v", i, ev.Timestamp)
+		}
+	}
+	if got := strings.Join(types, ","); got != "session_created,park_visited,charge,park_visited,victory" {
+		t.Errorf("Expected the events in order, got %s", got)
+	}
+
+	// A later read returns a copy the caller can't change
+	eventLog.Events[0].Type = "changed"
+	again, _ := svc.GetEventLog(ctx, sessionInfo.ID)
+	if again.Events[0].Type != "session_created" {
+		t.Error("Expected the event log to be returned as a copy")
+	}
+
+	if _, err := svc.GetEventLog(ctx, "missing"); err == nil {
+		t.Error("Expected an error for a missing session")
+	}
+}
+
+func TestGameService_EventLogSize(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager(), service.WithEventLog(3))
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"left", "up", "up", "down"}, false); err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	eventLog, err := svc.GetEventLog(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("GetEventLog failed: %v", err)
+	}
+	if eventLog.Count != 3 {
+		t.Fatalf("Expected the log capped at 3 events, got %d", eventLog.Count)
+	}
+	if first, last := eventLog.Events[0], eventLog.Events[2]; first.Type != "move" || last.Message != "Moved down to (2,1)" {
+		t.Errorf("Expected the 3 newest events, got %+v", eventLog.Events)
+	}
+
+	// A size of zero keeps nothing
+	svc = service.NewGameService(NewMockSessionManager(), NewMockConfigManager(), service.WithEventLog(0))
+	sessionInfo, err = svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if eventLog, _ := svc.GetEventLog(ctx, sessionInfo.ID); eventLog.Count != 0 || eventLog.Events == nil {
+		t.Errorf("Expected an empty event log when disabled, got %+v", eventLog)
+	}
+}
+
+func TestGameService_Surrender(t *testing.T) {
+	ctx := context.Background()
+	pub := &recordingPublisher{}
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager(), service.WithEventPublisher(pub))
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if _, err := svc.Move(ctx, sessionInfo.ID, "left", false); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+
+	state, err := svc.Surrender(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("Surrender failed: %v", err)
+	}
+	if !state.GameOver || state.Victory || state.GameOverReason != engine.GameOverSurrendered {
+		t.Errorf("Expected a game lost by surrender, got over=%v victory=%v reason=%q", state.GameOver, state.Victory, state.GameOverReason)
+	}
+	if state.Result == nil || state.Result.Reason != engine.ResultSurrendered || len(state.MoveHistory) != 1 {
+		t.Errorf("Expected a surrendered result with the history kept, got %+v and %d history entries", state.Result, len(state.MoveHistory))
+	}
+	events := pub.snapshot()
+	if last := events[len(events)-1]; last.Event.Type != "game_over" || last.State.GameOverReason != engine.GameOverSurrendered {
+		t.Errorf("Expected a game_over event with the final state, got %+v", last.Event)
+	}
+
+	// Surrender reports the same decision aids and outcome as a fetched state
+	fetched, err := svc.GetGameState(ctx, sessionInfo.ID)
+	if err != nil {
+		t.Fatalf("GetGameState failed: %v", err)
+	}
+	if state.LastMoveOutcome != engine.MoveOutcomeGameOver || fetched.LastMoveOutcome != state.LastMoveOutcome {
+		t.Errorf("Expected outcome game_over, got %q then %q", state.LastMoveOutcome, fetched.LastMoveOutcome)
+	}
+	if !reflect.DeepEqual(state.MovePreviews, fetched.MovePreviews) || !reflect.DeepEqual(state.RelativeView, fetched.RelativeView) ||
+		state.BatteryRisk != fetched.BatteryRisk || state.BatteryPercent != fetched.BatteryPercent {
+		t.Errorf("Expected the surrendered state to carry the fetched decision aids, got %+v and %+v", state, fetched)
+	}
+
+	// The session stays, but takes no more moves until reset
+	if _, err := svc.GetSession(ctx, sessionInfo.ID); err != nil {
+		t.Errorf("Expected the session to persist, got %v", err)
+	}
+	if _, err := svc.Move(ctx, sessionInfo.ID, "right", false); !errors.Is(err, engine.ErrGameOver) {
+		t.Errorf("Expected ErrGameOver moving after surrender, got %v", err)
+	}
+	if _, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"right"}, false); !errors.Is(err, engine.ErrGameOver) {
+		t.Errorf("Expected ErrGameOver bulk moving after surrender, got %v", err)
+	}
+	if _, err := svc.Surrender(ctx, sessionInfo.ID); !errors.Is(err, engine.ErrGameOver) {
+		t.Errorf("Expected ErrGameOver surrendering twice, got %v", err)
+	}
+	if result, err := svc.Move(ctx, sessionInfo.ID, "right", true); err != nil || !result.Success {
+		t.Errorf("Expected a move with reset to start a new game, got %v", err)
+	}
+
+	if _, err := svc.Surrender(ctx, "missing"); err == nil {
+		t.Error("Expected an error for an unknown session")
+	}
+}
+
+func TestGameService_CrashFeedback(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewGameService(NewMockSessionManager(), NewMockConfigManager())
+
+	sessionInfo, err := svc.CreateSession(ctx, "test")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Water lies above home at (3,2)
+	result, err := svc.Move(ctx, sessionInfo.ID, "up", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	want := engine.Position{X: 3, Y: 1}
+	if !result.GameState.Crash || result.GameState.CrashPos == nil || *result.GameState.CrashPos != want {
+		t.Errorf("Expected a crash at %+v, got crash=%v at %v", want, result.GameState.Crash, result.GameState.CrashPos)
+	}
+	state, _ := svc.GetGameState(ctx, sessionInfo.ID)
+	if !state.Crash || state.CrashPos == nil || *state.CrashPos != want {
+		t.Errorf("Expected the crash to be reported on the state, got crash=%v at %v", state.Crash, state.CrashPos)
+	}
+
+	// The next successful move clears it
+	result, err = svc.Move(ctx, sessionInfo.ID, "left", false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if result.GameState.Crash || result.GameState.CrashPos != nil {
+		t.Errorf("Expected a successful move to clear the crash, got crash=%v at %v", result.GameState.Crash, result.GameState.CrashPos)
+	}
+
+	// A bulk move that ends on a bump reports it too
+	bulk, err := svc.BulkMove(ctx, sessionInfo.ID, []string{"right", "up"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if !bulk.GameState.Crash || bulk.GameState.CrashPos == nil || *bulk.GameState.CrashPos != want {
+		t.Errorf("Expected the bulk move to end on a crash at %+v, got crash=%v at %v", want, bulk.GameState.Crash, bulk.GameState.CrashPos)
+	}
+
+	// A failed move that isn't into an obstacle is no crash
+	result, err = svc.Move(ctx, sessionInfo.ID, engine.ActionPark, false)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if result.Success || result.GameState.Crash {
+		t.Errorf("Expected a failed park without a crash, got success=%v crash=%v", result.Success, result.GameState.Crash)
+	}
+
+	if state, _ := svc.Reset(ctx, sessionInfo.ID); state.Crash {
+		t.Error("Expected reset to clear the crash")
+	}
+}
+
+func TestGameService_Doors(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	doors := *configs.configs["test"]
+	doors.Name = "doors"
+	// A door above home at (3,2) and its key to the right of home
+	doors.Layout = []string{"RRPRR", "RWRDR", "RRRHY", "RWRWR", "RRPRR"}
+	doors.Keys = []engine.LockTile{{ID: "gate", X: 4, Y: 2}}
+	doors.Doors = []engine.LockTile{{ID: "gate", X: 3, Y: 1}}
+	configs.SaveConfig("doors", &doors)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+	sess, err := svc.CreateSession(ctx, "doors")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	bulk, err := svc.BulkMove(ctx, sess.ID, []string{"up"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if bulk.StopReasonCode != "blocked_door" || bulk.AttemptedTo == nil || bulk.AttemptedTo.TileType != "door" || bulk.AttemptedTo.Passable {
+		t.Errorf("Expected the locked door to stop the sequence, got %q at %+v", bulk.StopReasonCode, bulk.AttemptedTo)
+	}
+
+	bulk, err = svc.BulkMove(ctx, sess.ID, []string{"right", "left", "up"}, false)
+	if err != nil {
+		t.Fatalf("BulkMove failed: %v", err)
+	}
+	if bulk.MovesExecuted != 3 || bulk.GameState.PlayerPos != (engine.Position{X: 3, Y: 1}) {
+		t.Errorf("Expected the key to open the door, got %d moves to %+v (%s)", bulk.MovesExecuted, bulk.GameState.PlayerPos, bulk.StoppedReason)
+	}
+	if keys := bulk.GameState.CollectedKeys; len(keys) != 1 || keys[0] != "gate" {
+		t.Errorf("Expected the gate key collected, got %v", keys)
+	}
+}
+
+func TestGameService_BulkMoveGameOverCodes(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	cooling := *configs.configs["test"]
+	cooling.Name = "cooling"
+	cooling.Chargers = []engine.ChargerLimit{{X: 3, Y: 2, Cooldown: 20}}
+	configs.SaveConfig("cooling", &cooling)
+	low := *configs.configs["test"]
+	low.Name = "low"
+	low.StartingBattery = 1
+	configs.SaveConfig("low", &low)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	// From home (3,2) the parks are at (2,0) and (2,4)
+	victory := []string{"left", "up", "up", "down", "down", "down", "down"}
+	// Charging fills the battery and starts the home's cooldown; five round
+	// trips to (4,2) then empty it back home while it still cools down
+	drain := []string{engine.ActionCharge}
+	for range 5 {
+		drain = append(drain, "right", "left")
+	}
+	tests := []struct {
+		name         string
+		config       string
+		moves        []string
+		executed     int
+		gameOverCode string
+		stopCode     string
+		stoppedOn    int
+	}{
+		// Empty on a charger the game goes on, but moving on needs a charge
+		{"ends on a charger at 0", "cooling", drain, 11, "", "", 0},
+		{"moves off a charger at 0", "cooling", append(drain, "left"), 11, "", "out_of_battery", 12},
+		{"strands on the last move", "low", []string{"left"}, 1, "stranded", "stranded", 0},
+		{"strands before the last move", "low", []string{"left", "left", "up"}, 1, "stranded", "stranded", 1},
+		{"wins on the last move", "test", victory, 7, "victory", "victory", 0},
+		{"wins before the last move", "test", append(victory, "up", "up"), 7, "victory", "victory", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionInfo, err := svc.CreateSession(ctx, tt.config)
+			if err != nil {
+				t.Fatalf("Failed to create session: %v", err)
+			}
+			result, err := svc.BulkMove(ctx, sessionInfo.ID, tt.moves, false)
+			if err != nil {
+				t.Fatalf("BulkMove failed: %v", err)
+			}
+			if result.MovesExecuted != tt.executed || result.GameOver != (tt.gameOverCode != "") {
+				t.Errorf("Expected %d moves and game_over=%v, got %d and %v: %s",
+					tt.executed, tt.gameOverCode != "", result.MovesExecuted, result.GameOver, result.Message)
+			}
+			if result.GameOverCode != tt.gameOverCode || result.StopReasonCode != tt.stopCode || result.StoppedOnMove != tt.stoppedOn {
+				t.Errorf("Expected game_over_code=%q stop_reason_code=%q stopped_on_move=%d, got %q %q %d",
+					tt.gameOverCode, tt.stopCode, tt.stoppedOn, result.GameOverCode, result.StopReasonCode, result.StoppedOnMove)
+			}
+		})
+	}
+}
+
+func TestGameService_GetConfigAnalytics(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	unplayed := *configs.configs["test"]
+	unplayed.Name = "unplayed"
+	configs.SaveConfig("unplayed", &unplayed)
+	svc := service.NewGameService(NewMockSessionManager(), configs, service.WithAnalyticsCache(0))
+
+	// A config nobody has played reports zeros
+	analytics, err := svc.GetConfigAnalytics(ctx, "unplayed")
+	if err != nil {
+		t.Fatalf("GetConfigAnalytics failed: %v", err)
+	}
+	if analytics.Sessions != 0 || analytics.WinRate != 0 || analytics.AverageMovesToWin != 0 || analytics.MostCommonGameOverReason != "" {
+		t.Errorf("Expected zeros for an unplayed config, got %+v", analytics)
+	}
+	if _, err := svc.GetConfigAnalytics(ctx, "missing"); err == nil {
+		t.Error("Expected an error for an unknown config")
+	}
+
+	// One win collecting both parks in 7 moves, two surrenders and a game
+	// still in progress
+	winner, _ := svc.CreateSession(ctx, "test")
+	result, err := svc.BulkMove(ctx, winner.ID, []string{"left", "up", "up", "down", "down", "down", "down"}, false)
+	if err != nil || !result.GameState.Victory {
+		t.Fatalf("Expected the moves to win, got %+v (%v)", result, err)
+	}
+	for i := 0; i < 2; i++ {
+		sess, _ := svc.CreateSession(ctx, "test")
+		if _, err := svc.Surrender(ctx, sess.ID); err != nil {
+			t.Fatalf("Surrender failed: %v", err)
+		}
+	}
+	playing, _ := svc.CreateSession(ctx, "test")
+	svc.Move(ctx, playing.ID, "left", false)
+	other, _ := svc.CreateSession(ctx, "unplayed")
+	svc.Surrender(ctx, other.ID)
+
+	analytics, err = svc.GetConfigAnalytics(ctx, "test")
+	if err != nil {
+		t.Fatalf("GetConfigAnalytics failed: %v", err)
+	}
+	if analytics.Sessions != 4 || analytics.FinishedGames != 3 || analytics.Wins != 1 {
+		t.Errorf("Expected 4 sessions, 3 finished and 1 win, got %+v", analytics)
+	}
+	if analytics.WinRate != 1.0/3 || analytics.AverageMovesToWin != 7 || analytics.AverageParksCollected != 2.0/3 {
+		t.Errorf("Unexpected averages: %+v", analytics)
+	}
+	if analytics.MostCommonGameOverReason != engine.ResultSurrendered || analytics.GameOverReasons[engine.ResultAllParks] != 1 {
+		t.Errorf("Expected surrendered to be most common, got %+v", analytics)
+	}
+
+	// With the cache on, analytics are reused until they expire
+	svc = service.NewGameService(NewMockSessionManager(), configs)
+	first, _ := svc.GetConfigAnalytics(ctx, "test")
+	sess, _ := svc.CreateSession(ctx, "test")
+	svc.Surrender(ctx, sess.ID)
+	if cached, _ := svc.GetConfigAnalytics(ctx, "test"); cached.FinishedGames != first.FinishedGames {
+		t.Errorf("Expected cached analytics, got %d finished games", cached.FinishedGames)
+	}
+}
+
+func TestGameService_RelativeView(t *testing.T) {
+	ctx := context.Background()
+	configs := NewMockConfigManager()
+	turned := *configs.configs["test"]
+	turned.Name = "turned"
+	// Home at (2,2) between a supercharger above, a trickle charger to the
+	// left and a park below
+	turned.Layout = []string{"RRPRR", "RWSBR", "RCHRR", "RRPWR", "RRPRR"}
+	configs.SaveConfig("turned", &turned)
+	svc := service.NewGameService(NewMockSessionManager(), configs)
+
+	tests := []struct {
+		moves                      []string // Out and back home, ending with the heading
+		heading                    string
+		rows                       []string
+		forward, left, right, back string
+	}{
+		{nil, "up", []string{"WSB", "CTR", "RPW"}, "S", "C", "R", "P"},
+		{[]string{"down", "up"}, "up", []string{"WSB", "CTR", "R✓W"}, "S", "C", "R", "✓"},
+		{[]string{"left", "right"}, "right", []string{"BRW", "STP", "WCR"}, "R", "S", "P", "C"},
+		{[]string{"up", "down"}, "down", []string{"WPR", "RTC", "BSW"}, "P", "R", "C", "S"},
+		{[]string{"right", "left"}, "left", []string{"RCW", "PTS", "WRB"}, "C", "P", "S", "R"},
+	}
+	for _, tt := range tests {
+		sess, err := svc.CreateSession(ctx, "turned")
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		for _, move := range tt.moves {
+			if result, err := svc.Move(ctx, sess.ID, move, false); err != nil || !result.Success {
+				t.Fatalf("Move %s failed: %v", move, err)
+			}
+		}
+		state, err := svc.GetGameState(ctx, sess.ID)
+		if err != nil {
+			t.Fatalf("GetGameState failed: %v", err)
+		}
+		view := state.RelativeView
+		if view == nil {
+			t.Fatalf("Expected a relative view after %v", tt.moves)
+		}
+		if view.Heading != tt.heading || !reflect.DeepEqual(view.Rows, tt.rows) {
+			t.Errorf("After %v: expected heading %s with rows %v, got %s with %v", tt.moves, tt.heading, tt.rows, view.Heading, view.Rows)
+		}
+		if view.Forward != tt.forward || view.Left != tt.left || view.Right != tt.right || view.Back != tt.back {
+			t.Errorf("After %v: expected forward/left/right/back %s%s%s%s, got %+v", tt.moves, tt.forward, tt.left, tt.right, tt.back, view)
+		}
+	}
+}
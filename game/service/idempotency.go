@@ -0,0 +1,111 @@
+package service
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key already used on
+// a session comes back with a different request
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different request")
+
+// Default idempotency settings, see WithIdempotency
+const (
+	DefaultIdempotencyWindow = 5 * time.Minute
+	DefaultIdempotencyKeys   = 32
+)
+
+// WithIdempotency configures how long the result of a move or bulk move sent
+// with an idempotency key is kept for retries, and how many keys each
+// session remembers before the least recently used is dropped. A window of
+// zero disables the cache, so every request is executed.
+func WithIdempotency(window time.Duration, keys int) Option {
+	return func(s *gameServiceImpl) {
+		s.idempotencyWindow = window
+		s.idempotencyKeys = keys
+	}
+}
+
+// idempotentResult is a remembered response to a keyed request
+type idempotentResult struct {
+	key     string
+	request string // Fingerprint of the request, see moveFingerprint
+	result  interface{}
+	at      time.Time
+}
+
+// idempotencyCache is a small LRU of one session's keyed results
+type idempotencyCache struct {
+	order   *list.List // Most recently used first
+	entries map[string]*list.Element
+}
+
+// cachedResult returns the result remembered for key on the session, or nil
+// when there is none within the window. A key remembered for a different
+// request returns ErrIdempotencyKeyReused. Callers hold s.mu.
+func (s *gameServiceImpl) cachedResult(sessionID, key, request string) (interface{}, error) {
+	if key == "" || s.idempotencyWindow <= 0 {
+		return nil, nil
+	}
+	cache := s.idempotency[sessionID]
+	if cache == nil {
+		return nil, nil
+	}
+	el, ok := cache.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := el.Value.(*idempotentResult)
+	if time.Since(entry.at) > s.idempotencyWindow {
+		cache.order.Remove(el)
+		delete(cache.entries, key)
+		return nil, nil
+	}
+	if entry.request != request {
+		return nil, fmt.Errorf("%w: %q", ErrIdempotencyKeyReused, key)
+	}
+	cache.order.MoveToFront(el)
+	return entry.result, nil
+}
+
+// rememberResult keeps result for retries of the keyed request, evicting the
+// session's least recently used key when it remembers too many. Callers hold
+// s.mu.
+func (s *gameServiceImpl) rememberResult(sessionID, key, request string, result interface{}) {
+	if key == "" || s.idempotencyWindow <= 0 || s.idempotencyKeys <= 0 {
+		return
+	}
+	cache := s.idempotency[sessionID]
+	if cache == nil {
+		cache = &idempotencyCache{order: list.New(), entries: make(map[string]*list.Element)}
+		s.idempotency[sessionID] = cache
+	}
+	if el, ok := cache.entries[key]; ok {
+		cache.order.Remove(el)
+	}
+	cache.entries[key] = cache.order.PushFront(&idempotentResult{key: key, request: request, result: result, at: time.Now()})
+	for cache.order.Len() > s.idempotencyKeys {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*idempotentResult).key)
+	}
+}
+
+// moveFingerprint identifies a move or bulk move request, so a key can't
+// replay the result of a different one
+func moveFingerprint(kind string, moves []string, reset bool) string {
+	return fmt.Sprintf("%s:%t:%s", kind, reset, strings.Join(moves, ","))
+}
+
+// snapshotState copies a result's game state so later moves don't change
+// what a retry gets back
+func snapshotState(state *engine.GameState) *engine.GameState {
+	cp := state.Clone()
+	cp.MovePreviews = state.MovePreviews
+	return cp
+}
@@ -0,0 +1,27 @@
+package service
+
+import "log/slog"
+
+// Logger receives the messages of the service, the session manager and the
+// API server, with attributes as alternating keys and values. *slog.Logger
+// implements it.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// DefaultLogger returns the Logger used when none is given, slog's default,
+// which writes through the standard log package
+func DefaultLogger() Logger {
+	return slog.Default()
+}
+
+// WithLogger sends the service's messages, such as failed saves, to logger
+// instead of DefaultLogger
+func WithLogger(logger Logger) Option {
+	return func(s *gameServiceImpl) {
+		s.logger = logger
+	}
+}
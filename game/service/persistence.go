@@ -0,0 +1,99 @@
+package service
+
+import "time"
+
+// Default save retry settings, see WithSaveRetry
+const (
+	DefaultSaveRetryBackoff    = time.Second
+	DefaultSaveRetryMaxBackoff = time.Minute
+)
+
+// WithSaveRetry sets how long after a failed save the session is saved
+// again. The wait doubles after each further failure, up to max. Zero
+// disables the retries, leaving the next change to save the session.
+func WithSaveRetry(backoff, max time.Duration) Option {
+	return func(s *gameServiceImpl) {
+		s.saveRetryBackoff = backoff
+		s.saveRetryMax = max
+	}
+}
+
+// failedSave is a session whose last save failed and is waiting for a retry
+type failedSave struct {
+	action   string
+	attempts int
+	timer    *time.Timer
+}
+
+// saveFailed records a failed save of a session and schedules a retry with
+// backoff; callers hold s.mu
+func (s *gameServiceImpl) saveFailed(sessionID, action string, err error) {
+	failed, ok := s.failedSaves[sessionID]
+	if !ok {
+		failed = &failedSave{}
+		s.failedSaves[sessionID] = failed
+	}
+	failed.action = action
+	failed.attempts++
+	if failed.timer != nil {
+		failed.timer.Stop()
+		failed.timer = nil
+	}
+
+	backoff := s.saveRetryBackoff
+	for i := 1; i < failed.attempts && backoff < s.saveRetryMax; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, s.saveRetryMax)
+	s.logger.Warn("failed to persist session",
+		"session_id", sessionID, "action", action, "attempt", failed.attempts, "retry_in", backoff, "error", err)
+	if backoff <= 0 {
+		return
+	}
+
+	failed.timer = time.AfterFunc(backoff, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		// Saved since, or dropped with the session
+		if s.failedSaves[sessionID] != failed {
+			return
+		}
+		if _, err := s.sessions.Get(sessionID); err != nil {
+			delete(s.failedSaves, sessionID)
+			return
+		}
+		s.saveNow(sessionID, failed.action)
+	})
+}
+
+// saveRecovered clears a session's failed save after it was saved; callers
+// hold s.mu
+func (s *gameServiceImpl) saveRecovered(sessionID string) {
+	failed, ok := s.failedSaves[sessionID]
+	if !ok {
+		return
+	}
+	if failed.timer != nil {
+		failed.timer.Stop()
+	}
+	delete(s.failedSaves, sessionID)
+	s.logger.Info("persisted session after failed saves", "session_id", sessionID, "attempts", failed.attempts)
+}
+
+// dropFailedSave stops retrying the save of a deleted session; callers hold
+// s.mu
+func (s *gameServiceImpl) dropFailedSave(sessionID string) {
+	if failed, ok := s.failedSaves[sessionID]; ok {
+		if failed.timer != nil {
+			failed.timer.Stop()
+		}
+		delete(s.failedSaves, sessionID)
+	}
+}
+
+// persistenceDegraded reports whether any session has unsaved changes after
+// a failed save; callers hold s.mu
+func (s *gameServiceImpl) persistenceDegraded() bool {
+	return len(s.failedSaves) > 0
+}
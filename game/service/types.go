@@ -14,6 +14,90 @@ type SessionInfo struct {
 	LastAccessedAt time.Time          `json:"last_accessed_at"`
 	GameState      *engine.GameState  `json:"game_state"`
 	GameConfig     *engine.GameConfig `json:"game_config"`
+	// GameOverReason mirrors GameState.GameOverReason for session list summaries
+	GameOverReason engine.GameOverReason `json:"game_over_reason,omitempty"`
+	// ConfigDrift reports that the session's config changed after it was saved
+	ConfigDrift bool `json:"config_drift"`
+	// TTLSeconds is the session's own inactivity timeout; zero means the
+	// server-wide cleanup window applies
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+	// Label is the session's optional user-facing name
+	Label string `json:"label,omitempty"`
+}
+
+// SessionConfig is the config a session plays on. The config's fields are
+// inlined so it decodes straight into an engine.GameConfig.
+type SessionConfig struct {
+	engine.GameConfig
+	ConfigID string `json:"config_id"`
+	// Checksum identifies the layout and rules the session was created or
+	// saved with; empty when the session manager doesn't record one
+	Checksum    string `json:"checksum,omitempty"`
+	ConfigDrift bool   `json:"config_drift"`
+}
+
+// SessionFilter selects sessions for DeleteSessions. Set fields must all
+// match; at least one must be set.
+type SessionFilter struct {
+	ConfigName string `json:"config_name,omitempty"` // Config ID or display name
+	GameOver   *bool  `json:"game_over,omitempty"`
+}
+
+// Empty reports whether the filter would match every session
+func (f SessionFilter) Empty() bool {
+	return f.ConfigName == "" && f.GameOver == nil
+}
+
+// CreateSessionOptions configures a new session
+type CreateSessionOptions struct {
+	TTLSeconds int `json:"ttl_seconds,omitempty"` // Inactivity timeout; zero uses the server-wide window
+}
+
+// MoveOptions configures a single move operation
+type MoveOptions struct {
+	Reset    bool   `json:"reset,omitempty"`
+	Autoplay bool   `json:"autoplay,omitempty"` // Tag the move as issued by the autoplay bot
+	Intent   string `json:"intent,omitempty"`   // Recorded on the move's history entry
+	// IdempotencyKey makes a retry with the same key return the first
+	// result instead of moving again, see WithIdempotency
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// BulkMoveOptions configures a bulk move operation
+type BulkMoveOptions struct {
+	Reset           bool   `json:"reset,omitempty"`
+	ContinueOnBlock bool   `json:"continue_on_block,omitempty"` // Record blocked moves as failed steps instead of stopping
+	Intent          string `json:"intent,omitempty"`            // Recorded on the history entry of the first move
+	// Intents annotates each move by index; an empty or missing entry falls
+	// back to Intent for the first move and to no intent after it
+	Intents []string `json:"intents,omitempty"`
+	// StopBelowBattery halts the sequence once a move leaves the battery below
+	// it, with moves still to go; 0 disables the check
+	StopBelowBattery int `json:"stop_below_battery,omitempty"`
+	// IdempotencyKey makes a retry with the same key return the first
+	// result instead of moving again, see WithIdempotency
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
+// MultiBulkMoveResult is one bulk move applied to several sessions, keyed by
+// session ID. A session that couldn't be moved is in Errors rather than
+// Results.
+type MultiBulkMoveResult struct {
+	Results   map[string]*BulkMoveResult `json:"results"`
+	Errors    map[string]string          `json:"errors,omitempty"`
+	Succeeded int                        `json:"succeeded"`
+	Failed    int                        `json:"failed"`
+}
+
+// intent returns the intent to record with move i of the sequence
+func (o BulkMoveOptions) intent(i int) string {
+	if i < len(o.Intents) && o.Intents[i] != "" {
+		return o.Intents[i]
+	}
+	if i == 0 {
+		return o.Intent
+	}
+	return ""
 }
 
 // MoveResult contains the result of a move operation
@@ -24,22 +108,47 @@ type MoveResult struct {
 	Events      []GameEvent       `json:"events,omitempty"`
 	Step        *StepInfo         `json:"step,omitempty"`
 	AttemptedTo *AttemptInfo      `json:"attempted_to,omitempty"`
+	// LoopDetected is set when the recent moves circle without progress; a
+	// "loop_warning" event explains it. See WithLoopDetection.
+	LoopDetected bool `json:"loop_detected,omitempty"`
+}
+
+// MoveTowardResult is the move taken toward a target, with the direction
+// chosen and how many moves the shortest drive there still takes
+type MoveTowardResult struct {
+	*MoveResult
+	Target        engine.Position `json:"target"`
+	Direction     string          `json:"direction"`
+	RemainingPath int             `json:"remaining_path"`
 }
 
 // BulkMoveResult contains the result of multiple moves
 type BulkMoveResult struct {
 	// Summary
 	MovesExecuted  int               `json:"moves_executed"`
+	BlockedCount   int               `json:"blocked_count"`
 	TotalMoves     int               `json:"total_moves"`     // Deprecated: kept for backward compatibility (same as requested_moves)
 	RequestedMoves int               `json:"requested_moves"` // The number of moves requested in this call
 	Success        bool              `json:"success"`
 	GameState      *engine.GameState `json:"game_state"`
 	Events         []GameEvent       `json:"events"`
-	StoppedReason  string            `json:"stopped_reason,omitempty"`   // Human-readable reason
-	StopReasonCode string            `json:"stop_reason_code,omitempty"` // Machine-friendly code: blocked_boundary|blocked_building|blocked_water|out_of_battery|stranded|game_over|victory
-	StoppedOnMove  int               `json:"stopped_on_move,omitempty"`  // 1-based index of the move that caused stop
-	Truncated      bool              `json:"truncated,omitempty"`
-	Limit          int               `json:"limit,omitempty"`
+	StoppedReason  string            `json:"stopped_reason,omitempty"` // Human-readable reason
+	// StopReasonCode is a machine-friendly code for why the sequence stopped
+	// or, when it ran to the end of a finished game, the game over code. A
+	// turned-down move reports, first match wins: not_on_charger or
+	// not_on_park for a failed charge or park, blocked_boundary,
+	// blocked_building, blocked_water or blocked_door (a locked door) for
+	// a crash (even one that ends the game), would_strand, the game over code when the move ended the
+	// game (out_of_battery for moving on an empty battery, stranded for
+	// running empty off a charger), charge_hold, and out_of_battery for an
+	// empty battery on a charger. A move that ends the game before the last
+	// reports the game over code (victory, stranded, hazard, ...), a
+	// StopBelowBattery stop low_battery, and a game already over before the
+	// sequence game_over.
+	StopReasonCode string `json:"stop_reason_code,omitempty"`
+	StoppedOnMove  int    `json:"stopped_on_move,omitempty"` // 1-based index of the move that caused stop
+	Truncated      bool   `json:"truncated,omitempty"`
+	Limit          int    `json:"limit,omitempty"`
 
 	// Start/end snapshot
 	StartPos     engine.Position `json:"start_pos"`
@@ -55,12 +164,14 @@ type BulkMoveResult struct {
 	AttemptedTo *AttemptInfo `json:"attempted_to,omitempty"`
 
 	// Final status aids
-	GameOver      bool     `json:"game_over"`
-	GameOverCode  string   `json:"game_over_code,omitempty"`
-	Message       string   `json:"message,omitempty"`
-	PossibleMoves []string `json:"possible_moves,omitempty"`
-	LocalView3x3  []string `json:"local_view_3x3,omitempty"`
-	BatteryRisk   string   `json:"battery_risk,omitempty"`
+	GameOver       bool     `json:"game_over"`
+	GameOverCode   string   `json:"game_over_code,omitempty"`
+	Message        string   `json:"message,omitempty"`
+	PossibleMoves  []string `json:"possible_moves,omitempty"`
+	LocalView3x3   []string `json:"local_view_3x3,omitempty"`
+	BatteryRisk    string   `json:"battery_risk,omitempty"`
+	BatteryPercent int      `json:"battery_percent"`
+	LoopDetected   bool     `json:"loop_detected,omitempty"` // Recent moves circle without progress, see MoveResult
 }
 
 // StepInfo is a compact record for each executed move in the bulk call
@@ -77,9 +188,11 @@ type StepInfo struct {
 	Charged       bool            `json:"charged,omitempty"`
 	Park          bool            `json:"park,omitempty"`
 	Victory       bool            `json:"victory,omitempty"`
+	AttemptedTo   *AttemptInfo    `json:"attempted_to,omitempty"` // Set on blocked steps
+	Intent        string          `json:"intent,omitempty"`       // Intent recorded with the step, as stored in history
 }
 
-// AttemptInfo details the first failed target cell attempted
+// AttemptInfo details a failed target cell attempted
 type AttemptInfo struct {
 	X        int    `json:"x"`
 	Y        int    `json:"y"`
@@ -90,16 +203,23 @@ type AttemptInfo struct {
 
 // GameEvent represents an event that occurred during gameplay
 type GameEvent struct {
-	Type      string          `json:"type"` // "move", "charge", "park_visited", "game_over", "victory", "reset"
+	Type      string          `json:"type"` // "move", "charge", "wait", "park_visited", "game_over", "victory", "reset", "loop_warning", "hazard_hit"
 	Message   string          `json:"message"`
 	Timestamp time.Time       `json:"timestamp"`
 	Position  engine.Position `json:"position,omitempty"`
 }
 
+// SessionEvent is a game event together with the session it happened in
+type SessionEvent struct {
+	SessionID string
+	Event     GameEvent
+	State     *engine.GameState // Live session state; copy what is needed before Publish returns
+}
+
 // HistoryOptions configures move history retrieval
 type HistoryOptions struct {
 	Page  int    `json:"page"`
-	Limit int    `json:"limit"`
+	Limit int    `json:"limit"` // 0 uses the config's default page size
 	Order string `json:"order"` // "asc" or "desc"
 }
 
@@ -114,12 +234,238 @@ type HistoryResponse struct {
 	HasPrevious bool                      `json:"has_previous"`
 }
 
+// Outcomes reported in MoveDetail.Event
+const (
+	MoveEventMove        = "move"         // Ordinary step onto a new cell
+	MoveEventStay        = "stay"         // Succeeded without leaving the cell, such as a wait
+	MoveEventCharge      = "charge"       // Battery went up
+	MoveEventPark        = "park"         // Collected a park
+	MoveEventTeleport    = "teleport"     // Debug teleport
+	MoveEventHazardHit   = "hazard_hit"   // Ran into a patrolling hazard
+	MoveEventBlocked     = "blocked"      // Move failed; the player stayed put
+	MoveEventWouldStrand = "would_strand" // Turned down by the battery reserve
+)
+
+// MoveDetail is one move history entry with what it did to the player
+type MoveDetail struct {
+	engine.MoveHistoryEntry
+	Index      int `json:"index"`       // 1-based position in the cumulative history
+	TotalMoves int `json:"total_moves"` // Length of the cumulative history
+	// TileEntered is the current type of the cell the move ended on; empty
+	// for a failed move
+	TileEntered engine.CellType `json:"tile_entered,omitempty"`
+	Event       string          `json:"event"`
+	// CurrentSegment is false for moves made before the last reset
+	CurrentSegment bool `json:"current_segment"`
+}
+
+// ParkInfo describes one park on a session's grid
+type ParkInfo struct {
+	ID        string          `json:"id"`
+	Position  engine.Position `json:"position"`
+	Collected bool            `json:"collected"`
+	Points    int             `json:"points"`            // What collecting it scores under the session's scoring mode
+	Expired   bool            `json:"expired,omitempty"` // Expired before it was collected; it no longer counts
+}
+
+// ParksResponse lists every park of a session, ordered by ID, with totals
+type ParksResponse struct {
+	Parks     []ParkInfo `json:"parks"`
+	Collected int        `json:"collected"`
+	Total     int        `json:"total"`
+	Required  int        `json:"required"` // Parks needed for victory: those not expired
+}
+
+// EventLogResponse lists a session's most recent events, oldest first
+type EventLogResponse struct {
+	SessionID string      `json:"session_id"`
+	Count     int         `json:"count"`
+	Events    []GameEvent `json:"events"`
+}
+
+// SessionComparison aligns the current games of two sessions on the same config
+type SessionComparison struct {
+	ConfigName     string       `json:"config_name"`
+	A              SessionTrace `json:"a"`
+	B              SessionTrace `json:"b"`
+	Diverged       bool         `json:"diverged"`
+	DivergedAtMove int          `json:"diverged_at_move,omitempty"` // 1-based index of the first move where the paths differ
+}
+
+// SessionTrace summarizes one side of a session comparison
+type SessionTrace struct {
+	SessionID       string `json:"session_id"`
+	MovesUsed       int    `json:"moves_used"`
+	ParksCollected  int    `json:"parks_collected"`
+	FinalBattery    int    `json:"final_battery"`
+	Outcome         string `json:"outcome"`           // victory|game_over|in_progress
+	ParksOverTime   []int  `json:"parks_over_time"`   // parks collected after each move
+	BatteryOverTime []int  `json:"battery_over_time"` // battery after each move
+}
+
+// LeaderboardSort selects how GetLeaderboard ranks sessions
+type LeaderboardSort string
+
+const (
+	// LeaderboardByScore ranks every session by parks collected, fewer moves
+	// first on equal scores
+	LeaderboardByScore LeaderboardSort = "score"
+	// LeaderboardByMoves ranks only victorious sessions, fewest moves to win first
+	LeaderboardByMoves LeaderboardSort = "moves"
+)
+
+// LeaderboardOptions selects and orders the sessions on a leaderboard
+type LeaderboardOptions struct {
+	ConfigName string          `json:"config_name,omitempty"` // Config ID or display name; empty ranks every session
+	SortBy     LeaderboardSort `json:"sort_by,omitempty"`     // Defaults to LeaderboardByScore
+	Limit      int             `json:"limit,omitempty"`       // Maximum entries; zero returns all
+}
+
+// Leaderboard ranks sessions by their current game. Remaining ties are broken
+// by the earlier created session, then by session ID.
+type Leaderboard struct {
+	ConfigName string             `json:"config_name,omitempty"`
+	SortBy     LeaderboardSort    `json:"sort_by"`
+	Total      int                `json:"total"` // Ranked sessions before the limit
+	Entries    []LeaderboardEntry `json:"entries"`
+}
+
+// LeaderboardEntry is one ranked session
+type LeaderboardEntry struct {
+	Rank       int       `json:"rank"` // 1-based
+	SessionID  string    `json:"session_id"`
+	ConfigName string    `json:"config_name"`
+	Score      int       `json:"score"`
+	Moves      int       `json:"moves"` // Moves in the current game
+	Battery    int       `json:"battery"`
+	Victory    bool      `json:"victory"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ConfigAnalytics aggregates the current games of every session playing a
+// config. Finished games are those whose game is over; averages are 0 while
+// there are none to average.
+type ConfigAnalytics struct {
+	ConfigName            string  `json:"config_name"`
+	Sessions              int     `json:"sessions"`
+	FinishedGames         int     `json:"finished_games"`
+	Wins                  int     `json:"wins"`
+	WinRate               float64 `json:"win_rate"`                // Wins out of finished games, 0 to 1
+	AverageMovesToWin     float64 `json:"average_moves_to_win"`    // Successful moves of won games
+	AverageParksCollected float64 `json:"average_parks_collected"` // Per finished game
+	// MostCommonGameOverReason is the reason most finished games ended with,
+	// the first alphabetically on a tie; empty while none has finished
+	MostCommonGameOverReason engine.ResultReason         `json:"most_common_game_over_reason,omitempty"`
+	GameOverReasons          map[engine.ResultReason]int `json:"game_over_reasons"`
+	GeneratedAt              time.Time                   `json:"generated_at"` // Served from the cache until it is WithAnalyticsCache old
+}
+
+// GhostPath is a saved session's current run, move by move, for a live
+// session to race against
+type GhostPath struct {
+	SessionID     string          `json:"session_id"`      // Live session the ghost is for
+	FromSessionID string          `json:"from_session_id"` // Session the run was recorded in
+	ConfigName    string          `json:"config_name"`
+	Start         engine.Position `json:"start"`
+	Steps         []GhostStep     `json:"steps"`
+	Outcome       string          `json:"outcome"`          // victory|game_over|in_progress
+	DurationSecs  int64           `json:"duration_seconds"` // From the first to the last step
+}
+
+// GhostStep is one move of a ghost path
+type GhostStep struct {
+	Step          int             `json:"step"` // 1-based index in the run
+	Action        string          `json:"action"`
+	Position      engine.Position `json:"position"` // Position after the move
+	Battery       int             `json:"battery"`
+	Success       bool            `json:"success"`
+	Timestamp     int64           `json:"timestamp"`      // Unix seconds when the move was made
+	OffsetSeconds int64           `json:"offset_seconds"` // Seconds since the first step
+}
+
+// SolveResult is a winning move plan computed from a session's current state
+type SolveResult struct {
+	Solved     bool     `json:"solved"`
+	Moves      []string `json:"moves"`                 // Moves to submit in order; empty when already won
+	MoveCount  int      `json:"move_count"`            // len(Moves)
+	Reason     string   `json:"reason,omitempty"`      // Why no plan was returned
+	ReasonCode string   `json:"reason_code,omitempty"` // game_over|unsolvable|budget_exhausted
+	ElapsedMs  int64    `json:"elapsed_ms"`
+}
+
+// PlanStep is one move of a winning plan with what it is for. Purpose is
+// "park" when the move is on the way to collect the park at Target, "key"
+// when it heads to the key at Target to open its doors, or "charge" when
+// it heads to the charger at Target to charge to full.
+// Direction may also be a charge, park or wait action.
+type PlanStep struct {
+	Direction    string          `json:"direction"`
+	Purpose      string          `json:"purpose"`
+	BatteryAfter int             `json:"battery_after"`
+	Target       engine.Position `json:"target"`
+}
+
+// VerifyResult reports whether replaying a session's current game from its
+// move history reproduces the live state
+type VerifyResult struct {
+	SessionID     string          `json:"session_id"`
+	Verified      bool            `json:"verified"`
+	MovesReplayed int             `json:"moves_replayed"` // Moves since the last reset
+	Mismatch      *ReplayMismatch `json:"mismatch,omitempty"`
+}
+
+// ReplayMismatch is the first field where the replayed state differs from the
+// live one. Field is a JSON path into the game state such as
+// "current_moves[3].to_position.x" or "battery"; for lists of different
+// lengths it ends in ".length" and the values are the lengths.
+type ReplayMismatch struct {
+	Field    string `json:"field"`
+	Live     any    `json:"live"`
+	Replayed any    `json:"replayed"`
+}
+
+// SharedSessionOptions configures a new shared session
+type SharedSessionOptions struct {
+	// HideOpponentStats masks other players' battery and score in the
+	// updates each player receives, until the game is over
+	HideOpponentStats bool `json:"hide_opponent_stats,omitempty"`
+}
+
+// SharedSessionInfo describes a shared session where several players race on one grid
+type SharedSessionInfo struct {
+	ID                string                  `json:"id"`
+	ConfigName        string                  `json:"config_name"`
+	CreatedAt         time.Time               `json:"created_at"`
+	LastAccessedAt    time.Time               `json:"last_accessed_at"`
+	HideOpponentStats bool                    `json:"hide_opponent_stats,omitempty"`
+	State             *engine.SharedGameState `json:"state"`
+}
+
+// SharedMoveResult is the outcome of one player's move in a shared session
+type SharedMoveResult struct {
+	Success   bool                    `json:"success"`
+	PlayerID  string                  `json:"player_id"`
+	Player    *engine.SharedPlayer    `json:"player"`
+	Message   string                  `json:"message"`
+	Collected string                  `json:"collected,omitempty"` // ID of the park this move collected
+	State     *engine.SharedGameState `json:"state"`
+	// HideOpponentStats is set for sessions whose updates are masked per
+	// player with ViewFor
+	HideOpponentStats bool `json:"hide_opponent_stats,omitempty"`
+}
+
 // ConfigInfo provides information about a game configuration
 type ConfigInfo struct {
 	Filename    string `json:"filename"`
 	ConfigID    string `json:"config_id"` // The identifier to use for session creation
 	Name        string `json:"name"`      // Display name
 	Description string `json:"description"`
+	Author      string `json:"author,omitempty"`  // Optional config metadata
+	Version     string `json:"version,omitempty"` // Optional config metadata
+	Notes       string `json:"notes,omitempty"`   // Optional config metadata
 	GridSize    int    `json:"grid_size"`
+	GridWidth   int    `json:"grid_width"`
+	GridHeight  int    `json:"grid_height"`
 	MaxBattery  int    `json:"max_battery"`
+	Difficulty  int    `json:"difficulty_score"` // See engine.AnalyzeConfig
 }
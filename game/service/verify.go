@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// verifySkipped are the game state fields VerifySession never compares: the
+// cumulative history, which holds earlier games, and the views computed for
+// clients rather than played
+var verifySkipped = []string{
+	"move_history", "local_view", "local_view_3x3", "relative_view", "battery_risk", "battery_percent",
+	"move_previews", "last_move_outcome", "crash", "crash_pos", "persistence_degraded", "view",
+}
+
+// WithVerifyIgnore leaves more game state fields out of VerifySession's
+// comparison, named by their JSON path without list indices, such as
+// "message" or "current_moves.timestamp". Use it for fields a deliberate
+// engine change is known to alter.
+func WithVerifyIgnore(fields ...string) Option {
+	return func(s *gameServiceImpl) {
+		s.verifyIgnore = append(s.verifyIgnore, fields...)
+	}
+}
+
+// VerifySession replays the moves of a session's current game on a fresh
+// engine and compares the result with the live state, reporting the first
+// field that differs. The moves are compared one by one before the rest of
+// the state, so a mismatch points at the first move that no longer plays
+// out as recorded. A session cloned without its history doesn't verify, as
+// its moves don't lead to its state.
+func (s *gameServiceImpl) VerifySession(ctx context.Context, sessionID string) (*VerifyResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, err := s.sessions.Get(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("session not found: %w", err)
+	}
+	s.sessions.UpdateLastAccessed(sessionID)
+
+	live := sess.Engine.GetState()
+	replay, err := engine.NewEngine(sess.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay engine: %w", err)
+	}
+	if err := replay.SetState(live.Clone()); err != nil {
+		return nil, fmt.Errorf("failed to load replay state: %w", err)
+	}
+	replayed := replay.Rewind(len(live.CurrentMoves))
+	// Surrendering ends the game without a move to replay
+	if live.GameOverReason == engine.GameOverSurrendered && !replayed.GameOver {
+		replay.Surrender()
+		replayed = replay.GetState()
+	}
+
+	ignore := map[string]bool{}
+	for _, field := range append(verifySkipped, s.verifyIgnore...) {
+		ignore[field] = true
+	}
+	mismatch := diffValue("current_moves", "current_moves",
+		reflect.ValueOf(live.CurrentMoves), reflect.ValueOf(replayed.CurrentMoves), ignore)
+	if mismatch == nil {
+		ignore["current_moves"] = true
+		mismatch = diffValue("", "", reflect.ValueOf(*live), reflect.ValueOf(*replayed), ignore)
+	}
+
+	return &VerifyResult{
+		SessionID:     sessionID,
+		Verified:      mismatch == nil,
+		MovesReplayed: len(live.CurrentMoves),
+		Mismatch:      mismatch,
+	}, nil
+}
+
+// diffValue returns the first difference between live and replayed, walking
+// structs by their JSON names. path locates the values for the report and
+// key, the path without list indices, is looked up in ignore. Empty and nil
+// lists and maps are equal, as saving drops the difference.
+func diffValue(path, key string, live, replayed reflect.Value, ignore map[string]bool) *ReplayMismatch {
+	if ignore[key] {
+		return nil
+	}
+	switch live.Kind() {
+	case reflect.Pointer:
+		if live.IsNil() || replayed.IsNil() {
+			if live.IsNil() == replayed.IsNil() {
+				return nil
+			}
+			return &ReplayMismatch{Field: path, Live: live.Interface(), Replayed: replayed.Interface()}
+		}
+		return diffValue(path, key, live.Elem(), replayed.Elem(), ignore)
+	case reflect.Struct:
+		for i := 0; i < live.NumField(); i++ {
+			field := live.Type().Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if m := diffValue(joinPath(path, name), joinPath(key, name), live.Field(i), replayed.Field(i), ignore); m != nil {
+				return m
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if live.Len() != replayed.Len() {
+			return &ReplayMismatch{Field: path + ".length", Live: live.Len(), Replayed: replayed.Len()}
+		}
+		for i := 0; i < live.Len(); i++ {
+			if m := diffValue(fmt.Sprintf("%s[%d]", path, i), key, live.Index(i), replayed.Index(i), ignore); m != nil {
+				return m
+			}
+		}
+		return nil
+	case reflect.Map:
+		keys := live.MapKeys()
+		for _, k := range replayed.MapKeys() {
+			if !live.MapIndex(k).IsValid() {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, k := range keys {
+			l, r := live.MapIndex(k), replayed.MapIndex(k)
+			elemPath := fmt.Sprintf("%s[%v]", path, k)
+			if !l.IsValid() || !r.IsValid() {
+				return &ReplayMismatch{Field: elemPath, Live: mapValue(l), Replayed: mapValue(r)}
+			}
+			if m := diffValue(elemPath, key, l, r, ignore); m != nil {
+				return m
+			}
+		}
+		return nil
+	default:
+		if !reflect.DeepEqual(live.Interface(), replayed.Interface()) {
+			return &ReplayMismatch{Field: path, Live: live.Interface(), Replayed: replayed.Interface()}
+		}
+		return nil
+	}
+}
+
+// joinPath appends a field name to a JSON path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// mapValue returns a map entry's value, or nil for a missing entry
+func mapValue(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
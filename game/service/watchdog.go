@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// Default loop watchdog settings, see WithLoopDetection
+const (
+	DefaultLoopWindow   = 8
+	DefaultLoopMaxCells = 4
+)
+
+// WithLoopDetection configures the loop watchdog, which flags move results
+// whose last window moves circled among at most maxCells cells without
+// progress. The flag is advisory; moves are never refused. A window of zero
+// disables it.
+func WithLoopDetection(window, maxCells int) Option {
+	return func(s *gameServiceImpl) {
+		s.loopWindow = window
+		s.loopMaxCells = maxCells
+	}
+}
+
+// loopWarning checks the current game of state for a loop and returns the
+// warning event to surface with the move result, if any
+func (s *gameServiceImpl) loopWarning(state *engine.GameState) (GameEvent, bool) {
+	if state.GameOver {
+		return GameEvent{}, false
+	}
+	cells, ok := detectLoop(state, s.loopWindow, s.loopMaxCells)
+	if !ok {
+		return GameEvent{}, false
+	}
+	return GameEvent{
+		Type: "loop_warning",
+		Message: fmt.Sprintf("Possible loop: the last %d moves circled %d cells without progress; try a different route",
+			s.loopWindow, cells),
+		Timestamp: time.Now(),
+		Position:  state.PlayerPos,
+	}, true
+}
+
+// detectLoop reports whether the last window moves of the current game form
+// a tight cycle: they cover at most maxCells cells, end where they started,
+// collect no new park and leave the battery no higher than it was. It returns
+// the number of cells covered.
+func detectLoop(state *engine.GameState, window, maxCells int) (int, bool) {
+	moves := state.CurrentMoves
+	if window <= 0 || len(moves) < window {
+		return 0, false
+	}
+	tail := moves[len(moves)-window:]
+	first, last := tail[0], tail[len(tail)-1]
+	if first.FromPosition != last.ToPosition || last.Battery > first.Battery-first.BatteryDelta {
+		return 0, false
+	}
+
+	// Cells the player stood on before the window; entering a collected park
+	// outside this set means it was collected within the window
+	earlier := map[engine.Position]bool{moves[0].FromPosition: true}
+	for _, m := range moves[:len(moves)-window] {
+		earlier[m.ToPosition] = true
+	}
+
+	cells := map[engine.Position]bool{first.FromPosition: true}
+	for _, m := range tail {
+		if m.Action == engine.ActionPark && m.Success {
+			return 0, false
+		}
+		to := m.ToPosition
+		if !earlier[to] && state.InBounds(to.X, to.Y) {
+			if cell := state.Grid[to.Y][to.X]; cell.Type == engine.Park && cell.Visited {
+				return 0, false
+			}
+		}
+		earlier[to] = true
+		cells[to] = true
+	}
+	if len(cells) > maxCells {
+		return 0, false
+	}
+	return len(cells), true
+}
@@ -0,0 +1,754 @@
+package session
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// CompactVersion is the format version written by EncodeCompact. Bump it
+// when the layout changes and teach DecodeCompact to read the previous one.
+// Version 2 adds the primary home, version 3 the hazards, version 4 the
+// scoring mode and charge count, version 5 the charge hold, version 6 the
+// park expiry, version 7 the carried parks, version 8 the result's battery
+// bonus, version 9 the lives and last checkpoint, version 10 the collected
+// keys and version 11 the session label.
+const CompactVersion = 11
+
+// compactMagic starts every compact session file
+var compactMagic = []byte("RTGS")
+
+// ErrNotCompact is returned by DecodeCompact for data without the compact header
+var ErrNotCompact = errors.New("not a compact session")
+
+// ErrCompactVersion is returned by DecodeCompact for files of an unknown version
+var ErrCompactVersion = errors.New("unsupported compact session version")
+
+// cellCodes are the bytes that stand for each cell type in the encoded grid
+var cellCodes = map[engine.CellType]byte{
+	engine.Road:         'R',
+	engine.Home:         'H',
+	engine.Park:         'P',
+	engine.Supercharger: 'S',
+	engine.Fuel:         'F',
+	engine.Trickle:      'C',
+	engine.Checkpoint:   'K',
+	engine.Key:          'Y',
+	engine.Door:         'D',
+	engine.Water:        'W',
+	engine.Building:     'B',
+}
+
+// History entry flags
+const (
+	entrySuccess = 1 << iota
+	entryAutoplay
+	entryIntent
+	entryRandomEvent
+	entryRevisitPenalty
+	entryHazardHit
+	entryWouldStrand
+	entryParkCollected
+)
+
+// How the current moves are stored
+const (
+	currentMovesNil = iota
+	currentMovesList
+	currentMovesHistoryTail // The last n entries of the move history
+)
+
+// EncodeCompact encodes a persisted session, whose GameState must be an
+// *engine.GameState, in a binary format much smaller than JSON for large
+// grids: the grid is run-length encoded by row, park state is kept as
+// bitsets and numbers are varints. The computed views on the state (local
+// views, battery risk and percent, move previews) are left out; the service
+// rebuilds them on the next action.
+func EncodeCompact(data *PersistedSessionData) ([]byte, error) {
+	state, ok := data.GameState.(*engine.GameState)
+	if !ok || state == nil {
+		return nil, fmt.Errorf("compact encoding needs an *engine.GameState, got %T", data.GameState)
+	}
+
+	w := &compactWriter{buf: append([]byte(nil), compactMagic...)}
+	w.uvarint(CompactVersion)
+
+	w.str(data.ID)
+	w.str(data.ConfigName)
+	if err := w.stamp(data.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := w.stamp(data.LastAccessedAt); err != nil {
+		return nil, err
+	}
+	w.str(data.ConfigChecksum)
+	w.varint(int64(data.TTLSeconds))
+
+	if err := w.grid(state); err != nil {
+		return nil, err
+	}
+	w.pos(state.PlayerPos)
+	w.varint(int64(state.Battery))
+	w.varint(int64(state.MaxBattery))
+	w.varint(int64(state.Score))
+	w.str(state.Message)
+	w.flag(state.GameOver)
+	w.flag(state.Victory)
+	w.str(string(state.GameOverReason))
+	w.flag(state.Result != nil)
+	if r := state.Result; r != nil {
+		w.str(string(r.Outcome))
+		w.str(string(r.Reason))
+		w.varint(int64(r.MovesUsed))
+		w.varint(int64(r.ElapsedMoves))
+		w.varint(int64(r.ParksCollected))
+		w.varint(int64(r.TotalParks))
+		w.varint(int64(r.FinalScore))
+		w.varint(int64(r.ChargeCount))
+		w.varint(int64(r.ChargePenalty))
+		w.varint(int64(r.BatteryBonus))
+	}
+	w.str(state.ConfigName)
+	w.history(state.MoveHistory)
+	w.varint(int64(state.TotalMoves))
+	w.varint(state.RandomSeed)
+
+	w.count(len(state.Chargers), state.Chargers == nil)
+	for _, c := range state.Chargers {
+		w.pos(c.Position)
+		w.varint(int64(c.UsesLeft))
+		w.varint(int64(c.CooldownLeft))
+		w.flag(c.Depleted)
+		w.varint(int64(c.UsedOnMove))
+	}
+	w.count(len(state.ConsumedFuel), state.ConsumedFuel == nil)
+	for _, f := range state.ConsumedFuel {
+		w.pos(f.Position)
+		w.varint(int64(f.Amount))
+		w.varint(int64(f.OnMove))
+	}
+	w.count(len(state.VisitedCells), state.VisitedCells == nil)
+	for _, p := range state.VisitedCells {
+		w.pos(p)
+	}
+	w.varint(int64(state.VisitedCellCount))
+
+	if state.CurrentMoves == nil {
+		w.uvarint(currentMovesNil)
+	} else if isHistoryTail(state.MoveHistory, state.CurrentMoves) {
+		w.uvarint(currentMovesHistoryTail)
+		w.uvarint(uint64(len(state.CurrentMoves)))
+	} else {
+		w.uvarint(currentMovesList)
+		w.history(state.CurrentMoves)
+	}
+	w.varint(int64(state.CurrentMovesCount))
+	w.str(string(state.LastMoveOutcome))
+	w.pos(state.PrimaryHome)
+	w.count(len(state.Hazards), state.Hazards == nil)
+	for _, h := range state.Hazards {
+		w.pos(h.Position)
+		w.uvarint(uint64(h.Step))
+	}
+	w.str(state.ScoringMode)
+	w.varint(int64(state.ChargeCount))
+	w.varint(int64(state.ChargePenalty))
+	w.varint(int64(state.ChargeHold))
+	w.count(len(state.ParkExpiry), state.ParkExpiry == nil)
+	for _, p := range state.ParkExpiry {
+		w.str(p.ParkID)
+		w.pos(p.Position)
+		w.varint(int64(p.MovesLeft))
+		w.flag(p.Expired)
+		w.varint(int64(p.ExpiredOnMove))
+	}
+	w.count(len(state.CarriedParks), state.CarriedParks == nil)
+	for _, id := range state.CarriedParks {
+		w.str(id)
+	}
+	w.varint(int64(state.Lives))
+	w.flag(state.LastCheckpoint != nil)
+	if state.LastCheckpoint != nil {
+		w.pos(*state.LastCheckpoint)
+	}
+	w.count(len(state.CollectedKeys), state.CollectedKeys == nil)
+	for _, id := range state.CollectedKeys {
+		w.str(id)
+	}
+	w.str(data.Label)
+
+	return w.buf, nil
+}
+
+// DecodeCompact decodes a session written by EncodeCompact. The returned
+// data's GameState is an *engine.GameState.
+func DecodeCompact(b []byte) (*PersistedSessionData, error) {
+	if !bytes.HasPrefix(b, compactMagic) {
+		return nil, ErrNotCompact
+	}
+	r := &compactReader{buf: b[len(compactMagic):]}
+	version := r.uvarint()
+	if r.err == nil && (version < 1 || version > CompactVersion) {
+		return nil, fmt.Errorf("%w: %d", ErrCompactVersion, version)
+	}
+
+	data := &PersistedSessionData{}
+	data.ID = r.str()
+	data.ConfigName = r.str()
+	data.CreatedAt = r.stamp()
+	data.LastAccessedAt = r.stamp()
+	data.ConfigChecksum = r.str()
+	data.TTLSeconds = r.num()
+
+	state := &engine.GameState{}
+	r.grid(state)
+	state.PlayerPos = r.pos()
+	state.Battery = r.num()
+	state.MaxBattery = r.num()
+	state.Score = r.num()
+	state.Message = r.str()
+	state.GameOver = r.flag()
+	state.Victory = r.flag()
+	state.GameOverReason = engine.GameOverReason(r.str())
+	if r.flag() {
+		state.Result = &engine.GameResult{
+			Outcome:        engine.GameOutcome(r.str()),
+			Reason:         engine.ResultReason(r.str()),
+			MovesUsed:      r.num(),
+			ElapsedMoves:   r.num(),
+			ParksCollected: r.num(),
+			TotalParks:     r.num(),
+			FinalScore:     r.num(),
+		}
+		if version >= 4 {
+			state.Result.ChargeCount = r.num()
+			state.Result.ChargePenalty = r.num()
+		}
+		if version >= 8 {
+			state.Result.BatteryBonus = r.num()
+		}
+	}
+	state.ConfigName = r.str()
+	state.MoveHistory = r.history()
+	state.TotalMoves = r.num()
+	state.RandomSeed = r.varint()
+
+	if n, ok := r.count(); ok {
+		state.Chargers = make([]engine.ChargerStatus, n)
+		for i := range state.Chargers {
+			state.Chargers[i] = engine.ChargerStatus{
+				Position:     r.pos(),
+				UsesLeft:     r.num(),
+				CooldownLeft: r.num(),
+				Depleted:     r.flag(),
+				UsedOnMove:   r.num(),
+			}
+		}
+	}
+	if n, ok := r.count(); ok {
+		state.ConsumedFuel = make([]engine.FuelPickup, n)
+		for i := range state.ConsumedFuel {
+			state.ConsumedFuel[i] = engine.FuelPickup{Position: r.pos(), Amount: r.num(), OnMove: r.num()}
+		}
+	}
+	if n, ok := r.count(); ok {
+		state.VisitedCells = make([]engine.Position, n)
+		for i := range state.VisitedCells {
+			state.VisitedCells[i] = r.pos()
+		}
+	}
+	state.VisitedCellCount = r.num()
+
+	switch mode := r.uvarint(); mode {
+	case currentMovesNil:
+	case currentMovesHistoryTail:
+		n := r.uvarint()
+		if n > uint64(len(state.MoveHistory)) {
+			r.fail(fmt.Errorf("current moves tail of %d exceeds history of %d", n, len(state.MoveHistory)))
+			break
+		}
+		state.CurrentMoves = append([]engine.MoveHistoryEntry{}, state.MoveHistory[len(state.MoveHistory)-int(n):]...)
+	case currentMovesList:
+		state.CurrentMoves = r.history()
+	default:
+		r.fail(fmt.Errorf("unknown current moves encoding %d", mode))
+	}
+	state.CurrentMovesCount = r.num()
+	state.LastMoveOutcome = engine.MoveOutcome(r.str())
+	// Version 1 states get their primary home back from the config on load
+	if version >= 2 {
+		state.PrimaryHome = r.pos()
+	}
+	// Earlier versions restart the hazards' patrols on load
+	if version >= 3 {
+		if n, ok := r.count(); ok {
+			state.Hazards = make([]engine.HazardStatus, n)
+			for i := range state.Hazards {
+				state.Hazards[i] = engine.HazardStatus{Position: r.pos(), Step: int(r.uvarint())}
+			}
+		}
+	}
+	if version >= 4 {
+		state.ScoringMode = r.str()
+		state.ChargeCount = r.num()
+		state.ChargePenalty = r.num()
+	}
+	if version >= 5 {
+		state.ChargeHold = r.num()
+	}
+	// Earlier versions start the park deadlines afresh on load
+	if version >= 6 {
+		if n, ok := r.count(); ok {
+			state.ParkExpiry = make([]engine.ParkExpiryStatus, n)
+			for i := range state.ParkExpiry {
+				state.ParkExpiry[i] = engine.ParkExpiryStatus{
+					ParkID:        r.str(),
+					Position:      r.pos(),
+					MovesLeft:     r.num(),
+					Expired:       r.flag(),
+					ExpiredOnMove: r.num(),
+				}
+			}
+		}
+	}
+	if version >= 7 {
+		if n, ok := r.count(); ok {
+			state.CarriedParks = make([]string, n)
+			for i := range state.CarriedParks {
+				state.CarriedParks[i] = r.str()
+			}
+		}
+	}
+	if version >= 9 {
+		state.Lives = r.num()
+		if r.flag() {
+			checkpoint := r.pos()
+			state.LastCheckpoint = &checkpoint
+		}
+	}
+	if version >= 10 {
+		if n, ok := r.count(); ok {
+			state.CollectedKeys = make([]string, n)
+			for i := range state.CollectedKeys {
+				state.CollectedKeys[i] = r.str()
+			}
+		}
+	}
+	if version >= 11 {
+		data.Label = r.str()
+	}
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode compact session: %w", r.err)
+	}
+	if len(r.buf) > 0 {
+		return nil, fmt.Errorf("failed to decode compact session: %d trailing bytes", len(r.buf))
+	}
+	data.GameState = state
+	return data, nil
+}
+
+// isHistoryTail reports whether current is exactly the last entries of history
+func isHistoryTail(history, current []engine.MoveHistoryEntry) bool {
+	if len(current) > len(history) {
+		return false
+	}
+	tail := history[len(history)-len(current):]
+	for i := range current {
+		if current[i] != tail[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compactWriter appends compact values to a buffer
+type compactWriter struct {
+	buf []byte
+}
+
+func (w *compactWriter) uvarint(v uint64) { w.buf = binary.AppendUvarint(w.buf, v) }
+
+func (w *compactWriter) varint(v int64) { w.buf = binary.AppendVarint(w.buf, v) }
+
+func (w *compactWriter) flag(v bool) {
+	if v {
+		w.buf = append(w.buf, 1)
+	} else {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *compactWriter) str(s string) {
+	w.uvarint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *compactWriter) pos(p engine.Position) {
+	w.varint(int64(p.X))
+	w.varint(int64(p.Y))
+}
+
+// stamp writes a time with its zone offset
+func (w *compactWriter) stamp(t time.Time) error {
+	b, err := t.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode time: %w", err)
+	}
+	w.str(string(b))
+	return nil
+}
+
+// count writes a slice length, keeping nil slices apart from empty ones
+func (w *compactWriter) count(n int, isNil bool) {
+	if isNil {
+		w.uvarint(0)
+		return
+	}
+	w.uvarint(uint64(n) + 1)
+}
+
+// grid writes each row as runs of one cell type, then the park IDs with
+// bitsets of the visited cells and the parks in VisitedParks
+func (w *compactWriter) grid(state *engine.GameState) error {
+	w.uvarint(uint64(len(state.Grid)))
+	type marked struct {
+		index int
+		cell  engine.Cell
+	}
+	var cells []marked
+	index := 0
+	for _, row := range state.Grid {
+		w.uvarint(uint64(len(row)))
+		var runs [][2]int // code, length
+		for _, cell := range row {
+			code, ok := cellCodes[cell.Type]
+			if !ok {
+				return fmt.Errorf("cannot encode cell type %q", cell.Type)
+			}
+			if n := len(runs); n > 0 && runs[n-1][0] == int(code) {
+				runs[n-1][1]++
+			} else {
+				runs = append(runs, [2]int{int(code), 1})
+			}
+			if cell.ID != "" || cell.Visited {
+				cells = append(cells, marked{index, cell})
+			}
+			index++
+		}
+		w.uvarint(uint64(len(runs)))
+		for _, run := range runs {
+			w.buf = append(w.buf, byte(run[0]))
+			w.uvarint(uint64(run[1]))
+		}
+	}
+
+	w.uvarint(uint64(len(cells)))
+	last := 0
+	for _, m := range cells {
+		w.uvarint(uint64(m.index - last))
+		last = m.index
+		w.str(m.cell.ID)
+	}
+	visited := make([]byte, (len(cells)+7)/8)
+	inVisitedParks := make([]byte, (len(cells)+7)/8)
+	found := 0
+	for i, m := range cells {
+		if m.cell.Visited {
+			visited[i/8] |= 1 << (i % 8)
+		}
+		if m.cell.ID != "" && state.VisitedParks[m.cell.ID] {
+			inVisitedParks[i/8] |= 1 << (i % 8)
+			found++
+		}
+	}
+	for id, v := range state.VisitedParks {
+		if !v {
+			return fmt.Errorf("cannot encode unvisited park %q in visited parks", id)
+		}
+	}
+	if found != len(state.VisitedParks) {
+		return fmt.Errorf("cannot encode visited parks that are not on the grid")
+	}
+	w.buf = append(w.buf, visited...)
+	w.buf = append(w.buf, inVisitedParks...)
+	w.flag(state.VisitedParks != nil)
+	return nil
+}
+
+// history writes move history entries, with timestamps and move numbers as
+// deltas from the previous entry
+func (w *compactWriter) history(entries []engine.MoveHistoryEntry) {
+	w.count(len(entries), entries == nil)
+	var prevTime int64
+	prevNumber := 0
+	for _, e := range entries {
+		var flags byte
+		if e.Success {
+			flags |= entrySuccess
+		}
+		if e.Autoplay {
+			flags |= entryAutoplay
+		}
+		if e.Intent != "" {
+			flags |= entryIntent
+		}
+		if e.RandomEvent != "" {
+			flags |= entryRandomEvent
+		}
+		if e.RevisitPenalty != 0 {
+			flags |= entryRevisitPenalty
+		}
+		if e.HazardHit {
+			flags |= entryHazardHit
+		}
+		if e.WouldStrand {
+			flags |= entryWouldStrand
+		}
+		if e.ParkCollected != "" {
+			flags |= entryParkCollected
+		}
+		w.buf = append(w.buf, flags)
+		w.str(e.Action)
+		w.pos(e.FromPosition)
+		w.pos(e.ToPosition)
+		w.varint(int64(e.Battery))
+		w.varint(int64(e.BatteryDelta))
+		w.varint(int64(e.Cost))
+		w.varint(e.Timestamp - prevTime)
+		w.varint(int64(e.MoveNumber - prevNumber))
+		prevTime, prevNumber = e.Timestamp, e.MoveNumber
+		if e.Intent != "" {
+			w.str(e.Intent)
+		}
+		if e.RandomEvent != "" {
+			w.str(e.RandomEvent)
+		}
+		if e.RevisitPenalty != 0 {
+			w.varint(int64(e.RevisitPenalty))
+		}
+		if e.ParkCollected != "" {
+			w.str(e.ParkCollected)
+		}
+	}
+}
+
+// compactReader reads compact values; the first error sticks and every
+// later read returns a zero value
+type compactReader struct {
+	buf []byte
+	err error
+}
+
+func (r *compactReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *compactReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.fail(fmt.Errorf("bad varint"))
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *compactReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.fail(fmt.Errorf("bad varint"))
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *compactReader) num() int { return int(r.varint()) }
+
+func (r *compactReader) take(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.buf)) {
+		r.fail(fmt.Errorf("unexpected end of data"))
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *compactReader) raw() byte {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *compactReader) flag() bool { return r.raw() == 1 }
+
+func (r *compactReader) str() string { return string(r.take(r.uvarint())) }
+
+func (r *compactReader) pos() engine.Position {
+	return engine.Position{X: r.num(), Y: r.num()}
+}
+
+func (r *compactReader) stamp() time.Time {
+	var t time.Time
+	if b := r.take(r.uvarint()); b != nil {
+		if err := t.UnmarshalBinary(b); err != nil {
+			r.fail(fmt.Errorf("bad time: %w", err))
+		}
+	}
+	return t
+}
+
+// count reads a slice length written by compactWriter.count. Every element
+// takes at least a byte, so longer counts are rejected before allocating.
+func (r *compactReader) count() (int, bool) {
+	n := r.uvarint()
+	if n == 0 {
+		return 0, false
+	}
+	if n-1 > uint64(len(r.buf)) {
+		r.fail(fmt.Errorf("count %d exceeds remaining data", n-1))
+		return 0, false
+	}
+	return int(n - 1), true
+}
+
+// grid reads the grid, park IDs and visited parks written by compactWriter.grid
+func (r *compactReader) grid(state *engine.GameState) {
+	types := make(map[byte]engine.CellType, len(cellCodes))
+	for cellType, code := range cellCodes {
+		types[code] = cellType
+	}
+
+	height := r.uvarint()
+	if height > uint64(len(r.buf)) {
+		r.fail(fmt.Errorf("grid height %d exceeds remaining data", height))
+		return
+	}
+	state.Grid = make([][]engine.Cell, height)
+	var flat []*engine.Cell
+	for y := range state.Grid {
+		width := r.uvarint()
+		runs := r.uvarint()
+		if r.err != nil || runs > width || runs > uint64(len(r.buf)) {
+			r.fail(fmt.Errorf("bad grid row %d", y))
+			return
+		}
+		row := make([]engine.Cell, 0, width)
+		for range runs {
+			cellType, ok := types[r.raw()]
+			length := r.uvarint()
+			if !ok || length == 0 || uint64(len(row))+length > width {
+				r.fail(fmt.Errorf("bad run in grid row %d", y))
+				return
+			}
+			for range length {
+				row = append(row, engine.Cell{Type: cellType})
+			}
+		}
+		if uint64(len(row)) != width {
+			r.fail(fmt.Errorf("grid row %d has %d of %d cells", y, len(row), width))
+			return
+		}
+		state.Grid[y] = row
+		for x := range row {
+			flat = append(flat, &row[x])
+		}
+	}
+
+	n := r.uvarint()
+	if n > uint64(len(flat)) {
+		r.fail(fmt.Errorf("%d marked cells on a grid of %d", n, len(flat)))
+		return
+	}
+	cells := make([]*engine.Cell, n)
+	index := uint64(0)
+	for i := range cells {
+		delta := r.uvarint()
+		index += delta
+		if r.err != nil || index >= uint64(len(flat)) || (i > 0 && delta == 0) {
+			r.fail(fmt.Errorf("bad marked cell index %d", index))
+			return
+		}
+		cells[i] = flat[index]
+		cells[i].ID = r.str()
+	}
+	visited := r.take((n + 7) / 8)
+	inVisitedParks := r.take((n + 7) / 8)
+	if r.flag() {
+		state.VisitedParks = make(map[string]bool)
+	}
+	if r.err != nil {
+		return
+	}
+	for i, cell := range cells {
+		cell.Visited = visited[i/8]&(1<<(i%8)) != 0
+		if inVisitedParks[i/8]&(1<<(i%8)) != 0 {
+			if state.VisitedParks == nil {
+				r.fail(fmt.Errorf("visited park %q without visited parks", cell.ID))
+				return
+			}
+			state.VisitedParks[cell.ID] = true
+		}
+	}
+}
+
+// history reads entries written by compactWriter.history
+func (r *compactReader) history() []engine.MoveHistoryEntry {
+	n, ok := r.count()
+	if !ok {
+		return nil
+	}
+	entries := make([]engine.MoveHistoryEntry, n)
+	var prevTime int64
+	prevNumber := 0
+	for i := range entries {
+		flags := r.raw()
+		e := engine.MoveHistoryEntry{
+			Success:      flags&entrySuccess != 0,
+			Autoplay:     flags&entryAutoplay != 0,
+			HazardHit:    flags&entryHazardHit != 0,
+			WouldStrand:  flags&entryWouldStrand != 0,
+			Action:       r.str(),
+			FromPosition: r.pos(),
+			ToPosition:   r.pos(),
+			Battery:      r.num(),
+			BatteryDelta: r.num(),
+			Cost:         r.num(),
+		}
+		e.Timestamp = prevTime + r.varint()
+		e.MoveNumber = prevNumber + r.num()
+		prevTime, prevNumber = e.Timestamp, e.MoveNumber
+		if flags&entryIntent != 0 {
+			e.Intent = r.str()
+		}
+		if flags&entryRandomEvent != 0 {
+			e.RandomEvent = r.str()
+		}
+		if flags&entryRevisitPenalty != 0 {
+			e.RevisitPenalty = r.num()
+		}
+		if flags&entryParkCollected != 0 {
+			e.ParkCollected = r.str()
+		}
+		entries[i] = e
+	}
+	return entries
+}
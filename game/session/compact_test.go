@@ -0,0 +1,264 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	gameconfig "github.com/wricardo/tesla-road-trip-game/game/config"
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// playedCompactState plays a game touching every part of the state the
+// compact encoding stores: parks, a limited charger, fuel, revisits, random
+// events, intents, autoplay, a wait, a hazard hit, a blocked move and a reset
+func playedCompactState(t *testing.T) *engine.GameState {
+	t.Helper()
+	config := createTestConfig()
+	config.Layout = []string{
+		"BBBBB",
+		"BRHPB",
+		"BRFSB",
+		"BPPPB",
+		"BBBBB",
+	}
+	config.Legend["F"] = "fuel"
+	config.FuelAmount = 3
+	config.Chargers = []engine.ChargerLimit{{X: 3, Y: 2, Uses: 2, Cooldown: 1}}
+	config.RevisitPenalty = 1
+	config.WaitCost = 1
+	config.Hazards = []engine.Hazard{{Path: []engine.Position{{X: 1, Y: 1}, {X: 1, Y: 2}}}}
+	config.HazardPolicy = engine.HazardPolicyPenalty
+	config.HazardPenalty = 1
+	config.RandomEvents = &engine.RandomEventsConfig{DrainChance: 0.5, DrainAmount: 1, Seed: 7}
+	config.ParkExpiry = map[string]int{"park_3": 2}
+	config.ResetPreservesScore = true
+
+	e, err := engine.NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	e.Move("right")
+	e.Reset()
+	e.MoveWithMeta("right", engine.MoveMeta{Intent: "grab the nearest park"})
+	e.Move("down")
+	e.Move("left")
+	e.Move(engine.ActionWait)
+	e.Move("up")
+	e.MoveWithMeta("left", engine.MoveMeta{Autoplay: true})
+	e.Move("up")
+
+	state := e.GetState()
+	if len(state.VisitedParks) != 1 || len(state.ConsumedFuel) != 1 || state.Chargers[0].UsedOnMove == 0 {
+		t.Fatalf("Expected a collected park, used fuel and a used charger, got %+v", state)
+	}
+	if len(state.CarriedParks) != 1 || state.CarriedParks[0] != "park_0" {
+		t.Fatalf("Expected park_0 to be carried over the reset, got %v", state.CarriedParks)
+	}
+	if len(state.ParkExpiry) != 1 || !state.ParkExpiry[0].Expired {
+		t.Fatalf("Expected park_3 to expire, got %+v", state.ParkExpiry)
+	}
+	if len(state.Hazards) != 1 || !state.CurrentMoves[5].HazardHit {
+		t.Fatalf("Expected the hazard to step onto the player, got %+v", state.CurrentMoves)
+	}
+	return state
+}
+
+func TestCompact_RoundTrip(t *testing.T) {
+	state := playedCompactState(t)
+	created := time.Date(2026, 10, 15, 9, 30, 0, 123, time.UTC)
+	data := &PersistedSessionData{
+		ID:             "cmp1",
+		ConfigName:     "test",
+		CreatedAt:      created,
+		LastAccessedAt: created.Add(time.Minute).In(time.FixedZone("UTC-3", -3*60*60)),
+		GameState:      state,
+		ConfigChecksum: "abc123",
+		TTLSeconds:     600,
+		Label:          "Sunday drive",
+	}
+
+	encoded, err := EncodeCompact(data)
+	if err != nil {
+		t.Fatalf("EncodeCompact failed: %v", err)
+	}
+	decoded, err := DecodeCompact(encoded)
+	if err != nil {
+		t.Fatalf("DecodeCompact failed: %v", err)
+	}
+
+	if decoded.ID != data.ID || decoded.ConfigName != data.ConfigName || decoded.ConfigChecksum != data.ConfigChecksum ||
+		decoded.TTLSeconds != data.TTLSeconds || decoded.Label != data.Label {
+		t.Errorf("Expected session fields %+v, got %+v", data, decoded)
+	}
+	if !decoded.CreatedAt.Equal(data.CreatedAt) || !decoded.LastAccessedAt.Equal(data.LastAccessedAt) {
+		t.Errorf("Expected times %v and %v, got %v and %v",
+			data.CreatedAt, data.LastAccessedAt, decoded.CreatedAt, decoded.LastAccessedAt)
+	}
+	if _, offset := decoded.LastAccessedAt.Zone(); offset != -3*60*60 {
+		t.Errorf("Expected the zone offset to survive, got %d", offset)
+	}
+	if got := decoded.GameState.(*engine.GameState); !reflect.DeepEqual(got, state) {
+		want, _ := json.Marshal(state)
+		have, _ := json.Marshal(got)
+		t.Errorf("Decoded state differs\nwant %s\ngot  %s", want, have)
+	}
+}
+
+func TestCompact_RoundTripEdgeCases(t *testing.T) {
+	cases := map[string]func(*engine.GameState){
+		"nil slices": func(s *engine.GameState) {
+			s.Chargers, s.ConsumedFuel, s.VisitedCells = nil, nil, nil
+			s.MoveHistory, s.CurrentMoves = nil, nil
+		},
+		"current moves apart from history": func(s *engine.GameState) {
+			s.CurrentMoves = []engine.MoveHistoryEntry{{Action: "down", Success: true, MoveNumber: 9, Timestamp: 5}}
+		},
+		"turned down by the battery reserve": func(s *engine.GameState) {
+			s.CurrentMoves[len(s.CurrentMoves)-1].WouldStrand = true
+		},
+		"collected a park": func(s *engine.GameState) {
+			s.CurrentMoves[len(s.CurrentMoves)-1].ParkCollected = "park_0"
+		},
+		"finished game": func(s *engine.GameState) {
+			s.GameOver = true
+			s.GameOverReason = engine.GameOverStranded
+			s.ScoringMode, s.ChargePenalty = engine.ScoringEco, 2
+			s.Result = &engine.GameResult{Outcome: "loss", Reason: "stranded", MovesUsed: 3, ElapsedMoves: 4, TotalParks: 4, FinalScore: 8,
+				ChargeCount: 2, ChargePenalty: 2}
+		},
+		"respawn point": func(s *engine.GameState) {
+			s.Lives = 2
+			s.LastCheckpoint = &engine.Position{X: 1, Y: 2}
+		},
+		"keys and doors": func(s *engine.GameState) {
+			s.Grid[4][1] = engine.Cell{Type: engine.Key, ID: "gate", Visited: true}
+			s.Grid[4][2] = engine.Cell{Type: engine.Door, ID: "gate"}
+			s.CollectedKeys = []string{"gate", "vault"}
+		},
+		"won on energy scoring": func(s *engine.GameState) {
+			s.GameOver, s.Victory = true, true
+			s.GameOverReason = engine.GameOverVictory
+			s.ScoringMode = engine.ScoringEnergy
+			s.Result = &engine.GameResult{Outcome: engine.OutcomeVictory, Reason: engine.ResultAllParks, MovesUsed: 7, ElapsedMoves: 7,
+				ParksCollected: 4, TotalParks: 4, FinalScore: 13, ChargeCount: 1, BatteryBonus: 9}
+		},
+		"no visited parks map": func(s *engine.GameState) {
+			s.VisitedParks = nil
+			for y := range s.Grid {
+				for x := range s.Grid[y] {
+					s.Grid[y][x].Visited = false
+				}
+			}
+		},
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			state := playedCompactState(t)
+			mutate(state)
+			encoded, err := EncodeCompact(&PersistedSessionData{ID: "edge", GameState: state})
+			if err != nil {
+				t.Fatalf("EncodeCompact failed: %v", err)
+			}
+			decoded, err := DecodeCompact(encoded)
+			if err != nil {
+				t.Fatalf("DecodeCompact failed: %v", err)
+			}
+			if got := decoded.GameState.(*engine.GameState); !reflect.DeepEqual(got, state) {
+				t.Errorf("Decoded state differs: want %+v, got %+v", state, got)
+			}
+		})
+	}
+}
+
+func TestCompact_DecodesOlderVersions(t *testing.T) {
+	state := playedCompactState(t)
+	state.Hazards = nil
+	state.ParkExpiry = nil
+	state.CarriedParks = nil
+	encoded, err := EncodeCompact(&PersistedSessionData{ID: "old1", GameState: state})
+	if err != nil {
+		t.Fatalf("EncodeCompact failed: %v", err)
+	}
+
+	// Each older version ends earlier, every field here taking one byte:
+	// version 10 before the empty label, version 9 also before the nil collected keys, versions 7 and 8 also before
+	// the lives and the missing checkpoint, version 6 also before the nil
+	// carried parks, version 5 also before the nil park expiry, version 4
+	// also before the charge hold, version 3 also before the scoring mode,
+	// charge count and penalty, version 2 also before the nil hazards and
+	// version 1 before the primary home too
+	for version, cut := range map[byte]int{1: 13, 2: 11, 3: 10, 4: 7, 5: 6, 6: 5, 7: 4, 8: 4, 9: 2, 10: 1} {
+		old := append([]byte(nil), encoded[:len(encoded)-cut]...)
+		old[len(compactMagic)] = version
+		decoded, err := DecodeCompact(old)
+		if err != nil {
+			t.Fatalf("DecodeCompact failed for version %d: %v", version, err)
+		}
+		got := decoded.GameState.(*engine.GameState)
+		if (got.PrimaryHome == state.PrimaryHome) != (version >= 2) || got.Battery != state.Battery {
+			t.Errorf("Version %d: expected the state with primary home only from version 2, got %+v", version, got)
+		}
+	}
+}
+
+func TestCompact_SmallerThanJSON(t *testing.T) {
+	gameConfig, err := engine.LoadGameConfig("../../configs/classic.json")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+	e, err := engine.NewEngine(gameConfig)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	for _, move := range []string{"left", "left", "up", "right", "down", "down"} {
+		e.Move(move)
+	}
+	data := &PersistedSessionData{ID: "big1", ConfigName: "classic", GameState: e.GetState(),
+		ConfigChecksum: gameconfig.Checksum(gameConfig)}
+
+	encoded, err := EncodeCompact(data)
+	if err != nil {
+		t.Fatalf("EncodeCompact failed: %v", err)
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Failed to marshal JSON: %v", err)
+	}
+	if len(encoded)*4 > len(jsonData) {
+		t.Errorf("Expected the compact encoding to be under a quarter of JSON, got %d vs %d bytes", len(encoded), len(jsonData))
+	}
+}
+
+func TestCompact_RejectsBadData(t *testing.T) {
+	encoded, err := EncodeCompact(&PersistedSessionData{ID: "bad1", GameState: playedCompactState(t)})
+	if err != nil {
+		t.Fatalf("EncodeCompact failed: %v", err)
+	}
+
+	if _, err := DecodeCompact([]byte(`{"id":"bad1"}`)); !errors.Is(err, ErrNotCompact) {
+		t.Errorf("Expected ErrNotCompact for JSON, got %v", err)
+	}
+
+	future := append([]byte(nil), encoded...)
+	future[len(compactMagic)] = CompactVersion + 1
+	if _, err := DecodeCompact(future); !errors.Is(err, ErrCompactVersion) {
+		t.Errorf("Expected ErrCompactVersion for a newer file, got %v", err)
+	}
+
+	// Every truncation fails cleanly rather than panicking
+	for n := len(compactMagic); n < len(encoded); n++ {
+		if _, err := DecodeCompact(encoded[:n]); err == nil {
+			t.Fatalf("Expected an error for data truncated to %d of %d bytes", n, len(encoded))
+		}
+	}
+	if _, err := DecodeCompact(append(encoded, 0)); err == nil {
+		t.Error("Expected an error for trailing data")
+	}
+
+	if _, err := EncodeCompact(&PersistedSessionData{ID: "bad2"}); err == nil {
+		t.Error("Expected an error for a session without a game state")
+	}
+}
@@ -7,30 +7,74 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/wricardo/tesla-road-trip-game/game/config"
 	"github.com/wricardo/tesla-road-trip-game/game/engine"
 	"github.com/wricardo/tesla-road-trip-game/game/service"
 )
 
+// Session file encodings
+const (
+	EncodingJSON    = "json"    // Indented JSON in <id>.json, easy to inspect; the default
+	EncodingCompact = "compact" // EncodeCompact's binary format in <id>.bin
+)
+
+// fileExtensions maps each encoding to the extension of its session files
+var fileExtensions = map[string]string{
+	EncodingJSON:    ".json",
+	EncodingCompact: ".bin",
+}
+
 // FilePersistence implements SessionPersistence using file system storage
 type FilePersistence struct {
 	sessionsDir   string
 	configManager service.ConfigManager
+	encoding      string
+	eventLog      bool
+}
+
+// FileOption configures a FilePersistence
+type FileOption func(*FilePersistence)
+
+// WithEncoding sets the encoding sessions are saved in. Sessions in either
+// encoding are loaded, so existing files keep working after a switch.
+func WithEncoding(encoding string) FileOption {
+	return func(fp *FilePersistence) {
+		fp.encoding = encoding
+	}
+}
+
+// WithPersistedEventLog saves each session's event log with it, so it
+// survives restarts. Only JSON files hold the log; compact files leave it out.
+func WithPersistedEventLog() FileOption {
+	return func(fp *FilePersistence) {
+		fp.eventLog = true
+	}
 }
 
 // NewFilePersistence creates a new file-based session persistence layer
-func NewFilePersistence(sessionsDir string, configManager service.ConfigManager) (*FilePersistence, error) {
+func NewFilePersistence(sessionsDir string, configManager service.ConfigManager, opts ...FileOption) (*FilePersistence, error) {
+	fp := &FilePersistence{
+		sessionsDir:   sessionsDir,
+		configManager: configManager,
+		encoding:      EncodingJSON,
+	}
+	for _, opt := range opts {
+		opt(fp)
+	}
+	if _, ok := fileExtensions[fp.encoding]; !ok {
+		return nil, fmt.Errorf("unknown session encoding %q, use %s or %s", fp.encoding, EncodingJSON, EncodingCompact)
+	}
+
 	// Create sessions directory if it doesn't exist
 	if err := os.MkdirAll(sessionsDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create sessions directory: %w", err)
 	}
 
-	return &FilePersistence{
-		sessionsDir:   sessionsDir,
-		configManager: configManager,
-	}, nil
+	return fp, nil
 }
 
-// Save persists a session to a JSON file
+// Save persists a session to a file in the configured encoding, replacing a
+// file of the same session in the other encoding
 func (fp *FilePersistence) Save(session *service.Session) error {
 	if session == nil {
 		return fmt.Errorf("session cannot be nil")
@@ -42,6 +86,11 @@ func (fp *FilePersistence) Save(session *service.Session) error {
 		return fmt.Errorf("failed to get config ID: %w", err)
 	}
 
+	checksum := session.ConfigChecksum
+	if checksum == "" {
+		checksum = config.Checksum(session.Config)
+	}
+
 	// Create persisted data structure
 	data := PersistedSessionData{
 		ID:             session.ID,
@@ -49,41 +98,65 @@ func (fp *FilePersistence) Save(session *service.Session) error {
 		CreatedAt:      session.CreatedAt,
 		LastAccessedAt: session.LastAccessedAt,
 		GameState:      session.Engine.GetState(),
+		ConfigChecksum: checksum,
+		TTLSeconds:     session.TTLSeconds,
+		Label:          session.Label,
+	}
+	if fp.eventLog {
+		data.EventLog = session.EventLog
 	}
 
-	// Marshal to JSON with indentation for readability
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+	var fileData []byte
+	if fp.encoding == EncodingCompact {
+		fileData, err = EncodeCompact(&data)
+	} else {
+		// Marshal to JSON with indentation for readability
+		fileData, err = json.MarshalIndent(data, "", "  ")
+	}
 	if err != nil {
 		return fmt.Errorf("failed to marshal session data: %w", err)
 	}
 
 	// Write to file
-	filePath := fp.getFilePath(session.ID)
-	if err := os.WriteFile(filePath, jsonData, 0644); err != nil {
+	filePath := fp.getFilePath(session.ID, fp.encoding)
+	if err := os.WriteFile(filePath, fileData, 0644); err != nil {
 		return fmt.Errorf("failed to write session file: %w", err)
 	}
 
+	// Drop a copy left in the other encoding so it can't shadow this one
+	for encoding := range fileExtensions {
+		if encoding == fp.encoding {
+			continue
+		}
+		if err := os.Remove(fp.getFilePath(session.ID, encoding)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old session file: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// Load retrieves a session from a JSON file
+// Load retrieves a session from its file, in either encoding
 func (fp *FilePersistence) Load(id string) (*service.Session, error) {
-	filePath := fp.getFilePath(id)
-
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	filePath, encoding, ok := fp.findFile(id)
+	if !ok {
 		return nil, ErrSessionNotFound
 	}
 
 	// Read file
-	jsonData, err := os.ReadFile(filePath)
+	fileData, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read session file: %w", err)
 	}
 
-	// Unmarshal JSON
 	var data PersistedSessionData
-	if err := json.Unmarshal(jsonData, &data); err != nil {
+	if encoding == EncodingCompact {
+		decoded, err := DecodeCompact(fileData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal session data: %w", err)
+		}
+		data = *decoded
+	} else if err := json.Unmarshal(fileData, &data); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal session data: %w", err)
 	}
 
@@ -99,19 +172,23 @@ func (fp *FilePersistence) Load(id string) (*service.Session, error) {
 		return nil, fmt.Errorf("failed to create game engine: %w", err)
 	}
 
-	// Restore game state
-	gameStateJSON, err := json.Marshal(data.GameState)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal game state: %w", err)
-	}
-
-	var gameState engine.GameState
-	if err := json.Unmarshal(gameStateJSON, &gameState); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal game state: %w", err)
+	// Restore game state; compact files decode straight into one
+	gameState, ok := data.GameState.(*engine.GameState)
+	if !ok {
+		gameStateJSON, err := json.Marshal(data.GameState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal game state: %w", err)
+		}
+		gameState = &engine.GameState{}
+		if err := json.Unmarshal(gameStateJSON, gameState); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal game state: %w", err)
+		}
+		// Saves from before battery deltas were recorded
+		gameState.FillMissingBatteryDeltas()
 	}
 
 	// Set the restored state to the engine
-	if err := gameEngine.SetState(&gameState); err != nil {
+	if err := gameEngine.SetState(gameState); err != nil {
 		return nil, fmt.Errorf("failed to set game state: %w", err)
 	}
 
@@ -122,23 +199,27 @@ func (fp *FilePersistence) Load(id string) (*service.Session, error) {
 		Config:         gameConfig,
 		CreatedAt:      data.CreatedAt,
 		LastAccessedAt: data.LastAccessedAt,
+		ConfigChecksum: data.ConfigChecksum,
+		TTLSeconds:     data.TTLSeconds,
+		Label:          data.Label,
+		EventLog:       data.EventLog,
 	}
 
 	return session, nil
 }
 
-// Delete removes a session file
+// Delete removes a session's files
 func (fp *FilePersistence) Delete(id string) error {
-	filePath := fp.getFilePath(id)
-
 	// Check if file exists
 	if !fp.Exists(id) {
 		return ErrSessionNotFound
 	}
 
-	// Remove file
-	if err := os.Remove(filePath); err != nil {
-		return fmt.Errorf("failed to remove session file: %w", err)
+	// Remove the file in every encoding
+	for encoding := range fileExtensions {
+		if err := os.Remove(fp.getFilePath(id, encoding)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove session file: %w", err)
+		}
 	}
 
 	return nil
@@ -152,32 +233,58 @@ func (fp *FilePersistence) ListAll() ([]string, error) {
 	}
 
 	var sessionIDs []string
+	seen := make(map[string]bool)
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
 		}
 
 		name := entry.Name()
-		if strings.HasSuffix(name, ".json") {
-			// Remove .json extension to get session ID
-			sessionID := strings.TrimSuffix(name, ".json")
-			sessionIDs = append(sessionIDs, sessionID)
+		for _, ext := range fileExtensions {
+			// Remove the extension to get session ID
+			if sessionID, ok := strings.CutSuffix(name, ext); ok && !seen[sessionID] {
+				seen[sessionID] = true
+				sessionIDs = append(sessionIDs, sessionID)
+			}
 		}
 	}
 
 	return sessionIDs, nil
 }
 
-// Exists checks if a session file exists
+// Exists checks if a session file exists in either encoding
 func (fp *FilePersistence) Exists(id string) bool {
-	filePath := fp.getFilePath(id)
-	_, err := os.Stat(filePath)
-	return err == nil
+	_, _, ok := fp.findFile(id)
+	return ok
+}
+
+// Ping checks that the sessions directory is still there
+func (fp *FilePersistence) Ping() error {
+	info, err := os.Stat(fp.sessionsDir)
+	if err != nil {
+		return fmt.Errorf("sessions directory unavailable: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("sessions path %s is not a directory", fp.sessionsDir)
+	}
+	return nil
+}
+
+// getFilePath returns the full file path for a session ID in an encoding
+func (fp *FilePersistence) getFilePath(id, encoding string) string {
+	return filepath.Join(fp.sessionsDir, id+fileExtensions[encoding])
 }
 
-// getFilePath returns the full file path for a session ID
-func (fp *FilePersistence) getFilePath(id string) string {
-	return filepath.Join(fp.sessionsDir, fmt.Sprintf("%s.json", id))
+// findFile returns the path and encoding of a session's file, trying the
+// configured encoding first
+func (fp *FilePersistence) findFile(id string) (string, string, bool) {
+	for _, encoding := range []string{fp.encoding, EncodingJSON, EncodingCompact} {
+		path := fp.getFilePath(id, encoding)
+		if _, err := os.Stat(path); err == nil {
+			return path, encoding, true
+		}
+	}
+	return "", "", false
 }
 
 // getConfigIDFromName returns the config ID (filename without extension) from display name
@@ -1,8 +1,10 @@
 package session
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -102,6 +104,49 @@ func TestFilePersistence(t *testing.T) {
 		if len(loadedSession.Engine.GetMoveHistory()) != len(session.Engine.GetMoveHistory()) {
 			t.Errorf("Move history not persisted correctly")
 		}
+		saved, loaded := session.Engine.GetLastMove(), loadedSession.Engine.GetLastMove()
+		if loaded.BatteryDelta != saved.BatteryDelta || loaded.Cost != saved.Cost {
+			t.Errorf("Expected delta %d cost %d after reload, got delta %d cost %d",
+				saved.BatteryDelta, saved.Cost, loaded.BatteryDelta, loaded.Cost)
+		}
+	})
+
+	t.Run("Load Save Without Battery Deltas", func(t *testing.T) {
+		if err := persistence.Save(session); err != nil {
+			t.Fatalf("Failed to save session: %v", err)
+		}
+
+		// Strip the fields to mimic a save from an older version
+		path := filepath.Join(tempDir, "test1.json")
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read session file: %v", err)
+		}
+		var data map[string]any
+		if err := json.Unmarshal(raw, &data); err != nil {
+			t.Fatalf("Failed to parse session file: %v", err)
+		}
+		state := data["game_state"].(map[string]any)
+		for _, key := range []string{"move_history", "current_moves"} {
+			entries, _ := state[key].([]any)
+			for _, e := range entries {
+				delete(e.(map[string]any), "battery_delta")
+				delete(e.(map[string]any), "cost")
+			}
+		}
+		raw, _ = json.Marshal(data)
+		if err := os.WriteFile(path, raw, 0644); err != nil {
+			t.Fatalf("Failed to write session file: %v", err)
+		}
+
+		loaded, err := persistence.Load("test1")
+		if err != nil {
+			t.Fatalf("Failed to load legacy session: %v", err)
+		}
+		last := loaded.Engine.GetLastMove()
+		if last == nil || last.Cost != 1 || last.BatteryDelta != -1 {
+			t.Errorf("Expected legacy move to default to cost 1 and delta -1, got %+v", last)
+		}
 	})
 
 	t.Run("List All Sessions", func(t *testing.T) {
@@ -245,3 +290,137 @@ func TestFilePersistenceFileStructure(t *testing.T) {
 func containsString(str, substr string) bool {
 	return strings.Contains(str, substr)
 }
+
+func TestFilePersistence_CompactEncoding(t *testing.T) {
+	tempDir := t.TempDir()
+	configManager, err := config.NewManager("../../configs")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	if _, err := NewFilePersistence(tempDir, configManager, WithEncoding("xml")); err == nil {
+		t.Error("Expected an error for an unknown encoding")
+	}
+	compact, err := NewFilePersistence(tempDir, configManager, WithEncoding(EncodingCompact))
+	if err != nil {
+		t.Fatalf("Failed to create file persistence: %v", err)
+	}
+
+	gameConfig := configManager.GetDefault()
+	gameEngine, err := engine.NewEngine(gameConfig)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	gameEngine.MoveWithMeta("left", engine.MoveMeta{Intent: "scout"})
+	gameEngine.Move("up")
+	session := &service.Session{
+		ID:             "bin1",
+		Engine:         gameEngine,
+		Config:         gameConfig,
+		CreatedAt:      time.Now(),
+		LastAccessedAt: time.Now(),
+		TTLSeconds:     300,
+	}
+
+	if err := compact.Save(session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "bin1.bin")); err != nil {
+		t.Fatalf("Expected a .bin session file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "bin1.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected no .json session file, got %v", err)
+	}
+
+	loaded, err := compact.Load("bin1")
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Engine.GetState(), gameEngine.GetState()) {
+		t.Error("Expected the loaded state to match the saved one")
+	}
+	if loaded.TTLSeconds != 300 || !loaded.CreatedAt.Equal(session.CreatedAt) {
+		t.Errorf("Expected session fields to survive, got %+v", loaded)
+	}
+
+	// JSON persistence still reads the compact file, and saving replaces it
+	jsonPersistence, err := NewFilePersistence(tempDir, configManager)
+	if err != nil {
+		t.Fatalf("Failed to create file persistence: %v", err)
+	}
+	if ids, _ := jsonPersistence.ListAll(); len(ids) != 1 || ids[0] != "bin1" {
+		t.Errorf("Expected the compact session to be listed, got %v", ids)
+	}
+	reloaded, err := jsonPersistence.Load("bin1")
+	if err != nil {
+		t.Fatalf("Failed to load compact session with JSON persistence: %v", err)
+	}
+	if err := jsonPersistence.Save(reloaded); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "bin1.bin")); !os.IsNotExist(err) {
+		t.Errorf("Expected the .bin file to be replaced, got %v", err)
+	}
+
+	if err := compact.Delete("bin1"); err != nil {
+		t.Fatalf("Failed to delete session: %v", err)
+	}
+	if compact.Exists("bin1") {
+		t.Error("Expected the session to be gone")
+	}
+}
+
+func TestFilePersistence_EventLog(t *testing.T) {
+	tempDir := t.TempDir()
+	configManager, err := config.NewManager("../../configs")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	gameConfig := configManager.GetDefault()
+	gameEngine, err := engine.NewEngine(gameConfig)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	events := []service.GameEvent{
+		{Type: "session_created", Message: "Session created with config default", Timestamp: time.Unix(100, 0).UTC()},
+		{Type: "move", Message: "Moved left to (6,7)", Timestamp: time.Unix(101, 0).UTC(), Position: engine.Position{X: 6, Y: 7}},
+	}
+	session := &service.Session{
+		ID:             "log1",
+		Engine:         gameEngine,
+		Config:         gameConfig,
+		CreatedAt:      time.Now(),
+		LastAccessedAt: time.Now(),
+		EventLog:       events,
+	}
+
+	// The log is left out by default
+	plain, err := NewFilePersistence(tempDir, configManager)
+	if err != nil {
+		t.Fatalf("Failed to create file persistence: %v", err)
+	}
+	if err := plain.Save(session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	loaded, err := plain.Load("log1")
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	if loaded.EventLog != nil {
+		t.Errorf("Expected no saved event log by default, got %+v", loaded.EventLog)
+	}
+
+	logged, err := NewFilePersistence(tempDir, configManager, WithPersistedEventLog())
+	if err != nil {
+		t.Fatalf("Failed to create file persistence: %v", err)
+	}
+	if err := logged.Save(session); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+	loaded, err = logged.Load("log1")
+	if err != nil {
+		t.Fatalf("Failed to load session: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.EventLog, events) {
+		t.Errorf("Expected the event log to survive, got %+v", loaded.EventLog)
+	}
+}
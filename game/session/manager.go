@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/wricardo/tesla-road-trip-game/game/config"
 	"github.com/wricardo/tesla-road-trip-game/game/engine"
 	"github.com/wricardo/tesla-road-trip-game/game/service"
 )
@@ -23,26 +24,41 @@ var (
 type Manager struct {
 	sessions    map[string]*service.Session
 	persistence SessionPersistence
+	logger      service.Logger
 	mu          sync.RWMutex
 }
 
-// NewManager creates a new session manager
-func NewManager() *Manager {
-	return &Manager{
-		sessions: make(map[string]*service.Session),
+// ManagerOption configures optional session manager settings
+type ManagerOption func(*Manager)
+
+// WithLogger sends the manager's messages, such as failed saves and loads, to
+// logger instead of service.DefaultLogger
+func WithLogger(logger service.Logger) ManagerOption {
+	return func(m *Manager) {
+		m.logger = logger
 	}
 }
 
+// NewManager creates a new session manager
+func NewManager(opts ...ManagerOption) *Manager {
+	return NewManagerWithPersistence(nil, opts...)
+}
+
 // NewManagerWithPersistence creates a new session manager with persistence
-func NewManagerWithPersistence(persistence SessionPersistence) *Manager {
-	return &Manager{
+func NewManagerWithPersistence(persistence SessionPersistence, opts ...ManagerOption) *Manager {
+	m := &Manager{
 		sessions:    make(map[string]*service.Session),
 		persistence: persistence,
+		logger:      service.DefaultLogger(),
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // Create creates a new session with the given ID and configuration
-func (m *Manager) Create(id string, config *engine.GameConfig) (*service.Session, error) {
+func (m *Manager) Create(id string, gameConfig *engine.GameConfig) (*service.Session, error) {
 	if id == "" {
 		id = m.generateSessionID()
 	}
@@ -56,7 +72,7 @@ func (m *Manager) Create(id string, config *engine.GameConfig) (*service.Session
 	}
 
 	// Create game engine
-	eng, err := engine.NewEngine(config)
+	eng, err := engine.NewEngine(gameConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create engine: %w", err)
 	}
@@ -65,9 +81,10 @@ func (m *Manager) Create(id string, config *engine.GameConfig) (*service.Session
 	session := &service.Session{
 		ID:             id,
 		Engine:         eng,
-		Config:         config,
+		Config:         gameConfig,
 		CreatedAt:      time.Now(),
 		LastAccessedAt: time.Now(),
+		ConfigChecksum: config.Checksum(gameConfig),
 	}
 
 	m.sessions[strings.ToLower(id)] = session
@@ -76,7 +93,7 @@ func (m *Manager) Create(id string, config *engine.GameConfig) (*service.Session
 	if m.persistence != nil {
 		if err := m.persistence.Save(session); err != nil {
 			// Log error but don't fail the creation
-			fmt.Printf("Warning: Failed to persist session %s: %v\n", id, err)
+			m.logger.Warn("failed to persist session", "session_id", id, "error", err)
 		}
 	}
 
@@ -103,6 +120,7 @@ func (m *Manager) Get(id string) (*service.Session, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to load persisted session: %w", err)
 		}
+		markConfigDrift(session)
 
 		// Add to memory cache
 		m.mu.Lock()
@@ -215,13 +233,21 @@ func (m *Manager) UpdateLastAccessed(id string) error {
 	// Auto-save if persistence is enabled
 	if m.persistence != nil {
 		if err := m.persistence.Save(session); err != nil {
-			fmt.Printf("Warning: Failed to persist session %s after access update: %v\n", id, err)
+			m.logger.Warn("failed to persist session after access update", "session_id", id, "error", err)
 		}
 	}
 
 	return nil
 }
 
+// CheckStorage reports whether session persistence, if configured, is reachable
+func (m *Manager) CheckStorage() error {
+	if m.persistence == nil {
+		return nil
+	}
+	return m.persistence.Ping()
+}
+
 // Save saves a specific session to persistence
 func (m *Manager) Save(id string) error {
 	if m.persistence == nil {
@@ -243,16 +269,21 @@ func (m *Manager) Save(id string) error {
 	return m.persistence.Save(session)
 }
 
-// CleanupExpiredSessions removes sessions that haven't been accessed in the given duration
+// CleanupExpiredSessions removes sessions that haven't been accessed within
+// their own TTL, or within maxAge for sessions without one
 func (m *Manager) CleanupExpiredSessions(maxAge time.Duration) int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	cutoff := time.Now().Add(-maxAge)
+	now := time.Now()
 	removed := 0
 
 	for id, session := range m.sessions {
-		if session.LastAccessedAt.Before(cutoff) {
+		ttl := maxAge
+		if session.TTLSeconds > 0 {
+			ttl = time.Duration(session.TTLSeconds) * time.Second
+		}
+		if session.LastAccessedAt.Before(now.Add(-ttl)) {
 			delete(m.sessions, id)
 			removed++
 		}
@@ -310,21 +341,32 @@ func (m *Manager) LoadPersistedSessions() error {
 
 		session, err := m.persistence.Load(id)
 		if err != nil {
-			fmt.Printf("Warning: Failed to load persisted session %s: %v\n", id, err)
+			m.logger.Warn("failed to load persisted session", "session_id", id, "error", err)
 			continue
 		}
+		if markConfigDrift(session) {
+			m.logger.Warn("config changed since session was saved", "config", session.Config.Name, "session_id", id)
+		}
 
 		m.sessions[strings.ToLower(id)] = session
 		loadedCount++
 	}
 
 	if loadedCount > 0 {
-		fmt.Printf("Loaded %d persisted sessions from storage\n", loadedCount)
+		m.logger.Info("loaded persisted sessions from storage", "count", loadedCount)
 	}
 
 	return nil
 }
 
+// markConfigDrift flags a reloaded session whose config no longer matches the
+// checksum it was saved with. Saves without a checksum are trusted.
+func markConfigDrift(session *service.Session) bool {
+	session.ConfigDrift = session.ConfigChecksum != "" &&
+		session.ConfigChecksum != config.Checksum(session.Config)
+	return session.ConfigDrift
+}
+
 // SaveAllSessions saves all in-memory sessions to persistence
 func (m *Manager) SaveAllSessions() error {
 	if m.persistence == nil {
@@ -341,7 +383,7 @@ func (m *Manager) SaveAllSessions() error {
 	errorCount := 0
 	for _, session := range sessions {
 		if err := m.persistence.Save(session); err != nil {
-			fmt.Printf("Warning: Failed to save session %s: %v\n", session.ID, err)
+			m.logger.Warn("failed to save session", "session_id", session.ID, "error", err)
 			errorCount++
 		}
 	}
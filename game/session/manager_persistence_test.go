@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/wricardo/tesla-road-trip-game/game/config"
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
 )
 
 func TestManagerWithPersistence(t *testing.T) {
@@ -218,3 +220,184 @@ func TestManagerWithPersistence(t *testing.T) {
 		}
 	})
 }
+
+func TestManagerWithPersistence_ConfigDrift(t *testing.T) {
+	configDir, sessionsDir := t.TempDir(), t.TempDir()
+	gameConfig, err := engine.LoadGameConfig("../../configs/classic.json")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+
+	// restart simulates a server restart: fresh config cache, fresh sessions
+	restart := func() (*config.Manager, *Manager) {
+		t.Helper()
+		configManager, err := config.NewManager(configDir)
+		if err != nil {
+			t.Fatalf("Failed to create config manager: %v", err)
+		}
+		persistence, err := NewFilePersistence(sessionsDir, configManager)
+		if err != nil {
+			t.Fatalf("Failed to create file persistence: %v", err)
+		}
+		return configManager, NewManagerWithPersistence(persistence)
+	}
+	reload := func() *service.Session {
+		t.Helper()
+		_, manager := restart()
+		if err := manager.LoadPersistedSessions(); err != nil {
+			t.Fatalf("Failed to load persisted sessions: %v", err)
+		}
+		session, err := manager.Get("stale")
+		if err != nil {
+			t.Fatalf("Failed to get reloaded session: %v", err)
+		}
+		return session
+	}
+
+	configManager, manager := restart()
+	if err := configManager.SaveConfig("drifty", gameConfig); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if _, err := manager.Create("stale", gameConfig); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	if reload().ConfigDrift {
+		t.Error("Unchanged config should not be flagged as drifted")
+	}
+
+	edited := *gameConfig
+	edited.Layout = append([]string(nil), gameConfig.Layout...)
+	edited.Layout[1] = "BRWRRRRRRRRRRRB" // Flood one road cell
+	configManager, _ = restart()
+	if err := configManager.SaveConfig("drifty", &edited); err != nil {
+		t.Fatalf("Failed to save edited config: %v", err)
+	}
+
+	if !reload().ConfigDrift {
+		t.Error("Editing the layout should flag the session as drifted")
+	}
+}
+
+func TestManagerWithPersistence_ConsumedFuel(t *testing.T) {
+	configDir, sessionsDir := t.TempDir(), t.TempDir()
+	gameConfig, err := engine.LoadGameConfig("../../configs/classic.json")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+	gameConfig.Layout = append([]string(nil), gameConfig.Layout...)
+	gameConfig.Layout[7] = "PRRRRHHHHHRFRRP" // Fuel two cells right of the starting home
+	gameConfig.FuelAmount = 5
+	gameConfig.StartingBattery = 10
+
+	configManager, err := config.NewManager(configDir)
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	if err := configManager.SaveConfig("fuel", gameConfig); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	persistence, err := NewFilePersistence(sessionsDir, configManager)
+	if err != nil {
+		t.Fatalf("Failed to create file persistence: %v", err)
+	}
+	manager := NewManagerWithPersistence(persistence)
+	session, err := manager.Create("fuel", gameConfig)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	session.Engine.Move("right")
+	session.Engine.Move("right")
+	if err := manager.Save("fuel"); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+
+	reloaded, err := NewManagerWithPersistence(persistence).Get("fuel")
+	if err != nil {
+		t.Fatalf("Failed to reload session: %v", err)
+	}
+	state := reloaded.Engine.GetState()
+	if state.Battery != 13 || len(state.ConsumedFuel) != 1 {
+		t.Fatalf("Expected one pickup and battery 13, got battery %d and %+v", state.Battery, state.ConsumedFuel)
+	}
+	if state.Grid[7][11].Type != engine.Road {
+		t.Errorf("Expected the consumed fuel to stay road, got %s", state.Grid[7][11].Type)
+	}
+
+	reloaded.Engine.Move("left")
+	reloaded.Engine.Move("right")
+	if battery := reloaded.Engine.GetBattery(); battery != 11 {
+		t.Errorf("Expected no fuel on the second visit, got battery %d", battery)
+	}
+}
+
+func TestManagerWithPersistence_TTL(t *testing.T) {
+	configManager, err := config.NewManager("../../configs")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	persistence, err := NewFilePersistence(t.TempDir(), configManager)
+	if err != nil {
+		t.Fatalf("Failed to create file persistence: %v", err)
+	}
+	gameConfig, err := configManager.LoadConfig("classic")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+
+	manager := NewManagerWithPersistence(persistence)
+	session, err := manager.Create("ttl1", gameConfig)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	session.TTLSeconds = 90
+	if err := manager.Save("ttl1"); err != nil {
+		t.Fatalf("Failed to save session: %v", err)
+	}
+
+	reloaded, err := NewManagerWithPersistence(persistence).Get("ttl1")
+	if err != nil {
+		t.Fatalf("Failed to reload session: %v", err)
+	}
+	if reloaded.TTLSeconds != 90 {
+		t.Errorf("Expected TTLSeconds 90 after reload, got %d", reloaded.TTLSeconds)
+	}
+}
+
+func TestManagerWithPersistence_Label(t *testing.T) {
+	configManager, err := config.NewManager("../../configs")
+	if err != nil {
+		t.Fatalf("Failed to create config manager: %v", err)
+	}
+	gameConfig, err := configManager.LoadConfig("classic")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+
+	for _, encoding := range []string{EncodingJSON, EncodingCompact} {
+		t.Run(encoding, func(t *testing.T) {
+			persistence, err := NewFilePersistence(t.TempDir(), configManager, WithEncoding(encoding))
+			if err != nil {
+				t.Fatalf("Failed to create file persistence: %v", err)
+			}
+
+			manager := NewManagerWithPersistence(persistence)
+			session, err := manager.Create("label1", gameConfig)
+			if err != nil {
+				t.Fatalf("Failed to create session: %v", err)
+			}
+			session.Label = "Sunday drive"
+			if err := manager.Save("label1"); err != nil {
+				t.Fatalf("Failed to save session: %v", err)
+			}
+
+			reloaded, err := NewManagerWithPersistence(persistence).Get("label1")
+			if err != nil {
+				t.Fatalf("Failed to reload session: %v", err)
+			}
+			if reloaded.Label != "Sunday drive" {
+				t.Errorf("Expected label %q after reload, got %q", "Sunday drive", reloaded.Label)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	gameconfig "github.com/wricardo/tesla-road-trip-game/game/config"
 	"github.com/wricardo/tesla-road-trip-game/game/engine"
 )
 
@@ -71,6 +72,9 @@ func TestManager_Create(t *testing.T) {
 		if session.Engine == nil {
 			t.Error("Expected engine to be initialized")
 		}
+		if session.ConfigChecksum != gameconfig.Checksum(config) {
+			t.Errorf("Expected the config checksum recorded, got %q", session.ConfigChecksum)
+		}
 	})
 
 	t.Run("create with auto-generated ID", func(t *testing.T) {
@@ -275,6 +279,35 @@ func TestManager_CleanupExpired(t *testing.T) {
 	}
 }
 
+func TestManager_CleanupExpiredSessionTTL(t *testing.T) {
+	manager := NewManager()
+	config := createTestConfig()
+
+	short, _ := manager.Create("short", config)
+	short.TTLSeconds = 60
+	manager.Create("default", config)
+
+	// Both idle for 10 minutes: past the short TTL, within the default window
+	idle := time.Now().Add(-10 * time.Minute)
+	short.LastAccessedAt = idle
+	defaultSession, _ := manager.Get("default")
+	defaultSession.LastAccessedAt = idle
+
+	if deleted := manager.CleanupExpiredSessions(1 * time.Hour); deleted != 1 {
+		t.Fatalf("Expected 1 session to be deleted, got %d", deleted)
+	}
+	if manager.sessionExists("short") {
+		t.Error("Expected the short-TTL session to be cleaned up")
+	}
+	if !manager.sessionExists("default") {
+		t.Error("Expected the default-TTL session to survive")
+	}
+
+	if deleted := manager.CleanupExpiredSessions(5 * time.Minute); deleted != 1 {
+		t.Errorf("Expected the default-TTL session to expire once past the global window, got %d deleted", deleted)
+	}
+}
+
 func TestManager_UpdateLastAccessed(t *testing.T) {
 	manager := NewManager()
 	config := createTestConfig()
@@ -22,6 +22,9 @@ type SessionPersistence interface {
 
 	// Exists checks if a session exists in storage
 	Exists(id string) bool
+
+	// Ping checks that the storage is reachable
+	Ping() error
 }
 
 // PersistedSessionData represents the JSON structure for persisted sessions
@@ -31,4 +34,14 @@ type PersistedSessionData struct {
 	CreatedAt      time.Time `json:"created_at"`
 	LastAccessedAt time.Time `json:"last_accessed_at"`
 	GameState      any       `json:"game_state"` // Will be *engine.GameState when loaded
+	// ConfigChecksum is config.Checksum of the config the session was played on;
+	// empty in saves from before checksums were recorded
+	ConfigChecksum string `json:"config_checksum,omitempty"`
+	// TTLSeconds is the session's own inactivity timeout, if it has one
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+	// Label is the session's user-facing name, if it has one
+	Label string `json:"label,omitempty"`
+	// EventLog is the session's recent events, saved only when the
+	// persistence is asked to keep them
+	EventLog []service.GameEvent `json:"event_log,omitempty"`
 }
@@ -0,0 +1,32 @@
+// Package strategy provides move-selection heuristics for the Tesla Road Trip Game.
+//
+// The strategy package implements:
+//   - Breadth-first pathfinding over passable grid cells
+//   - A random strategy that picks among the currently possible moves
+//   - A greedy strategy that heads for the nearest unvisited park and
+//     detours to the nearest charger when the battery cannot cover the trip
+//   - The bruteforcer's systematic strategy, which plans a full park
+//     collection order up front and commits to charger detours
+//   - A solver that searches for a full winning plan, and reports how many
+//     states it expanded
+//   - A planner strategy that plays the solver's plan move by move,
+//     planning again when the game strays from it
+//   - GenerateByDifficulty, which builds winnable random configs that grow
+//     in size, park count, walls and battery pressure with difficulty
+//
+// Strategies are pure functions of a GameState: they never mutate the state
+// they inspect, so callers decide how (and through which layer) the chosen
+// move is applied.
+//
+// Usage:
+//
+//	strat, err := strategy.New("greedy")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	direction := strat.Next(gameEngine.GetState())
+//	if direction != "" {
+//		gameEngine.Move(direction)
+//	}
+package strategy
@@ -0,0 +1,180 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// Difficulty range accepted by GenerateByDifficulty
+const (
+	MinDifficulty = 1
+	MaxDifficulty = 10
+)
+
+// How difficulty maps to a generated config. Each setting moves linearly
+// from its easy value at MinDifficulty to its hard value at MaxDifficulty.
+const (
+	genGridSizeEasy, genGridSizeHard           = 7, 16    // Width and height, border included
+	genParksEasy, genParksHard                 = 2, 8     // Parks to collect
+	genSuperchargersEasy, genSuperchargersHard = 3, 0     // Superchargers besides home
+	genWallPercentEasy, genWallPercentHard     = 5, 30    // Chance of an interior cell being building or water
+	genSlackPercentEasy, genSlackPercentHard   = 175, 100 // Max battery as a share of the round trip to the farthest park
+	genTargetMovesEasy, genTargetMovesHard     = 10, 140  // Solver plan length the generator aims for
+)
+
+// Search limits of GenerateByDifficulty
+const (
+	genCandidates  = 3    // Winnable layouts compared against the target move count
+	genMaxAttempts = 200  // Layouts tried before giving up
+	genSolveNodes  = 5000 // Solver states per layout, so a layout's fate doesn't depend on machine speed
+)
+
+// ErrInvalidDifficulty means the difficulty is outside MinDifficulty to MaxDifficulty
+var ErrInvalidDifficulty = fmt.Errorf("difficulty must be between %d and %d", MinDifficulty, MaxDifficulty)
+
+// GenerateByDifficulty builds a random winnable config whose size, park
+// count, walls and battery margin scale with difficulty. Several candidate
+// layouts are proven winnable with the solver and the one whose plan length
+// is closest to the difficulty's target move count is returned. The same
+// difficulty and seed always give the same config.
+func GenerateByDifficulty(difficulty int, seed int64) (*engine.GameConfig, error) {
+	if difficulty < MinDifficulty || difficulty > MaxDifficulty {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidDifficulty, difficulty)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	target := scaleDifficulty(difficulty, genTargetMovesEasy, genTargetMovesHard)
+	var best *engine.GameConfig
+	bestOff, found := 0, 0
+	for attempt := 0; attempt < genMaxAttempts && found < genCandidates; attempt++ {
+		config := generateLayout(rng, difficulty)
+		if config == nil {
+			continue
+		}
+		config.Name = fmt.Sprintf("generated-d%d-%d", difficulty, seed)
+		config.Description = fmt.Sprintf("Generated at difficulty %d from seed %d", difficulty, seed)
+		if engine.ValidateGameConfig(config) != nil {
+			continue
+		}
+
+		plan, _, err := solve(context.Background(), engine.InitGameStateFromConfig(config), config, genSolveNodes)
+		if err != nil {
+			continue
+		}
+		found++
+		off := len(plan) - target
+		if off < 0 {
+			off = -off
+		}
+		if best == nil || off < bestOff {
+			best, bestOff = config, off
+		}
+	}
+	if best == nil {
+		return nil, errors.New("no winnable config found for this difficulty and seed")
+	}
+	return best, nil
+}
+
+// scaleDifficulty interpolates between a setting's easy and hard values
+func scaleDifficulty(difficulty, easy, hard int) int {
+	steps := MaxDifficulty - MinDifficulty
+	return easy + ((hard-easy)*(difficulty-MinDifficulty)*2+steps)/(steps*2)
+}
+
+// generateLayout places walls, home, parks and superchargers at random and
+// sizes the battery to the layout, or returns nil when too few cells are
+// reachable from home to fit everything
+func generateLayout(rng *rand.Rand, difficulty int) *engine.GameConfig {
+	size := scaleDifficulty(difficulty, genGridSizeEasy, genGridSizeHard)
+	parks := scaleDifficulty(difficulty, genParksEasy, genParksHard)
+	superchargers := scaleDifficulty(difficulty, genSuperchargersEasy, genSuperchargersHard)
+	walls := scaleDifficulty(difficulty, genWallPercentEasy, genWallPercentHard)
+
+	grid := make([][]byte, size)
+	for y := range grid {
+		grid[y] = make([]byte, size)
+		for x := range grid[y] {
+			switch {
+			case x == 0 || y == 0 || x == size-1 || y == size-1:
+				grid[y][x] = 'B'
+			case rng.Intn(100) >= walls:
+				grid[y][x] = 'R'
+			case rng.Intn(3) == 0:
+				grid[y][x] = 'W'
+			default:
+				grid[y][x] = 'B'
+			}
+		}
+	}
+
+	home := engine.Position{X: 1 + rng.Intn(size-2), Y: 1 + rng.Intn(size-2)}
+	grid[home.Y][home.X] = 'H'
+
+	// Parks and superchargers go on random roads connected to home
+	var reachable []engine.Position
+	seen := map[engine.Position]bool{home: true}
+	queue := []engine.Position{home}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, d := range []engine.Position{{X: 0, Y: -1}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 1, Y: 0}} {
+			n := engine.Position{X: pos.X + d.X, Y: pos.Y + d.Y}
+			if grid[n.Y][n.X] == 'R' && !seen[n] {
+				seen[n] = true
+				reachable = append(reachable, n)
+				queue = append(queue, n)
+			}
+		}
+	}
+	if len(reachable) < 2*(parks+superchargers) {
+		return nil
+	}
+	rng.Shuffle(len(reachable), func(i, j int) { reachable[i], reachable[j] = reachable[j], reachable[i] })
+	for i, pos := range reachable[:parks+superchargers] {
+		if i < parks {
+			grid[pos.Y][pos.X] = 'P'
+		} else {
+			grid[pos.Y][pos.X] = 'S'
+		}
+	}
+
+	config := &engine.GameConfig{
+		GridSize: size,
+		Legend: map[string]string{
+			"R": "road",
+			"H": "home",
+			"P": "park",
+			"S": "supercharger",
+			"W": "water",
+			"B": "building",
+		},
+	}
+	for _, row := range grid {
+		config.Layout = append(config.Layout, string(row))
+	}
+	config.Messages.Welcome = "Welcome! Drive your Tesla to collect parks. Watch your battery!"
+	config.Messages.HomeCharge = "Home sweet home! Battery fully charged!"
+	config.Messages.SuperchargerCharge = "Supercharger! Battery fully charged!"
+	config.Messages.ParkVisited = "Park visited! Score: %d"
+	config.Messages.ParkAlreadyVisited = "Already visited this park"
+	config.Messages.Victory = "Victory! All %d parks visited!"
+	config.Messages.OutOfBattery = "Out of battery! Game Over!"
+	config.Messages.Stranded = "Stranded with no battery! Game Over!"
+	config.Messages.CantMove = "Can't move there!"
+	config.Messages.BatteryStatus = "Battery: %d/%d"
+	config.Messages.HitWall = "You crashed into a wall! Game Over!"
+
+	// Battery covers the round trip to the farthest park, with a margin that
+	// shrinks as difficulty grows
+	config.MaxBattery = engine.MaxBattery
+	roundTrip := 2 * engine.AnalyzeConfig(config).MaxParkChargerDistance
+	slack := scaleDifficulty(difficulty, genSlackPercentEasy, genSlackPercentHard)
+	config.MaxBattery = min(max((roundTrip*slack+99)/100, engine.MinBattery), engine.MaxBattery)
+	config.StartingBattery = config.MaxBattery
+	return config
+}
@@ -0,0 +1,86 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+func TestGenerateByDifficulty_Winnable(t *testing.T) {
+	for _, difficulty := range []int{MinDifficulty, 5, MaxDifficulty} {
+		config, err := GenerateByDifficulty(difficulty, 1)
+		if err != nil {
+			t.Fatalf("Difficulty %d: %v", difficulty, err)
+		}
+		eng, err := engine.NewEngine(config)
+		if err != nil {
+			t.Fatalf("Difficulty %d: generated config rejected: %v", difficulty, err)
+		}
+		plan, err := Solve(context.Background(), eng.GetState(), config)
+		if err != nil {
+			t.Fatalf("Difficulty %d: Solve returned error: %v", difficulty, err)
+		}
+		replay(t, eng, plan)
+	}
+}
+
+func TestGenerateByDifficulty_Deterministic(t *testing.T) {
+	a, err := GenerateByDifficulty(4, 42)
+	if err != nil {
+		t.Fatalf("GenerateByDifficulty failed: %v", err)
+	}
+	b, err := GenerateByDifficulty(4, 42)
+	if err != nil {
+		t.Fatalf("GenerateByDifficulty failed: %v", err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Expected the same config for the same seed, got\n%v\n%v", a.Layout, b.Layout)
+	}
+}
+
+func TestGenerateByDifficulty_Scales(t *testing.T) {
+	const seeds = 5
+	type totals struct{ size, parks, moves, nodes int }
+	measure := func(difficulty int) totals {
+		var sum totals
+		for seed := int64(1); seed <= seeds; seed++ {
+			config, err := GenerateByDifficulty(difficulty, seed)
+			if err != nil {
+				t.Fatalf("Difficulty %d seed %d: %v", difficulty, seed, err)
+			}
+			plan, stats, err := SolveWithStats(context.Background(), engine.InitGameStateFromConfig(config), config)
+			if err != nil {
+				t.Fatalf("Difficulty %d seed %d: Solve returned error: %v", difficulty, seed, err)
+			}
+			sum.size += config.GridSize
+			sum.parks += engine.AnalyzeConfig(config).ParkCount
+			sum.moves += len(plan)
+			sum.nodes += stats.Nodes
+		}
+		return sum
+	}
+
+	prev := measure(MinDifficulty)
+	for _, difficulty := range []int{4, 7, MaxDifficulty} {
+		cur := measure(difficulty)
+		t.Logf("Difficulty %d: %+v", difficulty, cur)
+		if cur.size <= prev.size || cur.parks <= prev.parks {
+			t.Errorf("Difficulty %d: expected larger configs than the previous level, got %+v after %+v", difficulty, cur, prev)
+		}
+		if cur.moves <= prev.moves || cur.nodes <= prev.nodes {
+			t.Errorf("Difficulty %d: expected longer plans and more solver nodes than the previous level, got %+v after %+v", difficulty, cur, prev)
+		}
+		prev = cur
+	}
+}
+
+func TestGenerateByDifficulty_OutOfRange(t *testing.T) {
+	for _, difficulty := range []int{MinDifficulty - 1, MaxDifficulty + 1} {
+		if _, err := GenerateByDifficulty(difficulty, 1); !errors.Is(err, ErrInvalidDifficulty) {
+			t.Errorf("Difficulty %d: expected ErrInvalidDifficulty, got %v", difficulty, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package strategy
+
+import (
+	"context"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// Planner follows a winning plan from Solve one move at a time. It plans
+// again whenever the game strays from what the plan expected, such as after
+// a random event or a move made by someone else.
+type Planner struct {
+	ctx    context.Context
+	config *engine.GameConfig
+	plan   []string
+	expect *planStep // Where the plan's last move was expected to leave the game
+}
+
+// planStep is the part of a state a plan is checked against
+type planStep struct {
+	pos     engine.Position
+	battery int
+	score   int
+}
+
+// NewPlanner creates a solver-backed strategy for games of config. Planning
+// stops when ctx is done, after which Next returns "".
+func NewPlanner(ctx context.Context, config *engine.GameConfig) *Planner {
+	return &Planner{ctx: ctx, config: config}
+}
+
+// Name returns "solver"
+func (p *Planner) Name() string {
+	return "solver"
+}
+
+// Next returns the next move of the plan, planning first when there is no
+// plan or the state doesn't match it; "" when no winning plan is found
+func (p *Planner) Next(state *engine.GameState) string {
+	if state.GameOver {
+		return ""
+	}
+	if len(p.plan) == 0 || !p.onPlan(state) {
+		ctx, cancel := context.WithTimeout(p.ctx, DefaultSolveBudget)
+		plan, err := Solve(ctx, state, p.config)
+		cancel()
+		if err != nil || len(plan) == 0 {
+			p.plan, p.expect = nil, nil
+			return ""
+		}
+		p.plan = plan
+	}
+
+	move := p.plan[0]
+	p.plan = p.plan[1:]
+	next := state.Clone()
+	next.MoveHistory, next.CurrentMoves = nil, nil
+	next.MovePlayer(move, p.config)
+	p.expect = &planStep{pos: next.PlayerPos, battery: next.Battery, score: next.Score}
+	return move
+}
+
+// onPlan reports whether state is where the plan's last move was expected
+// to leave the game
+func (p *Planner) onPlan(state *engine.GameState) bool {
+	return p.expect != nil && *p.expect == planStep{pos: state.PlayerPos, battery: state.Battery, score: state.Score}
+}
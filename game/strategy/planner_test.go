@@ -0,0 +1,53 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPlanner_ReplansWhenTheGameStrays(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BPRHRPB",
+		"BBRBRBB",
+		"BBPRRBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+	}, 8)
+	planner := NewPlanner(context.Background(), eng.GetConfig())
+	if planner.Name() != "solver" {
+		t.Errorf("Expected name solver, got %s", planner.Name())
+	}
+
+	if !eng.Move(planner.Next(eng.GetState())) {
+		t.Fatalf("First planned move failed: %s", eng.GetState().Message)
+	}
+	// A move the plan didn't make puts the game off the plan
+	eng.Move("down")
+
+	for moves := 0; !eng.IsVictory(); moves++ {
+		if moves > 50 {
+			t.Fatal("Planner did not win within 50 moves")
+		}
+		move := planner.Next(eng.GetState())
+		if move == "" || !eng.Move(move) {
+			t.Fatalf("Planned move %q failed: %s", move, eng.GetState().Message)
+		}
+	}
+}
+
+func TestPlanner_StopsWithItsContext(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBB",
+		"BPRHB",
+		"BBBBB",
+		"BBBBB",
+		"BBBBB",
+	}, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if move := NewPlanner(ctx, eng.GetConfig()).Next(eng.GetState()); move != "" {
+		t.Errorf("Expected no move once cancelled, got %q", move)
+	}
+}
@@ -0,0 +1,310 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+var (
+	// ErrNoSolution means the search budget ran out before a winning plan was found
+	ErrNoSolution = errors.New("no solution found within the search budget")
+	// ErrUnsolvable means the search finished without finding any winning plan
+	ErrUnsolvable = errors.New("no winning plan exists from this state")
+)
+
+// DefaultSolveBudget bounds how long Solve searches when no deadline is given
+const DefaultSolveBudget = 2 * time.Second
+
+// Purposes of the legs a plan is made of
+const (
+	PurposePark   = "park"   // Drive to an unvisited park and collect it
+	PurposeCharge = "charge" // Drive to a charger and charge to full there
+	PurposeKey    = "key"    // Drive to a key to open its doors
+)
+
+// PlannedMove is one move of a winning plan, annotated with the leg it
+// belongs to: what the leg is for, the park, key or charger it heads to, and the
+// battery left after the move
+type PlannedMove struct {
+	Action       string
+	Purpose      string
+	Target       engine.Position
+	BatteryAfter int
+}
+
+// SolveStats describes the work a search did
+type SolveStats struct {
+	Nodes int `json:"nodes"` // States expanded, a measure of how hard the config is to plan
+}
+
+// Solve searches for a move sequence that wins the game from the given state.
+// It plans like the bruteforcer's systematic strategy, as a series of legs to
+// an unvisited park, an uncollected key or a charger (charging to full
+// there), but replays every leg through the engine so battery, charging and
+// game-over rules are exactly those of a real game. Legs are tried nearest
+// first with backtracking. The state is not modified.
+//
+// Solve stops at the context deadline, or after DefaultSolveBudget when the
+// context has none, and then returns ErrNoSolution.
+func Solve(ctx context.Context, state *engine.GameState, config *engine.GameConfig) ([]string, error) {
+	plan, _, err := SolveWithStats(ctx, state, config)
+	return plan, err
+}
+
+// SolveWithStats is Solve, also reporting how much searching it took
+func SolveWithStats(ctx context.Context, state *engine.GameState, config *engine.GameConfig) ([]string, SolveStats, error) {
+	plan, stats, err := solve(ctx, state, config, 0)
+	if err != nil {
+		return nil, stats, err
+	}
+	return actions(plan), stats, nil
+}
+
+// SolvePlan is Solve, returning each move with the leg of the plan it
+// belongs to instead of the bare actions
+func SolvePlan(ctx context.Context, state *engine.GameState, config *engine.GameConfig) ([]PlannedMove, error) {
+	plan, _, err := solve(ctx, state, config, 0)
+	return plan, err
+}
+
+// actions returns the actions of a plan in order
+func actions(plan []PlannedMove) []string {
+	moves := make([]string, len(plan))
+	for i, m := range plan {
+		moves[i] = m.Action
+	}
+	return moves
+}
+
+// solve runs the search, giving up with ErrNoSolution after maxNodes states
+// when maxNodes is positive, which unlike a deadline doesn't depend on the
+// speed of the machine
+func solve(ctx context.Context, state *engine.GameState, config *engine.GameConfig, maxNodes int) ([]PlannedMove, SolveStats, error) {
+	if state == nil || config == nil {
+		return nil, SolveStats{}, ErrUnsolvable
+	}
+	if state.Victory {
+		return []PlannedMove{}, SolveStats{}, nil
+	}
+	if state.GameOver {
+		return nil, SolveStats{}, ErrUnsolvable
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultSolveBudget)
+		defer cancel()
+	}
+
+	// History is irrelevant to the plan; dropping it keeps clones cheap
+	start := state.Clone()
+	start.MoveHistory = nil
+	start.CurrentMoves = nil
+
+	s := &solver{ctx: ctx, config: config, maxNodes: maxNodes, seen: make(map[string]bool)}
+	for y, row := range start.Grid {
+		for x, cell := range row {
+			pos := engine.Position{X: x, Y: y}
+			switch cell.Type {
+			case engine.Park:
+				s.parks = append(s.parks, pos)
+			case engine.Key:
+				s.keys = append(s.keys, pos)
+			case engine.Home, engine.Supercharger, engine.Trickle:
+				s.chargers = append(s.chargers, pos)
+			}
+		}
+	}
+
+	plan, found := s.search(start)
+	stats := SolveStats{Nodes: s.nodes}
+	if found {
+		return plan, stats, nil
+	}
+	if s.timedOut {
+		return nil, stats, ErrNoSolution
+	}
+	return nil, stats, ErrUnsolvable
+}
+
+// solver holds the depth-first search over legs
+type solver struct {
+	ctx      context.Context
+	config   *engine.GameConfig
+	parks    []engine.Position
+	keys     []engine.Position
+	chargers []engine.Position
+	seen     map[string]bool // States already explored without success
+	nodes    int
+	maxNodes int  // Zero for no limit
+	timedOut bool // The deadline or node limit was hit
+}
+
+// leg is a candidate path from the current state
+type leg struct {
+	moves  []string
+	target engine.Position
+	charge bool // Top up to full at the end of the leg
+	park   bool // Collect the park at the end of the leg if entering didn't
+	key    bool // Pick up the key at the end of the leg
+}
+
+// purpose returns the PurposePark, PurposeKey or PurposeCharge the leg serves
+func (l leg) purpose() string {
+	switch {
+	case l.charge:
+		return PurposeCharge
+	case l.key:
+		return PurposeKey
+	}
+	return PurposePark
+}
+
+func (s *solver) search(state *engine.GameState) ([]PlannedMove, bool) {
+	if s.ctx.Err() != nil || (s.maxNodes > 0 && s.nodes >= s.maxNodes) {
+		s.timedOut = true
+		return nil, false
+	}
+
+	key := s.stateKey(state)
+	if s.seen[key] {
+		return nil, false
+	}
+	s.seen[key] = true
+	s.nodes++
+
+	for _, l := range s.legs(state) {
+		next, moves, ok := s.play(state, l)
+		if !ok {
+			continue
+		}
+		if next.Victory {
+			return moves, true
+		}
+		if rest, found := s.search(next); found {
+			return append(moves, rest...), true
+		}
+		if s.timedOut {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// legs lists paths to every reachable unvisited park, nearest first, followed
+// by paths to every uncollected key and then to every charger
+func (s *solver) legs(state *engine.GameState) []leg {
+	var parkLegs, keyLegs, chargerLegs []leg
+	for _, pos := range s.parks {
+		if cell := state.Grid[pos.Y][pos.X]; cell.Type != engine.Park || cell.Visited {
+			continue // Collected or expired
+		}
+		if path := pathTo(state, pos); path != nil {
+			parkLegs = append(parkLegs, leg{moves: path, target: pos, park: true})
+		}
+	}
+	for _, pos := range s.keys {
+		if state.Grid[pos.Y][pos.X].Visited {
+			continue
+		}
+		if path := pathTo(state, pos); path != nil {
+			keyLegs = append(keyLegs, leg{moves: path, target: pos, key: true})
+		}
+	}
+	for _, pos := range s.chargers {
+		if path := pathTo(state, pos); path != nil {
+			if len(path) == 0 && state.Battery >= state.MaxBattery {
+				continue // Nothing to gain from charging here
+			}
+			chargerLegs = append(chargerLegs, leg{moves: path, target: pos, charge: true})
+		}
+	}
+
+	sort.SliceStable(parkLegs, func(i, j int) bool { return len(parkLegs[i].moves) < len(parkLegs[j].moves) })
+	sort.SliceStable(keyLegs, func(i, j int) bool { return len(keyLegs[i].moves) < len(keyLegs[j].moves) })
+	sort.SliceStable(chargerLegs, func(i, j int) bool { return len(chargerLegs[i].moves) < len(chargerLegs[j].moves) })
+	return append(append(parkLegs, keyLegs...), chargerLegs...)
+}
+
+// play applies a leg to a copy of state, stopping early on victory. It
+// fails if any move is rejected or the game is lost.
+func (s *solver) play(state *engine.GameState, l leg) (*engine.GameState, []PlannedMove, bool) {
+	next := state.Clone()
+	moves := make([]PlannedMove, 0, len(l.moves))
+
+	apply := func(action string) bool {
+		if !next.MovePlayer(action, s.config) {
+			return false
+		}
+		moves = append(moves, PlannedMove{Action: action, Purpose: l.purpose(), Target: l.target, BatteryAfter: next.Battery})
+		return !next.GameOver || next.Victory
+	}
+
+	for _, dir := range l.moves {
+		// A charger's move penalty holds the player until it runs out
+		for next.ChargeHold > 0 {
+			if !apply(engine.ActionWait) {
+				return nil, nil, false
+			}
+		}
+		if !apply(dir) {
+			return nil, nil, false
+		}
+		if next.Victory {
+			return next, moves, true
+		}
+	}
+	if l.park && OnUncollectedPark(next) {
+		if !apply(engine.ActionPark) {
+			return nil, nil, false
+		}
+		if next.Victory {
+			return next, moves, true
+		}
+	}
+	if l.charge {
+		// Arriving on a charger applies one increment; spend turns for the rest
+		for next.Battery < next.MaxBattery {
+			if !apply(engine.ActionCharge) {
+				return nil, nil, false
+			}
+		}
+	}
+	return next, moves, true
+}
+
+// stateKey identifies the parts of a state that affect what can still be won
+func (s *solver) stateKey(state *engine.GameState) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d,%d,%d,%d:", state.PlayerPos.X, state.PlayerPos.Y, state.Battery, state.ChargeHold)
+	for _, pos := range s.parks {
+		if state.Grid[pos.Y][pos.X].Visited {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	// Collected keys change which doors are open
+	for _, id := range state.CollectedKeys {
+		fmt.Fprintf(&b, ";%s", id)
+	}
+	// Parks with a deadline make the moves already spent matter too
+	for _, p := range state.ParkExpiry {
+		fmt.Fprintf(&b, ",%d", p.MovesLeft)
+	}
+	return b.String()
+}
+
+// pathTo returns the shortest path from the player to target ignoring
+// battery, or nil when target is unreachable
+func pathTo(state *engine.GameState, target engine.Position) []string {
+	return PathToNearest(state, func(pos engine.Position, _ engine.Cell) bool {
+		return pos == target
+	})
+}
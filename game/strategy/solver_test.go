@@ -0,0 +1,305 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// replay applies a plan to the engine and reports whether it won
+func replay(t *testing.T, eng *engine.GameEngine, plan []string) {
+	t.Helper()
+	for i, move := range plan {
+		if !eng.Move(move) {
+			t.Fatalf("Move %d (%s) of plan failed: %s", i+1, move, eng.GetState().Message)
+		}
+	}
+	if !eng.IsVictory() {
+		t.Fatalf("Plan of %d moves did not win: score=%d battery=%d", len(plan), eng.GetScore(), eng.GetBattery())
+	}
+}
+
+func TestSolve_WinsWithChargingDetours(t *testing.T) {
+	// Each park needs a return to home before the next one
+	eng := createTestEngine(t, []string{
+		"BBBBBBBBB",
+		"BPRRHRRPB",
+		"BBBBRBBBB",
+		"BBBBRBBBB",
+		"BBBBPBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+	}, 6)
+
+	plan, err := Solve(context.Background(), eng.GetState(), eng.GetConfig())
+	if err != nil {
+		t.Fatalf("Solve returned error: %v", err)
+	}
+	if len(eng.GetMoveHistory()) != 0 {
+		t.Fatal("Solve must not modify the state")
+	}
+	replay(t, eng, plan)
+}
+
+func TestSolvePlan_Annotations(t *testing.T) {
+	// Starting on the home at (3,1) with 1 battery, the plan must charge first
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BPRHRPB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+	}, 4)
+	eng.GetConfig().ChargePerTurn = 1
+	state := eng.GetState()
+	state.Battery = 1
+	if err := eng.SetState(state); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+
+	plan, err := SolvePlan(context.Background(), eng.GetState(), eng.GetConfig())
+	if err != nil {
+		t.Fatalf("SolvePlan returned error: %v", err)
+	}
+	home := engine.Position{X: 3, Y: 1}
+	charged := false
+	for i, m := range plan {
+		switch m.Purpose {
+		case PurposePark:
+			if cell := eng.GetState().Grid[m.Target.Y][m.Target.X]; cell.Type != engine.Park {
+				t.Errorf("Move %d heads for %v, which is not a park", i+1, m.Target)
+			}
+		case PurposeCharge:
+			charged = true
+			if m.Target != home {
+				t.Errorf("Move %d heads for %v, expected the home", i+1, m.Target)
+			}
+		default:
+			t.Errorf("Move %d has unknown purpose %q", i+1, m.Purpose)
+		}
+		if !eng.Move(m.Action) {
+			t.Fatalf("Move %d (%s) of plan failed: %s", i+1, m.Action, eng.GetState().Message)
+		}
+		if eng.GetBattery() != m.BatteryAfter {
+			t.Errorf("Move %d: expected battery %d, got %d", i+1, m.BatteryAfter, eng.GetBattery())
+		}
+	}
+	if !eng.IsVictory() {
+		t.Fatal("Expected the plan to win")
+	}
+	if !charged {
+		t.Error("Expected the plan to include a leg to the charger")
+	}
+	if last := plan[len(plan)-1]; last.Purpose != PurposePark || last.Target != eng.GetPlayerPosition() {
+		t.Errorf("Expected the plan to end collecting a park, got %+v", last)
+	}
+}
+
+func TestSolvePlan_Keys(t *testing.T) {
+	// The park behind the door at (2,1) needs the key down the side road first
+	config := *createTestEngine(t, []string{"BBBBB", "BHRPB", "BRBBB", "BRBBB", "BBBBB"}, 10).GetConfig()
+	config.Layout = []string{
+		"BBBBB",
+		"BHDPB",
+		"BRBBB",
+		"BYBBB",
+		"BBBBB",
+	}
+	config.Keys = []engine.LockTile{{ID: "gate", X: 1, Y: 3}}
+	config.Doors = []engine.LockTile{{ID: "gate", X: 2, Y: 1}}
+	eng, err := engine.NewEngine(&config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	plan, err := SolvePlan(context.Background(), eng.GetState(), eng.GetConfig())
+	if err != nil {
+		t.Fatalf("SolvePlan returned error: %v", err)
+	}
+	if len(plan) == 0 || plan[0].Purpose != PurposeKey || plan[0].Target != (engine.Position{X: 1, Y: 3}) {
+		t.Fatalf("Expected the plan to start heading for the key, got %+v", plan)
+	}
+	replay(t, eng, actions(plan))
+}
+
+func TestSolve_IncrementalCharging(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BPRHRPB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+	}, 4)
+	eng.GetConfig().ChargePerTurn = 1
+	state := eng.GetState()
+	state.Battery = 1
+	if err := eng.SetState(state); err != nil {
+		t.Fatalf("SetState failed: %v", err)
+	}
+
+	plan, err := Solve(context.Background(), eng.GetState(), eng.GetConfig())
+	if err != nil {
+		t.Fatalf("Solve returned error: %v", err)
+	}
+	charges := 0
+	for _, move := range plan {
+		if move == engine.ActionCharge {
+			charges++
+		}
+	}
+	if charges == 0 {
+		t.Errorf("Expected plan to spend turns charging, got %v", plan)
+	}
+	replay(t, eng, plan)
+}
+
+func TestSolve_ChargerMovePenalty(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBBBB",
+		"BPRRHRRPB",
+		"BBBBRBBBB",
+		"BBBBRBBBB",
+		"BBBBPBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+	}, 6)
+	eng.GetConfig().ChargerEffects = map[engine.CellType]engine.ChargerEffect{engine.Home: {MovePenalty: 2}}
+
+	plan, err := Solve(context.Background(), eng.GetState(), eng.GetConfig())
+	if err != nil {
+		t.Fatalf("Solve returned error: %v", err)
+	}
+	waits := 0
+	for _, move := range plan {
+		if move == engine.ActionWait {
+			waits++
+		}
+	}
+	if waits == 0 {
+		t.Errorf("Expected plan to wait out the home's move penalty, got %v", plan)
+	}
+	replay(t, eng, plan)
+}
+
+func TestSolve_RequireParkAction(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BPRHRPB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+	}, 4)
+	eng.GetConfig().RequireParkAction = true
+
+	plan, err := Solve(context.Background(), eng.GetState(), eng.GetConfig())
+	if err != nil {
+		t.Fatalf("Solve returned error: %v", err)
+	}
+	parks := 0
+	for _, move := range plan {
+		if move == engine.ActionPark {
+			parks++
+		}
+	}
+	if parks != 2 {
+		t.Errorf("Expected one park action per park, got %v", plan)
+	}
+	replay(t, eng, plan)
+}
+
+func TestSolve_MustReturnHome(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BPRHRPB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+	}, 6)
+	eng.GetConfig().MustReturnHome = true
+
+	plan, err := Solve(context.Background(), eng.GetState(), eng.GetConfig())
+	if err != nil {
+		t.Fatalf("Solve returned error: %v", err)
+	}
+	replay(t, eng, plan)
+	if pos := eng.GetState().PlayerPos; pos != (engine.Position{X: 3, Y: 1}) {
+		t.Errorf("Expected the plan to end at home, ended at %v", pos)
+	}
+}
+
+func TestSolve_Unsolvable(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBBBB",
+		"BPRRHRRPB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+		"BBBBBBBBB",
+	}, 4)
+
+	// One charge left, too far from home to collect a park and get back
+	state := eng.GetState()
+	state.PlayerPos = engine.Position{X: 2, Y: 1}
+	state.Battery = 1
+
+	_, err := Solve(context.Background(), eng.GetState(), eng.GetConfig())
+	if !errors.Is(err, ErrUnsolvable) {
+		t.Errorf("Expected ErrUnsolvable, got %v", err)
+	}
+}
+
+func TestSolve_BudgetExhausted(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BPRRRPB",
+		"BRBBBRB",
+		"BRRHRRB",
+		"BRBBBRB",
+		"BPRRRPB",
+		"BBBBBBB",
+	}, 8)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	_, err := Solve(ctx, eng.GetState(), eng.GetConfig())
+	if !errors.Is(err, ErrNoSolution) {
+		t.Errorf("Expected ErrNoSolution, got %v", err)
+	}
+}
+
+func TestSolve_ShippedConfig(t *testing.T) {
+	config, err := engine.LoadGameConfig("../../configs/classic.json")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+	eng, err := engine.NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	plan, err := Solve(context.Background(), eng.GetState(), eng.GetConfig())
+	if err != nil {
+		t.Fatalf("Solve returned error: %v", err)
+	}
+	replay(t, eng, plan)
+}
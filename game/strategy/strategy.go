@@ -0,0 +1,239 @@
+package strategy
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// Directions lists the cardinal move directions in a stable order
+var Directions = []string{"up", "down", "left", "right"}
+
+// Strategy picks the next direction to move for a given game state
+type Strategy interface {
+	// Name returns the identifier used to select the strategy
+	Name() string
+	// Next returns the next direction to move, or "" when no move should be made
+	Next(state *engine.GameState) string
+}
+
+// New returns the strategy registered under the given name
+func New(name string) (Strategy, error) {
+	switch name {
+	case "random":
+		return NewRandom(rand.New(rand.NewSource(time.Now().UnixNano()))), nil
+	case "greedy":
+		return &Greedy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown strategy '%s' (available: random, greedy)", name)
+	}
+}
+
+// Random picks uniformly among the currently possible moves
+type Random struct {
+	rng *rand.Rand
+}
+
+// NewRandom creates a random strategy using the provided source of randomness
+func NewRandom(rng *rand.Rand) *Random {
+	return &Random{rng: rng}
+}
+
+// Name returns "random"
+func (r *Random) Name() string {
+	return "random"
+}
+
+// Next returns a random possible move
+func (r *Random) Next(state *engine.GameState) string {
+	moves := PossibleMoves(state)
+	if len(moves) == 0 {
+		return ""
+	}
+	return moves[r.rng.Intn(len(moves))]
+}
+
+// Greedy heads for the nearest unvisited park and detours to the nearest
+// charger when the battery cannot cover the trip
+type Greedy struct{}
+
+// Name returns "greedy"
+func (g *Greedy) Name() string {
+	return "greedy"
+}
+
+// Next returns the first step of the greedy plan
+func (g *Greedy) Next(state *engine.GameState) string {
+	if OnUncollectedPark(state) {
+		return engine.ActionPark
+	}
+	if state == nil || state.GameOver || state.Battery <= 0 {
+		return ""
+	}
+
+	// Every park is collected but the config wants the player back home
+	if state.ReturnHome {
+		if homePath := PathToNearest(state, isHome); len(homePath) > 0 {
+			return homePath[0]
+		}
+		return ""
+	}
+
+	parkPath := NearestUnvisitedParkPath(state)
+	if len(parkPath) == 0 {
+		return ""
+	}
+
+	// Budget the trip to the park plus the way back to a charger, unless this
+	// park is the last one and collecting it wins the game
+	required := len(parkPath)
+	if engine.CountTotalParks(state.Grid)-len(state.VisitedParks) > 1 {
+		parkPos := FollowPath(state.PlayerPos, parkPath)
+		if back := PathToNearestFrom(state, parkPos, isCharger); back != nil {
+			required += len(back)
+		}
+	}
+	if required <= state.Battery {
+		return parkPath[0]
+	}
+
+	// Not enough battery for the park: recharge first unless already on a charger
+	chargerPath := NearestChargerPath(state)
+	if len(chargerPath) > 0 {
+		return chargerPath[0]
+	}
+	return parkPath[0]
+}
+
+// PossibleMoves returns the directions the player can currently move in
+func PossibleMoves(state *engine.GameState) []string {
+	if state == nil || state.GameOver || state.Battery <= 0 {
+		return nil
+	}
+	var moves []string
+	for _, dir := range Directions {
+		next := Step(state.PlayerPos, dir)
+		if state.CanMoveTo(next.X, next.Y) {
+			moves = append(moves, dir)
+		}
+	}
+	return moves
+}
+
+// Step returns the position reached by moving one cell in the given direction
+func Step(pos engine.Position, direction string) engine.Position {
+	switch direction {
+	case "up":
+		pos.Y--
+	case "down":
+		pos.Y++
+	case "left":
+		pos.X--
+	case "right":
+		pos.X++
+	}
+	return pos
+}
+
+// FollowPath returns the position reached by applying the directions in order
+func FollowPath(pos engine.Position, path []string) engine.Position {
+	for _, dir := range path {
+		pos = Step(pos, dir)
+	}
+	return pos
+}
+
+// PathToNearest runs a breadth-first search from the player's position and
+// returns the directions leading to the closest passable cell accepted by goal.
+// It returns an empty slice when the player already stands on a goal cell and
+// nil when no goal cell is reachable.
+func PathToNearest(state *engine.GameState, goal func(pos engine.Position, cell engine.Cell) bool) []string {
+	if state == nil {
+		return nil
+	}
+	return PathToNearestFrom(state, state.PlayerPos, goal)
+}
+
+// PathToNearestFrom is PathToNearest starting from an arbitrary position
+func PathToNearestFrom(state *engine.GameState, start engine.Position, goal func(pos engine.Position, cell engine.Cell) bool) []string {
+	if state == nil || !state.CanMoveTo(start.X, start.Y) {
+		return nil
+	}
+	if goal(start, state.Grid[start.Y][start.X]) {
+		return []string{}
+	}
+
+	type node struct {
+		pos  engine.Position
+		path []string
+	}
+
+	visited := map[engine.Position]bool{start: true}
+	queue := []node{{pos: start}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range Directions {
+			next := Step(current.pos, dir)
+			if visited[next] || !state.CanMoveTo(next.X, next.Y) {
+				continue
+			}
+			visited[next] = true
+
+			path := make([]string, len(current.path)+1)
+			copy(path, current.path)
+			path[len(current.path)] = dir
+
+			if goal(next, state.Grid[next.Y][next.X]) {
+				return path
+			}
+			queue = append(queue, node{pos: next, path: path})
+		}
+	}
+
+	return nil
+}
+
+// PathBetween returns the shortest path from start to goal, an empty slice
+// when they are the same cell and nil when goal is unreachable
+func PathBetween(state *engine.GameState, start, goal engine.Position) []string {
+	if start == goal {
+		return []string{}
+	}
+	return PathToNearestFrom(state, start, func(pos engine.Position, _ engine.Cell) bool {
+		return pos == goal
+	})
+}
+
+// NearestUnvisitedParkPath returns the shortest path to an unvisited park
+func NearestUnvisitedParkPath(state *engine.GameState) []string {
+	return PathToNearest(state, func(_ engine.Position, cell engine.Cell) bool {
+		return cell.Type == engine.Park && !cell.Visited
+	})
+}
+
+// NearestChargerPath returns the shortest path to a home or supercharger
+func NearestChargerPath(state *engine.GameState) []string {
+	return PathToNearest(state, isCharger)
+}
+
+// OnUncollectedPark reports whether the player stands on a park that still
+// needs the park action, as in configs that require one
+func OnUncollectedPark(state *engine.GameState) bool {
+	if state == nil || state.GameOver || !state.InBounds(state.PlayerPos.X, state.PlayerPos.Y) {
+		return false
+	}
+	cell := state.Grid[state.PlayerPos.Y][state.PlayerPos.X]
+	return cell.Type == engine.Park && !cell.Visited
+}
+
+func isCharger(_ engine.Position, cell engine.Cell) bool {
+	return engine.IsCharger(cell.Type)
+}
+
+func isHome(_ engine.Position, cell engine.Cell) bool {
+	return cell.Type == engine.Home
+}
@@ -0,0 +1,179 @@
+package strategy
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+func createTestEngine(t *testing.T, layout []string, battery int) *engine.GameEngine {
+	t.Helper()
+	config := &engine.GameConfig{
+		Name:            "Strategy Test",
+		Description:     "Configuration for strategy tests",
+		GridSize:        len(layout),
+		MaxBattery:      battery,
+		StartingBattery: battery,
+		Layout:          layout,
+		Legend: map[string]string{
+			"R": "road", "H": "home", "P": "park",
+			"S": "supercharger", "W": "water", "B": "building",
+		},
+	}
+	config.Messages.Welcome = "Welcome!"
+	config.Messages.ParkVisited = "Park visited! Score: %d"
+	config.Messages.Victory = "Victory! All %d parks visited!"
+	config.Messages.OutOfBattery = "Out of battery!"
+	config.Messages.BatteryStatus = "Battery: %d/%d"
+
+	eng, err := engine.NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	return eng
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"random", "greedy"} {
+		s, err := New(name)
+		if err != nil {
+			t.Fatalf("New(%q) returned error: %v", name, err)
+		}
+		if s.Name() != name {
+			t.Errorf("Expected name %q, got %q", name, s.Name())
+		}
+	}
+
+	if _, err := New("teleport"); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+}
+
+func TestPathToNearest(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBB",
+		"BHRRB",
+		"BWWRB",
+		"BPRRB",
+		"BBBBB",
+	}, 10)
+
+	path := NearestUnvisitedParkPath(eng.GetState())
+	expected := []string{"right", "right", "down", "down", "left", "left"}
+	if len(path) != len(expected) {
+		t.Fatalf("Expected path %v, got %v", expected, path)
+	}
+	for i := range expected {
+		if path[i] != expected[i] {
+			t.Fatalf("Expected path %v, got %v", expected, path)
+		}
+	}
+
+	// Standing on a charger yields an empty, non-nil path
+	if charger := NearestChargerPath(eng.GetState()); charger == nil || len(charger) != 0 {
+		t.Errorf("Expected empty path when already on charger, got %v", charger)
+	}
+}
+
+func TestPathToNearest_Unreachable(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBB",
+		"BHRBB",
+		"BWWWB",
+		"BPRRB",
+		"BBBBB",
+	}, 10)
+
+	if path := NearestUnvisitedParkPath(eng.GetState()); path != nil {
+		t.Errorf("Expected nil path for walled-off park, got %v", path)
+	}
+}
+
+func TestRandom_OnlyPossibleMoves(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBB",
+		"BHRPB",
+		"BWWWB",
+		"BRRRB",
+		"BBBBB",
+	}, 10)
+
+	r := NewRandom(rand.New(rand.NewSource(1)))
+	for i := 0; i < 20; i++ {
+		if dir := r.Next(eng.GetState()); dir != "right" {
+			t.Fatalf("Expected only possible move 'right', got %q", dir)
+		}
+	}
+}
+
+func TestGreedy_CollectsAllParks(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BPRRRPB",
+		"BRBBBRB",
+		"BRRHRRB",
+		"BRBBBRB",
+		"BPRRRPB",
+		"BBBBBBB",
+	}, 8)
+
+	g := &Greedy{}
+	for i := 0; i < 100 && !eng.IsGameOver(); i++ {
+		dir := g.Next(eng.GetState())
+		if dir == "" {
+			break
+		}
+		eng.Move(dir)
+	}
+
+	if !eng.IsVictory() {
+		t.Errorf("Expected greedy strategy to win, score=%d battery=%d", eng.GetScore(), eng.GetBattery())
+	}
+}
+
+func TestGreedy_ParksWhenRequired(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BPRRRPB",
+		"BRBBBRB",
+		"BRRHRRB",
+		"BRBBBRB",
+		"BPRRRPB",
+		"BBBBBBB",
+	}, 8)
+	eng.GetConfig().RequireParkAction = true
+
+	g := &Greedy{}
+	for i := 0; i < 100 && !eng.IsGameOver(); i++ {
+		dir := g.Next(eng.GetState())
+		if dir == "" {
+			break
+		}
+		eng.Move(dir)
+	}
+
+	if !eng.IsVictory() {
+		t.Errorf("Expected greedy strategy to park its way to victory, score=%d battery=%d", eng.GetScore(), eng.GetBattery())
+	}
+}
+
+func TestGreedy_RechargesWhenLow(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"BBBBBBB",
+		"BHRRRPB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+		"BBBBBBB",
+	}, 10)
+
+	state := eng.GetState()
+	state.PlayerPos = engine.Position{X: 2, Y: 1}
+	state.Battery = 2
+
+	if dir := (&Greedy{}).Next(state); dir != "left" {
+		t.Errorf("Expected greedy to head back to charger, got %q", dir)
+	}
+}
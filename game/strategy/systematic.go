@@ -1,46 +1,53 @@
-package main
+package strategy
 
 import (
 	"log"
 	"math"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
 )
 
-// SystematicStrategy plans complete park collection routes before execution
+// SystematicStrategy plans complete park collection routes before execution.
+// It keeps its route and charging commitments between calls, so create one
+// per game and call Reset before replaying from the start.
 type SystematicStrategy struct {
 	width       int
 	height      int
 	allParks    []ParkInfo
-	allChargers []Position
-	parkMap     map[Position]string // Position -> Park ID
+	allChargers []engine.Position
+	parkMap     map[engine.Position]string // Position -> Park ID
 
 	// Route planning
-	collectionOrder []Position // Planned park collection order
-	currentTarget   *Position  // Current park we're navigating to
-	targetIndex     int        // Index in collectionOrder
+	collectionOrder []engine.Position // Planned park collection order
+	currentTarget   *engine.Position  // Current park we're navigating to
+	targetIndex     int               // Index in collectionOrder
 
 	// Battery management
-	chargingTarget *Position // Charger we're committed to reaching
-	needsCharge    bool      // Flag to force charging mode
+	chargingTarget *engine.Position // Charger we're committed to reaching
+	needsCharge    bool             // Flag to force charging mode
 
 	// State tracking
-	visitedCells map[Position]int
+	visitedCells map[engine.Position]int
 	stuckCount   int
 	lastProgress int
 }
 
+// ParkInfo identifies a park on the grid
 type ParkInfo struct {
-	Pos Position
+	Pos engine.Position
 	ID  string
 }
 
-func NewSystematicStrategy(state *GameState) *SystematicStrategy {
+// NewSystematicStrategy scans the grid for parks and chargers and plans the
+// initial collection order
+func NewSystematicStrategy(state *engine.GameState) *SystematicStrategy {
 	s := &SystematicStrategy{
 		width:        len(state.Grid[0]),
 		height:       len(state.Grid),
 		allParks:     make([]ParkInfo, 0),
-		allChargers:  make([]Position, 0),
-		parkMap:      make(map[Position]string),
-		visitedCells: make(map[Position]int),
+		allChargers:  make([]engine.Position, 0),
+		parkMap:      make(map[engine.Position]string),
+		visitedCells: make(map[engine.Position]int),
 		targetIndex:  0,
 		stuckCount:   0,
 		lastProgress: 0,
@@ -50,12 +57,12 @@ func NewSystematicStrategy(state *GameState) *SystematicStrategy {
 	for y := 0; y < len(state.Grid); y++ {
 		for x := 0; x < len(state.Grid[0]); x++ {
 			cell := state.Grid[y][x]
-			pos := Position{X: x, Y: y}
+			pos := engine.Position{X: x, Y: y}
 
-			if cell.Type == "park" {
+			if cell.Type == engine.Park {
 				s.allParks = append(s.allParks, ParkInfo{Pos: pos, ID: cell.ID})
 				s.parkMap[pos] = cell.ID
-			} else if cell.Type == "home" || cell.Type == "supercharger" {
+			} else if engine.IsCharger(cell.Type) {
 				s.allChargers = append(s.allChargers, pos)
 			}
 		}
@@ -70,21 +77,21 @@ func NewSystematicStrategy(state *GameState) *SystematicStrategy {
 }
 
 // planCollectionOrder creates an optimized park collection sequence
-func (s *SystematicStrategy) planCollectionOrder(state *GameState) {
+func (s *SystematicStrategy) planCollectionOrder(state *engine.GameState) {
 	if len(s.allParks) == 0 {
 		return
 	}
 
 	// Build distance matrix once (optimization: use Manhattan for initial estimate)
-	distMatrix := make(map[Position]map[Position]int)
-	allPositions := []Position{state.PlayerPos}
+	distMatrix := make(map[engine.Position]map[engine.Position]int)
+	allPositions := []engine.Position{state.PlayerPos}
 	for _, park := range s.allParks {
 		allPositions = append(allPositions, park.Pos)
 	}
 
 	// Cache distances
 	for _, from := range allPositions {
-		distMatrix[from] = make(map[Position]int)
+		distMatrix[from] = make(map[engine.Position]int)
 		for _, to := range allPositions {
 			if from == to {
 				distMatrix[from][to] = 0
@@ -101,7 +108,7 @@ func (s *SystematicStrategy) planCollectionOrder(state *GameState) {
 		remaining[i] = true
 	}
 
-	s.collectionOrder = make([]Position, 0, len(s.allParks))
+	s.collectionOrder = make([]engine.Position, 0, len(s.allParks))
 	currentPos := state.PlayerPos
 	currentBattery := state.MaxBattery
 
@@ -159,7 +166,7 @@ func (s *SystematicStrategy) planCollectionOrder(state *GameState) {
 	}
 }
 
-func (s *SystematicStrategy) findNearestChargerDistance(pos Position) int {
+func (s *SystematicStrategy) findNearestChargerDistance(pos engine.Position) int {
 	minDist := math.MaxInt32
 	for _, chargerPos := range s.allChargers {
 		dist := s.manhattanDistance(pos, chargerPos)
@@ -170,11 +177,15 @@ func (s *SystematicStrategy) findNearestChargerDistance(pos Position) int {
 	return minDist
 }
 
-func (s *SystematicStrategy) NextMove(state *GameState) string {
+// NextMove returns the next direction to drive, or "" when no useful move exists
+func (s *SystematicStrategy) NextMove(state *engine.GameState) string {
+	if OnUncollectedPark(state) {
+		return engine.ActionPark
+	}
 	s.visitedCells[state.PlayerPos]++
 
 	cellType := state.Grid[state.PlayerPos.Y][state.PlayerPos.X].Type
-	isOnCharger := (cellType == "home" || cellType == "supercharger")
+	isOnCharger := engine.IsCharger(cellType)
 
 	// Check if we've reached charger and have sufficient charge
 	if s.chargingTarget != nil && isOnCharger {
@@ -190,9 +201,9 @@ func (s *SystematicStrategy) NextMove(state *GameState) string {
 	if s.chargingTarget != nil {
 		if isOnCharger {
 			// On charger but not full - wiggle to charge
-			for _, dir := range []string{"up", "down", "left", "right"} {
-				newPos := s.getNewPosition(state.PlayerPos, dir)
-				if s.isValidPosition(newPos, state) {
+			for _, dir := range Directions {
+				newPos := Step(state.PlayerPos, dir)
+				if state.CanMoveTo(newPos.X, newPos.Y) {
 					return dir
 				}
 			}
@@ -211,7 +222,7 @@ func (s *SystematicStrategy) NextMove(state *GameState) string {
 	batteryLow := state.Battery < (state.MaxBattery / 3)
 	if (batteryLow || s.needsCharge) && !isOnCharger && s.chargingTarget == nil {
 		// Find nearest charger and commit to it
-		var nearestCharger *Position
+		var nearestCharger *engine.Position
 		minDist := 999999
 		for _, chargerPos := range s.allChargers {
 			path := s.BFS(state.PlayerPos, chargerPos, state)
@@ -346,7 +357,7 @@ func (s *SystematicStrategy) NextMove(state *GameState) string {
 	}
 
 	// Only charge if we need more AND we're not already near full
-	if state.Battery < requiredBattery && state.Battery < (state.MaxBattery - 2) {
+	if state.Battery < requiredBattery && state.Battery < (state.MaxBattery-2) {
 		log.Printf("⚠️  Battery: %d < %d needed (%d to target + %d escape)",
 			state.Battery, requiredBattery, pathLength, nearestChargerFromTarget)
 		s.needsCharge = true
@@ -367,19 +378,22 @@ func (s *SystematicStrategy) NextMove(state *GameState) string {
 }
 
 // NextMoves returns up to maxMoves planned moves for efficient bulk execution
-func (s *SystematicStrategy) NextMoves(state *GameState, maxMoves int) []string {
+func (s *SystematicStrategy) NextMoves(state *engine.GameState, maxMoves int) []string {
+	if OnUncollectedPark(state) {
+		return []string{engine.ActionPark}
+	}
 	s.visitedCells[state.PlayerPos]++
 
 	// CRITICAL FIX: If standing on a charger with full battery, move off immediately
 	// This prevents infinite loops when bulk moves cross charger tiles
 	cellType := state.Grid[state.PlayerPos.Y][state.PlayerPos.X].Type
-	if (cellType == "home" || cellType == "supercharger") && state.Battery >= state.MaxBattery {
+	if engine.IsCharger(cellType) && state.Battery >= state.MaxBattery {
 		// Try to move to a non-charger position
-		for _, dir := range []string{"up", "down", "left", "right"} {
-			newPos := s.getNewPosition(state.PlayerPos, dir)
-			if s.isValidPosition(newPos, state) {
+		for _, dir := range Directions {
+			newPos := Step(state.PlayerPos, dir)
+			if state.CanMoveTo(newPos.X, newPos.Y) {
 				newCellType := state.Grid[newPos.Y][newPos.X].Type
-				if newCellType != "home" && newCellType != "supercharger" {
+				if !engine.IsCharger(newCellType) {
 					log.Printf("Moving off charger: %s", dir)
 					return []string{dir}
 				}
@@ -407,17 +421,17 @@ func (s *SystematicStrategy) NextMoves(state *GameState, maxMoves int) []string
 				}
 			}
 
-			newPos := s.getNewPosition(state.PlayerPos, preferredDir)
-			if s.isValidPosition(newPos, state) {
+			newPos := Step(state.PlayerPos, preferredDir)
+			if state.CanMoveTo(newPos.X, newPos.Y) {
 				log.Printf("Moving through charger field toward target: %s", preferredDir)
 				return []string{preferredDir}
 			}
 		}
 
 		// Last resort: any valid move
-		for _, dir := range []string{"up", "down", "left", "right"} {
-			newPos := s.getNewPosition(state.PlayerPos, dir)
-			if s.isValidPosition(newPos, state) {
+		for _, dir := range Directions {
+			newPos := Step(state.PlayerPos, dir)
+			if state.CanMoveTo(newPos.X, newPos.Y) {
 				log.Printf("Moving through chargers: %s", dir)
 				return []string{dir}
 			}
@@ -509,7 +523,7 @@ func (s *SystematicStrategy) NextMoves(state *GameState, maxMoves int) []string
 		if state.Battery < pathCost+safetyBuffer {
 			// Check if already on charger
 			cellType := state.Grid[state.PlayerPos.Y][state.PlayerPos.X].Type
-			if cellType == "home" || cellType == "supercharger" {
+			if engine.IsCharger(cellType) {
 				// Already charging - move off the charger first to avoid "charging" message loop
 				// Just return first move of path to target
 				if len(path) > 0 {
@@ -552,26 +566,13 @@ func (s *SystematicStrategy) NextMoves(state *GameState, maxMoves int) []string
 	return []string{}
 }
 
-func (s *SystematicStrategy) navigateToTarget(state *GameState, target Position) string {
-	path := s.BFS(state.PlayerPos, target, state)
-
-	if path != nil && len(path) > 0 {
-		return path[0]
-	}
-
-	// Try exploring if no direct path
-	return s.exploreMove(state)
-}
-
-func (s *SystematicStrategy) findDirectionToNearestCharger(state *GameState) string {
-	path := s.findPathToNearestCharger(state)
-	if len(path) > 0 {
-		return path[0]
-	}
-	return ""
+// BFS returns the shortest path between two positions ignoring battery, or nil
+// when goal is unreachable
+func (s *SystematicStrategy) BFS(start, goal engine.Position, state *engine.GameState) []string {
+	return PathBetween(state, start, goal)
 }
 
-func (s *SystematicStrategy) findPathToNearestCharger(state *GameState) []string {
+func (s *SystematicStrategy) findPathToNearestCharger(state *engine.GameState) []string {
 	var shortestPath []string
 	minDist := math.MaxInt32
 
@@ -586,7 +587,7 @@ func (s *SystematicStrategy) findPathToNearestCharger(state *GameState) []string
 	return shortestPath
 }
 
-func (s *SystematicStrategy) exploreMove(state *GameState) string {
+func (s *SystematicStrategy) exploreMove(state *engine.GameState) string {
 	// Try least visited direction
 	type DirScore struct {
 		dir   string
@@ -594,9 +595,9 @@ func (s *SystematicStrategy) exploreMove(state *GameState) string {
 	}
 
 	options := []DirScore{}
-	for _, dir := range []string{"up", "down", "left", "right"} {
-		newPos := s.getNewPosition(state.PlayerPos, dir)
-		if !s.isValidPosition(newPos, state) {
+	for _, dir := range Directions {
+		newPos := Step(state.PlayerPos, dir)
+		if !state.CanMoveTo(newPos.X, newPos.Y) {
 			continue
 		}
 
@@ -619,77 +620,7 @@ func (s *SystematicStrategy) exploreMove(state *GameState) string {
 	return best.dir
 }
 
-func (s *SystematicStrategy) BFS(start, goal Position, state *GameState) []string {
-	if start == goal {
-		return []string{}
-	}
-
-	type QueueItem struct {
-		pos  Position
-		path []string
-	}
-
-	queue := []QueueItem{{pos: start, path: []string{}}}
-	visited := make(map[Position]bool)
-	visited[start] = true
-
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
-
-		for _, dir := range []string{"up", "down", "left", "right"} {
-			newPos := s.getNewPosition(current.pos, dir)
-
-			if visited[newPos] || !s.isValidPosition(newPos, state) {
-				continue
-			}
-
-			newPath := append([]string{}, current.path...)
-			newPath = append(newPath, dir)
-
-			if newPos == goal {
-				return newPath
-			}
-
-			visited[newPos] = true
-			queue = append(queue, QueueItem{pos: newPos, path: newPath})
-		}
-	}
-
-	return nil
-}
-
-func (s *SystematicStrategy) isValidPosition(pos Position, state *GameState) bool {
-	if pos.Y < 0 || pos.Y >= len(state.Grid) || pos.X < 0 || pos.X >= len(state.Grid[0]) {
-		return false
-	}
-	cellType := state.Grid[pos.Y][pos.X].Type
-
-	// Water and buildings are always impassable
-	if cellType == "water" || cellType == "building" {
-		return false
-	}
-
-	// Allow movement through chargers - they're passable
-	// We'll handle the "charging" message at the API level
-	return true
-}
-
-func (s *SystematicStrategy) getNewPosition(pos Position, dir string) Position {
-	switch dir {
-	case "up":
-		return Position{X: pos.X, Y: pos.Y - 1}
-	case "down":
-		return Position{X: pos.X, Y: pos.Y + 1}
-	case "left":
-		return Position{X: pos.X - 1, Y: pos.Y}
-	case "right":
-		return Position{X: pos.X + 1, Y: pos.Y}
-	}
-	return pos
-}
-
-func (s *SystematicStrategy) manhattanDistance(a, b Position) int {
+func (s *SystematicStrategy) manhattanDistance(a, b engine.Position) int {
 	return abs(a.X-b.X) + abs(a.Y-b.Y)
 }
 
@@ -700,8 +631,9 @@ func abs(x int) int {
 	return x
 }
 
+// Reset clears per-attempt progress while keeping the planned collection order
 func (s *SystematicStrategy) Reset() {
-	s.visitedCells = make(map[Position]int)
+	s.visitedCells = make(map[engine.Position]int)
 	s.currentTarget = nil
 	s.targetIndex = 0
 	s.stuckCount = 0
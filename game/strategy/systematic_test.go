@@ -0,0 +1,126 @@
+package strategy
+
+import (
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// legacyBFS is the breadth-first search the bruteforcer shipped with before
+// it moved into this package, kept to check the shared pathfinding against
+func legacyBFS(start, goal engine.Position, state *engine.GameState) []string {
+	if start == goal {
+		return []string{}
+	}
+
+	type QueueItem struct {
+		pos  engine.Position
+		path []string
+	}
+
+	isValid := func(pos engine.Position) bool {
+		if pos.Y < 0 || pos.Y >= len(state.Grid) || pos.X < 0 || pos.X >= len(state.Grid[0]) {
+			return false
+		}
+		cellType := state.Grid[pos.Y][pos.X].Type
+		return cellType != "water" && cellType != "building"
+	}
+
+	queue := []QueueItem{{pos: start, path: []string{}}}
+	visited := make(map[engine.Position]bool)
+	visited[start] = true
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dir := range []string{"up", "down", "left", "right"} {
+			newPos := Step(current.pos, dir)
+
+			if visited[newPos] || !isValid(newPos) {
+				continue
+			}
+
+			newPath := append([]string{}, current.path...)
+			newPath = append(newPath, dir)
+
+			if newPos == goal {
+				return newPath
+			}
+
+			visited[newPos] = true
+			queue = append(queue, QueueItem{pos: newPos, path: newPath})
+		}
+	}
+
+	return nil
+}
+
+func TestSystematicBFS_MatchesLegacy(t *testing.T) {
+	eng := createTestEngine(t, []string{
+		"HRRWRRP",
+		"RBRWRBR",
+		"RBRRRBR",
+		"RBBBBBR",
+		"RRRPRWR",
+		"WWBRBWR",
+		"PRRRRRS",
+	}, 20)
+	state := eng.GetState()
+	s := &SystematicStrategy{}
+
+	compared := 0
+	for sy, row := range state.Grid {
+		for sx := range row {
+			start := engine.Position{X: sx, Y: sy}
+			if !state.CanMoveTo(sx, sy) {
+				continue
+			}
+			for gy, goalRow := range state.Grid {
+				for gx := range goalRow {
+					goal := engine.Position{X: gx, Y: gy}
+					want := legacyBFS(start, goal, state)
+					got := s.BFS(start, goal, state)
+					if !reflect.DeepEqual(got, want) {
+						t.Fatalf("BFS %v -> %v = %v, legacy returned %v", start, goal, got, want)
+					}
+					compared++
+				}
+			}
+		}
+	}
+	if compared == 0 {
+		t.Fatal("Expected to compare at least one path")
+	}
+}
+
+func TestSystematicStrategy_WinsShippedConfig(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	config, err := engine.LoadGameConfig("../../configs/classic.json")
+	if err != nil {
+		t.Fatalf("Failed to load classic config: %v", err)
+	}
+	eng, err := engine.NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+
+	s := NewSystematicStrategy(eng.GetState())
+	for moves := 0; moves < 3000 && !eng.IsGameOver(); moves++ {
+		direction := s.NextMove(eng.GetState())
+		if direction == "" {
+			break
+		}
+		eng.Move(direction)
+	}
+	if !eng.IsVictory() {
+		t.Fatalf("Expected systematic strategy to win, got score=%d battery=%d: %s",
+			eng.GetScore(), eng.GetBattery(), eng.GetState().Message)
+	}
+}
@@ -0,0 +1,10 @@
+// Package logtest provides helpers shared by the tests of several packages.
+//
+// Recorder is a service.Logger that keeps every message, so tests can assert
+// on what the API server, the game service or the session manager logged:
+//
+//	logger := &logtest.Recorder{}
+//	svc := service.NewGameService(sessions, configs, service.WithLogger(logger))
+//	...
+//	warnings := logger.Find("failed to persist session")
+package logtest
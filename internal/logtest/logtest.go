@@ -0,0 +1,48 @@
+package logtest
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Entry is a message recorded by Recorder
+type Entry struct {
+	Level string
+	Msg   string
+	Attrs map[string]any
+}
+
+// Recorder is a service.Logger that keeps every message, safe for logging
+// from server and timer goroutines
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (r *Recorder) record(level, msg string, args []any) {
+	attrs := make(map[string]any)
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[fmt.Sprint(args[i])] = args[i+1]
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, Entry{Level: level, Msg: msg, Attrs: attrs})
+}
+
+func (r *Recorder) Debug(msg string, args ...any) { r.record("DEBUG", msg, args) }
+func (r *Recorder) Info(msg string, args ...any)  { r.record("INFO", msg, args) }
+func (r *Recorder) Warn(msg string, args ...any)  { r.record("WARN", msg, args) }
+func (r *Recorder) Error(msg string, args ...any) { r.record("ERROR", msg, args) }
+
+// Find returns the recorded messages with msg, at any level
+func (r *Recorder) Find(msg string) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []Entry
+	for _, e := range r.entries {
+		if e.Msg == msg {
+			found = append(found, e)
+		}
+	}
+	return found
+}
@@ -4,11 +4,12 @@
 //  1. "server" (default) – runs the HTTP server exposing REST API, WebSocket, and an /mcp HTTP endpoint
 //  2. "stdio-mcp" – runs an MCP stdio server and spins up an internal HTTP API if none is available
 //
-// Flags control host/port, config directory, debug logging, version output,
+// Flags control host/port, config directory, debug logging, version output, CORS origins,
 // and optional ngrok tunneling for easy external access during development.
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
@@ -19,17 +20,21 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/wricardo/tesla-road-trip-game/api"
 	"github.com/wricardo/tesla-road-trip-game/game/config"
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
 	"github.com/wricardo/tesla-road-trip-game/game/service"
 	"github.com/wricardo/tesla-road-trip-game/game/session"
 	"github.com/wricardo/tesla-road-trip-game/transport/mcp"
+	"github.com/wricardo/tesla-road-trip-game/transport/webhook"
 	"github.com/wricardo/tesla-road-trip-game/transport/websocket"
 	"golang.ngrok.com/ngrok"
 	ngrokConfig "golang.ngrok.com/ngrok/config"
@@ -46,11 +51,25 @@ var (
 	port         = flag.Int("port", 8080, "HTTP server port")
 	host         = flag.String("host", "localhost", "HTTP server host")
 	configDir    = flag.String("config-dir", getConfigDirDefault(), "Directory containing game configurations")
-	debug        = flag.Bool("debug", false, "Enable debug logging")
+	debug        = flag.Bool("debug", false, "Enable debug logging, including per-request logs, and the debug endpoints")
 	version      = flag.Bool("version", false, "Show version information")
 	ngrokEnabled = flag.Bool("ngrok", false, "Enable ngrok tunnel")
 	ngrokAuth    = flag.String("ngrok-auth", "", "Ngrok auth token (or use NGROK_AUTHTOKEN env var)")
 	ngrokDomain  = flag.String("ngrok-domain", "", "Custom ngrok domain (optional)")
+	corsOrigin   = flag.String("cors-origin", "", "Comma-separated origins allowed to call the API, or * for any (default: localhost on any port)")
+	wsBuffer     = flag.Int("ws-buffer", websocket.DefaultSendBuffer, "Outgoing WebSocket messages queued per client")
+	wsOverflow   = flag.String("ws-overflow", websocket.OverflowDisconnect, "What to do with a WebSocket client whose queue is full: disconnect or drop_oldest")
+	saveEncoding = flag.String("session-encoding", session.EncodingJSON, "Encoding of saved session files: json (readable) or compact (binary, smaller)")
+	saveDebounce = flag.Duration("save-debounce", 500*time.Millisecond, "Minimum time between autosaves of a session while it is played (0 saves after every move)")
+	loopWindow   = flag.Int("loop-window", service.DefaultLoopWindow, "Recent moves inspected for agent loops that go nowhere (0 disables the watchdog)")
+	loopCells    = flag.Int("loop-cells", service.DefaultLoopMaxCells, "Most distinct cells a loop may cover to be flagged by the watchdog")
+	idemWindow   = flag.Duration("idempotency-window", service.DefaultIdempotencyWindow, "How long a move sent with an Idempotency-Key is remembered for retries (0 disables)")
+	eventLogSize = flag.Int("event-log-size", service.DefaultEventLogSize, "Recent events each session keeps for GET /api/sessions/{id}/eventlog (0 disables)")
+	saveEventLog = flag.Bool("persist-event-log", false, "Save each session's event log in its JSON session file")
+	verifyIgnore = flag.String("verify-ignore", "", "Comma-separated game state fields GET /api/sessions/{id}/verify leaves out of its comparison, e.g. message")
+	maxBody      = flag.Int64("max-body-bytes", api.DefaultMaxBodyBytes, "Largest API request body accepted; larger ones are answered with a 400")
+	strictJSON   = flag.Bool("strict-json", false, "Reject API request bodies with unknown fields instead of ignoring them")
+	maxGridSize  = flag.Int("max-grid-size", engine.MaxGridSize, fmt.Sprintf("Largest grid width or height a config may have (at most %d)", engine.GridSizeCeiling))
 )
 
 // getConfigDirDefault returns the default configuration directory.
@@ -117,36 +136,119 @@ func main() {
 
 	log.Printf("Starting %s v%s (mode: %s)", AppName, Version, mode)
 
+	// Create WebSocket hub; the service also pushes auto-resets through it
+	if *wsOverflow != websocket.OverflowDisconnect && *wsOverflow != websocket.OverflowDropOldest {
+		log.Fatalf("Unknown -ws-overflow %q. Use 'disconnect' or 'drop_oldest'", *wsOverflow)
+	}
+	hub := websocket.NewHubWithOptions(websocket.HubOptions{SendBuffer: *wsBuffer, Overflow: *wsOverflow})
+	go hub.Run()
+
 	// Initialize services
-	gameService, err := initializeServices()
+	gameService, webhooks, err := initializeServices(hub)
 	if err != nil {
 		log.Fatalf("Failed to initialize services: %v", err)
 	}
+	// Write sessions whose autosave is still debounced before exiting
+	if flusher, ok := gameService.(service.SaveFlusher); ok {
+		defer flusher.FlushSaves()
+	}
 
 	switch mode {
 	case "stdio-mcp", "mcp-stdio", "mcp":
 		// Run MCP stdio server with internal HTTP server
-		runStdioMCPWithInternalServer(gameService)
+		runStdioMCPWithInternalServer(gameService, hub, webhooks)
 		return
 
 	case "server", "http":
 		// Run HTTP server with API, WebSocket, and MCP endpoint
-		runHTTPServer(gameService)
+		runHTTPServer(gameService, hub, webhooks)
 
 	default:
 		log.Fatalf("Unknown mode: %s. Use 'server' (default) or 'stdio-mcp'", mode)
 	}
 }
 
+// mcpHTTPHandler serves MCP JSON-RPC messages posted over HTTP. A body holding
+// a JSON array is a batch: its messages are handled in order and their
+// responses returned as an array. Notifications get no response, so a request
+// made only of notifications is answered with 202 Accepted and no body.
+func mcpHTTPHandler(mcpServer *server.MCPServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read request", http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '[' {
+			writeMCPResponse(w, mcpServer.HandleMessage(r.Context(), body))
+			return
+		}
+
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			writeMCPResponse(w, mcpgo.NewJSONRPCError(mcpgo.RequestId{}, mcpgo.PARSE_ERROR, "Failed to parse batch", nil))
+			return
+		}
+		if len(batch) == 0 {
+			writeMCPResponse(w, mcpgo.NewJSONRPCError(mcpgo.RequestId{}, mcpgo.INVALID_REQUEST, "Empty batch", nil))
+			return
+		}
+		responses := make([]mcpgo.JSONRPCMessage, 0, len(batch))
+		for _, message := range batch {
+			if response := mcpServer.HandleMessage(r.Context(), message); response != nil {
+				responses = append(responses, response)
+			}
+		}
+		if len(responses) == 0 {
+			writeMCPResponse(w, nil)
+			return
+		}
+		writeMCPResponse(w, responses)
+	}
+}
+
+// writeMCPResponse writes a JSON-RPC response, or 202 Accepted when there is
+// none because the request was a notification
+func writeMCPResponse(w http.ResponseWriter, response any) {
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	responseData, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, "Failed to marshal response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(responseData)
+}
+
 // runHTTPServer starts the HTTP server with REST API, WebSocket hub, and an /mcp proxy endpoint.
 // If ngrok is enabled (via flag or environment), it also provisions a public tunnel.
-func runHTTPServer(gameService service.GameService) {
-	// Create WebSocket hub
-	hub := websocket.NewHub()
-	go hub.Run()
-
+func runHTTPServer(gameService service.GameService, hub *websocket.Hub, webhooks *webhook.Manager) {
 	// Create API server
 	apiServer := api.NewServer(gameService, hub)
+	apiServer.SetWebhooks(webhooks)
+	apiServer.SetRequestLogging(*debug)
+	apiServer.SetDebug(*debug)
+	apiServer.SetMaxBodyBytes(*maxBody)
+	apiServer.SetStrictJSON(*strictJSON)
+	if *corsOrigin != "" {
+		cors := api.DefaultCORSConfig()
+		cors.AllowedOrigins = strings.Split(*corsOrigin, ",")
+		for i := range cors.AllowedOrigins {
+			cors.AllowedOrigins[i] = strings.TrimSpace(cors.AllowedOrigins[i])
+		}
+		apiServer.SetCORSConfig(cors)
+		log.Printf("CORS allowed origins: %s", strings.Join(cors.AllowedOrigins, ", "))
+	}
 
 	// Setup HTTP server address
 	addr := fmt.Sprintf("%s:%d", *host, *port)
@@ -162,29 +264,7 @@ func runHTTPServer(gameService service.GameService) {
 	mainRouter.Handle("/", apiServer)
 
 	// Always add MCP endpoint for HTTP server
-	mainRouter.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "POST" {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			http.Error(w, "Failed to read request", http.StatusBadRequest)
-			return
-		}
-		defer r.Body.Close()
-
-		response := mcpClient.GetMCPServer().HandleMessage(r.Context(), body)
-
-		w.Header().Set("Content-Type", "application/json")
-		responseData, err := json.Marshal(response)
-		if err != nil {
-			http.Error(w, "Failed to marshal response", http.StatusInternalServerError)
-			return
-		}
-		w.Write(responseData)
-	})
+	mainRouter.HandleFunc("/mcp", mcpHTTPHandler(mcpClient.GetMCPServer()))
 
 	httpServer := &http.Server{
 		Addr:         addr,
@@ -313,20 +393,25 @@ func runHTTPServer(gameService service.GameService) {
 	log.Println("Server stopped")
 }
 
-// initializeServices wires session/config managers and the game service.
+// initializeServices wires session/config managers, webhooks and the game service,
+// which publishes auto-reset states to hub.
 // It also starts a background cleanup routine to prune stale sessions.
-func initializeServices() (service.GameService, error) {
+func initializeServices(hub *websocket.Hub) (service.GameService, *webhook.Manager, error) {
 	// Create config manager first (needed for persistence)
-	configManager, err := config.NewManager(*configDir)
+	configManager, err := config.NewManager(*configDir, config.WithMaxGridSize(*maxGridSize))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create config manager: %w", err)
+		return nil, nil, fmt.Errorf("failed to create config manager: %w", err)
 	}
 
 	// Create session persistence
 	sessionsDir := "sessions"
-	persistence, err := session.NewFilePersistence(sessionsDir, configManager)
+	fileOpts := []session.FileOption{session.WithEncoding(*saveEncoding)}
+	if *saveEventLog {
+		fileOpts = append(fileOpts, session.WithPersistedEventLog())
+	}
+	persistence, err := session.NewFilePersistence(sessionsDir, configManager, fileOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create session persistence: %w", err)
+		return nil, nil, fmt.Errorf("failed to create session persistence: %w", err)
 	}
 
 	// Create session manager with persistence
@@ -337,8 +422,28 @@ func initializeServices() (service.GameService, error) {
 		log.Printf("Warning: Failed to load persisted sessions: %v", err)
 	}
 
+	// Create webhook registry; deliveries run in the background
+	webhooks, err := webhook.NewManager("webhooks.json")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create webhook manager: %w", err)
+	}
+
+	var verifyIgnored []string
+	for _, field := range strings.Split(*verifyIgnore, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			verifyIgnored = append(verifyIgnored, field)
+		}
+	}
+
 	// Create game service
-	gameService := service.NewGameService(sessionManager, configManager)
+	gameService := service.NewGameService(sessionManager, configManager,
+		service.WithEventPublisher(webhooks),
+		service.WithEventPublisher(hub),
+		service.WithSaveDebounce(*saveDebounce),
+		service.WithLoopDetection(*loopWindow, *loopCells),
+		service.WithIdempotency(*idemWindow, service.DefaultIdempotencyKeys),
+		service.WithEventLog(*eventLogSize),
+		service.WithVerifyIgnore(verifyIgnored...))
 
 	// Start session cleanup routine
 	go sessionCleanupRoutine(sessionManager)
@@ -346,13 +451,14 @@ func initializeServices() (service.GameService, error) {
 	// Start filesystem sync routine
 	go filesystemSyncRoutine(sessionManager, persistence)
 
-	return gameService, nil
+	return gameService, webhooks, nil
 }
 
 // sessionCleanupRoutine periodically removes sessions that have not been accessed
-// within the provided retention window.
+// within their own TTL, or the 24h retention window for sessions without one.
+// It checks every minute so short session TTLs are honored promptly.
 func sessionCleanupRoutine(manager *session.Manager) {
-	ticker := time.NewTicker(1 * time.Hour)
+	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
 	for range ticker.C {
@@ -399,7 +505,7 @@ func filesystemSyncRoutine(manager *session.Manager, persistence session.Session
 // runStdioMCPWithInternalServer runs an MCP stdio server.
 // It tries to reuse an external API at http://localhost:8080; if unavailable, it
 // starts a minimal internal HTTP API bound to a random loopback port and targets that.
-func runStdioMCPWithInternalServer(gameService service.GameService) {
+func runStdioMCPWithInternalServer(gameService service.GameService, hub *websocket.Hub, webhooks *webhook.Manager) {
 	var baseURL string
 	var httpServer *http.Server
 	var listener net.Listener
@@ -431,12 +537,13 @@ func runStdioMCPWithInternalServer(gameService service.GameService) {
 
 		log.Printf("Starting internal HTTP server on %s for MCP stdio", internalAddr)
 
-		// Create WebSocket hub
-		hub := websocket.NewHub()
-		go hub.Run()
-
 		// Create API server
 		apiServer := api.NewServer(gameService, hub)
+		apiServer.SetWebhooks(webhooks)
+		apiServer.SetRequestLogging(*debug)
+		apiServer.SetDebug(*debug)
+		apiServer.SetMaxBodyBytes(*maxBody)
+		apiServer.SetStrictJSON(*strictJSON)
 
 		// Start internal HTTP server in background
 		httpServer = &http.Server{
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/transport/mcp"
+	"github.com/wricardo/tesla-road-trip-game/transport/websocket"
 )
 
 func TestConstants(t *testing.T) {
@@ -35,10 +42,11 @@ func TestInitializeServices(t *testing.T) {
 		t.Skip("Skipping test - configs directory not found")
 	}
 
-	gameService, err := initializeServices()
+	gameService, webhooks, err := initializeServices(websocket.NewHub())
 	if err != nil {
 		t.Fatalf("Failed to initialize services: %v", err)
 	}
+	defer webhooks.Close()
 
 	if gameService == nil {
 		t.Fatal("Expected game service to be initialized")
@@ -51,7 +59,7 @@ func TestInitializeServices_InvalidConfigDir(t *testing.T) {
 	*configDir = "/non/existent/path"
 	defer func() { *configDir = originalConfigDir }()
 
-	_, err := initializeServices()
+	_, _, err := initializeServices(websocket.NewHub())
 	if err == nil {
 		t.Error("Expected error for non-existent config directory")
 	}
@@ -94,9 +102,63 @@ func TestServiceInitialization(t *testing.T) {
 		t.Skip("Skipping test - configs directory not found")
 	}
 
-	_, err := initializeServices()
+	_, _, err := initializeServices(websocket.NewHub())
 	if err != nil {
 		// This is expected if configs are missing, but shouldn't panic
 		t.Logf("Service initialization failed as expected: %v", err)
 	}
 }
+
+func TestMCPHTTPHandler_Batch(t *testing.T) {
+	handler := mcpHTTPHandler(mcp.NewClient("http://localhost:0").GetMCPServer())
+	post := func(body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest("POST", "/mcp", strings.NewReader(body)))
+		return w
+	}
+
+	// Two requests and a notification yield two responses
+	w := post(`[
+		{"jsonrpc": "2.0", "id": 1, "method": "ping"},
+		{"jsonrpc": "2.0", "method": "notifications/initialized"},
+		{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}
+	]`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var responses []struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("Expected a JSON array, got %s: %v", w.Body.String(), err)
+	}
+	if len(responses) != 2 || responses[0].ID != 1 || responses[1].ID != 2 {
+		t.Fatalf("Expected responses for ids 1 and 2, got %s", w.Body.String())
+	}
+	if !strings.Contains(string(responses[1].Result), "annotated_bulk_move") {
+		t.Errorf("Expected the tool list in the second result, got %s", responses[1].Result)
+	}
+
+	// A single object still gets a single object back
+	w = post(`{"jsonrpc": "2.0", "id": 3, "method": "ping"}`)
+	if !strings.HasPrefix(w.Body.String(), "{") || !strings.Contains(w.Body.String(), `"id":3`) {
+		t.Errorf("Expected a single response object, got %s", w.Body.String())
+	}
+
+	// Notifications alone get no response body
+	for _, body := range []string{
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		`[{"jsonrpc": "2.0", "method": "notifications/initialized"}]`,
+	} {
+		if w := post(body); w.Code != http.StatusAccepted || w.Body.Len() != 0 {
+			t.Errorf("Expected 202 with no body for %s, got %d %q", body, w.Code, w.Body.String())
+		}
+	}
+
+	// An empty batch is an invalid request
+	if w := post(`[]`); !strings.Contains(w.Body.String(), "-32600") {
+		t.Errorf("Expected an invalid request error for an empty batch, got %s", w.Body.String())
+	}
+}
@@ -31,6 +31,10 @@ const cellIcons = {
     'home': '🏠',
     'park': '🌳',
     'supercharger': '⚡',
+    'trickle': '🔌',
+    'checkpoint': '🚩',
+    'key': '🔑',
+    'door': '🚪',
     'water': '💧',
     'building': '🏢',
     'road': '',
@@ -340,6 +344,10 @@ function renderGame() {
                 if (icon) {
                     if (cellData.type === 'park' && cellData.visited) {
                         cell.textContent = '✓';
+                    } else if (cellData.type === 'key' && cellData.visited) {
+                        cell.textContent = '';
+                    } else if (cellData.type === 'door' && (gameState.collected_keys || []).includes(cellData.id)) {
+                        cell.textContent = '🔓';
                     } else {
                         cell.textContent = icon;
                     }
@@ -695,6 +703,7 @@ function displayConfigurations(configs) {
                     <div>
                         <div class="config-title">${config.name} <span style="color: #888; font-size: 0.85em; font-weight: normal;">(${config.filename})</span></div>
                         <div style="font-size: 12px; color: #666; margin-top: 4px;">${config.description}</div>
+                        ${config.author || config.version ? `<div style="font-size: 11px; color: #888; margin-top: 2px;">${[config.author && 'by ' + config.author, config.version && 'v' + config.version].filter(Boolean).join(' · ')}</div>` : ''}
                     </div>
                     <div style="display: flex; gap: 8px; align-items: center;">
                         ${config.is_active ? '<span class="config-badge active">ACTIVE</span>' : ''}
@@ -1809,6 +1818,7 @@ function displaySessionList(sessions) {
                     <span class="session-id">${sessionId}</span>
                     <span class="session-status ${statusClass}">${statusIcon} ${statusText}</span>
                 </div>
+                ${session.label ? '<div class="session-label"></div>' : ''}
                 <div class="session-details">
                     <span class="session-detail">🗺️ ${session.config_name}</span>
                     <span class="session-detail">⚡ ${session.game_state?.battery || 0}/${session.game_state?.max_battery || 0}</span>
@@ -1818,6 +1828,11 @@ function displaySessionList(sessions) {
             </div>
         `;
 
+        // Labels are user input, so set them as text
+        if (session.label) {
+            itemEl.querySelector('.session-label').textContent = `🏷️ ${session.label}`;
+        }
+
         // Add event listener to checkbox after creating the element
         const checkbox = itemEl.querySelector('.session-checkbox');
         checkbox.addEventListener('click', (event) => {
@@ -36,12 +36,13 @@ func NewClient(baseURL string) *Client {
 	return c
 }
 
-// initMCPServer initializes the MCP server with all tools
+// initMCPServer initializes the MCP server with all tools and resources
 func (c *Client) initMCPServer() {
 	c.mcpServer = server.NewMCPServer(
 		"Tesla Road Trip Game",
 		"2.0.0",
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
 		server.WithInstructions(`Tesla Road Trip Game - MCP Interface
 
 This is a thin client that proxies all requests to the REST API server.
@@ -51,22 +52,33 @@ Visit all parks (P) to win. Your Tesla (T) starts with limited battery that depl
 
 AVAILABLE TOOLS:
 - game_state: Get current game state
+- possible_moves: Structured list of the directions you can drive, each with whether you can afford it and whether it reaches a charger or park
 - move: Single move (up/down/left/right) - requires intent explanation
 - bulk_move: Multiple moves at once - requires intent explanation
+- annotated_bulk_move: Multiple moves at once, each with its own optional intent
+- move_toward: One step along the shortest road to a cell, with the moves left to reach it
+- park: Collect the park you stand on (needed when the config requires a park action)
 - reset_game: Reset to initial state
+- give_up: End an unwinnable game as a defeat, keeping the session and history for review
 - move_history: View past moves
+- solve: Compute a full winning move plan from the current state
 - create_session: Create new game session
 - get_session: Get session details
 - list_sessions: List all active sessions
 - list_configs: List available configurations
-- game_instructions: Get comprehensive game instructions and rules
+- analyze_config: Difficulty metrics for a configuration
+- game_instructions: Get comprehensive game instructions and rules (pass session_id for its charger effects)
 - describe_cell: Get detailed info about a specific grid cell (helps verify R vs B vs W)
 
-NOTE: The 'intent' parameter on move/bulk_move tools serves as rubber duck debugging - explain your reasoning!`),
+AVAILABLE RESOURCES:
+- game://{session_id}/grid: The session's rendered grid and status, to attach as context without a tool call
+
+NOTE: The 'intent' parameter on move/bulk_move tools serves as rubber duck debugging - explain your reasoning! It is saved in move_history.`),
 	)
 
-	// Register all tools
+	// Register all tools and resources
 	c.registerTools()
+	c.registerResources()
 }
 
 // registerTools registers all MCP tools
@@ -138,12 +150,12 @@ func (c *Client) registerTools() {
 				},
 				"direction": map[string]interface{}{
 					"type":        "string",
-					"enum":        []string{"up", "down", "left", "right"},
-					"description": "Direction to move",
+					"enum":        []string{"up", "down", "left", "right", engine.ActionCharge, engine.ActionPark, engine.ActionWait},
+					"description": "Direction to move, charge to stay on a charger and charge there, park to collect the park you stand on, or wait to stay put for a turn",
 				},
 				"intent": map[string]interface{}{
 					"type":        "string",
-					"description": "Brief explanation of the intent behind this move (serves as a rubber duck to help explain your reasoning)",
+					"description": "Brief explanation of the intent behind this move (serves as a rubber duck to help explain your reasoning; recorded in move history, up to 200 characters)",
 				},
 				"reset": map[string]interface{}{
 					"type":        "boolean",
@@ -168,23 +180,144 @@ func (c *Client) registerTools() {
 					"type": "array",
 					"items": map[string]interface{}{
 						"type": "string",
-						"enum": []string{"up", "down", "left", "right"},
+						"enum": []string{"up", "down", "left", "right", engine.ActionCharge, engine.ActionPark, engine.ActionWait},
 					},
-					"description": "Array of moves",
+					"description": "Array of moves; charge stays in place on a charger, park collects the park you stand on and wait stays put for a turn",
 				},
 				"intent": map[string]interface{}{
 					"type":        "string",
-					"description": "Brief explanation of the intent behind this sequence of moves (serves as a rubber duck to help explain your reasoning)",
+					"description": "Brief explanation of the intent behind this sequence of moves (serves as a rubber duck to help explain your reasoning; recorded on the first move in history, up to 200 characters)",
 				},
 				"reset": map[string]interface{}{
 					"type":        "boolean",
 					"description": "Reset before moving",
 				},
+				"continue_on_block": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep executing after a move into a wall or the boundary (default false). Blocked moves cost no battery unless the config sets a wall-crash penalty and are reported as failed steps, but every following move runs from wherever you actually are, so a plan that assumed the blocked move succeeded may drift off course. Leave false to stop at the first block and replan.",
+				},
+				"stop_below_battery": map[string]interface{}{
+					"type":        "integer",
+					"description": "Stop early, with stop_reason_code low_battery, as soon as a move leaves the battery below this level so you can reassess (default 0, disabled)",
+				},
 			},
 			Required: []string{"session_id", "moves"},
 		},
 	}, c.handleBulkMove)
 
+	c.mcpServer.AddTool(mcp.Tool{
+		Name:        "possible_moves",
+		Description: "List the directions you can drive from where you stand, as structured data: each with the destination's cell character, whether the battery affords the move without stranding you, and whether it leads to a charger or an uncollected park. Returns an empty list with an explanation once the game is over.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID",
+				},
+			},
+			Required: []string{"session_id"},
+		},
+	}, c.handlePossibleMoves)
+
+	c.mcpServer.AddTool(mcp.Tool{
+		Name:        "annotated_bulk_move",
+		Description: "Execute multiple moves in sequence like bulk_move, with an optional intent for each move recorded alongside its step",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID",
+				},
+				"steps": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"direction": map[string]interface{}{
+								"type": "string",
+								"enum": []string{"up", "down", "left", "right", engine.ActionCharge, engine.ActionPark, engine.ActionWait},
+							},
+							"intent": map[string]interface{}{
+								"type":        "string",
+								"description": "Optional reason for this move (recorded in move history, up to 200 characters)",
+							},
+						},
+						"required": []string{"direction"},
+					},
+					"description": "Moves to execute in order, each with an optional intent",
+				},
+				"reset": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Reset before moving",
+				},
+				"continue_on_block": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Keep executing after a move into a wall or the boundary (default false); see bulk_move",
+				},
+				"stop_below_battery": map[string]interface{}{
+					"type":        "integer",
+					"description": "Stop early as soon as a move leaves the battery below this level (default 0, disabled)",
+				},
+			},
+			Required: []string{"session_id", "steps"},
+		},
+	}, c.handleAnnotatedBulkMove)
+
+	c.mcpServer.AddTool(mcp.Tool{
+		Name:        "move_toward",
+		Description: "Take one step along the shortest road to a cell, ignoring battery, and report how many moves are left to reach it. Call it repeatedly to drive there. Fails without moving when no road leads to the cell.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID",
+				},
+				"x": map[string]interface{}{
+					"type":        "integer",
+					"description": "X coordinate (column) of the target cell (0-based)",
+				},
+				"y": map[string]interface{}{
+					"type":        "integer",
+					"description": "Y coordinate (row) of the target cell (0-based)",
+				},
+			},
+			Required: []string{"session_id", "x", "y"},
+		},
+	}, c.handleMoveToward)
+
+	c.mcpServer.AddTool(mcp.Tool{
+		Name:        "park",
+		Description: "Collect the uncollected park you are standing on. Configs with require_park_action only collect parks this way; entering a park just reaches it. Costs no battery.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID",
+				},
+			},
+			Required: []string{"session_id"},
+		},
+	}, c.handlePark)
+
+	c.mcpServer.AddTool(mcp.Tool{
+		Name:        "wait",
+		Description: "Stay put for a turn without moving. Costs the config's wait_cost battery (free by default); running the battery out away from a charger ends the game.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID",
+				},
+			},
+			Required: []string{"session_id"},
+		},
+	}, c.handleWait)
+
 	c.mcpServer.AddTool(mcp.Tool{
 		Name:        "reset_game",
 		Description: "Reset the game to initial state",
@@ -200,6 +333,21 @@ func (c *Client) registerTools() {
 		},
 	}, c.handleReset)
 
+	c.mcpServer.AddTool(mcp.Tool{
+		Name:        "give_up",
+		Description: "Surrender the game when it can no longer be won. It ends as a defeat with the surrendered reason; the session and its history are kept, and further moves are refused until reset_game.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID",
+				},
+			},
+			Required: []string{"session_id"},
+		},
+	}, c.handleGiveUp)
+
 	c.mcpServer.AddTool(mcp.Tool{
 		Name:        "move_history",
 		Description: "Get move history for a session",
@@ -223,6 +371,21 @@ func (c *Client) registerTools() {
 		},
 	}, c.handleMoveHistory)
 
+	c.mcpServer.AddTool(mcp.Tool{
+		Name:        "solve",
+		Description: "Compute a complete winning move sequence from the current state, respecting battery and charging. Does not play any moves; submit the plan with bulk_move. Reports when no plan exists or the search budget runs out.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Session ID",
+				},
+			},
+			Required: []string{"session_id"},
+		},
+	}, c.handleSolve)
+
 	c.mcpServer.AddTool(mcp.Tool{
 		Name:        "list_configs",
 		Description: "List available game configurations",
@@ -232,18 +395,38 @@ func (c *Client) registerTools() {
 		},
 	}, c.handleListConfigs)
 
+	c.mcpServer.AddTool(mcp.Tool{
+		Name:        "analyze_config",
+		Description: "Analyze a configuration's difficulty: charger coverage, park distances and unreachable cells",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"config_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Configuration ID (from list_configs)",
+				},
+			},
+			Required: []string{"config_name"},
+		},
+	}, c.handleAnalyzeConfig)
+
 	c.mcpServer.AddTool(mcp.Tool{
 		Name:        "game_instructions",
-		Description: "Get comprehensive game instructions and rules",
+		Description: "Get comprehensive game instructions and rules. Pass a session ID to also get how that session's chargers charge.",
 		InputSchema: mcp.ToolInputSchema{
-			Type:       "object",
-			Properties: map[string]interface{}{},
+			Type: "object",
+			Properties: map[string]interface{}{
+				"session_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional session ID whose charger effects to describe",
+				},
+			},
 		},
 	}, c.handleGameInstructions)
 
 	c.mcpServer.AddTool(mcp.Tool{
 		Name:        "describe_cell",
-		Description: "Get detailed information about a specific cell in the grid, including its exact character type. Useful for verifying whether a cell is passable (R, H, P, S) or impassable (W, B).",
+		Description: "Get detailed information about a specific cell in the grid, including its exact character type. Useful for verifying whether a cell is passable (R, H, P, S, C) or impassable (W, B), and how a charger charges.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -265,6 +448,20 @@ func (c *Client) registerTools() {
 	}, c.handleDescribeCell)
 }
 
+// gridResourceTemplate is the URI of a session's grid resource
+const gridResourceTemplate = "game://{session_id}/grid"
+
+// registerResources registers all MCP resources
+func (c *Client) registerResources() {
+	c.mcpServer.AddResourceTemplate(
+		mcp.NewResourceTemplate(gridResourceTemplate, "Session grid",
+			mcp.WithTemplateDescription("The session's rendered grid with position, battery, score and status, as read by game_state"),
+			mcp.WithTemplateMIMEType("text/plain"),
+		),
+		c.handleGridResource,
+	)
+}
+
 // GetMCPServer returns the underlying MCP server for serving
 func (c *Client) GetMCPServer() *server.MCPServer {
 	return c.mcpServer
@@ -315,6 +512,43 @@ func (c *Client) apiCall(method, path string, body interface{}, result interface
 	return nil
 }
 
+// Resource handlers
+
+// handleGridResource renders a session's grid and status. Errors, such as
+// an unknown session, are returned as the resource's text so an agent that
+// attached it sees why it is empty.
+func (c *Client) handleGridResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	sessionID := resourceArgument(request.Params.Arguments, "session_id")
+	var text string
+	var state engine.GameState
+	if sessionID == "" {
+		text = "Error: the resource URI needs a session ID, e.g. game://abc123/grid"
+	} else if err := c.apiCall("GET", fmt.Sprintf("/api/sessions/%s/state", sessionID), nil, &state); err != nil {
+		text = fmt.Sprintf("Error: can't read the grid of session %s: %v", sessionID, err)
+	} else {
+		text = fmt.Sprintf("Session: %s\n%s", sessionID, formatGameState(&state))
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      request.Params.URI,
+		MIMEType: "text/plain",
+		Text:     text,
+	}}, nil
+}
+
+// resourceArgument returns a variable matched from a resource template URI,
+// which the server passes as a list of values
+func resourceArgument(args map[string]any, name string) string {
+	switch v := args[name].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
 // Tool handlers
 
 func (c *Client) handleCreateSession(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -349,8 +583,12 @@ func (c *Client) handleListSessions(ctx context.Context, request mcp.CallToolReq
 
 	result := fmt.Sprintf("Active Sessions (%d):\n\n", response.Count)
 	for _, s := range response.Sessions {
-		result += fmt.Sprintf("- %s (Config: %s, Created: %s)\n",
-			s.ID, s.ConfigName, s.CreatedAt.Format("15:04:05"))
+		label := ""
+		if s.Label != "" {
+			label = fmt.Sprintf(" %q", s.Label)
+		}
+		result += fmt.Sprintf("- %s%s (Config: %s, Created: %s)\n",
+			s.ID, label, s.ConfigName, s.CreatedAt.Format("15:04:05"))
 	}
 
 	return mcp.NewToolResultText(result), nil
@@ -391,12 +629,11 @@ func (c *Client) handleMove(ctx context.Context, request mcp.CallToolRequest) (*
 	intent, _ := args["intent"].(string)
 	reset, _ := args["reset"].(bool)
 
-	// Intent parameter serves as rubber duck debugging - we don't need to process it further
-	_ = intent
-
+	// Intent is recorded on the move history for later debugging
 	body := map[string]interface{}{
 		"direction": direction,
 		"reset":     reset,
+		"intent":    intent,
 	}
 
 	var result service.MoveResult
@@ -409,15 +646,63 @@ func (c *Client) handleMove(ctx context.Context, request mcp.CallToolRequest) (*
 	return mcp.NewToolResultText(response), nil
 }
 
+func (c *Client) handleMoveToward(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.Params.Arguments.(map[string]interface{})
+	sessionID, _ := args["session_id"].(string)
+	x, _ := args["x"].(float64)
+	y, _ := args["y"].(float64)
+
+	body := map[string]interface{}{
+		"x": int(x),
+		"y": int(y),
+	}
+
+	var result service.MoveTowardResult
+	err := c.apiCall("POST", fmt.Sprintf("/api/sessions/%s/move-toward", sessionID), body, &result)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response := fmt.Sprintf("Moved %s toward (%d,%d): %d moves left to reach it\n",
+		result.Direction, result.Target.X, result.Target.Y, result.RemainingPath)
+	response += formatMoveResult(result.MoveResult)
+	return mcp.NewToolResultText(response), nil
+}
+
+func (c *Client) handlePark(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.Params.Arguments.(map[string]interface{})
+	sessionID, _ := args["session_id"].(string)
+
+	var result service.MoveResult
+	err := c.apiCall("POST", fmt.Sprintf("/api/sessions/%s/park", sessionID), nil, &result)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(formatMoveResult(&result)), nil
+}
+
+func (c *Client) handleWait(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.Params.Arguments.(map[string]interface{})
+	sessionID, _ := args["session_id"].(string)
+
+	var result service.MoveResult
+	err := c.apiCall("POST", fmt.Sprintf("/api/sessions/%s/wait", sessionID), nil, &result)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(formatMoveResult(&result)), nil
+}
+
 func (c *Client) handleBulkMove(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.Params.Arguments.(map[string]interface{})
 	sessionID, _ := args["session_id"].(string)
 	movesRaw, _ := args["moves"].([]interface{})
 	intent, _ := args["intent"].(string)
 	reset, _ := args["reset"].(bool)
-
-	// Intent parameter serves as rubber duck debugging - we don't need to process it further
-	_ = intent
+	continueOnBlock, _ := args["continue_on_block"].(bool)
+	stopBelow, _ := args["stop_below_battery"].(float64)
 
 	// Convert moves to string array
 	moves := make([]string, 0, len(movesRaw))
@@ -428,8 +713,11 @@ func (c *Client) handleBulkMove(ctx context.Context, request mcp.CallToolRequest
 	}
 
 	body := map[string]interface{}{
-		"moves": moves,
-		"reset": reset,
+		"moves":              moves,
+		"reset":              reset,
+		"continue_on_block":  continueOnBlock,
+		"stop_below_battery": int(stopBelow),
+		"intent":             intent,
 	}
 
 	var result service.BulkMoveResult
@@ -442,6 +730,45 @@ func (c *Client) handleBulkMove(ctx context.Context, request mcp.CallToolRequest
 	return mcp.NewToolResultText(response), nil
 }
 
+func (c *Client) handleAnnotatedBulkMove(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.Params.Arguments.(map[string]interface{})
+	sessionID, _ := args["session_id"].(string)
+	stepsRaw, _ := args["steps"].([]interface{})
+	reset, _ := args["reset"].(bool)
+	continueOnBlock, _ := args["continue_on_block"].(bool)
+	stopBelow, _ := args["stop_below_battery"].(float64)
+
+	// Split the steps into the parallel moves and intents the API takes
+	moves := make([]string, 0, len(stepsRaw))
+	intents := make([]string, 0, len(stepsRaw))
+	for i, s := range stepsRaw {
+		step, _ := s.(map[string]interface{})
+		direction, _ := step["direction"].(string)
+		if direction == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("step %d has no direction", i+1)), nil
+		}
+		intent, _ := step["intent"].(string)
+		moves = append(moves, direction)
+		intents = append(intents, intent)
+	}
+
+	body := map[string]interface{}{
+		"moves":              moves,
+		"intents":            intents,
+		"reset":              reset,
+		"continue_on_block":  continueOnBlock,
+		"stop_below_battery": int(stopBelow),
+	}
+
+	var result service.BulkMoveResult
+	err := c.apiCall("POST", fmt.Sprintf("/api/sessions/%s/bulk-move", sessionID), body, &result)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(formatBulkMoveResult(sessionID, &result)), nil
+}
+
 func (c *Client) handleReset(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.Params.Arguments.(map[string]interface{})
 	sessionID, _ := args["session_id"].(string)
@@ -460,6 +787,24 @@ func (c *Client) handleReset(ctx context.Context, request mcp.CallToolRequest) (
 	return mcp.NewToolResultText(result), nil
 }
 
+func (c *Client) handleGiveUp(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.Params.Arguments.(map[string]interface{})
+	sessionID, _ := args["session_id"].(string)
+
+	var response struct {
+		Message string            `json:"message"`
+		State   *engine.GameState `json:"state"`
+	}
+
+	err := c.apiCall("POST", fmt.Sprintf("/api/sessions/%s/surrender", sessionID), nil, &response)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := fmt.Sprintf("%s\n\n%s", response.Message, formatGameState(response.State))
+	return mcp.NewToolResultText(result), nil
+}
+
 func (c *Client) handleMoveHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.Params.Arguments.(map[string]interface{})
 	sessionID, _ := args["session_id"].(string)
@@ -491,6 +836,32 @@ func (c *Client) handleMoveHistory(ctx context.Context, request mcp.CallToolRequ
 	return mcp.NewToolResultText(result), nil
 }
 
+func (c *Client) handlePossibleMoves(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.Params.Arguments.(map[string]interface{})
+	sessionID, _ := args["session_id"].(string)
+
+	var state engine.GameState
+	err := c.apiCall("GET", fmt.Sprintf("/api/sessions/%s/state", sessionID), nil, &state)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultStructuredOnly(listPossibleMoves(&state)), nil
+}
+
+func (c *Client) handleSolve(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.Params.Arguments.(map[string]interface{})
+	sessionID, _ := args["session_id"].(string)
+
+	var result service.SolveResult
+	err := c.apiCall("POST", fmt.Sprintf("/api/sessions/%s/solve", sessionID), nil, &result)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(formatSolveResult(&result)), nil
+}
+
 func (c *Client) handleListConfigs(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var configs []service.ConfigInfo
 	err := c.apiCall("GET", "/api/configs", nil, &configs)
@@ -500,14 +871,40 @@ func (c *Client) handleListConfigs(ctx context.Context, request mcp.CallToolRequ
 
 	result := "Available Configurations:\n\n"
 	for _, config := range configs {
-		result += fmt.Sprintf("• %s\n  %s\n  Grid: %dx%d, Battery: %d\n\n",
-			config.Name, config.Description, config.GridSize, config.GridSize, config.MaxBattery)
+		result += fmt.Sprintf("• %s\n  %s\n  Grid: %dx%d, Battery: %d, Difficulty: %d/100\n",
+			config.Name, config.Description, config.GridWidth, config.GridHeight, config.MaxBattery, config.Difficulty)
+		if config.Author != "" {
+			result += fmt.Sprintf("  Author: %s\n", config.Author)
+		}
+		if config.Version != "" {
+			result += fmt.Sprintf("  Version: %s\n", config.Version)
+		}
+		if config.Notes != "" {
+			result += fmt.Sprintf("  Notes: %s\n", config.Notes)
+		}
+		result += "\n"
 	}
 
 	return mcp.NewToolResultText(result), nil
 }
 
+func (c *Client) handleAnalyzeConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.Params.Arguments.(map[string]interface{})
+	configName, _ := args["config_name"].(string)
+
+	var analysis engine.ConfigAnalysis
+	err := c.apiCall("GET", fmt.Sprintf("/api/configs/%s/analysis", configName), nil, &analysis)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return mcp.NewToolResultText(formatConfigAnalysis(&analysis)), nil
+}
+
 func (c *Client) handleGameInstructions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, _ := request.Params.Arguments.(map[string]interface{})
+	sessionID, _ := args["session_id"].(string)
+
 	instructions := `🎮 Tesla Road Trip Game - Complete Instructions
 
 GAME OBJECTIVE:
@@ -525,6 +922,9 @@ GRID LEGEND:
 • H - Home (passable, charging station, represents your home base/garage)
 • P - Park (passable, collectible objective)
 • S - Supercharger (passable, charging station)
+• C - Trickle charger (passable, charging station that adds a little battery per charge)
+• Y - Key (passable, opens the doors that share its id)
+• D - Door (impassable until its key is collected)
 • W - Water (impassable obstacle) ⚠️ Do NOT confuse with R
 • B - Building (impassable obstacle) ⚠️ Do NOT confuse with R
 • ✓ - Visited park (shows completed objectives)
@@ -614,6 +1014,11 @@ MOVEMENT COMMANDS:
 CHARGING LOCATIONS:
 - Home tiles (H): Your Tesla garage/base, provides full charge
 - Superchargers (S): Public charging stations, provide full charge
+- Trickle chargers (C): Slow charging stations, add 1 battery per charge
+- Configs may change how each kind charges, and may hold you on a charger
+  for a few turns after it charges; pass session_id to see the session's effects
+- Some configs only top up a little as you drive through a charger; wait on
+  it for a turn to charge fully
 
 VICTORY CONDITIONS:
 - Visit ALL parks in the grid to achieve victory
@@ -639,6 +1044,14 @@ Remember: Success requires meticulous character recognition, systematic mapping,
 
 Good luck navigating your Tesla Road Trip! 🚗⚡🌳`
 
+	if sessionID != "" {
+		var cfg service.SessionConfig
+		if err := c.apiCall("GET", fmt.Sprintf("/api/sessions/%s/config", sessionID), nil, &cfg); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		instructions += formatChargerEffects(sessionID, &cfg.GameConfig)
+	}
+
 	return mcp.NewToolResultText(instructions), nil
 }
 
@@ -656,10 +1069,15 @@ func (c *Client) handleDescribeCell(ctx context.Context, request mcp.CallToolReq
 	}
 
 	// Check bounds
-	gridSize := len(state.Grid)
-	if x < 0 || x >= gridSize || y < 0 || y >= gridSize {
-		return mcp.NewToolResultError(fmt.Sprintf("Coordinates (%d, %d) are out of bounds. Grid size is %dx%d (0-%d for both x and y)",
-			x, y, gridSize, gridSize, gridSize-1)), nil
+	width, height := gridDimensions(&state)
+	if !state.InBounds(x, y) {
+		return mcp.NewToolResultError(fmt.Sprintf("Coordinates (%d, %d) are out of bounds. Grid size is %dx%d (x 0-%d, y 0-%d)",
+			x, y, width, height, width-1, height-1)), nil
+	}
+
+	var cfg service.SessionConfig
+	if err := c.apiCall("GET", fmt.Sprintf("/api/sessions/%s/config", sessionID), nil, &cfg); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Get cell information
@@ -694,7 +1112,7 @@ func (c *Client) handleDescribeCell(ctx context.Context, request mcp.CallToolReq
 		cellType = "Home"
 		passable = true
 		if description == "" {
-			description = "Home/Garage - provides full battery charge"
+			description = "Home/Garage - " + describeChargerEffect(engine.Home, &cfg.GameConfig)
 		}
 	case engine.Park:
 		if cell.Visited {
@@ -722,7 +1140,63 @@ func (c *Client) handleDescribeCell(ctx context.Context, request mcp.CallToolReq
 		cellType = "Supercharger"
 		passable = true
 		if description == "" {
-			description = "Supercharger station - provides full battery charge"
+			description = "Supercharger station - " + describeChargerEffect(engine.Supercharger, &cfg.GameConfig)
+		}
+	case engine.Trickle:
+		if cellChar == "" {
+			cellChar = "C"
+		}
+		cellType = "Trickle charger"
+		passable = true
+		if description == "" {
+			description = "Trickle charger - " + describeChargerEffect(engine.Trickle, &cfg.GameConfig)
+		}
+	case engine.Fuel:
+		if cellChar == "" {
+			cellChar = "F"
+		}
+		cellType = "Fuel"
+		passable = true
+		if description == "" {
+			description = "Fuel pickup - adds battery once, then becomes road"
+		}
+	case engine.Checkpoint:
+		if cellChar == "" {
+			cellChar = "K"
+		}
+		cellType = "Checkpoint"
+		passable = true
+		if description == "" {
+			description = "Checkpoint - running out of battery later respawns you here while lives remain"
+		}
+	case engine.Key:
+		if cellChar == "" {
+			cellChar = "Y"
+		}
+		cellType = "Key"
+		if cell.Visited {
+			cellType = "Key (Collected)"
+		}
+		passable = true
+		if description == "" {
+			description = fmt.Sprintf("Key %q - collecting it opens every door with the same id", cell.ID)
+		}
+	case engine.Door:
+		if cellChar == "" {
+			cellChar = "D"
+		}
+		passable = state.DoorOpen(x, y)
+		if passable {
+			cellType = "Door (Unlocked)"
+		} else {
+			cellType = "Door (Locked)"
+		}
+		if description == "" {
+			if passable {
+				description = fmt.Sprintf("Door %q - unlocked, its key has been collected", cell.ID)
+			} else {
+				description = fmt.Sprintf("Door %q - LOCKED, impassable until its key is collected", cell.ID)
+			}
 		}
 	case engine.Water:
 		if cellChar == "" {
@@ -770,6 +1244,40 @@ IMPORTANT: The character '%s' is what appears in the grid display.
 	return mcp.NewToolResultText(result), nil
 }
 
+// describeChargerEffect says how chargers of type t charge under config
+func describeChargerEffect(t engine.CellType, config *engine.GameConfig) string {
+	effect := engine.ChargerEffectFor(t, config)
+	desc := "provides full battery charge"
+	switch {
+	case !effect.Instant:
+		desc = fmt.Sprintf("adds %d battery per charge", effect.Charge)
+	case config.ChargeRequiresWait:
+		desc = fmt.Sprintf("adds only %d battery when you drive through; wait here for a turn to charge fully", engine.PassThroughCharge)
+	}
+	if effect.MovePenalty > 0 {
+		desc += fmt.Sprintf(", then holds you there for %d turns before you can drive off", effect.MovePenalty)
+	}
+	return desc
+}
+
+// formatChargerEffects lists how each kind of charger on the session's
+// layout charges
+func formatChargerEffects(sessionID string, config *engine.GameConfig) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\nCHARGER EFFECTS (session %s):\n", sessionID)
+	layout := strings.Join(config.Layout, "")
+	for _, kind := range []struct {
+		char rune
+		name string
+		t    engine.CellType
+	}{{'H', "Home (H)", engine.Home}, {'S', "Supercharger (S)", engine.Supercharger}, {'C', "Trickle charger (C)", engine.Trickle}} {
+		if strings.ContainsRune(layout, kind.char) {
+			fmt.Fprintf(&b, "- %s: %s\n", kind.name, describeChargerEffect(kind.t, config))
+		}
+	}
+	return b.String()
+}
+
 func getCharacterReminder(char string) string {
 	switch char {
 	case "R":
@@ -784,6 +1292,16 @@ func getCharacterReminder(char string) string {
 		return "✅ This is a charging location (Supercharger) - safe to move here and will restore battery!"
 	case "P":
 		return "🎯 This is an objective (Park) - you need to visit all parks to win!"
+	case "C":
+		return "✅ This is a charging location (Trickle charger) - safe to move here, but it charges slowly!"
+	case "F":
+		return "⛽ This is a fuel pickup - it adds battery once and then becomes road."
+	case "Y":
+		return "🔑 This is a key - driving over it opens every door with the same id."
+	case "D":
+		return "🚪 This is a door - IMPASSABLE until you collect its key."
+	case "K":
+		return "🚩 This is a checkpoint - while you have lives left, running out of battery respawns you at the last one reached."
 	case "✓":
 		return "✅ This park has already been visited."
 	case "T":
@@ -796,8 +1314,12 @@ func getCharacterReminder(char string) string {
 // Formatting helpers
 
 func formatSessionInfo(session *service.SessionInfo) string {
-	return fmt.Sprintf("Session: %s\nConfig: %s\nCreated: %s\n\n%s",
-		session.ID, session.ConfigName,
+	label := ""
+	if session.Label != "" {
+		label = fmt.Sprintf("Label: %s\n", session.Label)
+	}
+	return fmt.Sprintf("Session: %s\n%sConfig: %s\nCreated: %s\n\n%s",
+		session.ID, label, session.ConfigName,
 		session.CreatedAt.Format("2006-01-02 15:04:05"),
 		formatGameState(session.GameState))
 }
@@ -808,7 +1330,6 @@ func formatGameState(state *engine.GameState) string {
 	}
 
 	var result strings.Builder
-	gridSize := len(state.Grid)
 
 	// Header (include cumulative total moves)
 	result.WriteString(fmt.Sprintf("Position: (%d,%d) | Battery: %d/%d | Score: %d | Moves: %d\n\n",
@@ -819,6 +1340,12 @@ func formatGameState(state *engine.GameState) string {
 	if state.BatteryRisk != "" {
 		result.WriteString(fmt.Sprintf("Battery risk: %s\n", state.BatteryRisk))
 	}
+	if state.NextPark != "" {
+		result.WriteString(fmt.Sprintf("Next park in order: %s\n", state.NextPark))
+	}
+	if state.ChargeHold > 0 {
+		result.WriteString(fmt.Sprintf("Held on the charger: %d more turns before you can drive off\n", state.ChargeHold))
+	}
 	// Prefer server-provided local_view_3x3; otherwise derive
 	if len(state.LocalView3x3) == 3 {
 		result.WriteString("Local 3x3:\n")
@@ -829,45 +1356,48 @@ func formatGameState(state *engine.GameState) string {
 		result.WriteString("Local 3x3:\n")
 		result.WriteString(v + "\n")
 	}
-
-	// Grid
-	for y := 0; y < gridSize; y++ {
-		for x := 0; x < gridSize; x++ {
-			if x == state.PlayerPos.X && y == state.PlayerPos.Y {
-				result.WriteString("T")
-			} else {
-				cell := state.Grid[y][x]
-				switch cell.Type {
-				case engine.Road:
-					result.WriteString("R")
-				case engine.Home:
-					result.WriteString("H")
-				case engine.Park:
-					if cell.Visited {
-						result.WriteString("✓")
-					} else {
-						result.WriteString("P")
-					}
-				case engine.Supercharger:
-					result.WriteString("S")
-				case engine.Water:
-					result.WriteString("W")
-				case engine.Building:
-					result.WriteString("B")
-				default:
-					result.WriteString(".")
-				}
+	if v := state.RelativeView; v != nil {
+		result.WriteString(fmt.Sprintf("Heading %s: forward %s, left %s, right %s, back %s\n", v.Heading, v.Forward, v.Left, v.Right, v.Back))
+	}
+	if len(state.MovePreviews) > 0 {
+		result.WriteString("Move previews:\n")
+		for _, dir := range []string{"up", "down", "left", "right"} {
+			p, ok := state.MovePreviews[dir]
+			if !ok {
+				continue
+			}
+			var tags []string
+			if p.Park {
+				tags = append(tags, "park")
+			}
+			if p.Charges {
+				tags = append(tags, "charges")
 			}
+			line := fmt.Sprintf("  %s → (%d,%d) %s battery=%d", dir, p.To.X, p.To.Y, p.TileChar, p.BatteryAfter)
+			if len(tags) > 0 {
+				line += " [" + strings.Join(tags, ", ") + "]"
+			}
+			result.WriteString(line + "\n")
 		}
 		result.WriteString("\n")
 	}
 
+	// Grid
+	result.WriteString(service.RenderGrid(state))
+
 	// Status
 	if state.GameOver {
 		if state.Victory {
 			result.WriteString("\n🎉 VICTORY!")
 		} else {
 			result.WriteString("\n💀 GAME OVER")
+			if state.GameOverReason != "" {
+				result.WriteString(fmt.Sprintf(" (%s)", state.GameOverReason))
+			}
+		}
+		if r := state.Result; r != nil {
+			result.WriteString(fmt.Sprintf("\nResult: %s • %s • %d/%d parks • score %d • %d moves (%d attempted)",
+				r.Outcome, r.Reason, r.ParksCollected, r.TotalParks, r.FinalScore, r.MovesUsed, r.ElapsedMoves))
 		}
 	}
 
@@ -922,14 +1452,14 @@ func formatBulkMoveResult(sessionID string, result *service.BulkMoveResult) stri
 	var b strings.Builder
 
 	// Session header
-	gridSize := 0
+	width, height := 0, 0
 	configName := ""
 	if result.GameState != nil {
-		gridSize = len(result.GameState.Grid)
+		width, height = gridDimensions(result.GameState)
 		configName = result.GameState.ConfigName
 	}
 	b.WriteString(fmt.Sprintf("Session: %s • Config: %s • Grid: %dx%d\n",
-		sessionID, configName, gridSize, gridSize))
+		sessionID, configName, width, height))
 
 	// Bulk summary
 	requested := result.RequestedMoves
@@ -937,6 +1467,15 @@ func formatBulkMoveResult(sessionID string, result *service.BulkMoveResult) stri
 		requested = result.TotalMoves // backward-compat
 	}
 	b.WriteString(fmt.Sprintf("Executed %d/%d moves\n", result.MovesExecuted, requested))
+	if result.BlockedCount > 0 {
+		b.WriteString(fmt.Sprintf("Blocked: %d moves (no battery used)\n", result.BlockedCount))
+		for _, step := range result.Steps {
+			if step.AttemptedTo != nil {
+				b.WriteString(fmt.Sprintf("- move %d %s: attempted (%d,%d) tile=%s\n",
+					step.Idx, step.Dir, step.AttemptedTo.X, step.AttemptedTo.Y, step.AttemptedTo.TileType))
+			}
+		}
+	}
 	if result.StoppedReason != "" {
 		b.WriteString(fmt.Sprintf("Stopped: %s\n", result.StoppedReason))
 	}
@@ -950,8 +1489,8 @@ func formatBulkMoveResult(sessionID string, result *service.BulkMoveResult) stri
 	}
 
 	// Recent steps: last N entries from current segment where N = moves_executed
-	if result.GameState != nil && result.MovesExecuted > 0 {
-		steps := getRecentSteps(result.GameState, result.MovesExecuted)
+	if result.GameState != nil && result.MovesExecuted+result.BlockedCount > 0 {
+		steps := getRecentSteps(result.GameState, result.MovesExecuted+result.BlockedCount)
 		if len(steps) > 0 {
 			b.WriteString("\nRecent steps (this call):\n")
 			for i, s := range steps {
@@ -993,6 +1532,48 @@ func formatBulkMoveResult(sessionID string, result *service.BulkMoveResult) stri
 	return b.String()
 }
 
+// formatConfigAnalysis renders config difficulty metrics
+func formatConfigAnalysis(a *engine.ConfigAnalysis) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Config: %s • Grid: %dx%d\n", a.Name, a.Width, a.Height))
+	b.WriteString(fmt.Sprintf("Battery: %d max, %d starting\n", a.MaxBattery, a.StartingBattery))
+	b.WriteString(fmt.Sprintf("Chargers: %d • Parks: %d\n", a.ChargerCount, a.ParkCount))
+	b.WriteString(fmt.Sprintf("Park-to-charger distance: %d..%d\n", a.MinParkChargerDistance, a.MaxParkChargerDistance))
+	b.WriteString(fmt.Sprintf("Difficulty: %d/100\n", a.DifficultyScore))
+	if len(a.UnreachableParks) > 0 {
+		b.WriteString(fmt.Sprintf("Unreachable parks: %d\n", len(a.UnreachableParks)))
+		for _, p := range a.UnreachableParks {
+			b.WriteString(fmt.Sprintf("- (%d,%d)\n", p.X, p.Y))
+		}
+	}
+	if len(a.OneWayParks) > 0 {
+		b.WriteString(fmt.Sprintf("One-way parks (no charger within return range): %d\n", len(a.OneWayParks)))
+	}
+	if len(a.UnreachableCells) > 0 {
+		b.WriteString(fmt.Sprintf("Cells beyond battery range of any charger: %d\n", len(a.UnreachableCells)))
+	}
+	return b.String()
+}
+
+// formatSolveResult renders a solver plan in bulk_move sized chunks
+func formatSolveResult(r *service.SolveResult) string {
+	if !r.Solved {
+		return fmt.Sprintf("❌ No solution found (%s): %s\nSearched for %dms.", r.ReasonCode, r.Reason, r.ElapsedMs)
+	}
+	if r.MoveCount == 0 {
+		return "🏆 Already won - no moves needed."
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("✅ Winning plan: %d moves (found in %dms)\n", r.MoveCount, r.ElapsedMs))
+	for start := 0; start < len(r.Moves); start += engine.MaxBulkMoves {
+		end := min(start+engine.MaxBulkMoves, len(r.Moves))
+		b.WriteString(fmt.Sprintf("Moves %d-%d: %s\n", start+1, end, strings.Join(r.Moves[start:end], ", ")))
+	}
+	b.WriteString(fmt.Sprintf("Submit each line with bulk_move (max %d moves per call).", engine.MaxBulkMoves))
+	return b.String()
+}
+
 // getRecentSteps returns the last N entries from CurrentMoves
 func getRecentSteps(state *engine.GameState, n int) []engine.MoveHistoryEntry {
 	total := len(state.CurrentMoves)
@@ -1015,8 +1596,8 @@ func formatStepLine(idx int, entry engine.MoveHistoryEntry, state *engine.GameSt
 	if entry.Success {
 		status = "✓"
 	}
-	return fmt.Sprintf("%d. %s (%d,%d)→(%d,%d) tile=%s batt=%d %s\n",
-		idx, entry.Action, from.X, from.Y, to.X, to.Y, tileChar, entry.Battery, status)
+	return fmt.Sprintf("%d. %s (%d,%d)→(%d,%d) tile=%s batt=%d %s%s\n",
+		idx, entry.Action, from.X, from.Y, to.X, to.Y, tileChar, entry.Battery, status, intentSuffix(entry.Intent))
 }
 
 // formatStoppedDiagnostic describes why the last attempt likely failed
@@ -1040,6 +1621,15 @@ func formatStoppedDiagnostic(movesExecuted int, state *engine.GameState) string
 		return ""
 	}
 
+	moveNum := movesExecuted + 1 // 1-based index of the failed attempt within this call
+
+	if last.Action == engine.ActionCharge {
+		return fmt.Sprintf("Blocked on move %d: cannot charge at (%d,%d), not on a charger", moveNum, last.FromPosition.X, last.FromPosition.Y)
+	}
+	if last.Action == engine.ActionPark {
+		return fmt.Sprintf("Blocked on move %d: cannot park at (%d,%d), no uncollected park", moveNum, last.FromPosition.X, last.FromPosition.Y)
+	}
+
 	// Compute attempted target based on direction
 	tx, ty := last.FromPosition.X, last.FromPosition.Y
 	switch strings.ToLower(last.Action) {
@@ -1053,17 +1643,14 @@ func formatStoppedDiagnostic(movesExecuted int, state *engine.GameState) string
 		tx++
 	}
 
-	gridSize := len(state.Grid)
-	moveNum := movesExecuted + 1 // 1-based index of the failed attempt within this call
-
 	// Boundary check
-	if tx < 0 || ty < 0 || ty >= gridSize || (gridSize > 0 && tx >= len(state.Grid[0])) {
+	if !state.InBounds(tx, ty) {
 		return fmt.Sprintf("Blocked on move %d: attempted (%d,%d) tile=boundary (impassable)", moveNum, tx, ty)
 	}
 
 	cell := state.Grid[ty][tx]
 	char := mapCellToChar(cell)
-	passable := cell.Type != engine.Water && cell.Type != engine.Building
+	passable := state.CanMoveTo(tx, ty)
 
 	reason := "blocked"
 	if !passable {
@@ -1077,6 +1664,70 @@ func formatStoppedDiagnostic(movesExecuted int, state *engine.GameState) string
 	return fmt.Sprintf("Blocked on move %d: attempted (%d,%d) tile=%s %s", moveNum, tx, ty, char, reason)
 }
 
+// possibleMove is one direction listed by the possible_moves tool
+type possibleMove struct {
+	Direction       string `json:"direction"`
+	DestinationChar string `json:"destination_char"`
+	// Affordable is set when the move can be made without leaving the
+	// battery empty away from a charger
+	Affordable     bool `json:"affordable"`
+	BatteryAfter   int  `json:"battery_after"`
+	LeadsToCharger bool `json:"leads_to_charger"`
+	LeadsToPark    bool `json:"leads_to_park"` // An uncollected park
+}
+
+// possibleMovesResult is the structured result of the possible_moves tool
+type possibleMovesResult struct {
+	Moves       []possibleMove `json:"moves"`
+	Explanation string         `json:"explanation,omitempty"`
+}
+
+// listPossibleMoves lists the passable neighbours of the player, judging
+// affordability from the state's move previews
+func listPossibleMoves(state *engine.GameState) possibleMovesResult {
+	result := possibleMovesResult{Moves: []possibleMove{}}
+	if state.GameOver {
+		result.Explanation = fmt.Sprintf("The game is over (%s), so no move can be made; use reset_game to play again", state.GameOverReason)
+		return result
+	}
+
+	for _, dir := range []string{"up", "down", "left", "right"} {
+		to := state.PlayerPos
+		switch dir {
+		case "up":
+			to.Y--
+		case "down":
+			to.Y++
+		case "left":
+			to.X--
+		case "right":
+			to.X++
+		}
+		if !state.CanMoveTo(to.X, to.Y) {
+			continue
+		}
+		cell := state.Grid[to.Y][to.X]
+		move := possibleMove{
+			Direction:       dir,
+			DestinationChar: inferTileChar(state, to.X, to.Y),
+			BatteryAfter:    max(state.Battery-1, 0),
+			LeadsToCharger:  engine.IsCharger(cell.Type),
+			LeadsToPark:     cell.Type == engine.Park && !cell.Visited,
+		}
+		if preview, ok := state.MovePreviews[dir]; ok && preview.To == to {
+			move.BatteryAfter = preview.BatteryAfter
+			move.Affordable = preview.BatteryAfter > 0 || move.LeadsToCharger
+		}
+		result.Moves = append(result.Moves, move)
+	}
+	if len(result.Moves) == 0 {
+		result.Explanation = "Every neighbouring cell is water, a building or off the grid"
+	} else if state.Battery <= 0 {
+		result.Explanation = "The battery is empty: charge before moving"
+	}
+	return result
+}
+
 // computePossibleMoves returns valid directions from the current state
 func computePossibleMoves(state *engine.GameState) []string {
 	if state == nil || state.GameOver || state.Battery <= 0 {
@@ -1094,8 +1745,7 @@ func computePossibleMoves(state *engine.GameState) []string {
 		if x < 0 || y < 0 || y >= gridH || x >= gridW {
 			return false
 		}
-		t := state.Grid[y][x].Type
-		return t != engine.Water && t != engine.Building
+		return state.CanMoveTo(x, y)
 	}
 	for _, d := range dirs {
 		x, y := px, py
@@ -1138,10 +1788,18 @@ func formatLocal3x3(state *engine.GameState) string {
 	return lines[0] + "\n" + lines[1] + "\n" + lines[2] + "\n"
 }
 
+// gridDimensions returns the width and height of the state's grid
+func gridDimensions(state *engine.GameState) (width, height int) {
+	height = len(state.Grid)
+	if height > 0 {
+		width = len(state.Grid[0])
+	}
+	return width, height
+}
+
 // inferTileChar returns a single-character representation for a cell at (x,y), handling OOB
 func inferTileChar(state *engine.GameState, x, y int) string {
-	gridH := len(state.Grid)
-	if x < 0 || y < 0 || y >= gridH || (gridH > 0 && x >= len(state.Grid[0])) {
+	if !state.InBounds(x, y) {
 		return "B" // out-of-bounds treated as building/wall
 	}
 	cell := state.Grid[y][x]
@@ -1161,6 +1819,16 @@ func mapCellToChar(cell engine.Cell) string {
 		return "P"
 	case engine.Supercharger:
 		return "S"
+	case engine.Fuel:
+		return "F"
+	case engine.Trickle:
+		return "C"
+	case engine.Checkpoint:
+		return "K"
+	case engine.Key:
+		return "Y"
+	case engine.Door:
+		return "D"
 	case engine.Water:
 		return "W"
 	case engine.Building:
@@ -1180,13 +1848,33 @@ func formatHistory(history *service.HistoryResponse) string {
 		if !move.Success {
 			status = "✗"
 		}
-		result += fmt.Sprintf("%d. %s %s [Battery: %d]\n",
-			num, move.Action, status, move.Battery)
+		result += fmt.Sprintf("%d. %s %s [Battery: %s]%s\n",
+			num, move.Action, status, batteryLabel(move), intentSuffix(move.Intent))
 	}
 
 	return result
 }
 
+// batteryLabel renders the battery after a move with its change, e.g. "9 (-1)"
+func batteryLabel(move engine.MoveHistoryEntry) string {
+	if move.BatteryDelta == 0 {
+		return fmt.Sprintf("%d", move.Battery)
+	}
+	return fmt.Sprintf("%d (%+d)", move.Battery, move.BatteryDelta)
+}
+
+// intentSuffix renders a move intent as a short trailing note
+func intentSuffix(intent string) string {
+	if intent == "" {
+		return ""
+	}
+	const maxLen = 60
+	if runes := []rune(intent); len(runes) > maxLen {
+		intent = string(runes[:maxLen-1]) + "…"
+	}
+	return fmt.Sprintf(" — %q", intent)
+}
+
 func formatCurrentSegment(state *engine.GameState) string {
 	if state == nil {
 		return "Current Segment: unavailable"
@@ -1205,7 +1893,7 @@ func formatCurrentSegment(state *engine.GameState) string {
 			status = "✗"
 		}
 		// i is zero-based within the segment
-		b.WriteString(fmt.Sprintf("%d. %s %s [Battery: %d]\n", i+1, move.Action, status, move.Battery))
+		b.WriteString(fmt.Sprintf("%d. %s %s [Battery: %s]%s\n", i+1, move.Action, status, batteryLabel(move), intentSuffix(move.Intent)))
 	}
 	return b.String()
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -202,6 +203,28 @@ func TestFormatGameState(t *testing.T) {
 	}
 }
 
+func TestFormatGameState_RectangularGrid(t *testing.T) {
+	grid := make([][]engine.Cell, 2)
+	for y := range grid {
+		grid[y] = make([]engine.Cell, 6)
+		for x := range grid[y] {
+			grid[y][x] = engine.Cell{Type: engine.Road}
+		}
+	}
+	gameState := &engine.GameState{
+		Grid:       grid,
+		PlayerPos:  engine.Position{X: 5, Y: 1},
+		Battery:    10,
+		MaxBattery: 10,
+	}
+
+	result := formatGameState(gameState)
+
+	if !strings.Contains(result, "RRRRRR\nRRRRRT\n") {
+		t.Errorf("Expected full 6x2 grid with player in the far corner, got: %s", result)
+	}
+}
+
 func TestFormatGameState_GameOver(t *testing.T) {
 	gameState := &engine.GameState{
 		PlayerPos:  engine.Position{X: 2, Y: 1},
@@ -284,6 +307,27 @@ func TestFormatMoveResult_Failed(t *testing.T) {
 
 }
 
+func TestFormatBulkMoveResult_Blocked(t *testing.T) {
+	bulkResult := &service.BulkMoveResult{
+		MovesExecuted:  1,
+		RequestedMoves: 2,
+		BlockedCount:   1,
+		Steps: []service.StepInfo{
+			{Idx: 1, Dir: "up", AttemptedTo: &service.AttemptInfo{X: 2, Y: 0, TileType: "water"}},
+			{Idx: 2, Dir: "left", Success: true},
+		},
+		GameState: &engine.GameState{Battery: 9},
+	}
+
+	result := formatBulkMoveResult("sess", bulkResult)
+
+	for _, field := range []string{"Blocked: 1 moves", "move 1 up: attempted (2,0) tile=water"} {
+		if !strings.Contains(result, field) {
+			t.Errorf("Expected '%s' in formatted output, got: %s", field, result)
+		}
+	}
+}
+
 func TestClient_handleGameInstructions(t *testing.T) {
 	client := NewClient("http://localhost:8080")
 	ctx := context.Background()
@@ -359,3 +403,446 @@ func TestClient_Integration(t *testing.T) {
 		t.Error("HTTP client not initialized")
 	}
 }
+
+func TestFormatConfigAnalysis(t *testing.T) {
+	analysis := &engine.ConfigAnalysis{
+		Name:             "Maze",
+		Width:            10,
+		Height:           10,
+		ChargerCount:     2,
+		ParkCount:        5,
+		DifficultyScore:  72,
+		UnreachableParks: []engine.Position{{X: 9, Y: 0}},
+	}
+
+	result := formatConfigAnalysis(analysis)
+
+	for _, field := range []string{"Config: Maze", "Chargers: 2 • Parks: 5", "Difficulty: 72/100", "- (9,0)"} {
+		if !strings.Contains(result, field) {
+			t.Errorf("Expected '%s' in formatted output, got: %s", field, result)
+		}
+	}
+}
+
+func TestFormatSolveResult(t *testing.T) {
+	moves := make([]string, 0, 60)
+	for len(moves) < 60 {
+		moves = append(moves, "up", "down")
+	}
+	result := formatSolveResult(&service.SolveResult{Solved: true, Moves: moves, MoveCount: len(moves)})
+	for _, field := range []string{"Winning plan: 60 moves", "Moves 1-50:", "Moves 51-60:"} {
+		if !strings.Contains(result, field) {
+			t.Errorf("Expected '%s' in formatted output, got: %s", field, result)
+		}
+	}
+
+	result = formatSolveResult(&service.SolveResult{Reason: "no winning plan exists from this state", ReasonCode: "unsolvable"})
+	if !strings.Contains(result, "No solution found (unsolvable)") {
+		t.Errorf("Expected infeasibility message, got: %s", result)
+	}
+}
+
+func TestFormatHistory_Intent(t *testing.T) {
+	history := &service.HistoryResponse{
+		Moves: []engine.MoveHistoryEntry{
+			{Action: "up", Success: true, Battery: 9, Intent: "park is two tiles north"},
+			{Action: "up", Success: true, Battery: 8},
+			{Action: "left", Success: true, Battery: 10, BatteryDelta: 2, Cost: 1},
+		},
+		TotalMoves: 3,
+		Page:       1,
+		PageSize:   20,
+		TotalPages: 1,
+	}
+
+	result := formatHistory(history)
+
+	if !strings.Contains(result, `1. up ✓ [Battery: 9] — "park is two tiles north"`) {
+		t.Errorf("Expected intent suffix on first move, got: %s", result)
+	}
+	if !strings.Contains(result, "2. up ✓ [Battery: 8]\n") {
+		t.Errorf("Expected no suffix without intent, got: %s", result)
+	}
+	if !strings.Contains(result, "3. left ✓ [Battery: 10 (+2)]\n") {
+		t.Errorf("Expected battery delta on charging move, got: %s", result)
+	}
+}
+
+func TestClient_handleAnnotatedBulkMove(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/sessions/sess/bulk-move" {
+			t.Errorf("Expected POST /api/sessions/sess/bulk-move, got %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Moves   []string `json:"moves"`
+			Intents []string `json:"intents"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if strings.Join(body.Moves, ",") != "left,down" || len(body.Intents) != 2 || body.Intents[0] != "reach the road" || body.Intents[1] != "" {
+			t.Errorf("Expected moves and parallel intents, got %+v", body)
+		}
+
+		resp := service.BulkMoveResult{
+			MovesExecuted:  2,
+			RequestedMoves: 2,
+			Steps: []service.StepInfo{
+				{Idx: 1, Dir: "left", Success: true, Intent: body.Intents[0]},
+				{Idx: 2, Dir: "down", Success: true},
+			},
+			GameState: &engine.GameState{
+				Battery: 8,
+				CurrentMoves: []engine.MoveHistoryEntry{
+					{Action: "left", Success: true, Battery: 9, Intent: body.Intents[0]},
+					{Action: "down", Success: true, Battery: 8},
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name: "annotated_bulk_move",
+			Arguments: map[string]interface{}{
+				"session_id": "sess",
+				"steps": []interface{}{
+					map[string]interface{}{"direction": "left", "intent": "reach the road"},
+					map[string]interface{}{"direction": "down"},
+				},
+			},
+		},
+	}
+
+	result, err := client.handleAnnotatedBulkMove(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handleAnnotatedBulkMove failed: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, `✓ — "reach the road"`) {
+		t.Errorf("Expected the step intent echoed back, got: %s", text)
+	}
+
+	// A step without a direction is rejected before calling the API
+	request.Params.Arguments = map[string]interface{}{
+		"session_id": "sess",
+		"steps":      []interface{}{map[string]interface{}{"intent": "lost"}},
+	}
+	result, err = client.handleAnnotatedBulkMove(context.Background(), request)
+	if err != nil || !result.IsError {
+		t.Errorf("Expected a tool error for a step without direction, got %+v, %v", result, err)
+	}
+}
+
+func TestClient_handleMoveToward(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/sessions/sess/move-toward" {
+			t.Errorf("Expected POST /api/sessions/sess/move-toward, got %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			X int `json:"x"`
+			Y int `json:"y"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if body.X == 1 && body.Y == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "target is unreachable: no road leads from (3,2) to (1,1)"}`))
+			return
+		}
+
+		resp := service.MoveTowardResult{
+			MoveResult: &service.MoveResult{
+				Success:   true,
+				GameState: &engine.GameState{PlayerPos: engine.Position{X: 2, Y: 2}, Battery: 9, MaxBattery: 10},
+			},
+			Target:        engine.Position{X: body.X, Y: body.Y},
+			Direction:     "left",
+			RemainingPath: 2,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "move_toward",
+			Arguments: map[string]interface{}{"session_id": "sess", "x": float64(2), "y": float64(0)},
+		},
+	}
+
+	result, err := client.handleMoveToward(context.Background(), request)
+	if err != nil {
+		t.Fatalf("handleMoveToward failed: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "Moved left toward (2,0): 2 moves left") || !strings.Contains(text, "Move successful") {
+		t.Errorf("Expected the step and the remaining path, got: %s", text)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"session_id": "sess", "x": float64(1), "y": float64(1)}
+	result, err = client.handleMoveToward(context.Background(), request)
+	if err != nil || !result.IsError {
+		t.Errorf("Expected a tool error for an unreachable target, got %+v, %v", result, err)
+	}
+}
+
+func TestClient_GridResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/sessions/sess/state" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "session not found"})
+			return
+		}
+		state := engine.GameState{
+			Grid:       [][]engine.Cell{{{Type: engine.Home}, {Type: engine.Road}}, {{Type: engine.Park, ID: "p1"}, {Type: engine.Water}}},
+			Battery:    7,
+			MaxBattery: 10,
+			Message:    "Ready",
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(state)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	read := func(uri string) string {
+		t.Helper()
+		msg, _ := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "resources/read",
+			"params":  map[string]string{"uri": uri},
+		})
+		resp, ok := client.GetMCPServer().HandleMessage(context.Background(), msg).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("Expected a response reading %s", uri)
+		}
+		result := resp.Result.(mcp.ReadResourceResult)
+		if len(result.Contents) != 1 {
+			t.Fatalf("Expected one content, got %d", len(result.Contents))
+		}
+		content := result.Contents[0].(mcp.TextResourceContents)
+		if content.URI != uri || content.MIMEType != "text/plain" {
+			t.Errorf("Expected text/plain content for %s, got %+v", uri, content)
+		}
+		return content.Text
+	}
+
+	text := read("game://sess/grid")
+	if !strings.Contains(text, "Session: sess") || !strings.Contains(text, "Battery: 7/10") || !strings.Contains(text, "Message: Ready") {
+		t.Errorf("Expected the rendered grid and status, got: %s", text)
+	}
+
+	// An unknown session reads as an error rather than failing the request
+	text = read("game://missing/grid")
+	if !strings.HasPrefix(text, "Error:") || !strings.Contains(text, "missing") || !strings.Contains(text, "session not found") {
+		t.Errorf("Expected a clear error for an unknown session, got: %s", text)
+	}
+}
+
+func TestClient_ChargerEffects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/sessions/sess/state":
+			json.NewEncoder(w).Encode(engine.GameState{
+				Grid: [][]engine.Cell{{{Type: engine.Home}, {Type: engine.Trickle}}, {{Type: engine.Park, ID: "p1"}, {Type: engine.Supercharger}}},
+			})
+		case "/api/sessions/sess/config":
+			cfg := service.SessionConfig{ConfigID: "test"}
+			cfg.Layout = []string{"HC", "PS"}
+			cfg.ChargerEffects = map[engine.CellType]engine.ChargerEffect{
+				engine.Home:    {MovePenalty: 2},
+				engine.Trickle: {Charge: 3},
+			}
+			json.NewEncoder(w).Encode(cfg)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL)
+
+	describe := func(x, y int) string {
+		t.Helper()
+		result, err := client.handleDescribeCell(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "describe_cell",
+				Arguments: map[string]interface{}{"session_id": "sess", "x": float64(x), "y": float64(y)},
+			},
+		})
+		if err != nil || result.IsError {
+			t.Fatalf("describe_cell (%d,%d) failed: %+v, %v", x, y, result, err)
+		}
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	if text := describe(1, 0); !strings.Contains(text, "Type: Trickle charger") || !strings.Contains(text, "adds 3 battery per charge") {
+		t.Errorf("Expected the configured trickle charge, got: %s", text)
+	}
+	if text := describe(1, 1); !strings.Contains(text, "provides full battery charge") {
+		t.Errorf("Expected the default supercharger effect, got: %s", text)
+	}
+
+	result, err := client.handleGameInstructions(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "game_instructions",
+			Arguments: map[string]interface{}{"session_id": "sess"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("game_instructions failed: %+v, %v", result, err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{
+		"CHARGER EFFECTS (session sess):",
+		"- Home (H): provides full battery charge, then holds you there for 2 turns before you can drive off",
+		"- Supercharger (S): provides full battery charge",
+		"- Trickle charger (C): adds 3 battery per charge",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in instructions, got: %s", want, text)
+		}
+	}
+}
+
+func TestClient_DescribeDoor(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/sessions/sess/state":
+			json.NewEncoder(w).Encode(engine.GameState{
+				Grid:          [][]engine.Cell{{{Type: engine.Home}, {Type: engine.Key, ID: "gate"}, {Type: engine.Door, ID: "gate"}}},
+				CollectedKeys: keys,
+			})
+		case "/api/sessions/sess/config":
+			cfg := service.SessionConfig{ConfigID: "test"}
+			cfg.Layout = []string{"HYD"}
+			json.NewEncoder(w).Encode(cfg)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL)
+
+	describe := func(x, y int) string {
+		t.Helper()
+		result, err := client.handleDescribeCell(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "describe_cell",
+				Arguments: map[string]interface{}{"session_id": "sess", "x": float64(x), "y": float64(y)},
+			},
+		})
+		if err != nil || result.IsError {
+			t.Fatalf("describe_cell (%d,%d) failed: %+v, %v", x, y, result, err)
+		}
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	if text := describe(1, 0); !strings.Contains(text, "Type: Key") || !strings.Contains(text, "Passable: true") {
+		t.Errorf("Expected a passable key, got: %s", text)
+	}
+	if text := describe(2, 0); !strings.Contains(text, "Type: Door (Locked)") || !strings.Contains(text, "Passable: false") {
+		t.Errorf("Expected a locked door, got: %s", text)
+	}
+	keys = []string{"gate"}
+	if text := describe(2, 0); !strings.Contains(text, "Type: Door (Unlocked)") || !strings.Contains(text, "Passable: true") {
+		t.Errorf("Expected the collected key to unlock the door, got: %s", text)
+	}
+}
+
+func TestClient_handlePossibleMoves(t *testing.T) {
+	road, park := engine.Cell{Type: engine.Road}, engine.Cell{Type: engine.Park, ID: "park_0"}
+	state := engine.GameState{
+		Grid: [][]engine.Cell{
+			{road, park, {Type: engine.Building}},
+			{{Type: engine.Supercharger}, road, {Type: engine.Water}},
+			{road, road, road},
+		},
+		PlayerPos:  engine.Position{X: 1, Y: 1},
+		Battery:    1,
+		MaxBattery: 10,
+		MovePreviews: map[string]engine.MovePreview{
+			"up":   {To: engine.Position{X: 1, Y: 0}, TileChar: "P", Park: true},
+			"down": {To: engine.Position{X: 1, Y: 2}, TileChar: "R"},
+			"left": {To: engine.Position{X: 0, Y: 1}, TileChar: "S", Charges: true, BatteryAfter: 10},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/sessions/sess/state" {
+			t.Errorf("Expected GET /api/sessions/sess/state, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(state)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "possible_moves",
+			Arguments: map[string]interface{}{"session_id": "sess"},
+		},
+	}
+	result, err := client.handlePossibleMoves(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("handlePossibleMoves failed: %+v, %v", result, err)
+	}
+	got := result.StructuredContent.(possibleMovesResult)
+	want := []possibleMove{
+		{Direction: "up", DestinationChar: "P", LeadsToPark: true},
+		{Direction: "down", DestinationChar: "R"},
+		{Direction: "left", DestinationChar: "S", Affordable: true, BatteryAfter: 10, LeadsToCharger: true},
+	}
+	if !reflect.DeepEqual(got.Moves, want) || got.Explanation != "" {
+		t.Errorf("Expected moves %+v, got %+v (%q)", want, got.Moves, got.Explanation)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, `"leads_to_charger":true`) {
+		t.Errorf("Expected the JSON text fallback, got %s", text)
+	}
+
+	// A finished game lists nothing and says why
+	state.GameOver, state.GameOverReason = true, engine.GameOverStranded
+	result, err = client.handlePossibleMoves(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("handlePossibleMoves failed: %+v, %v", result, err)
+	}
+	got = result.StructuredContent.(possibleMovesResult)
+	if len(got.Moves) != 0 || !strings.Contains(got.Explanation, "game is over (stranded)") {
+		t.Errorf("Expected an empty list with an explanation, got %+v", got)
+	}
+}
+
+func TestClient_handleListConfigs_Metadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]service.ConfigInfo{
+			{ConfigID: "coast", Name: "Coast", Author: "Ada", Version: "2.0", Notes: "Mind the ferry", GridWidth: 10, GridHeight: 8},
+			{ConfigID: "plain", Name: "Plain", GridWidth: 5, GridHeight: 5},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	result, err := client.handleListConfigs(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("handleListConfigs failed: %v", err)
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	for _, want := range []string{"Author: Ada", "Version: 2.0", "Notes: Mind the ferry"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the listing, got: %s", want, text)
+		}
+	}
+	if strings.Count(text, "Author:") != 1 {
+		t.Errorf("Expected metadata only for the annotated config, got: %s", text)
+	}
+}
@@ -12,12 +12,21 @@
 //   - game_state: Get current game state with grid visualization
 //   - move: Execute single directional movement
 //   - bulk_move: Execute multiple moves in sequence
+//   - annotated_bulk_move: Execute multiple moves, each with its own intent
+//   - wait: Stay put for a turn
 //   - reset_game: Reset game to initial state
+//   - give_up: Surrender an unwinnable game, keeping the session for review
 //   - move_history: Retrieve move history with pagination
 //   - create_session: Create new game session with config selection
 //   - get_session: Get specific session details
 //   - list_sessions: List all active sessions
 //   - list_configs: List available game configurations
+//   - analyze_config: Difficulty metrics for a configuration
+//
+// MCP Resources:
+//
+//   - game://{session_id}/grid: The session's rendered grid and status as
+//     text, for agents to attach as context without a tool call
 //
 // Transport Modes:
 //
@@ -0,0 +1,32 @@
+// Package webhook delivers game events to registered HTTP endpoints.
+//
+// The webhook package implements:
+//   - A registry of endpoints and the events each one subscribes to,
+//     persisted as a JSON file
+//   - Asynchronous delivery through a bounded queue
+//   - Retries with exponential backoff
+//   - Automatic disabling of endpoints that keep failing
+//
+// Events:
+//
+// Endpoints subscribe to any of victory, game_over, session_created and
+// park_visited. Each delivery is a POST with a JSON Payload carrying the
+// session ID, event type, timestamp and key state fields (position, battery,
+// score, moves, outcome).
+//
+// Delivery:
+//
+// Manager implements service.EventPublisher. Publish only enqueues, so move
+// processing is never slowed by slow or unreachable endpoints. When the queue
+// is full the oldest event is dropped and a warning reports the running drop
+// count. Warnings and errors go to the service.Logger given with WithLogger. A single worker posts events in order, retrying failed posts; after
+// several consecutive failed events an endpoint is disabled and must be
+// registered again.
+//
+// Usage:
+//
+//	webhooks, err := webhook.NewManager("webhooks.json")
+//	gameService := service.NewGameService(sessions, configs,
+//		service.WithEventPublisher(webhooks))
+//	apiServer.SetWebhooks(webhooks)
+package webhook
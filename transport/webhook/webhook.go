@@ -0,0 +1,373 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+)
+
+const (
+	// Events waiting for delivery; the oldest is dropped when full
+	defaultQueueSize = 256
+
+	// Delivery attempts per event before it counts as a failure
+	defaultMaxAttempts = 3
+
+	// Backoff before the first retry, doubled for each further retry
+	defaultRetryBackoff = 500 * time.Millisecond
+
+	// Consecutive failed deliveries after which an endpoint is disabled
+	defaultMaxFailures = 5
+
+	deliveryTimeout = 5 * time.Second
+)
+
+// EventTypes lists the events endpoints can subscribe to
+var EventTypes = []string{"victory", "game_over", "session_created", "park_visited"}
+
+// ErrNotFound is returned when deleting an unknown webhook
+var ErrNotFound = errors.New("webhook not found")
+
+// Webhook is a registered endpoint and the events it receives
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+	Failures  int       `json:"failures"` // Consecutive failed deliveries
+	Disabled  bool      `json:"disabled"`
+}
+
+// Payload is the JSON body posted to webhook endpoints
+type Payload struct {
+	SessionID      string                `json:"session_id"`
+	Event          string                `json:"event"`
+	Message        string                `json:"message,omitempty"`
+	Timestamp      time.Time             `json:"timestamp"`
+	ConfigName     string                `json:"config_name"`
+	PlayerPos      engine.Position       `json:"player_pos"`
+	Battery        int                   `json:"battery"`
+	Score          int                   `json:"score"`
+	TotalMoves     int                   `json:"total_moves"`
+	GameOver       bool                  `json:"game_over"`
+	Victory        bool                  `json:"victory"`
+	GameOverReason engine.GameOverReason `json:"game_over_reason,omitempty"`
+	Result         *engine.GameResult    `json:"result,omitempty"`
+}
+
+// Manager stores webhook registrations and delivers events to them in the
+// background. It implements service.EventPublisher.
+type Manager struct {
+	path   string
+	client *http.Client
+
+	mu    sync.Mutex
+	hooks []*Webhook
+
+	queue   chan Payload
+	dropped atomic.Uint64
+	done    chan struct{}
+
+	maxAttempts  int
+	retryBackoff time.Duration
+	maxFailures  int
+
+	logger service.Logger
+}
+
+// Option configures optional webhook manager settings
+type Option func(*Manager)
+
+// WithLogger sends the manager's messages, such as dropped events and failed
+// deliveries, to logger instead of service.DefaultLogger
+func WithLogger(logger service.Logger) Option {
+	return func(m *Manager) {
+		m.logger = logger
+	}
+}
+
+// NewManager loads registrations from path (created on first save) and starts
+// the delivery worker
+func NewManager(path string, opts ...Option) (*Manager, error) {
+	m := &Manager{
+		path:         path,
+		client:       &http.Client{Timeout: deliveryTimeout},
+		hooks:        []*Webhook{},
+		queue:        make(chan Payload, defaultQueueSize),
+		done:         make(chan struct{}),
+		maxAttempts:  defaultMaxAttempts,
+		retryBackoff: defaultRetryBackoff,
+		maxFailures:  defaultMaxFailures,
+		logger:       service.DefaultLogger(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read webhooks file: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &m.hooks); err != nil {
+			return nil, fmt.Errorf("failed to parse webhooks file: %w", err)
+		}
+	}
+
+	go m.run()
+	return m, nil
+}
+
+// Close stops the delivery worker; queued events are discarded
+func (m *Manager) Close() {
+	close(m.done)
+}
+
+// Register adds an endpoint for the given events
+func (m *Manager) Register(rawURL string, events []string) (*Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("at least one event is required (%v)", EventTypes)
+	}
+	for _, ev := range events {
+		if !validEvent(ev) {
+			return nil, fmt.Errorf("unknown event %q (valid: %v)", ev, EventTypes)
+		}
+	}
+
+	hook := &Webhook{
+		ID:        generateID(),
+		URL:       rawURL,
+		Events:    events,
+		CreatedAt: time.Now(),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+	if err := m.save(); err != nil {
+		m.hooks = m.hooks[:len(m.hooks)-1]
+		return nil, err
+	}
+	registered := *hook
+	return &registered, nil
+}
+
+// List returns copies of all registrations
+func (m *Manager) List() []Webhook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hooks := make([]Webhook, 0, len(m.hooks))
+	for _, h := range m.hooks {
+		hooks = append(hooks, *h)
+	}
+	return hooks
+}
+
+// Delete removes a registration
+func (m *Manager) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, h := range m.hooks {
+		if h.ID == id {
+			m.hooks = append(m.hooks[:i], m.hooks[i+1:]...)
+			return m.save()
+		}
+	}
+	return ErrNotFound
+}
+
+// Dropped returns how many events were discarded because the queue was full
+func (m *Manager) Dropped() uint64 {
+	return m.dropped.Load()
+}
+
+// Publish queues an event for delivery without blocking. When the queue is
+// full the oldest queued event is dropped to make room.
+func (m *Manager) Publish(event service.SessionEvent) {
+	if !validEvent(event.Event.Type) {
+		return
+	}
+
+	payload := Payload{
+		SessionID: event.SessionID,
+		Event:     event.Event.Type,
+		Message:   event.Event.Message,
+		Timestamp: event.Event.Timestamp,
+	}
+	if st := event.State; st != nil {
+		payload.ConfigName = st.ConfigName
+		payload.PlayerPos = st.PlayerPos
+		payload.Battery = st.Battery
+		payload.Score = st.Score
+		payload.TotalMoves = st.TotalMoves
+		payload.GameOver = st.GameOver
+		payload.Victory = st.Victory
+		payload.GameOverReason = st.GameOverReason
+		payload.Result = st.Result
+	}
+
+	for {
+		select {
+		case m.queue <- payload:
+			return
+		default:
+		}
+		select {
+		case <-m.queue:
+			n := m.dropped.Add(1)
+			m.logger.Warn("webhook queue full, dropped oldest event", "total_dropped", n)
+		default:
+		}
+	}
+}
+
+// run delivers queued events until Close
+func (m *Manager) run() {
+	for {
+		select {
+		case <-m.done:
+			return
+		case payload := <-m.queue:
+			for _, hook := range m.subscribers(payload.Event) {
+				m.deliver(hook, payload)
+			}
+		}
+	}
+}
+
+// subscribers returns copies of the enabled hooks subscribed to event
+func (m *Manager) subscribers(event string) []Webhook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var hooks []Webhook
+	for _, h := range m.hooks {
+		if h.Disabled {
+			continue
+		}
+		for _, ev := range h.Events {
+			if ev == event {
+				hooks = append(hooks, *h)
+				break
+			}
+		}
+	}
+	return hooks
+}
+
+// deliver posts a payload with retries and records the outcome on the hook
+func (m *Manager) deliver(hook Webhook, payload Payload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		m.logger.Error("failed to marshal webhook payload", "event", payload.Event, "error", err)
+		return
+	}
+
+	backoff := m.retryBackoff
+	for attempt := 1; attempt <= m.maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-m.done:
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		if err = m.post(hook.URL, body); err == nil {
+			m.recordResult(hook.ID, true)
+			return
+		}
+	}
+
+	m.logger.Warn("webhook delivery failed", "url", hook.URL, "attempts", m.maxAttempts, "error", err)
+	m.recordResult(hook.ID, false)
+}
+
+func (m *Manager) post(target string, body []byte) error {
+	resp, err := m.client.Post(target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// recordResult updates the failure counter, disabling the hook after too
+// many consecutive failures
+func (m *Manager) recordResult(id string, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, h := range m.hooks {
+		if h.ID != id {
+			continue
+		}
+		if ok {
+			if h.Failures == 0 {
+				return
+			}
+			h.Failures = 0
+		} else {
+			h.Failures++
+			if h.Failures >= m.maxFailures && !h.Disabled {
+				h.Disabled = true
+				m.logger.Warn("webhook disabled after consecutive failures", "url", h.URL, "failures", h.Failures)
+			}
+		}
+		if err := m.save(); err != nil {
+			m.logger.Error("failed to persist webhooks", "error", err)
+		}
+		return
+	}
+}
+
+// save writes registrations to disk; callers hold m.mu
+func (m *Manager) save() error {
+	data, err := json.MarshalIndent(m.hooks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhooks: %w", err)
+	}
+	if dir := filepath.Dir(m.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create webhooks directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(m.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write webhooks file: %w", err)
+	}
+	return nil
+}
+
+func validEvent(event string) bool {
+	for _, ev := range EventTypes {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// generateID returns a random 8-character hex ID
+func generateID() string {
+	bytes := make([]byte, 4)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	m, err := NewManager(filepath.Join(t.TempDir(), "webhooks.json"), WithLogger(slog.New(slog.DiscardHandler)))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	m.retryBackoff = time.Millisecond
+	t.Cleanup(m.Close)
+	return m
+}
+
+func testEvent(sessionID, eventType string) service.SessionEvent {
+	return service.SessionEvent{
+		SessionID: sessionID,
+		Event:     service.GameEvent{Type: eventType, Message: "test", Timestamp: time.Now()},
+		State: &engine.GameState{
+			ConfigName: "classic",
+			PlayerPos:  engine.Position{X: 2, Y: 3},
+			Battery:    7,
+			Score:      1,
+			TotalMoves: 12,
+		},
+	}
+}
+
+// receiver records payloads posted to it
+type receiver struct {
+	mu       sync.Mutex
+	payloads []Payload
+	received chan struct{}
+}
+
+func newReceiver(t *testing.T, status int) (*receiver, *httptest.Server) {
+	rcv := &receiver{received: make(chan struct{}, 100)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p Payload
+		json.NewDecoder(r.Body).Decode(&p)
+		rcv.mu.Lock()
+		rcv.payloads = append(rcv.payloads, p)
+		rcv.mu.Unlock()
+		w.WriteHeader(status)
+		rcv.received <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	return rcv, srv
+}
+
+func (r *receiver) wait(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.received:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for delivery %d of %d", i+1, n)
+		}
+	}
+}
+
+func TestRegister_Validation(t *testing.T) {
+	m := newTestManager(t)
+
+	tests := []struct {
+		name   string
+		url    string
+		events []string
+	}{
+		{"relative url", "/hook", []string{"victory"}},
+		{"unsupported scheme", "ftp://example.com/hook", []string{"victory"}},
+		{"no events", "http://example.com/hook", nil},
+		{"unknown event", "http://example.com/hook", []string{"victory", "moved"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.Register(tt.url, tt.events); err == nil {
+				t.Error("Expected registration error")
+			}
+		})
+	}
+
+	if len(m.List()) != 0 {
+		t.Errorf("Expected no registrations, got %d", len(m.List()))
+	}
+}
+
+func TestPublish_DeliversSubscribedEvents(t *testing.T) {
+	m := newTestManager(t)
+	rcv, srv := newReceiver(t, http.StatusOK)
+
+	if _, err := m.Register(srv.URL, []string{"victory"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	m.Publish(testEvent("s1", "park_visited"))
+	m.Publish(testEvent("s1", "victory"))
+	rcv.wait(t, 1)
+
+	// Give an unexpected extra delivery a chance to arrive
+	time.Sleep(50 * time.Millisecond)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if len(rcv.payloads) != 1 {
+		t.Fatalf("Expected 1 delivery, got %d", len(rcv.payloads))
+	}
+	p := rcv.payloads[0]
+	if p.Event != "victory" || p.SessionID != "s1" {
+		t.Errorf("Unexpected payload identity: %+v", p)
+	}
+	if p.ConfigName != "classic" || p.Battery != 7 || p.TotalMoves != 12 || p.PlayerPos != (engine.Position{X: 2, Y: 3}) {
+		t.Errorf("Expected state fields in payload, got %+v", p)
+	}
+}
+
+func TestPublish_IgnoresUnknownEvents(t *testing.T) {
+	m := newTestManager(t)
+	m.Publish(testEvent("s1", "battery_low"))
+
+	if len(m.queue) != 0 {
+		t.Errorf("Expected unknown event not to be queued, queue has %d", len(m.queue))
+	}
+}
+
+func TestDeliver_DisablesAfterRepeatedFailures(t *testing.T) {
+	m := newTestManager(t)
+	m.maxAttempts = 2
+	m.maxFailures = 2
+	rcv, srv := newReceiver(t, http.StatusInternalServerError)
+
+	if _, err := m.Register(srv.URL, []string{"game_over"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	// Two events, each retried twice
+	m.Publish(testEvent("s1", "game_over"))
+	m.Publish(testEvent("s2", "game_over"))
+	rcv.wait(t, 4)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		hooks := m.List()
+		if hooks[0].Disabled {
+			if hooks[0].Failures != 2 {
+				t.Errorf("Expected 2 recorded failures, got %d", hooks[0].Failures)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected webhook to be disabled, got %+v", hooks[0])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Disabled hooks receive nothing further
+	m.Publish(testEvent("s3", "game_over"))
+	time.Sleep(50 * time.Millisecond)
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if len(rcv.payloads) != 4 {
+		t.Errorf("Expected no deliveries after disabling, got %d total", len(rcv.payloads))
+	}
+}
+
+func TestPublish_DropsOldestWhenFull(t *testing.T) {
+	// No worker is started, so the queue only fills
+	m := &Manager{queue: make(chan Payload, 2), logger: slog.New(slog.DiscardHandler)}
+
+	m.Publish(testEvent("first", "victory"))
+	m.Publish(testEvent("second", "victory"))
+	m.Publish(testEvent("third", "victory"))
+
+	if m.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped event, got %d", m.Dropped())
+	}
+	if got := (<-m.queue).SessionID; got != "second" {
+		t.Errorf("Expected oldest event to be dropped, queue head is %q", got)
+	}
+	if got := (<-m.queue).SessionID; got != "third" {
+		t.Errorf("Expected newest event to be kept, got %q", got)
+	}
+}
+
+func TestManager_PersistsRegistrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	m, err := NewManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	keep, _ := m.Register("http://example.com/a", []string{"victory"})
+	drop, _ := m.Register("http://example.com/b", []string{"session_created"})
+	if err := m.Delete(drop.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := m.Delete(drop.ID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+	m.Close()
+
+	reloaded, err := NewManager(path)
+	if err != nil {
+		t.Fatalf("Failed to reload manager: %v", err)
+	}
+	defer reloaded.Close()
+
+	hooks := reloaded.List()
+	if len(hooks) != 1 || hooks[0].ID != keep.ID || hooks[0].URL != "http://example.com/a" {
+		t.Errorf("Expected only the kept registration after reload, got %+v", hooks)
+	}
+}
@@ -0,0 +1,198 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+// Client payload modes selected with ?mode= on the /ws URL; ?delta=true is
+// the same as ?mode=delta
+const (
+	ModeFull  = "full"
+	ModeDelta = "delta"
+)
+
+// Send a full snapshot to delta clients every this many updates
+const deltaSnapshotInterval = 20
+
+// CellChange is a single grid cell that changed since the previous broadcast
+type CellChange struct {
+	X    int         `json:"x"`
+	Y    int         `json:"y"`
+	Cell engine.Cell `json:"cell"`
+}
+
+// DeltaMessage is sent to delta-mode clients instead of the full game state.
+// It applies on top of the state with version Version-1: ChangedCells
+// replaces those grid cells, and the other fields replace the state's fields
+// of the same name.
+type DeltaMessage struct {
+	SessionID      string                `json:"session_id"`
+	Event          string                `json:"event"` // "state_delta"
+	Version        int                   `json:"version"`
+	ChangedCells   []CellChange          `json:"changed_cells"` // Empty when no cell changed
+	PlayerPos      engine.Position       `json:"player_pos"`
+	Battery        int                   `json:"battery"`
+	Score          int                   `json:"score"`
+	Message        string                `json:"message"`
+	GameOver       bool                  `json:"game_over"`
+	Victory        bool                  `json:"victory"`
+	GameOverReason engine.GameOverReason `json:"game_over_reason,omitempty"`
+	Result         *engine.GameResult    `json:"result,omitempty"`
+	TotalMoves     int                   `json:"total_moves"`
+}
+
+// sessionSnapshot is the last state broadcast to a session, used as the diff base
+type sessionSnapshot struct {
+	version   int
+	sinceFull int
+	state     *engine.GameState
+}
+
+// copyState returns a copy of the state whose grid is not shared with the original
+func copyState(state *engine.GameState) *engine.GameState {
+	cp := *state
+	cp.Grid = make([][]engine.Cell, len(state.Grid))
+	for y, row := range state.Grid {
+		cp.Grid[y] = append([]engine.Cell(nil), row...)
+	}
+	return &cp
+}
+
+// diffGrids returns the cells that differ between two grids, or ok=false when
+// the grids have different dimensions and cannot be diffed
+func diffGrids(prev, next [][]engine.Cell) (changes []CellChange, ok bool) {
+	if len(prev) != len(next) {
+		return nil, false
+	}
+	changes = []CellChange{}
+	for y := range next {
+		if len(prev[y]) != len(next[y]) {
+			return nil, false
+		}
+		for x := range next[y] {
+			if prev[y][x] != next[y][x] {
+				changes = append(changes, CellChange{X: x, Y: y, Cell: next[y][x]})
+			}
+		}
+	}
+	return changes, true
+}
+
+// snapshotMessage encodes a full state for a delta client
+func snapshotMessage(sessionID string, snap *sessionSnapshot, catchup bool) []byte {
+	data, err := json.Marshal(&Message{
+		SessionID: sessionID,
+		GameState: snap.state,
+		Event:     "state_snapshot",
+		Version:   snap.version,
+		Catchup:   catchup,
+	})
+	if err != nil {
+		log.Printf("Failed to marshal WebSocket snapshot: %v", err)
+		return nil
+	}
+	return data
+}
+
+// broadcastDelta advances the session snapshot and sends either a delta or a
+// full snapshot to each delta-mode client. Caller must hold h.mu.
+func (h *Hub) broadcastDelta(sessionID string, state *engine.GameState, clients map[*Client]bool) {
+	snap := h.snapshots[sessionID]
+	if snap == nil {
+		snap = &sessionSnapshot{}
+		h.snapshots[sessionID] = snap
+	}
+
+	var changes []CellChange
+	canDiff := false
+	if snap.state != nil {
+		changes, canDiff = diffGrids(snap.state.Grid, state.Grid)
+	}
+
+	prevVersion := snap.version
+	snap.version++
+	snap.state = copyState(state)
+	snap.sinceFull++
+	full := !canDiff || snap.sinceFull >= deltaSnapshotInterval
+	if full {
+		snap.sinceFull = 0
+	}
+
+	var deltaData, fullData []byte
+	for client := range clients {
+		if client.mode != ModeDelta {
+			continue
+		}
+
+		var data []byte
+		if full || client.version != prevVersion {
+			if fullData == nil {
+				fullData = snapshotMessage(sessionID, snap, false)
+			}
+			data = fullData
+		} else {
+			if deltaData == nil {
+				var err error
+				deltaData, err = json.Marshal(&DeltaMessage{
+					SessionID:      sessionID,
+					Event:          "state_delta",
+					Version:        snap.version,
+					ChangedCells:   changes,
+					PlayerPos:      state.PlayerPos,
+					Battery:        state.Battery,
+					Score:          state.Score,
+					Message:        state.Message,
+					GameOver:       state.GameOver,
+					Victory:        state.Victory,
+					GameOverReason: state.GameOverReason,
+					Result:         state.Result,
+					TotalMoves:     state.TotalMoves,
+				})
+				if err != nil {
+					log.Printf("Failed to marshal WebSocket delta: %v", err)
+					return
+				}
+			}
+			data = deltaData
+		}
+		if data == nil {
+			continue
+		}
+
+		dropped := client.dropped
+		if h.deliver(client, data) {
+			client.version = snap.version
+			if client.dropped > dropped {
+				// An earlier message was discarded; resync with the next snapshot
+				client.version = -1
+			}
+		}
+	}
+}
+
+// sendSnapshot sends the latest session snapshot to a delta client, seeding the
+// session snapshot from state when nothing has been broadcast yet
+func (h *Hub) sendSnapshot(client *Client, state *engine.GameState, catchup bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	snap := h.snapshots[client.sessionID]
+	if snap == nil || snap.state == nil {
+		if state == nil {
+			return
+		}
+		snap = &sessionSnapshot{state: copyState(state)}
+		h.snapshots[client.sessionID] = snap
+	}
+
+	data := snapshotMessage(client.sessionID, snap, catchup)
+	if data == nil {
+		return
+	}
+	if h.deliver(client, data) {
+		client.version = snap.version
+	}
+}
@@ -0,0 +1,269 @@
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+)
+
+func createDeltaTestEngine(t *testing.T) *engine.GameEngine {
+	t.Helper()
+	config := &engine.GameConfig{
+		Name:            "Delta Test",
+		Description:     "Configuration for delta tests",
+		GridSize:        5,
+		MaxBattery:      30,
+		StartingBattery: 30,
+		Layout: []string{
+			"PRRRP",
+			"RWRWR",
+			"RRHRR",
+			"RWRWR",
+			"PRRRP",
+		},
+		Legend: map[string]string{
+			"R": "road", "H": "home", "P": "park",
+			"S": "supercharger", "W": "water", "B": "building",
+		},
+	}
+	config.Messages.Welcome = "Welcome!"
+	config.Messages.ParkVisited = "Park visited! Score: %d"
+	config.Messages.Victory = "Victory! All %d parks visited!"
+	config.Messages.OutOfBattery = "Out of battery!"
+	config.Messages.BatteryStatus = "Battery: %d/%d"
+
+	eng, err := engine.NewEngine(config)
+	if err != nil {
+		t.Fatalf("Failed to create engine: %v", err)
+	}
+	return eng
+}
+
+// deltaReplica reconstructs session state from the messages a delta client receives
+type deltaReplica struct {
+	t         *testing.T
+	version   int
+	grid      [][]engine.Cell
+	playerPos engine.Position
+	battery   int
+	snapshots int
+	deltas    int
+}
+
+func (r *deltaReplica) apply(data []byte) {
+	var envelope struct {
+		Event string `json:"event"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		r.t.Fatalf("Failed to parse message: %v", err)
+	}
+
+	switch envelope.Event {
+	case "state_snapshot":
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			r.t.Fatalf("Failed to parse snapshot: %v", err)
+		}
+		r.version = msg.Version
+		r.grid = msg.GameState.Grid
+		r.playerPos = msg.GameState.PlayerPos
+		r.battery = msg.GameState.Battery
+		r.snapshots++
+	case "state_delta":
+		var msg DeltaMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			r.t.Fatalf("Failed to parse delta: %v", err)
+		}
+		if r.grid == nil {
+			r.t.Fatal("Received delta before any snapshot")
+		}
+		if msg.Version != r.version+1 {
+			r.t.Fatalf("Delta version %d does not follow %d", msg.Version, r.version)
+		}
+		for _, change := range msg.ChangedCells {
+			r.grid[change.Y][change.X] = change.Cell
+		}
+		r.version = msg.Version
+		r.playerPos = msg.PlayerPos
+		r.battery = msg.Battery
+		r.deltas++
+	default:
+		r.t.Fatalf("Unexpected event %q for delta client", envelope.Event)
+	}
+}
+
+func (r *deltaReplica) drain(client *Client) {
+	for {
+		select {
+		case data := <-client.send:
+			r.apply(data)
+		default:
+			return
+		}
+	}
+}
+
+func TestDeltaStreamReconstructsState(t *testing.T) {
+	hub := NewHub()
+	eng := createDeltaTestEngine(t)
+	sessionID := "delta-session"
+
+	deltaClient := &Client{hub: hub, sessionID: sessionID, mode: ModeDelta, send: make(chan []byte, 256)}
+	fullClient := &Client{hub: hub, sessionID: sessionID, mode: ModeFull, send: make(chan []byte, 256)}
+	hub.sendSnapshot(deltaClient, eng.GetState(), false)
+	hub.registerClient(deltaClient)
+	hub.registerClient(fullClient)
+
+	replica := &deltaReplica{t: t}
+
+	// Tour all four corner parks (with some wall bumps), reset, then wander
+	moves := []string{
+		"up", "up", "left", "left", "up", "right", "right", "right", "right",
+		"down", "down", "down", "down", "left", "left", "left", "left", "left",
+		"up", "up", "right", "right",
+	}
+	for _, dir := range moves {
+		eng.Move(dir)
+		hub.BroadcastToSession(sessionID, eng.GetState())
+		replica.drain(deltaClient)
+	}
+	eng.Reset()
+	hub.BroadcastToSession(sessionID, eng.GetState())
+	for _, dir := range []string{"left", "left", "up", "up"} {
+		eng.Move(dir)
+		hub.BroadcastToSession(sessionID, eng.GetState())
+	}
+	replica.drain(deltaClient)
+
+	state := eng.GetState()
+	for y := range state.Grid {
+		for x := range state.Grid[y] {
+			if replica.grid[y][x] != state.Grid[y][x] {
+				t.Errorf("Cell (%d,%d) mismatch: replica %+v, authoritative %+v", x, y, replica.grid[y][x], state.Grid[y][x])
+			}
+		}
+	}
+	if replica.playerPos != state.PlayerPos || replica.battery != state.Battery {
+		t.Errorf("Replica player %+v battery %d, expected %+v battery %d",
+			replica.playerPos, replica.battery, state.PlayerPos, state.Battery)
+	}
+
+	// 27 broadcasts cross the periodic snapshot interval once
+	if replica.snapshots != 2 {
+		t.Errorf("Expected initial and one periodic snapshot, got %d", replica.snapshots)
+	}
+	if replica.deltas == 0 {
+		t.Error("Expected delta messages")
+	}
+
+	// Full-mode clients keep receiving complete states
+	var msg Message
+	if err := json.Unmarshal(<-fullClient.send, &msg); err != nil {
+		t.Fatalf("Failed to parse full message: %v", err)
+	}
+	if msg.Event != "state_update" || msg.GameState == nil {
+		t.Errorf("Expected full state_update for full client, got %+v", msg)
+	}
+}
+
+func TestDeltaSingleMoveIsSmall(t *testing.T) {
+	hub := NewHub()
+	eng := createDeltaTestEngine(t)
+	sessionID := "small-session"
+
+	deltaClient := &Client{hub: hub, sessionID: sessionID, mode: ModeDelta, send: make(chan []byte, 256)}
+	fullClient := &Client{hub: hub, sessionID: sessionID, mode: ModeFull, send: make(chan []byte, 256)}
+	hub.sendSnapshot(deltaClient, eng.GetState(), false)
+	<-deltaClient.send
+	hub.registerClient(deltaClient)
+	hub.registerClient(fullClient)
+
+	move := func(dir string) (delta DeltaMessage, deltaSize, fullSize int) {
+		t.Helper()
+		eng.Move(dir)
+		hub.BroadcastToSession(sessionID, eng.GetState())
+		data := <-deltaClient.send
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatalf("Failed to parse delta: %v", err)
+		}
+		if _, ok := fields["game_state"]; ok {
+			t.Fatalf("Expected a delta without the game state, got %s", data)
+		}
+		if err := json.Unmarshal(data, &delta); err != nil || delta.Event != "state_delta" {
+			t.Fatalf("Expected a state_delta, got %s (%v)", data, err)
+		}
+		return delta, len(data), len(<-fullClient.send)
+	}
+
+	// Driving onto road changes no cells, only the player fields
+	delta, deltaSize, fullSize := move("left")
+	if len(delta.ChangedCells) != 0 || delta.PlayerPos != (engine.Position{X: 1, Y: 2}) || delta.Battery != 29 {
+		t.Errorf("Expected no changed cells with the player at (1,2) on 29 battery, got %+v", delta)
+	}
+	if deltaSize*4 > fullSize {
+		t.Errorf("Expected the delta to be under a quarter of the full state, got %d vs %d bytes", deltaSize, fullSize)
+	}
+
+	// Collecting a park changes just that cell
+	move("left")
+	move("up")
+	delta, _, _ = move("up")
+	want := []CellChange{{X: 0, Y: 0, Cell: engine.Cell{Type: engine.Park, ID: "park_0", Visited: true}}}
+	if !reflect.DeepEqual(delta.ChangedCells, want) || delta.Score != 1 {
+		t.Errorf("Expected only the collected park to change, got %+v with score %d", delta.ChangedCells, delta.Score)
+	}
+}
+
+func TestDeltaClientOutOfSyncGetsSnapshot(t *testing.T) {
+	hub := NewHub()
+	eng := createDeltaTestEngine(t)
+	sessionID := "resync-session"
+
+	hub.BroadcastToSession(sessionID, eng.GetState())
+
+	// A client that never received a snapshot is brought up to date with a full state
+	client := &Client{hub: hub, sessionID: sessionID, mode: ModeDelta, send: make(chan []byte, 256), version: -1}
+	hub.registerClient(client)
+
+	eng.Move("left")
+	hub.BroadcastToSession(sessionID, eng.GetState())
+
+	var msg Message
+	if err := json.Unmarshal(<-client.send, &msg); err != nil {
+		t.Fatalf("Failed to parse message: %v", err)
+	}
+	if msg.Event != "state_snapshot" {
+		t.Errorf("Expected state_snapshot for out-of-sync client, got %s", msg.Event)
+	}
+}
+
+func TestDeltaSyncRequest(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	eng := createDeltaTestEngine(t)
+	sessionID := "sync-session"
+
+	client := &Client{hub: hub, sessionID: sessionID, mode: ModeDelta, send: make(chan []byte, 256)}
+	hub.sendSnapshot(client, eng.GetState(), false)
+	<-client.send
+	hub.register <- client
+
+	hub.syncRequests <- client
+
+	select {
+	case data := <-client.send:
+		var msg Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Fatalf("Failed to parse message: %v", err)
+		}
+		if msg.Event != "state_snapshot" || msg.GameState == nil {
+			t.Errorf("Expected state_snapshot in response to sync, got %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for sync snapshot")
+	}
+}
@@ -19,6 +19,36 @@
 //   - Incoming: {action: "move", direction: "up", sessionId: "abc1"}
 //   - Outgoing: Complete GameState JSON after each state change
 //
+// Reconnection:
+//
+// A reconnecting client may pass ?lastMove=N with the last total_moves it saw.
+// If the session has moved on since then, the current state is sent immediately
+// with "catchup": true so the client knows it missed updates and should replace
+// its local state. No catch-up is sent when nothing was missed.
+//
+// Delta Mode:
+//
+// Clients connecting with ?mode=delta (or ?delta=true) receive a full "state_snapshot" first and
+// then "state_delta" messages holding only the grid cells that changed since the
+// previous broadcast, plus player position, battery, score and message. Every
+// message carries a version; a delta applies only on top of version-1. A fresh
+// snapshot is sent every 20 updates, whenever a client falls out of step, and
+// when the client sends {"action": "sync"}. The default mode stays full-state.
+//
+// Compression:
+//
+// Clients connecting with ?encoding=gzip receive binary frames holding the
+// gzipped bytes of what a text frame would carry: one or more JSON messages
+// separated by newlines. It works with either mode. The default encoding
+// stays JSON text frames.
+//
+// Backpressure:
+//
+// Each client has a buffered send queue (HubOptions.SendBuffer). Broadcasts never
+// wait on a client: when its queue is full the client is disconnected, or with
+// OverflowDropOldest its oldest queued message is discarded. A delta client that
+// loses a message gets a full snapshot with the next update.
+//
 // Session Integration:
 //
 // WebSocket connections are session-aware. Clients specify their session ID
@@ -0,0 +1,15 @@
+package websocket
+
+import (
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+)
+
+// Publish implements service.EventPublisher. Moves made through the API are
+// broadcast by its handlers; Publish covers state changes the service makes
+// on its own, such as auto-resets after game over.
+func (h *Hub) Publish(event service.SessionEvent) {
+	if event.Event.Type != "auto_reset" || event.State == nil {
+		return
+	}
+	h.BroadcastToSession(event.SessionID, event.State)
+}
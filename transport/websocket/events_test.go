@@ -0,0 +1,31 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/wricardo/tesla-road-trip-game/game/engine"
+	"github.com/wricardo/tesla-road-trip-game/game/service"
+)
+
+func TestHubPublish_AutoResetOnly(t *testing.T) {
+	hub := NewHub()
+	client := &Client{
+		hub:       hub,
+		sessionID: "reset-test",
+		send:      make(chan []byte, 256),
+	}
+	hub.registerClient(client)
+
+	state := &engine.GameState{Battery: 10}
+
+	// Move events are already broadcast by the API handlers
+	hub.Publish(service.SessionEvent{SessionID: "reset-test", Event: service.GameEvent{Type: "victory"}, State: state})
+	if len(client.send) != 0 {
+		t.Fatalf("Expected no broadcast for victory event, got %d messages", len(client.send))
+	}
+
+	hub.Publish(service.SessionEvent{SessionID: "reset-test", Event: service.GameEvent{Type: "auto_reset"}, State: state})
+	if len(client.send) != 1 {
+		t.Errorf("Expected auto_reset to broadcast the fresh state, got %d messages", len(client.send))
+	}
+}
@@ -1,9 +1,12 @@
 package websocket
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"io"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -22,6 +25,22 @@ const (
 
 	// Maximum message size allowed from peer.
 	maxMessageSize = 512
+
+	// DefaultSendBuffer is the number of outgoing messages queued per client
+	// when HubOptions.SendBuffer is not set.
+	DefaultSendBuffer = 256
+)
+
+// Frame encodings a client can ask for
+const (
+	EncodingJSON = "json" // JSON text frames, the default
+	EncodingGzip = "gzip" // Binary frames holding the gzipped JSON
+)
+
+// Overflow policies for a client whose send buffer is full
+const (
+	OverflowDisconnect = "disconnect"  // Drop the client; it can reconnect and catch up
+	OverflowDropOldest = "drop_oldest" // Discard the client's oldest queued message
 )
 
 var upgrader = websocket.Upgrader{
@@ -40,6 +59,18 @@ type Message struct {
 	GameState *engine.GameState `json:"game_state,omitempty"`
 	Event     string            `json:"event,omitempty"`
 	Data      interface{}       `json:"data,omitempty"`
+
+	// PlayerID names the player a shared-session update is about
+	PlayerID string `json:"player_id,omitempty"`
+
+	// Version numbers the states sent to delta-mode clients (see DeltaMessage)
+	Version int `json:"version,omitempty"`
+
+	// Catchup is set on the state sent right after a reconnect when the session's
+	// cumulative move count (game_state.total_moves) is ahead of the client's
+	// ?lastMove=N. Clients should replace their local state with it rather than
+	// treat it as a single new move.
+	Catchup bool `json:"catchup,omitempty"`
 }
 
 // Client represents a WebSocket client
@@ -48,6 +79,42 @@ type Client struct {
 	conn      *websocket.Conn
 	send      chan []byte
 	sessionID string
+	playerID  string // the shared-session player the client plays as, if any
+	mode      string // ModeFull or ModeDelta
+	encoding  string // EncodingJSON or EncodingGzip
+	version   int    // last snapshot version delivered to a delta client
+	dropped   int    // queued messages discarded under OverflowDropOldest
+
+	// gzip compresses the frames of a gzip client, reset for each frame
+	gzip *gzip.Writer
+}
+
+// ClientOptions configures a new WebSocket client
+type ClientOptions struct {
+	// Mode selects full-state (default) or delta payloads
+	Mode string
+	// Encoding selects JSON text frames (default) or gzipped binary frames
+	Encoding string
+	// PlayerID is the shared-session player the client plays as; see
+	// BroadcastPlayerUpdateFor
+	PlayerID string
+	// Initial is delivered before any broadcast. For delta clients its game
+	// state seeds the session snapshot when nothing has been broadcast yet.
+	Initial *Message
+}
+
+// HubOptions configures a new Hub
+type HubOptions struct {
+	// SendBuffer is the number of messages queued per client before its
+	// overflow policy applies; 0 uses DefaultSendBuffer
+	SendBuffer int
+	// Overflow is OverflowDisconnect (the default) or OverflowDropOldest
+	Overflow string
+}
+
+// clientRequest is an action sent by a client over the socket
+type clientRequest struct {
+	Action string `json:"action"`
 }
 
 // Hub maintains the set of active clients and broadcasts messages
@@ -63,16 +130,47 @@ type Hub struct {
 
 	// Unregister requests from clients
 	unregister chan *Client
+
+	// Full snapshot requests from delta clients
+	syncRequests chan *Client
+
+	// Guards sessions, snapshots and client versions
+	mu sync.Mutex
+
+	// Last broadcast state per session, the base for delta payloads
+	snapshots map[string]*sessionSnapshot
+
+	// Per-client send buffer size and what to do when it fills up
+	sendBuffer int
+	overflow   string
 }
 
 // NewHub creates a new WebSocket hub
 func NewHub() *Hub {
-	return &Hub{
-		sessions:   make(map[string]map[*Client]bool),
-		broadcast:  make(chan *Message),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
+	return NewHubWithOptions(HubOptions{})
+}
+
+// NewHubWithOptions creates a new WebSocket hub with the given per-client
+// buffering. A client that stops reading never blocks broadcasts: once its
+// buffer is full it is disconnected or loses its oldest queued message.
+func NewHubWithOptions(opts HubOptions) *Hub {
+	h := &Hub{
+		sessions:     make(map[string]map[*Client]bool),
+		broadcast:    make(chan *Message),
+		register:     make(chan *Client),
+		unregister:   make(chan *Client),
+		syncRequests: make(chan *Client),
+		snapshots:    make(map[string]*sessionSnapshot),
+		sendBuffer:   opts.SendBuffer,
+		overflow:     opts.Overflow,
+	}
+	if h.sendBuffer <= 0 {
+		h.sendBuffer = DefaultSendBuffer
 	}
+	if h.overflow != OverflowDropOldest {
+		h.overflow = OverflowDisconnect
+	}
+	return h
 }
 
 // Run starts the hub's event loop
@@ -80,19 +178,39 @@ func (h *Hub) Run() {
 	for {
 		select {
 		case client := <-h.register:
+			h.mu.Lock()
 			h.registerClient(client)
+			h.mu.Unlock()
 
 		case client := <-h.unregister:
+			h.mu.Lock()
 			h.unregisterClient(client)
+			h.mu.Unlock()
 
 		case message := <-h.broadcast:
+			h.mu.Lock()
 			h.broadcastMessage(message)
+			h.mu.Unlock()
+
+		case client := <-h.syncRequests:
+			h.mu.Lock()
+			registered := h.sessions[client.sessionID][client]
+			h.mu.Unlock()
+			if registered {
+				h.sendSnapshot(client, nil, false)
+			}
 		}
 	}
 }
 
 // ServeWS handles WebSocket requests from clients
 func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request, sessionID string) {
+	h.ServeWSWithOptions(w, r, sessionID, ClientOptions{})
+}
+
+// ServeWSWithOptions handles a WebSocket request like ServeWS with a payload
+// mode and an optional initial message
+func (h *Hub) ServeWSWithOptions(w http.ResponseWriter, r *http.Request, sessionID string, opts ClientOptions) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
@@ -102,8 +220,36 @@ func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request, sessionID string)
 	client := &Client{
 		hub:       h,
 		conn:      conn,
-		send:      make(chan []byte, 256),
+		send:      make(chan []byte, h.sendBuffer),
 		sessionID: sessionID,
+		playerID:  opts.PlayerID,
+		mode:      ModeFull,
+		encoding:  EncodingJSON,
+	}
+	if opts.Mode == ModeDelta {
+		client.mode = ModeDelta
+	}
+	if opts.Encoding == EncodingGzip {
+		client.encoding = EncodingGzip
+		client.gzip = gzip.NewWriter(io.Discard)
+	}
+
+	if client.mode == ModeDelta {
+		// Delta clients always start from a full snapshot
+		var state *engine.GameState
+		catchup := false
+		if opts.Initial != nil {
+			state = opts.Initial.GameState
+			catchup = opts.Initial.Catchup
+		}
+		h.sendSnapshot(client, state, catchup)
+	} else if opts.Initial != nil {
+		data, err := json.Marshal(opts.Initial)
+		if err != nil {
+			log.Printf("Failed to marshal initial WebSocket message: %v", err)
+		} else {
+			client.send <- data
+		}
 	}
 
 	client.hub.register <- client
@@ -127,16 +273,21 @@ func (h *Hub) BroadcastToSession(sessionID string, state *engine.GameState) {
 		return
 	}
 
-	// Send to all clients in this session
-	if clients, ok := h.sessions[sessionID]; ok {
-		for client := range clients {
-			select {
-			case client.send <- data:
-			default:
-				// Client's send channel is full, close it
-				h.unregisterClient(client)
-			}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Send to all full-state clients in this session
+	clients := h.sessions[sessionID]
+	for client := range clients {
+		if client.mode == ModeDelta {
+			continue
 		}
+		h.deliver(client, data)
+	}
+
+	// Delta clients get only what changed since the previous broadcast
+	if state != nil {
+		h.broadcastDelta(sessionID, state, clients)
 	}
 }
 
@@ -151,6 +302,49 @@ func (h *Hub) BroadcastEvent(sessionID string, event string, data interface{}) {
 	h.broadcast <- message
 }
 
+// BroadcastPlayerUpdate sends one player's move in a shared session to every
+// client of that session as a "player_update" event keyed by playerID
+func (h *Hub) BroadcastPlayerUpdate(sessionID, playerID string, data interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.broadcastMessage(&Message{
+		SessionID: sessionID,
+		Event:     "player_update",
+		PlayerID:  playerID,
+		Data:      data,
+	})
+}
+
+// BroadcastPlayerUpdateFor sends one player's move in a shared session like
+// BroadcastPlayerUpdate, but gives each client its own view of it: view is
+// called once for every player ID the session's clients play as ("" for
+// clients that gave none) and returns the data that player may see
+func (h *Hub) BroadcastPlayerUpdateFor(sessionID, playerID string, view func(viewer string) interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	views := make(map[string][]byte)
+	for client := range h.sessions[sessionID] {
+		data, ok := views[client.playerID]
+		if !ok {
+			var err error
+			data, err = json.Marshal(&Message{
+				SessionID: sessionID,
+				Event:     "player_update",
+				PlayerID:  playerID,
+				Data:      view(client.playerID),
+			})
+			if err != nil {
+				log.Printf("Failed to marshal player update: %v", err)
+				return
+			}
+			views[client.playerID] = data
+		}
+		h.deliver(client, data)
+	}
+}
+
 // registerClient adds a client to a session
 func (h *Hub) registerClient(client *Client) {
 	if h.sessions[client.sessionID] == nil {
@@ -172,6 +366,7 @@ func (h *Hub) unregisterClient(client *Client) {
 			// Clean up empty sessions
 			if len(clients) == 0 {
 				delete(h.sessions, client.sessionID)
+				delete(h.snapshots, client.sessionID)
 			}
 
 			log.Printf("Client unregistered from session %s (remaining clients: %d)",
@@ -190,11 +385,32 @@ func (h *Hub) broadcastMessage(message *Message) {
 
 	if clients, ok := h.sessions[message.SessionID]; ok {
 		for client := range clients {
-			select {
-			case client.send <- data:
-			default:
-				h.unregisterClient(client)
-			}
+			h.deliver(client, data)
+		}
+	}
+}
+
+// deliver queues data for a client without blocking, applying the hub's
+// overflow policy when the client's buffer is full. It reports whether data
+// was queued. Caller must hold h.mu.
+func (h *Hub) deliver(client *Client, data []byte) bool {
+	for {
+		select {
+		case client.send <- data:
+			return true
+		default:
+		}
+
+		if h.overflow != OverflowDropOldest {
+			log.Printf("Client in session %s is not keeping up; disconnecting", client.sessionID)
+			h.unregisterClient(client)
+			return false
+		}
+		select {
+		case <-client.send:
+			client.dropped++
+		default:
+			// The write pump drained it meanwhile; try again
 		}
 	}
 }
@@ -214,15 +430,19 @@ func (c *Client) readPump() {
 	})
 
 	for {
-		// We don't process incoming messages from clients currently
-		// Just keep the connection alive
-		_, _, err := c.conn.ReadMessage()
+		_, data, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
+
+		// The only client action is a full snapshot request from delta clients
+		var req clientRequest
+		if json.Unmarshal(data, &req) == nil && req.Action == "sync" && c.mode == ModeDelta {
+			c.hub.syncRequests <- c
+		}
 	}
 }
 
@@ -244,19 +464,33 @@ func (c *Client) writePump() {
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
+			frameType := websocket.TextMessage
+			if c.encoding == EncodingGzip {
+				frameType = websocket.BinaryMessage
+			}
+			w, err := c.conn.NextWriter(frameType)
 			if err != nil {
 				return
 			}
-			w.Write(message)
+			var out io.Writer = w
+			if c.encoding == EncodingGzip {
+				c.gzip.Reset(w)
+				out = c.gzip
+			}
+			out.Write(message)
 
 			// Add queued messages to the current WebSocket message
 			n := len(c.send)
 			for i := 0; i < n; i++ {
-				w.Write([]byte{'\n'})
-				w.Write(<-c.send)
+				out.Write([]byte{'\n'})
+				out.Write(<-c.send)
 			}
 
+			if c.encoding == EncodingGzip {
+				if err := c.gzip.Close(); err != nil {
+					return
+				}
+			}
 			if err := w.Close(); err != nil {
 				return
 			}
@@ -1,9 +1,12 @@
 package websocket
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -173,6 +176,137 @@ func TestHubBroadcastToSession(t *testing.T) {
 	}
 }
 
+func TestHubBroadcastPlayerUpdate(t *testing.T) {
+	hub := NewHub()
+	client := &Client{hub: hub, sessionID: "m1a2b", send: make(chan []byte, 256), mode: ModeFull}
+	other := &Client{hub: hub, sessionID: "other", send: make(chan []byte, 256), mode: ModeFull}
+	hub.registerClient(client)
+	hub.registerClient(other)
+
+	hub.BroadcastPlayerUpdate("m1a2b", "alice", map[string]int{"score": 1})
+
+	select {
+	case data := <-client.send:
+		var message Message
+		if err := json.Unmarshal(data, &message); err != nil {
+			t.Fatalf("Failed to unmarshal message: %v", err)
+		}
+		if message.Event != "player_update" || message.PlayerID != "alice" || message.SessionID != "m1a2b" {
+			t.Errorf("Expected player_update for alice, got %+v", message)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timeout waiting for player update")
+	}
+
+	select {
+	case <-other.send:
+		t.Error("Clients of other sessions should not get the update")
+	default:
+	}
+}
+
+func TestHubBroadcastPlayerUpdateFor(t *testing.T) {
+	hub := NewHub()
+	alice := &Client{hub: hub, sessionID: "m1a2b", playerID: "alice", send: make(chan []byte, 256), mode: ModeFull}
+	bob := &Client{hub: hub, sessionID: "m1a2b", playerID: "bob", send: make(chan []byte, 256), mode: ModeFull}
+	spectator := &Client{hub: hub, sessionID: "m1a2b", send: make(chan []byte, 256), mode: ModeFull}
+	for _, c := range []*Client{alice, bob, spectator} {
+		hub.registerClient(c)
+	}
+
+	// Each viewer sees only its own battery; opponents' are masked
+	calls := 0
+	hub.BroadcastPlayerUpdateFor("m1a2b", "alice", func(viewer string) interface{} {
+		calls++
+		battery := map[string]interface{}{"alice": 7, "bob": 4}
+		for id := range battery {
+			if id != viewer {
+				battery[id] = "hidden"
+			}
+		}
+		return battery
+	})
+	if calls != 3 {
+		t.Errorf("Expected one view per viewer, got %d", calls)
+	}
+
+	for _, tt := range []struct {
+		client *Client
+		want   map[string]interface{}
+	}{
+		{alice, map[string]interface{}{"alice": 7.0, "bob": "hidden"}},
+		{bob, map[string]interface{}{"alice": "hidden", "bob": 4.0}},
+		{spectator, map[string]interface{}{"alice": "hidden", "bob": "hidden"}},
+	} {
+		var message Message
+		if err := json.Unmarshal(<-tt.client.send, &message); err != nil {
+			t.Fatalf("Failed to unmarshal message: %v", err)
+		}
+		if message.Event != "player_update" || message.PlayerID != "alice" {
+			t.Errorf("Expected a player_update for alice, got %+v", message)
+		}
+		if !reflect.DeepEqual(message.Data, tt.want) {
+			t.Errorf("Viewer %q: expected %v, got %v", tt.client.playerID, tt.want, message.Data)
+		}
+	}
+}
+
+func TestHubBackpressure(t *testing.T) {
+	for _, policy := range []string{OverflowDisconnect, OverflowDropOldest} {
+		t.Run(policy, func(t *testing.T) {
+			hub := NewHubWithOptions(HubOptions{SendBuffer: 2, Overflow: policy})
+			stalled := &Client{hub: hub, sessionID: "slow", send: make(chan []byte, hub.sendBuffer), mode: ModeFull}
+			reader := &Client{hub: hub, sessionID: "slow", send: make(chan []byte, hub.sendBuffer), mode: ModeFull}
+			hub.registerClient(stalled)
+			hub.registerClient(reader)
+
+			// The stalled client never drains; every broadcast must still get through
+			const updates = 10
+			for i := 1; i <= updates; i++ {
+				done := make(chan struct{})
+				go func() {
+					hub.BroadcastToSession("slow", &engine.GameState{TotalMoves: i})
+					close(done)
+				}()
+				select {
+				case <-done:
+				case <-time.After(time.Second):
+					t.Fatalf("Broadcast %d blocked on the stalled client", i)
+				}
+
+				var message Message
+				if err := json.Unmarshal(<-reader.send, &message); err != nil {
+					t.Fatalf("Failed to unmarshal message: %v", err)
+				}
+				if message.GameState.TotalMoves != i {
+					t.Fatalf("Reader expected update %d, got %d", i, message.GameState.TotalMoves)
+				}
+			}
+
+			registered := hub.sessions["slow"][stalled]
+			if policy == OverflowDisconnect {
+				if registered {
+					t.Error("Expected the stalled client to be disconnected")
+				}
+				return
+			}
+
+			// Drop-oldest keeps the client, holding only the newest updates
+			if !registered {
+				t.Fatal("Expected the stalled client to stay connected")
+			}
+			if stalled.dropped != updates-hub.sendBuffer {
+				t.Errorf("Expected %d dropped messages, got %d", updates-hub.sendBuffer, stalled.dropped)
+			}
+			var message Message
+			json.Unmarshal(<-stalled.send, &message)
+			if message.GameState.TotalMoves != updates-hub.sendBuffer+1 {
+				t.Errorf("Expected oldest queued update %d, got %d", updates-hub.sendBuffer+1, message.GameState.TotalMoves)
+			}
+		})
+	}
+}
+
 func TestHubBroadcastEvent(t *testing.T) {
 	hub := NewHub()
 	done := make(chan bool)
@@ -321,3 +455,59 @@ func TestWebSocketMessageReceive(t *testing.T) {
 		t.Error("GameState battery/score not correctly received")
 	}
 }
+
+func TestWebSocketGzipEncoding(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub.ServeWSWithOptions(w, r, "gzip-test", ClientOptions{Encoding: r.URL.Query().Get("encoding")})
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	gzipConn, _, err := websocket.DefaultDialer.Dial(wsURL+"?encoding=gzip", nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer gzipConn.Close()
+	textConn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to WebSocket: %v", err)
+	}
+	defer textConn.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	hub.BroadcastToSession("gzip-test", &engine.GameState{PlayerPos: engine.Position{X: 3, Y: 4}, Battery: 9})
+
+	// The gzip client gets a binary frame that decompresses to the message
+	gzipConn.SetReadDeadline(time.Now().Add(time.Second))
+	frameType, data, err := gzipConn.ReadMessage()
+	if err != nil {
+		t.Fatalf("Failed to read WebSocket message: %v", err)
+	}
+	if frameType != websocket.BinaryMessage {
+		t.Errorf("Expected a binary frame, got type %d", frameType)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected a gzip frame: %v", err)
+	}
+	var message Message
+	if err := json.NewDecoder(zr).Decode(&message); err != nil {
+		t.Fatalf("Failed to decode the decompressed message: %v", err)
+	}
+	if message.Event != "state_update" || message.GameState.PlayerPos != (engine.Position{X: 3, Y: 4}) || message.GameState.Battery != 9 {
+		t.Errorf("Unexpected message: %+v", message)
+	}
+
+	// Other clients keep their JSON text frames
+	textConn.SetReadDeadline(time.Now().Add(time.Second))
+	frameType, data, err = textConn.ReadMessage()
+	if err != nil {
+		t.Fatalf("Failed to read WebSocket message: %v", err)
+	}
+	if frameType != websocket.TextMessage || json.Unmarshal(data, &message) != nil {
+		t.Errorf("Expected a JSON text frame, got type %d: %s", frameType, data)
+	}
+}
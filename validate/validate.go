@@ -6,10 +6,13 @@
 //   - Battery constraints (starting <= max and both positive)
 //   - Required message keys
 //   - Connectivity: all parks are reachable from at least one home via passable cells
+//   - Isolated regions: passable cells walled off from home, reported as
+//     warnings, or as errors with -strict-regions
 package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -33,15 +36,28 @@ type Config struct {
 // If Valid is true, Errors contains informational messages; otherwise it
 // accumulates the validation errors that were found.
 type ValidationResult struct {
-	File   string
-	Valid  bool
-	Errors []string
+	File     string
+	Valid    bool
+	Errors   []string
+	Warnings []string
+}
+
+// Options changes how strictly validateConfigWithOptions judges a file
+type Options struct {
+	// StrictRegions reports passable cells unreachable from home as errors
+	// rather than warnings
+	StrictRegions bool
 }
 
 // validateConfig loads and validates a single configuration JSON file.
 // It performs structural checks, grid/legend validation, message presence,
 // and reachability analysis for parks.
 func validateConfig(filePath string) ValidationResult {
+	return validateConfigWithOptions(filePath, Options{})
+}
+
+// validateConfigWithOptions is validateConfig with the given options
+func validateConfigWithOptions(filePath string, opts Options) ValidationResult {
 	result := ValidationResult{
 		File:   filepath.Base(filePath),
 		Valid:  true,
@@ -79,6 +95,11 @@ func validateConfig(filePath string) ValidationResult {
 		'S': true, // Supercharger
 		'W': true, // Water
 		'B': true, // Building
+		'F': true, // Fuel pickup
+		'C': true, // Trickle charger
+		'K': true, // Checkpoint
+		'Y': true, // Key
+		'D': true, // Door
 	}
 
 	for i, row := range config.Layout {
@@ -159,12 +180,18 @@ func validateConfig(filePath string) ValidationResult {
 		} else {
 			result.Errors = append(result.Errors, reachabilityResult.Errors...)
 		}
+		if opts.StrictRegions && len(reachabilityResult.Warnings) > 0 {
+			result.Valid = false
+			result.Errors = append(result.Errors, reachabilityResult.Warnings...)
+		} else {
+			result.Warnings = append(result.Warnings, reachabilityResult.Warnings...)
+		}
 	}
 
 	// Add informational data
 	if result.Valid {
 		result.Errors = append(result.Errors, fmt.Sprintf("✓ Name: %s", config.Name))
-		result.Errors = append(result.Errors, fmt.Sprintf("✓ Grid: %dx%d", len(config.Layout), gridWidth))
+		result.Errors = append(result.Errors, fmt.Sprintf("✓ Grid: %dx%d", gridWidth, len(config.Layout)))
 		result.Errors = append(result.Errors, fmt.Sprintf("✓ Home cells: %d", homeCount))
 		result.Errors = append(result.Errors, fmt.Sprintf("✓ Parks: %d", parkCount))
 		result.Errors = append(result.Errors, fmt.Sprintf("✓ Superchargers: %d", superchargerCount))
@@ -176,7 +203,8 @@ func validateConfig(filePath string) ValidationResult {
 
 // validateConnectivity ensures all parks are reachable from a home using
 // 4-directional movement over passable cells (R, H, P, S). It reports any
-// unreachable parks and returns an aggregated ValidationResult.
+// unreachable parks and returns an aggregated ValidationResult, whose
+// Warnings list each region of passable cells walled off from home.
 func validateConnectivity(layout []string, homeCount, parkCount int) ValidationResult {
 	result := ValidationResult{
 		Valid:  true,
@@ -231,7 +259,9 @@ func validateConnectivity(layout []string, homeCount, parkCount int) ValidationR
 			return false
 		}
 		cell := rune(layout[y][x])
-		return cell == 'R' || cell == 'H' || cell == 'P' || cell == 'S'
+		// Doors count as open: their keys are checked by the engine
+		return cell == 'R' || cell == 'H' || cell == 'P' || cell == 'S' || cell == 'C' || cell == 'F' || cell == 'K' ||
+			cell == 'Y' || cell == 'D'
 	}
 
 	// Flood fill algorithm
@@ -279,12 +309,42 @@ func validateConnectivity(layout []string, homeCount, parkCount int) ValidationR
 		result.Errors = append(result.Errors, fmt.Sprintf("✓ Connectivity: All %d parks reachable from home", len(parks)))
 	}
 
+	// Flood fill each passable cell left over to group them into regions
+	for y := 0; y < height; y++ {
+		for x := 0; x < width && x < len(layout[y]); x++ {
+			if visited[fmt.Sprintf("%d,%d", x, y)] || !isPassable(x, y) {
+				continue
+			}
+			var cells []string
+			region := [][]int{{x, y}}
+			visited[fmt.Sprintf("%d,%d", x, y)] = true
+			for len(region) > 0 {
+				cx, cy := region[0][0], region[0][1]
+				region = region[1:]
+				cells = append(cells, fmt.Sprintf("(%d,%d)", cx, cy))
+				for _, dir := range [][]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					nx, ny := cx+dir[0], cy+dir[1]
+					nkey := fmt.Sprintf("%d,%d", nx, ny)
+					if !visited[nkey] && isPassable(nx, ny) {
+						visited[nkey] = true
+						region = append(region, []int{nx, ny})
+					}
+				}
+			}
+			result.Warnings = append(result.Warnings, "Isolated region unreachable from home: "+strings.Join(cells, " "))
+		}
+	}
+
 	return result
 }
 
 // main scans ../configs for *.json files and validates each one, printing a
 // concise report and exiting with non-zero status if any are invalid.
 func main() {
+	strictRegions := flag.Bool("strict-regions", false, "Report passable cells unreachable from home as errors instead of warnings")
+	flag.Parse()
+	opts := Options{StrictRegions: *strictRegions}
+
 	configDir := "../configs"
 	files, err := filepath.Glob(filepath.Join(configDir, "*.json"))
 	if err != nil {
@@ -294,7 +354,7 @@ func main() {
 
 	allValid := true
 	for _, file := range files {
-		result := validateConfig(file)
+		result := validateConfigWithOptions(file, opts)
 
 		fmt.Printf("\n%s %s\n", strings.Repeat("=", 20), result.File)
 
@@ -303,6 +363,9 @@ func main() {
 			for _, info := range result.Errors {
 				fmt.Println("  " + info)
 			}
+			for _, warning := range result.Warnings {
+				fmt.Println("  ⚠️  " + warning)
+			}
 		} else {
 			fmt.Println("❌ INVALID")
 			allValid = false
@@ -364,6 +364,84 @@ func TestValidateConnectivity_UnreachablePark(t *testing.T) {
 	}
 }
 
+func TestValidateConnectivity_IsolatedRoadPocket(t *testing.T) {
+	layout := []string{
+		"BBBBB",
+		"BHPBR",
+		"BRRBR",
+		"BBBBB",
+		"BRBBB",
+	}
+
+	result := validateConnectivity(layout, 1, 1)
+	if !result.Valid {
+		t.Errorf("Expected a pocket without parks to keep connectivity valid, got errors: %v", result.Errors)
+	}
+	want := []string{
+		"Isolated region unreachable from home: (4,1) (4,2)",
+		"Isolated region unreachable from home: (1,4)",
+	}
+	if len(result.Warnings) != len(want) {
+		t.Fatalf("Expected %d isolated region warnings, got %v", len(want), result.Warnings)
+	}
+	for i, w := range want {
+		if result.Warnings[i] != w {
+			t.Errorf("Warning %d: expected %q, got %q", i, w, result.Warnings[i])
+		}
+	}
+
+	result = validateConnectivity([]string{"BBBBB", "BHPRB", "BRRFB", "BBBBB", "BBBBB"}, 1, 1)
+	if len(result.Warnings) != 0 {
+		t.Errorf("Expected no warnings when every passable cell connects to home, got %v", result.Warnings)
+	}
+}
+
+func TestValidateConfig_StrictRegions(t *testing.T) {
+	config := `{
+		"name": "Pocket",
+		"description": "Road pocket walled off from home",
+		"grid_size": 5,
+		"layout": ["BBBBB", "BHPBR", "BRRBR", "BBBBB", "BBBBB"],
+		"max_battery": 10,
+		"starting_battery": 8,
+		"messages": {
+			"welcome": "Welcome!",
+			"park_visited": "Park visited!",
+			"victory": "Victory!",
+			"out_of_battery": "Out of battery!",
+			"supercharger_charge": "Charged!",
+			"home_charge": "Home charged!",
+			"battery_status": "Battery: %d/%d",
+			"cant_move": "Can't move!"
+		}
+	}`
+	path := filepath.Join(t.TempDir(), "pocket.json")
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	result := validateConfig(path)
+	if !result.Valid || len(result.Warnings) != 1 || !contains(result.Warnings[0], "(4,1) (4,2)") {
+		t.Errorf("Expected a valid config warning about the pocket, got valid=%v warnings=%v errors=%v",
+			result.Valid, result.Warnings, result.Errors)
+	}
+
+	result = validateConfigWithOptions(path, Options{StrictRegions: true})
+	if result.Valid || len(result.Warnings) != 0 {
+		t.Fatalf("Expected strict regions to reject the pocket, got valid=%v warnings=%v", result.Valid, result.Warnings)
+	}
+	found := false
+	for _, err := range result.Errors {
+		if contains(err, "Isolated region unreachable from home: (4,1) (4,2)") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("Expected the isolated region as an error, got %v", result.Errors)
+	}
+}
+
 func TestValidateConnectivity_EmptyLayout(t *testing.T) {
 	result := validateConnectivity([]string{}, 0, 0)
 	if result.Valid {